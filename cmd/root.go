@@ -138,6 +138,8 @@ func init() {
 	suiCmd.AddCommand(sui.WatchCmd)
 	suiCmd.AddCommand(sui.BuildCmd)
 	suiCmd.AddCommand(sui.TransCmd)
+	suiCmd.AddCommand(sui.BenchCmd)
+	suiCmd.AddCommand(sui.MaintenanceCmd)
 
 	rootCmd.AddCommand(
 		versionCmd,
@@ -0,0 +1,51 @@
+package sui
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/spf13/cobra"
+	"github.com/yaoapp/yao/sui/core"
+)
+
+// BenchCmd command
+var BenchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: L("Benchmark the template engine"),
+	Long:  L("Benchmark the template engine"),
+	Run: func(cmd *cobra.Command, args []string) {
+		option := &core.BenchOption{Cases: args, CPUProfile: cpuProfile, MemProfile: memProfile}
+		start := time.Now()
+		results, err := core.Bench(option)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, color.RedString(err.Error()))
+			os.Exit(1)
+		}
+
+		if benchJSON {
+			output, err := jsoniter.MarshalIndent(results, "", "  ")
+			if err != nil {
+				fmt.Fprintln(os.Stderr, color.RedString(err.Error()))
+				os.Exit(1)
+			}
+			fmt.Println(string(output))
+			return
+		}
+
+		fmt.Println(color.WhiteString("-----------------------"))
+		for _, res := range results {
+			fmt.Println(color.WhiteString("  %-10s %8d %12d ns/op %10d B/op %8d allocs/op %8d bytes", res.Name, res.Iterations, res.NsPerOp, res.BytesPerOp, res.AllocsPerOp, res.Size))
+		}
+		fmt.Println(color.WhiteString("-----------------------"))
+		if cpuProfile != "" {
+			fmt.Println(color.WhiteString("CPU Profile: %s", cpuProfile))
+		}
+		if memProfile != "" {
+			fmt.Println(color.WhiteString("Memory Profile: %s", memProfile))
+		}
+		fmt.Println(color.GreenString("Benchmark finished in %s", time.Since(start).Truncate(time.Millisecond)))
+	},
+}
@@ -88,7 +88,13 @@ var BuildCmd = &cobra.Command{
 			mode = "development"
 		}
 
-		warnings, err := tmpl.Build(&core.BuildOption{SSR: true, AssetRoot: assetRoot, ExecScripts: true, ScriptMinify: minify, StyleMinify: minify})
+		// The environment of the build-time directives, e.g. <!--#if prod--> ... <!--#endif-->
+		env := mode
+		if buildEnv != "" {
+			env = buildEnv
+		}
+
+		warnings, err := tmpl.Build(&core.BuildOption{SSR: true, AssetRoot: assetRoot, ExecScripts: true, ScriptMinify: minify, StyleMinify: minify, Env: env})
 		if err != nil {
 			fmt.Fprintln(os.Stderr, color.RedString(err.Error()))
 			return
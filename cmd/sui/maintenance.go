@@ -0,0 +1,90 @@
+package sui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/yaoapp/yao/config"
+	"github.com/yaoapp/yao/engine"
+	"github.com/yaoapp/yao/sui/core"
+)
+
+// MaintenanceCmd command
+var MaintenanceCmd = &cobra.Command{
+	Use:   "maintenance",
+	Short: L("Turn on or off the maintenance mode"),
+	Long:  L("Turn on or off the maintenance mode"),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			fmt.Fprintln(os.Stderr, color.RedString(L("yao sui maintenance <on|off|status>")))
+			return
+		}
+
+		Boot()
+
+		cfg := config.Conf
+		err := engine.Load(cfg, engine.LoadOption{Action: "sui.maintenance"})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, color.RedString(err.Error()))
+			return
+		}
+
+		core.MaintenanceStore = cfg.Maintenance
+		if core.MaintenanceStore == "" {
+			fmt.Fprintln(os.Stderr, color.RedString(L("Set the YAO_MAINTENANCE_STORE to toggle the maintenance mode of the running server")))
+			return
+		}
+
+		m := *core.GetMaintenance()
+		switch args[0] {
+		case "on":
+			m.Enabled = true
+			if maintenancePage != "" {
+				m.Page = maintenancePage
+			}
+			if maintenanceRetry > 0 {
+				m.RetryAfter = maintenanceRetry
+			}
+			if maintenanceMessage != "" {
+				m.Message = maintenanceMessage
+			}
+			if maintenanceAllow != "" {
+				m.Allow = strings.Split(maintenanceAllow, ",")
+			}
+			if maintenanceTokens != "" {
+				m.Tokens = strings.Split(maintenanceTokens, ",")
+			}
+
+		case "off":
+			m.Enabled = false
+
+		case "status":
+
+		default:
+			fmt.Fprintln(os.Stderr, color.RedString(L("yao sui maintenance <on|off|status>")))
+			return
+		}
+
+		if args[0] != "status" {
+			if err := core.SetMaintenance(&m); err != nil {
+				fmt.Fprintln(os.Stderr, color.RedString(err.Error()))
+				return
+			}
+		}
+
+		if !m.Enabled {
+			fmt.Println(color.GreenString("The maintenance mode is off"))
+			return
+		}
+		fmt.Println(color.YellowString("The maintenance mode is on since %s", m.Since.Format("2006-01-02 15:04:05")))
+		fmt.Println(color.WhiteString("-----------------------"))
+		fmt.Println(color.WhiteString("       Page: %s", m.Page))
+		fmt.Println(color.WhiteString("Retry After: %s", m.Retry()))
+		fmt.Println(color.WhiteString("      Allow: %s", strings.Join(m.Allow, ", ")))
+		fmt.Println(color.WhiteString("     Tokens: %d", len(m.Tokens)))
+		fmt.Println(color.WhiteString("-----------------------"))
+	},
+}
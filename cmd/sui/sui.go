@@ -3,12 +3,30 @@ package sui
 var data string
 var locales string
 var debug bool
+var buildEnv string
+var cpuProfile string
+var memProfile string
+var benchJSON bool
+var maintenancePage string
+var maintenanceRetry int
+var maintenanceMessage string
+var maintenanceAllow string
+var maintenanceTokens string
 
 func init() {
 	WatchCmd.PersistentFlags().StringVarP(&data, "data", "d", "::{}", L("Session Data"))
 	BuildCmd.PersistentFlags().StringVarP(&data, "data", "d", "::{}", L("Session Data"))
 	BuildCmd.PersistentFlags().BoolVarP(&debug, "debug", "D", false, L("Debug mode"))
+	BuildCmd.PersistentFlags().StringVarP(&buildEnv, "env", "e", "", L("The environment of the build-time directives, e.g. staging"))
 	TransCmd.PersistentFlags().StringVarP(&data, "data", "d", "::{}", L("Session Data"))
 	TransCmd.PersistentFlags().BoolVarP(&debug, "debug", "D", false, L("Debug mode"))
 	TransCmd.PersistentFlags().StringVarP(&locales, "locales", "l", "", L("Locales, separated by commas"))
+	BenchCmd.PersistentFlags().StringVarP(&cpuProfile, "cpuprofile", "c", "", L("Write the CPU profile to the file"))
+	BenchCmd.PersistentFlags().StringVarP(&memProfile, "memprofile", "m", "", L("Write the memory profile to the file"))
+	BenchCmd.PersistentFlags().BoolVarP(&benchJSON, "json", "j", false, L("Output the results as JSON"))
+	MaintenanceCmd.PersistentFlags().StringVarP(&maintenancePage, "page", "p", "", L("The URL path of the maintenance page"))
+	MaintenanceCmd.PersistentFlags().IntVarP(&maintenanceRetry, "retry", "r", 0, L("The seconds of the Retry-After header"))
+	MaintenanceCmd.PersistentFlags().StringVarP(&maintenanceMessage, "message", "m", "", L("The maintenance message"))
+	MaintenanceCmd.PersistentFlags().StringVarP(&maintenanceAllow, "allow", "i", "", L("The allowlisted IPs and CIDRs, separated by commas"))
+	MaintenanceCmd.PersistentFlags().StringVarP(&maintenanceTokens, "tokens", "t", "", L("The bypass tokens, separated by commas"))
 }
@@ -25,6 +25,7 @@ type Config struct {
 	Session       Session  `json:"session,omitempty"`                                         // Session Config
 	Studio        Studio   `json:"studio,omitempty"`                                          // Studio config
 	Runtime       Runtime  `json:"runtime,omitempty"`                                         // Runtime config
+	Maintenance   string   `json:"maintenance_store,omitempty" env:"YAO_MAINTENANCE_STORE"`   // The store of the maintenance mode, see sui.maintenance.on
 }
 
 // Studio the studio config
@@ -880,7 +880,7 @@ func initTablesDemoPetTabYao() (*asset, error) {
 	return a, nil
 }
 
-var _libsuiIndexTs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x5a\x5f\x73\xdb\x36\x12\x7f\xd7\xa7\xd8\x72\x3c\x1e\x39\x95\xa9\xdc\xcc\xdd\x0b\x15\x5a\xcd\xa5\xbe\xb6\x77\xb9\x26\x57\xb7\x73\x0f\xae\x47\x82\x29\x48\x62\x4c\x11\x2a\x00\xda\xd1\xd8\xfa\xee\x37\x8b\xff\x20\x29\x39\x49\xf3\x72\x99\x49\x42\x91\x8b\x1f\x76\x17\x8b\xc5\x0f\x0b\x2c\x9b\xba\x90\x25\xab\xe1\xe4\x64\x28\x68\x45\x0b\xc9\xf8\x19\x3c\x0e\x00\x2a\x2a\x81\x56\x9b\x0c\x7e\xfc\xf5\xdf\x6f\x2f\x2b\xba\xa1\xb5\x84\x27\xa8\x9b\xaa\x82\x5c\xfd\x37\x19\x00\x94\x4b\x18\xca\xdd\x96\xb2\x25\xd8\xe6\x90\xe7\x39\x24\x42\xf2\xb2\x5e\x25\x1a\x0b\x10\x09\x72\x58\xb0\xa2\x41\x9c\xf4\x8f\x86\xf2\xdd\x95\x69\xe0\x3b\x46\xc4\xfd\xc0\xc0\x3a\xbc\xb2\x16\x92\xd4\x05\xf6\x11\xe8\x12\x23\x5b\xe1\x08\x81\x56\x1b\x2b\x55\xb0\x5a\x48\x28\x6a\xc8\xb1\x41\xba\xa2\xf2\xb5\x94\xbc\xbc\x6d\x24\x1d\x26\x22\x2b\xea\x44\x75\xae\xdb\x15\x35\x9c\x9e\xa2\xf0\x37\x39\x24\x09\x3e\x1b\x13\x1f\xca\x7a\xc1\x1e\xae\x8b\xfa\x46\x1b\x69\xbd\xe7\xcc\x74\x1d\xb1\xcd\x96\xd5\xe8\xb1\x1c\x6a\xfa\x10\x34\x54\x4a\x4d\x8c\x30\xa7\xb2\xe1\xb5\x92\x98\xcd\x44\x53\xce\x5c\x3b\x14\x1b\x79\x18\xd3\x62\x3f\xd0\x7f\x6d\x3b\x35\x08\xfb\xc1\xc0\x8d\x62\x0b\x65\xc6\x19\x53\x50\x19\x18\xaf\x8d\xa0\x26\x1b\x9a\x81\x1e\x1e\xad\xb6\x41\x43\xbf\x14\x15\x13\x54\xc8\xe1\xfc\x5a\xfc\xfe\x7b\x56\xd4\xf9\xc9\x23\xca\xef\x6f\xe6\x67\x7d\x1d\x09\x49\x24\x1d\x7a\x2d\x15\x9c\x5c\x97\x22\x5d\x93\x7a\x51\x51\x2e\x20\xf7\x46\xa4\x0f\x44\x16\x6b\x78\x7a\x82\xc7\xfd\xc4\x0a\x5e\x51\xf4\x11\x11\xbb\xba\x00\x87\x3e\xbc\xa3\xbb\x11\xdc\x93\xaa\xa1\x23\x90\x84\xaf\xa8\x8c\x07\xd2\xc0\x43\x1e\xf7\x76\x7d\x47\x77\x37\xda\x55\xba\x15\x0a\xe8\x87\xa7\xa7\x40\x11\x74\x8b\x1f\x6e\x0b\xe6\xc7\xd9\xc1\x1f\x1d\x64\x65\xfd\xbb\xdb\x0f\x90\xbb\x0f\xb6\xdf\xcc\xfc\x3f\x72\xef\x85\x64\xdb\xf7\x9c\x6d\xc9\x8a\x20\x58\x16\x18\x7b\x16\x34\xb7\x00\xa9\x88\x23\x14\xbb\x3a\xdf\xfa\xf6\xc9\x08\x12\xc9\x1b\x9a\xb8\x58\x02\xd8\xdb\xde\xf6\xf6\x1d\x79\x20\xa5\x73\xd6\xd0\xf8\xd3\xaa\xed\x5a\x6a\x6b\x4a\x71\x15\xab\xe8\x7c\xe7\x3a\x98\x5a\xe5\x56\xcf\x28\x77\xa6\x3d\xa7\x14\x74\xad\x33\x58\x92\x4a\x50\xdb\x2b\x7a\xbe\xd3\x67\xe8\x0a\x1d\x96\x56\x5c\x4d\x6a\xfc\x83\x79\x69\x4b\xb8\x9e\x5b\xf1\x90\xa6\xfa\xbd\x09\xf5\x69\x27\x9a\x55\x14\xfb\xde\x2d\x8a\xce\x66\x9f\xd0\xf5\x78\x0c\xdf\x97\x62\xab\xa2\x58\xae\xa9\xf6\x24\x14\x6b\x52\xaf\x28\x14\x8d\x90\x6c\x03\xf4\x1e\x31\x25\xb3\x3a\x3a\x0d\x23\x67\x6b\x29\x9d\x1b\xde\xa8\x86\x97\xf8\xc6\xf8\x32\xd3\x90\xc9\x28\x50\x69\x41\x25\x29\xab\x0c\x1e\xe1\x8e\xee\x32\xf0\x13\x24\x8b\xe7\x49\xd6\xf2\x49\x10\x15\xce\x78\xad\x5a\xba\x30\xb6\xe8\xae\x95\x4a\x61\x9e\xe9\x9b\xf0\x38\xc8\xc2\xe7\x55\x35\xf9\x7e\x50\xd3\x2c\x9a\xbb\xd6\x97\xe8\xe5\x6f\x30\x41\x3f\x3d\xd9\xa9\xd5\xce\xbe\xf0\xcd\x81\x39\x16\xe5\x38\xab\x94\xf5\xdf\x1d\xe4\x90\xa0\x32\x59\x02\xdf\xa2\x33\x26\xc1\xc7\xfb\xbe\x24\x7f\x77\x16\x8a\x7c\x10\xac\x7f\x29\xc0\x0f\xe7\x44\x4a\x7e\x1e\xa2\x87\xf1\xec\xf3\x06\xca\x7a\x7d\x25\xdf\x75\x22\x08\xfe\x79\xf5\xee\x67\x8c\x4a\x41\x87\xf7\xce\xfd\x7b\x28\x54\x08\x0d\x69\x37\xe6\x02\x7b\xad\xc5\xfa\x5f\xf3\xf9\x7e\xa2\x87\xc6\x3a\xff\x6d\x29\x62\xef\xc7\x79\x52\x8d\x17\xe6\xa7\xfd\xe4\x6b\x0d\x88\x82\xb4\x23\x12\x74\x85\x4e\x13\xc6\xa7\xc4\x82\x19\xc1\x25\xe3\x30\xc4\x89\x5b\x42\x0e\x2f\x27\x50\xc2\x2b\x2d\x9f\x56\xb4\x5e\xc9\xf5\x04\xca\x6f\xbf\x6d\xa7\x57\x14\xc0\xa5\x01\xe5\xae\xcb\x9b\x70\xe6\xe2\xbb\x14\x17\xa6\x54\x48\xc2\xa5\xf8\x6f\x29\xd7\x43\x13\x0f\x67\xa1\x53\x7d\xb4\xf8\x26\x9c\x6e\x2b\x52\x50\x2b\x3f\x82\x24\x4c\xa2\x5f\x10\x1f\xdd\xe8\xe8\x8f\x90\x6e\x94\xa8\xc9\x88\xfe\xbc\xbe\xbb\x81\x3c\x8c\x16\xa5\xae\x9a\xd8\x81\x6e\x87\x42\xa7\x05\x13\x86\x90\x0f\x20\x67\x9e\x2c\xeb\x86\x06\xab\xc6\xa0\x07\xc2\x77\x7f\x24\x16\x5d\x24\xf4\xa6\x8a\x83\x54\xc6\x67\x0e\x95\x9f\x94\x97\x1d\x1d\x10\x92\x71\x6a\x12\xa3\xe5\x18\x8c\x53\xc7\x9a\x94\x90\x8d\x6a\x2f\xe4\xf3\x52\x80\x84\xc9\x39\x58\x22\x60\x1a\xa1\xb6\x98\x4b\xa6\xe6\xc8\xc0\x06\xc0\x6c\x26\x68\xb5\x34\xfc\x42\xbd\x1f\x8f\xe1\x3b\x29\xce\xcb\x55\xcd\x38\xb5\x9d\x9c\x18\x13\x34\xf0\x7f\x90\xd5\x0e\x9d\x69\x67\x7e\x9a\x2e\xcb\x7a\x11\x4d\xd3\x98\x6b\x77\xd1\x5b\xdc\x50\x23\x6b\xa5\x34\xb6\x82\x6c\x31\x67\xdf\x9f\xe2\xd7\x47\x3b\x34\xf0\x01\xe4\x51\x52\xde\x82\x7e\xad\x36\x01\x5f\x8e\xfe\xba\xaa\x0e\x76\x40\x37\x65\x9c\xd2\x70\xd6\x8e\x60\x41\x24\x89\x93\xdb\xa1\x45\x54\xcb\x3f\xba\x25\x13\x5b\xba\xd5\x2f\x54\xe9\xd0\x02\x18\x28\xc3\x69\xbd\x50\x3c\xb3\x4f\x9d\x11\xb0\x6d\xc8\x5a\xba\x83\xa8\x15\xe5\x2e\x42\x7e\x51\x70\x66\x20\x5d\xf3\x49\xe8\x33\x9e\x5e\x7e\xa4\x45\x68\xf4\xc1\x29\xa6\x34\x9e\x59\x7a\xa7\x7e\xe9\x26\xff\xa2\x3b\x31\x52\x49\x4c\x3f\x19\x32\x0a\x68\xf5\xc8\xf2\x41\xad\xb5\xd6\x50\xb9\xc8\x2e\x13\x3d\xd4\x39\xda\xee\x45\x7c\xdc\x76\x97\x2e\x19\xbf\x24\xc5\x7a\xd8\x4b\x04\xdc\xd2\x8c\x29\xc5\x21\xb7\x72\x2b\x42\xb9\x15\xd7\xe6\x1d\x7c\xa9\x68\x3d\xe4\x10\x24\x24\x3b\x9c\xd6\xc6\xaf\xd0\x3d\x42\x1d\xed\x3e\xcc\xab\xe8\x08\x8d\x76\x7a\x6a\x60\xd5\x66\x31\x4c\xcb\xed\x5c\x1f\x62\x85\xc4\xa0\x95\xe5\x0f\xe5\x78\x6d\xc2\x86\x0a\x41\x56\x68\x04\x4d\xed\xf3\xd3\x13\xa8\x7f\x92\xd7\x35\x50\xce\x19\x07\x56\x14\x0d\xe7\x74\x91\x4c\x5a\x00\xac\xa2\xa9\x12\x19\xce\xaf\xaf\x7e\xfb\xe9\x06\x54\xec\xc3\x8f\x66\xc3\x73\x89\x9f\x32\x38\x79\x34\xd0\x7b\x98\xbb\x0d\x58\x77\xc9\xd8\x07\x43\x81\xcf\x7e\x23\xa5\x3e\xf4\x04\xa6\x67\xb4\x18\x8a\x86\xa8\x67\x3a\x2e\x2d\x8f\x52\xbd\xb9\x4f\xe1\x3e\x6a\xdf\xbb\x09\xd5\xb3\xa0\xac\xcb\x78\xa7\x1b\x86\xf7\x6d\x59\x2f\x2e\x4d\xb6\xd0\xea\x5c\x22\x85\xcd\x2f\x7a\xaa\x03\xe6\x6b\xa7\x44\xa0\x3e\x9c\x17\xc8\x88\xd0\xd5\x49\x54\x2e\xc8\xe3\xb1\x8f\x3d\x9d\x68\x4f\xbf\x71\x0b\x11\xce\x6d\x28\x05\x70\xfa\x47\x53\x72\xba\x50\xfc\x48\x67\x33\xd4\xb4\xac\x57\xc9\x08\xba\x75\x82\x88\x74\xe1\x5e\x04\x27\xed\x1d\xdd\x89\x76\x4e\x14\x19\xfc\x42\x0b\xc6\x17\xaf\xf4\x36\x7f\x64\xb6\xfb\x17\x01\x13\xf4\xf3\x1e\xa7\x8f\x2d\x08\x5c\x63\x6c\x5e\xdf\xb4\xa9\xf2\x41\x91\x3e\x62\xe7\x3c\xe8\x59\x60\x3f\xcd\x53\x25\x9a\xae\xf0\x75\x79\xd3\xe5\x76\x3a\x37\x44\xdc\xce\x65\x9e\x6d\x23\xd6\x47\x81\x1c\xe3\xd3\x28\x8a\xf1\x9d\xc5\xc4\xe6\xf3\xb4\xd1\xa9\x22\xd2\xc6\x25\xa2\x4f\xd7\x46\xa3\xfc\x79\x6d\x44\xc6\xea\xf3\x5e\xde\x4b\x77\x61\x44\x1f\xd3\x45\x63\xb4\xb8\xb0\x0e\x26\x9b\xb2\x0e\xe0\xf4\xb2\x44\x1b\xa2\x7f\x47\xde\x83\x5b\x65\xd5\xd8\x87\x8c\xd6\x4f\xcf\x83\xda\xf4\xd3\x4e\xd6\xb7\x9a\x33\x19\x25\x50\x36\xda\x03\x98\x59\x37\x9b\x6d\xc9\x8a\x26\x5d\xe3\x7d\x89\xc8\x14\xdf\x10\xef\xa6\x4d\xf4\x0d\x7f\x73\x85\xc9\x5b\xb6\xd8\xb5\x65\xdc\x7a\x68\x3d\xd0\xf2\x90\xf2\xc9\x42\x27\x18\xdc\x8f\xd1\x9a\x72\xb3\x7e\x1b\x52\xe1\x33\x8d\xfe\xf3\x55\x96\xef\x20\x1f\xc7\xfb\x97\x88\xe0\xbb\xe2\x45\xb7\x30\xe9\x94\xef\xa9\xfa\x15\xf5\xbe\x55\x2d\xe9\x96\x40\x0f\x6d\x14\x0f\xac\x34\x3e\xff\x29\x78\xa8\x99\x84\x25\x6b\xea\xc5\x7c\xe4\x54\x09\x0c\x39\x5c\x83\x69\x71\xac\xd0\xb4\x6e\xb9\xb5\x5d\x42\xed\x86\x07\x4a\xc4\xc1\x11\x85\x06\x7e\x0e\x0a\x86\xcf\x06\xc5\x17\x85\xc4\x57\x0d\x88\x7d\x5c\xc5\x19\x44\x6b\xc3\x65\xb5\xb1\xbb\xf4\x0e\x23\x4f\xd4\xf8\x2b\xb1\x1b\x9d\x07\xcc\x12\x50\xca\xcb\xcf\x68\x7b\xfe\xa1\xb4\xed\x5d\x97\x8e\x9f\xc5\xcb\xaf\x5b\x98\xfd\x6b\xd5\x2e\xec\xf1\xb3\x9a\xf6\xd5\xa8\xed\xfe\x51\xf9\x5b\x1f\x17\x98\x12\x48\x6b\xda\x7f\x42\x45\x2b\xa8\x95\x3f\x43\x5e\x83\x4d\xc4\x55\x17\xcf\x14\xef\x82\x43\x8c\x56\xc5\x37\xc4\xb3\xc2\x2d\xd8\x1f\xa8\x44\x1a\x79\x48\xd5\x98\xf2\xf6\x97\x32\x5a\x7c\xf7\x79\x56\x1b\x73\x5a\x33\x51\xa8\x38\xce\x68\xed\xce\xc6\x96\x83\x0e\x71\xdc\x3f\xc9\x70\xbf\x84\xdf\xce\x63\xa2\xf5\xa9\x65\x38\xfb\x39\x1e\xe4\xbe\xd1\x78\x76\xa0\xc3\x61\x18\x69\x2f\x6a\xa2\x55\x2e\x77\xc6\x93\x3d\x03\xff\xbd\xde\xb0\x75\xeb\x7e\x46\xc1\x30\x40\x86\xc9\x2c\x38\x12\xc2\xc0\xd2\x1c\x56\x53\x41\xbd\xb3\x6c\x1d\xc0\xe8\xa9\x73\x4b\x8a\x3b\x5a\x2f\x66\x05\xa9\xaa\xe1\x00\x39\x7b\x23\xdd\xd9\x11\x52\xf2\x35\x25\x0b\xca\x45\x06\xd7\x31\xd1\xbc\xb9\xbe\x81\xa7\x43\x1c\xf4\x09\x7e\xd4\xcd\x10\x61\x43\xe5\x9a\x2d\x42\xcc\x34\x4d\x09\x5f\x89\x0c\x48\xbd\x1b\x9c\x65\xf0\x9e\xb3\x4d\x29\xe8\x2b\x52\xef\x2e\x02\x4a\xdf\xf0\x0a\x72\x98\x8f\xc9\xb6\x1c\xcf\x66\x3b\xc2\xc6\xa2\x29\xc7\xf7\x7f\x19\xf3\xa6\x3e\x79\x54\x9a\xee\xe7\x13\xaf\xa3\x3b\xa4\x49\xde\xb0\x5a\x62\x96\xfa\x75\xb7\xa5\x49\x06\x09\xd9\x6e\xab\xb2\x50\xc7\x0f\x63\x1c\x8c\x44\x6f\x36\x7e\xa1\x4b\xca\x29\xcf\xcc\x62\x92\x56\x4c\xcb\xa4\x6b\x4e\x97\x5a\xe4\x0d\x63\x77\x25\xcd\x7c\x1a\x29\xd4\x0b\xfd\x31\x4d\xd3\xb5\xb7\x73\xef\xb3\xe9\x96\xec\x2a\x46\x90\xda\x3c\x1a\xf3\x47\x80\x16\x6b\x19\x3f\xc1\x0c\x0b\x62\x8b\x9d\x9d\x5d\x3e\x2e\x0c\x46\x54\xd3\xe6\x54\x6c\x59\x2d\x70\xe2\xe8\x73\x9f\x25\x95\xc5\x7a\xd8\xf0\x6a\xe4\x7a\xca\x20\x79\xff\xee\xea\xd7\x64\x64\xdd\x32\x52\x1d\x64\xba\x9b\x7d\x84\x27\xe9\x47\xe9\xb0\x2c\x78\x8a\x6f\x87\x46\x0e\x89\xbf\xca\x53\x38\x56\xdd\xb3\x61\xc3\x1b\x10\xe6\xf4\x54\xc3\xb5\xb2\x89\x29\x3b\x04\xa9\x03\xa5\xce\xa2\x8d\x0e\x62\xb8\xde\x85\x24\xb2\x11\x70\x91\xc3\x5f\x5f\xbe\x6c\x13\x46\x9f\x38\x10\xd7\xe6\x0e\x37\xb3\xa7\xfd\xaf\x33\x98\xff\x83\x94\x15\x5d\x80\x64\x80\x91\x0e\x36\x7c\x54\x9a\x40\xaf\xe9\x40\x0a\x99\xc6\xc2\xf5\xa2\x9e\xa7\xc1\x73\x06\x7f\x7b\xf9\xb2\x75\xc0\x6b\x62\x38\xe5\xf4\x03\x2d\xe4\xf0\xd1\xaa\x3a\xd2\x50\xfb\xd8\xe2\x4e\x23\xc1\xaa\x7b\x3a\xf4\xb5\xa0\x4e\xf2\x3c\x9c\x38\xa7\xc1\xf3\x67\x58\x1a\xd9\x49\xad\x91\xb4\x63\x61\x6f\xca\x7d\xb6\x93\x0c\xb3\x6e\x5c\xf5\xfa\x24\x0f\xed\x31\x53\x8d\x5f\xbc\x18\xc0\x0b\xb8\xfa\xed\x27\xd0\xd5\x34\xfc\xf5\xdd\x96\x70\xb2\x09\x0f\xdb\xdc\x3b\x64\x5d\x03\x78\x31\xee\x4f\x71\xba\xbe\x37\x54\x93\xd3\xb4\xcd\x02\xa6\xfa\x14\x24\xa6\xf0\xe0\x1c\x7f\xeb\xc8\x6f\x25\x39\xcc\x52\xf8\x51\x57\xf6\xa6\x99\xd1\xf1\x9d\xfa\x19\xa6\x33\x9b\x0d\x7d\x46\x33\xfc\x75\xa8\xdc\x62\xd8\x76\x40\xd7\x91\x6c\xb3\x5b\xf4\x4e\x02\xd3\xe0\x43\x06\x27\x27\x01\xcb\x1d\x00\x9c\x01\x11\xde\x86\x89\xbd\x0d\xa1\xf1\xe3\xc3\xce\x67\x88\xba\xa3\xe8\xb8\x64\xba\x3e\xf6\xf3\xa3\x63\x97\xf4\xb4\x4f\xfc\xd5\x0e\xc3\x45\x35\x95\x74\xe4\xba\x4b\x28\xf5\x66\x44\x8f\x4f\x74\x0d\xc1\x9f\x62\xd9\xba\xc2\x51\x6b\x7e\x66\x40\xcd\xed\x15\xa5\x0a\x3c\x94\x72\x0d\xa2\x85\xfc\x8c\x49\x6d\x90\xc0\x9e\xf1\x18\x90\xdf\x2d\xe8\x92\x34\x95\x34\x03\x2f\x5c\x08\x40\x6e\x1f\xdc\x8a\xab\x7f\xdb\xbd\xb7\x13\xf0\x2f\xf2\x1c\x9a\x7a\x41\x97\x65\x4d\x17\x30\x05\xc9\x1b\x9c\x74\xb1\x54\x00\x24\xd6\xec\xe1\x2d\x23\xaa\x4e\xad\x8c\xe8\x79\xdf\x82\x54\x27\xf1\x1e\xd3\x4b\x06\xb0\xe8\xa7\xf7\x64\x45\x35\xd5\x08\x81\xe3\x2f\xc7\xa1\x43\x59\x7b\x8e\xf2\x9e\xd3\x2d\xe1\x14\x2a\xd5\xa5\xb9\x6b\x54\x19\x4d\x61\xfe\x4a\x6c\x49\x0d\x45\x45\x84\xc8\x13\xd1\x94\xe7\x7a\xa8\xce\x51\xa2\xac\x57\xc9\xc5\x5b\xfd\x90\xa6\xe9\xab\x31\xca\x5e\xcc\x6d\x54\x74\x2d\x3f\x3d\x6d\x39\x2e\x3c\x9b\x36\xb3\xac\xdf\x5f\xed\x2b\x4c\xe0\x55\xec\x75\x1b\x26\x66\x8a\xb6\xf7\x2b\x72\xfc\x16\xd3\x31\xf0\x14\xd3\x27\xc7\x46\xe1\x41\x38\x8d\xb7\x46\x66\x57\x84\x0f\x69\x59\xd7\x5a\x1e\x72\x03\x7b\x16\xc5\xab\xf5\x3f\x66\x30\xe3\xfd\x99\x59\x8a\xd5\x84\x54\x5b\x26\xcb\x32\x87\x01\x57\x0c\x2c\x0b\xc7\xf5\xf0\xf9\x87\x85\x7d\x44\x3a\x34\xd3\x15\x60\x7c\x52\xb9\x57\x9f\xcb\x84\x8a\xe1\x8e\x5a\x64\x6a\xb9\x00\x57\x65\x0a\x73\x46\x94\x32\x6c\xd9\x42\x6f\x3d\x55\xab\x68\xdb\xaa\xde\xbc\xd6\xa7\xc8\xd8\x76\xda\x7b\xf5\x4b\x49\x25\x67\xe1\xf5\x94\xc3\xa5\xa1\x4e\xeb\x6d\x73\x5b\x95\x45\x58\x13\x0e\xfa\x86\x3c\xd0\x61\x0a\x73\x5c\x09\x99\xdc\x9b\x05\x11\xdf\xee\xe7\xd0\x25\x98\x5b\x22\xd7\x98\x94\x82\xb9\x18\x96\x6d\x86\x1e\xf3\xf4\x34\x70\x47\xdf\x9d\x36\xa7\xd7\x27\xf0\x66\x35\xc9\x22\xea\xdc\xe5\xab\xfe\x54\x2a\x83\x59\x78\x04\x66\xb6\x27\xe3\x31\x6c\x28\x5f\x51\x55\xf3\x6b\x04\xe5\x36\xc6\x30\x72\xc2\x13\xbc\xa0\x0a\x78\x47\x77\x50\xd6\xd1\xf9\x1e\xd8\x4e\xd3\xf0\xcc\xc4\x3d\xc7\x97\xe1\x4c\x3d\xe7\x0b\x69\xfe\x11\x0e\xef\x4c\xfa\x99\xc8\xf2\x9e\xc2\x96\x29\x96\xfd\x47\x43\x85\xba\xe1\xa3\xae\x00\x51\x7e\xaf\x52\xd8\xff\x13\x6f\x57\xeb\x67\x7f\x56\x3c\xc8\x3e\xbb\xc4\xdc\x2c\x7c\xe8\x05\x67\x82\xea\xdb\xb8\xc6\x2c\x98\xe2\x48\xb6\xb2\x7d\xb6\x93\x16\xc2\x4c\x7a\x2b\x0e\x7d\x47\x3e\xcf\x5c\xac\x39\x5a\x55\x48\x3c\x59\x45\x38\x53\x6d\x0e\x8a\x0a\x2d\x26\x60\x9a\xb6\xea\xf5\x7b\x7d\xd4\xff\xbc\xf3\xba\x1a\x8e\xc7\xd6\x89\xba\xae\x11\x6e\x4f\x3e\xcf\x69\x07\xd7\x4d\x05\x9c\x5c\x78\x4b\xf5\xfb\x70\xed\xec\xb2\xa7\xa4\x2d\x9e\x78\xce\xbe\x3f\x4e\x96\x3a\x2d\x3d\x79\xa7\x1f\xb7\x8c\x4b\xc5\x6e\x03\x9a\xa9\xe7\x2c\x26\xb1\xe8\xb6\x31\xb6\xd2\xf7\xe1\xbc\xec\x15\xfe\xd6\x1f\x18\x8f\x3f\x30\xae\x3e\xa8\x4b\xa6\xd3\x0e\x31\x1f\x9a\xcb\x72\xa4\xde\x99\x2b\x90\xd3\x0c\x14\x9a\x72\xea\x3d\x2b\x17\x17\xd8\xfc\x0d\x53\xeb\xb4\x14\x5d\x08\xe4\xf6\x6a\x9c\xaf\xd5\x15\x3c\x53\xea\x50\x90\x93\x01\x26\x8b\xd0\xba\x90\xf7\x1b\x03\x75\xd5\x76\x1a\xdb\x88\xd3\xc8\x72\xc7\x52\x80\x20\x1b\x1a\x33\x54\x7f\x09\xd3\x33\x82\x69\xfb\x56\xb6\xd6\x05\x9e\xe0\x96\xb1\x8a\x92\xba\x0d\xab\x96\x36\x75\xd5\x57\xcd\xf6\x69\x76\x48\x10\xb9\x26\x3a\x31\x58\xdd\x0f\x0b\x5b\x54\xb7\x3a\x4d\xad\x57\xda\x92\x74\xb3\x95\xbb\x8e\x8f\xe2\x51\x35\x5e\xba\xa2\x32\x53\x95\x33\xb7\xd7\x02\x3f\x74\x6e\xac\xba\x0e\x8f\x23\xc1\x80\xfd\xd0\x06\x53\x00\x46\xc7\xcf\xe8\x4c\x21\x61\x2e\xef\x41\x53\xc3\xaf\xa0\xba\x02\xc7\xe0\xbe\x57\xab\xe8\x50\xbd\xee\x8d\xb4\xb6\x85\xa1\x97\xec\x65\xcf\xce\x74\x89\xae\xd3\x62\xc2\xdf\x4f\x06\xff\x0b\x00\x00\xff\xff\xf0\xc8\x18\x6d\xe5\x2f\x00\x00")
+var _libsuiIndexTs = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x3c\x7f\x73\xdb\xc6\x72\xff\xf3\x53\xac\x31\x1e\x0f\x98\x50\x90\xd3\xe9\xfb\x07\x34\xad\x97\x3a\x6e\xe2\xd6\x4d\xd2\xc8\xaf\xed\x8c\x9e\x46\x82\xc0\x23\x89\x08\xc4\x31\xb8\xa3\x64\x8e\xc4\xef\xde\xd9\xbd\xbd\x5f\x00\x48\xd9\x8e\x3b\xd3\x37\x9e\xb1\x48\xdc\xde\xfe\xba\xbd\xdd\xbd\xdd\x03\x17\xdb\xa6\xd4\x95\x6c\xe0\xf9\xf3\x54\x89\x5a\x94\x5a\xb6\x63\x78\x18\x01\xd4\x42\x83\xa8\xd7\x39\xfc\xf4\xe1\x3f\xde\xbf\xad\xc5\x5a\x34\x1a\x1e\xa1\xd9\xd6\x35\xcc\xe8\xcf\x74\x04\x50\x2d\x20\xd5\xbb\x8d\x90\x0b\xb0\xd3\x61\x36\x9b\x41\xa2\x74\x5b\x35\xcb\xc4\xe0\x02\xc4\x04\x33\x98\xcb\x72\x8b\x78\xb2\x3f\xb6\xa2\xdd\x9d\xf3\x04\x4f\x18\x31\xee\x47\x8c\xd6\xe1\xab\x1a\xa5\x8b\xa6\x44\x1a\x01\x2f\x31\x66\x0b\x1c\x61\x10\xf5\xda\x42\x95\xb2\x51\x1a\xca\x06\x66\xc8\x4a\xb6\x14\xfa\x7b\xad\xdb\xea\x66\xab\x45\x9a\xa8\xbc\x6c\x92\x31\x4e\x35\x94\xcb\x06\x5e\xbc\x40\xe0\x67\x33\x48\x12\xfc\xcc\x22\xde\x57\xcd\x5c\xde\x5f\x94\xcd\xa5\x11\xd2\x6a\xcf\x89\xe9\x08\xc9\xf5\x46\x36\xa8\xb1\x19\x34\xe2\x3e\x98\x48\x4c\x4d\x19\xb8\x15\x7a\xdb\x36\x04\x71\x75\xa5\xb6\xd5\x95\x9b\x87\x60\x13\x8f\x86\x67\xec\x49\x3a\x3f\x8f\x16\x61\x3f\x1a\xb9\x55\xec\x60\xb9\x6a\xa5\x24\x54\x39\xb0\xd6\x26\xd0\x14\x6b\x91\x83\x59\x1e\xc3\x36\x63\x43\xbd\x94\xb5\x54\x42\xe9\xf4\xfa\x42\xfd\xfd\xef\x79\xd9\xcc\x9e\x3f\x20\xfc\xfe\xf2\x7a\x3c\x44\x48\xe9\x42\x8b\xd4\x73\x49\xe8\xf4\xaa\x52\xd9\xaa\x68\xe6\xb5\x68\x15\xcc\xbc\x10\xd9\x7d\xa1\xcb\x15\x3c\x3e\xc2\xc3\x7e\x6a\x01\xcf\x05\xea\xa8\x50\xbb\xa6\x04\x87\x3d\xbd\x15\xbb\x09\xdc\x15\xf5\x56\x4c\x40\x17\xed\x52\xe8\x78\x21\x19\x3d\xcc\x62\x6a\x17\xb7\x62\x77\x89\xa8\x81\x67\x21\x80\xf9\xf0\xf8\x18\x30\x82\x6a\xf1\xcb\x6d\x91\xf9\x75\xb6\x4f\x8e\x2f\x32\x49\xff\xcb\xcd\xef\x30\x73\x03\x96\x6e\xce\x7f\x27\xee\xb9\xd2\x72\xf3\x6b\x2b\x37\xc5\xb2\x40\x11\xf3\x40\x58\x8f\xd7\x23\xc8\x54\x6c\xa1\x48\xea\x64\xe3\xe7\x27\x13\x48\x74\xbb\x15\xd6\x6c\xf1\xdf\xde\x52\xdb\xdb\x67\xc5\x7d\x51\x39\x65\xa5\xac\x4f\xcb\xb6\x9b\x69\xa4\xa9\xd4\x79\xcc\xa2\xd3\x1d\x83\x01\x9c\x59\xe6\x96\x4f\x30\x37\x36\xdb\x83\x18\x74\xb3\x73\x58\x14\xb5\x12\x96\x2a\x6a\xbe\x47\x33\x54\x85\x31\x4b\x0b\x4e\x9b\x1a\x3f\xa0\x5f\xda\x14\xad\xd9\x5b\xf1\x92\x66\xe6\x39\x9b\xfa\x59\xcf\x9a\xc9\x8a\x3d\x75\x8b\xc5\x78\xb3\x4f\x20\x7d\x7a\x0a\x3f\x54\x6a\x43\x56\xac\x57\x02\x48\x70\x28\x57\x45\xb3\x14\x50\x6e\x95\x96\x6b\x10\x77\xc8\x99\x96\x96\x47\xc7\x21\xe3\x30\xca\x36\x50\xc6\x37\xbc\xa1\x89\x6f\xf1\x09\xeb\x32\x37\x28\x93\x49\xc0\xd2\x5c\xe8\xa2\xaa\x73\x78\x80\x5b\xb1\xcb\xc1\x6f\x90\x3c\xde\x27\x79\x47\x27\x81\x55\x38\xe1\x0d\x6b\xd9\x9c\x65\x31\xa4\x89\x25\x06\x21\x3f\x33\xb4\xe1\x71\x91\x95\xf7\xab\xb4\xf9\x7e\xa4\x1d\xec\xcd\xf9\x56\xec\xac\x2e\x51\xcb\xcf\xd0\x41\x3f\x3e\xda\xad\xd5\xf5\xbe\xf0\xec\xc0\x1e\x8b\x7c\x9c\x65\xca\xea\xef\x16\x66\x90\x20\x33\x79\x02\xdf\xa2\x32\xa6\xc1\xe0\xdd\x90\x93\xbf\x65\xd1\x0c\xc8\xef\x4a\x36\x43\x50\x09\x0e\x9c\x14\x5a\xb7\x27\x21\xf6\xd0\x9e\xbd\xdf\x40\x58\xcf\xaf\x6e\x77\x3d\x0b\x82\x7f\x3b\xff\xe5\x67\xb4\x4a\x25\xd2\x3b\x66\x00\x60\x0f\x25\x99\x50\x2a\xc6\xfd\x19\x5e\x5e\x2b\xf1\x7e\x14\x0c\xdf\xe1\xd8\x7e\x3a\xb2\xca\x7f\x5f\xa9\x58\xfb\x16\xa5\x91\x13\x85\x40\x1f\xfc\xb0\x9f\x7e\xad\x05\x21\x94\x76\x45\x02\x52\xa8\x34\xc5\x3a\x2d\x2c\x32\x06\x5c\xc8\x16\x52\xdc\xb8\x15\xcc\xe0\xe5\x14\x2a\x78\x05\x08\xa3\xb2\x5a\x34\x4b\xbd\x9a\x42\xf5\xed\xb7\x9e\x92\x47\x88\xa1\x01\xe1\x2e\x2a\x76\xeb\x46\x04\x1c\xca\x30\x30\x65\x4a\x17\xad\x56\xff\x5d\xe9\x55\xca\xf6\x30\xf6\x78\x2c\xa6\x5b\x46\x63\xa6\xb4\x62\x53\x17\xa5\xb0\xf0\x13\x48\x42\x27\xfa\x05\xf6\xd1\xb7\x8e\x61\x0b\xe9\x5b\x09\xfe\x43\x2e\xd4\xc5\xed\x25\xcc\x42\x6b\x41\x12\x19\x6d\xec\x80\xb7\x43\xa6\xd3\x41\x13\x9a\x90\x37\x20\x27\x9e\xae\x9a\xad\xf3\xc2\xe1\x68\x80\xc2\x93\x3f\x62\x8b\xce\x12\x06\x5d\xc5\xc1\x54\xc6\x7b\x0e\xf4\xd9\x46\xcb\x2e\x1d\x50\x5a\xb6\x82\x1d\xa3\xcd\x31\x64\x2b\x5c\xd6\x44\x40\xd6\xaa\x3d\x90\xf7\x4b\x01\x26\x74\xce\x41\x88\x80\xb3\x08\x6b\x27\x73\xc9\x29\x29\x19\x59\x03\xb8\xba\x52\xa2\x5e\x70\x7e\x41\xdb\xed\xf4\x14\xfe\xaa\xd5\x49\xb5\x6c\x64\x2b\x2c\x91\xe7\x2c\x82\x41\xfc\x9f\x98\xd5\xa6\x4e\xb4\xb1\xdf\xa6\x8b\xaa\x99\x47\xdb\xd4\xe6\xab\x76\x19\xbb\xd8\x9d\x92\x43\xcc\x86\x29\x83\x9b\x50\x76\x32\x67\x4f\x8f\xf2\xeb\xa3\x04\x19\x7d\x80\xf2\x68\x52\xde\x41\xfd\x7d\x5d\xff\x29\xec\xdf\xd7\xf5\x41\x02\x62\x5d\xc5\x2e\x0d\x77\xed\x04\xe6\x85\x2e\x2c\xfa\xe3\x41\xd4\xc0\x3f\xb8\x90\x89\x33\x5d\xf4\x0b\x59\x1a\x0e\x80\x76\x3d\x3e\xac\x44\x60\x3c\x34\x0a\x72\x41\x81\xdf\x9e\x48\x26\xf4\xad\x2c\x6a\x4a\x75\xeb\x4a\x69\xd1\xc0\xcd\x0e\x54\x2e\x9b\xfc\x15\xf2\xf1\x3a\xe0\xf7\x56\xec\x60\x06\xe7\x94\x79\xa7\x38\x38\xce\xb4\x7c\x2f\xef\x45\xfb\xa6\x50\x22\x65\x06\x8d\x6c\x73\x51\xd6\x45\x2b\xd0\x68\x42\x8e\x63\x67\xa4\x72\xd4\x95\xf5\x5e\xe8\x70\xdc\xb4\x67\xbd\xac\xc6\xe0\x45\xb2\xb8\x75\x2c\x60\xa6\x36\x75\xa5\xd3\x64\x92\x8c\xb3\x75\xb1\x49\xd3\x66\x0c\xb3\xd7\xd0\x64\xba\xad\xd6\x69\x87\x41\x26\xc4\x61\x84\x50\x65\x55\x53\xd6\xdb\xb9\x50\x98\xb2\x0f\xb8\xde\xb2\x79\x42\x02\x7f\xf2\xb2\x93\x64\x2d\xb2\xfb\xa2\x6d\xd2\xeb\x8b\xf3\xbf\xbd\xbb\x84\xe7\x0f\x65\xb3\x07\x94\x54\x91\xba\xb7\x8d\x93\xd2\x2c\x0a\x9f\x51\xae\x27\x21\xa1\xf1\x90\xe3\x3a\xbc\xf8\xbd\x44\x0c\xe9\xd9\xf0\x3f\x60\x4c\x5d\xa3\x6d\x45\x33\x17\xed\x01\xb3\x9d\x80\xdc\x84\xd9\x6d\x7f\xb3\xa3\xdc\x1a\x5a\xe7\x49\x7e\x23\x74\xbc\xe1\xdd\xf4\x69\xb8\xb7\xda\xec\xed\x47\x51\x06\x54\xc6\x07\x5d\x31\xa9\xe9\xca\x1e\x03\xe8\x9b\xd9\x4f\xff\x2e\x76\x6a\x02\x18\xa5\xcc\x27\x3e\xb4\x00\x2a\x70\x62\xcf\x0d\x86\x6b\x36\x4b\xdc\x4a\x36\x9d\x18\x38\x62\xd9\xd0\x83\x06\x12\x9f\xdb\x2c\xb9\x6c\x21\xdb\xb7\x45\xb9\x4a\x07\x13\x46\x4b\x87\x22\x9f\xc3\xdc\x31\x1a\x44\xe5\x32\x33\xbb\xcc\xf8\x90\x8e\x7f\x30\x83\x20\x70\xd9\x6d\x6f\x65\xfc\x0a\xe4\x11\xd5\x51\xf2\x61\xfc\x45\x45\x18\x6c\x2f\x5e\x30\x5a\x2a\x2a\x78\x8a\xfd\x9c\x20\xc4\x15\x26\x90\x9d\x6c\xe0\x50\x2e\x60\x44\x58\x0b\xa5\x8a\x25\x0a\x21\x32\xfb\xf9\xf1\x11\xe8\xbf\xe4\xfb\x06\x44\xdb\xca\x16\x64\x59\x6e\xdb\x56\xcc\x83\xbc\xc5\x6f\x42\x02\xb1\xbb\x90\xb6\x05\xfc\xc4\x07\xe3\xb7\x38\x94\xc3\xf3\x07\x46\xbd\x87\x6b\x6b\x3d\x21\x87\xf1\x16\xe4\xf2\x0e\x58\xc3\x82\x17\x2f\x68\x60\xc0\x30\xfd\xc9\x07\x4d\x91\x0f\x74\xb9\xb1\x4b\x1e\x30\xd4\xdc\x50\x78\xde\xc6\x45\xdf\x8f\x46\xa7\xdf\x7c\x33\x82\x6f\x00\x53\x64\xd1\x90\xeb\x20\x0a\x70\x5f\xe9\x15\x7d\x5d\xcb\x79\xb5\xa8\xd0\x75\xb3\x5f\x57\x39\x41\x9c\xac\xe5\x5c\x81\x4b\x62\x21\x95\x2d\x0d\xe3\x5e\x1b\x4f\x40\x64\xcb\x0c\xca\xba\x2a\x6f\xb3\x4d\x4b\x13\x32\x3c\xdd\x23\x2d\xfe\x3e\x01\x7c\x30\x01\xde\xbd\x14\x29\xca\x62\xa3\xb7\xad\x98\xc0\xa6\x50\xaa\xba\xc3\x4d\x2b\x6e\xe4\xb6\x29\x45\xbe\x56\x50\x34\x73\xd0\xab\x56\x6a\x5d\xe3\xf7\x11\x7c\x73\x3a\xbc\x8b\x4d\x90\x49\x47\x00\x51\x59\x67\x04\x51\x61\x07\xf5\x60\x20\x45\x9b\x43\x4a\x53\x73\xb3\x86\xe4\xdf\xef\x64\x35\x1f\x85\x1b\x7b\x83\x69\x34\xda\x2e\x66\xc8\x1c\x13\x32\xe3\x99\x0d\x00\x9e\x16\x60\x86\xa7\x5a\xad\x2e\x5e\x52\x36\x8e\x09\x3d\x6a\x2a\x87\xdf\x44\x29\xdb\xf9\x2b\xa6\x0d\x45\xb3\x7b\xed\xbd\x84\xb3\x6e\x4e\xeb\xeb\x3a\xb6\xeb\x7e\x92\x1d\x2e\x43\x32\x26\x8b\x7d\xd8\xdb\x30\x81\x0f\x11\x39\x64\x59\x96\x16\x75\x7d\x81\x9c\x5d\x22\xd0\xc3\x7e\x8c\xfe\x6f\x68\x67\xc4\x26\x9d\x18\x93\x7e\xd7\xdc\x15\x75\x65\xa3\x88\x33\x86\x64\x02\x36\x91\xc4\x74\x97\x24\xce\x54\x5d\x95\x22\xfd\x6e\xec\xfc\x47\xba\x96\x73\x52\x65\x28\xdb\x85\x3f\x96\xe3\xe6\x5d\x4b\x17\x5f\xf3\x90\x7d\xbb\xb9\xad\x3f\x98\x51\x4c\x5b\x54\x8d\x98\xc3\x19\xfc\xbc\x5d\xdf\xd8\x6a\xcd\x18\x72\xc0\x2a\x0a\xce\x45\xab\x66\xad\x63\xc6\x01\x33\xb7\xc2\xd6\xe3\x22\xee\xcc\x5a\x55\x28\xba\x06\x2a\x04\xcd\x20\x82\xa0\x63\x0b\x62\x87\x33\xf8\xa7\xbf\xbc\x84\x3c\x1e\x0e\xd3\x91\x05\x86\x71\xa4\x6a\x1e\xe2\xca\xeb\x6a\x8d\xb6\x55\x34\xbb\xc8\xe5\x31\x6b\x9c\x4d\x79\x05\x01\x94\xb5\x28\xda\x0f\xd5\x5a\xc8\xad\x4e\x69\x36\xab\x04\x80\xbe\x51\x15\x57\x5b\x80\x94\x94\xbb\x68\x18\xd1\x84\x24\xe0\x09\xfb\xa8\xca\x4b\x4c\xdb\xbd\x73\x58\x6a\x0b\x71\x40\x6a\x3b\xfc\x84\xd4\x75\x41\xe7\xed\x97\x4f\x0a\x4b\xb6\xde\xc8\x7b\x98\xc1\x0f\x85\x16\x59\x23\xef\x6d\x86\x67\xd8\xc6\xb1\x13\x83\xef\x95\x11\xce\x4d\xb6\x31\x7e\x3a\x8a\xfd\x28\x13\x6f\xe4\xbd\x1d\x71\xfa\xe9\x2a\x86\xf3\x57\xf6\x47\xec\x5e\xb0\x62\x25\x37\x50\xb4\x02\x8a\xcd\xa6\xae\xc4\x1c\x0a\xcd\xee\x09\xfd\x9b\x5d\xf9\xb9\xf3\xd0\x95\x22\xf9\xc5\x1c\xea\x42\x8b\xd6\x79\x03\x93\x90\x60\x22\xf9\x60\x1d\x5b\x0e\xcf\x9e\x91\x26\xbb\x8e\xce\x0d\xf0\x77\xb3\x47\xbb\x75\xdc\xbe\x8f\xf2\x25\x24\x9a\x8d\xbe\x14\x4b\xf1\x04\x97\x71\xca\xf1\x6c\x36\xe3\x07\x14\xc7\x1a\xfd\x21\x4a\x3a\x62\x45\xee\x63\x84\xac\x1b\x0f\x4b\x5f\xad\x4b\xff\x41\x2c\x8a\x6d\xad\xd3\xf1\xe0\x5c\xd4\x63\x77\x62\xa7\xc2\x7b\x60\xa6\x0c\xb6\x26\xf9\xf0\xac\x15\x6b\x79\x27\x48\xee\xf7\xbc\xa1\xa9\xa5\xe2\x12\x30\x9b\xff\xa9\x08\x23\xae\x4b\xb0\xf8\xa4\x54\x44\x57\xcc\xe7\x9f\x88\xeb\x50\xa2\x58\x35\x55\xdc\x34\x08\x03\xc5\x4d\xd5\x18\xfc\x6e\xcd\xde\xd6\xeb\x60\xbd\x82\xb4\xdf\x8e\x1e\xf0\xee\x98\xf9\xa3\xdb\x4e\x92\xa8\xf3\x32\x8b\xd3\xa3\x41\xcf\xfd\xc6\x1d\xcb\x30\xe8\x41\xa5\xa0\x15\x7f\x6c\x2b\x3c\x2f\x61\xa9\x89\x08\x13\xa7\xd8\x82\xf2\xee\xbc\x6f\x10\x36\x1d\xff\x01\xf3\xda\x5b\xb1\x53\x81\x0c\x84\xa5\x1f\xdc\xcc\x5f\x1f\xdf\x2c\xb8\x4d\x70\x6d\x08\xbe\x40\x0f\x7f\x71\xd9\xad\x3a\x1e\x04\x19\xaa\x91\x39\x0d\xfa\x82\xda\x70\xc5\x0c\xcd\x6b\x00\xf8\xa2\xba\xec\x97\xc9\x90\xd1\x4e\x99\xcc\x25\xe7\x9b\xad\x5a\x1d\x45\xe4\x8a\x67\x38\x85\x8b\x67\xe3\xae\x9f\xfa\x1c\x6e\x4c\x36\x1d\x71\xe3\x72\xf5\x4f\xe7\x06\xa7\x7c\x0d\x6e\xf0\xe0\x7e\x32\x58\x42\xa4\xb3\xfb\x27\xf1\x62\x70\x74\xca\x8a\x34\xd3\x05\xfe\x03\x78\x06\x0b\x6e\xd6\x44\xff\xa5\x62\x1f\x4e\x93\xbd\xc9\xf8\x73\x3d\x54\x8d\x71\x86\xca\xf3\xef\x37\xad\x25\xab\x2e\x10\x96\x8d\x2e\xda\x75\x57\x57\x9b\x62\x29\x92\xbe\xf0\xec\x3b\x60\x66\xfb\x98\x88\xef\x72\xda\x81\xe2\x52\x98\xeb\xf1\xde\xc8\xf9\xae\x0b\xc3\x6e\xdb\x6b\xc0\x03\x0c\x24\xb9\x16\xc8\x34\x2d\x27\xbc\x8c\x81\xb3\xe9\xcf\x1c\x38\x4b\x7c\xda\x21\xd7\x33\xb2\x1f\x47\x5c\x47\xe5\x52\x5e\x0e\x2b\x8e\x2f\x0f\x79\x89\x86\x7a\xa8\x65\xb3\xef\xf4\x9e\xb8\xf8\xee\xfb\xc2\x07\xcb\xee\x07\xce\x63\xde\x05\x12\x7a\x68\xa4\x86\x85\xdc\x36\xf3\xeb\x89\x63\x25\x10\x24\x74\x7b\x81\x18\xfd\x4a\x44\x28\x1a\x97\x23\x3c\x93\x41\xc1\x74\x3a\x1a\xb6\x10\x84\x88\xed\x23\xb2\x0e\x1c\x0e\xda\xaf\x4f\xda\xc5\x97\x5a\xc5\x57\xb5\x89\x7d\xdc\x16\x73\xb1\xd0\x72\x62\xdb\x1e\xbd\x12\x67\x42\x26\x40\x60\x97\xe1\x61\xea\xf7\x4a\xbf\xfd\x8c\xb9\x27\xbf\x57\x76\xbe\x23\xe9\x0f\x22\xf6\x11\xed\x0b\x17\x9e\xfd\x63\x9a\x17\x52\xfc\xac\xa9\xfd\x24\x61\x2d\xe7\xa2\x3e\x9e\x24\x28\xa1\xff\x8b\x4b\x28\xe9\xa6\xd0\x2b\x1b\xf0\x5c\xcb\xb2\x68\x76\xc1\x82\xf5\xad\x10\x0f\x18\x18\x61\xb0\x04\x64\x76\x8a\xa1\x6d\x9e\xe1\x4e\x91\x37\xbf\x8b\x52\xd3\xe5\x8c\x60\xe8\x2c\x84\xcb\x3b\x11\x1a\xe3\x3b\x1d\x61\xf5\xaa\x73\xc4\x1d\x8e\xbe\x08\xcf\xe1\x16\x4e\xe0\xbb\x81\x90\xcb\xbc\xd9\x82\x0d\x3a\x71\xde\xc8\x96\xbd\xc7\x47\x57\xce\x31\xa3\xb3\x81\x76\x73\x07\xc2\xb1\xed\x63\x18\xeb\x22\x02\xf4\x36\x19\x62\xe8\x30\x7d\xe9\xce\x99\x9c\x24\xba\x35\x5a\x06\x6b\x44\x4b\x4a\x5b\x2a\x5c\xad\x60\x85\x78\x97\x9a\x1a\x80\x03\xef\x24\x76\xa8\x0d\x9b\xd2\x0d\x96\xb6\x39\x09\x36\x73\x75\xb1\xfc\x19\x93\x37\x54\x48\x72\xfe\xf6\xfd\xdb\x37\x1f\x68\x35\x1d\xc0\x7a\x5b\xeb\x6a\xe3\x8f\x74\xd6\x89\xc1\xf7\x6d\x5b\xec\xb2\x45\x2b\xd7\x1e\x9b\x69\x26\x88\xf9\x2f\x9c\xdd\x9a\x62\xb6\xc9\x9b\xbd\xb9\x99\xef\x51\x5f\x8d\x5d\xa1\x5d\x4d\x63\x5c\xe5\x4a\x94\xb7\x37\xf2\x63\xe0\x86\x6d\x43\x80\x47\x84\xaf\x19\xa1\x10\xa8\x36\x3a\x57\x61\x80\x46\x3f\x65\x47\x89\x94\x8a\x83\xb1\xfc\x28\xd4\xc1\xcb\x50\xd8\x19\x61\x70\x80\xeb\xaa\xd9\x6c\x35\x55\x38\x66\x96\xf2\x25\xf9\x05\x12\x7c\x96\x3c\x7f\x40\xca\xfb\xe4\xf2\x9a\xe7\xb0\x54\x26\xc8\x10\x9f\xd6\x18\x5e\xc3\x77\xb8\x34\x4e\x63\xab\x42\x05\x6b\x47\x7c\xc6\x89\x4f\x5f\xdb\x84\x6f\xec\x00\x00\xb2\x45\x55\x6b\xd1\xa6\xe9\x8d\xfc\xe8\xb5\x7c\x23\x3f\x66\xc4\xad\x98\x47\xc0\xb4\x24\x3d\xc8\xb8\xac\xb9\x1f\x45\xc4\x1d\xb7\x8c\xef\xc8\x9a\x35\x54\x36\x49\x6c\x67\xdb\x2c\x64\x4b\xf7\x28\xbc\x54\x5d\xb4\x5c\xe8\x45\xd0\x04\xfb\x83\x78\xb9\x2d\xb7\x15\x98\x18\x6a\x3c\xed\x77\x40\x63\x88\xee\x0e\xb3\xa3\x4f\xb8\x78\x02\x63\xf7\xee\xa6\x78\x1f\x6d\x1f\x79\xad\x45\x07\x63\x23\x47\xe0\x99\xbb\xc2\x86\x6c\x0f\xc1\xa3\x8b\xa5\x0a\x53\xb0\xd1\xd1\xa8\x4c\xdd\x64\x68\x9f\xab\x9c\xf0\xc4\xa7\xb7\xaf\xe3\x21\x82\x64\x76\xc6\x52\x1c\x77\x18\xd1\x6a\xdb\x2d\xd2\x33\x82\x79\x25\x13\x46\x87\xb7\x98\x12\xbe\x5f\xe3\x1e\xe5\x90\xd0\x4e\x4b\x58\x0b\x8e\x66\xef\x30\xcd\x29\x47\xb0\x0e\x5d\x3b\x34\xd4\xd0\x93\x3d\xeb\x5a\xaf\x5f\x9a\x78\x71\xec\xf2\x74\xdb\x0f\xd6\x45\x3b\x75\x62\xf1\x58\xaf\x58\x57\xe0\xc2\x2c\xc5\x58\x8e\xad\x6e\xd0\x11\x8f\x1b\x5d\x3c\x0a\xfd\xab\x47\x34\x61\xe8\xea\x11\xc0\xcd\xf6\xe6\xa6\x16\xca\xd4\x22\x6d\x01\x3e\xbe\x95\x84\x2c\xe4\x10\x30\xc2\xf7\x92\xfc\xfd\x23\xcc\xac\xf9\xe3\x78\x1a\x76\x04\x06\xb3\x0c\xdf\xf6\x27\xa5\xe1\x25\x22\xda\x45\xb8\xb4\x9d\x23\xc6\x27\x5c\x44\xe2\xfd\xda\x2f\x35\xf7\x7a\x49\x41\x4f\xef\xbc\x8f\xcf\x2a\x99\xd1\x22\x3e\x75\x10\x9f\x05\xee\xa0\xfd\x51\x68\xec\xea\x1c\x62\x35\x36\x81\x3e\xc7\x03\xed\xa7\xa7\x9b\x4c\x71\x8b\x89\x33\x72\xa1\xe2\x42\x7c\x6c\x3f\x4e\x69\xad\xbd\xc5\x73\xa8\xe5\xf4\x27\x1b\x4e\x5f\xd2\x6e\xba\x8e\x8b\x3a\x8e\xd7\xb0\xf5\x66\x0f\xd0\x03\xc3\xf1\x22\x0f\xad\xc6\x93\x0b\x1d\x2e\xc3\xc4\x68\xd1\x64\x4d\xd5\x62\xc7\x9a\x1c\x58\x78\xaa\x33\x85\xa4\x2c\x01\x66\x30\x34\x90\x34\xb9\x0a\x6e\xf2\xa2\x61\x19\x8f\x6b\xb2\x43\x73\xe7\xa6\x73\x6f\xd6\x84\x81\x9b\xa2\xbc\x15\xcd\xfc\x8a\x2a\x84\x23\x6c\xa1\x6d\x75\xd4\x19\x5a\x89\x62\x2e\x5a\x95\xc3\x85\x4d\xcb\xcd\xdf\xcb\x8b\x4b\x78\x3c\x54\xef\x7a\x84\x9f\xcc\x34\xdc\xcf\x6b\xa1\x57\x72\x1e\xe2\xcc\xb2\xac\x68\x97\x8a\xa2\xd4\x68\x9c\xc3\xaf\xad\x5c\x57\x4a\xbc\xa2\x5e\x90\x3f\x19\x6c\x5b\xec\xfe\x5c\x9f\x16\x9b\xea\xf4\xea\x6a\x57\xc8\x53\xb5\xad\x4e\xef\xbe\x3b\x6d\xb7\xcd\xf3\x07\xe2\x74\x7f\x3d\x1d\xb8\x6e\xc3\xa7\x52\xd5\x2e\xfe\xcc\x99\xe0\x22\x79\x5e\xaa\x76\x91\x5c\x42\xee\x8c\x81\xb5\xe1\x6e\xf1\x26\x6f\x64\xa3\xb1\xfd\xf4\x01\xa3\x55\x0e\x09\xd5\xca\x4b\x2a\xea\x9e\xe2\xb2\x27\x28\x2f\xc0\x6f\x62\x21\x5a\x6c\x81\x98\xf3\x71\x56\x4b\x03\x93\xad\x5a\xb1\x30\x20\x6f\xa4\xbc\xad\x44\xee\x1d\x56\x49\x0f\xcc\x20\xf6\xaf\x90\x19\x38\x83\x07\x48\xfe\xe7\xe4\x7c\x5b\x9d\xbc\x41\xe6\x72\xa0\xc7\x7b\x3a\xc0\x8c\x1d\x30\xf3\x39\xb1\xc5\x5e\x1b\xaa\x77\xb5\x2c\xf0\x9e\xc8\x03\xaf\xca\x04\x70\x21\x60\xb0\xf9\x86\x35\x19\x98\x75\xcd\x95\x71\xf0\x76\x72\xde\x61\x23\x1b\x85\xfb\xd9\xdc\x22\x5e\x08\x5d\xae\xd2\x6d\x5b\x4f\x1c\xa5\x1c\x92\x5f\x7f\x39\xff\x90\x4c\xc0\xf2\x46\x04\x72\xfa\xdf\xd5\x51\x38\x33\x10\x1f\xb5\xc3\x65\x91\x67\x5a\x7c\x74\x25\x76\x7b\xe6\x23\x13\xea\xbf\x69\xc0\x71\x16\xd1\xe0\x45\x6d\xfc\xdb\x71\x72\x7c\x48\x0a\x3c\x1a\xa2\x1f\xf7\x72\x46\x47\x1d\x2f\xdb\x6e\x15\xbc\x9e\xc1\x3f\xbf\x7c\xe9\xf1\x74\xfd\x19\xe2\xb5\x2e\x8d\x41\x30\x8b\x18\x7c\x9c\xc3\xf5\xbf\x16\x55\x2d\xe6\xa0\x25\xb5\x4e\xc0\x5a\x35\x79\x2f\xd4\x9a\xb1\x6f\x4f\xa8\x94\x73\x47\x85\x3e\x33\x6a\xfa\x9c\xc3\x5f\x5e\x72\xb3\xc9\x39\x09\xde\x5a\x59\x2b\xf0\x8c\x99\x3e\x58\x56\x27\x40\x53\x7c\xb5\x62\x34\x38\x49\xc9\xfa\x4e\xa4\xfe\xc6\xc8\x60\xb3\x74\xd8\x9f\x9f\x05\x9f\x3f\x43\xd2\x48\x4e\x61\x85\x14\x3d\x09\x07\x23\xc1\x93\x44\x72\x0c\x06\xe3\xe9\xb0\xb0\x47\x34\xb4\x0f\xee\x08\x9c\xff\xed\x1d\x98\x3b\x37\xd8\xc5\xff\xeb\xa6\x68\x8b\xb5\xaf\xee\x05\xcf\x30\xff\xa3\xe6\xfc\xa0\xe7\x35\xb7\x80\xb0\x39\xef\xe6\xe6\x41\xa5\xee\x31\xf0\x97\xdd\x6e\x3d\xae\xc7\x60\x23\x1d\x07\xcd\xb9\xf5\x2c\x67\x1e\xcd\x21\x37\xf4\xb2\xd6\x49\x3f\x8c\x3a\xf5\x3b\xe4\x05\xac\xbf\x74\x4c\xc5\xee\xf2\x2c\x18\xc8\xf1\x45\x20\xf7\x15\x8f\x6f\x63\x28\x94\x97\x61\x6a\xbb\xae\x08\xd3\xbd\x3a\x3f\xb8\x7e\x5e\x7c\x57\xa2\xc4\x48\xee\x68\xec\xaf\x8f\xae\x5d\x32\x30\x3f\xe1\xf5\x73\xc2\x0a\x73\xc8\x72\xc5\xc5\xfe\x51\xcb\xbc\xd0\x62\xd6\x27\x7a\xa9\xc5\xdf\x89\xb6\x65\x93\xa3\xd2\xfc\x2c\x41\xf0\xbb\x50\xc4\x8a\xb9\x4c\xa2\x3a\x98\x9f\x10\xa9\x8b\x24\x90\xe7\xf4\x14\x30\xed\x9c\x9b\xee\x23\x2f\xbc\x72\x26\x00\x33\xfb\xc1\x25\x02\x5c\xd3\xe0\xf6\x83\x03\xb0\xfd\x08\x5a\xea\xf0\x7a\x01\xa6\xf0\x90\x77\xa0\x02\x44\x6a\x25\xef\xdf\x4b\x74\xe7\x7c\x04\x1b\x78\xde\x41\x49\xef\x75\x40\xde\x87\x0c\xd0\xa2\x9e\x7e\x2d\x96\xc2\x64\x40\x21\xe2\x78\xe4\x38\xea\x10\xd6\xde\xca\xfd\xb5\x15\xf8\x42\x03\x60\x18\xc4\xfd\x6b\xfa\xf4\xcc\x29\x5c\xbf\x52\x9b\xa2\x81\xb2\x2e\x94\x9a\x25\x6a\x5b\x9d\x98\xa5\x3a\x41\x70\x6c\x0f\xbe\x46\x56\xab\x66\x99\x65\xd9\xab\x53\x84\x7d\x7d\x6d\xad\xa2\x27\x0f\x66\x16\xb1\xe2\xac\xb1\xd8\x33\xa0\x5c\x40\x7f\xd6\xd0\x0b\x71\xe0\x59\xec\x4d\x60\xff\x0d\x02\xd5\x3a\xcc\xc8\xf1\x77\xe2\x8e\x21\xcf\xd0\x7d\xb6\xf8\x22\x9d\x0d\x13\x9c\xe0\x86\x75\x61\x2e\x09\xe3\x87\xac\x6a\x1a\x03\x8f\x37\x4e\x88\xfa\x38\xb2\x57\xab\x7f\xf4\x60\xac\x7d\xce\xc9\xc8\xaf\xe0\xdd\xa8\x56\xd8\xe4\x37\x0d\x52\xd8\x40\xb2\x70\x5d\xc7\x07\xab\xc2\x16\xed\x03\x64\x59\x46\x5f\x26\xf4\xc9\xe7\x7a\xd1\x05\x08\xbc\x93\xa8\x72\x8a\x49\xfe\x32\x57\xe8\x33\x22\x97\x61\xdb\x36\xa6\x66\x4f\xb3\xb8\x28\xc3\x09\x11\x3e\xc1\x03\x00\x9f\x43\xcf\x06\xce\x66\x4c\x2d\x19\x87\x2f\x3b\x1d\xee\x8e\xf5\x66\x6f\xb6\x37\x75\x55\xda\x0a\x49\x87\x36\xcc\x02\x1e\xce\xe0\x1a\xc3\xad\xd4\x7b\x0e\x88\xf8\x74\x7f\x0d\xfd\x6c\x14\x8f\xe4\xe8\x94\x82\xbd\xe8\x7c\x2f\x06\x08\x8f\xf3\xc5\x8b\x40\x1d\x5d\xd6\xb0\x5b\xef\xf8\xfa\x84\x74\x9e\x36\x99\x0d\xd6\xd7\xc3\xf9\xaa\xbf\xbb\x9a\x03\x2f\x27\x7b\xb7\xbd\xdd\xdc\x6b\xd1\x2e\x05\xd5\x59\xb7\x4a\xb4\xd6\xc6\xd0\x72\xc2\xfb\xe0\x41\x23\x14\x2f\x59\x57\x4d\x74\x5b\x1c\x2c\xd1\xcc\x96\xc9\x2f\x39\xdf\x0a\xde\x17\xb4\xaf\x56\x72\x3f\xeb\x0b\xcf\x04\x47\x12\x7e\x27\xd2\xcf\x85\xc6\x5b\x30\x1b\x89\x3d\x53\xf1\xc7\x56\x60\x5f\x54\x92\x8c\x4a\xb4\x77\xa2\xfd\x07\xcb\xdb\x71\x31\x9e\x0d\x7b\xc5\x81\x08\x68\xb2\xcf\x7e\x62\xce\x81\x0f\xb5\xe0\x44\x40\x28\xab\x1a\x0e\x98\xea\x88\xb7\xb2\x34\xbb\x4e\x0b\xd1\xd8\x14\xda\x2b\xb6\xdb\x25\xb4\x0d\xad\xf1\xb4\x33\x1c\xf7\xbb\xc6\x7f\xa2\x16\x92\xf8\x5c\x16\xd1\x71\x3f\x3e\x28\x85\x74\x12\x05\x9e\xea\x49\xba\xaa\xd9\xe8\x53\x74\xdb\xe7\xf0\xf4\xd4\xea\x98\x92\x1a\xcb\xe7\x17\xe8\xf4\x60\x58\x25\xc4\xc9\x6b\x2f\xa9\x79\x1e\x86\xd6\x7e\x72\x15\x28\xc6\x80\x27\x3e\xa5\xb7\xe6\xd8\x93\x96\x54\xd4\x9f\xd9\xcb\xed\x31\xec\xd1\xee\x32\x29\xbb\x73\x7e\x0a\x52\x95\xd3\xb3\xb1\x79\x8f\x83\x32\xa1\x95\xac\x29\xbe\x2a\x58\x54\x35\x62\xae\xe8\x8d\x0e\x1c\xe7\x3b\xfe\xc5\xa6\x02\x69\xee\x13\x6f\xf0\x50\x84\x5b\x10\x52\xfc\x1f\xcf\xea\xdf\xd0\x40\x35\xaf\x05\xdd\x6a\x84\x14\x3f\x8e\x41\xb6\x70\xbf\x12\xcd\x10\x1d\x55\xb6\x92\x29\xb1\xad\xdf\x55\xe2\x1e\xd2\xbb\x4a\x55\x37\xb5\x18\x0f\xdd\x05\x26\xb6\x0f\xf5\x60\x8f\xf4\x1a\x68\xde\x65\x12\x5c\x63\xa5\x27\xb6\x05\x1c\xba\x1c\xdd\x56\xcb\x25\xa5\x10\x16\xa4\x17\xae\x68\x80\xa3\x15\xca\xcf\x96\x6c\xe6\xb3\x97\x37\xf7\x38\x43\xae\x71\xc0\x93\x0d\x8e\xf6\x96\xe0\x0c\x12\x96\x9d\x1a\x82\xc9\xbb\x46\x8b\x56\x09\x92\xff\x97\x1b\xe3\x23\x13\xbc\xec\x62\x42\x9d\xdf\x7e\x7c\x37\x91\x41\xf8\xf6\xc2\xd0\xec\x34\x15\x8d\x6e\x2b\xa1\x22\x1b\xe7\xab\x42\x66\x24\x53\x72\x2d\x0c\x9c\xe9\xba\xd0\xa7\xac\x52\x1e\x5f\xb3\x8c\xfa\x65\xe0\x28\xe3\x9b\xb5\xa5\x6c\x1a\x34\x50\xb6\x5e\x9f\x95\x85\x4f\x6c\xd9\xdf\xda\x78\x80\x82\x3f\x74\x15\x65\xf7\x41\xe4\x3b\xbb\xda\xab\xe6\x56\x75\x1c\x5f\xde\xcd\x6b\xf1\xa6\xa8\x6b\xac\x0c\x0e\x6a\xae\x9f\x6b\x01\xc3\x64\x03\x28\xd2\x20\xea\x74\x19\x8a\xc5\xdc\x8f\x0f\x97\x29\x03\x6b\x38\x74\x8f\x00\x13\x84\xc1\x22\xb8\x3d\x70\x25\xfe\x04\x87\xb0\x56\xa0\x8e\x77\x31\xd7\xbd\x3f\xf4\x5d\x00\xac\x0a\x05\x8d\x75\x1d\x74\x9f\x24\xba\x2e\xe8\x45\x0b\x0e\x9b\x94\xd2\xbc\xb5\xfd\x88\xcf\xca\x1b\xdd\xd4\x33\xf7\xf1\x1f\x2c\x83\xfc\xe2\xb4\xef\xc8\x2b\x9b\x19\x76\xd7\xd2\x40\x65\x87\x72\xc4\x87\xbd\x4d\x10\xa9\xe3\x84\x08\x60\x1f\x5d\x27\xfe\xbf\x4a\xdb\xfe\x3f\xe4\x63\x64\xe5\x16\x5d\x26\x6f\xfd\xee\xc5\xbb\xeb\xf7\xa4\x5a\x7a\x2f\x27\xc5\xa3\x81\x85\x33\x35\xce\x3d\xf4\x1e\x7d\x10\x1f\x7d\xed\x65\x3f\x1a\x0a\xf2\x47\x92\x3e\x7f\x71\x79\x28\x22\x70\x82\x72\x7a\x0a\xef\x30\xcf\xc1\x88\xc8\xd1\xd4\x6d\xbd\x2f\xfd\x55\x16\x3e\x64\x7f\xc2\x2f\xb1\x74\xae\xae\xf9\x5d\xbd\x1f\x3d\x91\xfb\x1d\xc9\xfb\x42\xd7\xd5\x19\x12\xf5\xfa\xa9\x97\x0a\x69\x5e\x8e\x2e\x4f\xcc\x93\xf1\x81\x24\xed\xeb\xa6\x5a\x83\xae\xd0\x4f\xa9\x0f\x24\x47\x2e\x01\xa3\x4c\x4a\x7c\xdc\xc8\x96\xbb\xfb\xbe\x1e\x67\x1a\x26\xad\x94\x3a\xfa\x91\x20\xa4\x8b\x56\x27\x82\xd2\xe7\x39\x7e\x37\x03\xb2\x8d\x07\x64\x4b\x8e\x85\x7e\x1b\xe6\xac\x57\x01\x4d\xfd\x85\x31\x6c\x45\x15\x5a\x9c\xe5\x40\xd8\xdc\x7b\x4c\xaf\x71\xfa\x1b\xb4\xa4\xa2\xd1\xaa\x8f\x02\x3b\x50\x64\x90\x78\xda\xb3\x25\xd7\x4b\x42\x39\x1d\xed\xa7\xb1\x74\x61\x81\x95\x05\x34\x77\x13\xcf\x62\x19\xb1\xf0\x61\x8b\x74\x95\x32\x97\x80\xa2\x52\x20\x5f\x29\x44\x91\x5d\xe9\x25\xc6\xe1\xca\xc1\xf0\x08\x37\x52\xd6\xa2\x68\xba\x68\xa9\x86\x80\x1d\x3c\x73\xac\x3a\xcb\x0f\x01\x62\x51\x0f\x95\x18\x94\x51\xce\xf2\xa7\xb0\xba\xc5\x3e\xb3\x5a\xe9\x42\x8a\xf5\x46\xef\x7a\x3a\x8a\x57\x95\xb5\x74\x8e\x3f\x3d\x92\x06\x1a\xe6\x0e\xaa\xbf\xb6\x82\xef\x9c\x4d\x8f\x21\x33\x6f\xfe\xa3\x4d\xfd\xd8\x45\x46\x8b\xcd\x3c\x7e\x06\x31\xc2\x84\x87\xe6\x01\x6c\xb4\xfc\x84\xaa\x0f\x70\x0c\x1d\x96\xa8\x72\x6c\xdc\xce\x5e\x0f\x5b\x5a\x57\xc2\x50\x4b\xf6\x37\x5a\x42\x53\x1a\xf5\x7e\x1c\x28\x1d\x4f\x47\xfb\xe9\xe8\x7f\x07\x00\xd1\x7b\x13\x22\x9c\x4b\x00\x00")
 
 func libsuiIndexTsBytes() ([]byte, error) {
 	return bindataRead(
@@ -895,7 +895,7 @@ func libsuiIndexTs() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "libsui/index.ts", size: 12261, mode: os.FileMode(420), modTime: time.Unix(1730855738, 0)}
+	info := bindataFileInfo{name: "libsui/index.ts", size: 19356, mode: os.FileMode(420), modTime: time.Unix(1792153821, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/sui/api"
+	"github.com/yaoapp/yao/sui/core"
+)
+
+// withMaintenance serve the maintenance page with 503 to everyone except the allowlisted IPs and tokens,
+// the admin and the assets of the maintenance page are not blocked
+func withMaintenance(c *gin.Context) {
+	m := core.GetMaintenance()
+	if !m.Enabled {
+		c.Next()
+		return
+	}
+
+	path := c.Request.URL.Path
+	if strings.HasPrefix(path, "/api/__yao/") || strings.HasPrefix(path, "/__yao_admin_root/") ||
+		(AdminRoot != "" && strings.HasPrefix(path, AdminRoot)) || strings.Contains(path, "/assets/") {
+		c.Next()
+		return
+	}
+
+	// The bypass token, the query sets the cookie for the following requests
+	token := c.GetHeader(core.MaintenanceTokenHeader)
+	if token == "" {
+		if token = c.Query(core.MaintenanceCookie); token != "" && m.Allowed("", token) {
+			c.SetCookie(core.MaintenanceCookie, token, 0, "/", "", false, true)
+		}
+	}
+	if token == "" {
+		token, _ = c.Cookie(core.MaintenanceCookie)
+	}
+	if m.Allowed(c.ClientIP(), token) {
+		c.Next()
+		return
+	}
+
+	message := m.Message
+	if message == "" {
+		message = "The service is under maintenance, please try again later"
+	}
+
+	c.Header("Retry-After", m.Retry())
+	c.Header("Cache-Control", "no-store")
+	if strings.HasPrefix(path, "/api/") || m.Page == "" {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"code": http.StatusServiceUnavailable, "message": message})
+		return
+	}
+
+	// Render the sui maintenance page
+	c.Request.URL.Path = strings.TrimSuffix(m.Page, "/") + ".sui"
+	if strings.HasSuffix(m.Page, "/") {
+		c.Request.URL.Path = m.Page + "index.sui"
+	}
+	r, _, err := api.NewRequestContext(c)
+	if err == nil {
+		var html string
+		html, _, err = r.Render()
+		if err == nil {
+			c.Data(http.StatusServiceUnavailable, core.FormatContentType(r.Request.Format()), []byte(html))
+			c.Abort()
+			return
+		}
+	}
+
+	log.Error("[SUI] the maintenance page %s error: %s", m.Page, err.Error())
+	c.Data(http.StatusServiceUnavailable, "text/plain; charset=utf-8", []byte(message))
+	c.Abort()
+}
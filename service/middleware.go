@@ -4,17 +4,21 @@ import (
 	"bytes"
 	"compress/gzip"
 	"net/http"
+	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yaoapp/gou/application"
 	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/yao/sui/api"
+	"github.com/yaoapp/yao/sui/core"
 )
 
 // Middlewares the middlewares
 var Middlewares = []gin.HandlerFunc{
 	gin.Logger(),
+	withMaintenance,
 	withStaticFileServer,
 }
 
@@ -45,6 +49,21 @@ func withStaticFileServer(c *gin.Context) {
 		return
 	}
 
+	// SUI URL normalization and redirects
+	if target, code, ok := core.RouteRedirect(c.Request.URL.Path); ok {
+		if c.Request.URL.RawQuery != "" {
+			target = target + "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(code, target)
+		c.Abort()
+		return
+	}
+
+	// SUI locale prefixes and localized slugs
+	if api.LocaleRoute(c) {
+		return
+	}
+
 	// Rewrite
 	for _, rewrite := range rewriteRules {
 		// log.Debug("Rewrite: %s => %s", c.Request.URL.Path, rewrite.Replacement)
@@ -73,6 +92,9 @@ func withStaticFileServer(c *gin.Context) {
 			return
 		}
 
+		contentType := core.FormatContentType(r.Request.Format())
+		c.Header("Vary", "Accept, HX-Request")
+
 		html, code, err := r.Render()
 		if err != nil {
 			if code == 301 || code == 302 {
@@ -103,18 +125,29 @@ func withStaticFileServer(c *gin.Context) {
 				return
 			}
 
-			c.Header("Content-Type", "text/html; charset=utf-8")
+			c.Header("Content-Type", contentType)
 			c.Header("Content-Encoding", "gzip")
-			c.Data(http.StatusOK, "text/html", buf.Bytes())
+			c.Data(http.StatusOK, contentType, buf.Bytes())
 			c.Done()
 		}
 
-		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Header("Content-Type", contentType)
 		c.String(200, html)
 		c.Next()
 		return
 	}
 
+	// The assets of the active SUI release
+	public := filepath.Join(string(os.PathSeparator), "public", c.Request.URL.Path)
+	if file := core.ReleaseFile(public); file != public {
+		file = filepath.Join(application.App.Root(), file)
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			c.File(file)
+			c.Abort()
+			return
+		}
+	}
+
 	// static file server
 	AppFileServer.ServeHTTP(c.Writer, c.Request)
 	c.Abort()
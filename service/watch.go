@@ -9,6 +9,7 @@ import (
 	"github.com/yaoapp/gou/server/http"
 	"github.com/yaoapp/yao/config"
 	"github.com/yaoapp/yao/engine"
+	"github.com/yaoapp/yao/sui/core"
 )
 
 // Watch the application code change for hot update
@@ -23,6 +24,13 @@ func Watch(srv *http.Server, interrupt chan uint8) (err error) {
 			return
 		}
 
+		// SUI locales, reload the locales only
+		if core.IsLocaleFile(name) {
+			core.ReloadLocales()
+			fmt.Println(color.GreenString("[Watch] Locale: %s changed", name))
+			return
+		}
+
 		// Reload
 		err = engine.Reload(config.Conf, engine.LoadOption{Action: "watch"})
 		if err != nil {
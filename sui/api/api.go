@@ -30,6 +30,26 @@ var dsl = []byte(`
 			"in": [":context", "$param.route", ":payload"],
 			"out": { "status": 200, "type": "application/json" }
 		},
+		{
+			"label": "Track",
+			"description": "Collect the analytics events of the s:track-* annotations",
+			"path": "/track",
+			"guard": "-",
+			"method": "POST",
+			"process": "sui.track.collect",
+			"in": [":context", ":payload"],
+			"out": { "status": 200, "type": "application/json" }
+		},
+		{
+			"label": "Storybook",
+			"description": "Browse the just-in-time components with the sample props, the development mode only",
+			"path": "/storybook/:id/:template",
+			"guard": "-",
+			"method": "GET",
+			"process": "sui.storybook.render",
+			"in": ["$param.id", "$param.template", "$query.component"],
+			"out": { "status": 200, "type": "text/html; charset=utf-8" }
+		},
 		// 
 		// 
 		// Remove the following code
@@ -243,7 +263,7 @@ var dsl = []byte(`
 		// 	"guard": "query-jwt",
 		// 	"method": "GET",
 		// 	"process": "sui.Preview.Render",
-		// 	"in": ["$param.id", "$param.template_id", "$param.route", "$header.Referer"],
+		// 	"in": ["$param.id", "$param.template_id", "$param.route", "$header.Referer", "$query.now"],
 		// 	"out": {"status": 200, "type": "text/html; charset=utf-8"}
 		// },
 
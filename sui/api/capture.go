@@ -0,0 +1,77 @@
+package api
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/process"
+	"github.com/yaoapp/kun/exception"
+	"github.com/yaoapp/yao/sui/core"
+)
+
+// CaptureOn turn on the capture mode, the requests of the routes are snapshotted into the fixtures
+// Args[0] the option (optional) {"routes": ["/shop"], "max": 20}
+func CaptureOn(process *process.Process) interface{} {
+	m := &core.CaptureMode{}
+	if process.NumOfArgs() > 0 {
+		raw, err := jsoniter.Marshal(process.Args[0])
+		if err != nil {
+			exception.New(err.Error(), 400).Throw()
+		}
+		if err := jsoniter.Unmarshal(raw, m); err != nil {
+			exception.New(err.Error(), 400).Throw()
+		}
+	}
+
+	m.Enabled = true
+	core.SetCaptureMode(m)
+	return core.GetCaptureMode()
+}
+
+// CaptureOff turn off the capture mode
+func CaptureOff(process *process.Process) interface{} {
+	m := core.GetCaptureMode()
+	m.Enabled = false
+	core.SetCaptureMode(&m)
+	return core.GetCaptureMode()
+}
+
+// CaptureStatus get the capture mode
+func CaptureStatus(process *process.Process) interface{} {
+	return core.GetCaptureMode()
+}
+
+// CaptureList list the fixtures, the latest first
+// Args[0] the route prefix (optional)
+func CaptureList(process *process.Process) interface{} {
+	prefix := ""
+	if process.NumOfArgs() > 0 {
+		prefix = process.ArgsString(0)
+	}
+
+	captures, err := core.ListCaptures(prefix)
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+	return captures
+}
+
+// CaptureReplay render the template of the fixture again with the captured data, return the html. the data of the
+// page is not executed again, see core.Capture.Replay
+// Args[0] the id of the fixture
+func CaptureReplay(process *process.Process) interface{} {
+	process.ValidateArgNums(1)
+	capture, err := core.LoadCapture(process.ArgsString(0))
+	if err != nil {
+		exception.New(err.Error(), 404).Throw()
+	}
+
+	script, err := core.LoadScript(capture.File, true)
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+
+	html, err := capture.Replay(script)
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+	return html
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/yaoapp/yao/sui/core"
+)
+
+// csrfSeed set the seed cookie of the CSRF token if the visitor has no seed, the token is rendered by <s:csrf/> and {{ $csrf }}
+func (r *Request) csrfSeed() {
+	if r.Request.CSRF != "" || r.context == nil {
+		return
+	}
+	r.Request.CSRF = core.NewCSRFSeed()
+	http.SetCookie(r.context.Writer, &http.Cookie{
+		Name:     core.CSRFCookie,
+		Value:    r.Request.CSRF,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   r.Request.URL.Scheme == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// verifyCSRF verify the CSRF token of the POST page actions, the method is empty for the form submissions
+func (r *Request) verifyCSRF(route string, method string, values map[string]interface{}) error {
+	if !core.GetCSRF(route).Check(method) {
+		return nil
+	}
+	return r.Request.VerifyCSRF(values)
+}
@@ -0,0 +1,16 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/yaoapp/yao/sui/core"
+)
+
+// varyDevice set the Vary header of the page uses the $device, and ask the browser for the client hints
+func (r *Request) varyDevice() {
+	if r.context == nil {
+		return
+	}
+	r.context.Writer.Header().Add("Vary", strings.Join(core.DeviceHeaders, ", "))
+	r.context.Header("Accept-CH", "Sec-CH-UA-Mobile")
+}
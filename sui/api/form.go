@@ -0,0 +1,56 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/yaoapp/gou/process"
+	"github.com/yaoapp/kun/exception"
+	"github.com/yaoapp/yao/sui/core"
+)
+
+// FormVerify verify the submitted values of the s:form (honeypot, minimum submit time and captcha)
+// Args[0] the submitted values, Args[1] the request headers (optional)
+func FormVerify(process *process.Process) interface{} {
+	process.ValidateArgNums(1)
+	values := process.ArgsMap(0)
+
+	r := &core.Request{Sid: process.Sid}
+	if process.NumOfArgs() > 1 {
+		headers := map[string][]string{}
+		for name, value := range process.ArgsMap(1) {
+			key := http.CanonicalHeaderKey(name)
+			switch v := value.(type) {
+			case string:
+				headers[key] = []string{v}
+			case []interface{}:
+				for _, item := range v {
+					if s, ok := item.(string); ok {
+						headers[key] = append(headers[key], s)
+					}
+				}
+			}
+		}
+		r.Headers = headers
+	}
+
+	name, err := core.VerifyForm(values, r)
+	if err != nil {
+		exception.New(err.Error(), 403).Throw()
+		return nil
+	}
+	return map[string]interface{}{"form": name}
+}
+
+// verifyForm verify the submitted values if they have the s:form token, the values without the token are not checked
+func (r *Request) verifyForm(values map[string]interface{}) error {
+	if _, has := values[core.FormTokenField]; !has {
+		return nil
+	}
+	_, err := core.VerifyForm(values, r.Request)
+	return err
+}
+
+// FormMetrics get the rejected submissions counts of the s:form
+func FormMetrics(process *process.Process) interface{} {
+	return core.FormMetrics()
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/yaoapp/yao/sui/core"
+)
+
+// varyGeo set the Vary header of the page with the s:geo nodes, the shared caches key the page by the geo
+// headers. the page is private if the country is resolved by the geo-IP provider (the headers can not tell)
+func (r *Request) varyGeo() {
+	if r.context == nil {
+		return
+	}
+
+	r.context.Writer.Header().Add("Vary", strings.Join(core.GeoHeaders, ", "))
+	if r.Request.Country != "" && !r.geoHeader() && r.context.Writer.Header().Get("Cache-Control") == "" {
+		r.context.Header("Cache-Control", "private")
+	}
+}
+
+// geoHeader check if the country is resolved by the geo headers
+func (r *Request) geoHeader() bool {
+	for _, name := range core.GeoHeaders {
+		if strings.EqualFold(strings.TrimSpace(r.context.GetHeader(name)), r.Request.Country) {
+			return true
+		}
+	}
+	return false
+}
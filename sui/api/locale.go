@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yaoapp/yao/sui/core"
+)
+
+// LocaleRoute resolve the locale prefix and the localized slugs of the page request, the request path is replaced
+// with the page route. the request without the prefix is redirected to the detected locale, true if it is redirected
+func LocaleRoute(c *gin.Context) bool {
+	route := c.Request.URL.Path
+	if strings.Contains(path.Base(route), ".") {
+		return false // the assets and the .sui files
+	}
+
+	routing := core.GetLocaleRouting(route)
+	if routing == nil {
+		return false
+	}
+
+	cookie := ""
+	if value, err := c.Cookie("locale"); err == nil {
+		cookie = value
+	}
+
+	locale, page, redirect := routing.Redirect(route, cookie, c.GetHeader("Accept-Language"), c.Request.Header)
+	if redirect != "" {
+		target := (&url.URL{Path: redirect}).EscapedPath()
+		if c.Request.URL.RawQuery != "" {
+			target = target + "?" + c.Request.URL.RawQuery
+		}
+		c.Header("Vary", "Accept-Language, Cookie")
+		c.Redirect(302, target)
+		c.Abort()
+		return true
+	}
+
+	c.Set("__sui_locale", &core.LocaleRoute{Locale: locale, Links: routing.Links(page, locale)})
+	c.Request.URL.Path = page
+	c.Request.URL.RawPath = ""
+	return false
+}
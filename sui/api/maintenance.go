@@ -0,0 +1,44 @@
+package api
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/process"
+	"github.com/yaoapp/kun/exception"
+	"github.com/yaoapp/yao/sui/core"
+)
+
+// MaintenanceOn turn on the maintenance mode
+// Args[0] the option (optional) {"page": "/maintenance", "retry_after": 600, "allow": ["10.0.0.0/8"], "tokens": ["..."]}
+func MaintenanceOn(process *process.Process) interface{} {
+	m := &core.Maintenance{}
+	if process.NumOfArgs() > 0 {
+		raw, err := jsoniter.Marshal(process.Args[0])
+		if err != nil {
+			exception.New(err.Error(), 400).Throw()
+		}
+		if err := jsoniter.Unmarshal(raw, m); err != nil {
+			exception.New(err.Error(), 400).Throw()
+		}
+	}
+
+	m.Enabled = true
+	if err := core.SetMaintenance(m); err != nil {
+		exception.New(err.Error(), 400).Throw()
+	}
+	return m
+}
+
+// MaintenanceOff turn off the maintenance mode
+func MaintenanceOff(process *process.Process) interface{} {
+	m := *core.GetMaintenance()
+	m.Enabled = false
+	if err := core.SetMaintenance(&m); err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+	return &m
+}
+
+// MaintenanceStatus get the maintenance mode
+func MaintenanceStatus(process *process.Process) interface{} {
+	return core.GetMaintenance()
+}
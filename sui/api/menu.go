@@ -0,0 +1,76 @@
+package api
+
+import (
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/process"
+	"github.com/yaoapp/gou/session"
+	"github.com/yaoapp/kun/exception"
+	"github.com/yaoapp/yao/sui/core"
+)
+
+// MenuTree get the resolved tree of the menu, for the admin layout and the menu editor
+// Args[0] the name of the menu, e.g. main
+// Args[1] the option (optional) {"route": "/", "locale": "zh-cn", "all": false}, the all keeps the items the visitor
+// can not see and the roles and the locales of the items (the editor)
+func MenuTree(process *process.Process) interface{} {
+	process.ValidateArgNums(1)
+	name := process.ArgsString(0)
+	option := process.ArgsMap(1, map[string]interface{}{})
+	menu := menuOption(option)
+
+	items, err := menu.Items(name)
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+
+	all, _ := option["all"].(bool)
+	locale, _ := option["locale"].(string)
+	roles := []string{}
+	if !all {
+		sess := map[string]interface{}{}
+		if process.Sid != "" {
+			if data, err := session.Global().ID(process.Sid).Dump(); err == nil && data != nil {
+				sess = data
+			}
+		}
+		roles = menu.VisitorRoles(sess)
+	}
+	return core.MenuTree(items, roles, locale, all)
+}
+
+// MenuSave replace the items of the menu with the edited tree
+// Args[0] the name of the menu, e.g. main
+// Args[1] the tree [{"label": "Home", "route": "/", "roles": ["admin"], "locales": {"zh-cn": "首页"}, "children": [...]}]
+// Args[2] the option (optional) {"route": "/"}
+func MenuSave(process *process.Process) interface{} {
+	process.ValidateArgNums(2)
+	name := process.ArgsString(0)
+	menu := menuOption(process.ArgsMap(2, map[string]interface{}{}))
+
+	raw, err := jsoniter.Marshal(process.Args[1])
+	if err != nil {
+		exception.New(err.Error(), 400).Throw()
+	}
+	tree := []*core.MenuItem{}
+	if err := jsoniter.Unmarshal(raw, &tree); err != nil {
+		exception.New("the tree of the menu is invalid: %s", 400, err.Error()).Throw()
+	}
+
+	if err := menu.Save(name, tree); err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+	return nil
+}
+
+// menuOption the menus of the route of the option, / by default
+func menuOption(option map[string]interface{}) *core.MenuOption {
+	route, _ := option["route"].(string)
+	if route == "" {
+		route = "/"
+	}
+	menu := core.GetMenus(route)
+	if menu == nil {
+		exception.New("the menus of %s are not set, please set the menus of the sui", 404, route).Throw()
+	}
+	return menu
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/yaoapp/gou/process"
+	"github.com/yaoapp/gou/session"
+	"github.com/yaoapp/kun/exception"
+	"github.com/yaoapp/yao/sui/core"
+)
+
+func init() {
+	process.RegisterGroup("preferences", map[string]process.Handler{
+		"get":   PreferencesGet,
+		"set":   PreferencesSet,
+		"reset": PreferencesReset,
+		"all":   PreferencesAll,
+		"save":  PreferencesSave,
+	})
+}
+
+// PreferencesGet get the value of the preference of the user of the session
+// Args: key
+func PreferencesGet(process *process.Process) interface{} {
+	process.ValidateArgNums(1)
+	value, err := core.GetPreference(preferencesUser(process, false), process.ArgsString(0))
+	if err != nil {
+		exception.New(err.Error(), 404).Throw()
+	}
+	return value
+}
+
+// PreferencesSet validate and set the value of the preference of the user of the session, return the current value
+// Args: key, value
+func PreferencesSet(process *process.Process) interface{} {
+	process.ValidateArgNums(2)
+	value, err := core.SetPreference(preferencesUser(process, true), process.ArgsString(0), process.Args[1])
+	if err != nil {
+		exception.New(err.Error(), 400).Throw()
+	}
+	return value
+}
+
+// PreferencesReset remove the value of the preference of the user of the session, the default is used
+// Args: key
+func PreferencesReset(process *process.Process) interface{} {
+	process.ValidateArgNums(1)
+	value, err := core.SetPreference(preferencesUser(process, true), process.ArgsString(0), nil)
+	if err != nil {
+		exception.New(err.Error(), 400).Throw()
+	}
+	return value
+}
+
+// PreferencesAll get the keys of the preferences and the preferences of the user of the session
+func PreferencesAll(process *process.Process) interface{} {
+	keys := core.PreferenceKeys()
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	res := []map[string]interface{}{}
+	for _, name := range names {
+		key := keys[name]
+		res = append(res, map[string]interface{}{
+			"key": name, "type": key.Type, "label": key.Label, "description": key.Description,
+			"default": key.Default, "options": key.Options, "min": key.Min, "max": key.Max, "pattern": key.Pattern,
+		})
+	}
+	return map[string]interface{}{
+		"keys":   res,
+		"values": core.PreferencesData(preferencesUser(process, false)),
+	}
+}
+
+// PreferencesSave set the preferences of the user of the session, the values are validated before any of them is set
+// Args: values {"ui.theme": "dark", "table.admin.user.columns": ["id", "name"]}
+func PreferencesSave(process *process.Process) interface{} {
+	process.ValidateArgNums(1)
+	values, err := core.SetPreferences(preferencesUser(process, true), process.ArgsMap(0))
+	if err != nil {
+		exception.New(err.Error(), 400).Throw()
+	}
+	return values
+}
+
+// preferencesUser get the user of the session of the process, throw 401 if the user is required but not signed in
+func preferencesUser(process *process.Process, required bool) string {
+	sess := map[string]interface{}{}
+	if process.Sid != "" {
+		if data, err := session.Global().ID(process.Sid).Dump(); err == nil && data != nil {
+			sess = data
+		}
+	}
+	user := core.PreferencesUser(sess)
+	if user == "" && required {
+		exception.New("the preferences require the signed in user", 401).Throw()
+	}
+	return user
+}
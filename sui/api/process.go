@@ -13,6 +13,7 @@ import (
 	"github.com/yaoapp/gou/process"
 	"github.com/yaoapp/gou/types"
 	"github.com/yaoapp/kun/exception"
+	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/yao/sui/core"
 )
 
@@ -28,10 +29,12 @@ func init() {
 		"template.asset":       TemplateAsset,
 		"template.assetupload": TemplateAssetUpload,
 		"template.render":      TemplateRender,
+		"template.refs":        TemplateReferences,
 		// "template.run":         TemplateRun,
 
-		"locale.get": LocaleGet,
-		"theme.get":  ThemeGet,
+		"locale.get":    LocaleGet,
+		"locale.reload": LocaleReload,
+		"theme.get":     ThemeGet,
 
 		"block.get":    BlockGet,
 		"block.find":   BlockFind,
@@ -51,6 +54,8 @@ func init() {
 		"page.remove":    PageRemove,
 		"page.exist":     PageExist,
 		"page.asset":     PageAsset,
+		"page.deps":      PageDependencies,
+		"page.audit":     PageAudit,
 
 		"editor.render":              EditorRender,
 		"editor.source":              EditorSource,
@@ -59,11 +64,38 @@ func init() {
 
 		"media.search": MediaSearch,
 
+		"track.collect": TrackCollect, // do not use this in script or flow, this is an internal method.
+
+		"form.verify":  FormVerify,
+		"form.metrics": FormMetrics,
+
+		"maintenance.on":     MaintenanceOn,
+		"maintenance.off":    MaintenanceOff,
+		"maintenance.status": MaintenanceStatus,
+
 		"preview.render": PreviewRender,
 
+		"capture.on":     CaptureOn,
+		"capture.off":    CaptureOff,
+		"capture.status": CaptureStatus,
+		"capture.list":   CaptureList,
+		"capture.replay": CaptureReplay,
+
+		"menu.tree": MenuTree,
+		"menu.save": MenuSave,
+
 		"build.all":  BuildAll,
 		"build.page": BuildPage,
 
+		"build.webcomponent": BuildWebComponent,
+
+		"storybook.render": StorybookRender, // the development mode only
+
+		"release.publish":  ReleasePublish,
+		"release.activate": ReleaseActivate,
+		"release.rollback": ReleaseRollback,
+		"release.list":     ReleaseList,
+
 		"trans.all":  TransAll,
 		"trans.page": TransPage,
 
@@ -145,6 +177,43 @@ func TemplateFind(process *process.Process) interface{} {
 	return tmpl
 }
 
+// TemplateReferences find the template expressions referencing the data path in all the pages of the template
+// e.g. sui.template.refs <sui> <template> "user.name"
+func TemplateReferences(process *process.Process) interface{} {
+	process.ValidateArgNums(3)
+
+	sui := get(process)
+	tmpl, err := sui.GetTemplate(process.ArgsString(1))
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+
+	path := process.ArgsString(2)
+	pages, err := tmpl.Pages()
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+
+	refs := []core.TemplateReference{}
+	for _, page := range pages {
+		if err := page.Load(); err != nil {
+			log.Error("[SUI] template.refs load %s error: %s", page.Get().Route, err.Error())
+			continue
+		}
+
+		pageRefs, err := core.FindReferences(page.Get().Codes.HTML.Code, path)
+		if err != nil {
+			log.Error("[SUI] template.refs parse %s error: %s", page.Get().Route, err.Error())
+			continue
+		}
+		for _, ref := range pageRefs {
+			ref.Route = page.Get().Route
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
 // TemplateAsset handle the find Template request
 func TemplateAsset(process *process.Process) interface{} {
 	process.ValidateArgNums(3)
@@ -316,6 +385,20 @@ func LocaleGet(process *process.Process) interface{} {
 	return locals
 }
 
+// LocaleReload reload the locales used by the renderer, without restarting the server
+// sui.locale.reload <name>... the locale names, reload all locales if not given
+func LocaleReload(process *process.Process) interface{} {
+	names := []string{}
+	for i := 0; i < process.NumOfArgs(); i++ {
+		name := process.ArgsString(i)
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	version := core.ReloadLocales(names...)
+	return map[string]interface{}{"version": version}
+}
+
 // ThemeGet handle the find Template request
 func ThemeGet(process *process.Process) interface{} {
 	process.ValidateArgNums(2)
@@ -747,6 +830,49 @@ func PageExist(process *process.Process) interface{} {
 	return tmpl.PageExist(route)
 }
 
+// PageDependencies handle the dependency closure of the page request
+func PageDependencies(process *process.Process) interface{} {
+	process.ValidateArgNums(3)
+	sui := get(process)
+	templateID := process.ArgsString(1)
+	route := route(process, 2)
+
+	tmpl, err := sui.GetTemplate(templateID)
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+
+	deps, err := tmpl.Dependencies(route)
+	if err != nil {
+		exception.New(err.Error(), 404).Throw()
+	}
+	return deps
+}
+
+// PageAudit check the accessibility of the page rendered by the mock request
+func PageAudit(process *process.Process) interface{} {
+	process.ValidateArgNums(3)
+	sui := get(process)
+	templateID := process.ArgsString(1)
+	route := route(process, 2)
+
+	tmpl, err := sui.GetTemplate(templateID)
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+
+	page, err := tmpl.Page(route)
+	if err != nil {
+		exception.New(err.Error(), 404).Throw()
+	}
+
+	findings, err := page.Audit()
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+	return findings
+}
+
 // PageAsset handle the find Template request
 func PageAsset(process *process.Process) interface{} {
 	process.ValidateArgNums(3)
@@ -878,7 +1004,7 @@ func EditorSourceAfterSaveTemp(process *process.Process) interface{} {
 	return EditorSource(process)
 }
 
-// PreviewRender handle the render page request
+// PreviewRender handle the render page request, Args[4] the time of the time-travel preview (optional)
 func PreviewRender(process *process.Process) interface{} {
 
 	process.ValidateArgNums(3)
@@ -886,6 +1012,10 @@ func PreviewRender(process *process.Process) interface{} {
 	templateID := process.ArgsString(1)
 	route := route(process, 2)
 	referer := process.ArgsString(3, "")
+	now, err := core.ParsePreviewTime(process.ArgsString(4, ""))
+	if err != nil {
+		exception.New(err.Error(), 400).Throw()
+	}
 
 	tmpl, err := sui.GetTemplate(templateID)
 	if err != nil {
@@ -898,7 +1028,7 @@ func PreviewRender(process *process.Process) interface{} {
 	}
 
 	// Request data
-	html, err := page.PreviewRender(referer)
+	html, err := page.PreviewRenderAt(referer, now)
 	if err != nil {
 		exception.New(err.Error(), 500).Throw()
 	}
@@ -966,12 +1096,31 @@ func BuildAll(process *process.Process) interface{} {
 		data = v
 	}
 
+	profile := false
+	if v, ok := option["profile"].(bool); ok {
+		profile = v
+	}
+
+	env := ""
+	if v, ok := option["env"].(string); ok {
+		env = v
+	}
+
+	webComponents := []string{}
+	if v, ok := option["webcomponents"].([]interface{}); ok {
+		for _, pattern := range v {
+			if pattern, ok := pattern.(string); ok {
+				webComponents = append(webComponents, pattern)
+			}
+		}
+	}
+
 	tmpl, err := sui.GetTemplate(templateID)
 	if err != nil {
 		exception.New(err.Error(), 500).Throw()
 	}
 
-	warnings, err := tmpl.Build(&core.BuildOption{SSR: ssr, AssetRoot: assetRoot, Data: data})
+	warnings, err := tmpl.Build(&core.BuildOption{SSR: ssr, AssetRoot: assetRoot, Data: data, Profile: profile, WebComponents: webComponents, Env: env})
 	if err != nil {
 		exception.New(err.Error(), 500).Throw()
 	}
@@ -1026,6 +1175,47 @@ func BuildPage(process *process.Process) interface{} {
 	return nil
 }
 
+// BuildWebComponent build the page as a standalone custom element
+// Args: sui, template, route, option {"tag": "<custom-element-name>"}, data
+func BuildWebComponent(process *process.Process) interface{} {
+	process.ValidateArgNums(3)
+	sui := get(process)
+	templateID := process.ArgsString(1)
+	route := route(process, 2)
+	option := process.ArgsMap(3, map[string]interface{}{})
+
+	tag := ""
+	if v, ok := option["tag"].(string); ok {
+		tag = v
+	}
+
+	tmpl, err := sui.GetTemplate(templateID)
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+
+	page, err := tmpl.Page(route)
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+
+	err = page.Load()
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+
+	data := process.ArgsMap(4, map[string]interface{}{})
+	file, warnings, err := page.BuildAsWebComponent(nil, &core.BuildOption{ComponentName: tag, Data: data})
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+
+	if tag == "" {
+		tag = core.WebComponentTag(page.Get().Route)
+	}
+	return map[string]interface{}{"file": file, "tag": tag, "warnings": warnings}
+}
+
 // TransAll handle the render page request
 func TransAll(process *process.Process) interface{} {
 
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"github.com/yaoapp/gou/process"
+	"github.com/yaoapp/kun/exception"
+	"github.com/yaoapp/yao/sui/core"
+)
+
+// ReleasePublish build the template, snapshot the public root into a new release and activate it
+// Args[0] the sui id, Args[1] the template id, Args[2] the option (optional) {"version": "v2", "ssr": true, "data": {}}
+func ReleasePublish(process *process.Process) interface{} {
+	process.ValidateArgNums(2)
+	sui := get(process)
+	templateID := process.ArgsString(1)
+	option := process.ArgsMap(2, map[string]interface{}{})
+
+	ssr := true
+	if v, ok := option["ssr"].(bool); ok {
+		ssr = v
+	}
+
+	data := map[string]interface{}{}
+	if v, ok := option["data"].(map[string]interface{}); ok {
+		data = v
+	}
+
+	version := ""
+	if v, ok := option["version"].(string); ok {
+		version = v
+	}
+
+	tmpl, err := sui.GetTemplate(templateID)
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+
+	warnings, err := tmpl.Build(&core.BuildOption{SSR: ssr, Data: data})
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+
+	id := process.ArgsString(0)
+	root := releaseRoot(sui)
+	release, err := core.SnapshotRelease(id, root, version)
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+
+	err = core.ActivateRelease(id, root, release.Version)
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+	release.Active = true
+
+	return map[string]interface{}{"release": release, "warnings": warnings}
+}
+
+// ReleaseActivate activate the release of the version
+// Args[0] the sui id, Args[1] the version
+func ReleaseActivate(process *process.Process) interface{} {
+	process.ValidateArgNums(2)
+	sui := get(process)
+	err := core.ActivateRelease(process.ArgsString(0), releaseRoot(sui), process.ArgsString(1))
+	if err != nil {
+		exception.New(err.Error(), 400).Throw()
+	}
+	return nil
+}
+
+// ReleaseRollback activate the release before the active one
+// Args[0] the sui id
+func ReleaseRollback(process *process.Process) interface{} {
+	process.ValidateArgNums(1)
+	sui := get(process)
+	release, err := core.RollbackRelease(process.ArgsString(0), releaseRoot(sui))
+	if err != nil {
+		exception.New(err.Error(), 400).Throw()
+	}
+	return release
+}
+
+// ReleaseList list the releases of the sui, the latest first
+// Args[0] the sui id
+func ReleaseList(process *process.Process) interface{} {
+	process.ValidateArgNums(1)
+	get(process)
+	releases, err := core.Releases(process.ArgsString(0))
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+	return releases
+}
+
+func releaseRoot(sui core.SUI) string {
+	public := sui.GetPublic()
+	if public == nil || public.Root == "" {
+		return "/"
+	}
+	return public.Root
+}
@@ -81,7 +81,13 @@ func Render(process *process.Process) interface{} {
 		comp = v
 	}
 
-	html, err := r.renderHTML(c, name, comp, c.HTML, core.Data(data))
+	// Get the props of the async component (optional)
+	props := map[string]interface{}{}
+	if v, ok := option["props"].(map[string]interface{}); ok {
+		props = v
+	}
+
+	html, err := r.renderHTML(c, name, comp, c.HTML, core.Data(data), props)
 	if err != nil {
 		return fmt.Sprintf("<span class='sui-render-error'> %s </span>", err.Error())
 	}
@@ -89,7 +95,7 @@ func Render(process *process.Process) interface{} {
 	return html
 }
 
-func (r *Request) renderHTML(c *core.Cache, name string, comp string, html string, data core.Data) (string, error) {
+func (r *Request) renderHTML(c *core.Cache, name string, comp string, html string, data core.Data, props map[string]interface{}) (string, error) {
 
 	doc, err := core.NewDocument([]byte(html))
 	if err != nil {
@@ -114,6 +120,9 @@ func (r *Request) renderHTML(c *core.Cache, name string, comp string, html strin
 		Request:      r.Request,
 	}
 
+	// Fill in the async component with the props of the placeholder
+	async := core.AsyncComponent(sel, props)
+
 	// Parse the template
 	parser := core.NewTemplateParser(data, &option)
 	err = parser.RenderSelection(sel)
@@ -121,6 +130,14 @@ func (r *Request) renderHTML(c *core.Cache, name string, comp string, html strin
 		return "", fmt.Errorf("Parser error: %w", err)
 	}
 
+	// The just-in-time component is replaced by the rendered one
+	if async && sel.Nodes[0].Parent == nil {
+		sel = doc.Find(fmt.Sprintf("[s\\:render='%s']", name)).First()
+		if sel.Length() == 0 {
+			return "", fmt.Errorf("Render %s not found", name)
+		}
+	}
+
 	sel.Find("[sui-hide]").Remove()
 	parser.Tidy(sel)
 
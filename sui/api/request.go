@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -25,7 +27,7 @@ type Request struct {
 	context *gin.Context
 }
 
-var reRouteVar = regexp.MustCompile(`\[([0-9a-z_]+)\]`)
+var reRouteVar = regexp.MustCompile(`\[(?:\.\.\.)?([0-9a-z_]+)(?::[^/]*)?\]`)
 
 // NewRequestContext is the constructor for Request.
 func NewRequestContext(c *gin.Context) (*Request, int, error) {
@@ -35,6 +37,12 @@ func NewRequestContext(c *gin.Context) (*Request, int, error) {
 		return nil, 404, err
 	}
 
+	// Validate the typed route parameters, e.g. [id:int]
+	values, err := core.TypedParams(strings.TrimSuffix(c.Request.URL.Path, ".sui"), params)
+	if err != nil {
+		return nil, 404, err
+	}
+
 	log.Trace("[Request] %s params:%v", file, params)
 	payload, body, err := payload(c)
 	if err != nil {
@@ -60,18 +68,29 @@ func NewRequestContext(c *gin.Context) (*Request, int, error) {
 		}
 	}
 
-	return &Request{
+	csrf, _ := c.Cookie(core.CSRFCookie)
+
+	var localized *core.LocaleRoute = nil
+	if v, has := c.Get("__sui_locale"); has {
+		localized, _ = v.(*core.LocaleRoute)
+	}
+
+	r := &Request{
 		File:    file,
 		context: c,
 		Request: &core.Request{
-			Sid:     sid,
-			Method:  c.Request.Method,
-			Query:   c.Request.URL.Query(),
-			Body:    body,
-			Payload: payload,
-			Referer: c.Request.Referer(),
-			Headers: url.Values(c.Request.Header),
-			Params:  params,
+			Sid:       sid,
+			Method:    c.Request.Method,
+			Query:     c.Request.URL.Query(),
+			Body:      body,
+			Payload:   payload,
+			Referer:   c.Request.Referer(),
+			Headers:   url.Values(c.Request.Header),
+			Remote:    c.Request.RemoteAddr,
+			Params:    params,
+			Values:    values,
+			Localized: localized,
+			CSRF:      csrf,
 			URL: core.ReqeustURL{
 				URL:    fmt.Sprintf("%s://%s%s", schema, c.Request.Host, path),
 				Host:   c.Request.Host,
@@ -80,14 +99,26 @@ func NewRequestContext(c *gin.Context) (*Request, int, error) {
 				Scheme: schema,
 			},
 		},
-	}, 200, nil
+	}
+
+	// The country of the visitor, the page cache is keyed by the request (see Hash)
+	r.Request.Country = r.Request.GeoCountry()
+	return r, 200, nil
 }
 
 // Render is the response for the page API.
 func (r *Request) Render() (string, int, error) {
 
+	// Server timing of the rendering phases
+	var timing *core.ServerTiming = nil
+	if r.Request.TimingMode() {
+		timing = core.NewServerTiming()
+		defer r.serverTiming(timing)
+	}
+
 	// Read content from cache
 	var c *core.Cache = nil
+	stop := timing.Start("cache", "Page Cache")
 	if !r.Request.DisableCache() {
 		c = core.GetCache(r.File)
 	}
@@ -106,6 +137,7 @@ func (r *Request) Render() (string, int, error) {
 		}
 		go log.Trace("[SUI] The page %s is cached file=%s", r.Request.URL.Path, r.File)
 	}
+	stop()
 
 	// Guard the page
 	code, err := r.Guard(c)
@@ -113,6 +145,26 @@ func (r *Request) Render() (string, int, error) {
 		return "", code, err
 	}
 
+	// Verify the CSRF token of the form submissions
+	if r.Request.Method == http.MethodPost {
+		if err := r.verifyCSRF(r.Request.URL.Path, "", r.Request.Payload); err != nil {
+			return "", 403, err
+		}
+	}
+	r.csrfSeed()
+
+	// Verify the submitted s:form values
+	if err := r.verifyForm(r.Request.Payload); err != nil {
+		return "", 403, err
+	}
+
+	// Embed mode, allow the page to be framed by the given origins
+	if c.Embed.Enable() && r.context != nil {
+		policy := r.context.Writer.Header().Get("Content-Security-Policy")
+		r.context.Header("Content-Security-Policy", core.MergeContentSecurityPolicy(policy, c.Embed.ContentSecurityPolicy()))
+		r.context.Writer.Header().Del("X-Frame-Options")
+	}
+
 	requestHash := r.Hash()
 	data := core.Data{}
 	dataCacheKey := fmt.Sprintf("data:%s", requestHash)
@@ -134,6 +186,8 @@ func (r *Request) Render() (string, int, error) {
 	}
 
 	if !dataHitCache {
+		stop = timing.Start("data", "Data Sources")
+
 		// Request the data
 		// Copy the script pointer to the request For page backend script execution
 		r.Request.Script = c.Script
@@ -157,10 +211,38 @@ func (r *Request) Render() (string, int, error) {
 		if c.DataCacheTime > 0 && c.CacheStore != "" {
 			go c.SetData(dataCacheKey, data, c.DataCacheTime)
 		}
+		stop()
+	}
+
+	// Mask the sensitive fields, the json, the cache key and the html use the masked data.
+	// The data is masked here only, the parser renders the data as it is
+	if len(c.Mask) > 0 || len(core.MaskRules) > 0 {
+		data = data.Mask(core.VisitorMaskRules(c.Mask, r.Request.Anonymous()))
+	}
+
+	// Return the page data as json, without the fields the visitor can not see
+	r.Request.Models = core.DataModels(c.Data, c.Global)
+	format := r.Request.Format()
+	if format == core.FormatJSON {
+		raw, err := jsoniter.MarshalToString(data.Visible(core.GetVisibility(r.Request.URL.Path), r.Request))
+		if err != nil {
+			return "", 500, fmt.Errorf("data error, %s", err.Error())
+		}
+		return raw, 200, nil
+	}
+
+	// The page varies by the country of the visitor
+	if strings.Contains(c.HTML, "s:geo=") {
+		r.varyGeo()
+	}
+
+	// The page varies by the device of the visitor
+	if strings.Contains(c.HTML, "$device") {
+		r.varyDevice()
 	}
 
 	// Read from cache directly
-	key := fmt.Sprintf("page:%s:%s", requestHash, data.Hash())
+	key := fmt.Sprintf("page:%s:%s:%s", format, requestHash, data.Hash())
 	if !r.Request.DisableCache() && c.CacheTime > 0 && c.CacheStore != "" {
 		html, exists := c.GetHTML(key)
 		if exists {
@@ -179,24 +261,73 @@ func (r *Request) Render() (string, int, error) {
 		Root:         c.Root,
 		Script:       c.Script,
 		Imports:      c.Imports,
+		Format:       format,
+		Embed:        c.Embed,
+		Timing:       timing,
+		CacheStore:   c.CacheStore,
+		Restricted:   core.GetRestrictedProfile(r.Request.URL.Path),
+		Precompile:   c.Precompile,
+		Minify:       c.Minify && !r.Request.DebugMode(),
+		Hydration:    c.Hydration,
+		Bindings:     c.Bindings,
 		Request:      r.Request,
 	}
 
+	// Snapshot the request into the fixture, see core.CaptureMode
+	if core.CaptureRoute(r.Request.URL.Path) {
+		capture := core.NewCapture(r.File, c.HTML, data, &option)
+		go func() {
+			if err := capture.Save(); err != nil {
+				log.Error("[SUI] Capture %s %s", r.Request.URL.Path, err.Error())
+			}
+		}()
+	}
+
 	// Parse the template
-	parser := core.NewTemplateParser(data, &option)
-	html, err := parser.Render(c.HTML)
+	parser := core.AcquireTemplateParser(data, &option)
+	defer core.ReleaseTemplateParser(parser)
+	html, err := parser.RenderContext(r.ctx(), c.HTML)
 	if err != nil {
 		return "", 500, fmt.Errorf("render error, please re-complie the page %s", err.Error())
 	}
 
-	// Save to The Cache
+	// The markup violations of the validation mode
+	for _, violation := range parser.Violations() {
+		log.Warn("[SUI] The page %s is invalid %s file=%s", r.Request.URL.Path, violation.Error(), r.File)
+	}
+
+	// Save to The Cache, the page expires when the scheduled contents are published or unpublished
 	if c.CacheTime > 0 && c.CacheStore != "" {
-		go c.SetHTML(key, html, c.CacheTime)
+		ttl := c.CacheTime
+		if next := parser.NextSchedule(); !next.IsZero() && time.Until(next) < ttl {
+			ttl = time.Until(next)
+		}
+		if ttl > 0 {
+			go c.SetHTML(key, html, ttl)
+		}
 	}
 
 	return html, 200, nil
 }
 
+// ctx the context of the rendering, the rendering is aborted when the client is gone
+func (r *Request) ctx() context.Context {
+	if r.context == nil || r.context.Request == nil {
+		return context.Background()
+	}
+	return r.context.Request.Context()
+}
+
+// serverTiming set the Server-Timing header of the response
+func (r *Request) serverTiming(timing *core.ServerTiming) {
+	if r.context == nil || r.context.Writer.Written() {
+		return
+	}
+	if header := timing.Header(); header != "" {
+		r.context.Header("Server-Timing", header)
+	}
+}
+
 // MakeCache is the cache for the page API.
 func (r *Request) MakeCache() (*core.Cache, int, error) {
 
@@ -218,6 +349,12 @@ func (r *Request) MakeCache() (*core.Cache, int, error) {
 	cacheTime := 0
 	dataCacheTime := 0
 	root := ""
+	var embed *core.PageEmbed = nil
+	var mask []core.MaskRule = nil
+	precompile := false
+	minify := false
+	var hydration *core.PageHydration
+	bindings := false
 
 	configSel := doc.Find("script[name=config]")
 	if configSel != nil && configSel.Length() > 0 {
@@ -245,6 +382,12 @@ func (r *Request) MakeCache() (*core.Cache, int, error) {
 		cacheTime = conf.Cache
 		dataCacheTime = conf.DataCache
 		root = conf.Root
+		embed = conf.Embed
+		mask = conf.Mask
+		precompile = conf.Precompile
+		minify = conf.Minify
+		hydration = conf.Hydration
+		bindings = conf.Bindings
 	}
 
 	dataText := ""
@@ -297,6 +440,12 @@ func (r *Request) MakeCache() (*core.Cache, int, error) {
 		DataCacheTime: time.Duration(dataCacheTime) * time.Second,
 		Script:        script,
 		Imports:       imports,
+		Embed:         embed,
+		Mask:          mask,
+		Precompile:    precompile,
+		Minify:        minify,
+		Hydration:     hydration,
+		Bindings:      bindings,
 	}
 
 	go core.SetCache(r.File, cache)
@@ -368,7 +517,7 @@ func parserPath(c *gin.Context) (string, map[string]string, error) {
 
 	fileParts := []string{string(os.PathSeparator), "public"}
 	fileParts = append(fileParts, parts...)
-	filename := filepath.Join(fileParts...) + ".sui"
+	filename := core.ReleaseFile(filepath.Join(fileParts...) + ".sui")
 
 	v, _ := c.Get("rewrite")
 	if v != true {
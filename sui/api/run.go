@@ -64,6 +64,29 @@ func Run(process *process.Process) interface{} {
 		return nil
 	}
 
+	// Verify the CSRF token of the backend call, the header or the field of the submitted values
+	var values map[string]interface{} = nil
+	for _, arg := range args {
+		if v, ok := arg.(map[string]interface{}); ok && v[core.CSRFField] != nil {
+			values = v
+			break
+		}
+	}
+	if err := r.verifyCSRF(route, method, values); err != nil {
+		exception.New(err.Error(), 403).Throw()
+		return nil
+	}
+
+	// Verify the submitted s:form values
+	for _, arg := range args {
+		if values, ok := arg.(map[string]interface{}); ok {
+			if err := r.verifyForm(values); err != nil {
+				exception.New(err.Error(), 403).Throw()
+				return nil
+			}
+		}
+	}
+
 	// Load the script
 	file := filepath.Join("/public", route)
 
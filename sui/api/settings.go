@@ -0,0 +1,90 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/yaoapp/gou/process"
+	"github.com/yaoapp/kun/exception"
+	"github.com/yaoapp/yao/sui/core"
+)
+
+func init() {
+	process.RegisterGroup("settings", map[string]process.Handler{
+		"get":   SettingsGet,
+		"set":   SettingsSet,
+		"reset": SettingsReset,
+		"all":   SettingsAll,
+		"save":  SettingsSave,
+	})
+}
+
+// SettingsGet get the value of the setting
+// Args: key, tenant (optional)
+func SettingsGet(process *process.Process) interface{} {
+	process.ValidateArgNums(1)
+	value, err := core.GetSetting(process.ArgsString(0), process.ArgsString(1, ""))
+	if err != nil {
+		exception.New(err.Error(), 404).Throw()
+	}
+	return value
+}
+
+// SettingsSet validate and set the value of the setting, return the current value
+// Args: key, value, tenant (optional)
+func SettingsSet(process *process.Process) interface{} {
+	process.ValidateArgNums(2)
+	value, err := core.SetSetting(process.ArgsString(0), process.ArgsString(2, ""), process.Args[1])
+	if err != nil {
+		exception.New(err.Error(), 400).Throw()
+	}
+	return value
+}
+
+// SettingsReset remove the value of the setting, the value of the app or the default is used
+// Args: key, tenant (optional)
+func SettingsReset(process *process.Process) interface{} {
+	process.ValidateArgNums(1)
+	value, err := core.SetSetting(process.ArgsString(0), process.ArgsString(1, ""), nil)
+	if err != nil {
+		exception.New(err.Error(), 400).Throw()
+	}
+	return value
+}
+
+// SettingsAll get the keys and the values of the settings, for the admin editing the settings
+// Args: tenant (optional)
+func SettingsAll(process *process.Process) interface{} {
+	tenant := process.ArgsString(0, "")
+	keys := core.SettingKeys()
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	res := []map[string]interface{}{}
+	for _, name := range names {
+		key := keys[name]
+		if tenant != "" && !key.Tenant {
+			continue
+		}
+		value, _ := core.GetSetting(name, tenant)
+		res = append(res, map[string]interface{}{
+			"key": name, "type": key.Type, "label": key.Label, "description": key.Description,
+			"default": key.Default, "options": key.Options, "min": key.Min, "max": key.Max,
+			"pattern": key.Pattern, "tenant": key.Tenant, "value": value,
+		})
+	}
+	return res
+}
+
+// SettingsSave set the values of the settings, the values are validated before any of them is set
+// Args: values {"site.title": "Yao"}, tenant (optional)
+func SettingsSave(process *process.Process) interface{} {
+	process.ValidateArgNums(1)
+	values, err := core.SetSettings(process.ArgsMap(0), process.ArgsString(1, ""))
+	if err != nil {
+		exception.New(err.Error(), 400).Throw()
+	}
+	return values
+}
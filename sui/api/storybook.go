@@ -0,0 +1,41 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/yaoapp/gou/process"
+	"github.com/yaoapp/kun/exception"
+	"github.com/yaoapp/yao/sui/core"
+)
+
+// StorybookRender render the just-in-time components of the template in isolation with the sample props of the
+// __stories.json, the development mode only (or YAO_SUI_STORYBOOK=true)
+// Args: sui, template, component (optional, e.g. /card), data (optional)
+func StorybookRender(process *process.Process) interface{} {
+	process.ValidateArgNums(2)
+	if !core.StorybookMode() {
+		exception.New("the storybook is only available in the development mode", 403).Throw()
+	}
+
+	sui := get(process)
+	tmpl, err := sui.GetTemplate(process.ArgsString(1))
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+
+	component := strings.Trim(process.ArgsString(2, ""), "/")
+	if component != "" {
+		component = "/" + component
+	}
+
+	storybook, err := tmpl.Storybook(process.ArgsMap(3, map[string]interface{}{}))
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+
+	html, err := storybook.Render(&core.Request{Method: "GET", Sid: process.Sid}, component)
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+	}
+	return html
+}
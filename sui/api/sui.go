@@ -36,6 +36,7 @@ func New(dsl *core.DSL) (core.SUI, error) {
 
 // Load load the sui
 func Load(cfg config.Config) error {
+	core.MaintenanceStore = cfg.Maintenance
 	exts := []string{"*.sui.yao", "*.sui.jsonc", "*.sui.json"}
 	err := application.App.Walk("suis", func(root, file string, isdir bool) error {
 		if isdir {
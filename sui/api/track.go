@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/process"
+	"github.com/yaoapp/kun/exception"
+	"github.com/yaoapp/yao/sui/core"
+)
+
+// TrackCollect collect the analytics events posted by the s:track-* runtime
+func TrackCollect(process *process.Process) interface{} {
+	process.ValidateArgNums(2)
+	ctx, ok := process.Args[0].(*gin.Context)
+	if !ok {
+		exception.New("The context is required", 400).Throw()
+		return nil
+	}
+
+	payload := process.ArgsMap(1)
+	raw, err := jsoniter.Marshal(payload["events"])
+	if err != nil {
+		exception.New("The events must be an array", 400).Throw()
+		return nil
+	}
+
+	events := []core.TrackEvent{}
+	err = jsoniter.Unmarshal(raw, &events)
+	if err != nil {
+		exception.New("The events must be an array", 400).Throw()
+		return nil
+	}
+
+	sid := ""
+	if v, has := ctx.Get("__sid"); has {
+		if s, ok := v.(string); ok {
+			sid = s
+		}
+	}
+
+	r := &core.Request{
+		Sid:     sid,
+		Method:  ctx.Request.Method,
+		Referer: ctx.Request.Referer(),
+		Headers: url.Values(ctx.Request.Header),
+		Remote:  ctx.Request.RemoteAddr,
+	}
+
+	token, _ := payload["token"].(string)
+	err = core.VerifyTrack(token, events, r)
+	if err != nil {
+		exception.New(err.Error(), 400).Throw()
+		return nil
+	}
+
+	err = core.CollectTrack(events, r)
+	if err != nil {
+		exception.New(err.Error(), 500).Throw()
+		return nil
+	}
+
+	return map[string]interface{}{"count": len(events)}
+}
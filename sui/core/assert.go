@@ -0,0 +1,60 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/yaoapp/kun/log"
+)
+
+// AssertionError the data contract of the s:assert directive is violated, returned by the rendering in the strict mode
+type AssertionError struct {
+	Route      string `json:"route,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Expression string `json:"expression,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// Error the error message
+func (e *AssertionError) Error() string {
+	return fmt.Sprintf("assertion failed: %s (%s %s)", e.Message, e.Path, e.Expression)
+}
+
+// assertNode the s:assert directive, check the data contract at the rendering boundary
+//
+//	<ul s:assert="{{ len(products) <= 100 }}" s:assert-message="too many products">...</ul>
+//
+// the violation is logged and reported as the render error, the rendering fails in the strict mode.
+// the hidden nodes (the false s:if branches) are not checked
+func (parser *TemplateParser) assertNode(sel *goquery.Selection) {
+	defer parser.option.Timing.Start("assert", "s:assert")()
+	node := sel.Nodes[0]
+	if teleportHidden(node) {
+		return
+	}
+
+	stmt := sel.AttrOr("s:assert", "")
+	message := sel.AttrOr("s:assert-message", "")
+	if message != "" {
+		message, _ = parser.data.ReplaceGuard(message, parser.guard)
+	}
+
+	res, _, err := parser.data.ExecGuard(stmt, parser.guard)
+	if err == nil && res == true {
+		return
+	}
+
+	if message == "" {
+		message = "the contract is violated"
+		if err != nil {
+			message = err.Error()
+		}
+	}
+
+	assertion := &AssertionError{Route: parser.option.Route, Path: NodePath(node), Expression: stmt, Message: message}
+	log.Warn("[SUI] %s %s", assertion.Route, assertion.Error())
+	parser.renderError(node, "s:assert", stmt, assertion)
+	if parser.option.Strict && parser.assertion == nil {
+		parser.assertion = assertion
+	}
+}
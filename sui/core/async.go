@@ -0,0 +1,132 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// asyncAttr the async component call, the component is rendered as the placeholder and filled in by the
+// render api after the page is loaded, e.g. the below-the-fold widgets of the slow data sources
+//
+//	<div is="/widgets/sales" s:async="visible" range="30d"></div>
+//
+// the triggers are load (default), idle and visible
+const asyncAttr = "s:async"
+
+// AsyncPlaceholder the placeholder of the async components, rendered in the component root until it is filled in
+var AsyncPlaceholder = `<div class="sui-async-loading" aria-busy="true"></div>`
+
+// asyncTriggers the triggers of the async components
+var asyncTriggers = map[string]bool{"": true, "load": true, "idle": true, "visible": true}
+
+// asyncCall mark the async component call at build, the render name (s:render) locates the component in the
+// built page for the render api. return the error if the trigger is not supported
+func asyncCall(sel *goquery.Selection, namespace string, index int) error {
+	trigger, has := sel.Attr(asyncAttr)
+	if !has {
+		return nil
+	}
+
+	trigger = strings.ToLower(strings.TrimSpace(trigger))
+	if !asyncTriggers[trigger] {
+		return fmt.Errorf("the s:async trigger %s is not supported, should be load, idle or visible", trigger)
+	}
+	if trigger == "" {
+		trigger = "load"
+	}
+	sel.SetAttr(asyncAttr, trigger)
+	sel.SetAttr("s:render", fmt.Sprintf("__async-%s-%d", namespace, index))
+	return nil
+}
+
+// copyAsync copy the async call of the component instance to the component root
+func copyAsync(from *goquery.Selection, to *goquery.Selection) {
+	trigger, has := from.Attr(asyncAttr)
+	if !has {
+		return
+	}
+	to.SetAttr(asyncAttr, trigger)
+	to.SetAttr("s:render", from.AttrOr("s:render", ""))
+}
+
+// asyncNode render the placeholder of the async component, the props are resolved with the current data and
+// kept on the component root, the client posts them to the render api to fill in the component
+func (parser *TemplateParser) asyncNode(sel *goquery.Selection) {
+	node := sel.Nodes[0]
+	for i, attr := range node.Attr {
+		if !strings.HasPrefix(attr.Key, "prop:") {
+			continue
+		}
+		val, values := parser.data.ReplaceGuard(attr.Val, parser.guard)
+		if HasJSON(values) {
+			sel.SetAttr(fmt.Sprintf("json-attr-%s", attr.Key), "true")
+		}
+		node.Attr[i].Val = val
+	}
+
+	// The spread props of the component call
+	spread := parser.spreadProps(sel)
+	for _, name := range sortedKeys(spread) {
+		if _, has := sel.Attr("prop:" + name); has {
+			continue
+		}
+		val, json, ok := spreadValue(spread[name])
+		if !ok {
+			continue
+		}
+		sel.SetAttr("prop:"+name, val)
+		if json {
+			sel.SetAttr(fmt.Sprintf("json-attr-prop:%s", name), "true")
+		}
+	}
+
+	sel.Empty()
+	nodes, err := html.ParseFragment(strings.NewReader(AsyncPlaceholder), fragmentContext(node))
+	if err != nil {
+		parser.renderError(node, asyncAttr, sel.AttrOr(asyncAttr, ""), err)
+		return
+	}
+	for _, child := range nodes {
+		node.AppendChild(child)
+	}
+}
+
+// isAsync check if the component is rendered as the async placeholder
+func (parser *TemplateParser) isAsync(sel *goquery.Selection) bool {
+	_, has := sel.Attr(asyncAttr)
+	return has
+}
+
+// AsyncComponent prepare the async component of the built page to be filled in by the render api, the props
+// are posted by the placeholder. return false if the selection is not the async component
+func AsyncComponent(sel *goquery.Selection, props map[string]interface{}) bool {
+	if len(sel.Nodes) == 0 || !sel.Is("[s\\:async]") {
+		return false
+	}
+	sel.RemoveAttr(asyncAttr)
+	sel.RemoveAttr("s:props") // the spread props are resolved by the placeholder
+	callProps(sel, props)
+	return true
+}
+
+// callProps set the props of the component call, the prop: attributes of the s:cn component, or the attributes of
+// the just-in-time component call. the objects and the lists are the json attributes
+func callProps(sel *goquery.Selection, props map[string]interface{}) {
+	prefix := "prop:"
+	if _, has := sel.Attr("is"); has {
+		prefix = ""
+	}
+	for _, name := range sortedKeys(props) {
+		val, json, ok := spreadValue(props[name])
+		if !ok || strings.HasPrefix(name, "s:") || name == "is" {
+			continue
+		}
+		sel.SetAttr(prefix+name, val)
+		if json {
+			sel.SetAttr(fmt.Sprintf("json-attr-%s%s", prefix, name), "true")
+		}
+	}
+}
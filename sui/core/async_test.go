@@ -0,0 +1,69 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsyncCall(t *testing.T) {
+	doc, err := NewDocumentString(`<html><body><div is="/sales" s:async range="30d"></div><div is="/map" s:async="later"></div></body></html>`)
+	assert.Nil(t, err)
+
+	sel := doc.Find("[is='/sales']")
+	assert.Nil(t, asyncCall(sel, "ns", 3))
+	assert.Equal(t, "load", sel.AttrOr("s:async", ""))
+	assert.Equal(t, "__async-ns-3", sel.AttrOr("s:render", ""))
+
+	assert.Contains(t, asyncCall(doc.Find("[is='/map']"), "ns", 4).Error(), "the s:async trigger later is not supported")
+	assert.Nil(t, asyncCall(doc.Find("body"), "ns", 0))
+}
+
+func TestParserAsync(t *testing.T) {
+	source := `<html><head></head><body>` +
+		`<div s:cn="Sales" s:async="visible" s:render="__async-ns-1" prop:range="{{ range }}" s:props="{{ extra }}">` +
+		`<span>{{ total }}</span></div>` +
+		`</body></html>`
+
+	data := Data{"range": "30d", "extra": map[string]interface{}{"limit": 10, "range": "7d"}}
+	parser := NewTemplateParser(data, &ParserOption{Route: "/dashboard", Request: &Request{}})
+	html, err := parser.Render(source)
+	assert.Nil(t, err)
+
+	// The placeholder keeps the resolved props and the render name
+	assert.Contains(t, html, `prop:range="30d"`)
+	assert.Contains(t, html, `prop:limit="10"`)
+	assert.Contains(t, html, `s:async="visible"`)
+	assert.Contains(t, html, `s:render="__async-ns-1"`)
+	assert.Contains(t, html, `sui-async-loading`)
+	assert.NotContains(t, html, `<span>`)
+}
+
+func TestAsyncComponent(t *testing.T) {
+	doc, err := NewDocumentString(`<html><body>` +
+		`<div s:cn="Sales" s:async="load" s:render="a" s:props="{{ extra }}"></div>` +
+		`<div is="/sales/[type]" s:jit="true" s:async="load" s:render="b"></div>` +
+		`<div s:render="c"></div>` +
+		`</body></html>`)
+	assert.Nil(t, err)
+
+	props := map[string]interface{}{"range": "30d", "filter": map[string]interface{}{"status": "paid"}, "s:cn": "Forged"}
+	sel := doc.Find("[s\\:render='a']")
+	assert.True(t, AsyncComponent(sel, props))
+	assert.Equal(t, "30d", sel.AttrOr("prop:range", ""))
+	assert.Equal(t, `{"status":"paid"}`, sel.AttrOr("prop:filter", ""))
+	assert.Equal(t, "true", sel.AttrOr("json-attr-prop:filter", ""))
+	assert.Equal(t, "Sales", sel.AttrOr("s:cn", ""))
+	_, has := sel.Attr("s:async")
+	assert.False(t, has)
+	_, has = sel.Attr("s:props")
+	assert.False(t, has)
+
+	// The just-in-time component call has the props as the attributes
+	sel = doc.Find("[s\\:render='b']")
+	assert.True(t, AsyncComponent(sel, props))
+	assert.Equal(t, "30d", sel.AttrOr("range", ""))
+	assert.Equal(t, "true", sel.AttrOr("json-attr-filter", ""))
+
+	assert.False(t, AsyncComponent(doc.Find("[s\\:render='c']"), props))
+}
@@ -0,0 +1,250 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/yaoapp/yao/config"
+	"golang.org/x/net/html"
+)
+
+// AuditEnabled audit the accessibility of all the rendered pages (YAO_SUI_AUDIT=true), e.g. in the CI
+var AuditEnabled = os.Getenv("YAO_SUI_AUDIT") == "true"
+
+// AuditMinContrast the min contrast ratio of the text and the background colors of the inline styles (WCAG AA)
+var AuditMinContrast = 4.5
+
+// AuditFinding the accessibility issue of the rendered page
+type AuditFinding struct {
+	Rule     string `json:"rule"`           // img-alt, label, duplicate-id, heading-order, contrast
+	Severity string `json:"severity"`       // error or warning
+	Path     string `json:"path,omitempty"` // the CSS-like selector of the node, see NodePath
+	Message  string `json:"message"`
+}
+
+// Error the message of the finding
+func (f AuditFinding) Error() string {
+	if f.Path == "" {
+		return fmt.Sprintf("%s: %s", f.Rule, f.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", f.Path, f.Rule, f.Message)
+}
+
+// Audit check the accessibility of the rendered nodes: the images without the alt text, the unlabeled form
+// controls, the duplicate ids, the skipped heading levels and the low contrast inline styles.
+// the hidden nodes (sui-hide) are not checked
+func Audit(sel *goquery.Selection) []AuditFinding {
+	findings := []AuditFinding{}
+	ids := map[string]*html.Node{}
+	labels := map[string]bool{}
+	heading := 0
+
+	sel.Find("label[for]").Each(func(i int, label *goquery.Selection) {
+		labels[label.AttrOr("for", "")] = true
+	})
+
+	var walk func(node *html.Node, labeled bool)
+	walk = func(node *html.Node, labeled bool) {
+		if node.Type != html.ElementNode {
+			for child := node.FirstChild; child != nil; child = child.NextSibling {
+				walk(child, labeled)
+			}
+			return
+		}
+
+		if hasAttr(node, "sui-hide") || node.Data == "script" || node.Data == "style" || node.Data == "template" {
+			return
+		}
+
+		finding := func(rule, severity, message string) {
+			findings = append(findings, AuditFinding{Rule: rule, Severity: severity, Path: NodePath(node), Message: message})
+		}
+
+		// The duplicate ids
+		if id := attrValue(node, "id"); id != "" {
+			if _, has := ids[id]; has {
+				finding("duplicate-id", "error", fmt.Sprintf("the id %q is used by %s", id, NodePath(ids[id])))
+			} else {
+				ids[id] = node
+			}
+		}
+
+		switch node.Data {
+		case "img", "area":
+			if !hasAttr(node, "alt") && !auditNamed(node) && attrValue(node, "role") != "presentation" {
+				finding("img-alt", "error", "the image has no alt text, use alt=\"\" for the decorative images")
+			}
+
+		case "input", "select", "textarea":
+			typ := strings.ToLower(attrValue(node, "type"))
+			switch {
+			case node.Data == "input" && (typ == "hidden" || typ == "submit" || typ == "reset" || typ == "button"):
+			case node.Data == "input" && typ == "image":
+				if !hasAttr(node, "alt") && !auditNamed(node) {
+					finding("img-alt", "error", "the image button has no alt text")
+				}
+			case !labeled && !auditNamed(node) && !labels[attrValue(node, "id")]:
+				finding("label", "error", fmt.Sprintf("the %s has no label, use <label for>, aria-label or aria-labelledby", node.Data))
+			}
+
+		case "label":
+			labeled = true
+
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			level := int(node.Data[1] - '0')
+			if heading > 0 && level > heading+1 {
+				finding("heading-order", "warning", fmt.Sprintf("the heading level skips from h%d to h%d", heading, level))
+			}
+			heading = level
+		}
+
+		// The contrast hints of the inline styles
+		if style := attrValue(node, "style"); style != "" {
+			if ratio, ok := auditContrast(style); ok && ratio < AuditMinContrast {
+				finding("contrast", "warning", fmt.Sprintf("the contrast ratio %.2f of the inline colors is less than %.1f", ratio, AuditMinContrast))
+			}
+		}
+
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child, labeled)
+		}
+	}
+
+	for _, node := range sel.Nodes {
+		walk(node, false)
+	}
+	return findings
+}
+
+// auditNamed check if the node has the accessible name of the aria attributes or the title
+func auditNamed(node *html.Node) bool {
+	for _, name := range []string{"aria-label", "aria-labelledby", "title"} {
+		if strings.TrimSpace(attrValue(node, name)) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// auditContrast get the contrast ratio of the color and the background color of the inline style,
+// false if the style does not set both of them or the colors are not the hex or rgb() colors
+func auditContrast(style string) (float64, bool) {
+	var fg, bg []float64
+	for _, decl := range strings.Split(style, ";") {
+		name, value, found := strings.Cut(decl, ":")
+		if !found {
+			continue
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "color":
+			fg = auditColor(value)
+		case "background-color", "background":
+			bg = auditColor(value)
+		}
+	}
+	if fg == nil || bg == nil {
+		return 0, false
+	}
+
+	l1, l2 := auditLuminance(fg), auditLuminance(bg)
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05), true
+}
+
+// auditColor parse the #rgb, #rrggbb, rgb() color and the black and white names
+func auditColor(value string) []float64 {
+	value = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "!important")))
+	switch value {
+	case "black":
+		return []float64{0, 0, 0}
+	case "white":
+		return []float64{255, 255, 255}
+	}
+
+	if strings.HasPrefix(value, "#") {
+		hex := value[1:]
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if len(hex) != 6 {
+			return nil
+		}
+		rgb := []float64{}
+		for i := 0; i < 6; i += 2 {
+			n, err := strconv.ParseUint(hex[i:i+2], 16, 8)
+			if err != nil {
+				return nil
+			}
+			rgb = append(rgb, float64(n))
+		}
+		return rgb
+	}
+
+	if strings.HasPrefix(value, "rgb(") && strings.HasSuffix(value, ")") {
+		parts := strings.Split(value[4:len(value)-1], ",")
+		if len(parts) != 3 {
+			return nil
+		}
+		rgb := []float64{}
+		for _, part := range parts {
+			n, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return nil
+			}
+			rgb = append(rgb, n)
+		}
+		return rgb
+	}
+	return nil
+}
+
+// auditLuminance the relative luminance of the color (WCAG 2)
+func auditLuminance(rgb []float64) float64 {
+	channel := func(v float64) float64 {
+		v = v / 255
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(rgb[0]) + 0.7152*channel(rgb[1]) + 0.0722*channel(rgb[2])
+}
+
+// auditEnabled check if the rendered page is audited, by the option, the YAO_SUI_AUDIT or the debug mode
+// of the development
+func (parser *TemplateParser) auditEnabled() bool {
+	if parser.option.Audit || AuditEnabled {
+		return true
+	}
+	return parser.debug() && config.Conf.Mode == "development"
+}
+
+// Audits get the accessibility findings of the rendered page, empty if the audit is not enabled
+func (parser *TemplateParser) Audits() []AuditFinding {
+	if parser.audits == nil {
+		return []AuditFinding{}
+	}
+	return parser.audits
+}
+
+// Audit render the page by the mock request and the fixtures as the preview, and check the accessibility of the
+// rendered page, e.g. the CI checks all the pages of the template
+func (page *Page) Audit() ([]AuditFinding, error) {
+	source, err := page.PreviewRender("")
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := NewDocumentString(source)
+	if err != nil {
+		return nil, err
+	}
+	return Audit(doc.Find("body")), nil
+}
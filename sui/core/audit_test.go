@@ -0,0 +1,53 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudit(t *testing.T) {
+	doc, err := NewDocumentString(`<html><body>` +
+		`<img src="/logo.png"/><img src="/line.png" alt=""/>` +
+		`<form><label for="email">Email</label><input id="email" name="email"/>` +
+		`<label>Name <input name="name"/></label>` +
+		`<input name="phone" placeholder="Phone"/><input type="hidden" name="token"/>` +
+		`<select name="city" aria-label="City"></select></form>` +
+		`<h1>Title</h1><h3 id="email">Skipped</h3><h2>Back</h2><h3>Ok</h3>` +
+		`<p style="color: #777; background-color: #888">Low</p>` +
+		`<p style="color:#000;background:#fff">High</p>` +
+		`<div sui-hide="true"><img src="/hidden.png"/></div>` +
+		`</body></html>`)
+	assert.Nil(t, err)
+
+	rules := map[string][]string{}
+	for _, finding := range Audit(doc.Find("body")) {
+		rules[finding.Rule] = append(rules[finding.Rule], finding.Path)
+	}
+	assert.Equal(t, []string{"html > body:nth-child(2) > img:nth-child(1)"}, rules["img-alt"])
+	assert.Equal(t, []string{"html > body:nth-child(2) > form:nth-child(3) > input:nth-child(4)"}, rules["label"])
+	assert.Equal(t, []string{"h3#email"}, rules["duplicate-id"])
+	assert.Equal(t, []string{"h3#email"}, rules["heading-order"])
+	assert.Equal(t, []string{"html > body:nth-child(2) > p:nth-child(8)"}, rules["contrast"])
+
+	ratio, ok := auditContrast("color: black; background: white")
+	assert.True(t, ok)
+	assert.InDelta(t, 21, ratio, 0.01)
+	_, ok = auditContrast("color: red")
+	assert.False(t, ok)
+}
+
+func TestParserAudit(t *testing.T) {
+	parser := NewTemplateParser(Data{"items": []interface{}{"a", "b"}}, &ParserOption{Audit: true, Request: &Request{}})
+	_, err := parser.Render(`<html><body><img s:for="items" s:for-item="item" id="{{ item }}" src="/{{ item }}.png"/></body></html>`)
+	assert.Nil(t, err)
+	audits := parser.Audits()
+	assert.Len(t, audits, 2)
+	assert.Equal(t, "img-alt", audits[0].Rule)
+
+	// Not enabled
+	parser = NewTemplateParser(Data{}, &ParserOption{Request: &Request{}})
+	_, err = parser.Render(`<html><body><img src="/logo.png"/></body></html>`)
+	assert.Nil(t, err)
+	assert.Empty(t, parser.Audits())
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"crypto/md5"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// AvatarColor the deterministic color of the value, the same value always gets the same color
+func AvatarColor(value string) string {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+	return hslColor(float64(h.Sum32()%360), 0.55, 0.45)
+}
+
+// Initials get the initials of the name, e.g. "Ada Lovelace" => "AL", "张三" => "张"
+func Initials(name string, max int) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '_' || r == '.' || r == '@'
+	})
+
+	initials := []string{}
+	for _, word := range words {
+		if len(initials) >= max {
+			break
+		}
+		clusters := Graphemes(word)
+		if len(clusters) == 0 {
+			continue
+		}
+		initials = append(initials, strings.ToUpper(clusters[0]))
+
+		// The CJK names use the first character only
+		if isCJK([]rune(clusters[0])[0]) {
+			break
+		}
+	}
+	return strings.Join(initials, "")
+}
+
+// Avatar the SVG avatar with the initials of the name, the shape is circle (default), square or rounded
+func Avatar(name string, size int, shape string) string {
+	text := Initials(name, 2)
+	if text == "" {
+		text = "?"
+	}
+
+	fill := AvatarColor(name)
+	background := ""
+	switch shape {
+	case "square":
+		background = fmt.Sprintf(`<rect width="100" height="100" fill="%s"/>`, fill)
+	case "rounded":
+		background = fmt.Sprintf(`<rect width="100" height="100" rx="16" fill="%s"/>`, fill)
+	default:
+		background = fmt.Sprintf(`<circle cx="50" cy="50" r="50" fill="%s"/>`, fill)
+	}
+
+	return fmt.Sprintf(
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 100 100">%s<text x="50" y="50" dy=".35em" font-family="sans-serif" font-size="40" text-anchor="middle" fill="#fff">%s</text></svg>`,
+		size, size, background, svgEscape(text),
+	)
+}
+
+// Identicon the symmetric 5x5 SVG identicon of the value (the md5 hash, the same as the common identicon services)
+func Identicon(value string, size int, background string) string {
+	sum := md5.Sum([]byte(strings.ToLower(strings.TrimSpace(value))))
+	fill := hslColor(float64(int(sum[12])<<4|int(sum[13])>>4)*360/4096, 0.5, 0.5)
+
+	var path strings.Builder
+	for i := 0; i < 15; i++ {
+		if sum[i/2]>>(uint(i%2)*4)&1 != 0 {
+			continue
+		}
+		x, y := i/5, i%5
+		fmt.Fprintf(&path, "M%d,%dh1v1h-1z", x+1, y+1)
+		if x < 2 {
+			fmt.Fprintf(&path, "M%d,%dh1v1h-1z", 5-x, y+1)
+		}
+	}
+	return svgImage(size, size, 7, 7, background, fmt.Sprintf(`<path d="%s" fill="%s"/>`, path.String(), fill))
+}
+
+// Placeholder the SVG placeholder image, the text is the size of the image if it's empty, e.g. 320×240
+func Placeholder(width, height int, text string, color string, background string) string {
+	if text == "" {
+		text = fmt.Sprintf("%d×%d", width, height)
+	}
+
+	fontSize := width / 8
+	if h := height / 4; h < fontSize {
+		fontSize = h
+	}
+	if fontSize < 8 {
+		fontSize = 8
+	}
+
+	return svgImage(width, height, width, height, background, fmt.Sprintf(
+		`<text x="50%%" y="50%%" dy=".35em" font-family="sans-serif" font-size="%d" text-anchor="middle" fill="%s">%s</text>`,
+		fontSize, svgEscape(color), svgEscape(text),
+	))
+}
+
+// hslColor convert the HSL color to the hex color
+func hslColor(h, s, l float64) string {
+	c := (1 - math.Abs(2*l-1)) * s
+	hp := h / 60
+	x := c * (1 - math.Abs(math.Mod(hp, 2)-1))
+	var r, g, b float64
+	switch {
+	case hp < 1:
+		r, g, b = c, x, 0
+	case hp < 2:
+		r, g, b = x, c, 0
+	case hp < 3:
+		r, g, b = 0, c, x
+	case hp < 4:
+		r, g, b = 0, x, c
+	case hp < 5:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	m := l - c/2
+	return fmt.Sprintf("#%02x%02x%02x", int((r+m)*255+0.5), int((g+m)*255+0.5), int((b+m)*255+0.5))
+}
@@ -0,0 +1,163 @@
+package core
+
+import (
+	"fmt"
+)
+
+// code128Patterns the bar and space widths of the Code 128 symbols, 103-105 are the start codes, 106 is the stop code
+var code128Patterns = []string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128CodeC  = 99
+	code128CodeB  = 100
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// Barcode the linear barcode, the modules are the bars (true) and the spaces (false) of the unit width
+type Barcode struct {
+	Text    string
+	modules []bool
+}
+
+// EncodeCode128 encode the text as the Code 128 barcode, the printable ASCII characters are supported,
+// the runs of the digits are encoded with the code set C
+func EncodeCode128(text string) (*Barcode, error) {
+	if text == "" {
+		return nil, fmt.Errorf("barcode the value is required")
+	}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] < 32 || text[i] > 126 {
+			return nil, fmt.Errorf("barcode the character %q is not supported by code128", text[i])
+		}
+	}
+
+	codes := []int{}
+	setC := code128Digits(text, 0) >= 4 && (code128Digits(text, 0) == len(text) || code128Digits(text, 0) >= 6)
+	if setC {
+		codes = append(codes, code128StartC)
+	} else {
+		codes = append(codes, code128StartB)
+	}
+
+	for i := 0; i < len(text); {
+		digits := code128Digits(text, i)
+		if setC {
+			if digits >= 2 {
+				codes = append(codes, int(text[i]-'0')*10+int(text[i+1]-'0'))
+				i += 2
+				continue
+			}
+			codes = append(codes, code128CodeB)
+			setC = false
+			continue
+		}
+
+		// Switch to the code set C for the long runs of the digits (even length, the odd digit is kept in B)
+		if digits >= 6 || (digits >= 4 && i+digits == len(text)) {
+			if digits%2 == 1 {
+				codes = append(codes, int(text[i])-32)
+				i++
+			}
+			codes = append(codes, code128CodeC)
+			setC = true
+			continue
+		}
+		codes = append(codes, int(text[i])-32)
+		i++
+	}
+
+	// The checksum
+	sum := codes[0]
+	for i, code := range codes[1:] {
+		sum += (i + 1) * code
+	}
+	codes = append(codes, sum%103, code128Stop)
+
+	barcode := &Barcode{Text: text, modules: []bool{}}
+	for _, code := range codes {
+		bar := true
+		for _, width := range code128Patterns[code] {
+			for n := 0; n < int(width-'0'); n++ {
+				barcode.modules = append(barcode.modules, bar)
+			}
+			bar = !bar
+		}
+	}
+	return barcode, nil
+}
+
+// code128Digits the length of the digits run from the index
+func code128Digits(text string, start int) int {
+	n := 0
+	for i := start; i < len(text) && text[i] >= '0' && text[i] <= '9'; i++ {
+		n++
+	}
+	return n
+}
+
+// Width the width of the barcode in modules, the quiet zones are not included
+func (barcode *Barcode) Width() int {
+	return len(barcode.modules)
+}
+
+// SVG render the barcode as the inline SVG, the quiet zone is 10 modules, the text is shown below the bars if label is true
+func (barcode *Barcode) SVG(module int, height int, color string, background string, label bool) string {
+	border := 10
+	width := len(barcode.modules) + border*2
+	fontSize := 0
+	if label {
+		fontSize = height / 5
+		if fontSize < 10 {
+			fontSize = 10
+		}
+	}
+
+	path := acquireBuffer()
+	defer releaseBuffer(path)
+	for x := 0; x < len(barcode.modules); {
+		if !barcode.modules[x] {
+			x++
+			continue
+		}
+		start := x
+		for x < len(barcode.modules) && barcode.modules[x] {
+			x++
+		}
+		fmt.Fprintf(path, "M%d,0h%dv%dh-%dz", start+border, x-start, height, x-start)
+	}
+
+	content := fmt.Sprintf(`<path d="%s" fill="%s"/>`, path.String(), svgEscape(color))
+	if label {
+		content += fmt.Sprintf(
+			`<text x="%d" y="%d" font-family="monospace" font-size="%d" text-anchor="middle" fill="%s">%s</text>`,
+			width/2, height+fontSize, fontSize, svgEscape(color), svgEscape(barcode.Text),
+		)
+	}
+
+	// The viewBox is in modules horizontally and in pixels vertically
+	viewHeight := height + fontSize + fontSize/2
+	return svgImage(width*module, viewHeight, width, viewHeight, background, content, `preserveAspectRatio="none"`)
+}
+
+// Matrix get the modules with the quiet zone, the barcode is a single row
+func (barcode *Barcode) Matrix() [][]bool {
+	border := 10
+	row := make([]bool, len(barcode.modules)+border*2)
+	copy(row[border:], barcode.modules)
+	return [][]bool{row}
+}
@@ -0,0 +1,274 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// BenchCase the page of the render benchmark, the suite is the same between the runs so the results are comparable
+type BenchCase struct {
+	Name       string
+	Route      string
+	Source     string
+	Data       Data
+	Locale     string
+	Locales    map[string]*Locale       // the locale of the page, the name and the locale
+	Components map[string]*JitComponent // the just-in-time components, the route and the component
+}
+
+// BenchResult the result of the render benchmark
+type BenchResult struct {
+	Name        string `json:"name"`
+	Iterations  int    `json:"iterations"`
+	NsPerOp     int64  `json:"ns_per_op"`
+	AllocsPerOp int64  `json:"allocs_per_op"`
+	BytesPerOp  int64  `json:"bytes_per_op"`
+	Size        int    `json:"size"` // the size of the rendered page
+}
+
+// BenchOption the option of the render benchmark
+type BenchOption struct {
+	Cases      []string // the names of the cases, all cases if empty
+	CPUProfile string   // the file of the pprof cpu profile
+	MemProfile string   // the file of the pprof heap profile
+}
+
+// BenchCases the built-in render benchmark suite, the small page, the loop-heavy page,
+// the component-heavy page and the i18n-heavy page
+func BenchCases() []*BenchCase {
+	return []*BenchCase{benchSmall(), benchLoop(), benchComponent(), benchI18n()}
+}
+
+// Bench run the render benchmark suite, the profiles are written if the files are set
+func Bench(option *BenchOption) ([]*BenchResult, error) {
+	if option == nil {
+		option = &BenchOption{}
+	}
+
+	cases := []*BenchCase{}
+	for _, c := range BenchCases() {
+		if len(option.Cases) == 0 || benchSelected(option.Cases, c.Name) {
+			cases = append(cases, c)
+		}
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("bench: no case matches %s, the cases are small, loop, component, i18n", strings.Join(option.Cases, ","))
+	}
+
+	if option.CPUProfile != "" {
+		file, err := os.Create(option.CPUProfile)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		if err := pprof.StartCPUProfile(file); err != nil {
+			return nil, err
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	results := []*BenchResult{}
+	for _, c := range cases {
+		result, err := c.Bench()
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if option.MemProfile != "" {
+		file, err := os.Create(option.MemProfile)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(file); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// Bench run the benchmark of the case
+func (c *BenchCase) Bench() (*BenchResult, error) {
+	restore := c.setup()
+	defer restore()
+
+	html, err := c.render()
+	if err != nil {
+		return nil, fmt.Errorf("bench %s: %s", c.Name, err.Error())
+	}
+
+	var failed error
+	res := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := c.render(); err != nil {
+				failed = err
+				return
+			}
+		}
+	})
+	if failed != nil {
+		return nil, fmt.Errorf("bench %s: %s", c.Name, failed.Error())
+	}
+
+	return &BenchResult{
+		Name:        c.Name,
+		Iterations:  res.N,
+		NsPerOp:     res.NsPerOp(),
+		AllocsPerOp: res.AllocsPerOp(),
+		BytesPerOp:  res.AllocedBytesPerOp(),
+		Size:        len(html),
+	}, nil
+}
+
+// Render render the page of the case once
+func (c *BenchCase) Render() (string, error) {
+	restore := c.setup()
+	defer restore()
+	return c.render()
+}
+
+func (c *BenchCase) render() (string, error) {
+	option := &ParserOption{Route: c.Route, Request: &Request{}}
+	if c.Locale != "" {
+		option.Locale = c.Locale
+	}
+	parser := AcquireTemplateParser(c.Data, option)
+	defer ReleaseTemplateParser(parser)
+	return parser.Render(c.Source)
+}
+
+// setup install the components and the locales of the case, the returned function restores them
+func (c *BenchCase) setup() func() {
+	components := map[string]*JitComponent{}
+	for route, comp := range c.Components {
+		components[route] = Components[route]
+		Components[route] = comp
+	}
+
+	locales := map[string]map[string]*Locale{}
+	for name, locale := range c.Locales {
+		locales[name] = Locales[name]
+		locale.version = atomic.LoadUint64(&LocaleVersion)
+		Locales[name] = map[string]*Locale{c.Route: locale}
+	}
+
+	return func() {
+		for route, comp := range components {
+			if comp == nil {
+				delete(Components, route)
+				continue
+			}
+			Components[route] = comp
+		}
+		for name, locale := range locales {
+			if locale == nil {
+				delete(Locales, name)
+				continue
+			}
+			Locales[name] = locale
+		}
+	}
+}
+
+func benchSelected(names []string, name string) bool {
+	for _, n := range names {
+		if strings.TrimSpace(n) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func benchSmall() *BenchCase {
+	return &BenchCase{
+		Name:  "small",
+		Route: "/__bench/small",
+		Source: `<html><head><title>{{ title }}</title></head><body><header class="header"><a href="/">{{ site }}</a></header>` +
+			`<main class="main"><h1>{{ title }}</h1><p s:if="user.name != ''">Hello, {{ user.name }}</p><p s:else>Sign in</p>` +
+			`<ul><li s:for="{{ links }}" s:for-item="link"><a href="{{ link.url }}">{{ link.label }}</a></li></ul></main>` +
+			`<footer class="footer">© {{ site }}</footer></body></html>`,
+		Data: Data{
+			"title": "Home",
+			"site":  "Yao",
+			"user":  map[string]interface{}{"name": "Ada"},
+			"links": []interface{}{
+				map[string]interface{}{"url": "/docs", "label": "Docs"},
+				map[string]interface{}{"url": "/blog", "label": "Blog"},
+				map[string]interface{}{"url": "/about", "label": "About"},
+			},
+		},
+	}
+}
+
+func benchLoop() *BenchCase {
+	rows := []interface{}{}
+	for i := 0; i < 500; i++ {
+		rows = append(rows, map[string]interface{}{
+			"id": i, "name": fmt.Sprintf("Item %d", i), "price": float64(i) * 1.5, "active": i%3 != 0,
+			"tags": []interface{}{"a", "b", "c"},
+		})
+	}
+	return &BenchCase{
+		Name:  "loop",
+		Route: "/__bench/loop",
+		Source: `<html><head></head><body><table><tbody>` +
+			`<tr s:for="{{ rows }}" s:for-item="row" class="row" data-id="{{ row.id }}">` +
+			`<td>{{ row.id }}</td><td>{{ row.name }}</td><td>{{ row.price }}</td>` +
+			`<td><span s:if="row.active" class="on">Active</span><span s:else class="off">Inactive</span></td>` +
+			`<td><i s:for="{{ row.tags }}" s:for-item="tag">{{ tag }}</i></td></tr>` +
+			`</tbody></table></body></html>`,
+		Data: Data{"rows": rows},
+	}
+}
+
+func benchComponent() *BenchCase {
+	cards := []interface{}{}
+	for i := 0; i < 100; i++ {
+		cards = append(cards, map[string]interface{}{"title": fmt.Sprintf("Card %d", i), "body": "The card body"})
+	}
+	return &BenchCase{
+		Name:  "component",
+		Route: "/__bench/component",
+		Source: `<html><head></head><body><div class="grid">` +
+			`<div s:for="{{ cards }}" s:for-item="card" class="cell"><div is="/__bench/card" s:jit title="{{ card.title }}" body="{{ card.body }}"></div></div>` +
+			`</div></body></html>`,
+		Data: Data{"cards": cards},
+		Components: map[string]*JitComponent{
+			"/__bench/card": {
+				route:       "/__bench/card",
+				html:        `<div class="card"><h3>{% title %}</h3><p>{% body %}</p><button class="btn" s:on-click="open">Open</button></div>`,
+				buildOption: &BuildOption{},
+			},
+		},
+	}
+}
+
+func benchI18n() *BenchCase {
+	var source strings.Builder
+	keys := map[string]string{}
+	source.WriteString(`<html><head></head><body><main>`)
+	for i := 0; i < 300; i++ {
+		key := fmt.Sprintf("trans_%d", i)
+		keys[key] = fmt.Sprintf("Bonjour %d", i)
+		fmt.Fprintf(&source, `<p s:trans-node="%s">Hello %d</p>`, key, i)
+		fmt.Fprintf(&source, `<input s:trans-attr-placeholder="%s_attr" placeholder="{{ '::Search' }}" />`, key)
+	}
+	source.WriteString(`</main></body></html>`)
+	return &BenchCase{
+		Name:    "i18n",
+		Route:   "/__bench/i18n",
+		Source:  source.String(),
+		Data:    Data{},
+		Locale:  "__bench",
+		Locales: map[string]*Locale{"__bench": {Name: "__bench", Keys: keys, Messages: map[string]string{"Search": "Rechercher"}}},
+	}
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBenchCases(t *testing.T) {
+	expected := map[string]string{
+		"small":     "<p>Hello, Ada</p>",
+		"loop":      "<td>Item 499</td>",
+		"component": "<h3>Card 99</h3>",
+		"i18n":      ">Bonjour 299</p>",
+	}
+	for _, c := range BenchCases() {
+		html, err := c.Render()
+		assert.Nil(t, err, c.Name)
+		assert.Contains(t, html, expected[c.Name], c.Name)
+	}
+
+	// The components and the locales are restored
+	assert.NotContains(t, Components, "/__bench/card")
+	assert.NotContains(t, Locales, "__bench")
+
+	_, err := Bench(&BenchOption{Cases: []string{"unknown"}})
+	assert.NotNil(t, err)
+}
+
+func BenchmarkSUI(b *testing.B) {
+	for _, c := range BenchCases() {
+		c := c
+		b.Run(c.Name, func(b *testing.B) {
+			restore := c.setup()
+			defer restore()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := c.render(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/yaoapp/kun/log"
+	"golang.org/x/net/html"
+)
+
+// BindingsID the id of the script element of the binding map, <script type="application/json" id="__sui_bindings">
+const BindingsID = "__sui_bindings"
+
+// PageBindings the binding map of the rendered page, embedded in the page if the bindings of the page config is true
+//
+//	{
+//	  "route": "/shop",
+//	  "stableKeys": true,
+//	  "bindings": {
+//	    "3": {"key": "3", "type": "text", "expr": "{{ count }} items", "path": "html:0/body:1/span:0/#text:0", "data": ["count"]},
+//	    "4": {"key": "4", "type": "attr", "name": "class", "expr": "{{ cls }}", "path": "html:0/body:1/span:0", "data": ["cls"]}
+//	  }
+//	}
+//
+// the frontend runtimes (e.g. the React or the Vue adapters) find the text bindings by the s:key-text attribute of the
+// parent, the attribute bindings by the s:bind:<name> attribute or the path, and read the data of the bindings from
+// __sui_data to hydrate the rendered markup. the keys are stable across the renderings if the StableKeys is enabled
+type PageBindings struct {
+	Route      string             `json:"route"`
+	StableKeys bool               `json:"stableKeys"` // the keys are stable across the renderings
+	Bindings   map[string]Binding `json:"bindings"`   // the bindings by the keys
+}
+
+// Binding the text or the attribute binding of the rendered page
+type Binding struct {
+	Key  string   `json:"key"`
+	Type string   `json:"type"`           // text or attr
+	Name string   `json:"name,omitempty"` // the attribute of the attr binding
+	Expr string   `json:"expr"`           // the bindings of the source, e.g. {{ count }} items
+	Path string   `json:"path"`           // the node path, e.g. html:0/body:1/p:0/#text:0
+	Data []string `json:"data,omitempty"` // the data paths read by the binding, e.g. user.name
+}
+
+// Bindings get the binding map of the rendering, nil if the bindings option is disabled
+func (parser *TemplateParser) Bindings() *PageBindings {
+	if parser.bindings == nil {
+		return nil
+	}
+
+	bindings := make(map[string]Binding, len(parser.bindings))
+	for key, binding := range parser.bindings {
+		bindings[key] = binding
+	}
+	return &PageBindings{Route: parser.option.Route, StableKeys: parser.option.StableKeys, Bindings: bindings}
+}
+
+// bind record the binding of the node if the bindings option is enabled
+func (parser *TemplateParser) bind(node *html.Node, key string, typ string, name string, expr string) {
+	if parser.bindings == nil {
+		return
+	}
+
+	paths := parser.stmtPaths(expr)
+	sort.Strings(paths)
+	data := []string{}
+	for i, path := range paths {
+		if i == 0 || path != paths[i-1] {
+			data = append(data, path)
+		}
+	}
+	parser.bindings[key] = Binding{Key: key, Type: typ, Name: name, Expr: expr, Path: nodePath(node), Data: data}
+}
+
+func (parser *TemplateParser) bindingsInjectionScript() string {
+	bindings := parser.Bindings()
+	if bindings == nil {
+		return ""
+	}
+
+	raw, err := jsonStable.MarshalToString(bindings)
+	if err != nil {
+		log.Error("[SUI] Bindings %s", err.Error())
+		return ""
+	}
+	return fmt.Sprintf(`<script type="application/json" id="%s">%s</script>`, BindingsID, raw)
+}
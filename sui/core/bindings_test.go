@@ -0,0 +1,49 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindings(t *testing.T) {
+	source := `<html><body><span class="{{ cls }}">{{ count }} items of {{ user.name }}</span><a :href="link.url">Link</a></body></html>`
+	data := Data{"count": 2, "cls": "badge", "user": map[string]interface{}{"name": "Yao"}, "link": map[string]interface{}{"url": "/"}}
+
+	// The bindings are not embedded by default
+	html, err := NewTemplateParser(data, &ParserOption{}).Render(source)
+	assert.Nil(t, err)
+	assert.NotContains(t, html, BindingsID)
+
+	parser := NewTemplateParser(data, &ParserOption{Route: "/shop", Bindings: true, StableKeys: true})
+	html, err = parser.Render(source)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `<script type="application/json" id="__sui_bindings">`)
+
+	bindings := parser.Bindings()
+	assert.Equal(t, "/shop", bindings.Route)
+	assert.True(t, bindings.StableKeys)
+	assert.Len(t, bindings.Bindings, 3)
+
+	types := map[string]Binding{}
+	for key, binding := range bindings.Bindings {
+		assert.Equal(t, key, binding.Key)
+		types[binding.Type+":"+binding.Name] = binding
+	}
+	text := types["text:"]
+	assert.Equal(t, "{{ count }} items of {{ user.name }}", text.Expr)
+	assert.Equal(t, "html:0/body:1/span:0/#text:0", text.Path)
+	assert.Equal(t, []string{"count", "user.name"}, text.Data)
+	assert.Contains(t, html, `s:key-text="`+text.Key+`"`)
+	assert.Equal(t, "{{ cls }}", types["attr:class"].Expr)
+	assert.Equal(t, "html:0/body:1/span:0", types["attr:class"].Path)
+	assert.Equal(t, []string{"link.url"}, types["attr:href"].Data)
+
+	// The embedded binding map is the same as the bindings of the parser
+	raw := strings.Split(strings.Split(html, `id="__sui_bindings">`)[1], "</script>")[0]
+	embedded := PageBindings{}
+	assert.Nil(t, jsoniter.UnmarshalFromString(raw, &embedded))
+	assert.Equal(t, *bindings, embedded)
+}
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Budgets the performance budgets of the built pages, the key is the route prefix and the longest prefix wins.
+// set in the budgets section of the sui DSL
+//
+//	"budgets": {
+//	  "/": {"html": 102400, "js": 262144, "blocking": 1},
+//	  "/landing": {"html": 51200, "js": 131072, "blocking": 0, "level": "error"}
+//	}
+type Budgets map[string]*Budget
+
+// Budget the performance budget of the pages, the zero limits are not checked
+type Budget struct {
+	HTML     int64  `json:"html,omitempty"`     // the max bytes of the built html
+	JS       int64  `json:"js,omitempty"`       // the max bytes of the inline and the local scripts
+	Blocking *int   `json:"blocking,omitempty"` // the max render-blocking scripts (in the head, no async or defer)
+	Level    string `json:"level,omitempty"`    // warning (default) or error, the error fails the build
+}
+
+// PageMetrics the metrics of the built page, recorded in the build manifest
+type PageMetrics struct {
+	HTML     int64    `json:"html"`              // the bytes of the built html
+	JS       int64    `json:"js"`                // the bytes of the inline scripts
+	Scripts  []string `json:"scripts,omitempty"` // the local scripts, the bytes are added by the check
+	Blocking int      `json:"blocking"`          // the render-blocking scripts
+}
+
+// BudgetViolation the metric of the page exceeds the budget
+type BudgetViolation struct {
+	Route  string `json:"route"`
+	Metric string `json:"metric"` // html, js or blocking
+	Value  int64  `json:"value"`
+	Limit  int64  `json:"limit"`
+	Level  string `json:"level"`
+}
+
+// Error the message of the violation
+func (v BudgetViolation) Error() string {
+	if v.Metric == "blocking" {
+		return fmt.Sprintf("%s has %d render-blocking scripts, the budget is %d", v.Route, v.Value, v.Limit)
+	}
+	return fmt.Sprintf("%s %s is %d bytes, the budget is %d bytes", v.Route, v.Metric, v.Value, v.Limit)
+}
+
+// get the budget of the route, nil if the route has no budget
+func (b Budgets) get(route string) *Budget {
+	var res *Budget = nil
+	matched := -1
+	for prefix, budget := range b {
+		prefix = "/" + strings.Trim(prefix, "/")
+		if len(prefix) <= matched {
+			continue
+		}
+		if prefix == "/" || route == prefix || strings.HasPrefix(route, prefix+"/") {
+			res, matched = budget, len(prefix)
+		}
+	}
+	return res
+}
+
+// recordMetrics record the metrics of the built page, the html is the compiled page
+func (m *BuildManifest) recordMetrics(page *Page, html string, doc *goquery.Document) {
+	metrics := &PageMetrics{HTML: int64(len(html)), Scripts: []string{}}
+	doc.Find("script").Each(func(i int, sel *goquery.Selection) {
+		typ := strings.ToLower(sel.AttrOr("type", ""))
+		if typ != "" && typ != "text/javascript" && typ != "module" && typ != "application/javascript" {
+			return // the json data of the page
+		}
+
+		src, external := sel.Attr("src")
+		if !external {
+			metrics.JS += int64(len(sel.Text()))
+			return
+		}
+
+		_, async := sel.Attr("async")
+		_, deferred := sel.Attr("defer")
+		if !async && !deferred && typ != "module" && sel.ParentsFiltered("head").Length() > 0 {
+			metrics.Blocking++
+		}
+		if internalLink(src) {
+			if u, err := url.Parse(src); err == nil {
+				metrics.Scripts = append(metrics.Scripts, u.Path)
+			}
+		}
+	})
+	m.Pages[page.Route] = metrics
+}
+
+// CheckBudgets check the metrics of the built pages against the budgets, the size gets the bytes of the
+// local script (e.g. /demo/assets/js/chart.js), false if the file is unknown
+func (m *BuildManifest) CheckBudgets(budgets Budgets, size func(file string) (int64, bool)) []BudgetViolation {
+	violations := []BudgetViolation{}
+	if len(budgets) == 0 {
+		return violations
+	}
+
+	routes := make([]string, 0, len(m.Pages))
+	for route := range m.Pages {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+
+	sizes := map[string]int64{}
+	for _, route := range routes {
+		budget := budgets.get(route)
+		if budget == nil {
+			continue
+		}
+
+		level := strings.ToLower(budget.Level)
+		if level != "error" {
+			level = "warning"
+		}
+
+		metrics := m.Pages[route]
+		js := metrics.JS
+		for _, file := range metrics.Scripts {
+			bytes, has := sizes[file]
+			if !has {
+				bytes, _ = size(file)
+				sizes[file] = bytes
+			}
+			js += bytes
+		}
+
+		if budget.HTML > 0 && metrics.HTML > budget.HTML {
+			violations = append(violations, BudgetViolation{Route: route, Metric: "html", Value: metrics.HTML, Limit: budget.HTML, Level: level})
+		}
+		if budget.JS > 0 && js > budget.JS {
+			violations = append(violations, BudgetViolation{Route: route, Metric: "js", Value: js, Limit: budget.JS, Level: level})
+		}
+		if budget.Blocking != nil && metrics.Blocking > *budget.Blocking {
+			violations = append(violations, BudgetViolation{Route: route, Metric: "blocking", Value: int64(metrics.Blocking), Limit: int64(*budget.Blocking), Level: level})
+		}
+	}
+	return violations
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildManifestCheckBudgets(t *testing.T) {
+	source := `<html><head>` +
+		`<script src="/demo/assets/libsui.min.js" type="text/javascript"></script>` +
+		`<script src="/demo/assets/js/chart.js?v=1"></script>` +
+		`<script src="/demo/assets/js/lazy.js" defer></script>` +
+		`<script src="https://cdn.example.com/analytics.js" async></script>` +
+		`</head><body><div>Hello</div>` +
+		`<script>console.log("hello")</script>` +
+		`<script name="data" type="json">{"title": "Hello"}</script>` +
+		`</body></html>`
+
+	doc, err := NewDocumentString(source)
+	assert.Nil(t, err)
+
+	manifest := NewBuildManifest()
+	manifest.recordMetrics(&Page{Route: "/landing/index"}, source, doc)
+	manifest.recordMetrics(&Page{Route: "/about"}, source, doc)
+
+	metrics := manifest.Pages["/landing/index"]
+	assert.Equal(t, int64(len(source)), metrics.HTML)
+	assert.Equal(t, int64(len(`console.log("hello")`)), metrics.JS)
+	assert.Equal(t, 2, metrics.Blocking)
+	assert.Equal(t, []string{"/demo/assets/libsui.min.js", "/demo/assets/js/chart.js", "/demo/assets/js/lazy.js"}, metrics.Scripts)
+
+	zero := 0
+	budgets := Budgets{
+		"/":        {JS: 100 * 1024},
+		"/landing": {HTML: 100, JS: 1024, Blocking: &zero, Level: "error"},
+	}
+	size := func(file string) (int64, bool) {
+		if file == "/demo/assets/js/chart.js" {
+			return 2048, true
+		}
+		return 0, false
+	}
+
+	violations := manifest.CheckBudgets(budgets, size)
+	messages := []string{}
+	for _, violation := range violations {
+		assert.Equal(t, "error", violation.Level)
+		messages = append(messages, violation.Error())
+	}
+	assert.Equal(t, []string{
+		fmt.Sprintf("/landing/index html is %d bytes, the budget is 100 bytes", len(source)),
+		"/landing/index js is 2068 bytes, the budget is 1024 bytes",
+		"/landing/index has 2 render-blocking scripts, the budget is 0",
+	}, messages)
+
+	// No budgets
+	assert.Empty(t, manifest.CheckBudgets(nil, size))
+}
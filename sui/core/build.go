@@ -65,6 +65,7 @@ func (page *Page) Build(ctx *BuildContext, option *BuildOption) (*goquery.Docume
 	body.SetAttr("s:assets", option.AssetRoot)
 
 	// Bind the Page events
+	EmitEvents(doc.Selection)
 	if !option.JitMode {
 		page.BindEvent(ctx, doc.Selection, "__page", true)
 	}
@@ -202,6 +203,7 @@ func (page *Page) BuildAsComponent(sel *goquery.Selection, ctx *BuildContext, op
 	page.parseDynamics(ctx, doc.Selection)
 
 	// Bind the component events
+	EmitEvents(doc.Selection)
 	page.BindEvent(ctx, doc.Selection, component, false)
 
 	body := doc.Selection.Find("body")
@@ -232,6 +234,10 @@ func (page *Page) BuildAsComponent(sel *goquery.Selection, ctx *BuildContext, op
 
 	// page.copyProps(ctx, sel, first, attrs...)
 	page.parseProps(sel, first, attrs...)
+	copyAsync(sel, first)
+	for _, attr := range undeclaredEmits(sel.Nodes[0], first.Nodes[0]) {
+		ctx.warnings = append(ctx.warnings, emitError(page.Route, attr, first.Nodes[0]).Error())
+	}
 	page.copySlots(sel, first)
 	page.copyChildren(sel, first)
 	page.buildComponents(doc, ctx, &opt)
@@ -420,6 +426,7 @@ func (page *Page) copyChildren(from *goquery.Selection, to *goquery.Selection) e
 	// copy trans-node and trans-text properties
 	transNode, hasTransNode := from.Attr("s:trans-node")
 	transText, hasTransText := from.Attr("s:trans-text")
+	transPlural, hasTransPlural := from.Attr("s:trans-plural")
 	if hasTransNode || hasTransText {
 		parent := to.Find("children").Parent()
 		if hasTransNode {
@@ -428,6 +435,9 @@ func (page *Page) copyChildren(from *goquery.Selection, to *goquery.Selection) e
 		if hasTransText {
 			parent.SetAttr("s:trans-text", transText)
 		}
+		if hasTransPlural {
+			parent.SetAttr("s:trans-plural", transPlural)
+		}
 	}
 
 	to.Find("children").ReplaceWithSelection(children)
@@ -446,8 +456,8 @@ func (page *Page) parseProps(from *goquery.Selection, to *goquery.Selection, ext
 
 	for _, attr := range attrs {
 
-		// Copy Event
-		if strings.HasPrefix(attr.Key, "s:event") || strings.HasPrefix(attr.Key, "s:on-") || strings.HasPrefix(attr.Key, "data:") || strings.HasPrefix(attr.Key, "json:") {
+		// Copy Event and the spread props
+		if strings.HasPrefix(attr.Key, "s:event") || strings.HasPrefix(attr.Key, "s:on-") || strings.HasPrefix(attr.Key, "data:") || strings.HasPrefix(attr.Key, "json:") || attr.Key == "s:props" {
 			to.SetAttr(attr.Key, attr.Val)
 			continue
 		}
@@ -624,11 +634,22 @@ func (page *Page) buildComponents(doc *goquery.Document, ctx *BuildContext, opti
 			return
 		}
 
+		// The async component call
+		if err := asyncCall(sel, page.namespace, i); err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s on page %s", err.Error(), page.Route))
+			sel.RemoveAttr(asyncAttr)
+		}
+
 		// Check if Just-In-Time Component ( "is" has variable )
 		if ctx.isJitComponent(name) {
 			sel.SetAttr("s:jit", "true")
 			sel.SetAttr("s:parent", page.namespace)
-			ctx.addJitComponent(name)
+			if componentRegistry(name) != nil {
+				return // fetched from the registry, not built
+			}
+			for _, pattern := range jitPatterns(name, sel.AttrOr(isAllowAttr, "")) {
+				ctx.addJitComponent(pattern)
+			}
 			return
 		}
 
@@ -821,7 +842,19 @@ func (page *Page) BuildScripts(ctx *BuildContext, option *BuildOption, component
 // BuildHTML build the html
 func (page *Page) BuildHTML(option *BuildOption) (string, error) {
 
-	html := string(page.Codes.HTML.Code)
+	// The statements of the custom delimiters, the document keeps the {{ }} statements
+	code, err := Delimit(page.Codes.HTML.Code, page.GetConfig().Delimiters)
+	if err != nil {
+		return "", fmt.Errorf("%s %s", page.Route, err.Error())
+	}
+
+	// Compose the layout chain of the page, the page fills the regions of the layouts
+	code, err = page.composeLayout(code)
+	if err != nil {
+		return "", err
+	}
+
+	html := code
 
 	if option.WithWrapper {
 		html = fmt.Sprintf("<body>%s</body>", html)
@@ -829,8 +862,14 @@ func (page *Page) BuildHTML(option *BuildOption) (string, error) {
 
 	if !option.IgnoreDocument {
 		html = string(page.Document)
-		if page.Codes.HTML.Code != "" {
-			html = strings.Replace(html, "{{ __page }}", page.Codes.HTML.Code, 1)
+
+		// Embed mode, render without the layout chrome
+		if embed := page.embed(); embed != nil && !embed.Document {
+			html = string(EmbedDocument(page.Document))
+		}
+
+		if code != "" {
+			html = strings.Replace(html, "{{ __page }}", code, 1)
 		}
 	}
 
@@ -838,6 +877,14 @@ func (page *Page) BuildHTML(option *BuildOption) (string, error) {
 		html = strings.ReplaceAll(html, "@assets", option.AssetRoot)
 	}
 
+	// Resolve the build-time directives by the environment of the build, e.g. <!--#if prod--> ... <!--#endif-->
+	if option.Env != "" {
+		html = resolveDirectives(html, option.Env)
+	}
+
+	// Remove the template comments, the notes are not in the compiled pages
+	html = stripComments(html)
+
 	res, err := page.CompileHTML([]byte(html), false)
 	if err != nil {
 		return "", err
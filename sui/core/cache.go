@@ -22,6 +22,12 @@ type Cache struct {
 	DataCacheTime time.Duration
 	Script        *Script
 	Imports       map[string]string
+	Embed         *PageEmbed
+	Mask          []MaskRule
+	Precompile    bool
+	Minify        bool
+	Hydration     *PageHydration
+	Bindings      bool
 }
 
 const (
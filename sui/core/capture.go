@@ -0,0 +1,311 @@
+package core
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/application"
+	"github.com/yaoapp/kun/log"
+)
+
+// CaptureMode the capture mode of the server, the requests of the routes are snapshotted into the fixtures, and the
+// templates of the fixtures are rendered again locally with the captured data by the replay, so the pages looking
+// wrong only in production are reproducible.
+// toggled by the sui.capture.* processes, or YAO_SUI_CAPTURE=/shop,/blog (* for all the routes) when starting
+//
+//	yao run sui.capture.on '::{"routes": ["/shop"], "max": 20}'
+//	yao run sui.capture.list
+//	yao run sui.capture.replay 20240102T150405-1a2b3c4d
+type CaptureMode struct {
+	Enabled bool      `json:"enabled"`
+	Routes  []string  `json:"routes,omitempty"` // the route prefixes, all the routes if empty
+	Max     int       `json:"max,omitempty"`    // the max fixtures of the capture, CaptureMax by default
+	Count   int       `json:"count"`            // the fixtures captured since enabled
+	Since   time.Time `json:"since,omitempty"`
+}
+
+// Capture the fixture of the request, the data is masked by the mask rules of the page, the cookies, the session and
+// the credentials of the request are not captured, the settings, the preferences and the device of the visitor are
+// captured as the data instead
+type Capture struct {
+	ID      string        `json:"id"`
+	Time    time.Time     `json:"time"`  // the time of the request, the $now of the replay
+	File    string        `json:"file"`  // the page file, e.g. /public/web/shop/index.sui
+	Route   string        `json:"route"` // the route of the request
+	Request *Request      `json:"request"`
+	Option  *ParserOption `json:"option"` // the locale, the theme and the options of the rendering
+	Data    Data          `json:"data"`
+	HTML    string        `json:"html"` // the compiled page
+}
+
+// CapturesRoot the root of the fixtures in the application
+var CapturesRoot = filepath.Join(string(os.PathSeparator), ".sui", "captures")
+
+// CaptureMax the max fixtures of the capture mode by default
+var CaptureMax = 100
+
+// captureHeaders the headers not captured, the credentials of the visitor
+var captureHeaders = map[string]bool{"Cookie": true, "Authorization": true, "Proxy-Authorization": true, MaintenanceTokenHeader: true}
+
+// captureCookies the cookies captured, the others (e.g. the session id) are not captured
+var captureCookies = map[string]bool{"locale": true, "color-theme": true}
+
+var captureIDRe = regexp.MustCompile(`^[0-9A-Za-z][0-9A-Za-z\-]*$`)
+var captureMode = captureFromEnv()
+var captureMutex sync.Mutex
+
+// captureFromEnv the capture mode of YAO_SUI_CAPTURE
+func captureFromEnv() *CaptureMode {
+	routes := strings.TrimSpace(os.Getenv("YAO_SUI_CAPTURE"))
+	if routes == "" {
+		return &CaptureMode{}
+	}
+
+	m := &CaptureMode{Enabled: true, Since: time.Now()}
+	if routes != "*" {
+		for _, route := range strings.Split(routes, ",") {
+			if route = strings.TrimSpace(route); route != "" {
+				m.Routes = append(m.Routes, route)
+			}
+		}
+	}
+	return m
+}
+
+// GetCaptureMode get the capture mode
+func GetCaptureMode() CaptureMode {
+	captureMutex.Lock()
+	defer captureMutex.Unlock()
+	return *captureMode
+}
+
+// SetCaptureMode set the capture mode, the count is reset
+func SetCaptureMode(m *CaptureMode) {
+	if m == nil {
+		m = &CaptureMode{}
+	}
+	mode := *m
+	mode.Count = 0
+	mode.Since = time.Time{}
+	if mode.Enabled {
+		mode.Since = time.Now()
+	}
+
+	captureMutex.Lock()
+	defer captureMutex.Unlock()
+	captureMode = &mode
+}
+
+// CaptureRoute check if the request of the route should be captured, the count of the capture mode is increased
+func CaptureRoute(route string) bool {
+	captureMutex.Lock()
+	defer captureMutex.Unlock()
+	if !captureMode.Enabled {
+		return false
+	}
+
+	max := captureMode.Max
+	if max <= 0 {
+		max = CaptureMax
+	}
+	if captureMode.Count >= max {
+		return false
+	}
+
+	matched := len(captureMode.Routes) == 0
+	for _, prefix := range captureMode.Routes {
+		prefix = "/" + strings.Trim(prefix, "/")
+		if prefix == "/" || route == prefix || strings.HasPrefix(route, prefix+"/") {
+			matched = true
+			break
+		}
+	}
+	if matched {
+		captureMode.Count++
+	}
+	return matched
+}
+
+// NewCapture snapshot the request of the page, call it before rendering, the data and the option are copied
+func NewCapture(file string, source string, data Data, option *ParserOption) *Capture {
+	now := time.Now()
+	opt := *option
+	opt.Request = nil
+	opt.Timing = nil
+	opt.Profile = nil
+	opt.Script = nil
+
+	snapshot := Data{}
+	for key, value := range data {
+		snapshot[key] = value
+	}
+
+	var req *Request = nil
+	if r := option.Request; r != nil {
+		now = r.Time()
+		req = &Request{
+			Method:    r.Method,
+			AssetRoot: r.AssetRoot,
+			Referer:   r.Referer,
+			Query:     r.Query,
+			Params:    r.Params,
+			Headers:   url.Values{},
+			URL:       r.URL,
+			Theme:     r.Theme,
+			Locale:    r.Locale,
+			Country:   r.Country,
+		}
+		for name, values := range r.Headers {
+			if !captureHeaders[http.CanonicalHeaderKey(name)] {
+				req.Headers[name] = values
+			}
+		}
+
+		// The data of the visitor are resolved when capturing, the replay has no session
+		if hasSettings() {
+			snapshot[settingKey] = SettingsData(settingsTenant(r))
+		}
+		if hasPreferences() {
+			snapshot[prefKey] = PreferencesData(PreferencesUser(r.sessionData()))
+		}
+		snapshot[deviceKey] = r.Device().Data()
+	}
+
+	if cookies, ok := snapshot["$cookie"].(map[string]string); ok {
+		captured := map[string]string{}
+		for name, value := range cookies {
+			if captureCookies[name] {
+				captured[name] = value
+			}
+		}
+		snapshot["$cookie"] = captured
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(fmt.Sprintf("%s|%s|%d", file, opt.Route, now.UnixNano())))
+	return &Capture{
+		ID:      fmt.Sprintf("%s-%08x", now.UTC().Format("20060102T150405"), h.Sum32()),
+		Time:    now,
+		File:    file,
+		Route:   opt.Route,
+		Request: req,
+		Option:  &opt,
+		Data:    snapshot,
+		HTML:    source,
+	}
+}
+
+// Save write the fixture into the CapturesRoot of the application
+func (capture *Capture) Save() error {
+	raw, err := jsoniter.MarshalIndent(capture, "", "  ")
+	if err != nil {
+		return fmt.Errorf("capture %s: %s", capture.ID, err.Error())
+	}
+
+	dir := filepath.Join(application.App.Root(), CapturesRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, capture.ID+".json"), raw, 0644); err != nil {
+		return err
+	}
+	log.Info("[SUI] The request %s is captured %s", capture.Route, capture.ID)
+	return nil
+}
+
+// LoadCapture read the fixture of the id
+func LoadCapture(id string) (*Capture, error) {
+	if !captureIDRe.MatchString(id) {
+		return nil, fmt.Errorf("the capture %s is invalid", id)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(application.App.Root(), CapturesRoot, id+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("the capture %s is not found", id)
+		}
+		return nil, err
+	}
+
+	capture := &Capture{}
+	if err := jsoniter.Unmarshal(raw, capture); err != nil {
+		return nil, fmt.Errorf("the capture %s is invalid: %s", id, err.Error())
+	}
+	return capture, nil
+}
+
+// ListCaptures the fixtures of the route prefix, the latest first, without the data and the pages
+func ListCaptures(prefix string) ([]*Capture, error) {
+	entries, err := os.ReadDir(filepath.Join(application.App.Root(), CapturesRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Capture{}, nil
+		}
+		return nil, err
+	}
+
+	captures := []*Capture{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		capture, err := LoadCapture(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			log.Warn("[SUI] %s", err.Error())
+			continue
+		}
+		if prefix != "" && capture.Route != prefix && !strings.HasPrefix(capture.Route, strings.TrimSuffix(prefix, "/")+"/") {
+			continue
+		}
+		captures = append(captures, &Capture{ID: capture.ID, Time: capture.Time, File: capture.File, Route: capture.Route})
+	}
+
+	sort.Slice(captures, func(i, j int) bool { return captures[i].Time.After(captures[j].Time) })
+	return captures, nil
+}
+
+// Replay render the template of the fixture again with the captured data, as of the time of the request. it is a
+// template-only replay, the data of the fixture is the data after executing the page data and the global data, they
+// are not executed again, so the changes of the data processes are not reproduced. the script is the backend of the
+// processes called while rendering, the components are rendered by the local templates and their BeforeRender hooks.
+// the cache of the page is disabled
+func (capture *Capture) Replay(script *Script) (string, error) {
+	option := ParserOption{}
+	if capture.Option != nil {
+		option = *capture.Option
+	}
+
+	req := &Request{}
+	if capture.Request != nil {
+		r := *capture.Request
+		req = &r
+	}
+	req.Now = capture.Time
+	req.Script = script
+	option.Request = req
+	option.Script = script
+	option.DisableCache = true
+
+	data := Data{}
+	for key, value := range capture.Data {
+		data[key] = value
+	}
+	data[nowKey] = capture.Time
+
+	parser := NewTemplateParser(data, &option)
+	html, err := parser.Render(capture.HTML)
+	if err != nil {
+		return "", fmt.Errorf("replay %s: %s", capture.ID, err.Error())
+	}
+	return html, nil
+}
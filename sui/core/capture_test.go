@@ -0,0 +1,83 @@
+package core
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/gou/application"
+)
+
+func TestCaptureMode(t *testing.T) {
+	defer SetCaptureMode(nil)
+
+	SetCaptureMode(&CaptureMode{Enabled: true, Routes: []string{"/shop"}, Max: 2})
+	assert.False(t, CaptureRoute("/blog"))
+	assert.False(t, CaptureRoute("/shopping"))
+	assert.True(t, CaptureRoute("/shop"))
+	assert.True(t, CaptureRoute("/shop/cart"))
+	assert.False(t, CaptureRoute("/shop/cart")) // the max fixtures
+	assert.Equal(t, 2, GetCaptureMode().Count)
+
+	SetCaptureMode(&CaptureMode{Enabled: false})
+	assert.False(t, CaptureRoute("/shop"))
+}
+
+func TestCaptureReplay(t *testing.T) {
+	defer func(root string) { CapturesRoot = root }(CapturesRoot)
+	CapturesRoot = filepath.Join(string(os.PathSeparator), fmt.Sprintf("__captures_test_%d", time.Now().UnixNano()))
+	defer os.RemoveAll(filepath.Join(application.App.Root(), CapturesRoot))
+
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	req := &Request{
+		Method:  "GET",
+		URL:     ReqeustURL{Path: "/shop/cart"},
+		Headers: url.Values{"Cookie": {"sid=secret; locale=fr"}, "Authorization": {"Bearer secret"}, "User-Agent": {"Mozilla/5.0"}},
+		Sid:     "secret",
+		Now:     now,
+	}
+	data := Data{"title": "Cart", "$cookie": map[string]string{"sid": "secret", "locale": "fr"}}
+	source := `<html><body><h1>{{ title }}</h1><p>{{ $now.Year() }}</p></body></html>`
+
+	capture := NewCapture("/public/shop/cart.sui", source, data, &ParserOption{Route: "/shop/cart", Locale: "fr", Request: req})
+	assert.Nil(t, capture.Save())
+	assert.True(t, strings.HasPrefix(capture.ID, "20240102T150405-"))
+
+	// The id is derived from the time of the request
+	again := NewCapture("/public/shop/cart.sui", source, data, &ParserOption{Route: "/shop/cart", Locale: "fr", Request: req})
+	assert.Equal(t, capture.ID, again.ID)
+
+	// The credentials of the visitor are not captured
+	loaded, err := LoadCapture(capture.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "/shop/cart", loaded.Route)
+	assert.Equal(t, "fr", loaded.Option.Locale)
+	assert.True(t, now.Equal(loaded.Time))
+	assert.Equal(t, "", loaded.Request.Sid)
+	assert.Empty(t, loaded.Request.Headers.Get("Cookie"))
+	assert.Empty(t, loaded.Request.Headers.Get("Authorization"))
+	assert.Equal(t, "Mozilla/5.0", loaded.Request.Headers.Get("User-Agent"))
+	assert.Equal(t, map[string]interface{}{"locale": "fr"}, loaded.Data["$cookie"])
+
+	// The replay renders the page as of the time of the request
+	html, err := loaded.Replay(nil)
+	assert.Nil(t, err)
+	assert.Contains(t, html, "<h1>Cart</h1>")
+	assert.Contains(t, html, "<p>2024</p>")
+
+	captures, err := ListCaptures("/shop")
+	assert.Nil(t, err)
+	assert.Len(t, captures, 1)
+	assert.Equal(t, capture.ID, captures[0].ID)
+	assert.Empty(t, captures[0].HTML)
+	captures, _ = ListCaptures("/blog")
+	assert.Len(t, captures, 0)
+
+	_, err = LoadCapture("../secret")
+	assert.Contains(t, err.Error(), "the capture ../secret is invalid")
+}
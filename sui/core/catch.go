@@ -0,0 +1,75 @@
+package core
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+func (parser *TemplateParser) isCatch(sel *goquery.Selection) bool {
+	_, has := sel.Attr("s:catch")
+	return has
+}
+
+// catchNode the error boundary, render the children again with the error variable when any descendant statement fails
+// <div s:catch="err"><p s:if="!err">{{ user.profile.name }}</p><p s:if="err">{{ err.message }}</p></div>
+func (parser *TemplateParser) catchNode(sel *goquery.Selection) {
+	defer parser.option.Timing.Start("catch", "s:catch")()
+	name := sel.AttrOr("s:catch", "")
+	if name == "" {
+		name = "error"
+	}
+
+	node := sel.Nodes[0]
+	backup := sel.Clone().Nodes[0]
+	start := len(parser.errors)
+	replaces := len(parser.replace)
+
+	parser.pushScope()
+	defer parser.popScope()
+	parser.setVar(name, nil)
+
+	parser.catching++
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		parser.parseNode(child)
+	}
+	parser.catching--
+
+	if len(parser.errors) == start {
+		return
+	}
+
+	// Discard the rendered children
+	errors := []string{}
+	for _, err := range parser.errors[start:] {
+		errors = append(errors, errorMessage(err))
+	}
+	parser.errors = parser.errors[:start]
+	parser.releaseReplace(replaces)
+
+	for child := node.FirstChild; child != nil; child = node.FirstChild {
+		node.RemoveChild(child)
+	}
+	for child := backup.FirstChild; child != nil; child = backup.FirstChild {
+		backup.RemoveChild(child)
+		node.AppendChild(child)
+	}
+
+	// Render the children with the error
+	parser.setVar(name, map[string]interface{}{"message": errors[0], "errors": errors})
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		parser.parseNode(child)
+	}
+}
+
+// catchValues record the errors of the bindings inside the s:catch boundary, the directive is the attribute name
+// of the binding, or "text" for the text nodes
+func (parser *TemplateParser) catchValues(node *html.Node, directive string, values []StringValue) {
+	if parser.catching == 0 {
+		return
+	}
+	for _, value := range values {
+		if value.Error != nil {
+			parser.renderError(node, directive, value.Stmt, value.Error)
+		}
+	}
+}
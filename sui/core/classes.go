@@ -0,0 +1,137 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// classStyleNode the s:class and s:style object bindings, merged with the class and the style attributes
+//
+//	<li class="item" s:class="{ active: item.selected, 'is-new': item.new }">  => class="item active"
+//	<p style="margin: 0" s:style="{ color: theme.primary, display: item.hidden ? 'none' : '' }"> => style="margin: 0; color: #333"
+//
+// the classes of the truthy values are added, the properties of the empty values are skipped,
+// and the properties of the object override the same properties of the style attribute
+func (parser *TemplateParser) classStyleNode(sel *goquery.Selection) {
+	if stmt, has := sel.Attr("s:class"); has {
+		defer parser.option.Timing.Start("class", "s:class")()
+		value, err := parser.classStyleExec(stmt)
+		if err != nil {
+			parser.renderError(sel.Nodes[0], "s:class", stmt, err)
+		} else {
+			sel.SetAttr("class", mergeClasses(sel.AttrOr("class", ""), value))
+		}
+	}
+
+	if stmt, has := sel.Attr("s:style"); has {
+		defer parser.option.Timing.Start("style", "s:style")()
+		value, err := parser.classStyleExec(stmt)
+		if err != nil {
+			parser.renderError(sel.Nodes[0], "s:style", stmt, err)
+			return
+		}
+		style, err := mergeStyles(sel.AttrOr("style", ""), value)
+		if err != nil {
+			parser.renderError(sel.Nodes[0], "s:style", stmt, err)
+			return
+		}
+		if style == "" {
+			sel.RemoveAttr("style")
+			return
+		}
+		sel.SetAttr("style", style)
+	}
+}
+
+// classStyleExec exec the object expression, the {{ }} of the statement is optional
+func (parser *TemplateParser) classStyleExec(stmt string) (interface{}, error) {
+	stmt = strings.TrimSpace(stmt)
+	if strings.HasPrefix(stmt, "{{") && strings.HasSuffix(stmt, "}}") {
+		stmt = strings.TrimSpace(stmt[2 : len(stmt)-2])
+	}
+	res, _, err := parser.data.ExecGuard(stmt, parser.guard)
+	return res, err
+}
+
+// mergeClasses merge the classes of the value with the class attribute, the value is the object of the class names
+// and the conditions, the list or the string of the class names
+func mergeClasses(class string, value interface{}) string {
+	classes := strings.Fields(class)
+	has := map[string]bool{}
+	for _, name := range classes {
+		has[name] = true
+	}
+
+	add := func(names ...string) {
+		for _, name := range names {
+			if name != "" && !has[name] {
+				has[name] = true
+				classes = append(classes, name)
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		add(strings.Fields(v)...)
+
+	case []interface{}:
+		for _, item := range v {
+			if item != nil {
+				add(strings.Fields(fmt.Sprintf("%v", item))...)
+			}
+		}
+
+	case map[string]interface{}:
+		for _, name := range sortedKeys(v) {
+			if empty, _ := _empty(v[name]); empty != true {
+				add(strings.Fields(name)...)
+			}
+		}
+	}
+	return strings.Join(classes, " ")
+}
+
+// mergeStyles merge the properties of the object with the style attribute
+func mergeStyles(style string, value interface{}) (string, error) {
+	if value == nil {
+		return style, nil
+	}
+
+	props, ok := value.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("s:style should be an object, got %T", value)
+	}
+
+	res := []string{}
+	for _, decl := range strings.Split(style, ";") {
+		decl = strings.TrimSpace(decl)
+		if decl == "" {
+			continue
+		}
+		name, _, _ := strings.Cut(decl, ":")
+		if _, override := props[strings.TrimSpace(name)]; override {
+			continue
+		}
+		res = append(res, decl)
+	}
+
+	for _, name := range sortedKeys(props) {
+		switch v := props[name].(type) {
+		case nil:
+			continue
+		case bool:
+			if !v {
+				continue
+			}
+		case string:
+			if strings.TrimSpace(v) == "" {
+				continue
+			}
+		}
+		res = append(res, fmt.Sprintf("%s: %v", name, props[name]))
+	}
+	return strings.Join(res, "; "), nil
+}
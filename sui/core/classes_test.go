@@ -0,0 +1,41 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeClasses(t *testing.T) {
+	assert.Equal(t, "item active is-new", mergeClasses("item", map[string]interface{}{"active": true, "is-new": 1, "hidden": false, "empty": ""}))
+	assert.Equal(t, "item active", mergeClasses("item active", map[string]interface{}{"active": true}))
+	assert.Equal(t, "a b c", mergeClasses("a", []interface{}{"b", nil, "c"}))
+	assert.Equal(t, "a b", mergeClasses("", "a b"))
+}
+
+func TestMergeStyles(t *testing.T) {
+	style, err := mergeStyles("margin: 0; color: red", map[string]interface{}{"color": "#333", "opacity": 0, "display": ""})
+	assert.Nil(t, err)
+	assert.Equal(t, "margin: 0; color: #333; opacity: 0", style)
+
+	_, err = mergeStyles("", "color: red")
+	assert.NotNil(t, err)
+}
+
+func TestParserClassStyle(t *testing.T) {
+	data := Data{
+		"theme": map[string]interface{}{"primary": "#0af"},
+		"items": []interface{}{
+			map[string]interface{}{"name": "A", "selected": true, "new": false},
+			map[string]interface{}{"name": "B", "selected": false, "new": true},
+		},
+	}
+	parser := NewTemplateParser(data, &ParserOption{})
+	html, err := parser.Render(`<ul>` +
+		`<li s:for="items" s:for-item="item" class="item" s:class="{ active: item.selected, 'is-new': item.new }">{{ item.name }}</li>` +
+		`</ul><p style="margin: 0" s:style="{ color: theme.primary }">Hi</p>`)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `class="item active"`)
+	assert.Contains(t, html, `class="item is-new"`)
+	assert.Contains(t, html, `style="margin: 0; color: #0af"`)
+}
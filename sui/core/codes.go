@@ -0,0 +1,213 @@
+package core
+
+import (
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// MaxCodeSize the max width and height (in pixels) of the generated images
+var MaxCodeSize = 2048
+
+// MaxCodeModule the max width (in pixels) of the barcode module
+var MaxCodeModule = 16
+
+// codeComponents the built-in generated image components
+var codeComponents = map[string]bool{
+	"s:qrcode":      true,
+	"s:barcode":     true,
+	"s:avatar":      true,
+	"s:identicon":   true,
+	"s:placeholder": true,
+}
+
+// isCode check if the node is a built-in generated image component, e.g. <s:qrcode>, <s:barcode>, <s:avatar>
+func (parser *TemplateParser) isCode(sel *goquery.Selection) bool {
+	return len(sel.Nodes) > 0 && codeComponents[sel.Nodes[0].Data]
+}
+
+// codeNode render the <s:qrcode> and <s:barcode> components as the inline SVG (or the data-URI PNG if format="png")
+//
+//	<s:qrcode value="{{ ticket.url }}" size="160" level="M" color="#000" background="#fff"></s:qrcode>
+//	<s:barcode value="{{ invoice.no }}" width="2" height="60" label="true"></s:barcode>
+//	<s:avatar value="{{ user.name }}" size="40" shape="circle"></s:avatar>
+//	<s:identicon value="{{ user.email }}" size="40"></s:identicon>
+//	<s:placeholder width="320" height="240" text="No image"></s:placeholder>
+//
+// the id, class, style and alt attributes are kept on the rendered element
+func (parser *TemplateParser) codeNode(sel *goquery.Selection) {
+	defer parser.option.Timing.Start("code", "Generated images")()
+	parser.parsed(sel)
+	parser.hide(sel)
+
+	name := strings.TrimPrefix(sel.Nodes[0].Data, "s:")
+	attrs := map[string]string{}
+	for _, attr := range sel.Nodes[0].Attr {
+		val, values := parser.data.ReplaceGuard(attr.Val, parser.guard)
+		parser.catchValues(sel.Nodes[0], attr.Key, values)
+		attrs[attr.Key] = val
+	}
+
+	value := attrs["value"]
+	if value == "" && name != "placeholder" {
+		parser.codeError(sel, fmt.Errorf("%s error: the value is required", name))
+		return
+	}
+
+	format := strings.ToLower(attrs["format"])
+	fill := codeAttr(attrs, "color", "#000")
+	background := codeAttr(attrs, "background", "#fff")
+
+	source := ""
+	switch name {
+	case "qrcode":
+		qr, err := EncodeQR(value, QRLevel(attrs["level"]))
+		if err != nil {
+			parser.codeError(sel, err)
+			return
+		}
+
+		size := codeInt(attrs, "size", 128, MaxCodeSize)
+		if format == "png" {
+			scale := size / (qr.Size + 8)
+			source = pngImage(qr.Matrix(), scale, scale, size, size)
+			break
+		}
+		source = qr.SVG(size, fill, background)
+
+	case "barcode":
+		barcode, err := EncodeCode128(value)
+		if err != nil {
+			parser.codeError(sel, err)
+			return
+		}
+
+		module := codeInt(attrs, "width", 2, MaxCodeModule)
+		height := codeInt(attrs, "height", 60, MaxCodeSize)
+		if limit := MaxCodeSize / (barcode.Width() + 20); module > limit {
+			module = limit
+		}
+		if module < 1 {
+			parser.codeError(sel, fmt.Errorf("barcode error: the value is too long"))
+			return
+		}
+
+		if format == "png" {
+			width := (barcode.Width() + 20) * module
+			source = pngImage(barcode.Matrix(), module, height, width, height)
+			break
+		}
+		source = barcode.SVG(module, height, fill, background, attrs["label"] == "true")
+
+	case "avatar":
+		source = Avatar(value, codeInt(attrs, "size", 40, MaxCodeSize), attrs["shape"])
+
+	case "identicon":
+		source = Identicon(value, codeInt(attrs, "size", 40, MaxCodeSize), codeAttr(attrs, "background", "#f0f0f0"))
+
+	case "placeholder":
+		width, height := codeInt(attrs, "width", 320, MaxCodeSize), codeInt(attrs, "height", 240, MaxCodeSize)
+		source = Placeholder(width, height, attrs["text"], codeAttr(attrs, "color", "#999"), codeAttr(attrs, "background", "#eee"))
+	}
+
+	nodes, err := html.ParseFragment(strings.NewReader(source), fragmentContext(sel.Nodes[0].Parent))
+	if err != nil || len(nodes) == 0 {
+		parser.codeError(sel, fmt.Errorf("%s error: %v", name, err))
+		return
+	}
+
+	// Keep the attributes of the component
+	node := nodes[0]
+	for _, key := range []string{"id", "class", "style"} {
+		if val, has := attrs[key]; has {
+			node.Attr = append(node.Attr, html.Attribute{Key: key, Val: val})
+		}
+	}
+	if alt, has := attrs["alt"]; has {
+		if node.Data == "img" {
+			node.Attr = append(node.Attr, html.Attribute{Key: "alt", Val: alt})
+		} else {
+			node.Attr = append(node.Attr, html.Attribute{Key: "role", Val: "img"}, html.Attribute{Key: "aria-label", Val: alt})
+		}
+	}
+	parser.addReplace(sel, nodes)
+}
+
+// codeError keep the node hidden and record the error
+func (parser *TemplateParser) codeError(sel *goquery.Selection, err error) {
+	parser.renderError(sel.Nodes[0], sel.Nodes[0].Data, sel.AttrOr("value", ""), err)
+	setError(sel, err)
+}
+
+func codeAttr(attrs map[string]string, name string, defaultValue string) string {
+	if val := strings.TrimSpace(attrs[name]); val != "" {
+		return val
+	}
+	return defaultValue
+}
+
+// codeInt get the size attribute, the value is clamped to the max
+func codeInt(attrs map[string]string, name string, defaultValue int, max int) int {
+	val, err := strconv.Atoi(strings.TrimSpace(attrs[name]))
+	if err != nil || val <= 0 {
+		return defaultValue
+	}
+	if val > max {
+		return max
+	}
+	return val
+}
+
+// svgImage the SVG element of the generated images
+func svgImage(width, height, viewWidth, viewHeight int, background string, content string, attrs ...string) string {
+	extra := ""
+	if len(attrs) > 0 {
+		extra = " " + strings.Join(attrs, " ")
+	}
+	return fmt.Sprintf(
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges"%s><rect width="100%%" height="100%%" fill="%s"/>%s</svg>`,
+		width, height, viewWidth, viewHeight, extra, svgEscape(background), content,
+	)
+}
+
+var svgEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&#34;", "'", "&#39;")
+
+func svgEscape(value string) string {
+	return svgEscaper.Replace(value)
+}
+
+// pngImage render the matrix as the data-URI PNG image (black on white), each module is scaleX x scaleY pixels
+func pngImage(matrix [][]bool, scaleX, scaleY int, width, height int) string {
+	if scaleX < 1 {
+		scaleX = 1
+	}
+	if scaleY < 1 {
+		scaleY = 1
+	}
+
+	img := image.NewGray(image.Rect(0, 0, len(matrix[0])*scaleX, len(matrix)*scaleY))
+	for y := 0; y < img.Rect.Dy(); y++ {
+		for x := 0; x < img.Rect.Dx(); x++ {
+			c := color.Gray{Y: 255}
+			if matrix[y/scaleY][x/scaleX] {
+				c = color.Gray{Y: 0}
+			}
+			img.SetGray(x, y, c)
+		}
+	}
+
+	buf := acquireBuffer()
+	defer releaseBuffer(buf)
+	png.Encode(buf, img)
+	return fmt.Sprintf(
+		`<img src="data:image/png;base64,%s" width="%d" height="%d"/>`,
+		base64.StdEncoding.EncodeToString(buf.Bytes()), width, height,
+	)
+}
@@ -0,0 +1,174 @@
+package core
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/yaoapp/yao/config"
+)
+
+// commentsRe the template comments {# note #}, the {#- and -#} trim the whitespace before and after the comment.
+// the whitespace after {# and before #} is required, so the {#id} of the styles and the scripts is not a comment
+var commentsRe = regexp.MustCompile(`(\s*)\{#(-?)\s(?:[\s\S]*?\s)?(-?)#\}(\s*)`)
+
+// commentsHTMLRe the template comments in the html comment syntax <!--s: note -->, for the editors
+var commentsHTMLRe = regexp.MustCompile(`<!--s:[\s\S]*?-->`)
+
+// CommentsEnv the environment of the build-time directives, the mode of the app (development, production) by default,
+// e.g. YAO_SUI_BUILD_ENV=staging
+var CommentsEnv = os.Getenv("YAO_SUI_BUILD_ENV")
+
+// directiveBlockRe the conditional compile blocks, the blocks can be nested
+//
+//	<!--#if prod--> <script src="/analytics.js"></script> <!--#elif staging--> ... <!--#else--> ... <!--#endif-->
+var directiveBlockRe = regexp.MustCompile(`<!--#(if|elif|else|endif)(?:\s+([^>]*?))?\s*-->`)
+
+// directiveRe the build-only comments, the content is kept if the condition is true, e.g. <!--#dev <pre>{{ $json }}</pre> -->
+var directiveRe = regexp.MustCompile(`<!--#(!?[A-Za-z][A-Za-z0-9_:.\-|!]*)(?:\s([\s\S]*?))?-->`)
+
+// directiveSSI the server side includes, <!--#include virtual="/footer.html" --> is not a directive
+var directiveSSI = map[string]bool{"include": true, "echo": true, "set": true, "config": true, "exec": true, "fsize": true, "flastmod": true, "printenv": true}
+
+// directiveAlias the short names of the environments
+var directiveAlias = map[string]string{"dev": "development", "prod": "production"}
+
+// commentsEnv the environment of the build-time directives
+func commentsEnv() string {
+	if CommentsEnv != "" {
+		return CommentsEnv
+	}
+	return config.Conf.Mode
+}
+
+// resolveDirectives resolve the build-time directives by the environment, the conditional compile blocks and the
+// build-only comments, so the development helpers never ship. the condition is the environments separated by |,
+// ! negates, env:NAME is true if the environment variable is set, e.g. <!--#if prod|staging-->, <!--#!dev ... -->
+func resolveDirectives(source string, env string) string {
+	if !strings.Contains(source, "<!--#") {
+		return source
+	}
+
+	matches := directiveBlockRe.FindAllStringSubmatchIndex(source, -1)
+	if len(matches) > 0 {
+		var res strings.Builder
+		kept, taken := []bool{}, []bool{} // the frames of the nested blocks
+		keep := func() bool {
+			for _, k := range kept {
+				if !k {
+					return false
+				}
+			}
+			return true
+		}
+
+		last := 0
+		for _, m := range matches {
+			if keep() {
+				res.WriteString(source[last:m[0]])
+			}
+			last = m[1]
+			cond := ""
+			if m[4] >= 0 {
+				cond = source[m[4]:m[5]]
+			}
+
+			top := len(kept) - 1
+			switch source[m[2]:m[3]] {
+			case "if":
+				ok := directiveTrue(cond, env)
+				kept, taken = append(kept, ok), append(taken, ok)
+			case "elif":
+				if top >= 0 {
+					kept[top] = !taken[top] && directiveTrue(cond, env)
+					taken[top] = taken[top] || kept[top]
+				}
+			case "else":
+				if top >= 0 {
+					kept[top], taken[top] = !taken[top], true
+				}
+			case "endif":
+				if top >= 0 {
+					kept, taken = kept[:top], taken[:top]
+				}
+			}
+		}
+		if keep() { // the block without the endif is closed at the end
+			res.WriteString(source[last:])
+		}
+		source = res.String()
+	}
+
+	return directiveRe.ReplaceAllStringFunc(source, func(comment string) string {
+		m := directiveRe.FindStringSubmatch(comment)
+		if directiveSSI[m[1]] {
+			return comment
+		}
+		if !directiveTrue(m[1], env) {
+			return ""
+		}
+		return strings.TrimSpace(m[2])
+	})
+}
+
+// directiveTrue check the condition of the directive against the environment
+func directiveTrue(cond string, env string) bool {
+	for _, term := range strings.Split(cond, "|") {
+		term = strings.TrimSpace(term)
+		not := strings.HasPrefix(term, "!")
+		term = strings.TrimPrefix(term, "!")
+		if term == "" {
+			continue
+		}
+
+		ok := false
+		if strings.HasPrefix(term, "env:") {
+			value := strings.ToLower(os.Getenv(strings.TrimPrefix(term, "env:")))
+			ok = value != "" && value != "false" && value != "0"
+		} else {
+			if alias, has := directiveAlias[term]; has {
+				term = alias
+			}
+			ok = strings.EqualFold(term, env)
+		}
+		if ok != not {
+			return true
+		}
+	}
+	return false
+}
+
+// stripComments remove the template comments, the notes of the developers are never rendered, and resolve the
+// build-time directives by the environment of the app.
+// the html comments are kept as they are, e.g. {# the hero of the home page #} <!--s: TODO: the dark mode -->
+func stripComments(source string) string {
+	if strings.Contains(source, "<!--#") {
+		source = resolveDirectives(source, commentsEnv())
+	}
+	if strings.Contains(source, "<!--s:") {
+		source = commentsHTMLRe.ReplaceAllString(source, "")
+	}
+	if !strings.Contains(source, "{#") {
+		return source
+	}
+
+	matches := commentsRe.FindAllStringSubmatchIndex(source, -1)
+	if len(matches) == 0 {
+		return source
+	}
+
+	var res strings.Builder
+	last := 0
+	for _, m := range matches {
+		res.WriteString(source[last:m[0]])
+		if m[5]-m[4] == 0 { // {# keeps the whitespace before the comment
+			res.WriteString(source[m[2]:m[3]])
+		}
+		if m[7]-m[6] == 0 { // #} keeps the whitespace after the comment
+			res.WriteString(source[m[8]:m[9]])
+		}
+		last = m[1]
+	}
+	res.WriteString(source[last:])
+	return res.String()
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripComments(t *testing.T) {
+	assert.Equal(t, `<p>Hello</p>  <p>World</p>`, stripComments(`<p>Hello</p> {# the note #} <p>World</p>`))
+	assert.Equal(t, `<p>Hello</p><p>World</p>`, stripComments("<p>Hello</p>\n  {#- the note -#}\n  <p>World</p>"))
+	assert.Equal(t, "<p>Hello</p> <p>World</p>", stripComments("<p>Hello</p> {#- the note #} <p>World</p>"))
+	assert.Equal(t, "<ul>\n<li>A</li></ul>", stripComments("<ul>\n{# the list\n  of the items #}<li>A</li></ul>"))
+	assert.Equal(t, `<p>Hello</p>`, stripComments(`<p>Hello</p><!--s: TODO: the dark mode -->`))
+	assert.Equal(t, `<p>Hello</p>`, stripComments(`<p>Hello</p>{# #}`))
+
+	// Not the template comments
+	assert.Equal(t, `<!-- the html comment --><p>Hello</p>`, stripComments(`<!-- the html comment --><p>Hello</p>`))
+	assert.Equal(t, `<style>a{#id{color:red}}</style>`, stripComments(`<style>a{#id{color:red}}</style>`))
+	assert.Equal(t, `<script>class A{#x=1}</script>`, stripComments(`<script>class A{#x=1}</script>`))
+}
+
+func TestRenderComments(t *testing.T) {
+	source := `<html><body><div class="hero">{# the hero of the home page #}<h1>{{ title }}</h1>` +
+		`<!--s: TODO: the dark mode --><!--[if IE]>IE<![endif]--></div></body></html>`
+	parser := NewTemplateParser(Data{"title": "Home"}, &ParserOption{})
+	html, err := parser.Render(source)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `>Home</h1>`)
+	assert.Contains(t, html, `<!--[if IE]>IE<![endif]-->`)
+	assert.NotContains(t, html, "the hero")
+	assert.NotContains(t, html, "dark mode")
+}
+
+func TestResolveDirectives(t *testing.T) {
+	source := `<p>A</p><!--#dev <pre>debug</pre> -->` +
+		`<!--#if prod|staging--><script src="/analytics.js"></script><!--#else--><!--#if dev-->` +
+		`<script src="/reload.js"></script><!--#endif--><!--#endif--><!--#!prod <i>beta</i>-->`
+
+	assert.Equal(t, `<p>A</p><pre>debug</pre><script src="/reload.js"></script><i>beta</i>`, resolveDirectives(source, "development"))
+	assert.Equal(t, `<p>A</p><script src="/analytics.js"></script>`, resolveDirectives(source, "production"))
+	assert.Equal(t, `<p>A</p><script src="/analytics.js"></script><i>beta</i>`, resolveDirectives(source, "staging"))
+	assert.Equal(t, `<p>A</p><i>beta</i>`, resolveDirectives(source, "test"))
+
+	// The elif and the environment variables
+	t.Setenv("SUI_ANALYTICS", "true")
+	source = `<!--#if dev-->dev<!--#elif env:SUI_ANALYTICS-->analytics<!--#else-->none<!--#endif-->`
+	assert.Equal(t, `analytics`, resolveDirectives(source, "production"))
+	assert.Equal(t, `dev`, resolveDirectives(source, "development"))
+
+	// Not the directives
+	assert.Equal(t, `<!--#include virtual="/footer.html" --><!-- note -->`, resolveDirectives(`<!--#include virtual="/footer.html" --><!-- note -->`, "production"))
+
+	// The build-time directives of the template comments
+	CommentsEnv = "production"
+	defer func() { CommentsEnv = "" }()
+	assert.Equal(t, `<p>A</p>`, stripComments(`<p>A</p><!--#dev <pre>debug</pre> -->{# note #}`))
+}
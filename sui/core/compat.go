@@ -0,0 +1,223 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/blang/semver"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/application"
+	"github.com/yaoapp/yao/share"
+)
+
+// EngineVersion the version of the engine, recorded in the build manifest and checked against the engine constraint of the DSL
+var EngineVersion = share.VERSION
+
+// EngineFeatures the syntax features the built pages may rely on, the pages built with a feature the engine
+// does not have (e.g. built with a newer engine) are reported when the sui is loaded
+var EngineFeatures = []string{"delimiters", "shorthand", "typed-params"}
+
+// RemovedDirectives the directives removed from the engine, the pages built before the version are reported
+// when the sui is loaded, e.g. "s:old": {Since: "0.11.0", Message: "use s:new instead"}
+var RemovedDirectives = map[string]*Incompatibility{}
+
+// ChangedProcesses the processes of which the signatures are changed, the pages built before the version are
+// reported when the sui is loaded, e.g. "sui.foo": {Since: "0.11.0", Message: "the second argument is the option"}
+var ChangedProcesses = map[string]*Incompatibility{}
+
+// BuildManifestFile the build manifest in the public root
+const BuildManifestFile = ".build.json"
+
+// Incompatibility the breaking change of the engine
+type Incompatibility struct {
+	Since   string `json:"since"`   // the engine version of the change
+	Message string `json:"message"` // the actionable hint, e.g. use s:new instead
+}
+
+// BuildManifest the engine version and the features the pages were built against, written by the build
+type BuildManifest struct {
+	Engine     string                  `json:"engine"`
+	Features   []string                `json:"features"`   // the syntax features used by the pages, see EngineFeatures
+	Directives []string                `json:"directives"` // the directives in the built pages
+	Processes  []string                `json:"processes"`  // the processes called by the pages
+	Routes     []string                `json:"routes"`     // the routes of the built pages, see CheckLinks
+	Pages      map[string]*PageMetrics `json:"pages"`      // the metrics of the built pages, see CheckBudgets
+	Built      time.Time               `json:"built"`
+	features   map[string]bool
+	directives map[string]bool
+	processes  map[string]bool
+	routes     map[string]bool
+	links      []LinkRef
+}
+
+var compatProcessRe = regexp.MustCompile(`P_\(\s*['"]([^'"]+)['"]`)
+
+// compatDirectiveFamilies the directives of which the names are generated, e.g. s:on-click => s:on
+var compatDirectiveFamilies = []string{"s:on-", "s:attr-", "s:trans-attr-", "s:key-", "s:json-", "s:data-", "s:track-", "s:event-"}
+
+// NewBuildManifest create the build manifest of the current engine
+func NewBuildManifest() *BuildManifest {
+	return &BuildManifest{
+		Engine:     EngineVersion,
+		features:   map[string]bool{},
+		directives: map[string]bool{},
+		processes:  map[string]bool{},
+		routes:     map[string]bool{},
+		Pages:      map[string]*PageMetrics{},
+	}
+}
+
+// Record record the features, the directives, the processes and the links of the built page
+func (m *BuildManifest) Record(page *Page, doc *goquery.Document) {
+	if page.Config != nil && len(page.Config.Delimiters) > 0 {
+		m.features["delimiters"] = true
+	}
+	if strings.Contains(page.Route, "[") && strings.Contains(page.Route, ":") {
+		m.features["typed-params"] = true
+	}
+
+	for _, process := range DependencyProcesses(page.Codes.DATA.Code) {
+		m.processes[process] = true
+	}
+
+	for _, node := range doc.Find("*").Nodes {
+		for _, attr := range node.Attr {
+			if strings.HasPrefix(attr.Key, ":") {
+				m.features["shorthand"] = true
+				continue
+			}
+			if strings.HasPrefix(attr.Key, "s:") {
+				m.directives[compatDirective(attr.Key)] = true
+			}
+			if strings.Contains(attr.Val, "P_(") {
+				for _, match := range compatProcessRe.FindAllStringSubmatch(attr.Val, -1) {
+					m.processes[match[1]] = true
+				}
+			}
+		}
+	}
+
+	if text := doc.Text(); strings.Contains(text, "P_(") {
+		for _, match := range compatProcessRe.FindAllStringSubmatch(text, -1) {
+			m.processes[match[1]] = true
+		}
+	}
+
+	m.recordLinks(page, doc)
+}
+
+// Bytes the json of the build manifest
+func (m *BuildManifest) Bytes() ([]byte, error) {
+	m.Features = compatKeys(m.features)
+	m.Directives = compatKeys(m.directives)
+	m.Processes = compatKeys(m.processes)
+	m.Routes = compatKeys(m.routes)
+	m.Built = time.Now()
+	return jsoniter.MarshalIndent(m, "", "  ")
+}
+
+// ReadBuildManifest read the build manifest of the public root, nil if the public root has no manifest
+func ReadBuildManifest(root string) (*BuildManifest, error) {
+	file := ReleaseFile(filepath.Join("public", root, BuildManifestFile))
+	if exists, _ := application.App.Exists(file); !exists {
+		return nil, nil
+	}
+
+	data, err := application.App.Read(file)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &BuildManifest{}
+	if err := jsoniter.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("%s %s", file, err.Error())
+	}
+	return manifest, nil
+}
+
+// CheckCompatibility check the engine against the engine constraint of the DSL (e.g. ">=0.10.4 <0.11.0")
+// and the build manifest, all the incompatibilities are reported with the hints
+func CheckCompatibility(constraint string, manifest *BuildManifest) error {
+	messages := []string{}
+	engine, err := semver.ParseTolerant(EngineVersion)
+	if err != nil {
+		return fmt.Errorf("the engine version %s is invalid", EngineVersion)
+	}
+
+	if constraint != "" {
+		accept, err := semver.ParseRange(constraint)
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("the engine constraint %q is invalid, e.g. \">=0.10.4 <0.11.0\"", constraint))
+		} else if !accept(engine) {
+			messages = append(messages, fmt.Sprintf("the app requires the engine %s, the engine is %s. install the required version of yao", constraint, EngineVersion))
+		}
+	}
+
+	if manifest != nil {
+		supported := map[string]bool{}
+		for _, feature := range EngineFeatures {
+			supported[feature] = true
+		}
+		for _, feature := range manifest.Features {
+			if !supported[feature] {
+				messages = append(messages, fmt.Sprintf("the pages use the %s feature of the engine %s, the engine %s does not support it. upgrade yao or rebuild the template", feature, manifest.Engine, EngineVersion))
+			}
+		}
+
+		for _, directive := range manifest.Directives {
+			if change, has := RemovedDirectives[directive]; has && manifest.before(change.Since) {
+				messages = append(messages, fmt.Sprintf("the directive %s is removed since %s, %s. update the pages and rebuild the template", directive, change.Since, change.Message))
+			}
+		}
+
+		for _, process := range manifest.Processes {
+			if change, has := ChangedProcesses[strings.ToLower(process)]; has && manifest.before(change.Since) {
+				messages = append(messages, fmt.Sprintf("the process %s is changed since %s, %s. update the pages and rebuild the template", process, change.Since, change.Message))
+			}
+		}
+	}
+
+	if len(messages) > 0 {
+		return fmt.Errorf("incompatible with the engine %s:\n  %s", EngineVersion, strings.Join(messages, "\n  "))
+	}
+	return nil
+}
+
+// before check if the pages were built before the engine version, true if the version is unknown
+func (m *BuildManifest) before(version string) bool {
+	since, err := semver.ParseTolerant(version)
+	if err != nil {
+		return false
+	}
+	built, err := semver.ParseTolerant(m.Engine)
+	if err != nil {
+		return true
+	}
+	return built.LT(since)
+}
+
+func compatDirective(name string) string {
+	for _, family := range compatDirectiveFamilies {
+		if strings.HasPrefix(name, family) {
+			return strings.TrimSuffix(family, "-")
+		}
+	}
+	if i := strings.Index(name[2:], ":"); i >= 0 {
+		return name[:i+2] // s:bind:href => s:bind
+	}
+	return name
+}
+
+func compatKeys(set map[string]bool) []string {
+	keys := []string{}
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildManifestRecord(t *testing.T) {
+	doc, err := NewDocumentString(`<html><body><a :href="url" s:on-click="open" s:bind:title="{{ title }}">{{ P_('utils.str.Upper', name) }}</a>` +
+		`<ul><li s:for="items" s:for-item="item">{{ item }}</li></ul></body></html>`)
+	assert.Nil(t, err)
+
+	page := &Page{Route: "/users/[id:int]", Codes: SourceCodes{DATA: Source{Code: `{"$users": {"process": "models.user.Get"}}`}}}
+	page.Config = &PageConfig{PageSetting: PageSetting{Delimiters: []string{"[[", "]]"}}}
+
+	manifest := NewBuildManifest()
+	manifest.Record(page, doc)
+	_, err = manifest.Bytes()
+	assert.Nil(t, err)
+	assert.Equal(t, EngineVersion, manifest.Engine)
+	assert.Equal(t, []string{"delimiters", "shorthand", "typed-params"}, manifest.Features)
+	assert.Equal(t, []string{"s:bind", "s:for", "s:for-item", "s:on"}, manifest.Directives)
+	assert.Equal(t, []string{"models.user.Get", "utils.str.Upper"}, manifest.Processes)
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	version, removed, changed := EngineVersion, RemovedDirectives, ChangedProcesses
+	defer func() { EngineVersion, RemovedDirectives, ChangedProcesses = version, removed, changed }()
+	EngineVersion = "0.10.4"
+	RemovedDirectives = map[string]*Incompatibility{"s:old": {Since: "0.10.4", Message: "use s:new instead"}}
+	ChangedProcesses = map[string]*Incompatibility{"sui.foo": {Since: "0.10.4", Message: "the second argument is the option"}}
+
+	assert.Nil(t, CheckCompatibility("", nil))
+	assert.Nil(t, CheckCompatibility(">=0.10.0 <0.11.0", nil))
+
+	err := CheckCompatibility(">=0.11.0", nil)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "requires the engine >=0.11.0")
+	}
+	assert.Error(t, CheckCompatibility("not a range", nil))
+
+	// Built with the current engine
+	assert.Nil(t, CheckCompatibility("", &BuildManifest{Engine: "0.10.4", Features: []string{"shorthand"}, Directives: []string{"s:old"}, Processes: []string{"sui.foo"}}))
+
+	// Built with an older engine
+	err = CheckCompatibility("", &BuildManifest{Engine: "0.10.3", Directives: []string{"s:if", "s:old"}, Processes: []string{"Sui.Foo"}})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "the directive s:old is removed since 0.10.4, use s:new instead")
+		assert.Contains(t, err.Error(), "the process Sui.Foo is changed since 0.10.4")
+	}
+
+	// Built with a newer engine
+	err = CheckCompatibility("", &BuildManifest{Engine: "0.11.0", Features: []string{"shorthand", "islands"}})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "the islands feature of the engine 0.11.0")
+	}
+}
@@ -33,6 +33,11 @@ func (page *Page) Compile(ctx *BuildContext, option *BuildOption) (string, strin
 		}
 	}
 
+	// Record the features of the page in the build manifest
+	if ctx != nil && ctx.global != nil && ctx.global.manifest != nil {
+		ctx.global.manifest.Record(page, doc)
+	}
+
 	body := doc.Find("body")
 	head := doc.Find("head")
 
@@ -100,6 +105,11 @@ func (page *Page) Compile(ctx *BuildContext, option *BuildOption) (string, strin
 		return "", "", warnings, fmt.Errorf("Generate html error: %s", err.Error())
 	}
 
+	// Record the metrics of the page for the performance budgets
+	if ctx != nil && ctx.global != nil && ctx.global.manifest != nil {
+		ctx.global.manifest.recordMetrics(page, html, doc)
+	}
+
 	// @todo: Minify the html
 	return html, config, warnings, nil
 }
@@ -0,0 +1,181 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// concurrentJob the sibling just-in-time component rendered in parallel
+type concurrentJob struct {
+	sel    *goquery.Selection
+	comp   *JitComponent
+	comsel *goquery.Selection
+	parser *TemplateParser
+	errors int // the errors before the component
+	tracks int // the analytics events before the component
+}
+
+// renderConcurrentComponents render the independent sibling just-in-time components of the node in parallel,
+// the max goroutines is ParserOption.Concurrency. The components are prepared in order (the props, the slots),
+// rendered by the isolated parsers, and reassembled in order by the replace map.
+func (parser *TemplateParser) renderConcurrentComponents(node *html.Node) {
+	if parser.option.Concurrency < 2 || parser.option.Restricted != nil || parser.option.Editor {
+		return
+	}
+
+	jobs := []*concurrentJob{}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+
+		// The variables set by the siblings are read by the following components, keep the order
+		if child.Data == "s:set" || child.Data == "set" || hasAttr(child, "s:set") {
+			return
+		}
+
+		if concurrentComponent(child) {
+			jobs = append(jobs, &concurrentJob{sel: goquery.NewDocumentFromNode(child).Selection})
+		}
+	}
+	if len(jobs) < 2 {
+		return
+	}
+
+	defer parser.option.Timing.Start("components", "Components")()
+
+	// Prepare the components in order
+	for _, job := range jobs {
+		parser.parsed(job.sel)
+		if !parser.limitDepth(job.sel, "is") {
+			continue
+		}
+		comp, err := parser.getJitComponent(job.sel)
+		if err != nil {
+			parser.componentError(job.sel, "is", job.sel.AttrOr("is", ""), err)
+			continue
+		}
+
+		comsel, err := parser.newJitComponentSel(job.sel, comp)
+		if err != nil {
+			parser.componentError(job.sel, "is", job.sel.AttrOr("is", ""), err)
+			continue
+		}
+
+		job.comp, job.comsel = comp, comsel
+		job.errors, job.tracks = len(parser.errors), len(parser.tracks)
+		job.parser = parser.isolate(job.sel.Nodes[0])
+		parser.sequence++ // the namespace of the next component, the same as parseJitComponent
+	}
+
+	// Render the components by the bounded workers
+	var wg sync.WaitGroup
+	workers := make(chan struct{}, parser.option.Concurrency)
+	for _, job := range jobs {
+		if job.comsel == nil {
+			continue
+		}
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(job *concurrentJob) {
+			defer func() {
+				if r := recover(); r != nil {
+					job.parser.componentError(job.comsel, "is", job.comp.route, fmt.Errorf("%v", r))
+				}
+				<-workers
+				wg.Done()
+			}()
+			job.parser.parseElementComponent(job.comsel)
+		}(job)
+	}
+	wg.Wait()
+
+	// Reassemble the components in order
+	for _, job := range jobs {
+		if job.comsel == nil {
+			continue
+		}
+
+		parser.addReplace(job.sel, job.comsel.Nodes)
+		parser.errors = append(parser.errors, job.parser.errors[job.errors:]...)
+		parser.tracks = append(parser.tracks, job.parser.tracks[job.tracks:]...)
+		parser.scheduleAt(job.parser.schedule)
+		parser.geo = parser.geo || job.parser.geo
+		if parser.assertion == nil {
+			parser.assertion = job.parser.assertion
+		}
+		if parser.mapping != nil {
+			for key, mapping := range job.parser.mapping {
+				parser.mapping[key] = mapping
+			}
+		}
+		if parser.hydrate != nil {
+			for path := range job.parser.hydrate {
+				parser.hydrate[path] = true
+			}
+		}
+		if parser.bindings != nil {
+			for key, binding := range job.parser.bindings {
+				parser.bindings[key] = binding
+			}
+		}
+		if parser.onces != nil {
+			for key, nodes := range job.parser.onces {
+				parser.onces[key] = nodes
+			}
+		}
+		if job.parser.context != nil {
+			parser.addContextAssets(job.parser.context.scripts, job.parser.context.styles)
+		}
+		parser.addContextAssets(job.comp.scripts, job.comp.styles)
+	}
+}
+
+// concurrentComponent check if the node is the just-in-time component without the directives,
+// the directives (s:if, s:for, s:cache ...) are evaluated in order
+func concurrentComponent(node *html.Node) bool {
+	is := false
+	for _, attr := range node.Attr {
+		switch {
+		case attr.Key == "is":
+			is = attr.Val != ""
+		case attr.Key == "s:jit":
+		case attr.Key == "parsed", strings.HasPrefix(attr.Key, "s:"):
+			return false
+		}
+	}
+	return is && hasAttr(node, "s:jit")
+}
+
+// isolate get the parser to render the component of the node in the goroutine, the data, the option and
+// the collected states are copied, the shared slices are clipped. the rendered elements are counted by the parser
+func (parser *TemplateParser) isolate(node *html.Node) *TemplateParser {
+	var new = *parser
+	new.data = parser.data.Overlay()
+
+	option := *parser.option
+	new.option = &option
+	new.sequence, new.keyScope = parser.componentScope(node)
+	new.context = nil
+	new.scopes = nil
+	new.fragments = nil
+	new.replace = []replacement{}
+	new.mapping = map[string]Mapping{}
+	if parser.hydrate != nil {
+		new.hydrate = map[string]bool{}
+	}
+	if parser.bindings != nil {
+		new.bindings = map[string]Binding{}
+	}
+	new.onces = map[string][]*html.Node{}
+	new.errors = parser.errors[:len(parser.errors):len(parser.errors)]
+	new.scripts = parser.scripts[:len(parser.scripts):len(parser.scripts)]
+	new.styles = parser.styles[:len(parser.styles):len(parser.styles)]
+	new.tracks = parser.tracks[:len(parser.tracks):len(parser.tracks)]
+	new.includes = parser.includes[:len(parser.includes):len(parser.includes)]
+	return &new
+}
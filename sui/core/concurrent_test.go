@@ -0,0 +1,113 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserConcurrentComponents(t *testing.T) {
+	Components["/test/card"] = &JitComponent{
+		route:       "/test/card",
+		html:        `<div class="card"><p s:for="[1,2]">{{ item }}</p><children></children></div>`,
+		buildOption: &BuildOption{},
+		scripts:     []ScriptNode{{Source: "function comp__test_card(){}", Component: "comp__test_card"}},
+	}
+	defer delete(Components, "/test/card")
+
+	cards := ""
+	for i := 0; i < 8; i++ {
+		cards += `<div is="/test/card" s:jit>Card` + string(rune('A'+i)) + `</div>`
+	}
+	source := `<html><head></head><body><main>` + cards + `<span s:for="[1]">{{ item }}</span></main></body></html>`
+
+	render := func() (string, *TemplateParser) {
+		parser := NewTemplateParser(Data{}, &ParserOption{Concurrency: 3, Request: &Request{}})
+		html, err := parser.Render(source)
+		assert.Nil(t, err)
+		assert.Empty(t, parser.Errors())
+		return html, parser
+	}
+
+	html, parser := render()
+	doc, err := NewDocumentString(html)
+	assert.Nil(t, err)
+
+	// The components are reassembled in order
+	texts := []string{}
+	doc.Find("main > .card").Each(func(i int, sel *goquery.Selection) {
+		texts = append(texts, sel.Text())
+		assert.Equal(t, Namespace("/test/card", i+1, false), sel.AttrOr("s:ns", ""))
+	})
+	assert.Equal(t, []string{"12CardA", "12CardB", "12CardC", "12CardD", "12CardE", "12CardF", "12CardG", "12CardH"}, texts)
+	assert.Equal(t, "1", doc.Find("main > span").Text())
+	assert.Len(t, parser.context.scripts, 1)
+
+	// The output is the same between the renderings
+	for i := 0; i < 5; i++ {
+		again, _ := render()
+		assert.Equal(t, sortAttrs(html), sortAttrs(again))
+	}
+
+	// The directives are evaluated in order
+	doc, _ = NewDocumentString(`<div><div is="/test/card" s:jit s:if="true"></div><div is="/test/card" s:jit></div></div>`)
+	assert.False(t, concurrentComponent(doc.Find("[s\\:if]").Nodes[0]))
+	assert.True(t, concurrentComponent(doc.Find("[is]").Last().Nodes[0]))
+}
+
+func TestParserConcurrentLimits(t *testing.T) {
+	Components["/test/list"] = &JitComponent{
+		route:       "/test/list",
+		html:        `<ul class="list"><li s:for="[1,2,3,4,5,6,7,8,9,10]">{{ item }}</li></ul>`,
+		buildOption: &BuildOption{},
+	}
+	defer delete(Components, "/test/list")
+
+	lists := ""
+	for i := 0; i < 6; i++ {
+		lists += `<div is="/test/list" s:jit></div>`
+	}
+	source := `<html><head></head><body><main>` + lists + `</main></body></html>`
+
+	render := func(option *ParserOption) (string, *TemplateParser) {
+		parser := NewTemplateParser(Data{}, option)
+		html, err := parser.Render(source)
+		assert.Nil(t, err)
+		return html, parser
+	}
+
+	// The rendered elements are counted by all the workers
+	for _, concurrency := range []int{0, 3} {
+		html, parser := render(&ParserOption{Concurrency: concurrency, Request: &Request{}, Limits: &RenderLimits{MaxNodes: 30}})
+		doc, err := NewDocumentString(html)
+		assert.Nil(t, err)
+		assert.LessOrEqual(t, doc.Find("main li").Length(), 30)
+		assert.Len(t, parser.Errors(), 1)
+	}
+
+	// The keys are the same whether the components are rendered in parallel or in order
+	for _, stable := range []bool{false, true} {
+		expected, ordered := render(&ParserOption{StableKeys: stable, Bindings: true, Request: &Request{}})
+		html, parallel := render(&ParserOption{StableKeys: stable, Bindings: true, Concurrency: 3, Request: &Request{}})
+		assert.Len(t, parallel.Bindings().Bindings, 60)
+		assert.Equal(t, ordered.Bindings().Bindings, parallel.Bindings().Bindings)
+		assert.Equal(t, sortAttrs(expected), sortAttrs(html))
+	}
+}
+
+// sortAttrs sort the attributes of the tags, the props of the components are set from the map
+func sortAttrs(html string) string {
+	doc, _ := NewDocumentString(html)
+	doc.Find("*").Each(func(i int, sel *goquery.Selection) {
+		attrs := sel.Nodes[0].Attr
+		for i := 1; i < len(attrs); i++ {
+			for j := i; j > 0 && attrs[j].Key < attrs[j-1].Key; j-- {
+				attrs[j], attrs[j-1] = attrs[j-1], attrs[j]
+			}
+		}
+	})
+	out, _ := doc.Html()
+	return strings.TrimSpace(out)
+}
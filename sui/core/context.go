@@ -29,9 +29,15 @@ func NewTranslateContext() *TranslateContext {
 func NewGlobalBuildContext() *GlobalBuildContext {
 	return &GlobalBuildContext{
 		jitComponents: map[string]bool{},
+		manifest:      NewBuildManifest(),
 	}
 }
 
+// Manifest get the build manifest of the built pages
+func (ctx *GlobalBuildContext) Manifest() *BuildManifest {
+	return ctx.manifest
+}
+
 // GetJitComponents get the just in time components
 func (ctx *BuildContext) GetJitComponents() []string {
 	if ctx.jitComponents == nil {
@@ -89,5 +95,5 @@ func (ctx *BuildContext) addJitComponent(name string) {
 func (ctx *BuildContext) isJitComponent(name string) bool {
 	hasStmt := dataTokens.MatchString(name)
 	hasProp := propTokens.MatchString(name)
-	return hasStmt || hasProp
+	return hasStmt || hasProp || componentRegistry(name) != nil
 }
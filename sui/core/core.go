@@ -1,6 +1,9 @@
 package core
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/yaoapp/gou/application"
 )
 
@@ -18,5 +21,102 @@ func Load(file string, id string) (*DSL, error) {
 		return nil, err
 	}
 
+	// The restricted profiles are imposed by the host, the page config can not change them
+	for prefix, profile := range dsl.Restricted {
+		RegisterRestrictedRoute(prefix, profile)
+	}
+
+	// The URL normalization and the redirects of the pages under the public root
+	if dsl.Routing != nil {
+		if dsl.Routing.Redirects != "" {
+			data, err := application.App.Read(dsl.Routing.Redirects)
+			if err != nil {
+				return nil, err
+			}
+			dsl.Routing.Rules, err = ParseRedirects(data)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s", dsl.Routing.Redirects, err.Error())
+			}
+		}
+
+		RegisterRoutingPolicy(dsl.routePrefix(), dsl.Routing)
+	}
+
+	// The locale prefixes of the routes
+	if dsl.I18n != nil {
+		RegisterLocaleRouting(dsl.routePrefix(), dsl.I18n)
+	}
+
+	// The visibility rules of the page data sent to the browser
+	if dsl.Visibility != nil {
+		RegisterVisibility(dsl.routePrefix(), dsl.Visibility)
+	}
+
+	// The admin widgets can be embedded in the pages
+	if dsl.Widgets != nil {
+		RegisterWidgets(dsl.routePrefix(), dsl.Widgets)
+	}
+
+	// The typed settings of the app
+	if err := RegisterSettings(dsl.Settings); err != nil {
+		return nil, fmt.Errorf("%s %s", file, err.Error())
+	}
+
+	// The per-user preferences
+	if err := RegisterPreferences(dsl.Preferences); err != nil {
+		return nil, fmt.Errorf("%s %s", file, err.Error())
+	}
+
+	// The menus of the pages
+	if dsl.Menus != nil {
+		RegisterMenus(dsl.routePrefix(), dsl.Menus)
+	}
+
+	// The CSRF protection of the POST page actions
+	if dsl.CSRF != nil {
+		RegisterCSRF(dsl.routePrefix(), dsl.CSRF)
+	}
+
+	// The registries of the shared components
+	for prefix, option := range dsl.Registries {
+		registry, err := NewHTTPRegistry(option)
+		if err != nil {
+			return nil, fmt.Errorf("%s registries %s %s", file, prefix, err.Error())
+		}
+		RegisterComponentRegistry(prefix, registry)
+	}
+
+	// The expression functions proxy to the processes
+	for name, fn := range dsl.Functions {
+		if err := RegisterProcessFunction(name, fn); err != nil {
+			return nil, fmt.Errorf("%s %s", file, err.Error())
+		}
+	}
+
+	// The active release of the public root, the dynamic public roots have no releases
+	var manifest *BuildManifest = nil
+	if dsl.Public == nil || !strings.ContainsAny(dsl.Public.Root, "{$") {
+		if err := LoadRelease(id, dsl.routePrefix()); err != nil {
+			return nil, err
+		}
+		manifest, err = ReadBuildManifest(dsl.routePrefix())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The engine version and the features the pages were built against
+	if err := CheckCompatibility(dsl.Engine, manifest); err != nil {
+		return nil, fmt.Errorf("%s %s", file, err.Error())
+	}
+
 	return &dsl, nil
 }
+
+// routePrefix the route prefix of the pages, the public root if it is static
+func (dsl *DSL) routePrefix() string {
+	if dsl.Public != nil && dsl.Public.Root != "" && !strings.ContainsAny(dsl.Public.Root, "{$") {
+		return dsl.Public.Root
+	}
+	return "/"
+}
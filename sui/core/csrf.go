@@ -0,0 +1,157 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	xhtml "golang.org/x/net/html"
+)
+
+// The CSRF token of the forms and the backend calls
+const (
+	CSRFField  = "__sui_csrf" // the hidden field of the forms, see <s:csrf/>
+	CSRFHeader = "X-Sui-Csrf" // the header of the backend calls, set by the client runtime
+	CSRFCookie = "__sui_csrf" // the cookie holds the seed of the token, set by the page rendering
+	csrfKey    = "$csrf"      // the data key of the token, {{ $csrf }}
+)
+
+// CSRF the CSRF protection of the POST page actions (the form submissions and the backend calls), set in the csrf
+// section of the sui DSL. the protection is opt-in, the routes without the csrf section are not checked, the token
+// is signed from the seed in the cookie
+//
+//	"csrf": {}
+//	"csrf": {"except": ["Webhook"]}
+//	"csrf": {"disable": true}
+//
+// migration: the protection was enabled by default, add "csrf": {} to the sui DSL to keep the POST page actions and
+// the backend calls checked, the forms should render the token by <s:csrf/>, and the custom clients send {{ $csrf }}
+// in the X-Sui-Csrf header
+type CSRF struct {
+	Disable bool     `json:"disable,omitempty"`
+	Except  []string `json:"except,omitempty"` // the backend methods not checked, e.g. the webhooks called by the other sites
+}
+
+var csrfs = map[string]*CSRF{}
+var csrfMutex sync.RWMutex
+
+// RegisterCSRF set the CSRF protection of the route prefix, e.g. the public root of the sui
+func RegisterCSRF(prefix string, csrf *CSRF) {
+	csrfMutex.Lock()
+	defer csrfMutex.Unlock()
+	prefix = "/" + strings.Trim(prefix, "/")
+	if csrf == nil {
+		delete(csrfs, prefix)
+		return
+	}
+	csrfs[prefix] = csrf
+}
+
+// GetCSRF get the CSRF protection of the path, the longest prefix wins, nil if not set (not checked)
+func GetCSRF(route string) *CSRF {
+	csrfMutex.RLock()
+	defer csrfMutex.RUnlock()
+	var res *CSRF = nil
+	matched := -1
+	for prefix, csrf := range csrfs {
+		if len(prefix) <= matched {
+			continue
+		}
+		if prefix == "/" || route == prefix || strings.HasPrefix(route, prefix+"/") {
+			res, matched = csrf, len(prefix)
+		}
+	}
+	return res
+}
+
+// Check check if the backend method (empty for the form submissions) should have the token
+func (csrf *CSRF) Check(method string) bool {
+	if csrf == nil {
+		return false
+	}
+	if csrf.Disable {
+		return false
+	}
+	for _, except := range csrf.Except {
+		if method != "" && strings.EqualFold(except, method) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewCSRFSeed create the seed of the CSRF token, the seed is kept in the cookie of the visitor
+func NewCSRFSeed() string {
+	seed := make([]byte, 16)
+	rand.Read(seed)
+	return hex.EncodeToString(seed)
+}
+
+// CSRFToken get the CSRF token of the request, empty if the request has no seed
+func (r *Request) CSRFToken() string {
+	if r == nil || r.CSRF == "" {
+		return ""
+	}
+	return formSign("csrf:" + r.CSRF) // the form tokens are base64 payloads, no colon
+}
+
+// VerifyCSRF verify the token of the header or the CSRFField of the submitted values
+func (r *Request) VerifyCSRF(values map[string]interface{}) error {
+	token := ""
+	if r.Headers != nil {
+		token = r.Headers.Get(CSRFHeader)
+	}
+	if token == "" && values != nil {
+		token, _ = values[CSRFField].(string)
+	}
+
+	expected := r.CSRFToken()
+	if token == "" || expected == "" || !hmac.Equal([]byte(token), []byte(expected)) {
+		return fmt.Errorf("the CSRF token is invalid, please reload the page")
+	}
+	return nil
+}
+
+// csrfNode render the <s:csrf/> element as the hidden field of the CSRF token
+//
+//	<form method="post"><s:csrf/>...</form> => <input type="hidden" name="__sui_csrf" value="...">
+func (parser *TemplateParser) csrfNode(sel *goquery.Selection) {
+	parser.parsed(sel)
+	parser.hide(sel)
+	node := sel.Nodes[0]
+	token := parser.option.Request.CSRFToken()
+	if token == "" {
+		parser.renderError(node, "s:csrf", "", fmt.Errorf("the CSRF token is not available, the page should be rendered by the sui"))
+		return
+	}
+
+	source := fmt.Sprintf(`<input type="hidden" name="%s" value="%s"/>`, CSRFField, html.EscapeString(token))
+	nodes, err := xhtml.ParseFragment(strings.NewReader(source), fragmentContext(node.Parent))
+	if err != nil {
+		parser.renderError(node, "s:csrf", "", err)
+		return
+	}
+	parser.addReplace(sel, nodes)
+}
+
+// isCSRF check if the node is the <s:csrf/> element
+func (parser *TemplateParser) isCSRF(sel *goquery.Selection) bool {
+	return len(sel.Nodes) > 0 && sel.Nodes[0].Data == "s:csrf"
+}
+
+// reSelfClosing the self-closing elements of the sui, the html parser does not close the custom elements by the slash
+var reSelfClosing = regexp.MustCompile(`<(s:csrf)((?:\s[^<>]*?)?)\s*/>`)
+
+// closeElements close the self-closing elements of the sui, e.g. <s:csrf/> => <s:csrf></s:csrf>
+func closeElements(source string) string {
+	if !strings.Contains(source, "<s:csrf") {
+		return source
+	}
+	return reSelfClosing.ReplaceAllString(source, "<$1$2></$1>")
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSRFCheck(t *testing.T) {
+	RegisterCSRF("/__csrf", &CSRF{Except: []string{"Webhook"}})
+	RegisterCSRF("/__csrf/open", &CSRF{Disable: true})
+	defer RegisterCSRF("/__csrf", nil)
+	defer RegisterCSRF("/__csrf/open", nil)
+
+	assert.False(t, GetCSRF("/__other").Check(""))
+	assert.False(t, GetCSRF("/__other").Check("Save"))
+	assert.True(t, GetCSRF("/__csrf/page").Check("Save"))
+	assert.False(t, GetCSRF("/__csrf/page").Check("webhook"))
+	assert.True(t, GetCSRF("/__csrf/page").Check(""))
+	assert.False(t, GetCSRF("/__csrf/open/page").Check("Save"))
+}
+
+func TestVerifyCSRF(t *testing.T) {
+	r := &Request{CSRF: NewCSRFSeed()}
+	token := r.CSRFToken()
+	assert.NotEmpty(t, token)
+	assert.Nil(t, r.VerifyCSRF(map[string]interface{}{CSRFField: token}))
+
+	r.Headers = url.Values{CSRFHeader: {token}}
+	assert.Nil(t, r.VerifyCSRF(nil))
+
+	// The token of the other visitor
+	other := &Request{CSRF: NewCSRFSeed(), Headers: url.Values{CSRFHeader: {token}}}
+	assert.NotNil(t, other.VerifyCSRF(nil))
+	assert.NotNil(t, (&Request{}).VerifyCSRF(map[string]interface{}{CSRFField: ""}))
+}
+
+func TestParserCSRF(t *testing.T) {
+	r := &Request{CSRF: NewCSRFSeed()}
+	token := r.CSRFToken()
+	parser := NewTemplateParser(Data{}, &ParserOption{Request: r})
+	html, err := parser.Render(`<html><body><form method="post"><s:csrf/><input name="email"/></form>` +
+		`<p>{{ $csrf }}</p></body></html>`)
+	assert.Nil(t, err)
+	assert.Empty(t, parser.Errors())
+	assert.Contains(t, html, `<form method="post"><input type="hidden" name="__sui_csrf" value="`+token+`"/><input name="email"/></form>`)
+	assert.Contains(t, html, `<p>`+token+`</p>`)
+
+	// The page is not rendered by the sui
+	parser = NewTemplateParser(Data{}, &ParserOption{Request: &Request{}})
+	_, err = parser.Render(`<html><body><form><s:csrf></s:csrf></form></body></html>`)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, parser.Errors())
+}
@@ -5,12 +5,12 @@ import (
 	"hash/fnv"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/expr-lang/expr"
 	"github.com/expr-lang/expr/ast"
 	"github.com/expr-lang/expr/vm"
-	jsoniter "github.com/json-iterator/go"
 	"github.com/yaoapp/gou/process"
 	"golang.org/x/net/html"
 )
@@ -45,12 +45,45 @@ type StringValue struct {
 	Error       error
 }
 
+// options the options of the expressions
+// The optional chaining and the nil coalescing are supported, e.g. {{ user?.profile?.name ?? 'Guest' }}, the member
+// accesses are nil-safe as well, see nilSafeMembers
 var options = []expr.Option{
 	expr.Function("P_", _process),
 	expr.Function("True", _true),
 	expr.Function("False", _false),
 	expr.Function("Empty", _empty),
+	expr.Function("Truncate", _truncate),
+	expr.Function("TruncateWords", _truncateWords),
+	expr.Function("Ellipsis", _ellipsis),
+	expr.Function("Decimal", _decimal, new(func(any) Decimal), new(func(any, string) Decimal)),
+	expr.Function("LocaleURL", _localeURL),
+	expr.Function("__filter", _filter),
+	expr.Function("__filter_locale", _filterLocale),
+	expr.Function("__process", _processFunction),
+	expr.Function("__menu", _menu),
 	expr.AllowUndefinedVariables(),
+	expr.Patch(nilSafeMembers{}),
+}
+
+// nilSafeMembers the patch of the member accesses, the missing keys and the nil values in the middle of the path are
+// read as nil instead of the errors, e.g. {{ user.profile.name ?? 'Guest' }} is {{ user?.profile?.name ?? 'Guest' }}.
+// the methods and the out of range indexes are not changed
+type nilSafeMembers struct{}
+
+// Visit visit the node
+func (nilSafeMembers) Visit(node *ast.Node) {
+	member, ok := (*node).(*ast.MemberNode)
+	if !ok || member.Method {
+		return
+	}
+
+	// The chain of the base is merged, the whole path is nil if any member of it is nil
+	if chain, ok := member.Node.(*ast.ChainNode); ok {
+		member.Node = chain.Node
+	}
+	member.Optional = true
+	ast.Patch(node, &ast.ChainNode{Node: member})
 }
 
 // Visit visit the node
@@ -78,6 +111,29 @@ func (data Data) Hash() string {
 	return fmt.Sprintf("%x", h.Sum64())
 }
 
+// Overlay get the shallow copy of the top level keys of the data, the layers are merged on top in order.
+// The renderings sharing the data (e.g. the cached page data) write the variables (s:set, the loop items, the
+// component data, the locale) to their own copies, so the top level keys of the shared data are not changed.
+// It is not copy-on-write: the nested maps and slices are shared with the data as they are, the processes
+// or the backend scripts changing a nested value change it for all the renderings
+func (data Data) Overlay(layers ...Data) Data {
+	size := len(data)
+	for _, layer := range layers {
+		size += len(layer)
+	}
+
+	overlay := make(Data, size)
+	for k, v := range data {
+		overlay[k] = v
+	}
+	for _, layer := range layers {
+		for k, v := range layer {
+			overlay[k] = v
+		}
+	}
+	return overlay
+}
+
 // New create a new expression
 func (data Data) New(stmt string) (*vm.Program, error) {
 
@@ -101,21 +157,43 @@ func (data Data) New(stmt string) (*vm.Program, error) {
 	// &#39; => ' &#34; => "
 	stmt = strings.ReplaceAll(stmt, "&#39;", "'")
 	stmt = strings.ReplaceAll(stmt, "&#34;", "\"")
+
+	// The filters, e.g. title | upper | truncate:40
+	stmt = filterPipes(stmt)
+
+	// The process functions, e.g. Price(item.price), process("utils.price.Format", item.price)
+	stmt = processFunctionCalls(stmt)
+
+	// The menus, e.g. $menu("main")
+	stmt = menuCalls(stmt)
 	return expr.Compile(stmt, append([]expr.Option{expr.Env(data)}, options...)...)
 }
 
 // Exec exec statement for the template
 func (data Data) Exec(stmt string) (interface{}, []Identifier, error) {
+	return data.ExecGuard(stmt, nil)
+}
+
+// ExecGuard exec statement with the access control of the data paths
+func (data Data) ExecGuard(stmt string, guard *DataGuard) (interface{}, []Identifier, error) {
+	if guard != nil && guard.observe != nil {
+		defer guard.observe(stmt, time.Now())
+	}
+
 	program, err := data.New(stmt)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	node := program.Node()
+	if err := guard.Check(node); err != nil {
+		return nil, nil, err
+	}
+
 	v := &Visitor{}
 	ast.Walk(&node, v)
 
-	res, err := expr.Run(program, data)
+	res, err := expr.Run(program, guard.Strip(data))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -137,9 +215,14 @@ func (data Data) Identifiers(stmt string) ([]Identifier, error) {
 
 // ExecString exec statement for the template
 func (data Data) ExecString(stmt string) StringValue {
+	return data.ExecStringGuard(stmt, nil)
+}
+
+// ExecStringGuard exec statement for the template with the access control of the data paths
+func (data Data) ExecStringGuard(stmt string, guard *DataGuard) StringValue {
 
 	str := StringValue{Stmt: stmt, Value: "", JSON: false, Identifiers: []Identifier{}, Error: nil}
-	res, identifiers, err := data.Exec(stmt)
+	res, identifiers, err := data.ExecGuard(stmt, guard)
 	if err != nil {
 		str.Error = err
 		return str
@@ -160,8 +243,11 @@ func (data Data) ExecString(stmt string) StringValue {
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool:
 		str.Value = fmt.Sprintf("%v", v)
 		break
+	case Decimal:
+		str.Value = v.String()
+		break
 	default:
-		res, err := jsoniter.MarshalToString(res)
+		res, err := jsonStable.MarshalToString(res)
 		if err != nil {
 			str.Error = err
 			break
@@ -179,11 +265,21 @@ func (data Data) Replace(value string) (string, []StringValue) {
 	return data.ReplaceUse(dataTokens, value)
 }
 
+// ReplaceGuard replace the statement with the access control of the data paths
+func (data Data) ReplaceGuard(value string, guard *DataGuard) (string, []StringValue) {
+	return data.ReplaceUseGuard(dataTokens, value, guard)
+}
+
 // ReplaceUse replace the statement use the regexp
 func (data Data) ReplaceUse(tokens Tokens, value string) (string, []StringValue) {
+	return data.ReplaceUseGuard(tokens, value, nil)
+}
+
+// ReplaceUseGuard replace the statement use the tokens with the access control of the data paths
+func (data Data) ReplaceUseGuard(tokens Tokens, value string, guard *DataGuard) (string, []StringValue) {
 	values := []StringValue{}
 	res := tokens.ReplaceAllStringFunc(value, func(stmt string) string {
-		v := data.ExecString(stmt)
+		v := data.ExecStringGuard(stmt, guard)
 		values = append(values, v)
 		return v.Value
 	})
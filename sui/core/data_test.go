@@ -0,0 +1,46 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataMissingKeys(t *testing.T) {
+	data := Data{
+		"user":     map[string]interface{}{"name": "Yao"},
+		"items":    []interface{}{map[string]interface{}{"name": "Apple"}},
+		"$session": map[string]interface{}{"token": "secret"},
+	}
+
+	// The missing keys in the middle of the path are read as nil
+	for _, stmt := range []string{`user.profile.name`, `user['profile'].name`, `missing.name`, `user?.profile.name`} {
+		res, _, err := data.Exec(stmt)
+		assert.Nil(t, err, stmt)
+		assert.Nil(t, res, stmt)
+	}
+
+	res, _, err := data.Exec(`user.profile.name ?? 'Guest'`)
+	assert.Nil(t, err)
+	assert.Equal(t, "Guest", res)
+
+	res, _, err = data.Exec(`items[0].name`)
+	assert.Nil(t, err)
+	assert.Equal(t, "Apple", res)
+
+	value, values := data.Replace(`{{ user.profile.name ?? 'Guest' }} {{ user.name ?? 'Guest' }} {{ missing.name }}!`)
+	assert.Equal(t, "Guest Yao !", value)
+	for _, v := range values {
+		assert.Nil(t, v.Error)
+	}
+
+	// The out of range indexes are still errors
+	_, _, err = data.Exec(`items[3].name`)
+	assert.NotNil(t, err)
+
+	// The denied paths are checked through the nil-safe members
+	for _, stmt := range []string{`$session.token ?? ''`, `$session?.token`, `$session['token'] ?? ''`} {
+		_, _, err = data.ExecGuard(stmt, NewDataGuard("$session.token"))
+		assert.NotNil(t, err, stmt)
+	}
+}
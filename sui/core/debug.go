@@ -0,0 +1,34 @@
+package core
+
+import (
+	"os"
+
+	"github.com/yaoapp/yao/config"
+)
+
+// DebugOverlayEnabled show the debug overlay in the production mode (YAO_SUI_DEBUG_OVERLAY=true)
+var DebugOverlayEnabled = os.Getenv("YAO_SUI_DEBUG_OVERLAY") == "true"
+
+// debugInjection the overlay of the debug mode, shows the render errors, the timing of the directives and the data snapshot
+// the overlay is shown in the development mode or if it is enabled by the config, the ?__debug query can not enable it
+func (parser *TemplateParser) debugInjection() string {
+	if !parser.debug() || parser.option.Editor {
+		return ""
+	}
+	if !DebugOverlayEnabled && config.Conf.Mode != "development" {
+		return ""
+	}
+
+	raw, err := jsonStable.MarshalToString(map[string]interface{}{
+		"route":      parser.option.Route,
+		"errors":     parser.Errors(),
+		"audits":     parser.Audits(),
+		"violations": parser.Violations(),
+		"timing":     parser.option.Timing.Metrics(),
+		"data":       parser.clientData(),
+	})
+	if err != nil {
+		raw, _ = jsonStable.MarshalToString(map[string]interface{}{"errors": []RenderError{{Message: err.Error()}}})
+	}
+	return debugInjectionScript(raw)
+}
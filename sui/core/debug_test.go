@@ -0,0 +1,53 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/yao/config"
+)
+
+func TestDebugInjection(t *testing.T) {
+	mode, enabled := config.Conf.Mode, DebugOverlayEnabled
+	defer func() { config.Conf.Mode, DebugOverlayEnabled = mode, enabled }()
+
+	data := Data{"name": "Yao", "$session": map[string]interface{}{"token": "secret"}}
+	render := func() *TemplateParser {
+		parser := NewTemplateParser(data, &ParserOption{Debug: true, Route: "/index", Deny: []string{"$session.token"}, Timing: NewServerTiming()})
+		parser.option.Timing.Start("show", "s:show")()
+		parser.option.Timing.Add("text", "Text bindings", time.Millisecond)
+		return parser
+	}
+
+	// The ?__debug query can not enable the overlay in the production mode
+	config.Conf.Mode = "production"
+	DebugOverlayEnabled = false
+	assert.Equal(t, "", render().debugInjection())
+
+	// The development mode
+	config.Conf.Mode = "development"
+	overlay := render().debugInjection()
+	assert.Contains(t, overlay, `"route":"/index"`)
+	assert.Contains(t, overlay, `"name":"show"`)
+	assert.Contains(t, overlay, `"name":"text"`)
+	assert.Contains(t, overlay, "Yao")
+	assert.NotContains(t, overlay, "secret")
+
+	// The timing of the directives
+	parser := NewTemplateParser(data, &ParserOption{Debug: true, Request: &Request{}})
+	html, err := parser.Render(`<html><body><p s:show="name">{{ name }}</p><s:qrcode value="{{ name }}"></s:qrcode></body></html>`)
+	assert.Nil(t, err)
+	for _, name := range []string{"show", "code", "text", "attrs"} {
+		assert.Contains(t, html, `"name":"`+name+`"`)
+	}
+
+	// Enabled by the config
+	config.Conf.Mode = "production"
+	DebugOverlayEnabled = true
+	assert.NotEqual(t, "", render().debugInjection())
+
+	// Not in the debug mode
+	parser = NewTemplateParser(data, &ParserOption{})
+	assert.Equal(t, "", parser.debugInjection())
+}
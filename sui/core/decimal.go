@@ -0,0 +1,321 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// MaxDecimalExponent the max exponent of the decimal strings, e.g. "1e64"
+var MaxDecimalExponent = 64
+
+// MaxDecimalPlaces the max places of the rounding and the formatting
+var MaxDecimalPlaces = 32
+
+// Decimal the exact decimal number of the expressions, the money strings are parsed without the float drift
+// e.g. {{ Decimal(order.price).Mul(order.qty).Add(order.shipping).Format(2) }}, {{ order.total | decimal:2 }}
+// the methods return the error of the invalid values, the expression returns the error
+type Decimal struct {
+	rat *big.Rat
+}
+
+// NewDecimal create the decimal from the string, the number or the decimal, e.g. "1234.56", "1,234.56", 12, 0.1
+// the strings are parsed with the separators of the locale ("en" by default), e.g. NewDecimal("1.234,56", "de")
+func NewDecimal(value interface{}, locale ...string) (Decimal, error) {
+	switch v := value.(type) {
+	case nil:
+		return Decimal{rat: new(big.Rat)}, nil
+	case Decimal:
+		return Decimal{rat: new(big.Rat).Set(v.value())}, nil
+	case *Decimal:
+		return Decimal{rat: new(big.Rat).Set(v.value())}, nil
+	case string:
+		name := "en"
+		if len(locale) > 0 && locale[0] != "" {
+			name = locale[0]
+		}
+		rat, err := parseDecimal(v, GetHumanizeLocale(name))
+		if err != nil {
+			return Decimal{}, err
+		}
+		return Decimal{rat: rat}, nil
+	case float32:
+		return NewDecimal(strconv.FormatFloat(float64(v), 'f', -1, 32))
+	case float64:
+		return NewDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+	case int:
+		return Decimal{rat: new(big.Rat).SetInt64(int64(v))}, nil
+	case int64:
+		return Decimal{rat: new(big.Rat).SetInt64(v)}, nil
+	}
+
+	n, ok := loopNumber(value)
+	if !ok {
+		return Decimal{}, fmt.Errorf("decimal %v is not a number", value)
+	}
+	return NewDecimal(n)
+}
+
+// MustDecimal create the decimal, panic if the value is not a number
+func MustDecimal(value interface{}) Decimal {
+	d, err := NewDecimal(value)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// parseDecimal parse the string with the separators of the locale.
+// the group separators should split the integer part by 3 digits, the ambiguous strings are rejected,
+// e.g. "1.234,56" is not a number of the "en" locale
+func parseDecimal(value string, locale *HumanizeLocale) (*big.Rat, error) {
+	s := strings.NewReplacer("_", "", " ", "", "\u00a0", "", "\u202f", "").Replace(strings.TrimSpace(value))
+	if s == "" {
+		return new(big.Rat), nil
+	}
+
+	sign := ""
+	if s[0] == '-' || s[0] == '+' {
+		sign, s = s[:1], s[1:]
+	}
+
+	exponent := 0
+	if idx := strings.IndexAny(s, "eE"); idx >= 0 {
+		exp, err := strconv.Atoi(s[idx+1:])
+		if err != nil {
+			return nil, fmt.Errorf("decimal %q is not a number", value)
+		}
+		if exp > MaxDecimalExponent || exp < -MaxDecimalExponent {
+			return nil, fmt.Errorf("decimal %q error: the exponent should be between -%d and %d", value, MaxDecimalExponent, MaxDecimalExponent)
+		}
+		exponent, s = exp, s[:idx]
+	}
+
+	integer, fraction := s, ""
+	if idx := strings.Index(s, locale.Decimal); idx >= 0 {
+		integer, fraction = s[:idx], s[idx+len(locale.Decimal):]
+		if fraction == "" || !decimalDigits(fraction) {
+			return nil, fmt.Errorf("decimal %q is not a number", value)
+		}
+	}
+
+	if locale.Group != "" && strings.Contains(integer, locale.Group) {
+		groups := strings.Split(integer, locale.Group)
+		for i, group := range groups {
+			if (i == 0 && (len(group) == 0 || len(group) > 3)) || (i > 0 && len(group) != 3) {
+				return nil, fmt.Errorf("decimal %q error: the group separators are ambiguous", value)
+			}
+		}
+		integer = strings.Join(groups, "")
+	}
+
+	if integer == "" || !decimalDigits(integer) {
+		return nil, fmt.Errorf("decimal %q is not a number", value)
+	}
+
+	number := sign + integer
+	if fraction != "" {
+		number = number + "." + fraction
+	}
+	rat, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return nil, fmt.Errorf("decimal %q is not a number", value)
+	}
+
+	if exponent > 0 {
+		rat.Mul(rat, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)))
+	} else if exponent < 0 {
+		rat.Quo(rat, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exponent)), nil)))
+	}
+	return rat, nil
+}
+
+func decimalDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (d Decimal) value() *big.Rat {
+	if d.rat == nil {
+		return new(big.Rat)
+	}
+	return d.rat
+}
+
+// Add a + b
+func (d Decimal) Add(value interface{}) (Decimal, error) {
+	b, err := NewDecimal(value)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return Decimal{rat: new(big.Rat).Add(d.value(), b.rat)}, nil
+}
+
+// Sub a - b
+func (d Decimal) Sub(value interface{}) (Decimal, error) {
+	b, err := NewDecimal(value)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return Decimal{rat: new(big.Rat).Sub(d.value(), b.rat)}, nil
+}
+
+// Mul a * b
+func (d Decimal) Mul(value interface{}) (Decimal, error) {
+	b, err := NewDecimal(value)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return Decimal{rat: new(big.Rat).Mul(d.value(), b.rat)}, nil
+}
+
+// Div a / b, the result is exact, use Round or Format to get the places
+func (d Decimal) Div(value interface{}) (Decimal, error) {
+	b, err := NewDecimal(value)
+	if err != nil {
+		return Decimal{}, err
+	}
+	if b.rat.Sign() == 0 {
+		return Decimal{}, fmt.Errorf("decimal division by zero")
+	}
+	return Decimal{rat: new(big.Rat).Quo(d.value(), b.rat)}, nil
+}
+
+// Cmp compare the decimals, -1 if a < b, 0 if a == b, 1 if a > b
+func (d Decimal) Cmp(value interface{}) (int, error) {
+	b, err := NewDecimal(value)
+	if err != nil {
+		return 0, err
+	}
+	return d.value().Cmp(b.rat), nil
+}
+
+// Round round half away from zero to the places
+func (d Decimal) Round(places int) Decimal {
+	places = decimalPlaces(places)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(places)), nil)
+	scaled := new(big.Rat).Mul(d.value(), new(big.Rat).SetInt(scale))
+
+	num := new(big.Int).Abs(scaled.Num())
+	quo, rem := new(big.Int).QuoRem(num, scaled.Denom(), new(big.Int))
+	if rem.Mul(rem, big.NewInt(2)).Cmp(scaled.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if scaled.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	return Decimal{rat: new(big.Rat).SetFrac(quo, scale)}
+}
+
+// Float the float value, for the comparisons in the expressions
+func (d Decimal) Float() float64 {
+	f, _ := d.value().Float64()
+	return f
+}
+
+// String the string of the decimal, the exact digits are kept (up to 16 places for the repeating decimals)
+func (d Decimal) String() string {
+	if d.value().IsInt() {
+		return d.value().Num().String()
+	}
+	s := d.value().FloatString(16)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// Format format the decimal with the places and the separators of the locale, e.g. Format(2), Format(2, "de")
+// => 1,234.50, 1.234,50
+func (d Decimal) Format(args ...interface{}) string {
+	places := 2
+	if len(args) > 0 {
+		if n, ok := loopNumber(args[0]); ok {
+			places = decimalPlaces(int(n))
+		}
+	}
+
+	locale := GetHumanizeLocale("en")
+	if len(args) > 1 {
+		if name, ok := args[1].(string); ok {
+			locale = GetHumanizeLocale(name)
+		}
+	}
+	return formatDecimal(d.Round(places), places, locale)
+}
+
+// MarshalJSON the decimal is a string in the JSON, the precision is kept
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+func formatDecimal(d Decimal, places int, locale *HumanizeLocale) string {
+	s := d.value().FloatString(places)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	integer, fraction := s, ""
+	if idx := strings.Index(s, "."); idx >= 0 {
+		integer, fraction = s[:idx], s[idx+1:]
+	}
+
+	var b strings.Builder
+	for i, c := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(locale.Group)
+		}
+		b.WriteRune(c)
+	}
+	if fraction != "" {
+		b.WriteString(locale.Decimal)
+		b.WriteString(fraction)
+	}
+	return sign + b.String()
+}
+
+// decimalPlaces clamp the places between 0 and the MaxDecimalPlaces
+func decimalPlaces(places int) int {
+	if places < 0 {
+		return 0
+	}
+	if places > MaxDecimalPlaces {
+		return MaxDecimalPlaces
+	}
+	return places
+}
+
+// _decimal Decimal(value[, locale]) the expression function
+func _decimal(args ...any) (interface{}, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, fmt.Errorf("Decimal should have one or two parameters")
+	}
+	if len(args) == 2 {
+		locale, ok := args[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("Decimal the locale should be a string")
+		}
+		return NewDecimal(args[0], locale)
+	}
+	return NewDecimal(args[0])
+}
+
+// _decimalFilter {{ total | decimal:2 }} format the value with the separators of the $locale
+func _decimalFilter(locale string, value interface{}, args ...interface{}) (interface{}, error) {
+	d, err := NewDecimal(value, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	places := 2
+	if len(args) > 0 {
+		if n, ok := loopNumber(args[0]); ok {
+			places = decimalPlaces(int(n))
+		}
+	}
+	return formatDecimal(d.Round(places), places, GetHumanizeLocale(locale)), nil
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecimal(t *testing.T) {
+	a, err := NewDecimal(0.1)
+	assert.Nil(t, err)
+	sum, err := a.Add(0.2)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.3", sum.String())
+	cmp, err := sum.Cmp("0.3")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, cmp)
+
+	d, err := NewDecimal("1,234.5")
+	assert.Nil(t, err)
+	assert.Equal(t, "1,234.50", d.Format(2))
+	assert.Equal(t, "1.234,50", d.Format(2, "de"))
+
+	d, err = NewDecimal("1.234,56", "de")
+	assert.Nil(t, err)
+	assert.Equal(t, "1234.56", d.String())
+
+	d, err = NewDecimal("1.5e3")
+	assert.Nil(t, err)
+	assert.Equal(t, "1500", d.String())
+
+	// The invalid and the ambiguous strings
+	for _, value := range []string{"1e999999999", "1e-999999999", "1.234,56", "1,2345.6", "12,34", "abc", "1.2.3", "-"} {
+		_, err := NewDecimal(value)
+		assert.NotNil(t, err, value)
+	}
+	_, err = NewDecimal("1,234.56", "de")
+	assert.NotNil(t, err)
+
+	// The errors are returned, not panicked
+	_, err = d.Div(0)
+	assert.NotNil(t, err)
+	_, err = d.Mul("abc")
+	assert.NotNil(t, err)
+
+	// The places are clamped
+	assert.Equal(t, "1500", d.Round(-1).String())
+	assert.Len(t, d.Format(1000000), len("1,500.")+MaxDecimalPlaces)
+}
+
+func TestDecimalExpression(t *testing.T) {
+	data := Data{"price": "0.1", "qty": 3, "fee": 0.2, "bad": "1e999999999"}
+
+	value, values := data.Replace(`{{ Decimal(price).Mul(qty).Add(fee).Format(2) }}`)
+	assert.Nil(t, values[0].Error)
+	assert.Equal(t, "0.50", value)
+
+	value, values = data.Replace(`{{ Decimal(0.1).Add(0.2) }}`)
+	assert.Nil(t, values[0].Error)
+	assert.Equal(t, "0.3", value)
+
+	value, values = data.Replace(`{{ Decimal("1.234,5", "de").Format(1, "de") }}`)
+	assert.Nil(t, values[0].Error)
+	assert.Equal(t, "1.234,5", value)
+
+	_, values = data.Replace(`{{ Decimal(price).Div(0) }}`)
+	assert.NotNil(t, values[0].Error)
+
+	_, values = data.Replace(`{{ Decimal(bad) }}`)
+	assert.NotNil(t, values[0].Error)
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The placeholders of the {{ and }} of the templates with the custom delimiters, the Vue and Angular
+// snippets are kept as they are, the placeholders are restored to {{ and }} in the output
+const (
+	delimiterOpen  = "\uE000\uE001"
+	delimiterClose = "\uE001\uE000"
+)
+
+var delimitersRestore = strings.NewReplacer(delimiterOpen, "{{", delimiterClose, "}}")
+
+// Delimit convert the statements of the custom delimiters to the {{ }} statements, e.g. [[ title ]] => {{ title }},
+// the {{ and }} of the source are not the statements, e.g. the Vue and Angular snippets. set the delimiters
+// in the page config or the ParserOption, the default delimiters are {{ and }}
+//
+//	{"delimiters": ["[[", "]]"]}
+func Delimit(source string, delimiters []string) (string, error) {
+	if len(delimiters) == 0 {
+		return source, nil
+	}
+	if len(delimiters) != 2 || delimiters[0] == "" || delimiters[1] == "" {
+		return "", fmt.Errorf("the delimiters should be the start and the end, e.g. [\"[[\", \"]]\"]")
+	}
+
+	start, end := delimiters[0], delimiters[1]
+	if start == "{{" && end == "}}" {
+		return source, nil
+	}
+	if strings.Contains(start, "{{") || strings.Contains(end, "}}") {
+		return "", fmt.Errorf("the delimiters %s %s should not contain {{ or }}", start, end)
+	}
+
+	source = strings.ReplaceAll(source, "{{", delimiterOpen)
+	source = strings.ReplaceAll(source, "}}", delimiterClose)
+	tokens := Tokens{{start: start, end: end}}
+	return tokens.ReplaceAllStringFunc(source, func(stmt string) string {
+		return "{{" + stmt[len(start):len(stmt)-len(end)] + "}}"
+	}), nil
+}
+
+// restoreDelimiters restore the {{ and }} of the templates with the custom delimiters
+func restoreDelimiters(html string) string {
+	if !strings.Contains(html, delimiterOpen) && !strings.Contains(html, delimiterClose) {
+		return html
+	}
+	return delimitersRestore.Replace(html)
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelimit(t *testing.T) {
+	source, err := Delimit(`<p>[[ title ]]</p><div id="app">{{ message }}</div>`, []string{"[[", "]]"})
+	assert.Nil(t, err)
+	assert.Equal(t, `<p>{{ title }}</p><div id="app">`+delimiterOpen+` message `+delimiterClose+`</div>`, source)
+	assert.Equal(t, `<div id="app">{{ message }}</div>`, restoreDelimiters(`<div id="app">`+delimiterOpen+` message `+delimiterClose+`</div>`))
+
+	source, err = Delimit(`<p>{% title %}</p>`, []string{"{%", "%}"})
+	assert.Nil(t, err)
+	assert.Equal(t, `<p>{{ title }}</p>`, source)
+
+	source, err = Delimit(`<p>{{ title }}</p>`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, `<p>{{ title }}</p>`, source)
+
+	_, err = Delimit(`<p>[[ title ]]</p>`, []string{"[["})
+	assert.Error(t, err)
+	_, err = Delimit(`<p>[[ title ]]</p>`, []string{"{{{", "]]"})
+	assert.Error(t, err)
+}
+
+func TestRenderDelimiters(t *testing.T) {
+	source := `<html><body><h1 class="[[ theme ]]">[[ title ]]</h1><p s:if="[[ show ]]">Shown</p>` +
+		`<div id="app" v-bind:title="{{ vue }}">{{ message }} {{- trimmed -}}</div></body></html>`
+	parser := NewTemplateParser(Data{"title": "Home", "theme": "dark", "show": true}, &ParserOption{Delimiters: []string{"[[", "]]"}})
+	html, err := parser.Render(source)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `>Home</h1>`)
+	assert.Contains(t, html, `class="dark"`)
+	assert.Contains(t, html, `>Shown</p>`)
+	assert.Contains(t, html, `v-bind:title="{{ vue }}"`)
+	assert.Contains(t, html, `{{ message }} {{- trimmed -}}</div>`)
+	assert.NotContains(t, html, delimiterOpen)
+
+	parser = NewTemplateParser(Data{"title": "Home"}, &ParserOption{Delimiters: []string{"[["}})
+	_, err = parser.Render(source)
+	assert.Error(t, err)
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// PageDependency the file or the process the page depends on
+type PageDependency struct {
+	Type string `json:"type"`           // page, component, layout, partial, script, style, asset, locale, data, config, process
+	Name string `json:"name"`           // the route, the file or the process name
+	File string `json:"file,omitempty"` // the file in the storage
+	Hash string `json:"hash,omitempty"` // the sha256 of the content, empty if the file is missing or the dependency is a process
+}
+
+// PageDependencies the dependency closure of the page, for the cache invalidation, the incremental builds
+// and the deployment diff reports
+type PageDependencies struct {
+	Route string           `json:"route"`
+	Hash  string           `json:"hash"` // the hash of the closure, changed if any of the dependencies changed
+	Items []PageDependency `json:"items"`
+	seen  map[string]bool
+}
+
+// NewPageDependencies create the dependency closure of the page
+func NewPageDependencies(route string) *PageDependencies {
+	return &PageDependencies{Route: route, Items: []PageDependency{}, seen: map[string]bool{}}
+}
+
+// Has check if the dependency is added
+func (deps *PageDependencies) Has(typ string, name string) bool {
+	return deps.seen[typ+":"+name]
+}
+
+// Add add the dependency, the content is nil if the file is missing or the dependency is a process
+func (deps *PageDependencies) Add(typ string, name string, file string, content []byte) {
+	if deps.Has(typ, name) {
+		return
+	}
+	deps.seen[typ+":"+name] = true
+
+	hash := ""
+	if content != nil {
+		hash = DependencyHash(content)
+	}
+	deps.Items = append(deps.Items, PageDependency{Type: typ, Name: name, File: file, Hash: hash})
+}
+
+// Sum sort the dependencies and compute the hash of the closure
+func (deps *PageDependencies) Sum() string {
+	sort.SliceStable(deps.Items, func(i, j int) bool {
+		if deps.Items[i].Type != deps.Items[j].Type {
+			return deps.Items[i].Type < deps.Items[j].Type
+		}
+		return deps.Items[i].Name < deps.Items[j].Name
+	})
+
+	h := sha256.New()
+	for _, item := range deps.Items {
+		h.Write([]byte(item.Type + "\x00" + item.Name + "\x00" + item.Hash + "\n"))
+	}
+	deps.Hash = hex.EncodeToString(h.Sum(nil))
+	return deps.Hash
+}
+
+// AddPartials add the partials of the <s:include src="..."> in the source, the nested partials are added too.
+// the partials are read from the public root, the dynamic src (with the {{ }}) can not be resolved and is skipped
+func (deps *PageDependencies) AddPartials(root string, route string, source string) {
+	parser := &TemplateParser{option: &ParserOption{Root: root, Route: route}}
+	deps.addPartials(parser, source)
+}
+
+func (deps *PageDependencies) addPartials(parser *TemplateParser, source string) {
+	_, partials, _ := DependencyScan(source)
+	for _, src := range partials {
+		file, err := parser.includeFile(src)
+		if err != nil || deps.Has("partial", file) || len(parser.includes) >= MaxIncludeDepth {
+			continue
+		}
+
+		content, err := includeRead(file)
+		if err != nil {
+			deps.Add("partial", file, file, nil)
+			continue
+		}
+		deps.Add("partial", file, file, content)
+
+		parser.includes = append(parser.includes, file)
+		deps.addPartials(parser, string(content))
+		parser.includes = parser.includes[:len(parser.includes)-1]
+	}
+}
+
+// DependencyHash the hash of the content of the dependency
+func DependencyHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// DependencyScan get the static references of the page source, the components (is="/route", <import s:from="/route">),
+// the partials (<s:include src="...">) and the assets (the @assets/ scripts, styles and images).
+// the references with the variables (the just-in-time components) are skipped
+func DependencyScan(source string) (components []string, partials []string, assets []string) {
+	components, partials, assets = []string{}, []string{}, []string{}
+	doc, err := NewDocumentString(source)
+	if err != nil {
+		return
+	}
+
+	seen := map[string]bool{}
+	add := func(list *[]string, kind string, value string) {
+		value = strings.TrimSpace(value)
+		if value == "" || strings.Contains(value, "{{") || strings.Contains(value, "}}") || seen[kind+value] {
+			return
+		}
+		seen[kind+value] = true
+		*list = append(*list, value)
+	}
+
+	for _, node := range doc.Find("*").Nodes {
+		for _, attr := range node.Attr {
+			switch {
+			case attr.Key == "is" && node.Data != "slot":
+				add(&components, "component", attr.Val)
+			case attr.Key == "s:from" && node.Data == "import":
+				add(&components, "component", attr.Val)
+			case attr.Key == "src" && node.Data == "s:include":
+				add(&partials, "partial", attr.Val)
+			case (attr.Key == "src" || attr.Key == "href") && strings.HasPrefix(attr.Val, "@assets/"):
+				add(&assets, "asset", strings.TrimPrefix(attr.Val, "@assets/"))
+			}
+		}
+	}
+	return
+}
+
+// DependencyProcesses get the processes called by the page data, e.g. {"$users": {"process": "models.user.Get", "__exec": true}}
+// the backend script methods (@method) are included
+func DependencyProcesses(data string) []string {
+	processes := []string{}
+	if strings.TrimSpace(data) == "" {
+		return processes
+	}
+
+	var value interface{}
+	if err := jsoniter.UnmarshalFromString(data, &value); err != nil {
+		return processes
+	}
+
+	seen := map[string]bool{}
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if name, ok := v["process"].(string); ok && name != "" && !seen[name] {
+				seen[name] = true
+				processes = append(processes, name)
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(value)
+	sort.Strings(processes)
+	return processes
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyScan(t *testing.T) {
+	source := `<import s:as="card" s:from="/components/card"></import>` +
+		`<div is="/components/header"></div><div is="/components/{{ name }}"></div><div is="/components/header"></div>` +
+		`<s:include src="partials/nav.html"></s:include><s:include src="{{ file }}"></s:include>` +
+		`<script src="@assets/app.js"></script><link rel="stylesheet" href="@assets/app.css" /><img src="https://example.com/a.png" />`
+
+	components, partials, assets := DependencyScan(source)
+	assert.Equal(t, []string{"/components/card", "/components/header"}, components)
+	assert.Equal(t, []string{"partials/nav.html"}, partials)
+	assert.Equal(t, []string{"app.js", "app.css"}, assets)
+
+	processes := DependencyProcesses(`{"users": {"process": "models.user.Get", "__exec": true}, "items": [{"process": "@Items"}], "title": "process"}`)
+	assert.Equal(t, []string{"@Items", "models.user.Get"}, processes)
+	assert.Empty(t, DependencyProcesses(`not json`))
+}
+
+func TestPageDependencies(t *testing.T) {
+	files := map[string]string{
+		"/public/demo/docs/partials/nav.html":  `<nav><s:include src="item.html"></s:include><s:include src="/docs/partials/nav.html"></s:include></nav>`,
+		"/public/demo/docs/partials/item.html": `<a>Item</a>`,
+	}
+	read := includeRead
+	defer func() { includeRead = read }()
+	includeRead = func(file string) ([]byte, error) {
+		if content, has := files[file]; has {
+			return []byte(content), nil
+		}
+		return nil, fmt.Errorf("%s not found", file)
+	}
+
+	deps := NewPageDependencies("/docs/index")
+	deps.Add("page", "/docs/index", "/docs/index/index.html", []byte("<div></div>"))
+	deps.Add("process", "models.user.Get", "", nil)
+	deps.AddPartials("demo", "/docs/index", `<s:include src="partials/nav.html"></s:include><s:include src="missing.html"></s:include>`)
+	hash := deps.Sum()
+
+	names := []string{}
+	for _, item := range deps.Items {
+		names = append(names, item.Type+" "+item.Name)
+	}
+	assert.Equal(t, []string{
+		"page /docs/index",
+		"partial /public/demo/docs/missing.html",
+		"partial /public/demo/docs/partials/item.html",
+		"partial /public/demo/docs/partials/nav.html",
+		"process models.user.Get",
+	}, names)
+	assert.Equal(t, DependencyHash([]byte("<a>Item</a>")), deps.Items[2].Hash)
+	assert.Empty(t, deps.Items[1].Hash)
+
+	// The hash of the closure is changed if any of the dependencies changed
+	files["/public/demo/docs/partials/item.html"] = `<a>Changed</a>`
+	changed := NewPageDependencies("/docs/index")
+	changed.Add("page", "/docs/index", "/docs/index/index.html", []byte("<div></div>"))
+	changed.Add("process", "models.user.Get", "", nil)
+	changed.AddPartials("demo", "/docs/index", `<s:include src="partials/nav.html"></s:include><s:include src="missing.html"></s:include>`)
+	assert.NotEqual(t, hash, changed.Sum())
+}
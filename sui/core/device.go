@@ -0,0 +1,70 @@
+package core
+
+import (
+	"regexp"
+	"strings"
+)
+
+// deviceKey the data key of the device of the visitor, {{ $device.mobile }}
+const deviceKey = "$device"
+
+// DeviceHeaders the headers the device is derived from, the pages use the $device vary by the headers
+var DeviceHeaders = []string{"User-Agent", "Sec-CH-UA-Mobile"}
+
+var reBot = regexp.MustCompile(`(?i)bot\b|bot/|crawl|spider|slurp|bingpreview|mediapartners|facebookexternalhit|embedly|lighthouse|headlesschrome|curl/|wget/|python-requests`)
+var reTablet = regexp.MustCompile(`(?i)ipad|tablet|kindle|silk/|playbook|nexus (7|9|10)`)
+var reMobile = regexp.MustCompile(`(?i)mobi|iphone|ipod|android|blackberry|bb10|opera mini|iemobile|windows phone`)
+
+// Device the device of the visitor, derived from the client hints and the user agent
+type Device struct {
+	Type string `json:"type"` // mobile, tablet or desktop
+	Bot  bool   `json:"bot"`  // the crawlers and the headless browsers
+}
+
+// Device get the device of the visitor. the Sec-CH-UA-Mobile client hint wins over the user agent, the android
+// devices without "mobile" in the user agent are tablets
+func (r *Request) Device() *Device {
+	ua := ""
+	hint := ""
+	if r != nil && r.Headers != nil {
+		ua = r.Headers.Get("User-Agent")
+		hint = strings.TrimSpace(r.Headers.Get("Sec-CH-UA-Mobile"))
+	}
+
+	device := &Device{Type: "desktop", Bot: reBot.MatchString(ua)}
+	switch {
+	case hint == "?1":
+		device.Type = "mobile"
+	case reTablet.MatchString(ua):
+		device.Type = "tablet"
+	case strings.Contains(strings.ToLower(ua), "android") && !strings.Contains(strings.ToLower(ua), "mobile"):
+		device.Type = "tablet"
+	case hint == "?0":
+		device.Type = "desktop"
+	case reMobile.MatchString(ua):
+		device.Type = "mobile"
+	}
+	return device
+}
+
+// Data the data of the device for the expressions
+//
+//	<div s:if="!$device.bot && $device.type != 'mobile'">...</div>
+//	<img s:if="$device.mobile" src="/hero-small.webp" />
+func (d *Device) Data() map[string]interface{} {
+	return map[string]interface{}{
+		"type":    d.Type,
+		"mobile":  d.Type == "mobile",
+		"tablet":  d.Type == "tablet",
+		"desktop": d.Type == "desktop",
+		"bot":     d.Bot,
+	}
+}
+
+// String the cache key of the device
+func (d *Device) String() string {
+	if d.Bot {
+		return d.Type + ":bot"
+	}
+	return d.Type
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestDevice(t *testing.T) {
+	device := func(ua string, hint string) *Device {
+		headers := url.Values{"User-Agent": {ua}}
+		if hint != "" {
+			headers.Set("Sec-CH-UA-Mobile", hint)
+		}
+		return (&Request{Headers: headers}).Device()
+	}
+
+	assert.Equal(t, "mobile", device("Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) Mobile/15E148", "").Type)
+	assert.Equal(t, "tablet", device("Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X)", "").Type)
+	assert.Equal(t, "tablet", device("Mozilla/5.0 (Linux; Android 14; SM-X710) Chrome/120.0 Safari/537.36", "?0").Type)
+	assert.Equal(t, "mobile", device("Mozilla/5.0 (Linux; Android 14; Pixel 8) Chrome/120.0 Mobile Safari/537.36", "").Type)
+	assert.Equal(t, "mobile", device("Mozilla/5.0 (Linux; Android 10; K) Chrome/120.0 Safari/537.36", "?1").Type)
+	assert.Equal(t, "desktop", device("Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0", "?0").Type)
+
+	bot := device("Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", "")
+	assert.True(t, bot.Bot)
+	assert.Equal(t, "desktop:bot", bot.String())
+	assert.False(t, device("Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0) Safari/605.1.15", "").Bot)
+	assert.Equal(t, "desktop", (*Request)(nil).Device().Type)
+}
+
+func TestParserDevice(t *testing.T) {
+	source := `<html><body>` +
+		`<img s:if="$device.mobile" src="/hero-small.webp"/>` +
+		`<img s:if="!$device.mobile" src="/hero.webp"/>` +
+		`<div s:if="!$device.bot">Chart</div>` +
+		`</body></html>`
+
+	r := &Request{Headers: url.Values{"User-Agent": {"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) Mobile/15E148"}}}
+	parser := NewTemplateParser(Data{}, &ParserOption{Request: r})
+	html, err := parser.Render(source)
+	assert.Nil(t, err)
+	assert.Empty(t, parser.Errors())
+	assert.Contains(t, html, `hero-small.webp`)
+	assert.NotContains(t, html, `"/hero.webp"`)
+	assert.Contains(t, html, `<div>Chart</div>`)
+
+	r = &Request{Headers: url.Values{"User-Agent": {"Mozilla/5.0 (compatible; bingbot/2.0)"}}}
+	parser = NewTemplateParser(Data{}, &ParserOption{Request: r})
+	html, err = parser.Render(source)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `"/hero.webp"`)
+	assert.NotContains(t, html, `Chart`)
+	assert.NotContains(t, html, deviceKey) // not sent to the browser
+
+	args, err := r.parseArgs([]interface{}{"$device.bot"})
+	assert.Nil(t, err)
+	assert.Equal(t, true, args[0])
+}
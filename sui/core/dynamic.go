@@ -0,0 +1,67 @@
+package core
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// isAllowAttr the allowlist of the component routes the bound is attribute can resolve to, the glob patterns
+// are separated by comma
+//
+//	<div is="{{ item.widget }}" s:is-allow="/widgets/*,/cards/*"></div>
+const isAllowAttr = "s:is-allow"
+
+// jitPatterns get the route patterns of the just-in-time component, the s:is-allow patterns or the is attribute
+// with the bindings replaced by "*". the patterns are used to compile the .jit files and to check the route
+func jitPatterns(is string, allow string) []string {
+	patterns := []string{}
+	for _, pattern := range strings.Split(allow, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		patterns = append(patterns, "/"+strings.TrimLeft(pattern, "/"))
+	}
+
+	if len(patterns) > 0 {
+		return patterns
+	}
+
+	pattern := dataTokens.ReplaceAllString(is, "*")
+	pattern = propTokens.ReplaceAllString(pattern, "*")
+	return []string{"/" + strings.TrimLeft(pattern, "/")}
+}
+
+// jitRoute check the route the is attribute resolved to, the route must be clean and match one of the patterns.
+// when the whole is attribute is bound (e.g. is="{{ item.widget }}") the s:is-allow is required, otherwise any
+// component of the template could be rendered by the data
+func (parser *TemplateParser) jitRoute(sel *goquery.Selection, route string) (string, error) {
+	is := sel.AttrOr("is", "")
+	patterns := jitPatterns(is, sel.AttrOr(isAllowAttr, ""))
+	if len(patterns) == 1 && patterns[0] == "/*" && strings.TrimSpace(sel.AttrOr(isAllowAttr, "")) == "" {
+		return "", fmt.Errorf("Component %s is bound, the %s attribute is required", is, isAllowAttr)
+	}
+
+	route = strings.TrimSpace(route)
+	if route == "" {
+		return "", fmt.Errorf("Component route is required")
+	}
+
+	if !strings.HasPrefix(route, "/") {
+		route = "/" + route
+	}
+
+	if strings.Contains(route, "..") || strings.ContainsAny(route, "\\\x00") || path.Clean(route) != route {
+		return "", fmt.Errorf("Component %s is not a valid route", route)
+	}
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, route); ok {
+			return route, nil
+		}
+	}
+	return "", fmt.Errorf("Component %s is not allowed, allowed: %s", route, strings.Join(patterns, ","))
+}
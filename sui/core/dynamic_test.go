@@ -0,0 +1,46 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJitPatterns(t *testing.T) {
+	assert.Equal(t, []string{"/card-*"}, jitPatterns("/card-{{ type }}", ""))
+	assert.Equal(t, []string{"/widgets/*", "/cards/*"}, jitPatterns("{{ item.widget }}", "widgets/*, /cards/*"))
+}
+
+func TestParserJitRoute(t *testing.T) {
+	doc, err := NewDocumentString(`<html><body>` +
+		`<div id="allow" is="{{ item.widget }}" s:jit="true" s:is-allow="/widgets/*,/cards/*"></div>` +
+		`<div id="bound" is="{{ item.widget }}" s:jit="true"></div>` +
+		`<div id="card" is="/card-{{ type }}" s:jit="true"></div>` +
+		`</body></html>`)
+	assert.Nil(t, err)
+	parser := NewTemplateParser(Data{}, nil)
+
+	route, err := parser.jitRoute(doc.Find("#allow"), "widgets/chart")
+	assert.Nil(t, err)
+	assert.Equal(t, "/widgets/chart", route)
+
+	_, err = parser.jitRoute(doc.Find("#allow"), "/admin/users")
+	assert.NotNil(t, err)
+	_, err = parser.jitRoute(doc.Find("#allow"), "/widgets/../admin")
+	assert.NotNil(t, err)
+	_, err = parser.jitRoute(doc.Find("#allow"), "/widgets/chart/secret")
+	assert.NotNil(t, err)
+	_, err = parser.jitRoute(doc.Find("#allow"), "")
+	assert.NotNil(t, err)
+
+	// The s:is-allow is required when the whole route is bound
+	_, err = parser.jitRoute(doc.Find("#bound"), "/widgets/chart")
+	assert.NotNil(t, err)
+
+	// The pattern of the is attribute
+	route, err = parser.jitRoute(doc.Find("#card"), "/card-news")
+	assert.Nil(t, err)
+	assert.Equal(t, "/card-news", route)
+	_, err = parser.jitRoute(doc.Find("#card"), "/page-news")
+	assert.NotNil(t, err)
+}
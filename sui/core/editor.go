@@ -82,12 +82,10 @@ func (page *Page) EditorRender() (*ResponseEditorRender, error) {
 		return nil, err
 	}
 
-	var data Data = nil
-	if page.Codes.DATA.Code != "" {
-		data, err = page.Exec(request)
-		if err != nil {
-			res.Warnings = append(res.Warnings, err.Error())
-		}
+	// The fixtures are used if exist
+	data, err := page.PreviewData(request)
+	if err != nil {
+		res.Warnings = append(res.Warnings, err.Error())
 	}
 	res.Render(data)
 
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/kun/log"
+)
+
+// embedScriptTmpl the height of the page is posted to the parent, only if the origin of the parent is allowed
+const embedScriptTmpl = `
+	(function () {
+		const origins = %s;
+		let parent = "";
+		if (location.ancestorOrigins && location.ancestorOrigins.length > 0) {
+			parent = location.ancestorOrigins[0];
+		} else if (document.referrer) {
+			try { parent = new URL(document.referrer).origin; } catch (e) {}
+		}
+		if (!parent || parent === "null" || !origins.some((origin) => origin === "*" || origin === parent)) return;
+		const target = parent;
+		let last = 0;
+		function __sui_embed_height() {
+			const height = document.documentElement.scrollHeight;
+			if (height === last) return;
+			last = height;
+			try {
+				window.parent.postMessage({ messageType: "sui:embed:height", height: height, url: location.href }, target);
+			} catch (e) { console.log("[SUI] embed height error:", e); }
+		}
+		window.addEventListener("load", __sui_embed_height);
+		if (typeof ResizeObserver === "function") {
+			new ResizeObserver(__sui_embed_height).observe(document.documentElement);
+		}
+	})();
+`
+
+// Enable check if the embed mode is enabled
+func (embed *PageEmbed) Enable() bool {
+	return embed != nil && embed.Enabled
+}
+
+// ContentSecurityPolicy the frame-ancestors policy of the embed mode
+func (embed *PageEmbed) ContentSecurityPolicy() string {
+	ancestors := []string{"'self'"}
+	for _, origin := range embed.Origins {
+		origin = strings.TrimSpace(origin)
+		if origin == "" {
+			continue
+		}
+		if origin == "*" {
+			return "frame-ancestors *"
+		}
+		ancestors = append(ancestors, origin)
+	}
+	return fmt.Sprintf("frame-ancestors %s", strings.Join(ancestors, " "))
+}
+
+// MergeContentSecurityPolicy merge the directive to the policy, the directive of the same name is replaced,
+// the other directives are kept, e.g. ("default-src 'self'; frame-ancestors 'none'", "frame-ancestors 'self'")
+// => "default-src 'self'; frame-ancestors 'self'"
+func MergeContentSecurityPolicy(policy string, directive string) string {
+	name := strings.ToLower(strings.Fields(directive + " ")[0])
+	res := []string{}
+	for _, item := range strings.Split(policy, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" || strings.ToLower(strings.Fields(item)[0]) == name {
+			continue
+		}
+		res = append(res, item)
+	}
+	res = append(res, directive)
+	return strings.Join(res, "; ")
+}
+
+// EmbedDocument remove the layout chrome of the document, keep the head only
+func EmbedDocument(document []byte) []byte {
+	doc, err := NewDocument(document)
+	if err != nil {
+		log.Error("[sui] embed document error %s", err.Error())
+		return document
+	}
+
+	doc.Find("body").SetHtml("{{ __page }}")
+	html, err := doc.Html()
+	if err != nil {
+		log.Error("[sui] embed document error %s", err.Error())
+		return document
+	}
+	return []byte(html)
+}
+
+func embedInjectionScript(origins []string) string {
+	allowed := []string{}
+	for _, origin := range origins {
+		if origin = strings.TrimSuffix(strings.TrimSpace(origin), "/"); origin != "" {
+			allowed = append(allowed, origin)
+		}
+	}
+	raw, err := jsoniter.MarshalToString(allowed)
+	if err != nil {
+		raw = "[]"
+	}
+	return fmt.Sprintf(`<script type="text/javascript">`+embedScriptTmpl+`</script>`, raw)
+}
+
+func (page *Page) embed() *PageEmbed {
+	config := page.GetConfig()
+	if config == nil || !config.Embed.Enable() {
+		return nil
+	}
+	return config.Embed
+}
@@ -0,0 +1,28 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbedContentSecurityPolicy(t *testing.T) {
+	embed := &PageEmbed{Enabled: true, Origins: []string{"https://a.com", " ", "https://b.com"}}
+	assert.Equal(t, "frame-ancestors 'self' https://a.com https://b.com", embed.ContentSecurityPolicy())
+
+	policy := MergeContentSecurityPolicy("default-src 'self'; Frame-Ancestors 'none'; img-src *", embed.ContentSecurityPolicy())
+	assert.Equal(t, "default-src 'self'; img-src *; frame-ancestors 'self' https://a.com https://b.com", policy)
+	assert.Equal(t, "frame-ancestors 'self'", MergeContentSecurityPolicy("", "frame-ancestors 'self'"))
+}
+
+func TestEmbedInjectionScript(t *testing.T) {
+	script := embedInjectionScript([]string{"https://a.com/", " https://b.com"})
+	assert.Contains(t, script, `const origins = ["https://a.com","https://b.com"];`)
+	assert.Contains(t, script, "location.ancestorOrigins")
+	assert.Contains(t, script, "document.referrer")
+	assert.Contains(t, script, "const target = parent;")
+	assert.NotContains(t, script, `, "*")`)
+
+	script = embedInjectionScript(nil)
+	assert.Contains(t, script, `const origins = [];`)
+}
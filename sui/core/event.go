@@ -2,10 +2,12 @@ package core
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/kun/log"
 	"golang.org/x/net/html"
 )
 
@@ -21,6 +23,7 @@ func (page *Page) BindEvent(ctx *BuildContext, sel *goquery.Selection, cn string
 		id := fmt.Sprintf("%s-%d", page.namespace, ctx.sequence)
 		s.SetAttr("s:event", id)
 		ReplaceEventData(s)
+		EventModifiers(s)
 		ctx.sequence++
 		if ispage {
 			s.SetAttr("s:event-cn", "__page")
@@ -42,6 +45,7 @@ func (parser *TemplateParser) BindEvent(sel *goquery.Selection, ns string, cn st
 		id := fmt.Sprintf("%s-%d-%d", ns, parser.sequence, i+1)
 		s.SetAttr("s:event", id)
 		ReplaceEventData(s)
+		EventModifiers(s)
 		s.SetAttr("s:event-cn", cn)
 		parser.sequence++
 		hasEvent = true
@@ -64,23 +68,33 @@ func GetEventScript(sequence int, sel *goquery.Selection, ns string, cn string,
 	if len(sel.Nodes) == 0 {
 		return nil
 	}
+	EventModifiers(sel)
 
-	// Page events
+	// Page events, in the order of the attributes
 	events := map[string]string{}
+	eventNames := []string{}
 	dataUnique := map[string]string{}
+	data := []string{}
 	jsonUnique := map[string]string{}
+	json := []string{}
 	id := fmt.Sprintf("%s-%d", prefix, sequence)
 	for _, attr := range sel.Nodes[0].Attr {
 
 		if strings.HasPrefix(attr.Key, "s:on-") {
 			name := strings.TrimPrefix(attr.Key, "s:on-")
 			handler := attr.Val
+			if _, has := events[name]; !has {
+				eventNames = append(eventNames, name)
+			}
 			events[name] = handler
 			continue
 		}
 
 		if strings.HasPrefix(attr.Key, "s:data-") {
 			name := strings.TrimPrefix(attr.Key, "s:data-")
+			if _, has := dataUnique[name]; !has {
+				data = append(data, name)
+			}
 			dataUnique[name] = attr.Val
 			sel.SetAttr(fmt.Sprintf("data:%s", name), attr.Val)
 			continue
@@ -88,29 +102,29 @@ func GetEventScript(sequence int, sel *goquery.Selection, ns string, cn string,
 
 		if strings.HasPrefix(attr.Key, "s:json-") {
 			name := strings.TrimPrefix(attr.Key, "s:json-")
+			if _, has := jsonUnique[name]; !has {
+				json = append(json, name)
+			}
 			jsonUnique[name] = attr.Val
 			sel.SetAttr(fmt.Sprintf("json:%s", name), attr.Val)
 			continue
 		}
 	}
 
-	data := []string{}
-	for name := range dataUnique {
-		data = append(data, name)
+	for _, name := range data {
 		sel.RemoveAttr(fmt.Sprintf("s:data-%s", name))
 	}
 
-	json := []string{}
-	for name := range jsonUnique {
-		json = append(json, name)
+	for _, name := range json {
 		sel.RemoveAttr(fmt.Sprintf("s:json-%s", name))
 	}
 
-	dataRaw, _ := jsoniter.MarshalToString(data)
-	jsonRaw, _ := jsoniter.MarshalToString(json)
+	dataRaw, _ := jsonStable.MarshalToString(data)
+	jsonRaw, _ := jsonStable.MarshalToString(json)
 
 	source := ""
-	for name, handler := range events {
+	for _, name := range eventNames {
+		handler := events[name]
 		if ispage {
 			source += pageEventInjectScript(id, name, dataRaw, jsonRaw, handler) + "\n"
 			sel.SetAttr("s:event-cn", "__page")
@@ -151,3 +165,160 @@ func ReplaceEventData(sel *goquery.Selection) {
 		}
 	}
 }
+
+// eventModifiers the modifiers of the event bindings, the debounce and the throttle have the wait (milliseconds)
+var eventModifiers = map[string]bool{
+	"prevent":  false,
+	"stop":     false,
+	"once":     false,
+	"self":     false,
+	"capture":  false,
+	"passive":  false,
+	"debounce": true,
+	"throttle": true,
+}
+
+// EventModifiers move the modifiers of the s:on- attributes to the s:event-mods attribute, the modifiers
+// are honored by the client runtime, see __sui_event_listen
+//
+//	s:on-click.prevent.stop="submit" => s:on-click="submit" s:event-mods='{"click":{"prevent":true,"stop":true}}'
+//	s:on-input.debounce:300="search" => s:on-input="search" s:event-mods='{"input":{"debounce":300}}'
+func EventModifiers(sel *goquery.Selection) {
+	if len(sel.Nodes) == 0 {
+		return
+	}
+
+	node := sel.Nodes[0]
+	mods := map[string]map[string]interface{}{}
+	for i, attr := range node.Attr {
+		if !strings.HasPrefix(attr.Key, "s:on-") || !strings.Contains(attr.Key, ".") {
+			continue
+		}
+
+		parts := strings.Split(strings.TrimPrefix(attr.Key, "s:on-"), ".")
+		name := parts[0]
+		if _, has := mods[name]; !has {
+			mods[name] = map[string]interface{}{}
+		}
+		for _, mod := range parts[1:] {
+			key, value, hasValue := strings.Cut(mod, ":")
+			wait, allowed := eventModifiers[key]
+			if !allowed {
+				log.Warn("[SUI] %s the event modifier %s is not supported", attr.Key, key)
+				continue
+			}
+			if !wait || !hasValue {
+				mods[name][key] = true
+				continue
+			}
+			ms, err := strconv.Atoi(value)
+			if err != nil || ms < 0 {
+				log.Warn("[SUI] %s the wait of the %s should be the milliseconds", attr.Key, key)
+				mods[name][key] = true
+				continue
+			}
+			mods[name][key] = ms
+		}
+		node.Attr[i].Key = "s:on-" + name
+	}
+
+	if len(mods) == 0 {
+		return
+	}
+
+	// The modifiers of the previous binding
+	if raw, has := sel.Attr("s:event-mods"); has {
+		prev := map[string]map[string]interface{}{}
+		if err := jsoniter.UnmarshalFromString(raw, &prev); err == nil {
+			for name, m := range prev {
+				if _, has := mods[name]; !has {
+					mods[name] = m
+				}
+			}
+		}
+	}
+
+	raw, err := jsonStable.MarshalToString(mods)
+	if err != nil {
+		log.Warn("[SUI] event modifiers %s", err.Error())
+		return
+	}
+	sel.SetAttr("s:event-mods", raw)
+}
+
+// emitPrefix the prefix of the listeners of the component events, the events the component emits upward
+// by this.emit(name, data)
+//
+//	<div is="/list" s:on:selected="onSelected"></div>
+const emitPrefix = "s:on:"
+
+// emitEventPrefix the prefix of the bindings of the component events, the client runtime dispatches
+// the emit:<name> event on the root of the emitting component instance
+const emitEventPrefix = "s:on-emit:"
+
+// EmitEvents rewrite the listeners of the component events (s:on:<name>) to the event bindings of the
+// namespace-qualified events (s:on-emit:<name>), the bindings are bound by the BindEvent
+func EmitEvents(sel *goquery.Selection) {
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode {
+			for i, attr := range node.Attr {
+				node.Attr[i].Key = emitKey(attr.Key)
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	for _, node := range sel.Nodes {
+		walk(node)
+	}
+}
+
+// emitKey the binding key of the component event listener, the other keys are not changed
+func emitKey(key string) string {
+	if !strings.HasPrefix(key, emitPrefix) {
+		return key
+	}
+	return emitEventPrefix + strings.ToLower(strings.TrimPrefix(key, emitPrefix))
+}
+
+// emitNames the events the component declares, s:emit="selected,changed"
+func emitNames(value string) []string {
+	names := []string{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// undeclaredEmits check the listeners of the component events on the caller against the events the
+// component root declares (s:emit), return the listeners of the undeclared events
+func undeclaredEmits(caller *html.Node, root *html.Node) []html.Attribute {
+	declared := emitNames(attrValue(root, "s:emit"))
+	attrs := []html.Attribute{}
+	for _, attr := range caller.Attr {
+		key := emitKey(attr.Key)
+		if !strings.HasPrefix(key, emitEventPrefix) {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimPrefix(key, emitEventPrefix), ".") // the modifiers
+		if !inStrings(name, declared) {
+			attrs = append(attrs, attr)
+		}
+	}
+	return attrs
+}
+
+// emitError the error of the listener of the undeclared component event
+func emitError(route string, attr html.Attribute, root *html.Node) error {
+	name, _, _ := strings.Cut(strings.TrimPrefix(emitKey(attr.Key), emitEventPrefix), ".")
+	declared := emitNames(attrValue(root, "s:emit"))
+	if len(declared) == 0 {
+		return fmt.Errorf("the component %s does not emit the event %s, the component declares no events (s:emit)", route, name)
+	}
+	return fmt.Errorf("the component %s does not emit the event %s, declared: %s", route, name, strings.Join(declared, ","))
+}
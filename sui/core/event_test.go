@@ -0,0 +1,61 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventModifiers(t *testing.T) {
+	doc, err := NewDocumentString(`<html><body><form s:on-submit.prevent.stop="save" s:on-input.debounce:300.unknown="search" s:on-click="open"></form></body></html>`)
+	assert.Nil(t, err)
+	sel := doc.Find("form")
+	EventModifiers(sel)
+
+	assert.Equal(t, "save", sel.AttrOr("s:on-submit", ""))
+	assert.Equal(t, "search", sel.AttrOr("s:on-input", ""))
+	assert.Equal(t, "open", sel.AttrOr("s:on-click", ""))
+	assert.Equal(t, `{"input":{"debounce":300},"submit":{"prevent":true,"stop":true}}`, sel.AttrOr("s:event-mods", ""))
+
+	// The modifiers of the previous binding are kept
+	sel.SetAttr("s:on-click.once", "open")
+	sel.RemoveAttr("s:on-click")
+	EventModifiers(sel)
+	assert.Equal(t, `{"click":{"once":true},"input":{"debounce":300},"submit":{"prevent":true,"stop":true}}`, sel.AttrOr("s:event-mods", ""))
+
+	// The script of the event
+	script := GetEventScript(1, sel, "ns", "comp", "event", true)
+	assert.Contains(t, script.Source, `__sui_event_listen(element, "submit"`)
+}
+
+func TestEmitEvents(t *testing.T) {
+	doc, err := NewDocumentString(`<html><body><div is="/list" s:on:selected="onSelected" s:on:changed.once="onChanged" s:on-click="open"></div></body></html>`)
+	assert.Nil(t, err)
+	EmitEvents(doc.Selection)
+
+	sel := doc.Find("div")
+	assert.Equal(t, "onSelected", sel.AttrOr("s:on-emit:selected", ""))
+	assert.Equal(t, "onChanged", sel.AttrOr("s:on-emit:changed.once", ""))
+	assert.Equal(t, "open", sel.AttrOr("s:on-click", ""))
+	_, has := sel.Attr("s:on:selected")
+	assert.False(t, has)
+
+	// The modifiers of the component events
+	EventModifiers(sel)
+	assert.Equal(t, "onChanged", sel.AttrOr("s:on-emit:changed", ""))
+	assert.Equal(t, `{"emit:changed":{"once":true}}`, sel.AttrOr("s:event-mods", ""))
+
+	// The events the component declares
+	root, err := NewDocumentString(`<html><body><ul s:emit="Selected, removed"></ul></body></html>`)
+	assert.Nil(t, err)
+	node := root.Find("ul").Nodes[0]
+	undeclared := undeclaredEmits(sel.Nodes[0], node)
+	if assert.Len(t, undeclared, 1) {
+		assert.Equal(t, "s:on-emit:changed", undeclared[0].Key)
+		assert.Equal(t, "the component /list does not emit the event changed, declared: selected,removed", emitError("/list", undeclared[0], node).Error())
+	}
+
+	root.Find("ul").RemoveAttr("s:emit")
+	assert.Len(t, undeclaredEmits(sel.Nodes[0], node), 2)
+	assert.Contains(t, emitError("/list", undeclared[0], node).Error(), "declares no events")
+}
@@ -0,0 +1,219 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Filter the filter of the expressions, e.g. {{ title | upper | truncate:40 }}
+// the value is the result of the previous expression, the args are the values after the colon
+type Filter func(value interface{}, args ...interface{}) (interface{}, error)
+
+// LocaleFilter the locale-aware filter, the locale is the $locale of the rendering, e.g. {{ created_at | fromNow }}
+type LocaleFilter func(locale string, value interface{}, args ...interface{}) (interface{}, error)
+
+var filters = map[string]Filter{}
+var localeFilters = map[string]LocaleFilter{}
+var filterMutex sync.RWMutex
+var filterRe = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\s*(?::([\s\S]*))?$`)
+
+func init() {
+	RegisterFilter("upper", filterString(strings.ToUpper))
+	RegisterFilter("lower", filterString(strings.ToLower))
+	RegisterFilter("trim", filterString(strings.TrimSpace))
+	RegisterFilter("capitalize", filterString(func(s string) string {
+		if s == "" {
+			return s
+		}
+		clusters := Graphemes(s)
+		return strings.ToUpper(clusters[0]) + strings.Join(clusters[1:], "")
+	}))
+	RegisterFilter("truncate", func(value interface{}, args ...interface{}) (interface{}, error) {
+		return truncateArgs("truncate", false, DefaultEllipsis, append([]interface{}{value}, args...)...)
+	})
+	RegisterFilter("truncate_words", func(value interface{}, args ...interface{}) (interface{}, error) {
+		return truncateArgs("truncate_words", true, DefaultEllipsis, append([]interface{}{value}, args...)...)
+	})
+	RegisterFilter("default", func(value interface{}, args ...interface{}) (interface{}, error) {
+		if empty, _ := _empty(value); empty == true && len(args) > 0 {
+			return args[0], nil
+		}
+		return value, nil
+	})
+	RegisterFilter("json", func(value interface{}, args ...interface{}) (interface{}, error) {
+		return jsonStable.MarshalToString(value)
+	})
+	RegisterFilter("join", func(value interface{}, args ...interface{}) (interface{}, error) {
+		sep := ","
+		if len(args) > 0 {
+			sep = fmt.Sprintf("%v", args[0])
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			return value, nil
+		}
+		values := make([]string, 0, len(items))
+		for _, item := range items {
+			values = append(values, fmt.Sprintf("%v", item))
+		}
+		return strings.Join(values, sep), nil
+	})
+}
+
+// RegisterFilter register the filter, the applications can add the custom filters without the backend scripts
+func RegisterFilter(name string, filter Filter) {
+	filterMutex.Lock()
+	defer filterMutex.Unlock()
+	filters[name] = filter
+}
+
+// GetFilter get the filter by name
+func GetFilter(name string) (Filter, bool) {
+	filterMutex.RLock()
+	defer filterMutex.RUnlock()
+	filter, has := filters[name]
+	return filter, has
+}
+
+// RegisterLocaleFilter register the locale-aware filter, the filter gets the $locale of the rendering
+func RegisterLocaleFilter(name string, filter LocaleFilter) {
+	filterMutex.Lock()
+	defer filterMutex.Unlock()
+	localeFilters[name] = filter
+}
+
+// GetLocaleFilter get the locale-aware filter by name
+func GetLocaleFilter(name string) (LocaleFilter, bool) {
+	filterMutex.RLock()
+	defer filterMutex.RUnlock()
+	filter, has := localeFilters[name]
+	return filter, has
+}
+
+// filterString the filter of the string values
+func filterString(fn func(string) string) Filter {
+	return func(value interface{}, args ...interface{}) (interface{}, error) {
+		switch v := value.(type) {
+		case nil:
+			return "", nil
+		case string:
+			return fn(v), nil
+		default:
+			return fn(fmt.Sprintf("%v", v)), nil
+		}
+	}
+}
+
+// _filter the function called by the pipes, __filter(name, value, args...)
+func _filter(args ...any) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("filter should have at least two parameters")
+	}
+
+	name, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("filter name should be a string")
+	}
+
+	filter, has := GetFilter(name)
+	if !has {
+		return nil, fmt.Errorf("filter %s not found", name)
+	}
+	return filter(args[1], args[2:]...)
+}
+
+// _filterLocale the function called by the pipes of the locale-aware filters, __filter_locale(name, $locale, value, args...)
+func _filterLocale(args ...any) (interface{}, error) {
+	if len(args) < 3 {
+		return nil, fmt.Errorf("filter should have at least three parameters")
+	}
+
+	name, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("filter name should be a string")
+	}
+
+	filter, has := GetLocaleFilter(name)
+	if !has {
+		return nil, fmt.Errorf("filter %s not found", name)
+	}
+
+	locale, _ := args[1].(string)
+	return filter(locale, args[2], args[3:]...)
+}
+
+// filterPipes rewrite the pipes to the filter calls
+// e.g. title | upper | truncate:40, "..." => __filter("truncate", __filter("upper", title), 40, "...")
+// the statement is kept as it is if any of the pipes is not a registered filter (the expr pipes, e.g. items | map(...))
+func filterPipes(stmt string) string {
+	segments := splitTopLevel(stmt, '|')
+	if len(segments) < 2 {
+		return stmt
+	}
+
+	res := strings.TrimSpace(segments[0])
+	for _, segment := range segments[1:] {
+		matches := filterRe.FindStringSubmatch(strings.TrimSpace(segment))
+		if matches == nil {
+			return stmt
+		}
+
+		fn := "__filter"
+		args := []string{fmt.Sprintf("%q", matches[1]), res}
+		if _, has := GetFilter(matches[1]); !has {
+			if _, has := GetLocaleFilter(matches[1]); !has {
+				return stmt
+			}
+			fn = "__filter_locale"
+			args = []string{fmt.Sprintf("%q", matches[1]), "$locale", res}
+		}
+
+		if strings.TrimSpace(matches[2]) != "" {
+			for _, arg := range splitTopLevel(matches[2], ',') {
+				args = append(args, strings.TrimSpace(arg))
+			}
+		}
+		res = fmt.Sprintf("%s(%s)", fn, strings.Join(args, ", "))
+	}
+	return res
+}
+
+// splitTopLevel split the statement by the separator outside the strings and the brackets, the "||" is not a separator
+func splitTopLevel(stmt string, sep byte) []string {
+	parts := []string{}
+	depth := 0
+	var quote byte = 0
+	start := 0
+	for i := 0; i < len(stmt); i++ {
+		c := stmt[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch c {
+		case '"', '\'', '`':
+			quote = c
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case sep:
+			if depth != 0 {
+				continue
+			}
+			if sep == '|' && ((i+1 < len(stmt) && stmt[i+1] == '|') || (i > 0 && stmt[i-1] == '|')) {
+				continue
+			}
+			parts = append(parts, stmt[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, stmt[start:])
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterPipes(t *testing.T) {
+	assert.Equal(t, `__filter("truncate", __filter("upper", title), 40, "...")`, filterPipes(`title | upper | truncate:40, "..."`))
+	assert.Equal(t, `a || b`, filterPipes(`a || b`))
+	assert.Equal(t, `items | map(# + 1)`, filterPipes(`items | map(# + 1)`))
+
+	RegisterFilter("reverse", filterString(func(s string) string {
+		runes := []rune(s)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes)
+	}))
+
+	data := Data{"title": "hello world", "tags": []interface{}{"a", "b"}}
+	res, _ := data.Replace(`{{ title | upper | truncate:8 }} {{ title | reverse }} {{ missing | default:"Guest" }} {{ tags | join:"|" }}`)
+	assert.Equal(t, "HELLO W… dlrow olleh Guest a|b", res)
+	assert.True(t, strings.HasPrefix(filterPipes(`"a|b" | upper`), `__filter("upper", "a|b")`))
+}
+
+func TestFilterHumanize(t *testing.T) {
+	assert.Equal(t, `__filter_locale("ordinal", $locale, rank)`, filterPipes(`rank | ordinal`))
+
+	data := Data{"rank": 22, "size": 1536, "seconds": 7500, "$locale": "en-us"}
+	res, _ := data.Replace(`{{ rank | ordinal }} {{ size | humanBytes }} {{ seconds | duration }}`)
+	assert.Equal(t, "22nd 1.5 KB 2 hours, 5 minutes", res)
+
+	data["$locale"] = "zh-CN"
+	res, _ = data.Replace(`{{ rank | ordinal }} {{ seconds | duration }}`)
+	assert.Equal(t, "第22 2小时5分钟", res)
+
+	now := time.Now()
+	assert.Equal(t, "3 minutes ago", GetHumanizeLocale("en").FromNow(now.Add(-3*time.Minute), now))
+	assert.Equal(t, "in 2 days", GetHumanizeLocale("en").FromNow(now.Add(48*time.Hour), now))
+	assert.Equal(t, "il y a 1 heure", GetHumanizeLocale("fr-FR").FromNow(now.Add(-time.Hour), now))
+
+	// The German duration is nominative, the relative time is dative
+	de := GetHumanizeLocale("de-DE")
+	assert.Equal(t, "vor 2 Tagen", de.FromNow(now.Add(-48*time.Hour), now))
+	assert.Equal(t, "in 3 Monaten", de.FromNow(now.Add(90*24*time.Hour), now))
+	assert.Equal(t, "2 Tage, 3 Stunden", de.Duration(51*time.Hour))
+	assert.Equal(t, "1 Jahr, 2 Monate", de.Duration(425*24*time.Hour))
+}
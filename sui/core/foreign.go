@@ -0,0 +1,87 @@
+package core
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// svgAttrNames the case-sensitive SVG attributes (the HTML parser lowercases the attribute names)
+var svgAttrNames = map[string]string{}
+
+func init() {
+	for _, name := range []string{
+		"attributeName", "attributeType", "baseFrequency", "baseProfile", "calcMode", "clipPathUnits",
+		"diffuseConstant", "edgeMode", "filterUnits", "glyphRef", "gradientTransform", "gradientUnits",
+		"kernelMatrix", "kernelUnitLength", "keyPoints", "keySplines", "keyTimes", "lengthAdjust",
+		"limitingConeAngle", "markerHeight", "markerUnits", "markerWidth", "maskContentUnits", "maskUnits",
+		"numOctaves", "pathLength", "patternContentUnits", "patternTransform", "patternUnits", "pointsAtX",
+		"pointsAtY", "pointsAtZ", "preserveAlpha", "preserveAspectRatio", "primitiveUnits", "refX", "refY",
+		"repeatCount", "repeatDur", "requiredExtensions", "requiredFeatures", "specularConstant",
+		"specularExponent", "spreadMethod", "startOffset", "stdDeviation", "stitchTiles", "surfaceScale",
+		"systemLanguage", "tableValues", "targetX", "targetY", "textLength", "viewBox", "viewTarget",
+		"xChannelSelector", "yChannelSelector", "zoomAndPan",
+	} {
+		svgAttrNames[strings.ToLower(name)] = name
+	}
+}
+
+// isForeign check if the node is a SVG or MathML element
+func isForeign(node *html.Node) bool {
+	return node != nil && node.Type == html.ElementNode && (node.Namespace == "svg" || node.Namespace == "math")
+}
+
+// attrName get the attribute name of the node, the SVG attributes keep the case, e.g. viewbox => viewBox
+func attrName(node *html.Node, name string) string {
+	if node == nil || node.Namespace != "svg" {
+		return name
+	}
+	if adjusted, has := svgAttrNames[strings.ToLower(name)]; has {
+		return adjusted
+	}
+	return name
+}
+
+// fragmentContext get the context to parse the fragment inserted into the parent node,
+// the SVG and MathML elements keep the namespace, e.g. the <linearGradient> included in the <svg>
+func fragmentContext(parent *html.Node) *html.Node {
+	if parent != nil && parent.Type == html.ElementNode {
+		return parent
+	}
+	return &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+}
+
+// NewForeignDocumentString create a new document, the body contents are parsed in the context of the parent node,
+// so the markup inserted into the SVG and MathML elements keeps the namespace and the case of the tag names
+func NewForeignDocumentString(htmlContent string, parent *html.Node) (*goquery.Document, error) {
+	doc, err := NewDocumentString(htmlContent)
+	if err != nil || !isForeign(parent) {
+		return doc, err
+	}
+
+	body := doc.Find("body")
+	if body.Length() == 0 {
+		return doc, nil
+	}
+
+	source, err := body.Html()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := html.ParseFragment(strings.NewReader(source), parent)
+	if err != nil {
+		return nil, err
+	}
+
+	node := body.Nodes[0]
+	for child := node.FirstChild; child != nil; child = node.FirstChild {
+		node.RemoveChild(child)
+	}
+	for _, child := range nodes {
+		node.AppendChild(child)
+	}
+	return doc, nil
+}
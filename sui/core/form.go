@@ -0,0 +1,436 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/config"
+)
+
+// FormTokenField the hidden field holds the signed form token
+const FormTokenField = "__sui_form"
+
+// The reasons of the rejected submissions
+const (
+	FormRejectToken    = "token"
+	FormRejectHoneypot = "honeypot"
+	FormRejectTooFast  = "too_fast"
+	FormRejectCaptcha  = "captcha"
+	FormRejectExpired  = "expired"
+	FormRejectReplay   = "replay"
+)
+
+// FormTokenTTL the lifetime of the form tokens
+var FormTokenTTL = 2 * time.Hour
+
+// FormGuard the bot protection setting of the s:form
+type FormGuard struct {
+	Name     string `json:"name"`
+	Honeypot string `json:"honeypot,omitempty"` // The honeypot field name, should be empty when submitted
+	MinTime  int    `json:"min_time,omitempty"` // The minimum seconds between rendering and submitting
+	Captcha  string `json:"captcha,omitempty"`  // The captcha connector name
+	Time     int64  `json:"time"`               // The rendered time (unix seconds)
+	Expires  int64  `json:"expires"`            // The expired time (unix seconds)
+	Nonce    string `json:"nonce"`              // The single-use nonce, the token can be submitted once
+}
+
+// CaptchaConnector the server-side captcha verification connector
+type CaptchaConnector struct {
+	Provider string `json:"provider"` // turnstile, hcaptcha
+	SiteKey  string `json:"sitekey"`
+	Secret   string `json:"secret"`
+}
+
+var captchaProviders = map[string]struct {
+	verify string
+	script string
+	class  string
+	field  string
+}{
+	"turnstile": {
+		verify: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+		script: "https://challenges.cloudflare.com/turnstile/v0/api.js",
+		class:  "cf-turnstile",
+		field:  "cf-turnstile-response",
+	},
+	"hcaptcha": {
+		verify: "https://api.hcaptcha.com/siteverify",
+		script: "https://js.hcaptcha.com/1/api.js",
+		class:  "h-captcha",
+		field:  "h-captcha-response",
+	},
+}
+
+var captchaConnectors = map[string]*CaptchaConnector{}
+var formRejected = map[string]map[string]uint64{}
+var formMutex sync.RWMutex
+var formSecret []byte
+var formNonces = map[string]int64{}
+var trustedProxies = []*net.IPNet{}
+var formHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// RegisterCaptcha register the captcha connector
+func RegisterCaptcha(name string, connector *CaptchaConnector) error {
+	if _, has := captchaProviders[connector.Provider]; !has {
+		return fmt.Errorf("captcha provider %s does not support", connector.Provider)
+	}
+	formMutex.Lock()
+	defer formMutex.Unlock()
+	captchaConnectors[name] = connector
+	return nil
+}
+
+// SetTrustedProxies set the trusted proxies (the IPs or the CIDRs), the proxy headers (Cf-Connecting-Ip,
+// X-Forwarded-For, X-Real-Ip) are trusted only if the request comes from them. The YAO_SUI_TRUSTED_PROXIES
+// environment variable (comma separated) is loaded by default
+func SetTrustedProxies(proxies []string) error {
+	nets := []*net.IPNet{}
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy = proxy + "/32"
+			} else {
+				proxy = proxy + "/128"
+			}
+		}
+		_, ipnet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return fmt.Errorf("trusted proxy %s error: %s", proxy, err.Error())
+		}
+		nets = append(nets, ipnet)
+	}
+
+	formMutex.Lock()
+	defer formMutex.Unlock()
+	trustedProxies = nets
+	return nil
+}
+
+func init() {
+	if proxies := os.Getenv("YAO_SUI_TRUSTED_PROXIES"); proxies != "" {
+		if err := SetTrustedProxies(strings.Split(proxies, ",")); err != nil {
+			log.Error("[SUI] %s", err.Error())
+		}
+	}
+}
+
+// GetCaptcha get the captcha connector, if not registered, read from the environment
+// YAO_SUI_<NAME>_SITEKEY, YAO_SUI_<NAME>_SECRET (the name should be the provider)
+func GetCaptcha(name string) (*CaptchaConnector, error) {
+	formMutex.RLock()
+	connector, has := captchaConnectors[name]
+	formMutex.RUnlock()
+	if has {
+		return connector, nil
+	}
+
+	if _, has := captchaProviders[name]; has {
+		prefix := fmt.Sprintf("YAO_SUI_%s_", strings.ToUpper(name))
+		if secret := os.Getenv(prefix + "SECRET"); secret != "" {
+			return &CaptchaConnector{Provider: name, SiteKey: os.Getenv(prefix + "SITEKEY"), Secret: secret}, nil
+		}
+	}
+	return nil, fmt.Errorf("captcha %s does not register", name)
+}
+
+// FormMetrics get the rejected submissions counts, form name => reason => count
+func FormMetrics() map[string]map[string]uint64 {
+	formMutex.RLock()
+	defer formMutex.RUnlock()
+	res := map[string]map[string]uint64{}
+	for name, reasons := range formRejected {
+		res[name] = map[string]uint64{}
+		for reason, count := range reasons {
+			res[name][reason] = count
+		}
+	}
+	return res
+}
+
+// VerifyForm verify the submitted values of the s:form, return the form name
+// the token is single-use, it is consumed when the submission passes the checks (the pages of the forms should not be cached)
+func VerifyForm(values map[string]interface{}, r *Request) (string, error) {
+
+	token, _ := values[FormTokenField].(string)
+	guard, err := parseFormToken(token)
+	if err != nil {
+		formReject("", FormRejectToken)
+		return "", err
+	}
+
+	if time.Now().Unix() > guard.Expires {
+		formReject(guard.Name, FormRejectExpired)
+		return guard.Name, fmt.Errorf("form %s rejected, the token is expired", guard.Name)
+	}
+
+	if guard.Honeypot != "" {
+		if v, has := values[guard.Honeypot]; has && v != nil && fmt.Sprintf("%v", v) != "" {
+			formReject(guard.Name, FormRejectHoneypot)
+			return guard.Name, fmt.Errorf("form %s rejected", guard.Name)
+		}
+	}
+
+	if guard.MinTime > 0 && time.Now().Unix()-guard.Time < int64(guard.MinTime) {
+		formReject(guard.Name, FormRejectTooFast)
+		return guard.Name, fmt.Errorf("form %s rejected, submitted too fast", guard.Name)
+	}
+
+	if guard.Captcha != "" {
+		err := verifyCaptcha(guard.Captcha, values, r)
+		if err != nil {
+			formReject(guard.Name, FormRejectCaptcha)
+			return guard.Name, err
+		}
+	}
+
+	if !formConsume(guard.Nonce, guard.Expires) {
+		formReject(guard.Name, FormRejectReplay)
+		return guard.Name, fmt.Errorf("form %s rejected, the token is used", guard.Name)
+	}
+
+	return guard.Name, nil
+}
+
+// formConsume mark the nonce as used, returns false if the nonce is used. The expired nonces are removed
+func formConsume(nonce string, expires int64) bool {
+	formMutex.Lock()
+	defer formMutex.Unlock()
+	if nonce == "" {
+		return false
+	}
+	if _, used := formNonces[nonce]; used {
+		return false
+	}
+
+	now := time.Now().Unix()
+	for key, exp := range formNonces {
+		if exp < now {
+			delete(formNonces, key)
+		}
+	}
+	formNonces[nonce] = expires
+	return true
+}
+
+func formReject(name string, reason string) {
+	formMutex.Lock()
+	defer formMutex.Unlock()
+	if _, has := formRejected[name]; !has {
+		formRejected[name] = map[string]uint64{}
+	}
+	formRejected[name][reason]++
+	log.Warn("[SUI] Form %s rejected: %s", name, reason)
+}
+
+func verifyCaptcha(name string, values map[string]interface{}, r *Request) error {
+	connector, err := GetCaptcha(name)
+	if err != nil {
+		return err
+	}
+
+	provider := captchaProviders[connector.Provider]
+	response, _ := values[provider.field].(string)
+	if response == "" {
+		return fmt.Errorf("captcha is required")
+	}
+
+	form := url.Values{"secret": {connector.Secret}, "response": {response}}
+	if ip := r.remoteIP(); ip != "" {
+		form.Set("remoteip", ip)
+	}
+
+	resp, err := formHTTPClient.PostForm(provider.verify, form)
+	if err != nil {
+		return fmt.Errorf("captcha verify error: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	var res struct {
+		Success bool     `json:"success"`
+		Errors  []string `json:"error-codes"`
+	}
+	err = jsoniter.NewDecoder(resp.Body).Decode(&res)
+	if err != nil {
+		return fmt.Errorf("captcha verify error: %s", err.Error())
+	}
+
+	if !res.Success {
+		return fmt.Errorf("captcha verify failed %s", strings.Join(res.Errors, ","))
+	}
+	return nil
+}
+
+// remoteIP get the IP of the visitor, the proxy headers are read only if the remote address is a trusted proxy
+func (r *Request) remoteIP() string {
+	if r == nil || r.Remote == "" {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(r.Remote)
+	if err != nil {
+		host = r.Remote
+	}
+	if r.Headers == nil || !trustedProxy(net.ParseIP(host)) {
+		return host
+	}
+
+	if ip := r.Headers.Get("Cf-Connecting-Ip"); ip != "" {
+		return ip
+	}
+	if ip := r.Headers.Get("X-Forwarded-For"); ip != "" {
+		return strings.TrimSpace(strings.Split(ip, ",")[0])
+	}
+	if ip := r.Headers.Get("X-Real-Ip"); ip != "" {
+		return ip
+	}
+	return host
+}
+
+func trustedProxy(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	formMutex.RLock()
+	defer formMutex.RUnlock()
+	for _, ipnet := range trustedProxies {
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse the s:form, s:form-honeypot, s:form-min-time and s:form-captcha attributes
+func (parser *TemplateParser) formElementNode(sel *goquery.Selection) {
+	defer parser.option.Timing.Start("form", "s:form")()
+	guard := FormGuard{
+		Name:     sel.AttrOr("s:form", ""),
+		Honeypot: sel.AttrOr("s:form-honeypot", ""),
+		Captcha:  sel.AttrOr("s:form-captcha", ""),
+		Time:     time.Now().Unix(),
+	}
+
+	if minTime := sel.AttrOr("s:form-min-time", ""); minTime != "" {
+		v, err := strconv.Atoi(minTime)
+		if err != nil {
+			parser.renderError(sel.Nodes[0], "s:form-min-time", minTime, fmt.Errorf("form %s min-time error: %s", guard.Name, err.Error()))
+		}
+		guard.MinTime = v
+	}
+
+	if guard.Honeypot != "" {
+		sel.AppendHtml(fmt.Sprintf(
+			`<div aria-hidden="true" style="position:absolute;left:-10000px;top:auto;width:1px;height:1px;overflow:hidden;">`+
+				`<input type="text" name="%s" value="" tabindex="-1" autocomplete="off" /></div>`,
+			html.EscapeString(guard.Honeypot),
+		))
+	}
+
+	if guard.Captcha != "" {
+		connector, err := GetCaptcha(guard.Captcha)
+		if err != nil {
+			parser.renderError(sel.Nodes[0], "s:form-captcha", guard.Captcha, err)
+		} else {
+			provider := captchaProviders[connector.Provider]
+			sel.AppendHtml(fmt.Sprintf(`<div class="%s" data-sitekey="%s"></div><script src="%s" async defer></script>`,
+				provider.class, html.EscapeString(connector.SiteKey), provider.script,
+			))
+		}
+	}
+
+	token, err := formToken(guard)
+	if err != nil {
+		parser.renderError(sel.Nodes[0], "s:form", guard.Name, err)
+		return
+	}
+	sel.AppendHtml(fmt.Sprintf(`<input type="hidden" name="%s" value="%s" />`, FormTokenField, token))
+
+	// The CSRF token of the page rendered by the sui
+	if csrf := parser.option.Request.CSRFToken(); csrf != "" {
+		sel.AppendHtml(fmt.Sprintf(`<input type="hidden" name="%s" value="%s" />`, CSRFField, csrf))
+	}
+}
+
+// formToken sign the form guard, base64(json).hmac, the nonce and the expired time are set if empty
+func formToken(guard FormGuard) (string, error) {
+	if guard.Nonce == "" {
+		nonce := make([]byte, 16)
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		guard.Nonce = hex.EncodeToString(nonce)
+	}
+	if guard.Expires == 0 {
+		guard.Expires = time.Now().Add(FormTokenTTL).Unix()
+	}
+
+	raw, err := jsoniter.Marshal(guard)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(raw)
+	return payload + "." + formSign(payload), nil
+}
+
+func parseFormToken(token string) (*FormGuard, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 || !hmac.Equal([]byte(formSign(parts[0])), []byte(parts[1])) {
+		return nil, fmt.Errorf("form token is invalid")
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("form token is invalid")
+	}
+
+	guard := &FormGuard{}
+	err = jsoniter.Unmarshal(raw, guard)
+	if err != nil {
+		return nil, fmt.Errorf("form token is invalid")
+	}
+	return guard, nil
+}
+
+func formSign(payload string) string {
+	mac := hmac.New(sha256.New, formKey())
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// formKey the signing key, derived from the JWT secret (the JWT secret is not used directly),
+// or a random key for the current process
+func formKey() []byte {
+	if config.Conf.JWTSecret != "" {
+		mac := hmac.New(sha256.New, []byte(config.Conf.JWTSecret))
+		mac.Write([]byte("yao.sui.form"))
+		return mac.Sum(nil)
+	}
+
+	formMutex.Lock()
+	defer formMutex.Unlock()
+	if formSecret == nil {
+		formSecret = make([]byte, 32)
+		rand.Read(formSecret)
+	}
+	return formSecret
+}
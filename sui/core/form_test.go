@@ -0,0 +1,83 @@
+package core
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/yao/config"
+)
+
+func TestVerifyForm(t *testing.T) {
+	token, err := formToken(FormGuard{Name: "contact", Honeypot: "website", Time: time.Now().Unix()})
+	if err != nil {
+		t.Fatalf("formToken error: %v", err)
+	}
+
+	name, err := VerifyForm(map[string]interface{}{FormTokenField: token, "website": ""}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "contact", name)
+
+	_, err = VerifyForm(map[string]interface{}{FormTokenField: token, "website": "http://spam"}, nil)
+	assert.NotNil(t, err)
+
+	_, err = VerifyForm(map[string]interface{}{FormTokenField: token + "0"}, nil)
+	assert.NotNil(t, err)
+
+	token, _ = formToken(FormGuard{Name: "signup", MinTime: 60, Time: time.Now().Unix()})
+	_, err = VerifyForm(map[string]interface{}{FormTokenField: token}, nil)
+	assert.NotNil(t, err)
+
+	metrics := FormMetrics()
+	assert.Equal(t, uint64(1), metrics["contact"][FormRejectHoneypot])
+	assert.Equal(t, uint64(1), metrics["signup"][FormRejectTooFast])
+}
+
+func TestVerifyFormToken(t *testing.T) {
+	// The expired token
+	token, _ := formToken(FormGuard{Name: "expired", Time: time.Now().Unix(), Expires: time.Now().Add(-time.Minute).Unix()})
+	_, err := VerifyForm(map[string]interface{}{FormTokenField: token}, nil)
+	assert.NotNil(t, err)
+
+	// The token is single-use
+	token, _ = formToken(FormGuard{Name: "once", Time: time.Now().Unix()})
+	_, err = VerifyForm(map[string]interface{}{FormTokenField: token}, nil)
+	assert.Nil(t, err)
+	_, err = VerifyForm(map[string]interface{}{FormTokenField: token}, nil)
+	assert.NotNil(t, err)
+
+	metrics := FormMetrics()
+	assert.Equal(t, uint64(1), metrics["expired"][FormRejectExpired])
+	assert.Equal(t, uint64(1), metrics["once"][FormRejectReplay])
+
+	// The proxy headers are trusted only from the trusted proxies
+	headers := url.Values{"X-Forwarded-For": {"1.2.3.4, 10.0.0.1"}}
+	r := &Request{Remote: "10.0.0.1:5000", Headers: headers}
+	assert.Equal(t, "10.0.0.1", r.remoteIP())
+
+	assert.Nil(t, SetTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"}))
+	defer SetTrustedProxies(nil)
+	assert.Equal(t, "1.2.3.4", r.remoteIP())
+	assert.Equal(t, "8.8.8.8", (&Request{Remote: "8.8.8.8:5000", Headers: headers}).remoteIP())
+	assert.NotNil(t, SetTrustedProxies([]string{"bad"}))
+
+	// The signing key is derived from the JWT secret
+	secret := config.Conf.JWTSecret
+	config.Conf.JWTSecret = "jwt-secret"
+	defer func() { config.Conf.JWTSecret = secret }()
+	assert.NotEqual(t, []byte("jwt-secret"), formKey())
+	assert.Len(t, formKey(), 32)
+}
+
+func TestFormElementError(t *testing.T) {
+	parser := NewTemplateParser(Data{}, &ParserOption{Fragment: true})
+	html, err := parser.Render(`<form s:form="contact" s:form-min-time="abc" s:form-captcha="unknown"></form>`)
+	assert.Nil(t, err)
+	assert.Contains(t, html, FormTokenField)
+
+	errs := parser.Errors()
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "s:form-min-time", errs[0].Directive)
+	assert.Equal(t, "s:form-captcha", errs[1].Directive)
+}
@@ -0,0 +1,250 @@
+package core
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/store"
+	"github.com/yaoapp/kun/log"
+	"golang.org/x/net/html"
+)
+
+// FragmentStore the store of the s:cache fragments (the gou store satisfies it)
+type FragmentStore interface {
+	Get(key string) (interface{}, bool)
+	Set(key string, value interface{}, ttl time.Duration) error
+}
+
+// DefaultFragmentTTL the default ttl of the s:cache fragments
+var DefaultFragmentTTL = 60 * time.Second
+
+// MaxFragmentItems the max items of the in-memory fragment store
+var MaxFragmentItems = 4096
+
+var fragmentStore FragmentStore = &memoryFragmentStore{items: map[string]memoryFragment{}}
+
+// SetFragmentStore set the default store of the s:cache fragments
+func SetFragmentStore(s FragmentStore) {
+	fragmentStore = s
+}
+
+type fragmentCache struct {
+	sel     *goquery.Selection
+	key     string
+	ttl     time.Duration
+	html    string
+	scripts int            // the offset of the just-in-time component scripts when the fragment starts
+	styles  int            // the offset of the just-in-time component styles when the fragment starts
+	assets  *fragmentValue // the assets of the fragment, set when the children are rendered
+}
+
+// fragmentValue the cached fragment, the html and the assets of the just-in-time components in it
+type fragmentValue struct {
+	HTML    string       `json:"html"`
+	Scripts []ScriptNode `json:"scripts,omitempty"`
+	Styles  []StyleNode  `json:"styles,omitempty"`
+}
+
+// cacheNode the s:cache="key" s:cache-ttl="60s" directive, return true if the fragment is cached
+// the rendered html is cached by the route, locale, theme and the key (the expressions are evaluated), and by
+// the country of the visitor if the fragment has the s:geo nodes, the device if the fragment uses the $device
+func (parser *TemplateParser) cacheNode(sel *goquery.Selection) bool {
+	defer parser.option.Timing.Start("cache", "s:cache")()
+	if parser.option.DisableCache || parser.option.Debug || parser.option.Editor || parser.option.Preview {
+		return false
+	}
+
+	key, values := parser.data.ReplaceGuard(sel.AttrOr("s:cache", ""), parser.guard)
+	for _, value := range values {
+		if value.Error != nil {
+			log.Warn("[SUI] s:cache %s: %s", sel.AttrOr("s:cache", ""), value.Error.Error())
+			return false
+		}
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(fmt.Sprintf("%s|%v|%v|%s", parser.option.Route, parser.option.Locale, parser.option.Theme, key)))
+	if _, has := sel.Attr("s:geo"); has || sel.Find("[s\\:geo]").Length() > 0 {
+		h.Write([]byte("|" + parser.country()))
+	}
+	if raw, err := goquery.OuterHtml(sel); err == nil && strings.Contains(raw, deviceKey) {
+		h.Write([]byte("|" + parser.option.Request.Device().String()))
+	}
+	fc := &fragmentCache{sel: sel, key: fmt.Sprintf("fragment:%x", h.Sum64()), ttl: fragmentTTL(sel.AttrOr("s:cache-ttl", ""))}
+
+	if value, has := parser.fragmentStore().Get(fc.key); has {
+		if cached, ok := fragmentDecode(value); ok {
+			fc.html = cached.HTML
+			parser.addContextAssets(cached.Scripts, cached.Styles)
+			parser.parsed(sel)
+			parser.fragments = append(parser.fragments, fc)
+			return true
+		}
+	}
+
+	if parser.context != nil {
+		fc.scripts, fc.styles = len(parser.context.scripts), len(parser.context.styles)
+	}
+	parser.fragments = append(parser.fragments, fc)
+	return false
+}
+
+// endFragment collect the assets of the rendered fragment, called when the children of the node are rendered
+func (parser *TemplateParser) endFragment(node *html.Node) {
+	for _, fc := range parser.fragments {
+		if fc.html != "" || fc.assets != nil || len(fc.sel.Nodes) == 0 || fc.sel.Nodes[0] != node {
+			continue
+		}
+		fc.assets = &fragmentValue{}
+		if parser.context != nil && fc.scripts <= len(parser.context.scripts) && fc.styles <= len(parser.context.styles) {
+			fc.assets.Scripts = append([]ScriptNode{}, parser.context.scripts[fc.scripts:]...)
+			fc.assets.Styles = append([]StyleNode{}, parser.context.styles[fc.styles:]...)
+		}
+		return
+	}
+}
+
+// fragmentDecode decode the cached fragment, the html string of the earlier versions is accepted
+func fragmentDecode(value interface{}) (*fragmentValue, bool) {
+	raw, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	if !strings.HasPrefix(raw, "{") {
+		return &fragmentValue{HTML: raw}, true
+	}
+
+	cached := &fragmentValue{}
+	if err := jsoniter.UnmarshalFromString(raw, cached); err != nil || cached.HTML == "" {
+		return nil, false
+	}
+	return cached, true
+}
+
+// isCacheHit check if the node is restored from the fragment cache
+func (parser *TemplateParser) isCacheHit(sel *goquery.Selection) bool {
+	return sel.AttrOr("s:cache-hit", "") == "true"
+}
+
+// flushFragments save the rendered fragments and restore the cached ones, after the replacement phase
+func (parser *TemplateParser) flushFragments() {
+	for _, fc := range parser.fragments {
+		if len(fc.sel.Nodes) == 0 {
+			continue
+		}
+
+		// Save the rendered fragment with the assets
+		if fc.html == "" {
+			html, err := goquery.OuterHtml(fc.sel)
+			if err != nil {
+				log.Error("[SUI] s:cache %s", err.Error())
+				continue
+			}
+
+			cached := fragmentValue{HTML: html}
+			if fc.assets != nil {
+				cached.Scripts, cached.Styles = fc.assets.Scripts, fc.assets.Styles
+			}
+			raw, err := jsoniter.MarshalToString(cached)
+			if err != nil {
+				log.Error("[SUI] s:cache %s", err.Error())
+				continue
+			}
+			err = parser.fragmentStore().Set(fc.key, raw, fc.ttl)
+			if err != nil {
+				log.Error("[SUI] s:cache %s", err.Error())
+			}
+			continue
+		}
+
+		// Restore the cached fragment
+		node := fc.sel.Nodes[0]
+		nodes, err := html.ParseFragment(strings.NewReader(fc.html), fragmentContext(node.Parent))
+		if err != nil {
+			log.Error("[SUI] s:cache %s", err.Error())
+			continue
+		}
+		fc.sel.ReplaceWithNodes(nodes...)
+	}
+	parser.fragments = nil
+}
+
+func (parser *TemplateParser) fragmentStore() FragmentStore {
+	if parser.option.CacheStore != "" {
+		if s, has := store.Pools[parser.option.CacheStore]; has {
+			return s
+		}
+	}
+	return fragmentStore
+}
+
+// fragmentTTL parse the ttl, e.g. "60s", "5m", "300" (seconds)
+func fragmentTTL(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return DefaultFragmentTTL
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil {
+		log.Warn("[SUI] s:cache-ttl %s: %s", value, err.Error())
+		return DefaultFragmentTTL
+	}
+	return ttl
+}
+
+type memoryFragment struct {
+	value  interface{}
+	expire time.Time
+}
+
+// memoryFragmentStore the default in-memory fragment store
+type memoryFragmentStore struct {
+	items map[string]memoryFragment
+	mutex sync.RWMutex
+}
+
+func (s *memoryFragmentStore) Get(key string) (interface{}, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	item, has := s.items[key]
+	if !has || time.Now().After(item.expire) {
+		return nil, false
+	}
+	return item.value, true
+}
+
+func (s *memoryFragmentStore) Set(key string, value interface{}, ttl time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.items) >= MaxFragmentItems {
+		now := time.Now()
+		for k, item := range s.items {
+			if now.After(item.expire) {
+				delete(s.items, k)
+			}
+		}
+
+		// Still full, drop an arbitrary item
+		if len(s.items) >= MaxFragmentItems {
+			for k := range s.items {
+				delete(s.items, k)
+				break
+			}
+		}
+	}
+
+	s.items[key] = memoryFragment{value: value, expire: time.Now().Add(ttl)}
+	return nil
+}
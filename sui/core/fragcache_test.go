@@ -0,0 +1,74 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFragmentCache(t *testing.T) {
+	store := &memoryFragmentStore{items: map[string]memoryFragment{}}
+	defer SetFragmentStore(fragmentStore)
+	SetFragmentStore(store)
+
+	source := `<html><head></head><body><div s:cache="news" s:cache-ttl="1s"><p>{{ title }}</p></div></body></html>`
+	render := func(title string) (string, *TemplateParser) {
+		parser := NewTemplateParser(Data{"title": title}, &ParserOption{Route: "/index", Request: &Request{}})
+		html, err := parser.Render(source)
+		assert.Nil(t, err)
+		return strings.Split(html, "var __sui_data")[0], parser
+	}
+
+	// Miss, the rendered fragment is saved
+	html, _ := render("First")
+	assert.Contains(t, html, "<p>First</p>")
+	assert.Len(t, store.items, 1)
+
+	// Hit, the cached fragment is restored
+	html, _ = render("Second")
+	assert.Contains(t, html, "<p>First</p>")
+	assert.NotContains(t, html, "Second")
+
+	// Expired
+	for key, item := range store.items {
+		item.expire = time.Now().Add(-time.Second)
+		store.items[key] = item
+	}
+	html, _ = render("Third")
+	assert.Contains(t, html, "<p>Third</p>")
+}
+
+func TestFragmentCacheAssets(t *testing.T) {
+	store := &memoryFragmentStore{items: map[string]memoryFragment{}}
+	defer SetFragmentStore(fragmentStore)
+	SetFragmentStore(store)
+
+	doc, err := NewDocumentString(`<div s:cache="card"><span>Card</span></div>`)
+	if err != nil {
+		t.Fatalf("NewDocumentString error: %v", err)
+	}
+
+	// Miss, the assets of the just-in-time components rendered in the fragment are cached with it
+	parser := NewTemplateParser(Data{}, &ParserOption{Route: "/index"})
+	parser.addContextAssets([]ScriptNode{{Source: "function Before(){}", Component: "Before"}}, nil)
+	sel := doc.Find("div")
+	assert.False(t, parser.cacheNode(sel))
+	parser.addContextAssets([]ScriptNode{{Source: "function Card(){}", Component: "Card"}}, []StyleNode{{Source: ".card{}", Component: "Card"}})
+	parser.endFragment(sel.Nodes[0])
+	parser.flushFragments()
+
+	// Hit, the assets are restored
+	doc, _ = NewDocumentString(`<div s:cache="card"><span>Changed</span></div>`)
+	parser = NewTemplateParser(Data{}, &ParserOption{Route: "/index"})
+	assert.True(t, parser.cacheNode(doc.Find("div")))
+	assert.Len(t, parser.context.scripts, 1)
+	assert.Equal(t, "function Card(){}", parser.context.scripts[0].Source)
+	assert.Len(t, parser.context.styles, 1)
+
+	// The html of the earlier versions
+	cached, ok := fragmentDecode("<div>Legacy</div>")
+	assert.True(t, ok)
+	assert.Equal(t, "<div>Legacy</div>", cached.HTML)
+}
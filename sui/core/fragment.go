@@ -0,0 +1,163 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// DefaultDoctype the doctype of the synthesized document
+const DefaultDoctype = "html"
+
+var fragmentTagRe = regexp.MustCompile(`^\s*(?:<!--[\s\S]*?-->\s*)*<([a-zA-Z][a-zA-Z0-9-]*)`)
+
+// fragmentContexts the parse context of the fragments start with the table and the select parts,
+// they are dropped by the html parser in the body context
+var fragmentContexts = map[string]atom.Atom{
+	"tr":       atom.Tbody,
+	"td":       atom.Tr,
+	"th":       atom.Tr,
+	"tbody":    atom.Table,
+	"thead":    atom.Table,
+	"tfoot":    atom.Table,
+	"caption":  atom.Table,
+	"colgroup": atom.Table,
+	"col":      atom.Colgroup,
+	"option":   atom.Select,
+	"optgroup": atom.Select,
+}
+
+// NewDocumentFragment create a new document from the html fragment,
+// keep the structure of the input, no html/head/body synthesis
+func NewDocumentFragment(htmlContent string) (*goquery.Document, error) {
+	htmlContent = closeElements(htmlContent)
+	context := sourceContext(htmlContent)
+	nodes, err := html.ParseFragment(strings.NewReader(htmlContent), context)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &html.Node{Type: html.DocumentNode}
+	for _, node := range nodes {
+		root.AppendChild(node)
+	}
+	return goquery.NewDocumentFromNode(root), nil
+}
+
+// sourceContext the parse context of the fragment source, chosen by the first tag, the body by default
+func sourceContext(htmlContent string) *html.Node {
+	name := ""
+	if match := fragmentTagRe.FindStringSubmatch(htmlContent); match != nil {
+		name = strings.ToLower(match[1])
+	}
+
+	context, has := fragmentContexts[name]
+	if !has {
+		context = atom.Body
+	}
+	return &html.Node{Type: html.ElementNode, Data: context.String(), DataAtom: context}
+}
+
+// renderFragment render the html fragment (email partials, htmx fragments, CMS blocks)
+// the scripts, styles and the data are not injected
+func (parser *TemplateParser) renderFragment(source string) (string, error) {
+
+	var doc *goquery.Document
+	var err error
+
+	source, err = Delimit(source, parser.option.Delimiters)
+	if err != nil {
+		return "", err
+	}
+
+	// Keep the full document as it is
+	source = trimTokens(stripComments(source))
+	if strings.Contains(source, "<html") {
+		doc, err = NewDocumentString(source)
+	} else {
+		doc, err = NewDocumentFragment(source)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	parser.locale = parser.Locale()
+
+	err = parser.RenderSelection(doc.Selection)
+	if err != nil {
+		return "", err
+	}
+
+	if parser.option.Request != nil || parser.option.Preview {
+		doc.Find("[sui-hide]").Remove()
+		parser.Tidy(doc.Selection)
+	}
+	if parser.option.Minify {
+		parser.minify(doc.Selection)
+	}
+	return doc.Html()
+}
+
+// RenderFragment render the subtree of the selector in the page with the current data, for the
+// htmx/turbo-style fragment requests. returns the html of the subtree and the updated mapping
+// of the bindings, the scripts, styles and the data are not injected
+func (parser *TemplateParser) RenderFragment(selector string, source string) (string, map[string]Mapping, error) {
+	if !strings.Contains(source, "<html") {
+		source = fmt.Sprintf(`<html lang="en-us">%s</html>`, source)
+	}
+
+	doc, err := parser.document(source)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sel := doc.Find(selector).First()
+	if sel.Length() == 0 {
+		return "", nil, fmt.Errorf("the fragment %s is not found", selector)
+	}
+
+	parser.locale = parser.Locale()
+	err = parser.RenderSelection(sel)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if parser.option.Request != nil || parser.option.Preview {
+		if _, hidden := sel.Attr("sui-hide"); hidden {
+			return "", parser.Mapping(), nil
+		}
+		sel.Find("[sui-hide]").Remove()
+		parser.Tidy(sel)
+	}
+	if parser.option.Minify {
+		parser.minify(sel)
+	}
+
+	html, err := goquery.OuterHtml(sel)
+	if err != nil {
+		return "", nil, err
+	}
+	return restoreDelimiters(html), parser.Mapping(), nil
+}
+
+// Mapping get a copy of the mapping of the bindings
+func (parser *TemplateParser) Mapping() map[string]Mapping {
+	mapping := map[string]Mapping{}
+	for key, value := range parser.mapping {
+		mapping[key] = value
+	}
+	return mapping
+}
+
+// doctype the doctype declaration of the synthesized document
+func (parser *TemplateParser) doctype() string {
+	doctype := strings.TrimSpace(parser.option.Doctype)
+	if doctype == "" {
+		doctype = DefaultDoctype
+	}
+	return "<!DOCTYPE " + doctype + ">"
+}
@@ -0,0 +1,234 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/yaoapp/gou/process"
+)
+
+// ProcessFunction the expression function proxies to the process, set in the functions section of the sui DSL
+//
+//	"functions": {
+//	  "Price": {"process": "utils.price.Format"},
+//	  "Stock": {"process": "models.stock.Find", "volatile": true}
+//	}
+//
+// the templates call the function by the name, {{ Price(item.price) }}, or by the process, {{ process("utils.price.Format", item.price) }}.
+// only the registered processes can be called, the results are memoized in the rendering (unless volatile) and the calls
+// are limited by the MaxProcessCalls of the render limits
+type ProcessFunction struct {
+	Process  string `json:"process"`
+	Volatile bool   `json:"volatile,omitempty"` // the result is not memoized, e.g. the stock or the time
+}
+
+// processCallsKey the data key of the process calls of the rendering
+const processCallsKey = "$__calls"
+
+// processCalls the process calls of the rendering, shared by the components
+type processCalls struct {
+	sid   string
+	max   int
+	count int
+	memo  map[string]interface{}
+	mutex sync.Mutex
+}
+
+var processFunctions = map[string]*ProcessFunction{}
+var processFunctionsMutex sync.RWMutex
+var processFunctionNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// RegisterProcessFunction register the expression function proxies to the process
+func RegisterProcessFunction(name string, fn *ProcessFunction) error {
+	if !processFunctionNameRe.MatchString(name) {
+		return fmt.Errorf("the function name %s is invalid", name)
+	}
+	if fn == nil || strings.TrimSpace(fn.Process) == "" {
+		return fmt.Errorf("the function %s should have the process", name)
+	}
+	if name == "process" || name == "P_" || strings.HasPrefix(name, "__") || builtinFunctions[name] {
+		return fmt.Errorf("the function %s is the built-in function", name)
+	}
+
+	processFunctionsMutex.Lock()
+	defer processFunctionsMutex.Unlock()
+	processFunctions[name] = fn
+	return nil
+}
+
+// builtinFunctions the built-in expression functions can not be overridden
+var builtinFunctions = map[string]bool{
+	"True": true, "False": true, "Empty": true, "Truncate": true, "TruncateWords": true,
+	"Ellipsis": true, "Decimal": true, "LocaleURL": true,
+}
+
+// processFunction get the function of the process, the registered processes only
+func processFunction(name string) (*ProcessFunction, bool) {
+	processFunctionsMutex.RLock()
+	defer processFunctionsMutex.RUnlock()
+	for _, fn := range processFunctions {
+		if strings.EqualFold(fn.Process, name) {
+			return fn, true
+		}
+	}
+	return nil, false
+}
+
+// newProcessCalls create the process calls of the rendering, nil if no function is registered
+func newProcessCalls(option *ParserOption) *processCalls {
+	processFunctionsMutex.RLock()
+	size := len(processFunctions)
+	processFunctionsMutex.RUnlock()
+	if size == 0 {
+		return nil
+	}
+
+	calls := &processCalls{max: option.Limits.maxProcessCalls(), memo: map[string]interface{}{}}
+	if option.Request != nil {
+		calls.sid = option.Request.Sid
+	}
+	return calls
+}
+
+// processFunctionCalls rewrite the calls of the process functions, the strings are kept
+// e.g. Price(item.price) => __process($__calls, "utils.price.Format", item.price)
+func processFunctionCalls(stmt string) string {
+	processFunctionsMutex.RLock()
+	defer processFunctionsMutex.RUnlock()
+	if len(processFunctions) == 0 || !strings.Contains(stmt, "(") {
+		return stmt
+	}
+
+	var b strings.Builder
+	var quote byte = 0
+	for i := 0; i < len(stmt); i++ {
+		c := stmt[i]
+		if quote != 0 {
+			b.WriteByte(c)
+			if c == '\\' && i+1 < len(stmt) {
+				i++
+				b.WriteByte(stmt[i])
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		if c == '"' || c == '\'' || c == '`' {
+			quote = c
+			b.WriteByte(c)
+			continue
+		}
+
+		// The identifier followed by the (, not the member or the variable, e.g. item.Price(), $Price()
+		if isIdentStart(c) && (i == 0 || !isIdentPart(stmt[i-1]) && stmt[i-1] != '.' && stmt[i-1] != '$') {
+			j := i
+			for j < len(stmt) && isIdentPart(stmt[j]) {
+				j++
+			}
+			name := stmt[i:j]
+			k := j
+			for k < len(stmt) && stmt[k] == ' ' {
+				k++
+			}
+			if k < len(stmt) && stmt[k] == '(' {
+				args := ""
+				if rest := strings.TrimSpace(stmt[k+1:]); !strings.HasPrefix(rest, ")") {
+					args = ", "
+				}
+				if name == "process" {
+					b.WriteString("__process(" + processCallsKey + args)
+					i = k
+					continue
+				}
+				if fn, has := processFunctions[name]; has {
+					fmt.Fprintf(&b, "__process(%s, %q%s", processCallsKey, fn.Process, args)
+					i = k
+					continue
+				}
+			}
+			b.WriteString(name)
+			i = j - 1
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+// _processFunction the function called by the process functions, __process($__calls, name, args...)
+func _processFunction(args ...any) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("process(name, args...) expects the process name")
+	}
+
+	calls, ok := args[0].(*processCalls)
+	if !ok || calls == nil {
+		return nil, fmt.Errorf("the process functions are not available, please register the functions in the sui")
+	}
+
+	name, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("process(name, args...) the name should be a string, got %T", args[1])
+	}
+
+	fn, has := processFunction(name)
+	if !has {
+		return nil, fmt.Errorf("the process %s is not registered as a function", name)
+	}
+	return calls.call(fn, args[2:])
+}
+
+// call call the process, the result of the same arguments is returned from the memo
+func (calls *processCalls) call(fn *ProcessFunction, args []interface{}) (interface{}, error) {
+	key := ""
+	if !fn.Volatile {
+		raw, err := jsonStable.MarshalToString(args)
+		if err == nil {
+			key = strings.ToLower(fn.Process) + ":" + raw
+		}
+	}
+
+	calls.mutex.Lock()
+	if key != "" {
+		if res, has := calls.memo[key]; has {
+			calls.mutex.Unlock()
+			return res, nil
+		}
+	}
+	if calls.count >= calls.max {
+		calls.mutex.Unlock()
+		return nil, fmt.Errorf("the process calls exceed the limit of %d", calls.max)
+	}
+	calls.count++
+	calls.mutex.Unlock()
+
+	p, err := process.Of(fn.Process, args...)
+	if err != nil {
+		return nil, err
+	}
+	if calls.sid != "" {
+		p.WithSID(calls.sid)
+	}
+	res, err := p.Exec()
+	if err != nil {
+		return nil, err
+	}
+
+	if key != "" {
+		calls.mutex.Lock()
+		calls.memo[key] = res
+		calls.mutex.Unlock()
+	}
+	return res, nil
+}
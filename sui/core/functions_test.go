@@ -0,0 +1,66 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/gou/process"
+)
+
+func TestProcessFunctionCalls(t *testing.T) {
+	assert.NotNil(t, RegisterProcessFunction("__Price", &ProcessFunction{Process: "utils.price.Format"}))
+	assert.NotNil(t, RegisterProcessFunction("1Price", &ProcessFunction{Process: "utils.price.Format"}))
+	assert.NotNil(t, RegisterProcessFunction("True", &ProcessFunction{Process: "utils.price.Format"}))
+	assert.NotNil(t, RegisterProcessFunction("Price", nil))
+
+	assert.Nil(t, RegisterProcessFunction("Price", &ProcessFunction{Process: "utils.price.Format"}))
+	defer unregisterProcessFunction("Price")
+
+	assert.Equal(t, `__process($__calls, "utils.price.Format", item.price)`, processFunctionCalls(`Price(item.price)`))
+	assert.Equal(t, `__process($__calls, "utils.price.Format")`, processFunctionCalls(`Price()`))
+	assert.Equal(t, `__process($__calls, "utils.price.Format", 1)`, processFunctionCalls(`process("utils.price.Format", 1)`))
+	assert.Equal(t, `item.Price(1) ~ 'Price(1)'`, processFunctionCalls(`item.Price(1) ~ 'Price(1)'`))
+	assert.Equal(t, `MyPrice(1)`, processFunctionCalls(`MyPrice(1)`))
+}
+
+func TestParserProcessFunctions(t *testing.T) {
+	calls := 0
+	process.Register("utils.price.Format", func(p *process.Process) interface{} {
+		calls++
+		return fmt.Sprintf("$%v", p.Args[0])
+	})
+	assert.Nil(t, RegisterProcessFunction("Price", &ProcessFunction{Process: "utils.price.Format"}))
+	defer unregisterProcessFunction("Price")
+
+	data := Data{"items": []interface{}{
+		map[string]interface{}{"price": 1}, map[string]interface{}{"price": 2}, map[string]interface{}{"price": 1},
+	}}
+	parser := NewTemplateParser(data, &ParserOption{Request: &Request{}})
+	html, err := parser.Render(`<html><body><ul><li s:for="items" s:for-item="item">{{ Price(item.price) }}</li></ul>` +
+		`<p>{{ process("utils.price.Format", 2) }}</p></body></html>`)
+	assert.Nil(t, err)
+	assert.Empty(t, parser.Errors())
+	assert.Contains(t, html, `<li>$1</li><li>$2</li><li>$1</li>`)
+	assert.Contains(t, html, `<p>$2</p>`)
+	assert.NotContains(t, html, processCallsKey)
+	assert.Equal(t, 2, calls) // memoized
+
+	// The call budget of the rendering
+	parser = NewTemplateParser(data, &ParserOption{Request: &Request{}, Limits: &RenderLimits{MaxProcessCalls: 1}})
+	html, err = parser.Render(`<html><body><ul><li s:for="items" s:for-item="item">{{ Price(item.price) }}</li></ul></body></html>`)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `<li>$1</li><li></li><li>$1</li>`)
+
+	// The processes not registered as the functions
+	parser = NewTemplateParser(data, &ParserOption{Request: &Request{}})
+	html, err = parser.Render(`<html><body><p>{{ process("utils.other", 2) }}</p></body></html>`)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `<p></p>`)
+}
+
+func unregisterProcessFunction(name string) {
+	processFunctionsMutex.Lock()
+	defer processFunctionsMutex.Unlock()
+	delete(processFunctions, name)
+}
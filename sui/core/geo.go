@@ -0,0 +1,177 @@
+package core
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/yaoapp/kun/log"
+)
+
+// GeoHeaders the headers of the visitor country set by the CDN or the load balancer, the headers are trusted
+// only if the request is sent by a trusted proxy, see SetTrustedProxies
+var GeoHeaders = []string{"Cf-Ipcountry", "Cloudfront-Viewer-Country", "X-Vercel-Ip-Country", "X-Country-Code"}
+
+// GeoProvider the geo-IP provider, return the ISO 3166-1 alpha-2 country code of the visitor IP
+type GeoProvider func(ip net.IP) (string, error)
+
+var geoProvider GeoProvider = nil
+var geoRegions = map[string][]string{
+	"EU":  {"AT", "BE", "BG", "HR", "CY", "CZ", "DK", "EE", "FI", "FR", "DE", "GR", "HU", "IE", "IT", "LV", "LT", "LU", "MT", "NL", "PL", "PT", "RO", "SK", "SI", "ES", "SE"},
+	"EEA": {"AT", "BE", "BG", "HR", "CY", "CZ", "DK", "EE", "FI", "FR", "DE", "GR", "HU", "IE", "IT", "LV", "LT", "LU", "MT", "NL", "PL", "PT", "RO", "SK", "SI", "ES", "SE", "IS", "LI", "NO"},
+	"UK":  {"GB"},
+}
+var geoMutex sync.RWMutex
+
+// SetGeoProvider set the geo-IP provider, the country of the visitor is resolved by the provider if the request
+// has no trusted geo header. nil to remove the provider
+func SetGeoProvider(provider GeoProvider) {
+	geoMutex.Lock()
+	defer geoMutex.Unlock()
+	geoProvider = provider
+}
+
+// RegisterGeoRegion register the region of the s:geo directive, the countries are the ISO 3166-1 alpha-2 codes.
+// nil to remove the region
+func RegisterGeoRegion(name string, countries []string) {
+	geoMutex.Lock()
+	defer geoMutex.Unlock()
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if countries == nil {
+		delete(geoRegions, name)
+		return
+	}
+
+	codes := []string{}
+	for _, country := range countries {
+		codes = append(codes, strings.ToUpper(strings.TrimSpace(country)))
+	}
+	geoRegions[name] = codes
+}
+
+// GeoCountry resolve the country of the visitor, the trusted geo header first, then the geo-IP provider.
+// return empty if the country is unknown
+func (r *Request) GeoCountry() string {
+	if r == nil {
+		return ""
+	}
+
+	if r.Headers != nil && r.Remote != "" {
+		host, _, err := net.SplitHostPort(r.Remote)
+		if err != nil {
+			host = r.Remote
+		}
+		if trustedProxy(net.ParseIP(host)) {
+			for _, name := range GeoHeaders {
+				if country := geoCode(r.Headers.Get(name)); country != "" {
+					return country
+				}
+			}
+		}
+	}
+
+	geoMutex.RLock()
+	provider := geoProvider
+	geoMutex.RUnlock()
+	if provider == nil {
+		return ""
+	}
+
+	ip := net.ParseIP(r.remoteIP())
+	if ip == nil {
+		return ""
+	}
+
+	country, err := provider(ip)
+	if err != nil {
+		log.Warn("[SUI] geo provider %s: %s", ip.String(), err.Error())
+		return ""
+	}
+	return geoCode(country)
+}
+
+// geoCode normalize the country code, the unknown codes of the CDN (XX, T1 the Tor network) are empty
+func geoCode(country string) string {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if len(country) != 2 || country == "XX" || country == "T1" {
+		return ""
+	}
+	return country
+}
+
+// geoMatch check if the country is in one of the regions or the countries, the "*" matches any known country
+func geoMatch(country string, regions []string) bool {
+	if country == "" {
+		return false
+	}
+
+	geoMutex.RLock()
+	defer geoMutex.RUnlock()
+	for _, region := range regions {
+		if region == "*" || region == country {
+			return true
+		}
+		for _, code := range geoRegions[region] {
+			if code == country {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// geoNode the s:geo directive, the node is rendered for the visitors of the regions or the countries only,
+// return false if the node is hidden. the regions start with "!" exclude the visitors
+//
+//	<div s:geo="EU,UK">The cookie notice</div>
+//	<div s:geo="!US">The international pricing</div>
+//
+// the visitors of the unknown country see the nodes without the regions only. the page varies by the country,
+// see VaryGeo
+func (parser *TemplateParser) geoNode(sel *goquery.Selection) bool {
+	parser.geo = true
+	value, _ := parser.data.ReplaceGuard(sel.AttrOr("s:geo", ""), parser.guard)
+
+	include := []string{}
+	exclude := []string{}
+	for _, region := range strings.Split(value, ",") {
+		region = strings.ToUpper(strings.TrimSpace(region))
+		if region == "" {
+			continue
+		}
+		if strings.HasPrefix(region, "!") {
+			exclude = append(exclude, strings.TrimSpace(region[1:]))
+			continue
+		}
+		include = append(include, region)
+	}
+
+	if len(include) == 0 && len(exclude) == 0 {
+		parser.renderError(sel.Nodes[0], "s:geo", value, fmt.Errorf("the regions are required"))
+		return true
+	}
+
+	country := parser.country()
+	visible := (len(include) == 0 || geoMatch(country, include)) && !geoMatch(country, exclude)
+	if !visible {
+		parser.parsed(sel)
+		parser.hide(sel)
+	}
+	return visible
+}
+
+// country the country of the visitor, resolved by the api when the request is created
+func (parser *TemplateParser) country() string {
+	if parser.option == nil || parser.option.Request == nil {
+		return ""
+	}
+	return parser.option.Request.Country
+}
+
+// VaryGeo check if the rendered page varies by the country of the visitor (has the s:geo nodes),
+// the shared caches should key the page by the country
+func (parser *TemplateParser) VaryGeo() bool {
+	return parser.geo
+}
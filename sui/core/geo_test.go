@@ -0,0 +1,75 @@
+package core
+
+import (
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeoCountry(t *testing.T) {
+	assert.Nil(t, SetTrustedProxies([]string{"10.0.0.0/8"}))
+	defer SetTrustedProxies(nil)
+
+	// The trusted header
+	r := &Request{Remote: "10.0.0.1:1234", Headers: url.Values{"Cf-Ipcountry": {"de"}}}
+	assert.Equal(t, "DE", r.GeoCountry())
+
+	// The header of the untrusted client
+	r = &Request{Remote: "203.0.113.1:1234", Headers: url.Values{"Cf-Ipcountry": {"DE"}}}
+	assert.Equal(t, "", r.GeoCountry())
+
+	// The geo-IP provider
+	SetGeoProvider(func(ip net.IP) (string, error) { return "gb", nil })
+	defer SetGeoProvider(nil)
+	assert.Equal(t, "GB", r.GeoCountry())
+
+	r = &Request{Remote: "10.0.0.1:1234", Headers: url.Values{"Cf-Ipcountry": {"XX"}}}
+	assert.Equal(t, "GB", r.GeoCountry())
+}
+
+func TestParserGeo(t *testing.T) {
+	RegisterGeoRegion("Nordics", []string{"se", "no", "dk", "fi", "is"})
+	defer RegisterGeoRegion("Nordics", nil)
+
+	source := `<html><body>` +
+		`<p s:geo="EU,UK">Cookies</p>` +
+		`<p s:geo="!US">International</p>` +
+		`<p s:geo="nordics">Nordic</p>` +
+		`<ul><li s:for="prices" s:for-item="price" s:geo="{{ price.region }}">{{ price.amount }}</li></ul>` +
+		`</body></html>`
+	data := Data{"prices": []interface{}{
+		map[string]interface{}{"region": "EU", "amount": "€10"},
+		map[string]interface{}{"region": "US", "amount": "$12"},
+	}}
+
+	parser := NewTemplateParser(data, &ParserOption{Request: &Request{Country: "FR"}})
+	html, err := parser.Render(source)
+	assert.Nil(t, err)
+	assert.Empty(t, parser.Errors())
+	assert.Contains(t, html, `<p>Cookies</p>`)
+	assert.Contains(t, html, `<p>International</p>`)
+	assert.NotContains(t, html, `Nordic`)
+	assert.Contains(t, html, `<ul><li>€10</li></ul>`)
+	assert.True(t, parser.VaryGeo())
+
+	parser = NewTemplateParser(data, &ParserOption{Request: &Request{Country: "US"}})
+	html, err = parser.Render(source)
+	assert.Nil(t, err)
+	assert.NotContains(t, html, `Cookies`)
+	assert.NotContains(t, html, `International`)
+	assert.Contains(t, html, `<ul><li>$12</li></ul>`)
+
+	// The unknown country
+	parser = NewTemplateParser(data, &ParserOption{Request: &Request{}})
+	html, err = parser.Render(source)
+	assert.Nil(t, err)
+	assert.NotContains(t, html, `Cookies`)
+	assert.Contains(t, html, `<p>International</p>`)
+
+	parser = NewTemplateParser(data, &ParserOption{Request: &Request{}})
+	_, err = parser.Render(`<html><body><p>Hello</p></body></html>`)
+	assert.Nil(t, err)
+	assert.False(t, parser.VaryGeo())
+}
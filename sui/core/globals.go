@@ -0,0 +1,40 @@
+package core
+
+import (
+	jsoniter "github.com/json-iterator/go"
+)
+
+// MergeGlobalData merge the global data files of the template, the later files override the earlier ones,
+// the objects are merged deeply, e.g. __data.json + __data.production.json
+func MergeGlobalData(sources ...[]byte) ([]byte, error) {
+	merged := map[string]interface{}{}
+	for _, source := range sources {
+		if len(source) == 0 {
+			continue
+		}
+
+		data := map[string]interface{}{}
+		if err := jsoniter.Unmarshal(source, &data); err != nil {
+			return nil, err
+		}
+		mergeGlobal(merged, data)
+	}
+	return jsoniter.Marshal(merged)
+}
+
+func mergeGlobal(dst map[string]interface{}, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, ok := value.(map[string]interface{})
+		if !ok {
+			dst[key] = value
+			continue
+		}
+
+		dstMap, ok := dst[key].(map[string]interface{})
+		if !ok {
+			dst[key] = srcMap
+			continue
+		}
+		mergeGlobal(dstMap, srcMap)
+	}
+}
@@ -0,0 +1,236 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/expr-lang/expr/ast"
+)
+
+// DataGuard the access control of the data paths, the expressions can not read the denied paths
+// e.g. "$session.token" denies $session.token, $session.token.xxx and reading the whole $session
+type DataGuard struct {
+	Deny      []string                           `json:"deny,omitempty"`
+	Sandbox   bool                               `json:"sandbox,omitempty"`   // only the allowed functions can be called
+	Functions []string                           `json:"functions,omitempty"` // the allowed functions in the sandbox, e.g. True, False, Empty
+	observe   func(stmt string, start time.Time) // time the expressions, see Profile
+}
+
+// DataDeny the data paths denied for all the templates
+var DataDeny = []string{}
+
+// guardVisitor collect the data paths read by the expression
+type guardVisitor struct {
+	nodes []ast.Node
+	bases map[ast.Node]bool
+	calls []string
+}
+
+// Visit visit the node
+func (v *guardVisitor) Visit(node *ast.Node) {
+	switch n := (*node).(type) {
+	case *ast.IdentifierNode:
+		v.nodes = append(v.nodes, n)
+	case *ast.MemberNode:
+		v.nodes = append(v.nodes, n)
+		v.bases[n.Node] = true
+		if chain, ok := n.Node.(*ast.ChainNode); ok {
+			v.bases[chain.Node] = true
+		}
+	case *ast.CallNode:
+		name, ok := guardPath(n.Callee)
+		if !ok {
+			name = "(...)"
+		}
+		v.calls = append(v.calls, name)
+	}
+}
+
+// NewDataGuard create the data guard with the global deny list and the given paths
+func NewDataGuard(deny ...string) *DataGuard {
+	paths := append([]string{}, DataDeny...)
+	for _, path := range deny {
+		path = strings.TrimSpace(path)
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	return &DataGuard{Deny: paths}
+}
+
+// Check check if the expression reads the denied data paths
+func (guard *DataGuard) Check(node ast.Node) error {
+	if guard == nil || (len(guard.Deny) == 0 && !guard.Sandbox) {
+		return nil
+	}
+
+	v := &guardVisitor{nodes: []ast.Node{}, bases: map[ast.Node]bool{}, calls: []string{}}
+	ast.Walk(&node, v)
+
+	if guard.Sandbox {
+		for _, name := range v.calls {
+			if !guard.allowed(name) {
+				return fmt.Errorf("function not allowed: %s", name)
+			}
+		}
+	}
+
+	for _, n := range v.nodes {
+		// The $env builtin reads the whole data, e.g. $env["$session"].token, toJSON($env)
+		if ident, is := n.(*ast.IdentifierNode); is && ident.Value == "$env" && len(guard.Deny) > 0 {
+			return fmt.Errorf("access denied: $env")
+		}
+
+		path, ok := guardPath(n)
+		if !ok {
+			// The dynamic member access of a parent of the denied path, e.g. $session[key]
+			if member, is := n.(*ast.MemberNode); is {
+				if base, ok := guardPath(member.Node); ok && guard.parentOf(base) {
+					return fmt.Errorf("access denied: %s[...]", base)
+				}
+			}
+			continue
+		}
+
+		if guard.denied(path) {
+			return fmt.Errorf("access denied: %s", path)
+		}
+
+		// Read the whole object contains the denied path
+		if !v.bases[n] && guard.parentOf(path) {
+			return fmt.Errorf("access denied: %s", path)
+		}
+	}
+	return nil
+}
+
+// allowed the function can be called in the sandbox
+func (guard *DataGuard) allowed(name string) bool {
+	for _, fn := range guard.Functions {
+		if fn == name {
+			return true
+		}
+	}
+	return false
+}
+
+// denied the path is denied or inside the denied path
+func (guard *DataGuard) denied(path string) bool {
+	for _, deny := range guard.Deny {
+		if path == deny || strings.HasPrefix(path, deny+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// parentOf the path is a parent of the denied path
+func (guard *DataGuard) parentOf(path string) bool {
+	for _, deny := range guard.Deny {
+		if strings.HasPrefix(deny, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// guardPath get the data path of the identifier or the member node with constant properties
+func guardPath(node ast.Node) (string, bool) {
+	switch n := node.(type) {
+	case *ast.IdentifierNode:
+		return n.Value, true
+
+	case *ast.ChainNode:
+		return guardPath(n.Node)
+
+	case *ast.MemberNode:
+		base, ok := guardPath(n.Node)
+		if !ok {
+			return "", false
+		}
+		switch prop := n.Property.(type) {
+		case *ast.StringNode:
+			return base + "." + prop.Value, true
+		case *ast.IntegerNode:
+			return fmt.Sprintf("%s.%d", base, prop.Value), true
+		}
+	}
+	return "", false
+}
+
+// Strip get a copy of the data without the denied paths, the data is not changed.
+// the expressions run against the stripped data, and the data sent to the client is stripped as well
+func (guard *DataGuard) Strip(data Data) Data {
+	if guard == nil || len(guard.Deny) == 0 || data == nil {
+		return data
+	}
+
+	res := Data{}
+	for k, v := range data {
+		res[k] = v
+	}
+	for _, path := range guard.Deny {
+		keys := strings.Split(path, ".")
+		if len(keys) == 1 {
+			delete(res, keys[0])
+			continue
+		}
+		if v, has := res[keys[0]]; has {
+			res[keys[0]] = guardStrip(v, keys[1:])
+		}
+	}
+	return res
+}
+
+// guardStrip copy the maps and the slices along the path, and remove the last key of the path
+func guardStrip(value interface{}, keys []string) interface{} {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return value
+		}
+		copied := map[string]interface{}{}
+		iter := rv.MapRange()
+		for iter.Next() {
+			copied[iter.Key().String()] = iter.Value().Interface()
+		}
+		if len(keys) == 1 {
+			delete(copied, keys[0])
+			return copied
+		}
+		if v, has := copied[keys[0]]; has {
+			copied[keys[0]] = guardStrip(v, keys[1:])
+		}
+		return copied
+
+	case reflect.Slice, reflect.Array:
+		idx, err := strconv.Atoi(keys[0])
+		if err != nil || idx < 0 || idx >= rv.Len() {
+			return value
+		}
+		copied := make([]interface{}, rv.Len())
+		for i := range copied {
+			copied[i] = rv.Index(i).Interface()
+		}
+		if len(keys) == 1 {
+			copied[idx] = nil
+			return copied
+		}
+		copied[idx] = guardStrip(copied[idx], keys[1:])
+		return copied
+
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return value
+		}
+		return guardStrip(rv.Elem().Interface(), keys)
+	}
+	return value
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataGuard(t *testing.T) {
+	data := Data{
+		"$session": map[string]interface{}{"name": "Yao", "token": "secret"},
+		"config":   map[string]interface{}{"title": "Site"},
+	}
+	guard := NewDataGuard("$session.token", "config")
+
+	res, _, err := data.ExecGuard(`$session.name`, guard)
+	assert.Nil(t, err)
+	assert.Equal(t, "Yao", res)
+
+	tests := []string{
+		`$session.token`,
+		`$session["token"]`,
+		`$session["to" + "ken"]`,
+		`toJSON($session)`,
+		`config.title`,
+		`config`,
+	}
+	for _, stmt := range tests {
+		_, _, err := data.ExecGuard(stmt, guard)
+		assert.NotNil(t, err, stmt)
+	}
+
+	value, values := data.ReplaceGuard(`Hello {{ $session.token }}`, guard)
+	assert.Equal(t, "Hello ", value)
+	assert.NotNil(t, values[0].Error)
+
+	// The $env builtin reads the whole data
+	for _, stmt := range []string{`$env["$session"].token`, `toJSON($env)`, `$env["$session"]["token"]`} {
+		res, _, err := data.ExecGuard(stmt, guard)
+		assert.NotNil(t, err, stmt)
+		assert.Nil(t, res, stmt)
+	}
+
+	// The denied paths are stripped, the data is not changed
+	stripped := guard.Strip(data)
+	assert.NotContains(t, stripped, "config")
+	assert.Equal(t, map[string]interface{}{"name": "Yao"}, stripped["$session"])
+	assert.Equal(t, "secret", data["$session"].(map[string]interface{})["token"])
+
+	// The denied paths are not sent to the client
+	parser := NewTemplateParser(data, &ParserOption{Deny: []string{"$session.token", "config"}})
+	html, err := parser.Render(`<html><body><p>{{ $session.name }}</p></body></html>`)
+	assert.Nil(t, err)
+	assert.Contains(t, html, ">Yao</p>")
+	assert.NotContains(t, html, "secret")
+	assert.NotContains(t, html, "Site")
+
+	// No guard
+	res, _, err = data.ExecGuard(`$session.token`, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "secret", res)
+}
@@ -0,0 +1,321 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HumanizeLocale the messages of the humanized formatting filters (fromNow, duration, humanBytes, ordinal)
+type HumanizeLocale struct {
+	Now       string               // e.g. just now
+	Past      string               // e.g. %s ago
+	Future    string               // e.g. in %s
+	Units     map[string][2]string // the singular and the plural of second, minute, hour, day, week, month and year
+	Relative  map[string][2]string // the forms of the units in the relative time, e.g. the dative of German (vor 2 Tagen), the Units are used if missing
+	Space     string               // the separator between the number and the unit
+	Join      string               // the separator between the units of the duration
+	Decimal   string               // the decimal separator
+	Group     string               // the thousands separator
+	Ordinal   func(n int64) string
+	ByteUnits []string
+}
+
+var humanizeLocales = map[string]*HumanizeLocale{}
+var humanizeMutex sync.RWMutex
+
+var humanizeUnits = []struct {
+	name    string
+	seconds float64
+}{
+	{"year", 365 * 24 * 3600},
+	{"month", 30 * 24 * 3600},
+	{"week", 7 * 24 * 3600},
+	{"day", 24 * 3600},
+	{"hour", 3600},
+	{"minute", 60},
+	{"second", 1},
+}
+
+func init() {
+	en := &HumanizeLocale{
+		Now: "just now", Past: "%s ago", Future: "in %s", Space: " ", Join: ", ", Decimal: ".", Group: ",",
+		Units: map[string][2]string{
+			"second": {"second", "seconds"}, "minute": {"minute", "minutes"}, "hour": {"hour", "hours"},
+			"day": {"day", "days"}, "week": {"week", "weeks"}, "month": {"month", "months"}, "year": {"year", "years"},
+		},
+		Ordinal: func(n int64) string {
+			suffix := "th"
+			switch {
+			case n%100 >= 11 && n%100 <= 13:
+			case n%10 == 1:
+				suffix = "st"
+			case n%10 == 2:
+				suffix = "nd"
+			case n%10 == 3:
+				suffix = "rd"
+			}
+			return fmt.Sprintf("%d%s", n, suffix)
+		},
+	}
+
+	zhCN := &HumanizeLocale{
+		Now: "刚刚", Past: "%s前", Future: "%s后", Space: "", Join: "", Decimal: ".", Group: ",",
+		Units: map[string][2]string{
+			"second": {"秒", "秒"}, "minute": {"分钟", "分钟"}, "hour": {"小时", "小时"},
+			"day": {"天", "天"}, "week": {"周", "周"}, "month": {"个月", "个月"}, "year": {"年", "年"},
+		},
+		Ordinal: func(n int64) string { return fmt.Sprintf("第%d", n) },
+	}
+
+	zhTW := &HumanizeLocale{
+		Now: "剛剛", Past: "%s前", Future: "%s後", Space: "", Join: "", Decimal: ".", Group: ",",
+		Units: map[string][2]string{
+			"second": {"秒", "秒"}, "minute": {"分鐘", "分鐘"}, "hour": {"小時", "小時"},
+			"day": {"天", "天"}, "week": {"週", "週"}, "month": {"個月", "個月"}, "year": {"年", "年"},
+		},
+		Ordinal: func(n int64) string { return fmt.Sprintf("第%d", n) },
+	}
+
+	ja := &HumanizeLocale{
+		Now: "たった今", Past: "%s前", Future: "%s後", Space: "", Join: "", Decimal: ".", Group: ",",
+		Units: map[string][2]string{
+			"second": {"秒", "秒"}, "minute": {"分", "分"}, "hour": {"時間", "時間"},
+			"day": {"日", "日"}, "week": {"週間", "週間"}, "month": {"か月", "か月"}, "year": {"年", "年"},
+		},
+		Ordinal: func(n int64) string { return fmt.Sprintf("%d番目", n) },
+	}
+
+	fr := &HumanizeLocale{
+		Now: "à l'instant", Past: "il y a %s", Future: "dans %s", Space: " ", Join: ", ", Decimal: ",", Group: "\u202f",
+		Units: map[string][2]string{
+			"second": {"seconde", "secondes"}, "minute": {"minute", "minutes"}, "hour": {"heure", "heures"},
+			"day": {"jour", "jours"}, "week": {"semaine", "semaines"}, "month": {"mois", "mois"}, "year": {"an", "ans"},
+		},
+		Ordinal: func(n int64) string {
+			if n == 1 {
+				return "1er"
+			}
+			return fmt.Sprintf("%de", n)
+		},
+		ByteUnits: []string{"o", "Ko", "Mo", "Go", "To", "Po"},
+	}
+
+	de := &HumanizeLocale{
+		Now: "gerade eben", Past: "vor %s", Future: "in %s", Space: " ", Join: ", ", Decimal: ",", Group: ".",
+		Units: map[string][2]string{
+			"second": {"Sekunde", "Sekunden"}, "minute": {"Minute", "Minuten"}, "hour": {"Stunde", "Stunden"},
+			"day": {"Tag", "Tage"}, "week": {"Woche", "Wochen"}, "month": {"Monat", "Monate"}, "year": {"Jahr", "Jahre"},
+		},
+		Relative: map[string][2]string{
+			"second": {"Sekunde", "Sekunden"}, "minute": {"Minute", "Minuten"}, "hour": {"Stunde", "Stunden"},
+			"day": {"Tag", "Tagen"}, "week": {"Woche", "Wochen"}, "month": {"Monat", "Monaten"}, "year": {"Jahr", "Jahren"},
+		},
+		Ordinal: func(n int64) string { return fmt.Sprintf("%d.", n) },
+	}
+
+	RegisterHumanizeLocale("en", en)
+	RegisterHumanizeLocale("zh-cn", zhCN)
+	RegisterHumanizeLocale("zh", zhCN)
+	RegisterHumanizeLocale("zh-tw", zhTW)
+	RegisterHumanizeLocale("zh-hk", zhTW)
+	RegisterHumanizeLocale("ja", ja)
+	RegisterHumanizeLocale("fr", fr)
+	RegisterHumanizeLocale("de", de)
+
+	RegisterLocaleFilter("fromNow", _fromNow)
+	RegisterLocaleFilter("duration", _duration)
+	RegisterLocaleFilter("humanBytes", _humanBytes)
+	RegisterLocaleFilter("ordinal", _ordinal)
+	RegisterLocaleFilter("decimal", _decimalFilter)
+}
+
+// RegisterHumanizeLocale register the messages of the locale, the name is the locale of the Locale module, e.g. en-us, zh-cn
+func RegisterHumanizeLocale(name string, locale *HumanizeLocale) {
+	humanizeMutex.Lock()
+	defer humanizeMutex.Unlock()
+	humanizeLocales[strings.ToLower(name)] = locale
+}
+
+// GetHumanizeLocale get the messages of the locale, e.g. zh-CN => zh-cn => zh => en
+func GetHumanizeLocale(name string) *HumanizeLocale {
+	humanizeMutex.RLock()
+	defer humanizeMutex.RUnlock()
+	name = strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+	if locale, has := humanizeLocales[name]; has {
+		return locale
+	}
+	if idx := strings.Index(name, "-"); idx > 0 {
+		if locale, has := humanizeLocales[name[:idx]]; has {
+			return locale
+		}
+	}
+	return humanizeLocales["en"]
+}
+
+// FromNow the relative time of the value, e.g. 3 minutes ago, in 2 days
+func (locale *HumanizeLocale) FromNow(t time.Time, now time.Time) string {
+	diff := now.Sub(t).Seconds()
+	if math.Abs(diff) < 45 {
+		return locale.Now
+	}
+
+	text := ""
+	for _, unit := range humanizeUnits {
+		if math.Abs(diff) >= unit.seconds {
+			text = locale.relative(int64(math.Round(math.Abs(diff)/unit.seconds)), unit.name)
+			break
+		}
+	}
+
+	if diff < 0 {
+		return fmt.Sprintf(locale.Future, text)
+	}
+	return fmt.Sprintf(locale.Past, text)
+}
+
+// Duration the humanized duration, the largest two units are used, e.g. 2 hours, 5 minutes
+func (locale *HumanizeLocale) Duration(d time.Duration) string {
+	seconds := math.Abs(d.Seconds())
+	parts := []string{}
+	for _, unit := range humanizeUnits {
+		if unit.name == "week" || seconds < unit.seconds {
+			continue
+		}
+		n := int64(seconds / unit.seconds)
+		seconds -= float64(n) * unit.seconds
+		parts = append(parts, locale.unit(n, unit.name))
+		if len(parts) == 2 {
+			break
+		}
+	}
+
+	if len(parts) == 0 {
+		return locale.unit(0, "second")
+	}
+	return strings.Join(parts, locale.Join)
+}
+
+// Bytes the humanized size of the bytes, e.g. 1.5 KB, the 1000 base is used if si is true
+func (locale *HumanizeLocale) Bytes(size float64, si bool) string {
+	units := locale.ByteUnits
+	if len(units) == 0 {
+		units = []string{"B", "KB", "MB", "GB", "TB", "PB"}
+	}
+
+	base := 1024.0
+	if si {
+		base = 1000.0
+	}
+
+	i := 0
+	for math.Abs(size) >= base && i < len(units)-1 {
+		size /= base
+		i++
+	}
+
+	number := strconv.FormatFloat(size, 'f', 1, 64)
+	if i == 0 || strings.HasSuffix(number, ".0") {
+		number = strconv.FormatFloat(math.Round(size), 'f', 0, 64)
+	}
+	return strings.Replace(number, ".", locale.Decimal, 1) + " " + units[i]
+}
+
+func (locale *HumanizeLocale) unit(n int64, name string) string {
+	return locale.label(n, locale.Units[name])
+}
+
+func (locale *HumanizeLocale) relative(n int64, name string) string {
+	names, has := locale.Relative[name]
+	if !has {
+		names = locale.Units[name]
+	}
+	return locale.label(n, names)
+}
+
+func (locale *HumanizeLocale) label(n int64, names [2]string) string {
+	label := names[1]
+	if n == 1 {
+		label = names[0]
+	}
+	return fmt.Sprintf("%d%s%s", n, locale.Space, label)
+}
+
+// toTime convert the value to the time, the numbers are the unix timestamps (seconds or milliseconds)
+func toTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case *time.Time:
+		return *v, nil
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"} {
+			if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+				return t, nil
+			}
+		}
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return toTime(n)
+		}
+		return time.Time{}, fmt.Errorf("%s is not a valid time", v)
+	}
+
+	n, ok := loopNumber(value)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%v is not a valid time", value)
+	}
+	if n > 1e12 {
+		return time.UnixMilli(int64(n)), nil
+	}
+	return time.Unix(int64(n), int64((n-math.Floor(n))*1e9)), nil
+}
+
+// _fromNow {{ created_at | fromNow }}
+func _fromNow(locale string, value interface{}, args ...interface{}) (interface{}, error) {
+	if value == nil {
+		return "", nil
+	}
+	t, err := toTime(value)
+	if err != nil {
+		return nil, fmt.Errorf("fromNow %s", err.Error())
+	}
+	return GetHumanizeLocale(locale).FromNow(t, time.Now()), nil
+}
+
+// _duration {{ seconds | duration }}, the strings are the Go durations, e.g. 1h30m
+func _duration(locale string, value interface{}, args ...interface{}) (interface{}, error) {
+	if s, ok := value.(string); ok {
+		d, err := time.ParseDuration(s)
+		if err == nil {
+			return GetHumanizeLocale(locale).Duration(d), nil
+		}
+	}
+
+	n, ok := loopNumber(value)
+	if !ok {
+		return nil, fmt.Errorf("duration %v is not a valid duration", value)
+	}
+	return GetHumanizeLocale(locale).Duration(time.Duration(n * float64(time.Second))), nil
+}
+
+// _humanBytes {{ size | humanBytes }}, {{ size | humanBytes:"si" }}
+func _humanBytes(locale string, value interface{}, args ...interface{}) (interface{}, error) {
+	n, ok := loopNumber(value)
+	if !ok {
+		return nil, fmt.Errorf("humanBytes %v is not a number", value)
+	}
+	si := len(args) > 0 && args[0] == "si"
+	return GetHumanizeLocale(locale).Bytes(n, si), nil
+}
+
+// _ordinal {{ rank | ordinal }}
+func _ordinal(locale string, value interface{}, args ...interface{}) (interface{}, error) {
+	n, ok := loopNumber(value)
+	if !ok {
+		return nil, fmt.Errorf("ordinal %v is not a number", value)
+	}
+	return GetHumanizeLocale(locale).Ordinal(int64(n)), nil
+}
@@ -0,0 +1,201 @@
+package core
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/expr-lang/expr/ast"
+)
+
+// PageHydration the hydration payload of the page (__sui_data), set in the page config
+//
+//	"hydration": {"partial": true, "keep": ["cart", "user.id"]}
+//
+// the partial payload embeds only the data paths read by the client bindings ({{ }} of the texts and the attributes,
+// s:model), the collections of the loops, the s:set values and the __sui_data references of the page scripts
+type PageHydration struct {
+	Partial bool     `json:"partial,omitempty"` // embed only the referenced data paths
+	Keep    []string `json:"keep,omitempty"`    // the data paths always embedded, e.g. the data read by the scripts dynamically
+}
+
+// hydrationKeep the data keys always embedded, the client runtime reads them
+var hydrationKeep = []string{csrfKey}
+
+// hydrationScriptRe the data read by the page scripts, __sui_data.cart, __sui_data["cart"]
+var hydrationScriptRe = regexp.MustCompile(`__sui_data(?:\.([A-Za-z_$][A-Za-z0-9_$]*)|\[["']([^"']+)["']\])`)
+
+// partial check if the partial hydration is enabled
+func (hydration *PageHydration) partial() bool {
+	return hydration != nil && hydration.Partial
+}
+
+// hydrateStmt record the data paths read by the statements of the binding, e.g. "{{ user.name }} ({{ user.role }})"
+func (parser *TemplateParser) hydrateStmt(source string) {
+	if parser.hydrate == nil {
+		return
+	}
+	for _, path := range parser.stmtPaths(source) {
+		parser.hydrate[path] = true
+	}
+}
+
+// stmtPaths the data paths read by the statements of the binding
+func (parser *TemplateParser) stmtPaths(source string) []string {
+	stmts := []string{source}
+	if matches := dataTokens.FindAllStringSubmatch(source, -1); len(matches) > 0 {
+		stmts = []string{}
+		for _, m := range matches {
+			stmts = append(stmts, m[0])
+		}
+	}
+
+	paths := []string{}
+	for _, stmt := range stmts {
+		program, err := parser.data.New(stmt)
+		if err != nil {
+			continue
+		}
+		paths = append(paths, hydrationPaths(program.Node())...)
+	}
+	return paths
+}
+
+// hydrationPaths the data paths read by the expression, the base of the dynamic member is read as a whole
+func hydrationPaths(node ast.Node) []string {
+	v := &guardVisitor{nodes: []ast.Node{}, bases: map[ast.Node]bool{}, calls: []string{}}
+	ast.Walk(&node, v)
+
+	paths := []string{}
+	for _, n := range v.nodes {
+		if path, ok := guardPath(n); ok {
+			if !v.bases[n] {
+				paths = append(paths, path)
+			}
+			continue
+		}
+
+		// The dynamic member, the base is read as a whole, e.g. items[i].name reads items
+		for member, ok := n.(*ast.MemberNode); ok; member, ok = member.Node.(*ast.MemberNode) {
+			if base, ok := guardPath(member.Node); ok {
+				paths = append(paths, base)
+				break
+			}
+		}
+	}
+	return paths
+}
+
+// hydrationData the data embedded in the page, the referenced paths of the client data if the partial hydration is enabled,
+// inline the source of the inline scripts of the page
+func (parser *TemplateParser) hydrationData(data Data, inline string) Data {
+	if parser.hydrate == nil {
+		return data
+	}
+
+	paths := map[string]bool{}
+	for path := range parser.hydrate {
+		paths[path] = true
+	}
+	for _, key := range hydrationKeep {
+		paths[key] = true
+	}
+	if parser.option.Hydration != nil {
+		for _, path := range parser.option.Hydration.Keep {
+			paths[path] = true
+		}
+	}
+	sources := []string{inline}
+	for _, scripts := range [][]ScriptNode{parser.scripts, parser.contextScripts()} {
+		for _, script := range scripts {
+			sources = append(sources, script.Source)
+		}
+	}
+	for _, source := range sources {
+		for _, m := range hydrationScriptRe.FindAllStringSubmatch(source, -1) {
+			paths[m[1]+m[2]] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(paths))
+	for path := range paths {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted) // the parent path first, e.g. user before user.name
+
+	res := Data{}
+	kept := []string{}
+	for _, path := range sorted {
+		covered := false
+		for _, k := range kept {
+			if strings.HasPrefix(path, k+".") {
+				covered = true
+				break
+			}
+		}
+		if covered {
+			continue
+		}
+		if hydrationPick(res, data, strings.Split(path, ".")) {
+			kept = append(kept, path)
+		}
+	}
+	return res
+}
+
+// contextScripts the scripts of the just-in-time components
+func (parser *TemplateParser) contextScripts() []ScriptNode {
+	if parser.context == nil {
+		return nil
+	}
+	return parser.context.scripts
+}
+
+// hydrationPick copy the value of the path from the source to the target, the maps along the path are copied
+// partially, the value of the other types (e.g. the lists) is copied as a whole. return false if the path is not found
+func hydrationPick(dst map[string]interface{}, src map[string]interface{}, keys []string) bool {
+	value, has := src[keys[0]]
+	if !has {
+		return false
+	}
+	if len(keys) == 1 {
+		dst[keys[0]] = value
+		return true
+	}
+
+	child, ok := hydrationMap(value)
+	if !ok {
+		dst[keys[0]] = value
+		return true
+	}
+	target, ok := dst[keys[0]].(map[string]interface{})
+	if !ok {
+		target = map[string]interface{}{}
+	}
+	if !hydrationPick(target, child, keys[1:]) {
+		return len(target) > 0
+	}
+	dst[keys[0]] = target
+	return true
+}
+
+// hydrationMap get the string keyed map of the value
+func hydrationMap(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case Data:
+		return v, true
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	res := map[string]interface{}{}
+	iter := rv.MapRange()
+	for iter.Next() {
+		res[iter.Key().String()] = iter.Value().Interface()
+	}
+	return res, true
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHydrationData(t *testing.T) {
+	source := `<html><body><h1>{{ user.name }}</h1><a href="{{ links[active].url }}">Link</a>` +
+		`<ul><li s:for="items" s:for-item="item">{{ item.title }}</li></ul><p s:if="report.total > 0">Report</p>` +
+		`<input s:model="form.email" /><script>console.log(__sui_data["config"])</script></body></html>`
+	data := Data{
+		"user":    map[string]interface{}{"name": "Yao", "password": "secret", "profile": map[string]interface{}{"bio": "..."}},
+		"links":   map[string]interface{}{"home": map[string]interface{}{"url": "/"}},
+		"active":  "home",
+		"items":   []interface{}{map[string]interface{}{"title": "A"}},
+		"report":  map[string]interface{}{"total": 10, "rows": []interface{}{1, 2, 3}},
+		"form":    map[string]interface{}{"email": "a@b.c", "token": "x"},
+		"config":  map[string]interface{}{"theme": "dark"},
+		"session": "hidden",
+	}
+
+	hydrated := func(option *ParserOption) string {
+		html, err := NewTemplateParser(data, option).Render(source)
+		assert.Nil(t, err)
+		return strings.Split(strings.Split(html, "var __sui_data = ")[1], ";\n")[0]
+	}
+
+	// The full data by default
+	assert.Contains(t, hydrated(&ParserOption{}), `"session":"hidden"`)
+
+	// The referenced paths only
+	payload := hydrated(&ParserOption{Hydration: &PageHydration{Partial: true, Keep: []string{"report.total"}}})
+	assert.Contains(t, payload, `"user":{"name":"Yao"}`)
+	assert.Contains(t, payload, `"links":{"home":{"url":"/"}}`)
+	assert.Contains(t, payload, `"active":"home"`)
+	assert.Contains(t, payload, `"items":[{"title":"A"}]`)
+	assert.Contains(t, payload, `"form":{"email":"a@b.c"}`)
+	assert.Contains(t, payload, `"config":{"theme":"dark"}`)
+	assert.Contains(t, payload, `"report":{"total":10}`)
+	assert.NotContains(t, payload, "secret")
+	assert.NotContains(t, payload, "session")
+	assert.NotContains(t, payload, "token")
+}
@@ -0,0 +1,263 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yaoapp/kun/log"
+)
+
+// icuRe the ICU argument of the message, a single brace with the argument, {name} or {count, plural, ...}
+var icuRe = regexp.MustCompile(`(?:^|[^{])\{\s*[A-Za-z_$][A-Za-z0-9_$.]*\s*[,}]`)
+
+// icuParser the parser of the ICU MessageFormat messages of the translations
+//
+//	messages:
+//	  "{{ count }} items": "{cart.count, plural, =0 {Your cart is empty} one {# item} other {# items}}"
+//	  "Hello {{ user.name }}": "{user.gender, select, female {Welcome back, Ms. {user.name}} other {Welcome back, {user.name}}}"
+//
+// the arguments are the data paths of the page, {name} and # are the bindings of the data ({{ name }}), the plural
+// and the select branches are chosen by the data when rendering. the plural categories follow the CLDR rules of the
+// locale, the exact =N branch first, the apostrophe quotes the braces, e.g. '{'literal'}'
+type icuParser struct {
+	parser *TemplateParser
+	source string
+	pos    int
+}
+
+// formatMessage format the ICU message of the translation, the message is returned as it is if it is not an ICU message
+func (parser *TemplateParser) formatMessage(message string) string {
+	if !icuRe.MatchString(message) {
+		return message
+	}
+
+	p := &icuParser{parser: parser, source: message}
+	res, err := p.message("", false)
+	if err != nil {
+		log.Warn("[SUI] The message %q is not a valid ICU message: %s", message, err.Error())
+		return message
+	}
+	return res
+}
+
+// message parse the message until the end, or the closing brace of the branch if nested. hash the data path of #
+func (p *icuParser) message(hash string, nested bool) (string, error) {
+	var sb strings.Builder
+	for p.pos < len(p.source) {
+		c := p.source[p.pos]
+		switch {
+
+		// The bindings of the message are kept, e.g. {{ count }}
+		case strings.HasPrefix(p.source[p.pos:], "{{"):
+			end := strings.Index(p.source[p.pos:], "}}")
+			if end < 0 {
+				return "", fmt.Errorf("the binding at %d is not closed", p.pos)
+			}
+			sb.WriteString(p.source[p.pos : p.pos+end+2])
+			p.pos += end + 2
+
+		case c == '\'':
+			sb.WriteString(p.quoted())
+
+		case c == '{':
+			arg, err := p.argument()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(arg)
+
+		case c == '}':
+			if !nested {
+				return "", fmt.Errorf("unexpected } at %d", p.pos)
+			}
+			return sb.String(), nil
+
+		case c == '#' && hash != "":
+			sb.WriteString("{{ " + hash + " }}")
+			p.pos++
+
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	if nested {
+		return "", fmt.Errorf("the branch is not closed")
+	}
+	return sb.String(), nil
+}
+
+// quoted the literal text of the apostrophe, the doubled apostrophe is an apostrophe, '{...}' the quoted text,
+// the apostrophe before the other characters is a literal apostrophe, e.g. don't
+func (p *icuParser) quoted() string {
+	p.pos++
+	if p.pos >= len(p.source) {
+		return "'"
+	}
+	if p.source[p.pos] == '\'' {
+		p.pos++
+		return "'"
+	}
+	if !strings.ContainsRune("{}#|", rune(p.source[p.pos])) {
+		return "'"
+	}
+
+	var sb strings.Builder
+	for p.pos < len(p.source) {
+		if p.source[p.pos] == '\'' {
+			if p.pos+1 < len(p.source) && p.source[p.pos+1] == '\'' {
+				sb.WriteByte('\'')
+				p.pos += 2
+				continue
+			}
+			p.pos++
+			break
+		}
+		sb.WriteByte(p.source[p.pos])
+		p.pos++
+	}
+	return sb.String()
+}
+
+// argument parse the argument, {name}, {name, number}, {name, plural, ...} or {name, select, ...}
+func (p *icuParser) argument() (string, error) {
+	start := p.pos
+	p.pos++
+	name := p.word(".$")
+	if name == "" {
+		return "", fmt.Errorf("the argument at %d has no name", start)
+	}
+
+	p.space()
+	if p.next('}') {
+		return "{{ " + name + " }}", nil
+	}
+	if !p.next(',') {
+		return "", fmt.Errorf("the argument %s is not closed", name)
+	}
+
+	p.space()
+	kind := p.word("")
+	p.space()
+	switch kind {
+	case "number":
+		// The style of the number is ignored, the value is formatted by the binding
+		end := strings.IndexByte(p.source[p.pos:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("the argument %s is not closed", name)
+		}
+		p.pos += end + 1
+		return "{{ " + name + " }}", nil
+
+	case "plural", "select":
+		if !p.next(',') {
+			return "", fmt.Errorf("the %s argument %s has no branches", kind, name)
+		}
+		return p.branches(name, kind)
+	}
+	return "", fmt.Errorf("the argument type %q of %s is not supported", kind, name)
+}
+
+// branches parse the branches of the plural or the select argument and choose one of them by the data
+func (p *icuParser) branches(name string, kind string) (string, error) {
+	hash := ""
+	offset := 0.0
+	if kind == "plural" {
+		hash = name
+		p.space()
+		if strings.HasPrefix(p.source[p.pos:], "offset:") {
+			p.pos += len("offset:")
+			p.space()
+			value, err := strconv.ParseFloat(p.word("."), 64)
+			if err != nil {
+				return "", fmt.Errorf("the offset of %s is invalid", name)
+			}
+			offset = value
+			hash = fmt.Sprintf("%s - %s", name, strconv.FormatFloat(offset, 'f', -1, 64))
+		}
+	}
+
+	branches := map[string]string{}
+	for {
+		p.space()
+		if p.pos >= len(p.source) {
+			return "", fmt.Errorf("the %s argument %s is not closed", kind, name)
+		}
+		if p.next('}') {
+			break
+		}
+
+		selector := p.word("=.-")
+		if selector == "" {
+			return "", fmt.Errorf("the %s argument %s has an invalid selector at %d", kind, name, p.pos)
+		}
+		p.space()
+		if !p.next('{') {
+			return "", fmt.Errorf("the branch %s of %s has no message", selector, name)
+		}
+		message, err := p.message(hash, true)
+		if err != nil {
+			return "", err
+		}
+		p.pos++ // the closing brace
+		branches[selector] = message
+	}
+	if _, has := branches["other"]; !has {
+		return "", fmt.Errorf("the %s argument %s has no other branch", kind, name)
+	}
+
+	value, _, err := p.parser.data.ExecGuard(name, p.parser.guard)
+	if err != nil {
+		return branches["other"], nil
+	}
+
+	selectors := []string{}
+	if kind == "plural" {
+		if count, ok := loopNumber(value); ok {
+			selectors = append(selectors, "="+strconv.FormatFloat(count, 'f', -1, 64))
+			selectors = append(selectors, PluralCategory(p.parser.localeName(), count-offset))
+		}
+	} else if value != nil {
+		selectors = append(selectors, fmt.Sprintf("%v", value))
+	}
+
+	for _, selector := range selectors {
+		if message, has := branches[selector]; has {
+			return message, nil
+		}
+	}
+	return branches["other"], nil
+}
+
+// word read the identifier, the letters, the digits, the underscores and the extra characters
+func (p *icuParser) word(extra string) string {
+	start := p.pos
+	for p.pos < len(p.source) {
+		c := p.source[p.pos]
+		if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || strings.IndexByte(extra, c) >= 0 {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.source[start:p.pos]
+}
+
+// space skip the white spaces
+func (p *icuParser) space() {
+	for p.pos < len(p.source) && strings.IndexByte(" \t\r\n", p.source[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// next consume the character if it is the next one
+func (p *icuParser) next(c byte) bool {
+	if p.pos < len(p.source) && p.source[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
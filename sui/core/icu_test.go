@@ -0,0 +1,46 @@
+package core
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatMessage(t *testing.T) {
+	parser := NewTemplateParser(Data{"count": 3, "user": map[string]interface{}{"gender": "female", "name": "Ada"}}, &ParserOption{Locale: "ru"})
+
+	// The placeholders are the bindings of the data
+	assert.Equal(t, "Hello {{ user.name }}, {{ count }} new", parser.formatMessage("Hello {user.name}, {count, number} new"))
+	assert.Equal(t, "{{ count }} items", parser.formatMessage("{{ count }} items"))
+	assert.Equal(t, "Don't {braces} #", parser.formatMessage("Don't '{braces}' #"))
+
+	// The branches are chosen by the data and the CLDR rules of the locale
+	assert.Equal(t, "{{ count }} товара", parser.formatMessage("{count, plural, one {# товар} few {# товара} other {# товаров}}"))
+	assert.Equal(t, "exactly three", parser.formatMessage("{count, plural, =3 {exactly three} other {#}}"))
+	assert.Equal(t, "You and {{ count - 1 }} others", parser.formatMessage("{count, plural, offset:1 =1 {You} other {You and # others}}"))
+	assert.Equal(t, "Ms. {{ user.name }}", parser.formatMessage("{user.gender, select, female {Ms. {user.name}} other {{user.name}}}"))
+	assert.Equal(t, "{{ user.name }}", parser.formatMessage("{user.role, select, admin {Admin} other {{user.name}}}"))
+
+	// The invalid messages are kept
+	assert.Equal(t, "{count, plural, one {#}}", parser.formatMessage("{count, plural, one {#}}"))
+	assert.Equal(t, "{count, date}", parser.formatMessage("{count, date}"))
+}
+
+func TestRenderICU(t *testing.T) {
+	Locales["en-us"] = map[string]*Locale{"/icu": {
+		Keys: map[string]string{
+			"cart": "{cart.count, plural, =0 {Your cart is empty} one {# item in the cart of {user.name}} other {# items in the cart of {user.name}}}",
+		},
+		version: atomic.LoadUint64(&LocaleVersion),
+	}}
+	defer delete(Locales, "en-us")
+
+	source := `<html><body><p s:trans-node="cart">{{ cart.count }} items</p></body></html>`
+	for count, expected := range map[int]string{0: "Your cart is empty", 1: "1 item in the cart of &lt;b&gt;Ada&lt;/b&gt;", 7: "7 items in the cart of &lt;b&gt;Ada&lt;/b&gt;"} {
+		data := Data{"cart": map[string]interface{}{"count": count}, "user": map[string]interface{}{"name": "<b>Ada</b>"}}
+		html, err := NewTemplateParser(data, &ParserOption{Route: "/icu", Locale: "en-us"}).Render(source)
+		assert.Nil(t, err)
+		assert.Contains(t, html, ">"+expected+"</p>")
+	}
+}
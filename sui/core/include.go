@@ -0,0 +1,127 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/yaoapp/gou/application"
+	"golang.org/x/net/html"
+)
+
+// MaxIncludeDepth the max depth of the nested s:include
+var MaxIncludeDepth = 16
+
+// includeRead read the included file from the application
+var includeRead = func(file string) ([]byte, error) {
+	return application.App.Read(ReleaseFile(file))
+}
+
+func (parser *TemplateParser) isInclude(sel *goquery.Selection) bool {
+	return len(sel.Nodes) > 0 && sel.Nodes[0].Data == "s:include"
+}
+
+// includeNode load and render the fragment of <s:include src="..."> inline with the current data
+// the src is resolved against the ParserOption.Root, the relative src is resolved against the current file
+func (parser *TemplateParser) includeNode(sel *goquery.Selection) {
+	defer parser.option.Timing.Start("include", "s:include")()
+	parser.parsed(sel)
+	parser.hide(sel)
+
+	src, _ := parser.data.ReplaceGuard(sel.AttrOr("src", ""), parser.guard)
+	file, err := parser.includeFile(src)
+	if err != nil {
+		parser.includeError(sel, err)
+		return
+	}
+
+	// Cycle detection
+	for _, included := range parser.includes {
+		if included == file {
+			parser.includeError(sel, fmt.Errorf("include %s error: cycle detected %s -> %s", src, strings.Join(parser.includes, " -> "), file))
+			return
+		}
+	}
+
+	if len(parser.includes) >= MaxIncludeDepth {
+		parser.includeError(sel, fmt.Errorf("include %s error: max depth %d exceeded", src, MaxIncludeDepth))
+		return
+	}
+
+	source, err := includeRead(file)
+	if err != nil {
+		parser.includeError(sel, fmt.Errorf("include %s error: %s", src, err.Error()))
+		return
+	}
+
+	parser.visitorData(string(source))
+	context := fragmentContext(sel.Nodes[0].Parent)
+	nodes, err := html.ParseFragment(strings.NewReader(string(source)), context)
+	if err != nil {
+		parser.includeError(sel, fmt.Errorf("include %s error: %s", src, err.Error()))
+		return
+	}
+
+	parser.includes = append(parser.includes, file)
+	defer func() { parser.includes = parser.includes[:len(parser.includes)-1] }()
+
+	// Attach the nodes to a container, the loops and the conditional chains of the top level
+	// need the parent and the siblings, the replacements of the fragment are applied in the container
+	container := &html.Node{Type: html.ElementNode, Data: context.Data, DataAtom: context.DataAtom, Namespace: context.Namespace}
+	for _, node := range nodes {
+		container.AppendChild(node)
+	}
+
+	offset := len(parser.replace)
+	for child := container.FirstChild; child != nil; child = child.NextSibling {
+		parser.parseNode(child)
+	}
+	parser.applyReplaceFrom(offset)
+
+	nodes = []*html.Node{}
+	for child := container.FirstChild; child != nil; child = container.FirstChild {
+		container.RemoveChild(child)
+		nodes = append(nodes, child)
+	}
+	parser.addReplace(sel, nodes)
+}
+
+// includeFile resolve the file path of the include src
+func (parser *TemplateParser) includeFile(src string) (string, error) {
+	src = strings.TrimSpace(src)
+	if src == "" {
+		return "", fmt.Errorf("include src is required")
+	}
+
+	root := filepath.Join(string(filepath.Separator), "public", parser.option.Root)
+	file := filepath.Join(root, src)
+	if !strings.HasPrefix(src, "/") {
+		dir := filepath.Join(root, filepath.Dir(parser.option.Route))
+		if len(parser.includes) > 0 {
+			dir = filepath.Dir(parser.includes[len(parser.includes)-1])
+		}
+		file = filepath.Join(dir, src)
+	}
+
+	if file != root && !strings.HasPrefix(file, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("include %s error: out of the root", src)
+	}
+	return file, nil
+}
+
+// includeError show the fallback content (the children of the s:include) when the include fails
+func (parser *TemplateParser) includeError(sel *goquery.Selection, err error) {
+	parser.renderError(sel.Nodes[0], "s:include", sel.AttrOr("src", ""), err)
+	if sel.Children().Length() == 0 && strings.TrimSpace(sel.Text()) == "" {
+		setError(sel, err)
+		return
+	}
+
+	nodes := []*html.Node{}
+	for child := sel.Nodes[0].FirstChild; child != nil; child = child.NextSibling {
+		parser.parseNode(child)
+		nodes = append(nodes, child)
+	}
+	parser.addReplace(sel, nodes)
+}
@@ -63,6 +63,8 @@ const initScriptTmpl = `
 			}
 		});
 		__sui_event_init(document.body);
+		__sui_model_init(document.body);
+		__sui_async_init(document.body);
 	});
 	%s
 `
@@ -85,7 +87,7 @@ const pageEventScriptTmpl = `
 	if (document.querySelector("[s\\:event=%s]")) {
 		let elms = document.querySelectorAll("[s\\:event=%s]");
 		elms.forEach(function (element) {
-			element.addEventListener("%s", function (event) {
+			__sui_event_listen(element, "%s", function (event) {
 				const dataKeys = %s;
 				const jsonKeys = %s;
 				const root = document.body;
@@ -99,7 +101,7 @@ const compEventScriptTmpl = `
 	if (document.querySelector("[s\\:event=%s]")) {
 		let elms = document.querySelectorAll("[s\\:event=%s]");
 		elms.forEach(function (element) {
-			element.addEventListener("%s", function (event) {
+			__sui_event_listen(element, "%s", function (event) {
 				const dataKeys = %s;
 				const jsonKeys = %s;
 				const root = __sui_component_root(element, "%s");
@@ -157,6 +159,69 @@ const componentInitScriptTmpl = `
 	}
 `
 
+const trackScriptTmpl = `
+	var __sui_track = %s;
+	document.addEventListener("DOMContentLoaded", function () {
+		document.querySelectorAll("[s\\:track-id]").forEach(function (element) {
+			const event = __sui_track.events[element.getAttribute("s:track-id")];
+			if (!event) return;
+			element.addEventListener(event.on || "click", function () {
+				const payload = JSON.stringify({
+					route: __sui_track.route,
+					token: __sui_track.token,
+					events: [{ name: event.name, props: event.props, route: __sui_track.route, time: Date.now() }],
+				});
+				try {
+					if (navigator.sendBeacon) {
+						navigator.sendBeacon(__sui_track.endpoint, new Blob([payload], { type: "application/json" }));
+						return;
+					}
+					fetch(__sui_track.endpoint, { method: "POST", body: payload, keepalive: true, headers: { "Content-Type": "application/json" } });
+				} catch (e) { console.log("[SUI] track error:", e); }
+			});
+		});
+	});
+`
+
+const debugScriptTmpl = `
+	var __sui_debug = %s;
+	document.addEventListener("DOMContentLoaded", function () {
+		var errors = __sui_debug.errors || [];
+		var audits = __sui_debug.audits || [];
+		var violations = __sui_debug.violations || [];
+		var panel = document.createElement("div");
+		panel.setAttribute("data-sui-debug", "true");
+		panel.style.cssText = "position:fixed;right:12px;bottom:12px;z-index:2147483647;max-width:560px;max-height:60vh;overflow:auto;font:12px/1.5 monospace;color:#fff;background:rgba(20,20,20,.92);border-radius:6px;box-shadow:0 2px 12px rgba(0,0,0,.3)";
+		var badge = document.createElement("div");
+		badge.style.cssText = "padding:6px 10px;cursor:pointer;background:" + (errors.length ? "#c0392b" : "#27ae60");
+		badge.textContent = "SUI " + (__sui_debug.route || "") + " · " + errors.length + " error(s)" + (audits.length ? " · " + audits.length + " a11y" : "") + (violations.length ? " · " + violations.length + " invalid" : "");
+		var body = document.createElement("pre");
+		body.style.cssText = "display:none;margin:0;padding:8px 10px;white-space:pre-wrap";
+		var lines = [];
+		errors.forEach(function (err) {
+			lines.push("✗ " + [err.path, err.directive ? err.directive + (err.expression ? '="' + err.expression + '"' : "") : ""].filter(Boolean).join(" ") + "\n  " + err.message);
+		});
+		audits.forEach(function (finding) {
+			lines.push("⚠ " + [finding.path, finding.rule].filter(Boolean).join(" ") + "\n  " + finding.message);
+		});
+		violations.forEach(function (v) {
+			lines.push("⚑ " + [v.line ? v.line + ":" + v.column : "", v.path, v.rule].filter(Boolean).join(" ") + "\n  " + v.message);
+		});
+		(__sui_debug.timing || []).forEach(function (metric) {
+			lines.push("⏱ " + metric.name + " " + (metric.duration / 1e6).toFixed(2) + "ms" + (metric.count > 1 ? " (" + metric.count + ")" : ""));
+		});
+		lines.push("data: " + JSON.stringify(__sui_debug.data, null, 2));
+		body.textContent = lines.join("\n");
+		badge.addEventListener("click", function () {
+			body.style.display = body.style.display === "none" ? "block" : "none";
+		});
+		panel.appendChild(badge);
+		panel.appendChild(body);
+		document.body.appendChild(panel);
+		errors.forEach(function (err) { console.warn("[SUI] render error:", err); });
+	});
+`
+
 // Inject code
 const backendScriptTmpl = `
 this.__sui_page = '%s';
@@ -180,6 +245,14 @@ func bodyInjectionScript(jsonRaw string, debug bool) string {
 	return fmt.Sprintf(`<script type="text/javascript">`+initScriptTmpl+`</script>`, jsonRaw, jsPrintData)
 }
 
+func debugInjectionScript(jsonRaw string) string {
+	return fmt.Sprintf(`<script type="text/javascript">`+debugScriptTmpl+`</script>`, jsonRaw)
+}
+
+func trackInjectionScript(jsonRaw string) string {
+	return fmt.Sprintf(`<script type="text/javascript">`+trackScriptTmpl+`</script>`, jsonRaw)
+}
+
 func headInjectionScript(jsonRaw string) string {
 	return fmt.Sprintf(`<script type="text/javascript">`+i118nScriptTmpl+`</script>`, jsonRaw)
 }
@@ -4,6 +4,7 @@ import (
 	"io"
 	"net/url"
 	"regexp"
+	"time"
 )
 
 // SUIs the loaded SUI instances
@@ -69,6 +70,8 @@ type ITemplate interface {
 	ExecAfterBuildScripts() []TemplateScirptResult
 
 	Trans(option *BuildOption) ([]string, error)
+	Dependencies(route string) (*PageDependencies, error)
+	Storybook(data map[string]interface{}) (*Storybook, error)
 }
 
 // IPage is the interface for the page
@@ -92,12 +95,15 @@ type IPage interface {
 	EditorDataSource() SourceData
 
 	PreviewRender(referer string) (string, error)
+	PreviewRenderAt(referer string, now time.Time) (string, error)
+	Audit() ([]AuditFinding, error)
 
 	AssetScript() (*Asset, error)
 	AssetStyle() (*Asset, error)
 
 	Build(globalCtx *GlobalBuildContext, option *BuildOption) ([]string, error)
 	BuildAsComponent(globalCtx *GlobalBuildContext, option *BuildOption) ([]string, error)
+	BuildAsWebComponent(globalCtx *GlobalBuildContext, option *BuildOption) (string, []string, error)
 
 	Trans(globalCtx *GlobalBuildContext, option *BuildOption) ([]string, error)
 }
@@ -0,0 +1,240 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// MaxIRCache the max pre-compiled templates kept in the memory
+var MaxIRCache = 1024
+
+// irElement the namespace of the raw nodes of the static element chunks, the node paths count them as elements
+const irElement = "sui:ir"
+
+// IR the pre-compiled template. The static subtrees (no bindings, no directives) are compiled to the static chunks,
+// the other nodes are the directive ops executed by the parser walk. The rendering clones the ops only, the chunks
+// are rendered to HTML once and shared by the renderings.
+type IR struct {
+	doc    *html.Node
+	chunks map[*html.Node]irChunk
+}
+
+// irChunk the static subtree
+type irChunk struct {
+	html     string // the pre-rendered HTML of the element, empty if the subtree is cloned (the text nodes, see CompileIR)
+	sequence int    // the keys taken by the walk of the subtree, the sequence keys of the following nodes stay the same
+}
+
+var irCache = map[string]*IR{}
+var irMutex sync.RWMutex
+
+// CompileIR compile the HTML document to the IR.
+// the teleport and the toc directives query the whole document, the static chunks of their pages are cloned instead
+// of being pre-rendered, so the nodes can be found and changed
+func CompileIR(source string) (*IR, error) {
+	doc, err := NewDocumentString(source)
+	if err != nil {
+		return nil, err
+	}
+
+	static := map[*html.Node]bool{}
+	root := doc.Nodes[0]
+	if irMark(root, false, static) {
+		for child := root.FirstChild; child != nil; child = child.NextSibling {
+			static[child] = true
+		}
+	}
+
+	prerender := !strings.Contains(source, "s:teleport") && !strings.Contains(source, "s:toc")
+	ir := &IR{doc: root, chunks: map[*html.Node]irChunk{}}
+	for node := range static {
+		chunk := irChunk{sequence: irSequence(node)}
+		if prerender && node.Type == html.ElementNode {
+			var buf bytes.Buffer
+			if err := html.Render(&buf, node); err != nil {
+				return nil, err
+			}
+			chunk.html = buf.String()
+		}
+		ir.chunks[node] = chunk
+	}
+	return ir, nil
+}
+
+// Static the number of the static chunks
+func (ir *IR) Static() int {
+	return len(ir.chunks)
+}
+
+// document get the document of the rendering, the IR is cached per route, theme and locale
+func (parser *TemplateParser) document(source string) (*goquery.Document, error) {
+	source, err := Delimit(source, parser.option.Delimiters)
+	if err != nil {
+		return nil, err
+	}
+
+	// The source positions of the elements, the validation mode renders the source without the IR
+	if parser.validating() {
+		source, parser.violations = sourceMap(source)
+		return NewDocumentString(trimTokens(stripComments(source)))
+	}
+
+	source = trimTokens(stripComments(source))
+	if !parser.precompile() {
+		return NewDocumentString(source)
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(source))
+	key := fmt.Sprintf("%s|%v|%v|%x", parser.option.Route, parser.option.Theme, parser.option.Locale, h.Sum64())
+
+	irMutex.RLock()
+	ir, has := irCache[key]
+	irMutex.RUnlock()
+	if !has {
+		ir, err = CompileIR(source)
+		if err != nil {
+			return nil, err
+		}
+
+		irMutex.Lock()
+		if len(irCache) >= MaxIRCache {
+			irCache = map[string]*IR{}
+		}
+		irCache[key] = ir
+		irMutex.Unlock()
+	}
+
+	parser.static = map[*html.Node]int{}
+	doc := ir.clone(ir.doc, parser.static)
+	return goquery.NewDocumentFromNode(doc), nil
+}
+
+// precompile check if the IR is used, the editor, preview, debug and restricted renderings always parse the HTML.
+// the audit and the minify walk the element nodes, the static chunks are raw nodes, so they parse the HTML as well
+func (parser *TemplateParser) precompile() bool {
+	option := parser.option
+	return option != nil && option.Precompile && !option.Editor && !option.Preview && !option.Debug && option.Restricted == nil &&
+		!option.Audit && !option.Minify
+}
+
+// CleanIR remove the pre-compiled templates, e.g. after the templates are rebuilt
+func CleanIR() {
+	irMutex.Lock()
+	defer irMutex.Unlock()
+	irCache = map[string]*IR{}
+}
+
+// irMark mark the top-most static subtrees, returns true if the whole subtree is static.
+// the children of the elements with the s: attributes are not static, e.g. the s:trans-node and the s:raw texts
+func irMark(node *html.Node, directive bool, static map[*html.Node]bool) bool {
+	all := !directive && irStatic(node)
+	children := []*html.Node{}
+	results := []bool{}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		res := irMark(child, irDirective(node), static)
+		children = append(children, child)
+		results = append(results, res)
+		all = all && res
+	}
+
+	if !all {
+		for i, child := range children {
+			if results[i] {
+				static[child] = true
+			}
+		}
+	}
+	return all
+}
+
+// irStatic check if the node itself has no bindings and no directives
+func irStatic(node *html.Node) bool {
+	switch node.Type {
+	case html.TextNode:
+		return !irBinding(node.Data)
+
+	case html.ElementNode:
+		switch node.Data {
+		case "script", "style", "link", "slot", "template", "set", "html", "head", "body":
+			return false
+		}
+		if strings.Contains(node.Data, ":") {
+			return false
+		}
+		for _, attr := range node.Attr {
+			if attr.Key == "is" || attr.Key == "parsed" || strings.HasPrefix(attr.Key, "...") || strings.Contains(attr.Key, ":") {
+				return false
+			}
+			if irBinding(attr.Val) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// The comments are removed by the Tidy, the doctype belongs to the document
+	return false
+}
+
+// irDirective check if the element has the s: attributes
+func irDirective(node *html.Node) bool {
+	for _, attr := range node.Attr {
+		if strings.HasPrefix(attr.Key, "s:") {
+			return true
+		}
+	}
+	return false
+}
+
+func irBinding(value string) bool {
+	return strings.Contains(value, "{{") || strings.Contains(value, "{%") || strings.Contains(value, "[{")
+}
+
+// irSequence the keys taken by the walk of the static subtree, one for each text node and each attribute
+func irSequence(node *html.Node) int {
+	n := len(node.Attr)
+	if node.Type == html.TextNode {
+		n = 1
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		n += irSequence(child)
+	}
+	return n
+}
+
+// clone clone the ops of the IR, the static chunks are the raw nodes of the pre-rendered HTML
+func (ir *IR) clone(node *html.Node, static map[*html.Node]int) *html.Node {
+	if chunk, has := ir.chunks[node]; has && chunk.html != "" {
+		raw := &html.Node{Type: html.RawNode, Data: chunk.html, Namespace: irElement}
+		static[raw] = chunk.sequence
+		return raw
+	}
+
+	clone := &html.Node{
+		Type:      node.Type,
+		DataAtom:  node.DataAtom,
+		Data:      node.Data,
+		Namespace: node.Namespace,
+		Attr:      make([]html.Attribute, len(node.Attr)),
+	}
+	copy(clone.Attr, node.Attr)
+	if chunk, has := ir.chunks[node]; has {
+		static[clone] = chunk.sequence
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		clone.AppendChild(ir.clone(child, static))
+	}
+	return clone
+}
+
+// isElementNode check if the node is an element, the static element chunks of the IR are elements
+func isElementNode(node *html.Node) bool {
+	return node.Type == html.ElementNode || (node.Type == html.RawNode && node.Namespace == irElement)
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -27,6 +28,7 @@ type JitComponent struct {
 const (
 	saveComponent uint8 = iota
 	removeComponent
+	cleanComponent
 )
 
 type componentData struct {
@@ -49,24 +51,37 @@ func init() {
 
 // parseComponent parse the component
 func (parser *TemplateParser) parseJitComponent(sel *goquery.Selection) {
+	defer parser.option.Timing.Start("components", "Components")()
 	parser.parsed(sel)
+	if parser.cancelled() || !parser.limitDepth(sel, "is") {
+		return
+	}
+
 	comp, err := parser.getJitComponent(sel)
 	if err != nil {
-		parser.errors = append(parser.errors, err)
-		setError(sel, err)
+		parser.componentError(sel, "is", sel.AttrOr("is", ""), err)
 		return
 	}
 
 	comsel, err := parser.newJitComponentSel(sel, comp)
 	if err != nil {
-		parser.errors = append(parser.errors, err)
-		setError(sel, err)
+		parser.componentError(sel, "is", sel.AttrOr("is", ""), err)
 		return
 	}
+
+	// The keys of the component are taken from the scope of the component, the following nodes keep the sequence
+	sequence, keyScope := parser.sequence, parser.keyScope
+	parser.sequence, parser.keyScope = parser.componentScope(sel.Nodes[0])
 	parser.parseElementComponent(comsel)
+	parser.sequence, parser.keyScope = sequence+1, keyScope
 	sel.ReplaceWithSelection(comsel)
 
-	if len(comp.scripts) == 0 && len(comp.styles) == 0 {
+	parser.addContextAssets(comp.scripts, comp.styles)
+}
+
+// addContextAssets add the scripts and the styles of the just-in-time components, the duplicates are skipped
+func (parser *TemplateParser) addContextAssets(scripts []ScriptNode, styles []StyleNode) {
+	if len(scripts) == 0 && len(styles) == 0 {
 		return
 	}
 
@@ -80,8 +95,8 @@ func (parser *TemplateParser) parseJitComponent(sel *goquery.Selection) {
 	}
 
 	// Add the scripts
-	if comp.scripts != nil {
-		for _, script := range comp.scripts {
+	if scripts != nil {
+		for _, script := range scripts {
 			hash := script.Hash()
 			if parser.context.scriptMaps[hash] {
 				continue
@@ -93,8 +108,8 @@ func (parser *TemplateParser) parseJitComponent(sel *goquery.Selection) {
 	}
 
 	// Add the styles
-	if comp.styles != nil {
-		for _, style := range comp.styles {
+	if styles != nil {
+		for _, style := range styles {
 			if parser.context.styleMaps[style.Component] {
 				continue
 			}
@@ -105,6 +120,7 @@ func (parser *TemplateParser) parseJitComponent(sel *goquery.Selection) {
 }
 
 func (parser *TemplateParser) newJitComponentSel(sel *goquery.Selection, comp *JitComponent) (*goquery.Selection, error) {
+	parser.visitorData(comp.html)
 
 	ns := Namespace(comp.route, parser.sequence+1, comp.buildOption.ScriptMinify)
 	cn := ComponentName(comp.route, comp.buildOption.ScriptMinify)
@@ -114,40 +130,36 @@ func (parser *TemplateParser) newJitComponentSel(sel *goquery.Selection, comp *J
 		"s:ready": cn + "()",
 	}
 
-	doc, err := NewDocumentString(comp.html)
+	doc, err := NewForeignDocumentString(trimTokens(stripComments(comp.html)), sel.Nodes[0].Parent)
 	if err != nil {
 		return nil, fmt.Errorf("Component %s failed to load, please recompile the component. %s", comp.route, err.Error())
 	}
 
 	root := doc.Find("body").First()
 	compSel := doc.Find("body").Children().First()
+	for _, attr := range undeclaredEmits(sel.Nodes[0], compSel.Nodes[0]) {
+		parser.renderError(sel.Nodes[0], attr.Key, attr.Val, emitError(comp.route, attr, compSel.Nodes[0]))
+	}
+
 	data := Data{}
 	for _, attr := range sel.Nodes[0].Attr {
-		if attr.Key == "is" || attr.Key == "s:jit" {
+		if attr.Key == "is" || attr.Key == "s:jit" || attr.Key == "s:props" || attr.Key == isAllowAttr {
 			continue
 		}
+		attr.Key = emitKey(attr.Key)
 
 		// ...variable
 		if strings.HasPrefix(attr.Key, "...") {
 			key := attr.Key[3:]
 			if parser.data != nil {
 				if values, ok := parser.data[key].(map[string]any); ok {
-					for name, value := range values {
-						switch v := value.(type) {
-						case string:
-							props[name] = v
-						case bool, int, float64:
-							props[name] = fmt.Sprintf("%v", v)
-
-						case nil:
-							props[name] = ""
-
-						default:
-							str, err := jsoniter.MarshalToString(value)
-							if err != nil {
-								continue
-							}
-							props[name] = str
+					for _, name := range sortedKeys(values) {
+						val, json, ok := spreadValue(values[name])
+						if !ok {
+							continue
+						}
+						props[name] = val
+						if json {
 							props[fmt.Sprintf("json-attr-%s", name)] = "true"
 						}
 					}
@@ -156,7 +168,7 @@ func (parser *TemplateParser) newJitComponentSel(sel *goquery.Selection, comp *J
 			continue
 		}
 
-		val, values := parser.data.Replace(attr.Val)
+		val, values := parser.data.ReplaceGuard(attr.Val, parser.guard)
 		if HasJSON(values) {
 			props[fmt.Sprintf("json-attr-%s", attr.Key)] = "true"
 		}
@@ -164,8 +176,31 @@ func (parser *TemplateParser) newJitComponentSel(sel *goquery.Selection, comp *J
 		data[attr.Key] = val
 	}
 
+	// The spread props of the component call, the props set on the element win
+	spread := parser.spreadProps(sel)
+	for _, name := range sortedKeys(spread) {
+		if _, has := props[name]; has {
+			continue
+		}
+		val, json, ok := spreadValue(spread[name])
+		if !ok {
+			continue
+		}
+		props[name] = val
+		data[name] = val
+		if json {
+			props[fmt.Sprintf("json-attr-%s", name)] = "true"
+		}
+	}
+
 	data.replaceNodeUse(propTokens, compSel.Nodes[0])
-	for key, val := range props {
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		val := props[key]
 		if strings.HasPrefix(key, "s:") || key == "parsed" {
 			compSel.SetAttr(key, val)
 			continue
@@ -199,6 +234,9 @@ func (parser *TemplateParser) newJitComponentSel(sel *goquery.Selection, comp *J
 		}
 	}
 
+	// Named slots: <template slot="name"> in the caller => <slot name="name">fallback</slot> in the component
+	parser.replaceSlots(sel, compSel)
+
 	// Replace the children
 	children := sel.Contents()
 	compSel.Find("children").ReplaceWithSelection(children)
@@ -206,6 +244,65 @@ func (parser *TemplateParser) newJitComponentSel(sel *goquery.Selection, comp *J
 	return compSel, nil
 }
 
+// replaceSlots fill the <slot> of the component with the <template slot="..."> of the caller,
+// the elements with the slot attribute are assigned to the slot as the standard slot semantics.
+// the slot without name is the default slot, filled with the other children of the caller.
+// The slot keeps the fallback content if the caller does not provide it.
+// The slots in the native <template> (e.g. the declarative shadow DOM) are kept for the client runtime.
+func (parser *TemplateParser) replaceSlots(sel *goquery.Selection, compSel *goquery.Selection) {
+
+	slots := compSel.Find("slot").FilterFunction(func(i int, slot *goquery.Selection) bool {
+		for p := slot.Nodes[0].Parent; p != nil; p = p.Parent {
+			if isInertTemplate(p) {
+				return false
+			}
+		}
+		return true
+	})
+	if slots.Length() == 0 {
+		return
+	}
+
+	provided := map[string]*goquery.Selection{}
+	sel.ChildrenFiltered("template[slot]").Each(func(i int, tmpl *goquery.Selection) {
+		name := tmpl.AttrOr("slot", "")
+		tmpl.Remove()
+		if _, has := provided[name]; has {
+			return
+		}
+		provided[name] = tmpl.Contents()
+	})
+
+	// The standard slot assignment: <div slot="name"> in the caller
+	sel.ChildrenFiltered("[slot]").Each(func(i int, elm *goquery.Selection) {
+		name := elm.AttrOr("slot", "")
+		elm.Remove()
+		elm.RemoveAttr("slot")
+		if contents, has := provided[name]; has {
+			provided[name] = contents.AddSelection(elm)
+			return
+		}
+		provided[name] = elm
+	})
+
+	slots.Each(func(i int, slot *goquery.Selection) {
+		name := slot.AttrOr("name", "")
+		if contents, has := provided[name]; has {
+			slot.ReplaceWithSelection(contents)
+			return
+		}
+
+		// The default slot
+		if name == "" && (sel.Children().Length() > 0 || strings.TrimSpace(sel.Text()) != "") {
+			slot.ReplaceWithSelection(sel.Contents())
+			return
+		}
+
+		// Fallback content
+		slot.ReplaceWithSelection(slot.Contents())
+	})
+}
+
 func (parser *TemplateParser) getJitComponent(sel *goquery.Selection) (*JitComponent, error) {
 	is := sel.AttrOr("is", "")
 	if is == "" {
@@ -213,7 +310,13 @@ func (parser *TemplateParser) getJitComponent(sel *goquery.Selection) (*JitCompo
 
 	}
 
-	is, _ = parser.data.Replace(is)
+	is, _ = parser.data.ReplaceGuard(is, parser.guard)
+	route, err := parser.jitRoute(sel, is)
+	if err != nil {
+		return nil, err
+	}
+	is = route
+
 	if parser.option == nil {
 		parser.option = &ParserOption{Debug: true, DisableCache: false}
 	}
@@ -223,14 +326,26 @@ func (parser *TemplateParser) getJitComponent(sel *goquery.Selection) (*JitCompo
 		return comp, nil
 	}
 
-	file := filepath.Join(string(os.PathSeparator), "public", parser.option.Root, is+".jit")
-	if exist, _ := application.App.Exists(file); !exist {
-		return nil, fmt.Errorf("Component %s file not found, please recompile the component", is)
-	}
+	// The component of the registry, cached by the registry
+	registry := componentRegistry(is)
+	var file string
+	var source []byte
+	if registry != nil {
+		file = is
+		source, err = registry.Fetch(is)
+		if err != nil {
+			return nil, fmt.Errorf("Component %s failed to fetch from the registry. %s", is, err.Error())
+		}
+	} else {
+		file = ReleaseFile(filepath.Join(string(os.PathSeparator), "public", parser.option.Root, is+".jit"))
+		if exist, _ := application.App.Exists(file); !exist {
+			return nil, fmt.Errorf("Component %s file not found, please recompile the component", is)
+		}
 
-	source, err := application.App.Read(file)
-	if err != nil {
-		return nil, fmt.Errorf("Component %s failed to load, please recompile the component", is)
+		source, err = application.App.Read(file)
+		if err != nil {
+			return nil, fmt.Errorf("Component %s failed to load, please recompile the component", is)
+		}
 	}
 
 	// Get the scripts
@@ -259,6 +374,10 @@ func (parser *TemplateParser) getJitComponent(sel *goquery.Selection) (*JitCompo
 		return nil, fmt.Errorf("Component %s failed to load, please recompile the component. %s", is, err.Error())
 	}
 
+	if registry != nil {
+		registryAssets(registry, scriptnodes, stylenodes)
+	}
+
 	comp := &JitComponent{
 		file:        file,
 		route:       is,
@@ -269,8 +388,10 @@ func (parser *TemplateParser) getJitComponent(sel *goquery.Selection) (*JitCompo
 		buildOption: buildOption,
 	}
 
-	// Save the component to the cache
-	chComp <- &componentData{is, comp, saveComponent}
+	// Save the component to the cache, the components of the registry are revalidated by the registry
+	if registry == nil {
+		chComp <- &componentData{is, comp, saveComponent}
+	}
 	return comp, nil
 }
 
@@ -404,6 +525,8 @@ func componentWriter() {
 				Components[data.file] = data.comp
 			case removeCache:
 				delete(Components, data.file)
+			case cleanComponent:
+				Components = map[string]*JitComponent{}
 			}
 		}
 	}
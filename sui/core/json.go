@@ -1,9 +1,25 @@
 package core
 
 import (
+	"sort"
+
 	jsoniter "github.com/json-iterator/go"
 )
 
+// jsonStable the json encoding of the rendered output, the map keys are sorted,
+// the repeated renderings of the same template produce the byte-identical output (the caches, the snapshot tests)
+var jsonStable = jsoniter.Config{EscapeHTML: true, SortMapKeys: true}.Froze()
+
+// sortedKeys get the sorted keys of the map, the attributes set from the maps are added in order
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // UnmarshalJSON Custom JSON unmarshal function for PageMock
 func (mock *PageMock) UnmarshalJSON(data []byte) error {
 
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// nextKey get the key of the node, the sequence number by default.
+// If the StableKeys option is enabled, the key is derived from the hash of node path and the statement,
+// so the keys are the same across renders even if the conditional branches change.
+func (parser *TemplateParser) nextKey(node *html.Node, stmt string) string {
+	parser.sequence = parser.sequence + 1
+	if !parser.option.StableKeys {
+		return fmt.Sprintf("%v", parser.sequence)
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(parser.keyScope))
+	h.Write([]byte{'|'})
+	h.Write([]byte(nodePath(node)))
+	h.Write([]byte{'|'})
+	h.Write([]byte(stmt))
+	return fmt.Sprintf("k%08x", h.Sum32())
+}
+
+// componentSequenceShift the range of the sequence keys of a just-in-time component, see componentScope
+const componentSequenceShift = 20
+
+// componentScope get the sequence and the key scope of the just-in-time component, derived from the path of the
+// component in the page, so the keys are the same whether the components are rendered in parallel or in order
+func (parser *TemplateParser) componentScope(node *html.Node) (int, string) {
+	scope := parser.keyScope + "/" + nodePath(node)
+	h := fnv.New32a()
+	h.Write([]byte(scope))
+	return int(h.Sum32()) << componentSequenceShift, scope
+}
+
+// nodePath get the path of the node, e.g. html:0/body:1/div:2, the text node is the last part with
+// the index of the text siblings, e.g. html:0/body:1/p:0/#text:1
+func nodePath(node *html.Node) string {
+	parts := []string{}
+	if node != nil && node.Type == html.TextNode {
+		idx := 0
+		for prev := node.PrevSibling; prev != nil; prev = prev.PrevSibling {
+			if prev.Type == html.TextNode {
+				idx++
+			}
+		}
+		parts = append(parts, fmt.Sprintf("#text:%d", idx))
+		node = node.Parent
+	}
+
+	for n := node; n != nil; n = n.Parent {
+		if n.Type != html.ElementNode {
+			continue
+		}
+
+		idx := 0
+		for prev := n.PrevSibling; prev != nil; prev = prev.PrevSibling {
+			if isElementNode(prev) {
+				idx++
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%s:%d", n.Data, idx))
+	}
+
+	// reverse
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,189 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LayoutsDir the directory of the layouts in the template root, the name of the layout is the path without the
+// extension, e.g. __layouts/admin.html is "admin", __layouts/admin/users.html is "admin/users"
+const LayoutsDir = "__layouts"
+
+// LayoutContent the default region of the layout, the page content out of the region fills
+const LayoutContent = "content"
+
+// MaxLayoutDepth the max depth of the layout chain, e.g. site → section → page is 2
+var MaxLayoutDepth = 8
+
+// layoutRegionRe the regions of the layout, the content of the region is the fallback if the region is not filled
+//
+//	<s:region name="content"/>
+//	<s:region name="sidebar"><nav>Default</nav></s:region>
+var layoutRegionRe = regexp.MustCompile(`(?s)<s:region\s+name=["']([^"']+)["']\s*(?:/>|>(.*?)</s:region>)`)
+
+// layoutExtendsRe the parent layout of the layout
+//
+//	<s:layout extends="site"> ... </s:layout>
+var layoutExtendsRe = regexp.MustCompile(`(?s)^\s*<s:layout(?:\s+extends=["']([^"']*)["'])?\s*>(.*)</s:layout>\s*$`)
+
+// layoutFillRe the start of the region fill, <template s:region="sidebar">
+var layoutFillRe = regexp.MustCompile(`<template\s+s:region=["']([^"']+)["']\s*>`)
+
+// Layout get the layout name of the page
+func (page *Page) Layout() string {
+	return strings.TrimSpace(page.GetConfig().Layout)
+}
+
+// composeLayout compose the layout chain of the page before rendering, the page fills the regions of the layout
+func (page *Page) composeLayout(code string) (string, error) {
+	name := page.Layout()
+	if name == "" {
+		return code, nil
+	}
+	res, err := ComposeLayout(code, name, page.Layouts)
+	if err != nil {
+		return "", fmt.Errorf("%s %s", page.Route, err.Error())
+	}
+	return res, nil
+}
+
+// ComposeLayout compose the layout chain (site → section → page) of the source. The source fills the regions of the
+// layout by <template s:region="name">, the rest of the source fills the content region. The layout extends the parent
+// layout by <s:layout extends="parent">, its output fills the content region of the parent, and the regions it does not
+// declare are passed up to the parent.
+func ComposeLayout(source string, name string, layouts map[string][]byte) (string, error) {
+	content, regions := layoutFills(source)
+	if _, has := regions[LayoutContent]; !has {
+		regions[LayoutContent] = content
+	}
+
+	chain := []string{}
+	for {
+		for _, prev := range chain {
+			if prev == name {
+				return "", fmt.Errorf("layout cycle %s → %s", strings.Join(chain, " → "), name)
+			}
+		}
+		chain = append(chain, name)
+		if len(chain) > MaxLayoutDepth {
+			return "", fmt.Errorf("layout chain %s is too deep (max %d)", strings.Join(chain, " → "), MaxLayoutDepth)
+		}
+
+		layout, has := layouts[name]
+		if !has {
+			return "", fmt.Errorf("layout %s not found", name)
+		}
+
+		parent, body := layoutExtends(string(layout))
+		output := fillRegions(body, regions)
+		if parent == "" {
+			return output, nil
+		}
+
+		// The fills of the layout for the parent, the fills of the child win
+		content, fills := layoutFills(output)
+		for region, fill := range fills {
+			if _, has := regions[region]; !has {
+				regions[region] = fill
+			}
+		}
+		regions[LayoutContent] = content
+		name = parent
+	}
+}
+
+// LayoutChain get the layout chain of the layout, the layout first and the root layout last, the missing layouts
+// and the cycles are not included
+func LayoutChain(name string, layouts map[string][]byte) []string {
+	chain := []string{}
+	seen := map[string]bool{}
+	for name != "" && !seen[name] && len(chain) < MaxLayoutDepth {
+		layout, has := layouts[name]
+		if !has {
+			break
+		}
+		seen[name] = true
+		chain = append(chain, name)
+		name, _ = layoutExtends(string(layout))
+	}
+	return chain
+}
+
+// layoutExtends get the parent layout and the body of the layout
+func layoutExtends(source string) (string, string) {
+	matches := layoutExtendsRe.FindStringSubmatch(source)
+	if matches == nil {
+		return "", source
+	}
+	return strings.TrimSpace(matches[1]), matches[2]
+}
+
+// fillRegions replace the regions of the layout with the fills, the filled regions are removed from the fills
+func fillRegions(layout string, regions map[string]string) string {
+	matches := layoutRegionRe.FindAllStringSubmatchIndex(layout, -1)
+	if len(matches) == 0 {
+		return layout
+	}
+
+	var res strings.Builder
+	last := 0
+	for _, m := range matches {
+		res.WriteString(layout[last:m[0]])
+		name := layout[m[2]:m[3]]
+		if fill, has := regions[name]; has {
+			res.WriteString(fill)
+			delete(regions, name)
+		} else if m[4] >= 0 {
+			res.WriteString(layout[m[4]:m[5]]) // the fallback
+		}
+		last = m[1]
+	}
+	res.WriteString(layout[last:])
+	return res.String()
+}
+
+// layoutFills get the region fills of the source, <template s:region="name"> ... </template>, and the rest of the source
+func layoutFills(source string) (string, map[string]string) {
+	fills := map[string]string{}
+	var rest strings.Builder
+	for {
+		m := layoutFillRe.FindStringSubmatchIndex(source)
+		if m == nil {
+			break
+		}
+		end, closing := templateEnd(source, m[1])
+		if end < 0 {
+			break
+		}
+		rest.WriteString(source[:m[0]])
+		fills[source[m[2]:m[3]]] = source[m[1]:end]
+		source = source[closing:]
+	}
+	rest.WriteString(source)
+	return strings.TrimSpace(rest.String()), fills
+}
+
+// templateEnd find the end of the template started at the offset, the nested templates are skipped.
+// return the start and the end of the closing tag, -1 if the template is not closed
+func templateEnd(source string, offset int) (int, int) {
+	depth := 1
+	for offset < len(source) {
+		open := strings.Index(source[offset:], "<template")
+		closing := strings.Index(source[offset:], "</template>")
+		if closing < 0 {
+			return -1, -1
+		}
+		if open >= 0 && open < closing {
+			depth++
+			offset += open + len("<template")
+			continue
+		}
+		depth--
+		if depth == 0 {
+			return offset + closing, offset + closing + len("</template>")
+		}
+		offset += closing + len("</template>")
+	}
+	return -1, -1
+}
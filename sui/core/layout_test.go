@@ -0,0 +1,40 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComposeLayout(t *testing.T) {
+	layouts := map[string][]byte{
+		"site": []byte(`<header><s:region name="header">Site</s:region></header>` +
+			`<main><s:region name="content"/></main><footer><s:region name="footer">&copy;</s:region></footer>`),
+		"admin": []byte(`<s:layout extends="site">` +
+			`<template s:region="header">Admin</template>` +
+			`<aside><s:region name="sidebar"><nav>Menu</nav></s:region></aside><section><s:region name="content"/></section>` +
+			`</s:layout>`),
+		"loop":  []byte(`<s:layout extends="loop2"><s:region name="content"/></s:layout>`),
+		"loop2": []byte(`<s:layout extends="loop"><s:region name="content"/></s:layout>`),
+	}
+
+	// The page fills the content, the default regions of the layouts are kept
+	res, err := ComposeLayout(`<h1>Users</h1>`, "admin", layouts)
+	assert.Nil(t, err)
+	assert.Equal(t, `<header>Admin</header><main><aside><nav>Menu</nav></aside><section><h1>Users</h1></section></main><footer>&copy;</footer>`, res)
+
+	// The page fills the regions of the section and the site, the fills of the page win
+	res, err = ComposeLayout(`<template s:region="sidebar"><template><b>Nested</b></template></template>`+
+		`<template s:region="header">Users</template><template s:region="footer">Page</template><h1>Users</h1>`, "admin", layouts)
+	assert.Nil(t, err)
+	assert.Equal(t, `<header>Users</header><main><aside><template><b>Nested</b></template></aside><section><h1>Users</h1></section></main><footer>Page</footer>`, res)
+
+	assert.Equal(t, []string{"admin", "site"}, LayoutChain("admin", layouts))
+	assert.Equal(t, []string{"loop", "loop2"}, LayoutChain("loop", layouts))
+
+	_, err = ComposeLayout(`<h1>Users</h1>`, "loop", layouts)
+	assert.Contains(t, err.Error(), "layout cycle loop → loop2 → loop")
+
+	_, err = ComposeLayout(`<h1>Users</h1>`, "missing", layouts)
+	assert.Contains(t, err.Error(), "layout missing not found")
+}
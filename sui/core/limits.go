@@ -0,0 +1,124 @@
+package core
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// RenderLimits the resource limits of the rendering, a malicious or a buggy template can not
+// exhaust the memory of the server or recurse forever. the limits are applied to all the renderings
+type RenderLimits struct {
+	MaxNodes          int `json:"maxNodes,omitempty"`          // the max elements to render, DefaultMaxNodes if 0
+	MaxLoopItems      int `json:"maxLoopItems,omitempty"`      // the max items of a s:for loop, DefaultMaxLoopItems if 0
+	MaxComponentDepth int `json:"maxComponentDepth,omitempty"` // the max nesting depth of the components, DefaultMaxComponentDepth if 0
+	MaxProcessCalls   int `json:"maxProcessCalls,omitempty"`   // the max calls of the process functions, DefaultMaxProcessCalls if 0
+}
+
+// DefaultMaxNodes the max elements to render
+var DefaultMaxNodes = 1000000
+
+// DefaultMaxLoopItems the max items of a s:for loop
+var DefaultMaxLoopItems = 100000
+
+// DefaultMaxComponentDepth the max nesting depth of the components
+var DefaultMaxComponentDepth = 64
+
+// DefaultMaxProcessCalls the max calls of the process functions in a rendering, the memoized results are not counted
+var DefaultMaxProcessCalls = 100
+
+func (limits *RenderLimits) maxNodes() int {
+	if limits != nil && limits.MaxNodes > 0 {
+		return limits.MaxNodes
+	}
+	return DefaultMaxNodes
+}
+
+func (limits *RenderLimits) maxLoopItems() int {
+	if limits != nil && limits.MaxLoopItems > 0 {
+		return limits.MaxLoopItems
+	}
+	return DefaultMaxLoopItems
+}
+
+func (limits *RenderLimits) maxComponentDepth() int {
+	if limits != nil && limits.MaxComponentDepth > 0 {
+		return limits.MaxComponentDepth
+	}
+	return DefaultMaxComponentDepth
+}
+
+func (limits *RenderLimits) maxProcessCalls() int {
+	if limits != nil && limits.MaxProcessCalls > 0 {
+		return limits.MaxProcessCalls
+	}
+	return DefaultMaxProcessCalls
+}
+
+// nodeCounter the rendered elements of the rendering, shared by the parsers of the components rendered in parallel
+type nodeCounter struct {
+	n int64
+}
+
+func (counter *nodeCounter) add() int {
+	return int(atomic.AddInt64(&counter.n, 1))
+}
+
+func (counter *nodeCounter) load() int {
+	return int(atomic.LoadInt64(&counter.n))
+}
+
+func (counter *nodeCounter) reset() {
+	atomic.StoreInt64(&counter.n, 0)
+}
+
+// limitNode count the rendered elements, return false if the node should not be rendered
+func (parser *TemplateParser) limitNode(sel *goquery.Selection) bool {
+	if parser.countNode(sel.Nodes[0]) {
+		return true
+	}
+	parser.restrictRemove(sel)
+	return false
+}
+
+// countNode count the rendered element, return false if the element is over the max elements
+func (parser *TemplateParser) countNode(node *html.Node) bool {
+	max := parser.option.Limits.maxNodes()
+	n := parser.nodes.add()
+	if n <= max {
+		return true
+	}
+
+	if n == max+1 {
+		parser.renderError(node, "limits", "", fmt.Errorf("limits: the template has more than %d elements", max))
+	}
+	return false
+}
+
+// limitLoop limit the items of the s:for loop
+func (parser *TemplateParser) limitLoop(node *html.Node, items []interface{}, keys []string) ([]interface{}, []string) {
+	max := parser.option.Limits.maxLoopItems()
+	if len(items) <= max {
+		return items, keys
+	}
+
+	parser.renderError(node, "s:for", "", fmt.Errorf("limits: the loop has more than %d items", max))
+	if keys != nil {
+		keys = keys[:max]
+	}
+	return items[:max], keys
+}
+
+// limitDepth check the nesting depth of the components, return false if the component should not be rendered
+func (parser *TemplateParser) limitDepth(sel *goquery.Selection, directive string) bool {
+	max := parser.option.Limits.maxComponentDepth()
+	if parser.depth < max {
+		return true
+	}
+
+	err := fmt.Errorf("limits: the components are nested more than %d levels", max)
+	parser.componentError(sel, directive, sel.AttrOr(directive, ""), err)
+	return false
+}
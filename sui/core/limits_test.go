@@ -0,0 +1,51 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderLimits(t *testing.T) {
+	// The max elements
+	source := `<html><head></head><body><ul><li s:for="[1,2,3,4,5]">{{ item }}</li></ul></body></html>`
+	parser := NewTemplateParser(Data{}, &ParserOption{Request: &Request{}, Limits: &RenderLimits{MaxNodes: 7}})
+	html, err := parser.Render(source)
+	assert.Nil(t, err)
+	assert.Contains(t, html, "<li>2</li>")
+	assert.NotContains(t, html, "<li>3</li>")
+	assert.Len(t, parser.Errors(), 1)
+
+	// The max items of the loop
+	parser = NewTemplateParser(Data{}, &ParserOption{Request: &Request{}, Limits: &RenderLimits{MaxLoopItems: 2}})
+	html, err = parser.Render(source)
+	assert.Nil(t, err)
+	assert.Contains(t, html, "<li>2</li>")
+	assert.NotContains(t, html, "<li>3</li>")
+	assert.Len(t, parser.Errors(), 1)
+
+	// The defaults
+	parser = NewTemplateParser(Data{}, &ParserOption{Request: &Request{}})
+	html, err = parser.Render(source)
+	assert.Nil(t, err)
+	assert.Contains(t, html, "<li>5</li>")
+	assert.Empty(t, parser.Errors())
+}
+
+func TestRenderLimitsDepth(t *testing.T) {
+	// The component renders itself forever
+	Components["/test/loop"] = &JitComponent{
+		route:       "/test/loop",
+		html:        `<div class="loop"><div is="/test/loop" s:jit></div></div>`,
+		buildOption: &BuildOption{},
+	}
+	defer delete(Components, "/test/loop")
+
+	source := `<html><head></head><body><div is="/test/loop" s:jit></div></body></html>`
+	parser := NewTemplateParser(Data{}, &ParserOption{Request: &Request{}, Limits: &RenderLimits{MaxComponentDepth: 3}})
+	html, err := parser.Render(source)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, strings.Count(html, `class="loop"`))
+	assert.NotEmpty(t, parser.Errors())
+}
@@ -0,0 +1,223 @@
+package core
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// LinkReportFile the report of the broken links in the public root, written by the build
+const LinkReportFile = ".links.json"
+
+// linkAttrs the attributes of the links and the asset references
+var linkAttrs = map[string][]string{
+	"a":      {"href"},
+	"area":   {"href"},
+	"link":   {"href"},
+	"img":    {"src", "srcset"},
+	"script": {"src"},
+	"source": {"src", "srcset"},
+	"video":  {"src", "poster"},
+	"audio":  {"src"},
+	"iframe": {"src"},
+}
+
+// LinkIgnore the path prefixes of the links are not checked, e.g. the api endpoints
+var LinkIgnore = []string{"/api/"}
+
+// LinkRef the internal link or the asset reference of the built page, located in the template source
+type LinkRef struct {
+	Page  string `json:"page"`           // the route of the page
+	File  string `json:"file,omitempty"` // the template source of the page
+	Line  int    `json:"line,omitempty"` // the line in the template source, 0 if the link is in a component
+	Path  string `json:"path,omitempty"` // the CSS-like selector of the node, see NodePath
+	Attr  string `json:"attr"`
+	URL   string `json:"url"`
+	Asset bool   `json:"asset,omitempty"`
+}
+
+// BrokenLink the link to the unknown route or the reference to the missing asset
+type BrokenLink struct {
+	LinkRef
+	Message string `json:"message"`
+}
+
+// Error the message of the broken link
+func (b BrokenLink) Error() string {
+	location := b.File
+	if location == "" {
+		location = b.Page
+	}
+	if b.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, b.Line)
+	}
+	return fmt.Sprintf("%s %s %s=%q %s", location, b.Path, b.Attr, b.URL, b.Message)
+}
+
+// recordLinks record the route and the internal links and the asset references of the built page,
+// the links of the expressions are checked by the rendering
+func (m *BuildManifest) recordLinks(page *Page, doc *goquery.Document) {
+	m.routes[page.Route] = true
+	assets := doc.Find("body").AttrOr("s:assets", "")
+	source := page.Codes.HTML.Code
+
+	for _, node := range doc.Find("*").Nodes {
+		attrs, has := linkAttrs[node.Data]
+		if !has {
+			continue
+		}
+
+		for _, attr := range node.Attr {
+			if !inStrings(attr.Key, attrs) {
+				continue
+			}
+
+			values := []string{attr.Val}
+			if attr.Key == "srcset" {
+				values = srcsetURLs(attr.Val)
+			}
+			for _, value := range values {
+				value = strings.TrimSpace(value)
+				if !internalLink(value) {
+					continue
+				}
+
+				ref := LinkRef{Page: page.Route, File: page.Codes.HTML.File, Path: NodePath(node), Attr: attr.Key, URL: value}
+				ref.Asset = assets != "" && strings.HasPrefix(value, strings.TrimSuffix(assets, "/")+"/")
+				ref.Line = sourceLine(source, value, assets)
+				m.links = append(m.links, ref)
+			}
+		}
+	}
+}
+
+// CheckLinks check the links of the built pages, the links must resolve to the built routes and the assets must
+// exist. the root is the public root, the exists checks the asset file of the path (e.g. /demo/assets/logo.png)
+func (m *BuildManifest) CheckLinks(root string, exists func(file string) bool) []BrokenLink {
+	m.Routes = compatKeys(m.routes)
+	broken := []BrokenLink{}
+	checked := map[string]bool{}
+	for _, ref := range m.links {
+		target, err := url.Parse(ref.URL)
+		if err != nil {
+			broken = append(broken, BrokenLink{LinkRef: ref, Message: fmt.Sprintf("the url is invalid, %s", err.Error())})
+			continue
+		}
+
+		file := target.Path
+		if !strings.HasPrefix(file, "/") {
+			file = path.Join(path.Dir(path.Join("/", strings.TrimPrefix(root, "/"), ref.Page)), file)
+		}
+		if linkIgnored(root, file) {
+			continue
+		}
+
+		if ref.Asset || path.Ext(file) != "" {
+			key := "asset:" + file
+			ok, has := checked[key]
+			if !has {
+				ok = exists(path.Clean(file))
+				checked[key] = ok
+			}
+			if !ok {
+				broken = append(broken, BrokenLink{LinkRef: ref, Message: "the asset does not exist"})
+			}
+			continue
+		}
+
+		if !m.hasRoute(root, file) {
+			broken = append(broken, BrokenLink{LinkRef: ref, Message: "the route does not exist"})
+		}
+	}
+
+	sort.SliceStable(broken, func(i, j int) bool { return broken[i].Page < broken[j].Page })
+	return broken
+}
+
+// hasRoute check if the path is one of the built routes, the dynamic segments ([id]) match any segment
+func (m *BuildManifest) hasRoute(root string, file string) bool {
+	root = "/" + strings.Trim(root, "/")
+	file = path.Clean("/" + strings.TrimPrefix(file, "/"))
+	if root != "/" && (file == root || strings.HasPrefix(file, root+"/")) {
+		file = path.Clean("/" + strings.TrimPrefix(file, root))
+	}
+	if file == "/" {
+		file = "/index"
+	}
+
+	for route := range m.routes {
+		if route == file || route == file+"/index" || routeMatch(strings.Split(route, "/"), strings.Split(file, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// linkIgnored check if the path is out of the public root or ignored by the LinkIgnore
+func linkIgnored(root string, file string) bool {
+	for _, prefix := range LinkIgnore {
+		if strings.HasPrefix(file, prefix) {
+			return true
+		}
+	}
+	root = "/" + strings.Trim(root, "/")
+	return root != "/" && file != root && !strings.HasPrefix(file, root+"/")
+}
+
+func routeMatch(route []string, file []string) bool {
+	if len(route) != len(file) {
+		return false
+	}
+	for i, segment := range route {
+		if strings.HasPrefix(segment, "[") && strings.HasSuffix(segment, "]") && file[i] != "" {
+			continue
+		}
+		if segment != file[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// internalLink check if the url is the static internal link, the external links, the anchors and the
+// expressions are skipped
+func internalLink(value string) bool {
+	if value == "" || strings.HasPrefix(value, "#") || strings.HasPrefix(value, "//") {
+		return false
+	}
+	if strings.Contains(value, "{{") || strings.Contains(value, "{%") || strings.Contains(value, "[{") {
+		return false
+	}
+	if target, err := url.Parse(value); err == nil && target.Scheme != "" {
+		return false // http:, mailto:, tel:, data:, javascript:
+	}
+	return !strings.HasPrefix(value, "?")
+}
+
+// srcsetURLs get the urls of the srcset, e.g. "a.png 1x, b.png 2x"
+func srcsetURLs(value string) []string {
+	urls := []string{}
+	for _, candidate := range strings.Split(value, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) > 0 {
+			urls = append(urls, fields[0])
+		}
+	}
+	return urls
+}
+
+// sourceLine get the line of the url in the template source, the asset root is @assets in the source
+func sourceLine(source string, value string, assets string) int {
+	i := strings.Index(source, value)
+	if i < 0 && assets != "" && strings.HasPrefix(value, assets) {
+		i = strings.Index(source, "@assets"+strings.TrimPrefix(value, assets))
+	}
+	if i < 0 {
+		return 0
+	}
+	return strings.Count(source[:i], "\n") + 1
+}
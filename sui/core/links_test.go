@@ -0,0 +1,44 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildManifestCheckLinks(t *testing.T) {
+	source := "<div>\n" +
+		"<a href=\"/demo/about\">About</a>\n" +
+		"<a href=\"/demo/blog/hello?ref=home#top\">Post</a>\n" +
+		"<a href=\"/demo/contact\">Contact</a>\n" +
+		"<a href=\"team\">Team</a>\n" +
+		"<img src=\"@assets/logo.png\" srcset=\"@assets/logo.png 1x, @assets/logo@2x.png 2x\"/>\n" +
+		"<a href=\"https://example.com\">Out</a><a href=\"#top\">Top</a><a href=\"/api/__yao/app\">Api</a>\n" +
+		"<a href=\"/demo/{{ slug }}\">Bound</a>\n" +
+		"</div>"
+
+	doc, err := NewDocumentString(`<html><body s:assets="/demo/assets">` + source + `</body></html>`)
+	assert.Nil(t, err)
+	doc.Find("img").SetAttr("src", "/demo/assets/logo.png")
+	doc.Find("img").SetAttr("srcset", "/demo/assets/logo.png 1x, /demo/assets/logo@2x.png 2x")
+
+	manifest := NewBuildManifest()
+	manifest.Record(&Page{Route: "/index", Codes: SourceCodes{HTML: Source{File: "/index/index.html", Code: source}}}, doc)
+	empty, err := NewDocumentString(`<html><body></body></html>`)
+	assert.Nil(t, err)
+	manifest.Record(&Page{Route: "/about"}, empty)
+	manifest.Record(&Page{Route: "/blog/[slug]"}, empty)
+
+	exists := func(file string) bool { return file == "/demo/assets/logo.png" }
+	broken := manifest.CheckLinks("/demo", exists)
+	messages := []string{}
+	for _, link := range broken {
+		messages = append(messages, link.Error())
+	}
+	assert.Equal(t, []string{
+		`/index/index.html:4 html > body:nth-child(2) > div > a:nth-child(3) href="/demo/contact" the route does not exist`,
+		`/index/index.html:5 html > body:nth-child(2) > div > a:nth-child(4) href="team" the route does not exist`,
+		`/index/index.html:6 html > body:nth-child(2) > div > img:nth-child(5) srcset="/demo/assets/logo@2x.png" the asset does not exist`,
+	}, messages)
+	assert.Equal(t, []string{"/about", "/blog/[slug]", "/index"}, manifest.Routes)
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/yaoapp/gou/application"
@@ -16,6 +17,9 @@ import (
 // Locales the locales
 var Locales = map[string]map[string]*Locale{}
 
+// LocaleVersion the version of the loaded locales, increased by ReloadLocales
+var LocaleVersion uint64 = 1
+
 type localeData struct {
 	name   string
 	path   string
@@ -28,6 +32,7 @@ var chLocale = make(chan *localeData, 1)
 const (
 	saveLocale uint8 = iota
 	removeLocale
+	cleanLocale
 )
 
 func init() {
@@ -49,6 +54,13 @@ func localeWriter() {
 				if _, ok := Locales[data.name]; ok {
 					delete(Locales[data.name], data.path)
 				}
+
+			case cleanLocale:
+				if data.name == "" {
+					Locales = map[string]map[string]*Locale{}
+					break
+				}
+				delete(Locales, data.name)
 			}
 		}
 	}
@@ -62,7 +74,6 @@ func (parser *TemplateParser) Locale() *Locale {
 		return nil
 	}
 
-	root := parser.option.Root
 	route := parser.option.Route
 	disableCache := parser.option.Preview || parser.option.Debug || parser.option.Editor || parser.option.DisableCache
 	locales, ok = Locales[name]
@@ -71,11 +82,81 @@ func (parser *TemplateParser) Locale() *Locale {
 	}
 
 	locale, ok := locales[route]
-	if ok && !disableCache {
+	if ok && !disableCache && locale.version == atomic.LoadUint64(&LocaleVersion) {
 		return locale
 	}
 
-	path := filepath.Join("public", parser.option.Root, ".locales", name, strings.TrimPrefix(route, root)+".yml")
+	// Load the locale and the fallbacks, the missing translations are inherited from the fallbacks in order
+	var files []*Locale
+	for _, lang := range LocaleChain(route, name) {
+		if file := parser.localeFile(lang); file != nil {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	locale = mergeLocales(name, files)
+	locale.version = atomic.LoadUint64(&LocaleVersion)
+
+	if locale.Timezone == "" {
+		locale.Timezone = GetSystemTimezone()
+	}
+
+	if locale.Direction == "" {
+		locale.Direction = "ltr"
+	}
+
+	if parser.data != nil {
+		parser.data["$timezone"] = locale.Timezone
+		parser.data["$direction"] = locale.Direction
+	}
+
+	chLocale <- &localeData{name, route, locale, saveLocale}
+	return locale
+}
+
+// LocaleChain the fallback chain of the locale of the route, the locale itself first. the chain is the fallbacks of the
+// locale in the i18n section if set, e.g. pt-br, pt-pt, pt, otherwise the parents of the locale and the default locale,
+// e.g. fr-ca, fr, en. the empty fallbacks disable the fallback of the locale
+func LocaleChain(route string, name string) []string {
+	chain := []string{name}
+	add := func(lang string) {
+		for _, n := range chain {
+			if strings.EqualFold(n, lang) {
+				return
+			}
+		}
+		if lang != "" {
+			chain = append(chain, lang)
+		}
+	}
+
+	name = strings.ToLower(name)
+	routing := GetLocaleRouting(route)
+	if routing != nil {
+		if fallbacks, has := routing.Fallbacks[name]; has {
+			for _, lang := range fallbacks {
+				add(lang)
+			}
+			return chain
+		}
+	}
+
+	for lang := name; strings.LastIndexAny(lang, "-_") > 0; {
+		lang = lang[:strings.LastIndexAny(lang, "-_")]
+		add(lang)
+	}
+	if routing != nil {
+		add(routing.Default)
+	}
+	return chain
+}
+
+// localeFile read the locale file of the route, nil if the file is not found
+func (parser *TemplateParser) localeFile(name string) *Locale {
+	route := parser.option.Route
+	path := ReleaseFile(filepath.Join("public", parser.option.Root, ".locales", name, strings.TrimPrefix(route, parser.option.Root)+".yml"))
 	if exists, err := application.App.Exists(path); !exists {
 		if err != nil {
 			log.Error("[parser] %s Locale %s", route, err.Error())
@@ -83,36 +164,67 @@ func (parser *TemplateParser) Locale() *Locale {
 		return nil
 	}
 
-	// Load the locale
-	locale = &Locale{Name: name}
-
 	raw, err := application.App.Read(path)
 	if err != nil {
 		log.Error("[parser] %s Locale %s", route, err.Error())
 		return nil
 	}
 
+	locale := &Locale{Name: name}
 	err = yaml.Unmarshal(raw, locale)
 	if err != nil {
 		log.Error("[parser] %s Locale %s", route, err.Error())
 		return nil
 	}
+	return locale
+}
 
-	if locale.Timezone == "" {
-		locale.Timezone = GetSystemTimezone()
+// mergeLocales merge the locale files of the fallback chain, the translations of the former files win
+func mergeLocales(name string, files []*Locale) *Locale {
+	locale := &Locale{Name: name, Keys: map[string]string{}, Messages: map[string]string{}, ScriptMessages: map[string]string{}}
+	for i := len(files) - 1; i >= 0; i-- {
+		file := files[i]
+		for key, message := range file.Keys {
+			locale.Keys[key] = message
+		}
+		for message, translated := range file.Messages {
+			locale.Messages[message] = translated
+		}
+		for message, translated := range file.ScriptMessages {
+			locale.ScriptMessages[message] = translated
+		}
+		if file.Formatter != "" {
+			locale.Formatter = file.Formatter
+		}
+		if file.Direction != "" {
+			locale.Direction = file.Direction
+		}
+		if file.Timezone != "" {
+			locale.Timezone = file.Timezone
+		}
 	}
+	return locale
+}
 
-	if locale.Direction == "" {
-		locale.Direction = "ltr"
+// ReloadLocales invalidate the loaded locales, the locale files will be read again on the next render.
+// if the names are not given, all the locales will be reloaded. returns the new locale version.
+func ReloadLocales(names ...string) uint64 {
+	version := atomic.AddUint64(&LocaleVersion, 1)
+	if len(names) == 0 {
+		chLocale <- &localeData{"", "", nil, cleanLocale}
+		return version
 	}
 
-	if parser.data != nil {
-		parser.data["$timezone"] = locale.Timezone
-		parser.data["$direction"] = locale.Direction
+	for _, name := range names {
+		chLocale <- &localeData{name, "", nil, cleanLocale}
 	}
+	return version
+}
 
-	chLocale <- &localeData{name, route, locale, saveLocale}
-	return locale
+// IsLocaleFile check if the file is a locale file of the SUI pages
+func IsLocaleFile(file string) bool {
+	file = filepath.ToSlash(file)
+	return strings.Contains(file, "/.locales/") && (strings.HasSuffix(file, ".yml") || strings.HasSuffix(file, ".yaml"))
 }
 
 // MergeTranslations merge the translations
@@ -1,7 +1,14 @@
 package core
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/gou/application"
 )
 
 func TestLocaleMergeTranslations(t *testing.T) {
@@ -196,6 +203,29 @@ func TestLocaleMerge(t *testing.T) {
 	}
 }
 
+func TestLocaleReload(t *testing.T) {
+	version := LocaleVersion
+	if v := ReloadLocales(); v != version+1 {
+		t.Errorf("expected version %d, got %d", version+1, v)
+	}
+
+	if v := ReloadLocales("zh-cn", "en-us"); v != version+2 {
+		t.Errorf("expected version %d, got %d", version+2, v)
+	}
+
+	files := map[string]bool{
+		"/public/.locales/zh-cn/index.yml":     true,
+		"/public/app/.locales/en-us/page.yaml": true,
+		"/public/.locales/zh-cn/index.json":    false,
+		"/public/index.yml":                    false,
+	}
+	for file, expected := range files {
+		if IsLocaleFile(file) != expected {
+			t.Errorf("IsLocaleFile(%s) expected %v", file, expected)
+		}
+	}
+}
+
 func testCompareMaps(a, b map[string]string) bool {
 	if len(a) != len(b) {
 		return false
@@ -207,3 +237,57 @@ func testCompareMaps(a, b map[string]string) bool {
 	}
 	return true
 }
+
+func TestLocaleChain(t *testing.T) {
+	assert.Equal(t, []string{"fr-CA", "fr"}, LocaleChain("/chain", "fr-CA"))
+	assert.Equal(t, []string{"zh-hant-tw", "zh-hant", "zh"}, LocaleChain("/chain", "zh-hant-tw"))
+
+	RegisterLocaleRouting("/chain", &LocaleRouting{
+		Locales:   []string{"en", "fr", "fr-ca", "pt-br"},
+		Fallbacks: map[string][]string{"pt-BR": {"pt-PT", "pt"}, "en-gb": {}},
+	})
+	defer RegisterLocaleRouting("/chain", nil)
+	assert.Equal(t, []string{"fr-CA", "fr", "en"}, LocaleChain("/chain/page", "fr-CA"))
+	assert.Equal(t, []string{"pt-br", "pt-pt", "pt"}, LocaleChain("/chain/page", "pt-br"))
+	assert.Equal(t, []string{"en-gb"}, LocaleChain("/chain/page", "en-gb"))
+	assert.Equal(t, []string{"en"}, LocaleChain("/chain/page", "en"))
+}
+
+func TestLocaleFallback(t *testing.T) {
+	id := fmt.Sprintf("__locale_test_%d", time.Now().UnixNano())
+	root := "/" + id
+	public := filepath.Join(application.App.Root(), "public", id)
+	defer os.RemoveAll(public)
+
+	write := func(name string, content string) {
+		file := filepath.Join(public, ".locales", name, "page.yml")
+		assert.Nil(t, os.MkdirAll(filepath.Dir(file), 0755))
+		assert.Nil(t, os.WriteFile(file, []byte(content), 0644))
+	}
+	write("en", "keys:\n  title: Title\n  footer: Footer\nscript_messages:\n  Saved: Saved\n")
+	write("fr", "direction: ltr\nkeys:\n  title: Titre\nmessages:\n  Cart: Panier\nscript_messages:\n  Saved: Enregistré\n")
+	write("fr-ca", "keys:\n  title: Titre (CA)\n")
+
+	RegisterLocaleRouting(root, &LocaleRouting{Locales: []string{"en", "fr", "fr-ca"}, Default: "en"})
+	defer RegisterLocaleRouting(root, nil)
+
+	parser := NewTemplateParser(Data{}, &ParserOption{Root: root, Route: root + "/page", Locale: "fr-ca", DisableCache: true})
+	locale := parser.Locale()
+	assert.NotNil(t, locale)
+	assert.Equal(t, "fr-ca", locale.Name)
+	assert.Equal(t, "Titre (CA)", locale.Keys["title"])
+	assert.Equal(t, "Footer", locale.Keys["footer"])
+	assert.Equal(t, "Panier", locale.Messages["Cart"])
+	assert.Equal(t, "Enregistré", locale.ScriptMessages["Saved"])
+
+	// The locale without the file inherits the translations of the fallbacks
+	parser = NewTemplateParser(Data{}, &ParserOption{Root: root, Route: root + "/page", Locale: "fr-be", DisableCache: true})
+	locale = parser.Locale()
+	assert.NotNil(t, locale)
+	assert.Equal(t, "fr-be", locale.Name)
+	assert.Equal(t, "Titre", locale.Keys["title"])
+
+	// The locale without the file and the fallbacks
+	parser = NewTemplateParser(Data{}, &ParserOption{Root: "/" + id + "_missing", Route: "/" + id + "_missing/page", Locale: "de", DisableCache: true})
+	assert.Nil(t, parser.Locale())
+}
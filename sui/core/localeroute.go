@@ -0,0 +1,266 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// LocaleRouting the locale prefixes and the localized slugs of the pages, /en/pricing and /zh-cn/定价 are the same page.
+// set in the i18n section of the sui DSL
+//
+//	"i18n": {
+//	  "locales": ["en", "zh-cn"],
+//	  "default": "en",
+//	  "prefix_default": false,              // /pricing is the default locale, /en/pricing is redirected to /pricing
+//	  "slugs": {"zh-cn": {"pricing": "定价"}}, // the localized segments of the routes
+//	  "geo_header": "CF-IPCountry",          // the country header of the CDN
+//	  "geo": {"CN": "zh-cn"},                // the country and the locale
+//	  "fallbacks": {"pt-br": ["pt-pt", "pt"]} // the fallback chains of the translations, see LocaleChain
+//	}
+//
+// the locale of the request without the prefix is detected by the cookie, the Accept-Language and the geo header,
+// and the request is redirected to the prefixed route. if the default locale is not prefixed, the unprefixed routes are
+// the default locale and only the home page is redirected
+type LocaleRouting struct {
+	Locales       []string                     `json:"locales"`
+	Default       string                       `json:"default,omitempty"`
+	PrefixDefault bool                         `json:"prefix_default,omitempty"`
+	Slugs         map[string]map[string]string `json:"slugs,omitempty"`
+	GeoHeader     string                       `json:"geo_header,omitempty"`
+	Geo           map[string]string            `json:"geo,omitempty"`
+	Fallbacks     map[string][]string          `json:"fallbacks,omitempty"`
+	prefix        string
+	reverse       map[string]map[string]string // the localized slugs and the segments
+	matcher       language.Matcher
+}
+
+// LocaleLink the link of the page in the locale, for the language menus, see $locales
+type LocaleLink struct {
+	Locale string `json:"locale"`
+	URL    string `json:"url"`
+	Active bool   `json:"active"`
+}
+
+// LocaleRoute the locale of the request resolved by the locale prefix
+type LocaleRoute struct {
+	Locale string
+	Links  []LocaleLink
+}
+
+var localeRoutings = map[string]*LocaleRouting{}
+var localeRoutingMutex sync.RWMutex
+
+// RegisterLocaleRouting set the locale routing of the route prefix, e.g. the public root of the sui
+func RegisterLocaleRouting(prefix string, routing *LocaleRouting) {
+	localeRoutingMutex.Lock()
+	defer localeRoutingMutex.Unlock()
+	prefix = "/" + strings.Trim(prefix, "/")
+	if routing == nil {
+		delete(localeRoutings, prefix)
+		return
+	}
+	routing.prepare(prefix)
+	localeRoutings[prefix] = routing
+}
+
+// GetLocaleRouting get the locale routing of the path, the longest prefix wins, nil if the path has no locale routing
+func GetLocaleRouting(route string) *LocaleRouting {
+	localeRoutingMutex.RLock()
+	defer localeRoutingMutex.RUnlock()
+	var res *LocaleRouting = nil
+	matched := -1
+	for prefix, routing := range localeRoutings {
+		if len(prefix) <= matched {
+			continue
+		}
+		if prefix == "/" || route == prefix || strings.HasPrefix(route, prefix+"/") {
+			res, matched = routing, len(prefix)
+		}
+	}
+	return res
+}
+
+func (routing *LocaleRouting) prepare(prefix string) {
+	routing.prefix = prefix
+	if routing.prefix == "/" {
+		routing.prefix = ""
+	}
+
+	for i, locale := range routing.Locales {
+		routing.Locales[i] = strings.ToLower(locale)
+	}
+	routing.Default = strings.ToLower(routing.Default)
+	if routing.Default == "" && len(routing.Locales) > 0 {
+		routing.Default = routing.Locales[0]
+	}
+
+	fallbacks := map[string][]string{}
+	for locale, chain := range routing.Fallbacks {
+		names := make([]string, 0, len(chain))
+		for _, name := range chain {
+			names = append(names, strings.ToLower(name))
+		}
+		fallbacks[strings.ToLower(locale)] = names
+	}
+	routing.Fallbacks = fallbacks
+
+	routing.reverse = map[string]map[string]string{}
+	for locale, slugs := range routing.Slugs {
+		reverse := map[string]string{}
+		for segment, slug := range slugs {
+			reverse[slug] = segment
+		}
+		routing.reverse[strings.ToLower(locale)] = reverse
+	}
+
+	tags := []language.Tag{}
+	for _, locale := range routing.Locales {
+		tags = append(tags, language.Make(locale))
+	}
+	routing.matcher = language.NewMatcher(tags)
+}
+
+// Resolve get the locale and the page route of the path, e.g. /zh-cn/定价 => zh-cn, /pricing.
+// false if the path has no locale prefix
+func (routing *LocaleRouting) Resolve(route string) (string, string, bool) {
+	rest := strings.TrimPrefix(route, routing.prefix)
+	segments := strings.Split(strings.TrimPrefix(rest, "/"), "/")
+	locale := strings.ToLower(segments[0])
+	if !routing.has(locale) {
+		return "", "", false
+	}
+
+	reverse := routing.reverse[locale]
+	for i, segment := range segments[1:] {
+		if origin, has := reverse[segment]; has {
+			segments[i+1] = origin
+		}
+	}
+	return locale, routing.prefix + "/" + strings.Join(segments[1:], "/"), true
+}
+
+// Localize get the localized path of the page route, e.g. /pricing, zh-cn => /zh-cn/定价
+func (routing *LocaleRouting) Localize(route string, locale string) string {
+	locale = strings.ToLower(locale)
+	rest := strings.TrimPrefix(strings.TrimPrefix(route, routing.prefix), "/")
+	segments := []string{}
+	if rest != "" {
+		segments = strings.Split(rest, "/")
+	}
+
+	slugs := routing.Slugs[locale]
+	for i, segment := range segments {
+		if slug, has := slugs[segment]; has {
+			segments[i] = slug
+		}
+	}
+
+	if locale != routing.Default || routing.PrefixDefault {
+		segments = append([]string{locale}, segments...)
+	}
+
+	localized := routing.prefix + "/" + strings.Join(segments, "/")
+	if strings.HasSuffix(route, "/") && !strings.HasSuffix(localized, "/") {
+		localized = localized + "/" // keep the trailing slash
+	}
+	return localized
+}
+
+// Links get the links of the page route in all locales, for the language menus
+func (routing *LocaleRouting) Links(route string, active string) []LocaleLink {
+	links := []LocaleLink{}
+	for _, locale := range routing.Locales {
+		link := (&url.URL{Path: routing.Localize(route, locale)}).EscapedPath()
+		links = append(links, LocaleLink{Locale: locale, URL: link, Active: locale == strings.ToLower(active)})
+	}
+	return links
+}
+
+// Detect detect the locale of the request by the cookie, the Accept-Language and the geo header
+func (routing *LocaleRouting) Detect(cookie string, acceptLanguage string, headers http.Header) string {
+	if cookie = strings.ToLower(cookie); routing.has(cookie) {
+		return cookie
+	}
+
+	if acceptLanguage != "" {
+		tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+		if err == nil && len(tags) > 0 {
+			_, index, confidence := routing.matcher.Match(tags...)
+			if confidence != language.No && index < len(routing.Locales) {
+				return routing.Locales[index]
+			}
+		}
+	}
+
+	if routing.GeoHeader != "" && headers != nil {
+		if locale, has := routing.Geo[strings.ToUpper(headers.Get(routing.GeoHeader))]; has && routing.has(strings.ToLower(locale)) {
+			return strings.ToLower(locale)
+		}
+	}
+	return routing.Default
+}
+
+// Redirect get the locale route of the request, the locale of the unprefixed path is detected and
+// the path is redirected to the prefixed route. the route is the page route of the prefixed path
+func (routing *LocaleRouting) Redirect(route string, cookie string, acceptLanguage string, headers http.Header) (locale string, page string, redirect string) {
+	if locale, page, ok := routing.Resolve(route); ok {
+		if locale == routing.Default && !routing.PrefixDefault {
+			return "", "", routing.Localize(page, locale)
+		}
+		return locale, page, ""
+	}
+
+	// The unprefixed paths are the default locale, the visitors are redirected on the home page only.
+	// the language menus set the locale cookie to switch to the default locale
+	if !routing.PrefixDefault && strings.TrimSuffix(route, "/") != routing.prefix {
+		return routing.Default, route, ""
+	}
+
+	locale = routing.Detect(cookie, acceptLanguage, headers)
+	if locale == routing.Default && !routing.PrefixDefault {
+		return locale, route, ""
+	}
+	return "", "", routing.Localize(route, locale)
+}
+
+// data the links of the language menus in the page data, see $locales
+func (route *LocaleRoute) data() []interface{} {
+	links := []interface{}{}
+	for _, link := range route.Links {
+		links = append(links, map[string]interface{}{"locale": link.Locale, "url": link.URL, "active": link.Active})
+	}
+	return links
+}
+
+// _localeURL LocaleURL(route, locale) the expression function, the localized URL of the page route
+// e.g. LocaleURL('/pricing', 'zh-cn') => /zh-cn/%E5%AE%9A%E4%BB%B7
+func _localeURL(args ...any) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("LocaleURL(route, locale) expects 2 arguments, got %d", len(args))
+	}
+	route, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("LocaleURL(route, locale) the route should be a string, got %T", args[0])
+	}
+	locale := fmt.Sprintf("%v", args[1])
+
+	routing := GetLocaleRouting(route)
+	if routing == nil || !routing.has(strings.ToLower(locale)) {
+		return route, nil
+	}
+	return (&url.URL{Path: routing.Localize(route, locale)}).EscapedPath(), nil
+}
+
+func (routing *LocaleRouting) has(locale string) bool {
+	for _, l := range routing.Locales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocaleRouting(t *testing.T) {
+	RegisterLocaleRouting("/site", &LocaleRouting{
+		Locales:   []string{"en", "zh-CN"},
+		Default:   "en",
+		Slugs:     map[string]map[string]string{"zh-cn": {"pricing": "定价"}},
+		GeoHeader: "CF-IPCountry",
+		Geo:       map[string]string{"CN": "zh-cn"},
+	})
+	defer RegisterLocaleRouting("/site", nil)
+
+	routing := GetLocaleRouting("/site/zh-cn/定价")
+	if !assert.NotNil(t, routing) {
+		return
+	}
+	assert.Nil(t, GetLocaleRouting("/other/pricing"))
+
+	// The prefixed routes
+	locale, page, redirect := routing.Redirect("/site/zh-cn/定价", "", "", nil)
+	assert.Equal(t, "zh-cn", locale)
+	assert.Equal(t, "/site/pricing", page)
+	assert.Empty(t, redirect)
+
+	// The default locale is not prefixed
+	_, _, redirect = routing.Redirect("/site/en/pricing", "", "", nil)
+	assert.Equal(t, "/site/pricing", redirect)
+	locale, page, redirect = routing.Redirect("/site/pricing", "", "zh-CN,zh;q=0.9", nil)
+	assert.Equal(t, "en", locale)
+	assert.Equal(t, "/site/pricing", page)
+	assert.Empty(t, redirect)
+
+	// The home page is redirected to the detected locale
+	_, _, redirect = routing.Redirect("/site/", "", "zh-CN,zh;q=0.9,en;q=0.8", nil)
+	assert.Equal(t, "/site/zh-cn/", redirect)
+	_, _, redirect = routing.Redirect("/site/", "", "", http.Header{"Cf-Ipcountry": {"cn"}})
+	assert.Equal(t, "/site/zh-cn/", redirect)
+	_, _, redirect = routing.Redirect("/site", "en", "zh-CN", nil)
+	assert.Empty(t, redirect)
+
+	// The language menus
+	links := routing.Links("/site/pricing", "zh-cn")
+	assert.Equal(t, []LocaleLink{
+		{Locale: "en", URL: "/site/pricing", Active: false},
+		{Locale: "zh-cn", URL: "/site/zh-cn/%E5%AE%9A%E4%BB%B7", Active: true},
+	}, links)
+
+	r := &Request{Localized: &LocaleRoute{Locale: "zh-cn", Links: links}}
+	data := r.NewData()
+	assert.Equal(t, "zh-cn", data["$locale"])
+
+	parser := NewTemplateParser(data, &ParserOption{Request: r})
+	html, err := parser.Render(`<nav><a s:for="$locales" s:for-item="link" href="{{ link.url }}" class="{{ link.active ? 'active' : '' }}">{{ link.locale }}</a>` +
+		`<a href="{{ LocaleURL('/site/pricing', 'zh-cn') }}">zh</a></nav>`)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `<a href="/site/zh-cn/%E5%AE%9A%E4%BB%B7" class="active">zh-cn</a>`)
+	assert.Contains(t, html, `<a href="/site/zh-cn/%E5%AE%9A%E4%BB%B7">zh</a>`)
+}
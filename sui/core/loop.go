@@ -0,0 +1,206 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// loopVars the variable names of the s:for loop
+type loopVars struct {
+	item  string
+	index string
+	key   string
+}
+
+// loopModifiers apply the s:for-where, s:for-order, s:for-offset and s:for-limit to the loop items
+// the order: where => order => offset => limit
+func (parser *TemplateParser) loopModifiers(sel *goquery.Selection, items []interface{}, keys []string, vars loopVars) ([]interface{}, []string, error) {
+
+	if where, has := sel.Attr("s:for-where"); has && strings.TrimSpace(where) != "" {
+		filtered := []interface{}{}
+		filteredKeys := []string{}
+		for idx, item := range items {
+			res, err := parser.loopExec(where, item, idx, keys, vars)
+			if err != nil {
+				return nil, nil, fmt.Errorf("for where %s error: %s", where, err.Error())
+			}
+			if res != true {
+				continue
+			}
+			filtered = append(filtered, item)
+			if keys != nil {
+				filteredKeys = append(filteredKeys, keys[idx])
+			}
+		}
+		items = filtered
+		if keys != nil {
+			keys = filteredKeys
+		}
+	}
+
+	if order, has := sel.Attr("s:for-order"); has && strings.TrimSpace(order) != "" {
+		stmt, desc := loopOrder(order)
+		values := make([]interface{}, len(items))
+		for idx, item := range items {
+			res, err := parser.loopExec(stmt, item, idx, keys, vars)
+			if err != nil {
+				return nil, nil, fmt.Errorf("for order %s error: %s", order, err.Error())
+			}
+			values[idx] = res
+		}
+
+		indexes := make([]int, len(items))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		sort.SliceStable(indexes, func(i, j int) bool {
+			if desc {
+				return loopLess(values[indexes[j]], values[indexes[i]])
+			}
+			return loopLess(values[indexes[i]], values[indexes[j]])
+		})
+
+		sorted := make([]interface{}, len(items))
+		var sortedKeys []string
+		if keys != nil {
+			sortedKeys = make([]string, len(keys))
+		}
+		for i, idx := range indexes {
+			sorted[i] = items[idx]
+			if keys != nil {
+				sortedKeys[i] = keys[idx]
+			}
+		}
+		items, keys = sorted, sortedKeys
+	}
+
+	offset, err := parser.loopInt(sel, "s:for-offset")
+	if err != nil {
+		return nil, nil, err
+	}
+	if offset > 0 {
+		if offset > len(items) {
+			offset = len(items)
+		}
+		items = items[offset:]
+		if keys != nil {
+			keys = keys[offset:]
+		}
+	}
+
+	limit, err := parser.loopInt(sel, "s:for-limit")
+	if err != nil {
+		return nil, nil, err
+	}
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+		if keys != nil {
+			keys = keys[:limit]
+		}
+	}
+
+	return items, keys, nil
+}
+
+// loopExec exec the statement with the loop variables
+func (parser *TemplateParser) loopExec(stmt string, item interface{}, idx int, keys []string, vars loopVars) (interface{}, error) {
+	parser.pushScope()
+	defer parser.popScope()
+	parser.setVar(vars.item, item)
+	parser.setVar(vars.index, idx)
+	if keys != nil {
+		parser.setVar(vars.key, keys[idx])
+	}
+	res, _, err := parser.data.ExecGuard(stmt, parser.guard)
+	return res, err
+}
+
+// loopInt get the int value of the s:for-offset and s:for-limit
+func (parser *TemplateParser) loopInt(sel *goquery.Selection, attr string) (int, error) {
+	stmt, has := sel.Attr(attr)
+	if !has || strings.TrimSpace(stmt) == "" {
+		return 0, nil
+	}
+
+	res, _, err := parser.data.ExecGuard(stmt, parser.guard)
+	if err != nil {
+		return 0, fmt.Errorf("%s %s error: %s", attr, stmt, err.Error())
+	}
+
+	switch v := res.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		return int(v), nil
+	case string:
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("%s %s error: %s", attr, stmt, err.Error())
+		}
+		return n, nil
+	}
+	return 0, fmt.Errorf("%s %s error: should be a number", attr, stmt)
+}
+
+// loopOrder parse the order statement, e.g. "item.price desc"
+func loopOrder(order string) (string, bool) {
+	order = strings.TrimSpace(order)
+	lower := strings.ToLower(order)
+	if strings.HasSuffix(lower, " desc") {
+		return strings.TrimSpace(order[:len(order)-5]), true
+	}
+	if strings.HasSuffix(lower, " asc") {
+		return strings.TrimSpace(order[:len(order)-4]), false
+	}
+	return order, false
+}
+
+// loopLess compare the values, numbers are compared numerically, nil is the smallest
+func loopLess(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b != nil
+	}
+
+	fa, oka := loopNumber(a)
+	fb, okb := loopNumber(b)
+	if oka && okb {
+		return fa < fb
+	}
+	return fmt.Sprintf("%v", a) < fmt.Sprintf("%v", b)
+}
+
+func loopNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
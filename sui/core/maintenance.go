@@ -0,0 +1,160 @@
+package core
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/store"
+	"github.com/yaoapp/kun/log"
+)
+
+// Maintenance the maintenance mode of the server, everyone except the allowlisted IPs and tokens is served the
+// maintenance page with 503 and Retry-After. toggled by the sui.maintenance.* processes or yao sui maintenance,
+// and persisted in the MaintenanceStore so the running servers pick it up without restarting
+type Maintenance struct {
+	Enabled    bool      `json:"enabled"`
+	Page       string    `json:"page,omitempty"`        // the URL path of the sui maintenance page, e.g. /maintenance
+	RetryAfter int       `json:"retry_after,omitempty"` // the seconds of the Retry-After header
+	Message    string    `json:"message,omitempty"`     // the message of the API requests and the requests without the page
+	Allow      []string  `json:"allow,omitempty"`       // the allowlisted IPs and CIDRs, e.g. 10.0.0.0/8
+	Tokens     []string  `json:"tokens,omitempty"`      // the bypass tokens, see MaintenanceTokenHeader
+	Since      time.Time `json:"since,omitempty"`
+}
+
+// MaintenanceStore the store of the maintenance mode, in-memory only if the store is not found
+var MaintenanceStore = ""
+
+// MaintenanceRefresh the interval of reading the maintenance mode from the store
+var MaintenanceRefresh = 5 * time.Second
+
+// MaintenanceTokenHeader the header of the bypass token, the ?__maintenance=<token> query sets the cookie of the same name
+const MaintenanceTokenHeader = "X-Maintenance-Token"
+
+// MaintenanceCookie the cookie and the query of the bypass token
+const MaintenanceCookie = "__maintenance"
+
+const maintenanceKey = "__yao.maintenance"
+
+var maintenance = &Maintenance{}
+var maintenanceLoaded time.Time
+var maintenanceMutex sync.RWMutex
+
+// GetMaintenance get the maintenance mode, read from the store every MaintenanceRefresh
+func GetMaintenance() *Maintenance {
+	maintenanceMutex.RLock()
+	current, loaded := maintenance, maintenanceLoaded
+	maintenanceMutex.RUnlock()
+	if time.Since(loaded) < MaintenanceRefresh {
+		return current
+	}
+
+	maintenanceMutex.Lock()
+	defer maintenanceMutex.Unlock()
+	maintenanceLoaded = time.Now()
+	s, has := store.Pools[MaintenanceStore]
+	if !has {
+		return maintenance
+	}
+
+	value, has := s.Get(maintenanceKey)
+	if !has {
+		maintenance = &Maintenance{}
+		return maintenance
+	}
+
+	var raw []byte
+	switch v := value.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return maintenance
+	}
+
+	m := &Maintenance{}
+	if err := jsoniter.Unmarshal(raw, m); err != nil {
+		log.Error("[SUI] the maintenance mode is not a valid json: %s", err.Error())
+		return maintenance
+	}
+	maintenance = m
+	return maintenance
+}
+
+// SetMaintenance set the maintenance mode and persist it in the store
+func SetMaintenance(m *Maintenance) error {
+	if m == nil {
+		m = &Maintenance{}
+	}
+	for _, allow := range m.Allow {
+		if net.ParseIP(allow) == nil {
+			if _, _, err := net.ParseCIDR(allow); err != nil {
+				return fmt.Errorf("maintenance: the allowlisted %s is not an IP or a CIDR", allow)
+			}
+		}
+	}
+	if !m.Enabled {
+		m.Since = time.Time{}
+	} else if m.Since.IsZero() {
+		m.Since = time.Now()
+	}
+
+	maintenanceMutex.Lock()
+	defer maintenanceMutex.Unlock()
+	if s, has := store.Pools[MaintenanceStore]; has {
+		raw, err := jsoniter.MarshalToString(m)
+		if err != nil {
+			return err
+		}
+		if err := s.Set(maintenanceKey, raw, 0); err != nil {
+			return err
+		}
+	} else if MaintenanceStore != "" {
+		log.Warn(`[SUI] The maintenance store "%s" is not found, the maintenance mode is not persisted`, MaintenanceStore)
+	}
+
+	maintenance = m
+	maintenanceLoaded = time.Now()
+	return nil
+}
+
+// Allowed check if the request bypasses the maintenance mode, by the client IP or the token
+func (m *Maintenance) Allowed(ip string, token string) bool {
+	if token != "" {
+		for _, t := range m.Tokens {
+			if t != "" && subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+				return true
+			}
+		}
+	}
+
+	addr := net.ParseIP(strings.TrimSpace(ip))
+	if addr == nil {
+		return false
+	}
+	for _, allow := range m.Allow {
+		if strings.Contains(allow, "/") {
+			if _, network, err := net.ParseCIDR(allow); err == nil && network.Contains(addr) {
+				return true
+			}
+			continue
+		}
+		if other := net.ParseIP(allow); other != nil && other.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Retry the Retry-After header of the maintenance mode
+func (m *Maintenance) Retry() string {
+	if m.RetryAfter <= 0 {
+		return "3600"
+	}
+	return fmt.Sprintf("%d", m.RetryAfter)
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceAllowed(t *testing.T) {
+	m := &Maintenance{Enabled: true, Allow: []string{"10.0.0.0/8", "192.168.1.10", "::1"}, Tokens: []string{"secret"}}
+	assert.True(t, m.Allowed("10.1.2.3", ""))
+	assert.True(t, m.Allowed("192.168.1.10", ""))
+	assert.True(t, m.Allowed("::1", ""))
+	assert.True(t, m.Allowed("8.8.8.8", "secret"))
+	assert.False(t, m.Allowed("192.168.1.11", ""))
+	assert.False(t, m.Allowed("8.8.8.8", "wrong"))
+	assert.False(t, m.Allowed("", ""))
+	assert.False(t, (&Maintenance{Tokens: []string{""}}).Allowed("", ""))
+}
+
+func TestMaintenanceRetry(t *testing.T) {
+	assert.Equal(t, "3600", (&Maintenance{}).Retry())
+	assert.Equal(t, "120", (&Maintenance{RetryAfter: 120}).Retry())
+}
+
+func TestSetMaintenance(t *testing.T) {
+	store, refresh := MaintenanceStore, MaintenanceRefresh
+	defer func() {
+		MaintenanceStore, MaintenanceRefresh = store, refresh
+		SetMaintenance(nil)
+	}()
+	MaintenanceStore, MaintenanceRefresh = "", time.Hour
+
+	err := SetMaintenance(&Maintenance{Enabled: true, Allow: []string{"not-an-ip"}})
+	assert.Error(t, err)
+	assert.False(t, GetMaintenance().Enabled)
+
+	err = SetMaintenance(&Maintenance{Enabled: true, Page: "/maintenance", Allow: []string{"127.0.0.1"}})
+	assert.Nil(t, err)
+	m := GetMaintenance()
+	assert.True(t, m.Enabled)
+	assert.Equal(t, "/maintenance", m.Page)
+	assert.False(t, m.Since.IsZero())
+
+	err = SetMaintenance(&Maintenance{})
+	assert.Nil(t, err)
+	assert.False(t, GetMaintenance().Enabled)
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yaoapp/gou/session"
+)
+
+// MaskRule the data masking rule
+type MaskRule struct {
+	Pattern   string `json:"pattern"`             // The field path pattern, e.g. "user.email", "users.*.phone", "**.password"
+	Format    string `json:"format,omitempty"`    // email, phone, last4, hide, all (default) or the replacement
+	Anonymous bool   `json:"anonymous,omitempty"` // Apply to the anonymous visitors only
+}
+
+// MaskRules the global masking rules, applied to all the pages
+var MaskRules = []MaskRule{}
+
+// Mask apply the masking rules to a copy of the data
+func (data Data) Mask(rules []MaskRule) Data {
+	if len(rules) == 0 {
+		return data
+	}
+	res := Data{}
+	for key, value := range data {
+		if masked, keep := maskValue([]string{key}, value, rules); keep {
+			res[key] = masked
+		}
+	}
+	return res
+}
+
+// MaskString mask the string with the format
+func MaskString(value string, format string) string {
+	length := utf8.RuneCountInString(value)
+	switch format {
+	case "", "all":
+		return strings.Repeat("*", length)
+
+	case "email":
+		parts := strings.SplitN(value, "@", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return strings.Repeat("*", length)
+		}
+		first, _ := utf8.DecodeRuneInString(parts[0])
+		return fmt.Sprintf("%c***@%s", first, parts[1])
+
+	case "phone", "last4":
+		if length <= 4 {
+			return strings.Repeat("*", length)
+		}
+		runes := []rune(value)
+		return strings.Repeat("*", length-4) + string(runes[length-4:])
+	}
+	return format
+}
+
+func maskValue(path []string, value interface{}, rules []MaskRule) (interface{}, bool) {
+
+	for _, rule := range rules {
+		if !maskMatch(strings.Split(rule.Pattern, "."), path) {
+			continue
+		}
+		if rule.Format == "hide" {
+			return nil, false
+		}
+		if value == nil {
+			return nil, true
+		}
+		return MaskString(fmt.Sprintf("%v", value), rule.Format), true
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return value, true
+		}
+		res := map[string]interface{}{}
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			if masked, keep := maskValue(append(path[:len(path):len(path)], key), iter.Value().Interface(), rules); keep {
+				res[key] = masked
+			}
+		}
+		return res, true
+
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return value, true
+		}
+		res := make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			if masked, keep := maskValue(append(path[:len(path):len(path)], fmt.Sprintf("%d", i)), rv.Index(i).Interface(), rules); keep {
+				res = append(res, masked)
+			}
+		}
+		return res, true
+
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return value, true
+		}
+		return maskValue(path, rv.Elem().Interface(), rules)
+	}
+
+	return value, true
+}
+
+// maskMatch match the path with the pattern, "*" matches one segment and "**" matches any segments
+func maskMatch(pattern []string, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if maskMatch(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(path) == 0 {
+		return false
+	}
+
+	if pattern[0] != "*" && pattern[0] != path[0] {
+		return false
+	}
+	return maskMatch(pattern[1:], path[1:])
+}
+
+// VisitorMaskRules get the global and the page rules should be applied to the visitor
+func VisitorMaskRules(rules []MaskRule, anonymous bool) []MaskRule {
+	all := append([]MaskRule{}, MaskRules...)
+	all = append(all, rules...)
+	res := []MaskRule{}
+	for _, rule := range all {
+		if rule.Anonymous && !anonymous {
+			continue
+		}
+		res = append(res, rule)
+	}
+	return res
+}
+
+// Anonymous check if the visitor of the request is anonymous (not signed in)
+func (r *Request) Anonymous() bool {
+	if r == nil || r.Sid == "" {
+		return true
+	}
+
+	id, err := session.Global().ID(r.Sid).Get("user_id")
+	return err != nil || id == nil
+}
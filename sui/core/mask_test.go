@@ -0,0 +1,62 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/kun/maps"
+)
+
+func TestMaskString(t *testing.T) {
+	assert.Equal(t, "j***@example.com", MaskString("john@example.com", "email"))
+	assert.Equal(t, "*******5678", MaskString("13912345678", "phone"))
+	assert.Equal(t, "******", MaskString("secret", ""))
+	assert.Equal(t, "[hidden]", MaskString("secret", "[hidden]"))
+}
+
+func TestDataMask(t *testing.T) {
+	data := Data{
+		"title": "Users",
+		"user":  map[string]interface{}{"name": "John", "email": "john@example.com", "password": "secret"},
+		"users": []interface{}{
+			map[string]interface{}{"name": "Jane", "phone": "13912345678"},
+		},
+	}
+
+	masked := data.Mask([]MaskRule{
+		{Pattern: "user.email", Format: "email"},
+		{Pattern: "users.*.phone", Format: "phone"},
+		{Pattern: "**.password", Format: "hide"},
+	})
+
+	user := masked["user"].(map[string]interface{})
+	assert.Equal(t, "Users", masked["title"])
+	assert.Equal(t, "John", user["name"])
+	assert.Equal(t, "j***@example.com", user["email"])
+	assert.NotContains(t, user, "password")
+	assert.Equal(t, "*******5678", masked["users"].([]interface{})[0].(map[string]interface{})["phone"])
+
+	// The origin data should not be changed
+	assert.Equal(t, "john@example.com", data["user"].(map[string]interface{})["email"])
+
+	// The typed maps and slices returned by the processes
+	data = Data{
+		"user":  maps.MapStrAny{"email": "john@example.com"},
+		"users": []maps.MapStrAny{{"phone": "13912345678", "password": "secret"}},
+	}
+	masked = data.Mask([]MaskRule{
+		{Pattern: "user.email", Format: "email"},
+		{Pattern: "users.*.phone", Format: "phone"},
+		{Pattern: "**.password", Format: "hide"},
+	})
+	assert.Equal(t, "j***@example.com", masked["user"].(map[string]interface{})["email"])
+	item := masked["users"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "*******5678", item["phone"])
+	assert.NotContains(t, item, "password")
+}
+
+func TestVisitorMaskRules(t *testing.T) {
+	rules := []MaskRule{{Pattern: "email"}, {Pattern: "phone", Anonymous: true}}
+	assert.Len(t, VisitorMaskRules(rules, true), 2)
+	assert.Len(t, VisitorMaskRules(rules, false), 1)
+}
@@ -0,0 +1,432 @@
+package core
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/process"
+)
+
+// MenuOption the hierarchical menus of the pages, the items are stored in the model and edited in the admin.
+// set in the menus section of the sui DSL
+//
+//	"menus": {"model": "menu", "roles": "$session.roles"}
+//
+// the columns of the model:
+//
+//	id, menu (the name, e.g. main), parent (the id of the parent item, null for the top items), label, route, icon,
+//	roles (json, the visitor should have one of the roles, everyone if empty), locales (json, the labels of the
+//	locales, e.g. {"zh-cn": "首页"}) and sort
+//
+// the templates get the resolved tree by {{ $menu("main") }}, the items the visitor can not see are removed
+type MenuOption struct {
+	Model string `json:"model"`           // the model of the items
+	Roles string `json:"roles,omitempty"` // the expression of the roles of the visitor against the $session, $session.roles by default
+}
+
+// MenuItem the item of the menu
+type MenuItem struct {
+	ID       interface{}       `json:"id,omitempty"`
+	Parent   interface{}       `json:"parent,omitempty"`
+	Label    string            `json:"label"`
+	Route    string            `json:"route,omitempty"`
+	Icon     string            `json:"icon,omitempty"`
+	Roles    []string          `json:"roles,omitempty"`
+	Locales  map[string]string `json:"locales,omitempty"`
+	Sort     int               `json:"sort,omitempty"`
+	Children []*MenuItem       `json:"children,omitempty"`
+}
+
+// MaxMenuItems the max items of a menu
+var MaxMenuItems = 500
+
+// menusKey the data key of the menus of the rendering
+const menusKey = "$__menus"
+
+// menuCallRe the $menu("main") helper, rewritten to __menu($__menus, "main")
+var menuCallRe = regexp.MustCompile(`\$menu\s*\(`)
+
+// menus the menus of the rendering, the trees are memoized
+type menus struct {
+	option *MenuOption
+	roles  []string
+	locale string
+	path   string
+	memo   map[string]interface{}
+	mutex  sync.Mutex
+}
+
+var menuOptions = map[string]*MenuOption{}
+var menuOptionsMutex sync.RWMutex
+
+// RegisterMenus set the menus of the pages of the route prefix, nil to remove the menus
+func RegisterMenus(prefix string, option *MenuOption) {
+	menuOptionsMutex.Lock()
+	defer menuOptionsMutex.Unlock()
+	prefix = "/" + strings.Trim(prefix, "/")
+	if option == nil {
+		delete(menuOptions, prefix)
+		return
+	}
+	menuOptions[prefix] = option
+}
+
+// GetMenus get the menus of the route, the longest prefix wins, nil if the route has no menus
+func GetMenus(route string) *MenuOption {
+	menuOptionsMutex.RLock()
+	defer menuOptionsMutex.RUnlock()
+	var res *MenuOption = nil
+	matched := -1
+	for prefix, option := range menuOptions {
+		if len(prefix) <= matched {
+			continue
+		}
+		if prefix == "/" || route == prefix || strings.HasPrefix(route, prefix+"/") {
+			res, matched = option, len(prefix)
+		}
+	}
+	return res
+}
+
+// newMenus create the menus of the rendering, nil if the route has no menus
+func newMenus(option *ParserOption) *menus {
+	menu := GetMenus(option.Route)
+	if menu == nil {
+		return nil
+	}
+
+	m := &menus{option: menu, path: option.Route, memo: map[string]interface{}{}}
+	if option.Request != nil {
+		m.roles = menu.VisitorRoles(option.Request.sessionData())
+		if option.Request.Locale != nil {
+			m.locale = fmt.Sprintf("%v", option.Request.Locale)
+		}
+		if option.Request.URL.Path != "" {
+			m.path = option.Request.URL.Path
+		}
+	}
+	return m
+}
+
+// VisitorRoles get the roles of the visitor by the roles expression of the menus
+func (option *MenuOption) VisitorRoles(sess map[string]interface{}) []string {
+	stmt := option.Roles
+	if stmt == "" {
+		stmt = "$session.roles"
+	}
+
+	res, _, err := Data{"$session": sess}.Exec(stmt)
+	if err != nil {
+		return []string{}
+	}
+	return menuStrings(res)
+}
+
+// Items get the items of the menu from the model, in the sort order
+func (option *MenuOption) Items(name string) ([]*MenuItem, error) {
+	if option.Model == "" {
+		return nil, fmt.Errorf("the model of the menus is required")
+	}
+
+	query := map[string]interface{}{
+		"wheres": []map[string]interface{}{{"column": "menu", "value": name}},
+		"orders": []map[string]interface{}{{"column": "sort", "option": "asc"}},
+		"limit":  MaxMenuItems,
+	}
+	p, err := process.Of(fmt.Sprintf("models.%s.Get", option.Model), query)
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.Exec()
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := jsoniter.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	rows := []map[string]interface{}{}
+	if err := jsoniter.Unmarshal(raw, &rows); err != nil {
+		return nil, fmt.Errorf("the items of the menu %s are invalid: %s", name, err.Error())
+	}
+
+	items := make([]*MenuItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, menuItem(row))
+	}
+	return items, nil
+}
+
+// Save replace the items of the menu with the tree, the parents and the sort orders are set by the tree
+func (option *MenuOption) Save(name string, tree []*MenuItem) error {
+	if option.Model == "" {
+		return fmt.Errorf("the model of the menus is required")
+	}
+
+	p, err := process.Of(fmt.Sprintf("models.%s.DestroyWhere", option.Model), map[string]interface{}{
+		"wheres": []map[string]interface{}{{"column": "menu", "value": name}},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := p.Exec(); err != nil {
+		return err
+	}
+
+	count := 0
+	var save func(items []*MenuItem, parent interface{}) error
+	save = func(items []*MenuItem, parent interface{}) error {
+		for i, item := range items {
+			if count++; count > MaxMenuItems {
+				return fmt.Errorf("the menu %s has more than %d items", name, MaxMenuItems)
+			}
+
+			roles, _ := jsoniter.MarshalToString(item.Roles)
+			locales, _ := jsoniter.MarshalToString(item.Locales)
+			row := map[string]interface{}{
+				"menu": name, "parent": parent, "label": item.Label, "route": item.Route, "icon": item.Icon,
+				"roles": roles, "locales": locales, "sort": i,
+			}
+			p, err := process.Of(fmt.Sprintf("models.%s.Create", option.Model), row)
+			if err != nil {
+				return err
+			}
+			id, err := p.Exec()
+			if err != nil {
+				return err
+			}
+			if err := save(item.Children, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return save(tree, nil)
+}
+
+// MenuTree build the tree of the items, the items the roles can not see (and their children) and the items of the
+// missing parents are removed. the labels are localized if the locale is set, the all keeps every item (the admin)
+func MenuTree(items []*MenuItem, roles []string, locale string, all bool) []*MenuItem {
+	children := map[string][]*MenuItem{}
+	for _, item := range items {
+		parent := ""
+		if item.Parent != nil && fmt.Sprintf("%v", item.Parent) != "0" {
+			parent = fmt.Sprintf("%v", item.Parent)
+		}
+		children[parent] = append(children[parent], item)
+	}
+
+	visited := map[string]bool{}
+	var build func(parent string) []*MenuItem
+	build = func(parent string) []*MenuItem {
+		res := []*MenuItem{}
+		for _, item := range children[parent] {
+			id := fmt.Sprintf("%v", item.ID)
+			if (item.ID != nil && visited[id]) || (!all && !menuVisible(item.Roles, roles)) {
+				continue
+			}
+			visited[id] = true
+
+			node := *item
+			if !all {
+				node.Label = menuLabel(item, locale)
+				node.Roles, node.Locales = nil, nil
+			}
+			node.Children = build(id)
+			res = append(res, &node)
+		}
+		sort.SliceStable(res, func(i, j int) bool { return res[i].Sort < res[j].Sort })
+		return res
+	}
+	return build("")
+}
+
+// menuVisible the visitor should have one of the roles of the item, everyone if the item has no roles
+func menuVisible(roles []string, visitor []string) bool {
+	if len(roles) == 0 {
+		return true
+	}
+	for _, role := range roles {
+		for _, has := range visitor {
+			if strings.EqualFold(role, has) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// menuLabel the label of the locale, the language (zh of zh-cn) if the locale has no label
+func menuLabel(item *MenuItem, locale string) string {
+	locale = strings.ToLower(locale)
+	if locale == "" || len(item.Locales) == 0 {
+		return item.Label
+	}
+	for _, name := range []string{locale, strings.SplitN(locale, "-", 2)[0]} {
+		for key, label := range item.Locales {
+			if strings.ToLower(key) == name && label != "" {
+				return label
+			}
+		}
+	}
+	return item.Label
+}
+
+// menuData the data of the tree for the templates, the active is true if the path is the route of the item or of
+// the children, e.g. {{ item.label }} {{ item.active }}
+func menuData(items []*MenuItem, path string) ([]interface{}, bool) {
+	res := []interface{}{}
+	active := false
+	for _, item := range items {
+		children, open := menuData(item.Children, path)
+		current := item.Route != "" && (path == item.Route || (item.Route != "/" && strings.HasPrefix(path, strings.TrimSuffix(item.Route, "/")+"/")))
+		res = append(res, map[string]interface{}{
+			"id": item.ID, "label": item.Label, "route": item.Route, "icon": item.Icon,
+			"active": current || open, "children": children,
+		})
+		active = active || current || open
+	}
+	return res, active
+}
+
+// menuItem the item of the model row, the roles and the locales are the json columns
+func menuItem(row map[string]interface{}) *MenuItem {
+	item := &MenuItem{ID: row["id"], Parent: row["parent"]}
+	item.Label, _ = row["label"].(string)
+	item.Route, _ = row["route"].(string)
+	item.Icon, _ = row["icon"].(string)
+	if v, ok := row["sort"].(float64); ok {
+		item.Sort = int(v)
+	}
+
+	item.Roles = menuStrings(row["roles"])
+	switch v := row["locales"].(type) {
+	case string:
+		jsoniter.UnmarshalFromString(v, &item.Locales)
+	case map[string]interface{}:
+		item.Locales = map[string]string{}
+		for key, label := range v {
+			item.Locales[key] = fmt.Sprintf("%v", label)
+		}
+	}
+	return item
+}
+
+// menuStrings the roles of the value, the list, the json list or the comma separated string
+func menuStrings(value interface{}) []string {
+	res := []string{}
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		for _, role := range v {
+			res = append(res, fmt.Sprintf("%v", role))
+		}
+	case string:
+		v = strings.TrimSpace(v)
+		if strings.HasPrefix(v, "[") {
+			jsoniter.UnmarshalFromString(v, &res)
+			return res
+		}
+		for _, role := range strings.Split(v, ",") {
+			if role = strings.TrimSpace(role); role != "" {
+				res = append(res, role)
+			}
+		}
+	}
+	return res
+}
+
+// menuCalls rewrite the $menu helper, e.g. $menu("main") => __menu($__menus, "main")
+func menuCalls(stmt string) string {
+	if !strings.Contains(stmt, "$menu") {
+		return stmt
+	}
+	return menuCallRe.ReplaceAllLiteralString(stmt, "__menu("+menusKey+", ")
+}
+
+// _menu the function called by the $menu helper, __menu($__menus, name)
+func _menu(args ...any) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("$menu(name) expects the name of the menu")
+	}
+
+	m, ok := args[0].(*menus)
+	if !ok || m == nil {
+		return nil, fmt.Errorf("the menus are not available, please set the menus of the sui")
+	}
+
+	name, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("$menu(name) the name should be a string, got %T", args[1])
+	}
+	return m.tree(name)
+}
+
+// tree get the resolved tree of the menu, memoized in the rendering
+func (m *menus) tree(name string) (interface{}, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if res, has := m.memo[name]; has {
+		return res, nil
+	}
+
+	items, err := m.option.Items(name)
+	if err != nil {
+		return nil, err
+	}
+	res, _ := menuData(MenuTree(items, m.roles, m.locale, false), m.path)
+	m.memo[name] = res
+	return res, nil
+}
+
+// widgetMenu render the resolved tree of the menu as the nested <ul>, the active items have the active class
+func (parser *TemplateParser) widgetMenu(id string, attrs map[string]string) (string, error) {
+	m, ok := parser.data[menusKey].(*menus)
+	if !ok || m == nil {
+		return "", fmt.Errorf("the menus are not available, please set the menus of the sui")
+	}
+	tree, err := m.tree(id)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	var render func(items []interface{})
+	render = func(items []interface{}) {
+		b.WriteString(`<ul>`)
+		for _, item := range items {
+			data := widgetMap(item)
+			if active, _ := data["active"].(bool); active {
+				b.WriteString(`<li class="active">`)
+			} else {
+				b.WriteString(`<li>`)
+			}
+			label := html.EscapeString(widgetValue(data["label"]))
+			if icon := widgetValue(data["icon"]); icon != "" {
+				label = fmt.Sprintf(`<i class="%s"></i>%s`, html.EscapeString(icon), label)
+			}
+			if route := widgetValue(data["route"]); route != "" {
+				fmt.Fprintf(&b, `<a href="%s">%s</a>`, html.EscapeString(route), label)
+			} else {
+				fmt.Fprintf(&b, `<span>%s</span>`, label)
+			}
+			if children := widgetList(data["children"]); len(children) > 0 {
+				render(children)
+			}
+			b.WriteString(`</li>`)
+		}
+		b.WriteString(`</ul>`)
+	}
+
+	fmt.Fprintf(&b, `<nav %s>`, widgetAttrs("menu", id, attrs))
+	render(tree.([]interface{}))
+	b.WriteString(`</nav>`)
+	return b.String(), nil
+}
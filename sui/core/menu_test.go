@@ -0,0 +1,74 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMenuTree(t *testing.T) {
+	items := []*MenuItem{
+		menuItem(map[string]interface{}{"id": float64(1), "label": "Home", "route": "/", "sort": float64(0)}),
+		menuItem(map[string]interface{}{"id": float64(2), "label": "Docs", "route": "/docs", "sort": float64(1), "locales": `{"zh": "文档"}`}),
+		menuItem(map[string]interface{}{"id": float64(3), "parent": float64(2), "label": "Guide", "route": "/docs/guide", "sort": float64(0)}),
+		menuItem(map[string]interface{}{"id": float64(4), "label": "Admin", "route": "/admin", "sort": float64(2), "roles": `["admin"]`}),
+		menuItem(map[string]interface{}{"id": float64(5), "parent": float64(4), "label": "Users", "route": "/admin/users"}),
+		menuItem(map[string]interface{}{"id": float64(6), "parent": float64(9), "label": "Orphan"}),
+	}
+
+	tree := MenuTree(items, []string{"editor"}, "zh-CN", false)
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "Home", tree[0].Label)
+	assert.Equal(t, "文档", tree[1].Label)
+	assert.Nil(t, tree[1].Locales)
+	assert.Equal(t, "Guide", tree[1].Children[0].Label)
+
+	tree = MenuTree(items, []string{"Admin"}, "", false)
+	assert.Len(t, tree, 3)
+	assert.Equal(t, "Users", tree[2].Children[0].Label)
+
+	// The editor keeps every item and the roles
+	tree = MenuTree(items, nil, "zh-cn", true)
+	assert.Len(t, tree, 3)
+	assert.Equal(t, "Docs", tree[1].Label)
+	assert.Equal(t, []string{"admin"}, tree[2].Roles)
+
+	data, active := menuData(MenuTree(items, nil, "", false), "/docs/guide")
+	assert.True(t, active)
+	assert.Equal(t, false, data[0].(map[string]interface{})["active"])
+	assert.Equal(t, true, data[1].(map[string]interface{})["active"])
+
+	assert.Equal(t, []string{"admin", "editor"}, menuStrings("admin, editor"))
+	assert.Equal(t, []string{"admin"}, menuStrings([]interface{}{"admin"}))
+	assert.Equal(t, []string{"admin"}, (&MenuOption{}).VisitorRoles(map[string]interface{}{"roles": []interface{}{"admin"}}))
+}
+
+func TestParserMenu(t *testing.T) {
+	items := []*MenuItem{
+		{ID: 1, Label: "Home", Route: "/"},
+		{ID: 2, Label: "Docs", Route: "/docs"},
+		{ID: 3, Parent: 2, Label: "Guide <new>", Route: "/docs/guide", Icon: "icon-book"},
+	}
+	tree, _ := menuData(MenuTree(items, nil, "", false), "/docs/guide")
+	m := &menus{option: &MenuOption{Model: "menu"}, memo: map[string]interface{}{"main": tree}}
+
+	assert.Equal(t, `__menu($__menus, "main")`, menuCalls(`$menu("main")`))
+	source := `<html><body><ul><li s:for="$menu('main')" s:for-item="item" class="{{ item.active ? 'active' : '' }}">{{ item.label }}</li></ul></body></html>`
+	parser := NewTemplateParser(Data{menusKey: m}, &ParserOption{Route: "/docs/guide", Request: &Request{}})
+	html, err := parser.Render(source)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `<li class="">Home</li>`)
+	assert.Contains(t, html, `<li class="active">Docs</li>`)
+	assert.NotContains(t, html, menusKey)
+
+	source, err = parser.widgetMenu("main", map[string]string{"id": "nav"})
+	assert.Nil(t, err)
+	assert.Equal(t, `<nav class="sui-widget sui-widget-menu" data-widget="menu.main" id="nav"><ul>`+
+		`<li><a href="/">Home</a></li>`+
+		`<li class="active"><a href="/docs">Docs</a><ul><li class="active"><a href="/docs/guide"><i class="icon-book"></i>Guide &lt;new&gt;</a></li></ul></li>`+
+		`</ul></nav>`, source)
+
+	// No menus
+	_, err = NewTemplateParser(Data{}, &ParserOption{Route: "/"}).widgetMenu("main", nil)
+	assert.Contains(t, err.Error(), "the menus are not available")
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/yaoapp/gou/runtime/transform"
+	"golang.org/x/net/html"
+)
+
+var trimBeforeTokens = regexp.MustCompile(`\s*\{\{-\s`)
+var trimAfterTokens = regexp.MustCompile(`\s-\}\}\s*`)
+var minifySpaces = regexp.MustCompile(`\s+`)
+
+// minifyKeep the elements keep the whitespace
+var minifyKeep = map[string]bool{"pre": true, "textarea": true, "script": true, "style": true, "code": true}
+
+// minifyBlocks the block elements, the whitespace around them is removed
+var minifyBlocks = map[string]bool{
+	"html": true, "head": true, "body": true, "title": true, "meta": true, "link": true, "script": true, "style": true,
+	"div": true, "p": true, "main": true, "header": true, "footer": true, "nav": true, "section": true, "article": true, "aside": true,
+	"ul": true, "ol": true, "li": true, "dl": true, "dt": true, "dd": true, "table": true, "thead": true, "tbody": true, "tfoot": true,
+	"tr": true, "td": true, "th": true, "form": true, "fieldset": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true,
+	"h6": true, "hr": true, "br": true, "pre": true, "figure": true, "figcaption": true, "blockquote": true, "option": true, "template": true,
+}
+
+// trimTokens apply the whitespace control of the tokens, {{- trims the whitespace before the token and -}} trims the
+// whitespace after the token, e.g. <li>\n  {{- name -}}\n</li> => <li>{{ name }}</li>
+func trimTokens(source string) string {
+	if !strings.Contains(source, "{{-") && !strings.Contains(source, "-}}") {
+		return source
+	}
+	source = trimBeforeTokens.ReplaceAllString(source, "{{ ")
+	return trimAfterTokens.ReplaceAllString(source, " }}")
+}
+
+// minify collapse the whitespace between the tags, strip the comments and minify the inline scripts and styles
+// of the rendered document. the whitespace of the pre, textarea and code elements is kept
+func (parser *TemplateParser) minify(sel *goquery.Selection) {
+	defer parser.option.Timing.Start("minify", "Minify")()
+	for _, node := range sel.Nodes {
+		minifyNode(node)
+	}
+}
+
+func minifyNode(node *html.Node) {
+	var next *html.Node
+	for child := node.FirstChild; child != nil; child = next {
+		next = child.NextSibling
+		switch child.Type {
+		case html.CommentNode:
+			// Keep the conditional comments and the <!--! ... --> comments
+			if !strings.HasPrefix(child.Data, "[if") && !strings.HasPrefix(child.Data, "!") {
+				node.RemoveChild(child)
+			}
+
+		case html.TextNode:
+			if minifyKeep[node.Data] {
+				continue
+			}
+			if strings.TrimSpace(child.Data) == "" && (minifyEdge(node, child.PrevSibling) || minifyEdge(node, child.NextSibling)) {
+				node.RemoveChild(child)
+				continue
+			}
+			child.Data = minifySpaces.ReplaceAllString(child.Data, " ")
+
+		case html.ElementNode:
+			switch child.Data {
+			case "script":
+				minifyScript(child)
+			case "style":
+				minifyStyle(child)
+			}
+			if !minifyKeep[child.Data] {
+				minifyNode(child)
+			}
+		}
+	}
+}
+
+// minifyEdge check if the sibling is the block element, or the start (end) of the block parent
+func minifyEdge(parent *html.Node, sibling *html.Node) bool {
+	if sibling == nil {
+		return minifyBlocks[parent.Data]
+	}
+	return sibling.Type == html.ElementNode && minifyBlocks[sibling.Data]
+}
+
+func minifyScript(node *html.Node) {
+	if node.FirstChild == nil || node.FirstChild != node.LastChild || hasAttr(node, "src") {
+		return
+	}
+	if typ, has := nodeAttr(node, "type"); has && typ != "" && typ != "text/javascript" && typ != "module" {
+		return // the json data and the templates
+	}
+	code, err := transform.MinifyJS(node.FirstChild.Data)
+	if err != nil {
+		return
+	}
+	node.FirstChild.Data = strings.TrimSpace(code)
+}
+
+func minifyStyle(node *html.Node) {
+	if node.FirstChild == nil || node.FirstChild != node.LastChild {
+		return
+	}
+	code, err := transform.MinifyCSS(node.FirstChild.Data)
+	if err != nil {
+		return
+	}
+	node.FirstChild.Data = strings.TrimSpace(code)
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserMinify(t *testing.T) {
+	source := `<html><head>
+		<title>Home</title>
+	</head>
+	<body>
+		<!-- the list -->
+		<ul>
+			<li s:for="items">
+				{{- item -}}
+			</li>
+		</ul>
+		<p>Hello,   <b>{{ name }}</b> <i>!</i>  </p>
+		<pre>  keep
+  this  </pre>
+	</body></html>`
+
+	data := Data{"items": []interface{}{"a", "b"}, "name": "Yao"}
+	parser := NewTemplateParser(data, &ParserOption{Request: &Request{}, Minify: true})
+	html, err := parser.Render(source)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `<head><title>Home</title>`)
+	assert.Contains(t, html, `<ul><li>a</li><li>b</li></ul>`)
+	assert.Contains(t, html, `<p>Hello, <b>Yao</b> <i>!</i></p><pre>  keep`+"\n  this  </pre><script")
+
+	// The whitespace control without the minification
+	parser = NewTemplateParser(data, &ParserOption{Request: &Request{}})
+	html, err = parser.Render(source)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `<li>a</li><li>b</li>`)
+	assert.Contains(t, html, "<p>Hello,   <b>Yao</b>")
+}
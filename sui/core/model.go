@@ -0,0 +1,165 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// modelNode the s:model two-way binding of the form controls, the current value of the data path is rendered into
+// the control, and the s:model attribute is kept for the client runtime to sync the changes back to __sui_data
+//
+//	<input type="email" s:model="form.email">           => value="ada@example.com"
+//	<input type="checkbox" s:model="form.agree">        => checked if true
+//	<input type="checkbox" value="go" s:model="form.tags"> => checked if the value is in the list
+//	<input type="radio" value="pro" s:model="form.plan">   => checked if the value is equal
+//	<select s:model="form.tags" multiple>...</select>      => the options of the values are selected
+//	<textarea s:model="form.bio"></textarea>
+//
+// the name of the control is the data path if not set, the value is compared with the evaluated attributes,
+// the options of the select are selected after the children are rendered, see modelOptions
+func (parser *TemplateParser) modelNode(sel *goquery.Selection) {
+	defer parser.option.Timing.Start("model", "s:model")()
+	node := sel.Nodes[0]
+	stmt := strings.TrimSpace(sel.AttrOr("s:model", ""))
+	value, err := parser.modelValue(node, stmt)
+	if err != nil {
+		return
+	}
+	parser.hydrateStmt(stmt)
+
+	if _, has := sel.Attr("name"); !has {
+		sel.SetAttr("name", stmt)
+	}
+
+	switch node.Data {
+	case "input":
+		switch strings.ToLower(sel.AttrOr("type", "text")) {
+		case "checkbox", "radio":
+			sel.SetAttr("s:bind:checked", stmt)
+			if modelChecked(value, sel.AttrOr("value", "on"), sel.AttrOr("type", "") == "checkbox") {
+				sel.SetAttr("checked", "")
+				return
+			}
+			sel.RemoveAttr("checked")
+
+		default:
+			sel.SetAttr("s:bind:value", stmt)
+			sel.SetAttr("value", modelString(value))
+		}
+
+	case "textarea":
+		sel.SetAttr("s:bind:value", stmt)
+		for child := node.FirstChild; child != nil; child = node.FirstChild {
+			node.RemoveChild(child)
+		}
+		node.AppendChild(&html.Node{Type: html.TextNode, Data: modelString(value)})
+
+	case "select":
+		sel.SetAttr("s:bind:value", stmt)
+
+	default:
+		parser.renderError(node, "s:model", stmt, fmt.Errorf("s:model should be on the input, select or textarea, got <%s>", node.Data))
+	}
+}
+
+// modelOptions select the options of the s:model select, the replacements of the children (e.g. s:for) are applied first
+func (parser *TemplateParser) modelOptions(node *html.Node, offset int) {
+	parser.applyReplaceFrom(offset)
+	stmt, _ := nodeAttr(node, "s:model")
+	value, err := parser.modelValue(node, strings.TrimSpace(stmt))
+	if err != nil {
+		return
+	}
+	modelSelect(node, value)
+}
+
+func (parser *TemplateParser) modelValue(node *html.Node, stmt string) (interface{}, error) {
+	if stmt == "" {
+		err := fmt.Errorf("s:model should be the data path, e.g. form.email")
+		parser.renderError(node, "s:model", stmt, err)
+		return nil, err
+	}
+	value, _, err := parser.data.ExecGuard(stmt, parser.guard)
+	if err != nil {
+		parser.renderError(node, "s:model", stmt, err)
+		return nil, err
+	}
+	return value, nil
+}
+
+func modelSelect(node *html.Node, value interface{}) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		if child.Data != "option" {
+			modelSelect(child, value) // <optgroup>
+			continue
+		}
+
+		option, has := nodeAttr(child, "value")
+		if !has {
+			text := acquireBuffer()
+			nodeText(child, text)
+			option = strings.TrimSpace(text.String())
+			releaseBuffer(text)
+		}
+
+		attrs := child.Attr[:0]
+		for _, attr := range child.Attr {
+			if attr.Key != "selected" {
+				attrs = append(attrs, attr)
+			}
+		}
+		child.Attr = attrs
+		if modelChecked(value, option, true) {
+			child.Attr = append(child.Attr, html.Attribute{Key: "selected"})
+		}
+	}
+}
+
+// modelChecked check if the control of the value is checked, the list values (checkbox, multi-select) contain the value
+func modelChecked(model interface{}, value string, multiple bool) bool {
+	switch v := model.(type) {
+	case nil:
+		return false
+	case bool:
+		if multiple {
+			return v
+		}
+	case []interface{}:
+		for _, item := range v {
+			if modelString(item) == value {
+				return true
+			}
+		}
+		return false
+	case []string:
+		for _, item := range v {
+			if item == value {
+				return true
+			}
+		}
+		return false
+	}
+	return modelString(model) == value
+}
+
+func modelString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool, int, int64, float64:
+		return fmt.Sprintf("%v", v)
+	}
+	raw, err := jsonStable.MarshalToString(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return raw
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelChecked(t *testing.T) {
+	assert.True(t, modelChecked(true, "on", true))
+	assert.False(t, modelChecked(false, "on", true))
+	assert.True(t, modelChecked([]interface{}{"go", 1}, "1", true))
+	assert.False(t, modelChecked([]interface{}{"go"}, "rust", true))
+	assert.True(t, modelChecked("pro", "pro", false))
+	assert.False(t, modelChecked(nil, "", false))
+}
+
+func TestParserModel(t *testing.T) {
+	data := Data{
+		"form": map[string]interface{}{
+			"email": "ada@example.com",
+			"agree": true,
+			"plan":  "pro",
+			"tags":  []interface{}{"go", "ts"},
+			"bio":   "<b>Hi</b>",
+		},
+		"plans": []interface{}{"free", "pro"},
+	}
+	parser := NewTemplateParser(data, &ParserOption{Request: &Request{}})
+	html, err := parser.Render(`<html><body>` +
+		`<input type="email" s:model="form.email">` +
+		`<input type="checkbox" s:model="form.agree" checked>` +
+		`<input type="checkbox" name="tags" value="go" s:model="form.tags"><input type="checkbox" name="tags" value="rust" s:model="form.tags" checked>` +
+		`<input s:for="plans" s:for-item="plan" type="radio" value="{{ plan }}" s:model="form.plan">` +
+		`<select s:model="form.tags" multiple><option selected>rust</option><option value="ts">TypeScript</option></select>` +
+		`<select s:model="form.plan"><option s:for="plans" s:for-item="plan" value="{{ plan }}">{{ plan }}</option></select>` +
+		`<textarea s:model="form.bio">old</textarea>` +
+		`</body></html>`)
+	assert.Nil(t, err)
+	assert.Empty(t, parser.Errors())
+	assert.Contains(t, html, `<input type="email" s:model="form.email" name="form.email" value="ada@example.com"/>`)
+	assert.Contains(t, html, `<input type="checkbox" s:model="form.agree" checked="" name="form.agree"/>`)
+	assert.Contains(t, html, `<input type="checkbox" name="tags" value="go" s:model="form.tags" checked=""/>`)
+	assert.Contains(t, html, `<input type="checkbox" name="tags" value="rust" s:model="form.tags"/>`)
+	assert.Contains(t, html, `<input type="radio" value="free" s:model="form.plan" name="form.plan"/>`)
+	assert.Contains(t, html, `<input type="radio" value="pro" s:model="form.plan" name="form.plan" checked=""/>`)
+	assert.Contains(t, html, `<option>rust</option><option value="ts" selected="">TypeScript</option>`)
+	assert.Contains(t, html, `<option value="free">free</option><option value="pro" selected="">pro</option>`)
+	assert.Contains(t, html, `<textarea s:model="form.bio" name="form.bio">&lt;b&gt;Hi&lt;/b&gt;</textarea>`)
+
+	// The s:model should be on the form controls
+	parser = NewTemplateParser(data, &ParserOption{Request: &Request{}})
+	_, err = parser.Render(`<html><body><div s:model="form.email"></div></body></html>`)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, parser.Errors())
+}
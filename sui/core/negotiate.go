@@ -0,0 +1,64 @@
+package core
+
+import (
+	"strings"
+)
+
+// The response formats of the SUI page
+const (
+	FormatHTML    = "html"    // The full html document (default)
+	FormatJSON    = "json"    // The page data as json
+	FormatPartial = "partial" // The html fragment of the body, for htmx-style requests
+)
+
+var formatContentTypes = map[string]string{
+	FormatHTML:    "text/html; charset=utf-8",
+	FormatJSON:    "application/json; charset=utf-8",
+	FormatPartial: "text/html; charset=utf-8",
+}
+
+// Format get the response format negotiated by the request
+// ?__sui_format=json|partial|html > HX-Request header > Accept header
+func (r *Request) Format() string {
+
+	if r.Query != nil {
+		if format := strings.ToLower(r.Query.Get("__sui_format")); format != "" {
+			if _, has := formatContentTypes[format]; has {
+				return format
+			}
+		}
+	}
+
+	if r.Headers == nil {
+		return FormatHTML
+	}
+
+	if strings.ToLower(r.Headers.Get("Hx-Request")) == "true" {
+		return FormatPartial
+	}
+
+	accept := strings.ToLower(r.Headers.Get("Accept"))
+	if accept == "" {
+		return FormatHTML
+	}
+
+	// Respect the order of the accept header, the first matched one wins
+	for _, part := range strings.Split(accept, ",") {
+		mime := strings.TrimSpace(strings.Split(part, ";")[0])
+		switch mime {
+		case "text/html", "application/xhtml+xml", "*/*":
+			return FormatHTML
+		case "application/json":
+			return FormatJSON
+		}
+	}
+	return FormatHTML
+}
+
+// FormatContentType get the content type of the response format
+func FormatContentType(format string) string {
+	if contentType, has := formatContentTypes[format]; has {
+		return contentType
+	}
+	return formatContentTypes[FormatHTML]
+}
@@ -0,0 +1,34 @@
+package core
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    url.Values
+		headers  url.Values
+		expected string
+	}{
+		{name: "Default", expected: FormatHTML},
+		{name: "Browser", headers: url.Values{"Accept": {"text/html,application/xhtml+xml,*/*;q=0.8"}}, expected: FormatHTML},
+		{name: "JSON", headers: url.Values{"Accept": {"application/json"}}, expected: FormatJSON},
+		{name: "HTMX", headers: url.Values{"Hx-Request": {"true"}, "Accept": {"*/*"}}, expected: FormatPartial},
+		{name: "Query", query: url.Values{"__sui_format": {"json"}}, headers: url.Values{"Accept": {"text/html"}}, expected: FormatJSON},
+		{name: "Invalid Query", query: url.Values{"__sui_format": {"xml"}}, expected: FormatHTML},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Request{Query: tt.query, Headers: tt.headers}
+			assert.Equal(t, tt.expected, r.Format())
+		})
+	}
+
+	assert.Equal(t, "application/json; charset=utf-8", FormatContentType(FormatJSON))
+	assert.Equal(t, "text/html; charset=utf-8", FormatContentType("unknown"))
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+func (parser *TemplateParser) isOnce(sel *goquery.Selection) bool {
+	_, has := sel.Attr("s:once")
+	return has
+}
+
+// onceNode the s:once directive, the children are rendered a single time and the output is reused
+// across the loop iterations and the component instances of the same rendering.
+// s:once="key" shares the output by the key, the source of the node is used if the key is empty
+func (parser *TemplateParser) onceNode(sel *goquery.Selection) {
+	defer parser.option.Timing.Start("once", "s:once")()
+	key := sel.AttrOr("s:once", "")
+	if key == "" {
+		source, err := goquery.OuterHtml(sel)
+		if err != nil {
+			parser.renderError(sel.Nodes[0], "s:once", key, fmt.Errorf("s:once error: %s", err.Error()))
+			return
+		}
+		h := fnv.New64a()
+		h.Write([]byte(source))
+		key = fmt.Sprintf("%x", h.Sum64())
+	}
+
+	node := sel.Nodes[0]
+	if parser.onces == nil {
+		parser.onces = map[string][]*html.Node{}
+	}
+
+	// Reuse the rendered children
+	if nodes, has := parser.onces[key]; has {
+		for child := node.FirstChild; child != nil; child = node.FirstChild {
+			node.RemoveChild(child)
+		}
+		for _, child := range nodes {
+			node.AppendChild(cloneNode(child))
+		}
+		return
+	}
+
+	// Render the children, the replacements of the subtree are applied before the output is saved
+	offset := len(parser.replace)
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		parser.parseNode(child)
+	}
+	parser.applyReplaceFrom(offset)
+
+	nodes := []*html.Node{}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		nodes = append(nodes, cloneNode(child))
+	}
+	parser.onces[key] = nodes
+}
+
+// cloneNode deep clone the node
+func cloneNode(node *html.Node) *html.Node {
+	clone := &html.Node{
+		Type:      node.Type,
+		DataAtom:  node.DataAtom,
+		Data:      node.Data,
+		Namespace: node.Namespace,
+		Attr:      make([]html.Attribute, len(node.Attr)),
+	}
+	copy(clone.Attr, node.Attr)
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		clone.AppendChild(cloneNode(child))
+	}
+	return clone
+}
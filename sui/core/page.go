@@ -71,6 +71,12 @@ func (page *Page) ExportConfig() string {
 		"cache":      page.Config.Cache,
 		"dataCache":  page.Config.DataCache,
 		"api":        page.Config.API,
+		"embed":      page.Config.Embed,
+		"mask":       page.Config.Mask,
+		"precompile": page.Config.Precompile,
+		"minify":     page.Config.Minify,
+		"hydration":  page.Config.Hydration,
+		"bindings":   page.Config.Bindings,
 		"root":       page.Root,
 	})
 
@@ -125,6 +131,41 @@ func (page *Page) Exec(request *Request) (Data, error) {
 	return data, nil
 }
 
+// PreviewData get the data of the editor and the preview, the fixtures (name.mock.json) are used if exist,
+// the backends of the page are not called. the $global of the fixtures is merged over the global data of the app.
+// Set the mock.live of the page config to use the live data.
+func (page *Page) PreviewData(request *Request) (Data, error) {
+	live := page.Config != nil && page.Config.Mock != nil && page.Config.Mock.Live
+	if page.Codes.MOCK.Code == "" || live {
+		if page.Codes.DATA.Code == "" && page.GlobalData == nil {
+			return nil, nil
+		}
+		return page.Exec(request)
+	}
+
+	data := Data{}
+	err := jsoniter.UnmarshalFromString(page.Codes.MOCK.Code, &data)
+	if err != nil {
+		return nil, fmt.Errorf("%s fixtures error: %s", page.Codes.MOCK.File, err.Error())
+	}
+
+	global := map[string]interface{}{}
+	if page.GlobalData != nil {
+		global, err = request.ExecString(string(page.GlobalData))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if fixtures, ok := data["$global"].(map[string]interface{}); ok {
+		for key, value := range fixtures {
+			global[key] = value
+		}
+	}
+	data["$global"] = global
+	return data, nil
+}
+
 // RenderTitle render the title
 func (page *Page) RenderTitle(data Data) string {
 
@@ -168,6 +209,9 @@ func (page *Page) Link(r *Request) string {
 		}
 		if strings.HasPrefix(part, "[") && strings.HasSuffix(part, "]") {
 			name := strings.TrimSuffix(strings.TrimPrefix(part, "["), "]")
+			if param, _, err := ParseRouteParam(part); err == nil {
+				name = param.Name // [id:int], [...path]
+			}
 			if name == "" {
 				continue
 			}
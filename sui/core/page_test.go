@@ -31,3 +31,73 @@ func TestPageExec(t *testing.T) {
 	assert.Equal(t, "文章搜索 1", res.Get("articles.data[0].description"))
 	assert.Equal(t, "/test/path", res.Get("url.path"))
 }
+
+func TestWebComponentTag(t *testing.T) {
+	assert.Equal(t, "card-item", WebComponentTag("/card-item"))
+	assert.Equal(t, "sui-card", WebComponentTag("/card"))
+	assert.Equal(t, "shop-product-card", WebComponentTag("/shop/product_card"))
+	assert.Equal(t, "sui-404", WebComponentTag("/404"))
+}
+
+func TestCompileAsWebComponent(t *testing.T) {
+	page := &Page{Route: "/card-item"}
+	doc, err := NewDocumentString(`<html><body><div class="card" s:ready="init()"><h3>{{ "Card" }}</h3><p s:if="false">Hidden</p></div></body></html>`)
+	if err != nil {
+		t.Fatalf("NewDocumentString error: %v", err)
+	}
+
+	ctx := NewBuildContext(nil)
+	ctx.scripts = append(ctx.scripts, ScriptNode{Source: `function init(root) { document.querySelector(".card").classList.add("ready"); }`})
+	ctx.styles = append(ctx.styles, StyleNode{Source: `.card { color: red; }`})
+
+	js, _, err := page.bundleWebComponent(ctx, doc, nil, &BuildOption{})
+	if err != nil {
+		t.Fatalf("bundleWebComponent error: %v", err)
+	}
+
+	assert.Contains(t, js, `const tag = "card-item";`)
+	assert.Contains(t, js, `\u003ch3\u003eCard\u003c/h3\u003e`)
+	assert.Contains(t, js, `.card { color: red; }`)
+	assert.NotContains(t, js, "Hidden")
+	assert.NotContains(t, js, "{{")
+	assert.Contains(t, js, `const setup = function (document, host) {`)
+	assert.Contains(t, js, `document.querySelector(".card")`)
+	assert.Contains(t, js, `return typeof init === "function" ? init : null;`)
+	assert.NotContains(t, js, "document.head.appendChild")
+}
+
+func TestPagePreviewData(t *testing.T) {
+	page := &Page{
+		Route: "/index",
+		Codes: SourceCodes{
+			DATA: Source{File: "index.json", Code: `{"title": "live"}`},
+			MOCK: Source{File: "index.mock.json", Code: `{"title": "Fixture", "items": [1, 2]}`},
+		},
+		Config: &PageConfig{Mock: &PageMock{Method: "GET"}},
+	}
+
+	// The fixtures are used, the backends are not called
+	data, err := page.PreviewData(&Request{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Fixture", data["title"])
+	assert.Len(t, data["items"], 2)
+	assert.Equal(t, map[string]interface{}{}, data["$global"])
+
+	// The $global of the fixtures is merged over the global data
+	page.GlobalData = []byte(`{"site": "Yao", "lang": "en"}`)
+	page.Codes.MOCK.Code = `{"title": "Fixture", "$global": {"lang": "fr"}}`
+	data, err = page.PreviewData(&Request{})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"site": "Yao", "lang": "fr"}, data["$global"])
+	page.GlobalData = nil
+
+	page.Codes.MOCK.Code = `{"title":`
+	_, err = page.PreviewData(&Request{})
+	assert.Contains(t, err.Error(), "index.mock.json")
+
+	// No data
+	page.Codes = SourceCodes{}
+	data, err = page.PreviewData(&Request{})
+	assert.Nil(t, err)
+	assert.Nil(t, data)
+}
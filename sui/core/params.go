@@ -0,0 +1,164 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RouteParam the parameter of the dynamic route, the page directory name is the parameter, e.g.
+//
+//	[id]                      the string
+//	[id:int]                  the integer, $param.id is the number
+//	[price:float]             the float
+//	[draft:bool]              the boolean, true/false/1/0
+//	[uid:uuid]                the uuid
+//	[slug:regex(^[a-z-]+$)]   the string matches the pattern
+//	[...path]                 the catch-all, $param.path is the list of the segments
+type RouteParam struct {
+	Name     string
+	Type     string // string, int, float, bool, uuid, regex
+	Pattern  *regexp.Regexp
+	CatchAll bool
+}
+
+var reParamName = regexp.MustCompile(`^[0-9a-z_]+$`)
+var reParamUUID = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ParseRouteParam parse the segment of the route, false if the segment is not the parameter
+func ParseRouteParam(segment string) (*RouteParam, bool, error) {
+	if !strings.HasPrefix(segment, "[") || !strings.HasSuffix(segment, "]") {
+		return nil, false, nil
+	}
+
+	param := &RouteParam{Type: "string"}
+	name := segment[1 : len(segment)-1]
+	if strings.HasPrefix(name, "...") {
+		param.CatchAll = true
+		name = strings.TrimPrefix(name, "...")
+	}
+
+	name, typ, typed := strings.Cut(name, ":")
+	if !reParamName.MatchString(name) {
+		return nil, true, fmt.Errorf("the route parameter %s is invalid", segment)
+	}
+	param.Name = name
+	if !typed {
+		return param, true, nil
+	}
+
+	if param.CatchAll {
+		return nil, true, fmt.Errorf("the route parameter %s is invalid, the catch-all parameter can not be typed", segment)
+	}
+
+	switch {
+	case typ == "string", typ == "int", typ == "float", typ == "bool", typ == "uuid":
+		param.Type = typ
+
+	case strings.HasPrefix(typ, "regex(") && strings.HasSuffix(typ, ")"):
+		pattern := typ[len("regex(") : len(typ)-1]
+		if !strings.HasPrefix(pattern, "^") {
+			pattern = "^(?:" + pattern + ")$"
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, true, fmt.Errorf("the route parameter %s is invalid, %s", segment, err.Error())
+		}
+		param.Type = "regex"
+		param.Pattern = re
+
+	default:
+		return nil, true, fmt.Errorf("the route parameter %s is invalid, the type %s is not supported", segment, typ)
+	}
+	return param, true, nil
+}
+
+// RouteParams get the parameters of the route, e.g. /blog/[id:int]/[...path]
+func RouteParams(route string) ([]*RouteParam, error) {
+	params := []*RouteParam{}
+	for _, segment := range strings.Split(route, "/") {
+		param, ok, err := ParseRouteParam(segment)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			params = append(params, param)
+		}
+	}
+	return params, nil
+}
+
+// Value validate the value of the parameter and convert it to the type
+func (param *RouteParam) Value(value string) (interface{}, error) {
+	if param.CatchAll {
+		segments := []interface{}{}
+		for _, segment := range strings.Split(strings.Trim(value, "/"), "/") {
+			if segment != "" {
+				segments = append(segments, segment)
+			}
+		}
+		return segments, nil
+	}
+
+	switch param.Type {
+	case "int":
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("the route parameter %s %q is not an integer", param.Name, value)
+		}
+		return int(v), nil
+
+	case "float":
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("the route parameter %s %q is not a number", param.Name, value)
+		}
+		return v, nil
+
+	case "bool":
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("the route parameter %s %q is not a boolean", param.Name, value)
+		}
+		return v, nil
+
+	case "uuid":
+		if !reParamUUID.MatchString(value) {
+			return nil, fmt.Errorf("the route parameter %s %q is not an uuid", param.Name, value)
+		}
+
+	case "regex":
+		if !param.Pattern.MatchString(value) {
+			return nil, fmt.Errorf("the route parameter %s %q does not match %s", param.Name, value, param.Pattern.String())
+		}
+	}
+	return value, nil
+}
+
+// TypedParams validate the parameters of the request by the route and convert them to the types.
+// the parameters not in the route are kept as they are
+func TypedParams(route string, values map[string]string) (map[string]interface{}, error) {
+	params, err := RouteParams(route)
+	if err != nil {
+		return nil, err
+	}
+
+	typed := map[string]interface{}{}
+	for name, value := range values {
+		typed[name] = value
+	}
+
+	for _, param := range params {
+		value, has := values[param.Name]
+		if !has {
+			continue
+		}
+		v, err := param.Value(value)
+		if err != nil {
+			return nil, err
+		}
+		typed[param.Name] = v
+	}
+	return typed, nil
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteParams(t *testing.T) {
+	params, err := RouteParams("/blog/[id:int]/[slug:regex([a-z-]+)]/[...path]")
+	assert.Nil(t, err)
+	if assert.Len(t, params, 3) {
+		assert.Equal(t, "id", params[0].Name)
+		assert.Equal(t, "int", params[0].Type)
+		assert.Equal(t, "slug", params[1].Name)
+		assert.Equal(t, "regex", params[1].Type)
+		assert.Equal(t, "path", params[2].Name)
+		assert.True(t, params[2].CatchAll)
+	}
+
+	_, err = RouteParams("/blog/[id:number]")
+	assert.NotNil(t, err)
+
+	_, err = RouteParams("/blog/[...path:int]")
+	assert.NotNil(t, err)
+}
+
+func TestTypedParams(t *testing.T) {
+	route := "/blog/[id:int]/[slug:regex([a-z-]+)]/[...path]"
+	values, err := TypedParams(route, map[string]string{"id": "42", "slug": "hello-world", "path": "a/b/c", "lang": "en"})
+	assert.Nil(t, err)
+	assert.Equal(t, 42, values["id"])
+	assert.Equal(t, "hello-world", values["slug"])
+	assert.Equal(t, []interface{}{"a", "b", "c"}, values["path"])
+	assert.Equal(t, "en", values["lang"])
+
+	_, err = TypedParams(route, map[string]string{"id": "abc", "slug": "hello"})
+	assert.NotNil(t, err)
+
+	_, err = TypedParams(route, map[string]string{"id": "1", "slug": "Hello"})
+	assert.NotNil(t, err)
+
+	values, err = TypedParams("/users/[uid:uuid]/[draft:bool]", map[string]string{"uid": "9b2e7c4a-1f3d-4e5b-8a6c-7d8e9f0a1b2c", "draft": "true"})
+	assert.Nil(t, err)
+	assert.Equal(t, true, values["draft"])
+
+	// The typed values in the page data
+	r := &Request{Params: map[string]string{"id": "42"}}
+	r.Values, _ = TypedParams("/blog/[id:int]", r.Params)
+	parser := NewTemplateParser(Data{"$param": r.ParamValues()}, &ParserOption{Request: r})
+	html, err := parser.Render(`<div s:if="$param.id + 1 == 43">{{ $param.id }}</div>`)
+	assert.Nil(t, err)
+	assert.Contains(t, html, "<div>42</div>")
+}
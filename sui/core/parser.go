@@ -1,32 +1,73 @@
 package core
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
-	jsoniter "github.com/json-iterator/go"
 	"github.com/yaoapp/kun/log"
 	"golang.org/x/net/html"
 )
 
+// MaxRangeItems the max items of the s:for-range loop
+var MaxRangeItems = 10000
+
+// plainElements walk the elements without the directives directly, see isPlainElement. (the benchmarks compare the two walks)
+var plainElements = true
+
+// plainElementExcludes the elements rendered by parseElementNode without the directive attributes
+var plainElementExcludes = map[string]bool{
+	"s:include": true,
+	"s:csrf":    true,
+	"s:widget":  true,
+	"s:set":     true,
+	"set":       true,
+}
+
 // Load the jit components
 var components = map[string]string{}
 
 // TemplateParser parser for the template
 type TemplateParser struct {
-	data     Data
-	mapping  map[string]Mapping                  // variable mapping
-	sequence int                                 // sequence for the rendering
-	errors   []error                             // errors
-	replace  map[*goquery.Selection][]*html.Node // replace nodes
-	option   *ParserOption                       // parser option
-	locale   *Locale                             // locale
-	context  *ParserContext                      // parser context
-	scripts  []ScriptNode                        // scripts
-	styles   []StyleNode                         // styles
+	data       Data
+	mapping    map[string]Mapping      // variable mapping
+	sequence   int                     // sequence for the rendering
+	errors     []error                 // errors
+	replace    []replacement           // replace nodes, in order
+	option     *ParserOption           // parser option
+	locale     *Locale                 // locale
+	context    *ParserContext          // parser context
+	scripts    []ScriptNode            // scripts
+	styles     []StyleNode             // styles
+	tracks     []TrackEvent            // analytics events
+	scopes     []scope                 // lexical scopes of the variables
+	includes   []string                // the stack of the included files
+	keyScope   string                  // the scope of the stable keys (the loop items)
+	catching   int                     // the depth of the s:catch boundaries
+	guard      *DataGuard              // the access control of the data paths
+	fragments  []*fragmentCache        // the s:cache fragments
+	nodes      *nodeCounter            // the rendered elements, shared by the components rendered in parallel, see RenderLimits
+	depth      int                     // the nesting depth of the components
+	onces      map[string][]*html.Node // the rendered children of the s:once nodes
+	ctx        context.Context         // the context of the rendering, see RenderContext
+	spanCtx    context.Context         // the context of the current span, see SetTracer
+	span       Span                    // the current span, the render errors are recorded
+	static     map[*html.Node]int      // the static chunks of the pre-compiled template and their keys, see IR
+	assertion  *AssertionError         // the first violated s:assert in the strict mode
+	schedule   time.Time               // the nearest publish or unpublish time of the rendered nodes, see NextSchedule
+	geo        bool                    // the rendered nodes vary by the country of the visitor, see VaryGeo
+	audits     []AuditFinding          // the accessibility findings of the rendered page, see Audits
+	violations []Violation             // the HTML5 conformance violations of the rendered page, see Violations
+	hydrate    map[string]bool         // the data paths read by the client bindings, see PageHydration
+	bindings   map[string]Binding      // the text and the attribute bindings by the keys, see PageBindings
 }
 
 // ParserContext parser context for the template
@@ -46,18 +87,38 @@ type Mapping struct {
 
 // ParserOption parser option
 type ParserOption struct {
-	Component    bool              `json:"component,omitempty"`
-	Editor       bool              `json:"editor,omitempty"`
-	Preview      bool              `json:"preview,omitempty"`
-	Debug        bool              `json:"debug,omitempty"`
-	DisableCache bool              `json:"disableCache,omitempty"`
-	Route        string            `json:"route,omitempty"`
-	Theme        any               `json:"theme,omitempty"`
-	Locale       any               `json:"locale,omitempty"`
-	Root         string            `json:"root,omitempty"`
-	Imports      map[string]string `json:"imports,omitempty"`
-	Script       *Script           `json:"-"` // backend script
-	Request      *Request          `json:"request,omitempty"`
+	Component    bool               `json:"component,omitempty"`
+	Editor       bool               `json:"editor,omitempty"`
+	Preview      bool               `json:"preview,omitempty"`
+	Debug        bool               `json:"debug,omitempty"`
+	DisableCache bool               `json:"disableCache,omitempty"`
+	Route        string             `json:"route,omitempty"`
+	Theme        any                `json:"theme,omitempty"`
+	Locale       any                `json:"locale,omitempty"`
+	Root         string             `json:"root,omitempty"`
+	Imports      map[string]string  `json:"imports,omitempty"`
+	Format       string             `json:"format,omitempty"`      // html, json, partial
+	Embed        *PageEmbed         `json:"embed,omitempty"`       // embed mode
+	Fragment     bool               `json:"fragment,omitempty"`    // render the fragment, keep the structure of the input
+	Doctype      string             `json:"doctype,omitempty"`     // the doctype of the synthesized document, default "html"
+	StableKeys   bool               `json:"stableKeys,omitempty"`  // derive the keys from the node path and the statement
+	Deny         []string           `json:"deny,omitempty"`        // the data paths the expressions can not read, e.g. $session.token
+	CacheStore   string             `json:"cacheStore,omitempty"`  // the store of the s:cache fragments, the in-memory store by default
+	Restricted   *RestrictedProfile `json:"restricted,omitempty"`  // the restricted profile of the user-supplied templates
+	Precompile   bool               `json:"precompile,omitempty"`  // render the cached IR of the template instead of parsing the HTML
+	Minify       bool               `json:"minify,omitempty"`      // collapse the whitespace, strip the comments and minify the inline scripts and styles
+	Concurrency  int                `json:"concurrency,omitempty"` // the max goroutines to render the sibling just-in-time components, in order if less than 2
+	Strict       bool               `json:"strict,omitempty"`      // fail the rendering if a s:assert contract is violated
+	Audit        bool               `json:"audit,omitempty"`       // check the accessibility of the rendered page, see Audits
+	Validate     bool               `json:"validate,omitempty"`    // check the HTML5 conformance of the rendered page, see Violations
+	Limits       *RenderLimits      `json:"limits,omitempty"`      // the resource limits of the rendering, the defaults if nil
+	Delimiters   []string           `json:"delimiters,omitempty"`  // the statement delimiters of the source, e.g. ["[[", "]]"], see Delimit
+	Hydration    *PageHydration     `json:"hydration,omitempty"`   // the hydration payload of the page, the full data if nil
+	Bindings     bool               `json:"bindings,omitempty"`    // embed the binding map of the page, see PageBindings
+	Timing       *ServerTiming      `json:"-"`                     // server timing of the rendering phases
+	Profile      *Profile           `json:"-"`                     // the time of the components and the expressions, see Profile
+	Script       *Script            `json:"-"`                     // backend script
+	Request      *Request           `json:"request,omitempty"`
 }
 
 // var keepWords = map[string]bool{
@@ -73,30 +134,52 @@ type ParserOption struct {
 // }
 
 var allowUsePropAttrs = map[string]bool{
-	"s:if":        true,
-	"s:elif":      true,
-	"s:for":       true,
-	"s:event":     true,
-	"s:event-jit": true,
-	"s:event-cn":  true,
-	"s:render":    true,
-	"s:public":    true,
-	"s:assets":    true,
-	"s:route":     true,
+	"s:if":           true,
+	"s:elif":         true,
+	"s:show":         true,
+	"s:publish-at":   true,
+	"s:unpublish-at": true,
+	"s:class":        true,
+	"s:style":        true,
+	"s:props":        true,
+	"s:model":        true,
+	"s:for":          true,
+	"s:for-range":    true,
+	"s:for-where":    true,
+	"s:for-order":    true,
+	"s:for-limit":    true,
+	"s:for-offset":   true,
+	"s:event":        true,
+	"s:event-jit":    true,
+	"s:event-cn":     true,
+	"s:render":       true,
+	"s:public":       true,
+	"s:assets":       true,
+	"s:route":        true,
+	"s:teleport":     true,
+	"s:assert":       true,
+	"s:is-allow":     true,
+	"s:geo":          true,
+	"s:emit":         true,
 }
 
 var keepAttrs = map[string]bool{
-	"s:ns":        true,
-	"s:cn":        true,
-	"s:hash":      true,
-	"s:ready":     true,
-	"s:event":     true,
-	"s:event-jit": true,
-	"s:event-cn":  true,
-	"s:render":    true,
-	"s:public":    true,
-	"s:assets":    true,
-	"s:route":     true,
+	"s:ns":         true,
+	"s:cn":         true,
+	"s:hash":       true,
+	"s:ready":      true,
+	"s:event":      true,
+	"s:event-jit":  true,
+	"s:event-cn":   true,
+	"s:render":     true,
+	"s:public":     true,
+	"s:assets":     true,
+	"s:route":      true,
+	"s:track-id":   true,
+	"s:model":      true,
+	"s:event-mods": true,
+	"s:emit":       true,
+	"s:async":      true,
 }
 
 // NewTemplateParser create a new template parser
@@ -105,37 +188,82 @@ func NewTemplateParser(data Data, option *ParserOption) *TemplateParser {
 		option = &ParserOption{}
 	}
 
-	return &TemplateParser{
-		data:     data,
-		mapping:  map[string]Mapping{},
-		sequence: 0,
-		errors:   []error{},
-		replace:  map[*goquery.Selection][]*html.Node{},
-		option:   option,
-		scripts:  []ScriptNode{},
-		styles:   []StyleNode{},
-	}
+	parser := &TemplateParser{}
+	parser.prepare(data, option)
+	return parser
 }
 
 // Render parses and renders the HTML template
 func (parser *TemplateParser) Render(html string) (string, error) {
+	end := parser.startSpan("sui.render", "sui.route", parser.option.Route)
+	result, err := parser.render(html)
+	end(err)
+	return restoreDelimiters(result), err
+}
+
+func (parser *TemplateParser) render(html string) (string, error) {
+
+	if parser.option.Fragment {
+		return parser.renderFragment(html)
+	}
+
+	// Time the directives for the debug overlay
+	if parser.debug() && parser.option.Timing == nil {
+		parser.option.Timing = NewServerTiming()
+	}
 
 	if !strings.Contains(html, "<html") {
-		html = fmt.Sprintf(`<!DOCTYPE html><html lang="en-us">%s</html>`, html)
+		html = fmt.Sprintf(`%s<html lang="en-us">%s</html>`, parser.doctype(), html)
 	}
 
-	doc, err := NewDocumentString(html)
+	stop := parser.option.Timing.Start("parse", "Parse")
+	doc, err := parser.document(html)
+	stop()
 	if err != nil {
 		return "", err
 	}
 
 	// Set the locale
+	stop = parser.option.Timing.Start("locale", "Locale")
 	parser.locale = parser.Locale()
+	stop()
+
+	stop = parser.option.Timing.Start("render", "Render")
 	err = parser.RenderSelection(doc.Selection)
+	stop()
 	if err != nil {
 		return "", err
 	}
 
+	// The accessibility audit of the rendered nodes
+	if parser.auditEnabled() && !parser.option.Component {
+		stop = parser.option.Timing.Start("audit", "Accessibility Audit")
+		parser.audits = Audit(doc.Find("body"))
+		stop()
+	}
+
+	// The HTML5 conformance of the rendered nodes, located by the source map
+	if parser.validating() {
+		stop = parser.option.Timing.Start("validate", "HTML Validation")
+		parser.violations = append(parser.violations, Validate(doc.Selection)...)
+		for _, node := range doc.Selection.Nodes {
+			stripPositions(node)
+		}
+		stop()
+	}
+
+	// For partial, return the body only
+	if parser.option.Format == FormatPartial {
+		if parser.option.Request != nil || parser.option.Preview {
+			doc.Find("[sui-hide]").Remove()
+			parser.Tidy(doc.Selection)
+		}
+		if parser.option.Minify {
+			parser.minify(doc.Selection)
+		}
+		return doc.Find("body").Html()
+	}
+
 	// Append the head
 	head := doc.Find("head")
 	if head.Length() > 0 {
@@ -145,7 +273,7 @@ func (parser *TemplateParser) Render(html string) (string, error) {
 			scriptMessages = parser.locale.ScriptMessages
 		}
 
-		data, err := jsoniter.MarshalToString(scriptMessages)
+		data, err := jsonStable.MarshalToString(scriptMessages)
 		if err != nil {
 			data = "{}"
 		}
@@ -169,11 +297,27 @@ func (parser *TemplateParser) Render(html string) (string, error) {
 	// Append the data to the body
 	body := doc.Find("body")
 	if body.Length() > 0 && !parser.option.Component {
-		data, err := jsoniter.MarshalToString(parser.data)
+		inline := ""
+		if parser.hydrate != nil {
+			inline = doc.Find("script").Text()
+		}
+		data, err := jsonStable.MarshalToString(parser.hydrationData(parser.clientData(), inline))
 		if err != nil {
-			data, _ = jsoniter.MarshalToString(map[string]string{"error": err.Error()})
+			data, _ = jsonStable.MarshalToString(map[string]string{"error": err.Error()})
 		}
 		body.AppendHtml(bodyInjectionScript(data, parser.debug()))
+		if track := parser.trackInjectionScript(); track != "" {
+			body.AppendHtml(track)
+		}
+		if bindings := parser.bindingsInjectionScript(); bindings != "" {
+			body.AppendHtml(bindings)
+		}
+		if overlay := parser.debugInjection(); overlay != "" {
+			body.AppendHtml(overlay)
+		}
+		if parser.option.Embed.Enable() && parser.option.Embed.Height {
+			body.AppendHtml(embedInjectionScript(parser.option.Embed.Origins))
+		}
 		parser.addScripts(body, parser.filterScripts("body", parser.scripts))
 
 		// Append the just-in-time components
@@ -194,6 +338,10 @@ func (parser *TemplateParser) Render(html string) (string, error) {
 		parser.Tidy(doc.Selection)
 	}
 
+	if parser.option.Minify {
+		parser.minify(doc.Selection)
+	}
+
 	// fmt.Println(doc.Html())
 	// fmt.Println(parser.errors)
 	return doc.Html()
@@ -206,70 +354,246 @@ func (parser *TemplateParser) RenderSelection(section *goquery.Selection) error
 		return fmt.Errorf("No nodes found")
 	}
 
+	// Release the pending replacements if the rendering exits early
+	defer parser.releaseReplace(0)
+
+	parser.visitorNode(section.Nodes[0])
 	parser.parseNode(section.Nodes[0])
-	// Replace the nodes
-	for sel, nodes := range parser.replace {
-		sel.ReplaceWithNodes(nodes...)
-		delete(parser.replace, sel)
+	if parser.assertion != nil {
+		return parser.assertion
 	}
 
+	// Replace the nodes
+	parser.applyReplace()
+
 	parser.Fmt(section)
+	parser.flushFragments()
+	parser.teleportNodes(section)
 	return nil
 }
 
 // Fmt formats the HTML template
 func (parser *TemplateParser) Fmt(doc *goquery.Selection) {
-	if parser.locale != nil {
-		sels := doc.Find(`[s\:trans-fmt]`)
-		sels.Each(func(i int, sel *goquery.Selection) {
-			name := sel.AttrOr("s:trans-fmt", "")
-			sel.SetText(parser.locale.Fmt(name, sel.Text()))
-		})
+	if parser.locale == nil {
+		return
+	}
+	for _, node := range doc.Nodes {
+		parser.fmtNode(node)
+	}
+}
+
+// fmtNode format the text of the s:trans-fmt descendants
+func (parser *TemplateParser) fmtNode(node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+
+		name, has := "", false
+		for _, attr := range child.Attr {
+			if attr.Key == "s:trans-fmt" {
+				name, has = attr.Val, true
+				break
+			}
+		}
+		if !has {
+			parser.fmtNode(child)
+			continue
+		}
+
+		text := acquireBuffer()
+		nodeText(child, text)
+		for c := child.FirstChild; c != nil; c = child.FirstChild {
+			child.RemoveChild(c)
+		}
+		child.AppendChild(&html.Node{Type: html.TextNode, Data: parser.locale.Fmt(name, text.String())})
+		releaseBuffer(text)
+	}
+}
+
+// nodeText get the text of the node and the descendants
+func nodeText(node *html.Node, text *bytes.Buffer) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.TextNode {
+			text.WriteString(child.Data)
+			continue
+		}
+		nodeText(child, text)
 	}
 }
 
 // Parse  parses and renders the HTML template
 func (parser *TemplateParser) parseNode(node *html.Node) {
 
+	// Stop the walk if the context is cancelled, see RenderContext
+	if parser.cancelled() {
+		return
+	}
+
+	// The static chunks of the pre-compiled template have nothing to render, the keys are skipped
+	if sequence, has := parser.static[node]; has {
+		parser.sequence += sequence
+		return
+	}
+
 	skipChildren := false
 
 	switch node.Type {
 	case html.ElementNode:
+		// The elements without the directives and the bindings are walked directly, no selection is created
+		if parser.isPlainElement(node) {
+			if !parser.parsePlainElement(node) {
+				parser.restrictRemove(goquery.NewDocumentFromNode(node).Selection)
+				skipChildren = true
+				break
+			}
+			skipChildren = isInertTemplate(node)
+			break
+		}
+
 		sel := goquery.NewDocumentFromNode(node).Selection
 		if parser.hasParsed(sel) {
 			break
 		}
 		parser.parseElementNode(sel)
 
-		// Skip children if the node is a loop node、element component or JIT component
-		skipChildren = parser.hasForStatement(sel) || parser.isElementComponent(sel) || parser.isJitComponent(sel)
+		// Skip children if the node is a loop node、element component, JIT component, include, generated image, catch, cached fragment, s:html, s:once, s:toc or native template
+		skipChildren = parser.hasForStatement(sel) || parser.isElementComponent(sel) || parser.isJitComponent(sel) || parser.isInclude(sel) || parser.isCode(sel) || parser.isCatch(sel) || parser.isCacheHit(sel) || parser.isHTML(sel) || parser.isOnce(sel) || parser.isToc(sel) || isInertTemplate(node)
 
 	case html.TextNode:
 		parser.parseTextNode(node)
 	}
 
+	// The options of the s:model select are rendered by the children (e.g. s:for)
+	model := node.Type == html.ElementNode && node.Data == "select" && hasAttr(node, "s:model")
+	offset := len(parser.replace)
+
 	// Recursively process child nodes
 	if !skipChildren {
-		for child := node.FirstChild; child != nil; child = child.NextSibling {
+		parser.renderConcurrentComponents(node)
+		for child := node.FirstChild; child != nil; {
+			next := child.NextSibling
 			parser.parseNode(child)
+
+			// The child replaced by the rendered nodes (e.g. the just-in-time component) is detached, go on with the next one
+			if child.Parent == node {
+				next = child.NextSibling
+			}
+			child = next
+		}
+	}
+
+	if model {
+		parser.modelOptions(node, offset)
+	}
+
+	// The assets of the s:cache fragment
+	if len(parser.fragments) > 0 && node.Type == html.ElementNode {
+		parser.endFragment(node)
+	}
+}
+
+// isPlainElement check if the element has nothing to render but the children, e.g. <div class="row">,
+// the directives, the components, the bindings and the restricted profile are rendered by parseElementNode
+func (parser *TemplateParser) isPlainElement(node *html.Node) bool {
+	if !plainElements || parser.option.Restricted != nil || plainElementExcludes[node.Data] || codeComponents[node.Data] {
+		return false
+	}
+
+	if parser.nodes.load() >= parser.option.Limits.maxNodes() {
+		return false
+	}
+
+	for _, attr := range node.Attr {
+		if strings.HasPrefix(attr.Key, "s:") || strings.HasPrefix(attr.Key, "...") || strings.HasPrefix(attr.Key, ":") || attr.Key == "is" || attr.Key == "parsed" {
+			return false
 		}
+		if strings.Contains(attr.Val, "{{") && dataTokens.MatchString(attr.Val) {
+			return false
+		}
+	}
+	return true
+}
+
+// parsePlainElement count the element and take the keys of the attributes, the same as parseElementNode does.
+// return false if the element is over the max elements, e.g. counted by the components rendered in parallel
+func (parser *TemplateParser) parsePlainElement(node *html.Node) bool {
+	if !parser.countNode(node) {
+		return false
 	}
+	parser.sequence += len(node.Attr)
+	return true
 }
 
 func (parser *TemplateParser) parseElementNode(sel *goquery.Selection) {
 
+	// The resource limits and the restricted profile of the user-supplied templates
+	if !parser.limitNode(sel) || !parser.restrictNode(sel) {
+		return
+	}
+
 	parser.transElementNode(sel) // Translations
 
 	node := sel.Get(0)
 
-	if _, exist := sel.Attr("s:for"); exist {
+	if parser.hasForStatement(sel) {
 		parser.forStatementNode(sel)
 	}
 
+	// Include the partial
+	if parser.isInclude(sel) {
+		parser.includeNode(sel)
+		return
+	}
+
+	// The CSRF token of the forms
+	if parser.isCSRF(sel) {
+		parser.csrfNode(sel)
+		return
+	}
+
+	// The admin widgets (table, form, chart)
+	if parser.isWidget(sel) {
+		parser.widgetNode(sel)
+		return
+	}
+
+	// Built-in generated image components (QR code, barcode, avatar, identicon, placeholder)
+	if parser.isCode(sel) {
+		parser.codeNode(sel)
+		return
+	}
+
 	if _, exist := sel.Attr("s:if"); exist {
 		parser.ifStatementNode(sel)
 	}
 
+	// The publish window, the node is hidden out of the window (the loop items are checked by the loop)
+	if !parser.hasForStatement(sel) && parser.hasSchedule(sel) && !parser.scheduleNode(sel) {
+		return
+	}
+
+	// The region of the visitor, the node is hidden for the other regions (the loop items are checked by the loop)
+	if _, exist := sel.Attr("s:geo"); exist && !parser.hasForStatement(sel) && !parser.geoNode(sel) {
+		return
+	}
+
+	// The data contract
+	if _, exist := sel.Attr("s:assert"); exist {
+		parser.assertNode(sel)
+	}
+
+	// Toggle the visibility, keep the node in the output
+	if _, exist := sel.Attr("s:show"); exist {
+		parser.showStatementNode(sel)
+	}
+
+	// Fragment cache
+	if _, exist := sel.Attr("s:cache"); exist && parser.cacheNode(sel) {
+		sel.SetAttr("s:cache-hit", "true")
+		return
+	}
+
 	// keep the node if the editor is enabled
 	if _, exist := sel.Attr("s:set"); exist || node.Data == "s:set" || node.Data == "set" {
 		parser.setStatementNode(sel)
@@ -285,14 +609,58 @@ func (parser *TemplateParser) parseElementNode(sel *goquery.Selection) {
 		parser.parseJitComponent(sel)
 	}
 
+	// Render once
+	if parser.isOnce(sel) {
+		parser.onceNode(sel)
+	}
+
+	// Table of contents
+	if parser.isToc(sel) {
+		parser.tocNode(sel)
+	}
+
+	// Sanitized raw html
+	if parser.isHTML(sel) {
+		parser.htmlElementNode(sel)
+	}
+
+	// Bot protection of the form
+	if _, exist := sel.Attr("s:form"); exist {
+		parser.formElementNode(sel)
+	}
+
+	// Error boundary
+	if parser.isCatch(sel) {
+		parser.catchNode(sel)
+	}
+
+	// Analytics events
+	if _, exist := sel.Attr("s:track"); exist {
+		parser.trackElementNode(sel)
+	}
+
 	// Parse the attributes
 	parser.parseElementAttrs(sel)
+	parser.restrictAssets(sel)
 }
 
 func (parser *TemplateParser) parseElementComponent(sel *goquery.Selection) {
 
+	defer parser.option.Timing.Start("components", "Components")()
 	parser.parsed(sel)
+	if !parser.limitDepth(sel, "s:cn") {
+		return
+	}
 	com := sel.AttrOr("s:cn", "")
+	defer parser.startSpan("sui.component", "sui.component", com, "sui.route", parser.option.Route)(nil)
+	defer parser.profileComponent(com)()
+
+	// The async component is filled in by the render api after the page is loaded
+	if parser.isAsync(sel) {
+		parser.asyncNode(sel)
+		return
+	}
+
 	props := map[string]interface{}{}
 	for _, attr := range sel.Nodes[0].Attr {
 		if strings.HasPrefix(attr.Key, "prop:") {
@@ -301,7 +669,7 @@ func (parser *TemplateParser) parseElementComponent(sel *goquery.Selection) {
 				continue
 			}
 
-			val, replaces := parser.data.Replace(attr.Val)
+			val, replaces := parser.data.ReplaceGuard(attr.Val, parser.guard)
 			if HasJSON(replaces) {
 				sel.SetAttr(fmt.Sprintf("json-attr-prop:%s", key), "true")
 			}
@@ -314,6 +682,15 @@ func (parser *TemplateParser) parseElementComponent(sel *goquery.Selection) {
 		}
 	}
 
+	// The spread props of the component call, the props set on the element win
+	for name, value := range parser.spreadProps(sel) {
+		if key := ToCamelCase(name); key != "" {
+			if _, has := props[key]; !has {
+				props[key] = value
+			}
+		}
+	}
+
 	// load the component based on the route
 	var script *Script
 	var err error
@@ -322,22 +699,27 @@ func (parser *TemplateParser) parseElementComponent(sel *goquery.Selection) {
 			file := filepath.Join(string(os.PathSeparator), "public", parser.option.Root, route)
 			script, err = LoadScript(file, parser.disableCache())
 			if err != nil {
-				parser.errors = append(parser.errors, err)
-				setError(sel, err)
+				parser.componentError(sel, "s:cn", com, err)
 				return
 			}
 		}
 	}
 
 	compParser := parser.clone(script)
+	compParser.depth = parser.depth + 1
 	dataRaw := ""
 
 	// Call the BeforeRender Hook
 	if script != nil {
-		data, err := script.BeforeRender(parser.option.Request, props)
+		var data Data
+		end := parser.startSpan("sui.script", "sui.component", com, "sui.method", "BeforeRender")
+		err := parser.callContext(func() (err error) {
+			data, err = script.BeforeRender(parser.option.Request, props)
+			return err
+		})
+		end(err)
 		if err != nil {
-			parser.errors = append(parser.errors, err)
-			setError(sel, err)
+			parser.componentError(sel, "s:cn", com, err)
 			return
 		}
 		if data != nil {
@@ -345,7 +727,7 @@ func (parser *TemplateParser) parseElementComponent(sel *goquery.Selection) {
 				compParser.data[k] = v
 			}
 		}
-		dataRaw, err = jsoniter.MarshalToString(data)
+		dataRaw, err = jsonStable.MarshalToString(data)
 		if err != nil {
 			dataRaw = fmt.Sprintf(`"%s"`, err.Error())
 		}
@@ -357,10 +739,16 @@ func (parser *TemplateParser) parseElementComponent(sel *goquery.Selection) {
 		sel.SetAttr("json:__component_data", dataRaw)
 	}
 
-	err = compParser.RenderSelection(sel)
+	err = renderComponent(compParser, sel)
+	parser.errors = compParser.errors // the errors of the component
+	parser.tracks = compParser.tracks // the analytics events of the component
+	parser.scheduleAt(compParser.schedule)
+	parser.geo = parser.geo || compParser.geo
+	if parser.assertion == nil {
+		parser.assertion = compParser.assertion
+	}
 	if err != nil {
-		parser.errors = append(parser.errors, err)
-		setError(sel, err)
+		parser.componentError(sel, "s:cn", com, err)
 	}
 	parser.sequence = compParser.sequence + 1
 	parser.context = compParser.context
@@ -369,11 +757,17 @@ func (parser *TemplateParser) parseElementComponent(sel *goquery.Selection) {
 
 func (parser *TemplateParser) clone(script *Script) *TemplateParser {
 	var new = *parser
-	new.data = Data{}
-	for k, v := range parser.data {
-		new.data[k] = v
-	}
+	new.data = parser.data.Overlay()
 	new.option.Script = script
+	new.scopes = nil
+	new.fragments = nil
+	new.replace = []replacement{}
+
+	// The slices of the pooled parser have the spare capacity, clip them to not share the backing arrays
+	new.errors = parser.errors[:len(parser.errors):len(parser.errors)]
+	new.scripts = parser.scripts[:len(parser.scripts):len(parser.scripts)]
+	new.styles = parser.styles[:len(parser.styles):len(parser.styles)]
+	new.tracks = parser.tracks[:len(parser.tracks):len(parser.tracks)]
 	return &new
 }
 
@@ -386,26 +780,26 @@ func (parser *TemplateParser) isElementComponent(sel *goquery.Selection) bool {
 
 func (parser *TemplateParser) transTextNode(node *html.Node) {
 
-	parentSel := goquery.NewDocumentFromNode(node.Parent).Selection
 	text := strings.TrimSpace(node.Data)
-	if text == "" {
+	if text == "" || node.Parent == nil {
 		return
 	}
 
-	// Translate the node
-	if key, exists := parentSel.Attr("s:trans-node"); exists {
-		text = parser.transNode(key, text)
+	// Translate the node, the plural form is selected by the s:trans-plural count
+	forms := parser.pluralForms(node.Parent)
+	if key, exists := nodeAttr(node.Parent, "s:trans-node"); exists {
+		text = parser.transNode(key, text, forms)
 	}
 
 	// Escape the text
-	if _, exists := parentSel.Attr("s:trans-escape"); exists {
+	if hasAttr(node.Parent, "s:trans-escape") {
 		text = parser.escapeText(text)
 	}
 
 	// Translate the text
-	if v, exists := parentSel.Attr("s:trans-text"); exists {
+	if v, exists := nodeAttr(node.Parent, "s:trans-text"); exists {
 		keys := strings.Split(v, ",")
-		text = parser.transText(text, keys)
+		text = parser.transText(text, keys, forms)
 	}
 
 	node.Data = strings.Replace(node.Data, strings.TrimSpace(node.Data), text, 1)
@@ -413,6 +807,7 @@ func (parser *TemplateParser) transTextNode(node *html.Node) {
 
 func (parser *TemplateParser) transElementNode(sel *goquery.Selection) {
 
+	var forms []string
 	for _, attr := range sel.Nodes[0].Attr {
 		if strings.HasPrefix(attr.Key, "s:trans-attr-") {
 			keys := strings.Split(attr.Val, ",")
@@ -421,7 +816,10 @@ func (parser *TemplateParser) transElementNode(sel *goquery.Selection) {
 			if value == "" {
 				continue
 			}
-			newValue := parser.transText(value, keys)
+			if forms == nil {
+				forms = parser.pluralForms(sel.Nodes[0])
+			}
+			newValue := parser.transText(value, keys, forms)
 			sel.SetAttr(name, newValue)
 		}
 	}
@@ -458,24 +856,25 @@ func (parser *TemplateParser) escape(value string) string {
 	return value
 }
 
-func (parser *TemplateParser) transNode(key string, message string) string {
+func (parser *TemplateParser) transNode(key string, message string, forms []string) string {
 
 	if parser.locale == nil {
 		return message
 	}
 
-	if lcMessage, has := parser.locale.Keys[key]; has && lcMessage != message {
-		return lcMessage
+	// The ICU messages are formatted by the data, e.g. {count, plural, one {# item} other {# items}}
+	if lcMessage, has := parser.localeKey(key, forms); has && lcMessage != message {
+		return parser.formatMessage(lcMessage)
 	}
 
-	if lcMessage, has := parser.locale.Messages[message]; has {
-		return lcMessage
+	if lcMessage, has := parser.localeMessage(message, forms); has {
+		return parser.formatMessage(lcMessage)
 	}
 
 	return message
 }
 
-func (parser *TemplateParser) transText(content string, keys []string) string {
+func (parser *TemplateParser) transText(content string, keys []string, forms []string) string {
 
 	matches := dataTokens.FindAllStringSubmatch(content, -1)
 	newContent := content
@@ -504,13 +903,13 @@ func (parser *TemplateParser) transText(content string, keys []string) string {
 			}
 
 			key := keys[i]
-			if lcMessage, has := parser.locale.Keys[key]; has && lcMessage != message {
+			if lcMessage, has := parser.localeKey(key, forms); has && lcMessage != message {
 				newContent = strings.Replace(newContent, "::"+message, lcMessage, 1)
 
 				continue
 			}
 
-			if lcMessage, has := parser.locale.Messages[message]; has {
+			if lcMessage, has := parser.localeMessage(message, forms); has {
 				newContent = strings.Replace(newContent, "::"+message, lcMessage, 1)
 				continue
 			}
@@ -522,17 +921,8 @@ func (parser *TemplateParser) transText(content string, keys []string) string {
 }
 
 // Remove the tag and replace it with the children
-func (parser *TemplateParser) removeWrapper(sel *goquery.Selection) {
-	children := sel.Children()
-	if children.Length() == 0 {
-		sel.Remove()
-		return
-	}
-	sel.ReplaceWithSelection(children)
-}
-
 func (parser *TemplateParser) setStatementNode(sel *goquery.Selection) {
-
+	defer parser.option.Timing.Start("set", "s:set")()
 	sel.SetAttr("parsed", "true")
 
 	name := sel.AttrOr("name", "")
@@ -542,20 +932,22 @@ func (parser *TemplateParser) setStatementNode(sel *goquery.Selection) {
 
 	valueExp := sel.AttrOr("value", "")
 	if dataTokens.MatchString(valueExp) {
-		val, _, err := parser.data.Exec(valueExp)
+		val, _, err := parser.data.ExecGuard(valueExp, parser.guard)
+		parser.hydrateStmt(valueExp)
 		if err != nil {
 			log.Warn("Set %s: %s", valueExp, err)
-			parser.data[name] = valueExp
+			parser.setVar(name, valueExp)
 			return
 		}
-		parser.data[name] = val
+		parser.setVar(name, val)
 		return
 	}
 
-	parser.data[name] = valueExp
+	parser.setVar(name, valueExp)
 }
 
 func (parser *TemplateParser) parseElementAttrs(sel *goquery.Selection, force ...bool) {
+	defer parser.option.Timing.Start("attrs", "Attribute bindings")()
 	if len(sel.Nodes) < 0 {
 		return
 	}
@@ -569,14 +961,15 @@ func (parser *TemplateParser) parseElementAttrs(sel *goquery.Selection, force ..
 	}
 
 	attrs := sel.Nodes[0].Attr
+	shorthands := []string{}
 	for _, attr := range attrs {
 
 		if strings.HasPrefix(attr.Key, "s:attr-") {
 			parser.sequence = parser.sequence + 1
-			val, _, _ := parser.data.Exec(attr.Val)
+			val, _, _ := parser.data.ExecGuard(attr.Val, parser.guard)
 			if v, ok := val.(bool); ok {
 				if v {
-					sel.SetAttr(strings.TrimPrefix(attr.Key, "s:attr-"), "")
+					sel.SetAttr(attrName(sel.Nodes[0], strings.TrimPrefix(attr.Key, "s:attr-")), "")
 				}
 			}
 			continue
@@ -592,22 +985,14 @@ func (parser *TemplateParser) parseElementAttrs(sel *goquery.Selection, force ..
 			key := attr.Key[3:]
 			if parser.data != nil {
 				if values, ok := parser.data[key].(map[string]any); ok {
-					for name, value := range values {
-						switch v := value.(type) {
-						case string:
-							sel.SetAttr(name, v)
-						case bool, int, float64:
-							sel.SetAttr(name, fmt.Sprintf("%v", v))
-
-						case nil:
-							sel.SetAttr(name, "")
-
-						default:
-							str, err := jsoniter.MarshalToString(value)
-							if err != nil {
-								continue
-							}
-							sel.SetAttr(name, str)
+					for _, name := range sortedKeys(values) {
+						val, json, ok := spreadValue(values[name])
+						if !ok {
+							continue
+						}
+						name = attrName(sel.Nodes[0], name)
+						sel.SetAttr(name, val)
+						if json {
 							sel.SetAttr(fmt.Sprintf("json-attr-%s", name), "true")
 						}
 					}
@@ -616,24 +1001,53 @@ func (parser *TemplateParser) parseElementAttrs(sel *goquery.Selection, force ..
 			}
 		}
 
-		parser.sequence = parser.sequence + 1
-		res, values := parser.data.Replace(attr.Val)
+		// The shorthand binding, :href="link.url" is the same as href="{{ link.url }}"
+		name, value := attr.Key, attr.Val
+		shorthand := len(attr.Key) > 1 && attr.Key[0] == ':'
+		if shorthand {
+			name = attrName(sel.Nodes[0], attr.Key[1:])
+			value = fmt.Sprintf("{{ %s }}", strings.TrimSpace(attr.Val))
+			shorthands = append(shorthands, attr.Key)
+		}
+
+		key := parser.nextKey(sel.Nodes[0], attr.Key+"="+attr.Val)
+		res, values := parser.data.ReplaceGuard(value, parser.guard)
+		parser.catchValues(sel.Nodes[0], name, values)
 		if values != nil && len(values) > 0 {
-			bindings := strings.TrimSpace(attr.Val)
-			key := fmt.Sprintf("%v", parser.sequence)
-			parser.mapping[attr.Key] = Mapping{
+			bindings := strings.TrimSpace(value)
+			parser.hydrateStmt(bindings)
+			parser.bind(sel.Nodes[0], key, "attr", name, bindings)
+			parser.mapping[name] = Mapping{
 				Key:   key,
 				Type:  "attr",
 				Value: bindings,
 			}
-			sel.SetAttr(attr.Key, res)
-			bindname := fmt.Sprintf("s:bind:%s", attr.Key)
+			sel.SetAttr(name, res)
+			bindname := fmt.Sprintf("s:bind:%s", name)
 			sel.SetAttr(bindname, bindings)
 			if HasJSON(values) {
-				sel.SetAttr(fmt.Sprintf("json-attr-%s", attr.Key), "true")
+				sel.SetAttr(fmt.Sprintf("json-attr-%s", name), "true")
 			}
 		}
 	}
+
+	// Remove the shorthand bindings after the loop, the attributes are shared with the loop
+	for _, key := range shorthands {
+		sel.RemoveAttr(key)
+	}
+
+	// The spread attributes, the attributes set on the element win
+	if props := parser.spreadProps(sel); len(props) > 0 {
+		parser.spreadAttrs(sel, props)
+	}
+
+	// The class and style object bindings, merged with the attributes evaluated above
+	parser.classStyleNode(sel)
+
+	// The two-way binding of the form controls, the value is compared with the attributes evaluated above
+	if _, exist := sel.Attr("s:model"); exist {
+		parser.modelNode(sel)
+	}
 }
 
 // Check if the element attributes have the s:raw command.
@@ -649,14 +1063,17 @@ func checkIsRawElement(node *html.Node) bool {
 	return false
 }
 func (parser *TemplateParser) parseTextNode(node *html.Node) {
+	defer parser.option.Timing.Start("text", "Text bindings")()
 	parser.transTextNode(node) // Translations
-	parser.sequence = parser.sequence + 1
-	res, values := parser.data.Replace(node.Data)
+	key := parser.nextKey(node, node.Data)
+	res, values := parser.data.ReplaceGuard(node.Data, parser.guard)
+	parser.catchValues(node.Parent, "text", values)
 	// Bind the variable to the parent node
 	if node.Parent != nil && values != nil && len(values) > 0 {
 		bindings := strings.TrimSpace(node.Data)
-		key := fmt.Sprintf("%v", parser.sequence)
 		if bindings != "" {
+			parser.hydrateStmt(bindings)
+			parser.bind(node, key, "text", "", bindings)
 			if checkIsRawElement(node) {
 				node.Type = html.RawNode
 			}
@@ -672,31 +1089,57 @@ func (parser *TemplateParser) hasForStatement(sel *goquery.Selection) bool {
 	if _, exist := sel.Attr("s:for"); exist {
 		return true
 	}
+	if _, exist := sel.Attr("s:for-range"); exist {
+		return true
+	}
 	return false
 }
 
 func (parser *TemplateParser) forStatementNode(sel *goquery.Selection) {
-
-	parser.sequence = parser.sequence + 1
-	parser.setKey("for", sel, parser.sequence)
+	defer parser.option.Timing.Start("for", "s:for")()
+	defer parser.startSpan("sui.for", "sui.for", sel.AttrOr("s:for", sel.AttrOr("s:for-range", "")))(nil)
+	forKey := parser.nextKey(sel.Nodes[0], sel.AttrOr("s:for", sel.AttrOr("s:for-range", "")))
+	parser.setKey("for", sel, forKey)
 	parser.parsed(sel)
 	parser.hide(sel) // Hide loop node
 
-	forAttr, _ := sel.Attr("s:for")
-	forItems, _, err := parser.data.Exec(forAttr)
+	var forItems interface{}
+	var err error
+	directive, forAttr := "s:for", sel.AttrOr("s:for", "")
+	if rangeAttr, has := sel.Attr("s:for-range"); has {
+		directive, forAttr = "s:for-range", rangeAttr
+		rangeAttr, _ = parser.data.ReplaceGuard(rangeAttr, parser.guard)
+		forItems, err = toRange(rangeAttr)
+	} else {
+		forItems, _, err = parser.data.ExecGuard(forAttr, parser.guard)
+		parser.hydrateStmt(forAttr)
+	}
 	if err != nil {
-		parser.errors = append(parser.errors, err)
+		parser.renderError(sel.Nodes[0], directive, forAttr, err)
 		return
 	}
 
-	items, err := parser.toArray(forItems)
+	items, keys, err := parser.toEntries(forItems, sel.AttrOr("s:for-key-order", ""))
 	if err != nil {
-		parser.errors = append(parser.errors, err)
+		parser.renderError(sel.Nodes[0], directive, forAttr, err)
 		return
 	}
 
 	itemVarName := sel.AttrOr("s:for-item", "item")
 	indexVarName := sel.AttrOr("s:for-index", "index")
+	keyVarName, hasKeyVar := sel.Attr("s:for-key")
+	if keyVarName == "" {
+		keyVarName = "key"
+	}
+
+	items, keys, err = parser.loopModifiers(sel, items, keys, loopVars{item: itemVarName, index: indexVarName, key: keyVarName})
+	if err != nil {
+		parser.renderError(sel.Nodes[0], directive, forAttr, err)
+		return
+	}
+	items, keys = parser.limitLoop(sel.Nodes[0], items, keys)
+	items, keys = parser.restrictLoop(sel.Nodes[0], items, keys)
+	parser.spanAttr("sui.loop.size", len(items))
 	itemNodes := []*html.Node{}
 
 	// Keep the node if the editor is enabled
@@ -705,40 +1148,68 @@ func (parser *TemplateParser) forStatementNode(sel *goquery.Selection) {
 		itemNodes = append(itemNodes, clone.Nodes...)
 	}
 
+	keyScope := parser.keyScope
 	for idx, item := range items {
 
+		if parser.cancelled() {
+			break
+		}
+
 		// Create a new node
 		new := sel.Clone()
 		parser.removeParsed(new)
-		parser.data[itemVarName] = item
-		parser.data[indexVarName] = idx
+		if !parser.limitNode(new) {
+			break
+		}
+		parser.pushScope()
+		parser.keyScope = fmt.Sprintf("%s/%s#%d", keyScope, forKey, idx)
+		parser.setVar(itemVarName, item)
+		parser.setVar(indexVarName, idx)
+		if keys != nil {
+			parser.setVar(keyVarName, keys[idx])
+		} else if hasKeyVar {
+			parser.setVar(keyVarName, idx)
+		}
 
 		// parser attributes
 		// Copy the if Attr from the parent node
 		if ifAttr, exists := new.Attr("s:if"); exists {
 
-			res, _, err := parser.data.Exec(ifAttr)
+			res, _, err := parser.data.ExecGuard(ifAttr, parser.guard)
 			if err != nil {
-				parser.errors = append(parser.errors, fmt.Errorf("if statement %v error: %v", parser.sequence, err))
+				parser.renderError(sel.Nodes[0], "s:if", ifAttr, err)
 				setError(new, err)
 				parser.show(new)
 				itemNodes = append(itemNodes, new.Nodes...)
+				parser.popScope()
 				continue
 			}
 
 			if res == true {
 				parser.hide(new)
+				parser.popScope()
 				continue
 			}
 		}
 
+		// The publish window of the item
+		if parser.hasSchedule(new) && !parser.scheduleNode(new) {
+			parser.popScope()
+			continue
+		}
+
+		// The region of the item
+		if _, exist := new.Attr("s:geo"); exist && !parser.geoNode(new) {
+			parser.popScope()
+			continue
+		}
+
 		parser.parseElementAttrs(new)
 		parser.parsed(new)
 
 		// Set the key
-		parser.sequence = parser.sequence + 1
 		parser.setKey("for-item-index", new, idx)
-		parser.setKey("for-item-key", new, parser.sequence)
+		parser.setKey("for-item-key", new, parser.nextKey(new.Nodes[0], fmt.Sprintf("%s#%d", forKey, idx)))
 
 		// Show the node
 		parser.show(new)
@@ -752,21 +1223,21 @@ func (parser *TemplateParser) forStatementNode(sel *goquery.Selection) {
 			parser.parseNode(new.Nodes[i])
 		}
 		itemNodes = append(itemNodes, new.Nodes...)
-	}
 
-	// Clean up the variables
-	delete(parser.data, itemVarName)
-	delete(parser.data, indexVarName)
+		// Restore the variables of the outer scope
+		parser.popScope()
+	}
+	parser.keyScope = keyScope
 
 	// Replace the node
 	// sel.ReplaceWithNodes(itemNodes...)
-	parser.replace[sel] = itemNodes
+	parser.addReplace(sel, itemNodes)
 }
 
 func (parser *TemplateParser) ifStatementNode(sel *goquery.Selection) {
+	defer parser.option.Timing.Start("if", "s:if")()
 
-	parser.sequence = parser.sequence + 1
-	parser.setKey("if", sel, parser.sequence)
+	parser.setKey("if", sel, parser.nextKey(sel.Nodes[0], sel.AttrOr("s:if", "")))
 	parser.parsed(sel)
 	parser.hide(sel) // Hide all elif and else nodes
 
@@ -782,9 +1253,9 @@ func (parser *TemplateParser) ifStatementNode(sel *goquery.Selection) {
 	}
 
 	// show the node if the condition is true
-	res, _, err := parser.data.Exec(ifAttr)
+	res, _, err := parser.data.ExecGuard(ifAttr, parser.guard)
 	if err != nil {
-		parser.errors = append(parser.errors, fmt.Errorf("if statement %v error: %v", parser.sequence, err))
+		parser.renderError(sel.Nodes[0], "s:if", ifAttr, err)
 		return
 	}
 
@@ -799,9 +1270,9 @@ func (parser *TemplateParser) ifStatementNode(sel *goquery.Selection) {
 	// else if
 	for _, elifNode := range elifNodes {
 		elifAttr := elifNode.AttrOr("s:elif", "")
-		res, _, err := parser.data.Exec(elifAttr)
+		res, _, err := parser.data.ExecGuard(elifAttr, parser.guard)
 		if err != nil {
-			parser.errors = append(parser.errors, err)
+			parser.renderError(elifNode.Nodes[0], "s:elif", elifAttr, err)
 			return
 		}
 
@@ -847,6 +1318,38 @@ func (parser *TemplateParser) elseStatementNode(sel *goquery.Selection) ([]*goqu
 	return elifNodes, elseNode
 }
 
+// showStatementNode the s:show directive, toggle display:none instead of removing the node,
+// so the client scripts can flip the visibility without re-rendering
+func (parser *TemplateParser) showStatementNode(sel *goquery.Selection) {
+	defer parser.option.Timing.Start("show", "s:show")()
+	showAttr := sel.AttrOr("s:show", "")
+	res, _, err := parser.data.ExecGuard(showAttr, parser.guard)
+	if err != nil {
+		parser.renderError(sel.Nodes[0], "s:show", showAttr, err)
+		return
+	}
+
+	// Remove the display:none set by the previous rendering
+	styles := []string{}
+	for _, style := range strings.Split(sel.AttrOr("style", ""), ";") {
+		style = strings.TrimSpace(style)
+		if style == "" || strings.ReplaceAll(style, " ", "") == "display:none" {
+			continue
+		}
+		styles = append(styles, style)
+	}
+
+	if res != true {
+		styles = append(styles, "display: none")
+	}
+
+	if len(styles) == 0 {
+		sel.RemoveAttr("style")
+		return
+	}
+	sel.SetAttr("style", strings.Join(styles, "; "))
+}
+
 func (parser *TemplateParser) setSuiAttr(sel *goquery.Selection, key, value string) *goquery.Selection {
 	key = fmt.Sprintf("data-sui-%s", key)
 	return sel.SetAttr(key, value)
@@ -902,45 +1405,103 @@ func (parser *TemplateParser) show(sel *goquery.Selection) {
 	// sel.SetAttr("style", style)
 }
 
-// Tidy the template by removing the parsed attributes
+// Tidy the template by removing the parsed attributes, the nodes are visited in a single pass
 func (parser *TemplateParser) Tidy(s *goquery.Selection) {
+	for _, node := range s.Nodes {
+		parser.tidyNode(node)
+	}
+}
 
-	s.Contents().Each(func(i int, child *goquery.Selection) {
-
-		node := child.Get(0)
-		if _, exist := child.Attr("s:jit"); node.Data == "slot" || exist {
-			parser.Tidy(child)
-			parser.removeWrapper(child)
-			return
+// tidyNode tidy the children of the node
+func (parser *TemplateParser) tidyNode(node *html.Node) {
+	for child := node.FirstChild; child != nil; {
+		next := child.NextSibling
+		switch {
+		case child.Type == html.ElementNode && (child.Data == "slot" || hasAttr(child, "s:jit")):
+			parser.tidyNode(child)
+			unwrapNode(child)
+
+		case child.Data == "s:set" || child.Type == html.CommentNode:
+			node.RemoveChild(child)
+
+		case isInertTemplate(child):
+			// Keep the native template for the client runtime
+			tidyAttrs(child)
+
+		default:
+			tidyAttrs(child)
+			parser.tidyNode(child)
 		}
+		child = next
+	}
+}
 
-		if node.Data == "s:set" {
-			child.Remove()
-			return
+// tidyAttrs remove the parsed attributes, the attributes are filtered in place
+func tidyAttrs(node *html.Node) {
+	n := 0
+	for _, attr := range node.Attr {
+		if strings.HasPrefix(attr.Key, "s:") && !keepAttrs[attr.Key] && !strings.HasPrefix(attr.Key, "s:on-") {
+			continue
 		}
 
-		if node.Type == html.CommentNode {
-			child.Remove()
-			return
+		if attr.Key == "parsed" || attr.Key == "is" || strings.HasPrefix(attr.Key, "...") {
+			continue
 		}
+		node.Attr[n] = attr
+		n++
+	}
+	node.Attr = node.Attr[:n]
+}
 
-		// Remove the parsed attribute
-		attrs := []html.Attribute{}
-		for _, attr := range node.Attr {
-			if strings.HasPrefix(attr.Key, "s:") && !keepAttrs[attr.Key] && !strings.HasPrefix(attr.Key, "s:on-") {
-				continue
-			}
+// unwrapNode replace the wrapper with the child elements, remove the wrapper if it has no child elements
+func unwrapNode(node *html.Node) {
+	parent := node.Parent
+	if parent == nil {
+		return
+	}
+	for child := node.FirstChild; child != nil; {
+		next := child.NextSibling
+		if child.Type == html.ElementNode {
+			node.RemoveChild(child)
+			parent.InsertBefore(child, node)
+		}
+		child = next
+	}
+	parent.RemoveChild(node)
+}
 
-			if attr.Key == "parsed" || attr.Key == "is" || strings.HasPrefix(attr.Key, "...") {
-				continue
-			}
-			attrs = append(attrs, attr)
+// isInertTemplate check if the node is a native <template>, the content is inert, not rendered but kept for the client runtime
+// the <template> with the slot attribute or the directives is rendered by SUI
+func isInertTemplate(node *html.Node) bool {
+	if node.Type != html.ElementNode || node.Data != "template" {
+		return false
+	}
+	for _, attr := range node.Attr {
+		if attr.Key == "slot" || strings.HasPrefix(attr.Key, "s:") {
+			return false
 		}
+	}
+	return true
+}
 
-		node.Attr = attrs
-		parser.Tidy(child)
-	})
+// hasAttr check if the node has the attribute
+// nodeAttr get the attribute of the node without creating the selection
+func nodeAttr(node *html.Node, key string) (string, bool) {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
 
+func hasAttr(node *html.Node, key string) bool {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
 }
 
 func (parser *TemplateParser) key(prefix string, sel *goquery.Selection) string {
@@ -977,6 +1538,92 @@ func (parser *TemplateParser) disableCache() bool {
 	return (parser.option != nil && parser.option.DisableCache) || parser.debug()
 }
 
+// toRange convert the range "start,end[,step]" to the loop items, the end is inclusive
+// e.g. "1,5" => [1,2,3,4,5], "10,0,5" => [10,5,0]
+func toRange(value string) ([]interface{}, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("range %s error: should be start,end[,step]", value)
+	}
+
+	numbers := []int{}
+	for _, part := range parts {
+		number, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("range %s error: %s", value, err.Error())
+		}
+		numbers = append(numbers, number)
+	}
+
+	start, end, step := numbers[0], numbers[1], 1
+	if start > end {
+		step = -1
+	}
+	if len(numbers) == 3 {
+		step = numbers[2]
+		if step < 0 && start < end || step > 0 && start > end {
+			step = -step
+		}
+	}
+
+	if step == 0 {
+		return nil, fmt.Errorf("range %s error: step should not be 0", value)
+	}
+
+	// Count the items in uint64, the distance of the int bounds overflows the int
+	distance, stride := uint64(end)-uint64(start), uint64(step)
+	if step < 0 {
+		distance, stride = uint64(start)-uint64(end), -uint64(step)
+	}
+	if distance/stride >= uint64(MaxRangeItems) {
+		return nil, fmt.Errorf("range %s error: too many items (max %d)", value, MaxRangeItems)
+	}
+
+	count := distance/stride + 1
+
+	res := make([]interface{}, 0, count)
+	for i := uint64(0); i < count; i++ {
+		res = append(res, start+int(i)*step)
+	}
+	return res, nil
+}
+
+// toEntries convert the value to the loop items, if the value is a map, the keys will be returned
+// order: "asc" (default, key-sorted), "desc" (key-sorted descending). The decoded maps do not keep the
+// insertion order, use an array of the entries if the order matters
+func (parser *TemplateParser) toEntries(value interface{}, order string) ([]interface{}, []string, error) {
+
+	if order != "" && order != "asc" && order != "desc" {
+		return nil, nil, fmt.Errorf("s:for-key-order %s error: should be asc or desc", order)
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Map {
+		items, err := parser.toArray(value)
+		return items, nil, err
+	}
+
+	values := map[string]interface{}{}
+	keys := make([]string, 0, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		key := fmt.Sprintf("%v", iter.Key().Interface())
+		keys = append(keys, key)
+		values[key] = iter.Value().Interface()
+	}
+
+	sort.Strings(keys)
+	if order == "desc" {
+		sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	}
+
+	items := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, values[key])
+	}
+	return items, keys, nil
+}
+
 func (parser *TemplateParser) toArray(value interface{}) ([]interface{}, error) {
 	switch values := value.(type) {
 
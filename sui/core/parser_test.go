@@ -1,10 +1,18 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/html"
 )
 
 func TestRender(t *testing.T) {
@@ -38,3 +46,923 @@ func TestRender(t *testing.T) {
 	assert.Contains(t, html, "hello space")
 	assert.Equal(t, 0, len(parser.errors))
 }
+
+func TestParserToEntries(t *testing.T) {
+	parser := NewTemplateParser(Data{}, nil)
+
+	items, keys, err := parser.toEntries(map[string]interface{}{"b": 2, "a": 1, "c": 3}, "")
+	if err != nil {
+		t.Fatalf("toEntries error: %v", err)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, keys)
+	assert.Equal(t, []interface{}{1, 2, 3}, items)
+
+	items, keys, err = parser.toEntries(map[string]string{"b": "B", "a": "A"}, "desc")
+	if err != nil {
+		t.Fatalf("toEntries error: %v", err)
+	}
+	assert.Equal(t, []string{"b", "a"}, keys)
+	assert.Equal(t, []interface{}{"B", "A"}, items)
+
+	items, keys, err = parser.toEntries([]interface{}{"x", "y"}, "")
+	if err != nil {
+		t.Fatalf("toEntries error: %v", err)
+	}
+	assert.Nil(t, keys)
+	assert.Equal(t, []interface{}{"x", "y"}, items)
+
+	// The insertion order is not supported, the decoded maps do not keep it
+	_, _, err = parser.toEntries(map[string]interface{}{"b": 2, "a": 1}, "insertion")
+	assert.NotNil(t, err)
+}
+
+func TestParserToRange(t *testing.T) {
+	items, err := toRange("1,5")
+	if err != nil {
+		t.Fatalf("toRange error: %v", err)
+	}
+	assert.Equal(t, []interface{}{1, 2, 3, 4, 5}, items)
+
+	items, err = toRange("10, 0, 5")
+	if err != nil {
+		t.Fatalf("toRange error: %v", err)
+	}
+	assert.Equal(t, []interface{}{10, 5, 0}, items)
+
+	_, err = toRange("1,10,0")
+	assert.NotNil(t, err)
+
+	_, err = toRange("1")
+	assert.NotNil(t, err)
+
+	// The distance of the bounds overflows the int
+	_, err = toRange("-9223372036854775808,9223372036854775807")
+	assert.NotNil(t, err)
+
+	_, err = toRange("9223372036854775807,-9223372036854775808,-1")
+	assert.NotNil(t, err)
+
+	items, err = toRange("9223372036854775805,9223372036854775807")
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{9223372036854775805, 9223372036854775806, 9223372036854775807}, items)
+}
+
+func TestParserScope(t *testing.T) {
+	parser := NewTemplateParser(Data{"item": "outer", "title": "Page"}, nil)
+
+	parser.pushScope()
+	parser.setVar("item", "a")
+	parser.setVar("index", 0)
+
+	parser.pushScope()
+	parser.setVar("item", "a.1")
+	parser.setVar("title", "Nested")
+	assert.Equal(t, "a.1", parser.data["item"])
+	parser.popScope()
+
+	assert.Equal(t, "a", parser.data["item"])
+	assert.Equal(t, 0, parser.data["index"])
+	assert.Equal(t, "Page", parser.data["title"])
+	parser.popScope()
+
+	assert.Equal(t, "outer", parser.data["item"])
+	assert.NotContains(t, parser.data, "index")
+
+	// Without scope, the variables are set to the page data
+	parser.setVar("total", 10)
+	assert.Equal(t, 10, parser.data["total"])
+}
+
+func TestParserLoopModifiers(t *testing.T) {
+	parser := NewTemplateParser(Data{"min": 10}, nil)
+	doc, err := NewDocumentString(`<ul><li s:for="products" s:for-where="item.price >= min" s:for-order="item.price desc" s:for-offset="1" s:for-limit="2"></li></ul>`)
+	if err != nil {
+		t.Fatalf("NewDocumentString error: %v", err)
+	}
+
+	items := []interface{}{
+		map[string]interface{}{"name": "a", "price": 5},
+		map[string]interface{}{"name": "b", "price": 30},
+		map[string]interface{}{"name": "c", "price": 20},
+		map[string]interface{}{"name": "d", "price": 10.5},
+		map[string]interface{}{"name": "e", "price": 40},
+	}
+
+	sel := doc.Find("li")
+	res, keys, err := parser.loopModifiers(sel, items, nil, loopVars{item: "item", index: "index", key: "key"})
+	if err != nil {
+		t.Fatalf("loopModifiers error: %v", err)
+	}
+
+	assert.Nil(t, keys)
+	assert.Len(t, res, 2)
+	assert.Equal(t, "b", res[0].(map[string]interface{})["name"])
+	assert.Equal(t, "c", res[1].(map[string]interface{})["name"])
+	assert.NotContains(t, parser.data, "item")
+}
+
+func TestParserRenderFragment(t *testing.T) {
+	parser := NewTemplateParser(Data{"name": "Yao"}, &ParserOption{Fragment: true, Preview: true})
+	html, err := parser.Render(`<tr-row><p>Hello {{ name }}</p></tr-row><span s:if="name == 'Yao'">!</span>`)
+	if err != nil {
+		t.Fatalf("Render error: %v", err)
+	}
+
+	assert.NotContains(t, html, "<html")
+	assert.NotContains(t, html, "<body")
+	assert.NotContains(t, html, "__sui_data")
+	assert.Contains(t, html, "Hello Yao</p></tr-row>")
+	assert.Contains(t, html, ">!</span>")
+
+	// The table rows and cells are kept
+	parser = NewTemplateParser(Data{"rows": []interface{}{"a", "b"}}, &ParserOption{Fragment: true, Preview: true})
+	html, err = parser.Render(`<!-- rows --><tr s:for="rows" s:for-item="row"><td>{{ row }}</td></tr>`)
+	assert.Nil(t, err)
+	assert.Contains(t, html, "<td>a</td></tr>")
+	assert.Contains(t, html, "<td>b</td></tr>")
+
+	parser = NewTemplateParser(Data{"name": "Yao"}, &ParserOption{Fragment: true, Preview: true})
+	html, err = parser.Render(`<td>{{ name }}</td><th>Name</th>`)
+	assert.Nil(t, err)
+	assert.Contains(t, html, "<td>Yao</td><th>Name</th>")
+
+	parser = NewTemplateParser(Data{}, &ParserOption{Fragment: true, Preview: true})
+	html, err = parser.Render(`<option value="1">One</option><option value="2">Two</option>`)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `<option value="1">One</option><option value="2">Two</option>`)
+}
+
+func TestParserSharedData(t *testing.T) {
+	source := `<html><head></head><body><s:set name="title" value="Changed"></s:set>` +
+		`<p s:for="items" s:for-item="title">{{ title }}</p><h1>{{ title }}</h1></body></html>`
+
+	// The top level keys of the shared data are not changed by the renderings in parallel
+	shared := Data{"title": "Home", "items": []interface{}{"a", "b"}}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			parser := NewTemplateParser(shared, &ParserOption{Request: &Request{}})
+			html, err := parser.Render(source)
+			assert.Nil(t, err)
+			assert.Contains(t, html, "<p>a</p><p>b</p>")
+			assert.Contains(t, html, "<h1>Changed</h1>")
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, Data{"title": "Home", "items": []interface{}{"a", "b"}}, shared)
+
+	// The layers are merged on top in order
+	overlay := shared.Overlay(Data{"title": "Page"}, Data{"$props": "x"})
+	assert.Equal(t, "Page", overlay["title"])
+	assert.Equal(t, "x", overlay["$props"])
+	assert.Equal(t, "Home", shared["title"])
+}
+
+func TestParserRenderFragmentSelector(t *testing.T) {
+	source := `<html><head></head><body><header>{{ title }}</header>` +
+		`<div id="cart"><span class="{{ cls }}">{{ count }} items</span><em s:if="count == 0">Empty</em></div>` +
+		`</body></html>`
+
+	parser := NewTemplateParser(Data{"title": "Shop", "count": 2, "cls": "badge"}, &ParserOption{Route: "/shop", Request: &Request{}})
+	html, mapping, err := parser.RenderFragment("#cart", source)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(html, `<div id="cart">`))
+	assert.Contains(t, html, "2 items")
+	assert.NotContains(t, html, "Empty")
+	assert.NotContains(t, html, "Shop")
+	assert.Equal(t, "{{ cls }}", mapping["class"].Value)
+
+	// The selector is not found
+	_, _, err = parser.RenderFragment("#missing", source)
+	assert.NotNil(t, err)
+}
+
+func TestParserIncludeFile(t *testing.T) {
+	parser := NewTemplateParser(Data{}, &ParserOption{Root: "demo", Route: "/docs/index"})
+
+	file, err := parser.includeFile("/partials/header.html")
+	assert.Nil(t, err)
+	assert.Equal(t, "/public/demo/partials/header.html", file)
+
+	file, err = parser.includeFile("footer.html")
+	assert.Nil(t, err)
+	assert.Equal(t, "/public/demo/docs/footer.html", file)
+
+	parser.includes = []string{"/public/demo/partials/header.html"}
+	file, err = parser.includeFile("nav.html")
+	assert.Nil(t, err)
+	assert.Equal(t, "/public/demo/partials/nav.html", file)
+
+	_, err = parser.includeFile("../../../etc/passwd")
+	assert.NotNil(t, err)
+}
+
+func TestParserIncludeRender(t *testing.T) {
+	files := map[string]string{
+		"/public/demo/partials/list.html":  `<li s:for="items" s:for-item="item">{{ item }}</li>`,
+		"/public/demo/partials/state.html": `<p s:if="state == 'a'">A</p><p s:elif="state == 'b'">B</p><p s:else>C</p>`,
+		"/public/demo/partials/loop.html":  `<div><s:include src="loop.html">Fallback</s:include></div>`,
+	}
+	read := includeRead
+	includeRead = func(file string) ([]byte, error) {
+		source, has := files[file]
+		if !has {
+			return nil, fmt.Errorf("%s not found", file)
+		}
+		return []byte(source), nil
+	}
+	defer func() { includeRead = read }()
+
+	render := func(source string, data Data) (string, *TemplateParser) {
+		parser := NewTemplateParser(data, &ParserOption{Root: "demo", Route: "/index", Fragment: true, Request: &Request{}})
+		html, err := parser.Render(source)
+		assert.Nil(t, err)
+		return html, parser
+	}
+
+	// The loop of the top level
+	html, parser := render(`<ul><s:include src="/partials/list.html"></s:include></ul>`, Data{"items": []interface{}{"x", "y", "z"}})
+	assert.Contains(t, html, "<li>x</li><li>y</li><li>z</li>")
+	assert.Empty(t, parser.Errors())
+
+	// The conditional chain of the top level
+	html, _ = render(`<div><s:include src="/partials/state.html"></s:include></div>`, Data{"state": "b"})
+	assert.Contains(t, html, "<p>B</p>")
+	assert.NotContains(t, html, ">A</p>")
+	assert.NotContains(t, html, ">C</p>")
+
+	html, _ = render(`<div><s:include src="/partials/state.html"></s:include></div>`, Data{"state": "x"})
+	assert.Contains(t, html, "<p>C</p>")
+	assert.NotContains(t, html, ">B</p>")
+
+	// The cycle detection
+	html, parser = render(`<section><s:include src="/partials/loop.html"></s:include></section>`, Data{})
+	assert.Contains(t, html, "Fallback")
+	assert.NotEmpty(t, parser.Errors())
+	assert.Contains(t, fmt.Sprintf("%v", parser.Errors()), "cycle detected")
+	assert.Empty(t, parser.includes)
+}
+
+func TestParserStableKeys(t *testing.T) {
+	source := `<div><i s:for="items" s:for-item="item">{{ item }}</i><p s:if="show">{{ title }}</p><span>{{ name }}</span></div>`
+	keyOf := func(data Data, stable bool) string {
+		parser := NewTemplateParser(data, &ParserOption{Fragment: true, StableKeys: stable})
+		html, err := parser.Render(source)
+		if err != nil {
+			t.Fatalf("Render error: %v", err)
+		}
+		doc, err := NewDocumentString(html)
+		if err != nil {
+			t.Fatalf("NewDocumentString error: %v", err)
+		}
+		return doc.Find("span").AttrOr("s:key-text", "")
+	}
+
+	short := Data{"items": []interface{}{"a"}, "show": true, "title": "Hello", "name": "Yao"}
+	long := Data{"items": []interface{}{"a", "b", "c"}, "show": false, "name": "Yao"}
+
+	// The sequence keys drift with the loop length and the branches
+	assert.NotEqual(t, keyOf(short, false), keyOf(long, false))
+
+	// The stable keys do not
+	key := keyOf(short, true)
+	assert.NotEmpty(t, key)
+	assert.Equal(t, key, keyOf(long, true))
+
+	// The same text in the sibling text nodes has the different keys
+	doc, err := NewDocumentString(`<p>{{ name }}<br/>{{ name }}</p>`)
+	if err != nil {
+		t.Fatalf("NewDocumentString error: %v", err)
+	}
+	p := doc.Find("p").Nodes[0]
+	parser := NewTemplateParser(Data{}, &ParserOption{StableKeys: true})
+	assert.NotEqual(t, parser.nextKey(p.FirstChild, "{{ name }}"), parser.nextKey(p.LastChild, "{{ name }}"))
+	assert.Equal(t, "html:0/body:1/p:0/#text:1", nodePath(p.LastChild))
+}
+
+func TestParserReplaceSlots(t *testing.T) {
+	caller, err := NewDocumentString(`<div is="/card"><template slot="header"><h1>Title</h1></template><p>Body</p></div>`)
+	if err != nil {
+		t.Fatalf("NewDocumentString error: %v", err)
+	}
+
+	comp, err := NewDocumentString(`<div class="card"><slot name="header"><h2>Default</h2></slot><slot name="footer"><small>Footer</small></slot><main><slot>Empty</slot></main></div>`)
+	if err != nil {
+		t.Fatalf("NewDocumentString error: %v", err)
+	}
+
+	parser := NewTemplateParser(Data{}, nil)
+	compSel := comp.Find(".card")
+	parser.replaceSlots(caller.Find("[is]"), compSel)
+
+	html, err := goquery.OuterHtml(compSel)
+	if err != nil {
+		t.Fatalf("OuterHtml error: %v", err)
+	}
+	assert.Equal(t, `<div class="card"><h1>Title</h1><small>Footer</small><main><p>Body</p></main></div>`, html)
+}
+
+func TestParserReplaceSlotsStandard(t *testing.T) {
+	caller, err := NewDocumentString(`<div is="/card"><span slot="footer">Copyright</span><p>Body</p></div>`)
+	if err != nil {
+		t.Fatalf("NewDocumentString error: %v", err)
+	}
+
+	comp, err := NewDocumentString(`<div class="card"><slot name="footer"></slot><main><slot></slot></main><template shadowrootmode="open"><slot name="native"></slot></template></div>`)
+	if err != nil {
+		t.Fatalf("NewDocumentString error: %v", err)
+	}
+
+	parser := NewTemplateParser(Data{}, nil)
+	compSel := comp.Find(".card")
+	parser.replaceSlots(caller.Find("[is]"), compSel)
+
+	html, err := goquery.OuterHtml(compSel)
+	if err != nil {
+		t.Fatalf("OuterHtml error: %v", err)
+	}
+	assert.Contains(t, html, `<span>Copyright</span><main><p>Body</p></main>`)
+	assert.Contains(t, html, `<slot name="native"></slot>`)
+}
+
+func TestParserCatch(t *testing.T) {
+	source := `<section s:catch="err"><p s:if="err == nil">{{ 1 + "a" }}</p><p s:if="err != nil" class="error">{{ err.message != "" ? "Failed" : "" }}</p></section>`
+	parser := NewTemplateParser(Data{}, &ParserOption{Fragment: true, Preview: true})
+	output, err := parser.Render(source)
+	if err != nil {
+		t.Fatalf("Render error: %v", err)
+	}
+
+	assert.Contains(t, output, `class="error"`)
+	assert.Contains(t, output, "Failed")
+	assert.Len(t, parser.errors, 0)
+
+	// The binding errors are the render errors with the node path and the expression
+	parser = NewTemplateParser(Data{}, &ParserOption{})
+	node := &html.Node{Type: html.ElementNode, Data: "a"}
+	_, values := parser.data.Replace(`{{ 1 + "a" }}`)
+	parser.catching++
+	parser.catchValues(node, "title", values)
+	parser.catching--
+	parser.catchValues(node, "title", values)
+
+	errs := parser.Errors()
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "a", errs[0].Path)
+	assert.Equal(t, "title", errs[0].Directive)
+	assert.Contains(t, errs[0].Expression, `1 + "a"`)
+}
+
+func TestParserReplaceRelease(t *testing.T) {
+	source := `<ul><li s:for="items" s:for-item="item"><span s:for="item.tags" s:for-item="tag">{{ tag }}</span></li></ul>`
+	data := Data{"items": []interface{}{
+		map[string]interface{}{"tags": []interface{}{"a", "b"}},
+		map[string]interface{}{"tags": []interface{}{"c"}},
+	}}
+
+	before := ReplaceMetrics()
+	parser := NewTemplateParser(data, &ParserOption{Fragment: true, Preview: true})
+	html, err := parser.Render(source)
+	if err != nil {
+		t.Fatalf("Render error: %v", err)
+	}
+	assert.Contains(t, html, "a</span>")
+	assert.Contains(t, html, "c</span>")
+	assert.Len(t, parser.replace, 0)
+	assert.Equal(t, int64(3), ReplaceMetrics().Applied-before.Applied)
+
+	// The pending replacements are released when the rendering exits early
+	doc, err := NewDocumentString(`<div><p>Hello</p></div>`)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	sel := doc.Find("p")
+	parser.addReplace(sel, sel.Clone().Nodes)
+	parser.addReplace(sel, sel.Clone().Nodes)
+	parser.releaseReplace(1)
+	assert.Len(t, parser.replace, 1)
+	assert.Nil(t, parser.replace[:2][1].sel)
+
+	// The detached nodes are skipped
+	sel.Remove()
+	parser.applyReplace()
+	assert.Len(t, parser.replace, 0)
+	assert.Equal(t, int64(1), ReplaceMetrics().Detached-before.Detached)
+}
+
+func TestParserTidy(t *testing.T) {
+	doc, err := NewDocumentString(`<div s:if="true" parsed="true" s:on-click="fn" class="a"><!-- c --><s:set name="x" value="1"></s:set><div s:jit="true" is="comp"><p s:key-text="1">Hi</p>text</div><slot></slot></div>`)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	parser := NewTemplateParser(Data{}, nil)
+	parser.Tidy(doc.Selection)
+	html, err := doc.Find("body").Html()
+	if err != nil {
+		t.Fatalf("Html error: %v", err)
+	}
+	assert.Equal(t, `<div s:on-click="fn" class="a"><p>Hi</p></div>`, html)
+}
+
+// tidyLegacy the Selection based tidy, the baseline of the benchmarks
+func tidyLegacy(s *goquery.Selection) {
+	s.Contents().Each(func(i int, child *goquery.Selection) {
+		node := child.Get(0)
+		if _, exist := child.Attr("s:jit"); node.Data == "slot" || exist {
+			tidyLegacy(child)
+			if child.Children().Length() == 0 {
+				child.Remove()
+				return
+			}
+			child.ReplaceWithSelection(child.Children())
+			return
+		}
+
+		if node.Data == "s:set" || node.Type == html.CommentNode {
+			child.Remove()
+			return
+		}
+
+		attrs := []html.Attribute{}
+		for _, attr := range node.Attr {
+			if strings.HasPrefix(attr.Key, "s:") && !keepAttrs[attr.Key] && !strings.HasPrefix(attr.Key, "s:on-") {
+				continue
+			}
+			if attr.Key == "parsed" || attr.Key == "is" || strings.HasPrefix(attr.Key, "...") {
+				continue
+			}
+			attrs = append(attrs, attr)
+		}
+		node.Attr = attrs
+		tidyLegacy(child)
+	})
+}
+
+func benchmarkTidySource() string {
+	var b strings.Builder
+	for i := 0; i < 500; i++ {
+		fmt.Fprintf(&b, `<div s:for="items" parsed="true" class="row"><span s:key-text="%d" s:bind="item">Item %d</span><!-- item --></div>`, i, i)
+	}
+	return b.String()
+}
+
+func BenchmarkTidy(b *testing.B) {
+	source := benchmarkTidySource()
+	parser := NewTemplateParser(Data{}, nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		doc, _ := NewDocumentString(source)
+		b.StartTimer()
+		parser.Tidy(doc.Selection)
+	}
+}
+
+func BenchmarkTidyLegacy(b *testing.B) {
+	source := benchmarkTidySource()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		doc, _ := NewDocumentString(source)
+		b.StartTimer()
+		tidyLegacy(doc.Selection)
+	}
+}
+
+func benchmarkRenderSource() string {
+	var b strings.Builder
+	b.WriteString(`<html><head></head><body><main class="page">`)
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&b, `<section class="row" id="row-%d"><div class="col"><h2 class="title">Row %d</h2><p class="text">Static <b>text</b> <i>and</i> <a href="#row-%d">link</a></p></div>`, i, i, i)
+		b.WriteString(`<ul class="list"><li class="item">One</li><li class="item">Two</li><li class="item">{{ name }}</li></ul></section>`)
+	}
+	b.WriteString(`</main></body></html>`)
+	return b.String()
+}
+
+func benchmarkRender(b *testing.B, plain bool) {
+	defer func(v bool) { plainElements = v }(plainElements)
+	plainElements = plain
+
+	source := benchmarkRenderSource()
+	data := Data{"name": "Yao"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		parser := NewTemplateParser(data, &ParserOption{Request: &Request{}})
+		if _, err := parser.Render(source); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParserRender(b *testing.B) {
+	benchmarkRender(b, true)
+}
+
+func BenchmarkParserRenderSelections(b *testing.B) {
+	benchmarkRender(b, false)
+}
+
+func TestParserPlainElements(t *testing.T) {
+	source := benchmarkRenderSource()
+	render := func(plain bool) string {
+		defer func(v bool) { plainElements = v }(plainElements)
+		plainElements = plain
+		parser := NewTemplateParser(Data{"name": "Yao"}, &ParserOption{Request: &Request{}})
+		html, err := parser.Render(source)
+		assert.Nil(t, err)
+		return html
+	}
+
+	// The direct walk renders the same output and the same keys
+	assert.Equal(t, render(false), render(true))
+}
+
+func TestParserDeterministic(t *testing.T) {
+	source := `<html><head></head><body><div ...attrs><ul><li s:for="users" s:for-item="user" data-user="{{ user }}">{{ user.name }}</li></ul></div></body></html>`
+	data := Data{
+		"attrs": map[string]interface{}{"id": "main", "class": "list", "title": "Users", "role": "list", "data-a": 1, "data-b": true},
+		"users": []interface{}{
+			map[string]interface{}{"name": "Ada", "role": "admin", "email": "ada@example.com", "age": 36},
+			map[string]interface{}{"name": "Bob", "role": "user", "email": "bob@example.com", "age": 28},
+		},
+		"meta": map[string]interface{}{"z": 1, "y": 2, "x": 3, "w": 4, "v": 5},
+	}
+
+	render := func() string {
+		parser := NewTemplateParser(data, &ParserOption{Request: &Request{}})
+		html, err := parser.Render(source)
+		assert.Nil(t, err)
+		return html
+	}
+
+	// The repeated renderings produce the byte-identical output
+	first := render()
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, first, render())
+	}
+	assert.Contains(t, first, `"meta":{"v":5,"w":4,"x":3,"y":2,"z":1}`)
+}
+
+func TestParserComponentError(t *testing.T) {
+	Components["/test/card"] = &JitComponent{route: "/test/card", html: `<div class="card">{{ title }}</div>`, buildOption: &BuildOption{}}
+	defer delete(Components, "/test/card")
+
+	source := `<html><head></head><body><div is="/test/missing" s:jit></div><div is="/test/card" s:jit title="Card"></div><p>{{ name }}</p></body></html>`
+
+	// The failed component is replaced by the placeholder, the rest of the page is rendered
+	parser := NewTemplateParser(Data{"name": "Yao"}, &ParserOption{Request: &Request{}})
+	html, err := parser.Render(source)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `<div class="sui-component-error" data-component="/test/missing"></div>`)
+	assert.Contains(t, html, `<p>Yao</p>`)
+	assert.NotContains(t, html, "color:red")
+	assert.Len(t, parser.Errors(), 1)
+
+	// The diagnostic comment in the debug mode
+	doc, err := NewDocumentString(`<div s:cn="Broken"></div>`)
+	assert.Nil(t, err)
+	parser = NewTemplateParser(Data{}, &ParserOption{Debug: true})
+	parser.componentError(doc.Find("div"), "s:cn", "Broken", fmt.Errorf("unexpected token --"))
+	html, _ = doc.Html()
+	assert.Contains(t, html, "<!-- component Broken error: unexpected token - - -->")
+	assert.Contains(t, html, "color:red")
+}
+
+func TestParserOnce(t *testing.T) {
+	source := `<ul><li s:for="items" s:for-item="item"><b>{{ item }}</b><span s:once>first {{ item }}</span></li></ul>`
+	parser := NewTemplateParser(Data{"items": []interface{}{"a", "b"}}, &ParserOption{Fragment: true, Preview: true})
+	html, err := parser.Render(source)
+	if err != nil {
+		t.Fatalf("Render error: %v", err)
+	}
+
+	assert.Contains(t, html, "<b>b</b>")
+	assert.Equal(t, 2, strings.Count(html, "first a"))
+	assert.NotContains(t, html, "first b")
+}
+
+func TestNewForeignDocumentString(t *testing.T) {
+	page, err := NewDocumentString(`<svg viewBox="0 0 10 10"><g></g></svg>`)
+	if err != nil {
+		t.Fatalf("NewDocumentString error: %v", err)
+	}
+
+	svg := page.Find("svg").Nodes[0]
+	doc, err := NewForeignDocumentString(`<linearGradient id="g" gradientunits="userSpaceOnUse"></linearGradient>`, svg)
+	if err != nil {
+		t.Fatalf("NewForeignDocumentString error: %v", err)
+	}
+
+	node := doc.Find("body").Children().Nodes[0]
+	assert.Equal(t, "linearGradient", node.Data)
+	assert.Equal(t, "svg", node.Namespace)
+	assert.Equal(t, "viewBox", attrName(svg, "viewbox"))
+	assert.Equal(t, "viewbox", attrName(page.Find("body").Nodes[0], "viewbox"))
+}
+
+func TestParserErrors(t *testing.T) {
+	source := `<div id="main"><ul><li>a</li><li s:if="items.(">b</li></ul></div>`
+	parser := NewTemplateParser(Data{}, &ParserOption{Fragment: true, Preview: true, Route: "/index"})
+	_, err := parser.Render(source)
+	if err != nil {
+		t.Fatalf("Render error: %v", err)
+	}
+
+	errs := parser.Errors()
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, "/index", errs[0].Route)
+		assert.Equal(t, "div#main > ul > li:nth-child(2)", errs[0].Path)
+		assert.Equal(t, "s:if", errs[0].Directive)
+		assert.Equal(t, "items.(", errs[0].Expression)
+		assert.NotEmpty(t, errs[0].Message)
+	}
+}
+
+func TestParserRenderContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	parser := NewTemplateParser(Data{"items": []interface{}{"a", "b"}}, &ParserOption{Fragment: true, Preview: true})
+	html, err := parser.RenderContext(ctx, `<ul><li s:for="items">{{ item }}</li></ul>`)
+	assert.True(t, errors.Is(err, ErrPartialRender))
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.NotContains(t, html, "<li>a</li>")
+
+	parser = NewTemplateParser(Data{"items": []interface{}{"a", "b"}}, &ParserOption{Fragment: true, Preview: true})
+	html, err = parser.RenderContext(context.Background(), `<ul><li s:for="items">{{ item }}</li></ul>`)
+	assert.Nil(t, err)
+	assert.Contains(t, html, "<li>a</li>")
+}
+
+func TestMergeGlobalData(t *testing.T) {
+	raw, err := MergeGlobalData(
+		[]byte(`{"site":"Yao","contact":{"email":"hi@yaoapps.com","phone":"1"},"api":"https://api.example.com"}`),
+		[]byte(`{"contact":{"phone":"2"},"api":"http://localhost:5099"}`),
+	)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"site":"Yao","contact":{"email":"hi@yaoapps.com","phone":"2"},"api":"http://localhost:5099"}`, string(raw))
+}
+
+func TestParserPrecompile(t *testing.T) {
+	source := `<html><body><div><p>static</p><p>{{ name }}</p></div><footer>© Yao</footer></body></html>`
+	ir, err := CompileIR(source)
+	if err != nil {
+		t.Fatalf("CompileIR error: %v", err)
+	}
+	assert.Equal(t, 2, ir.Static())
+
+	for _, name := range []string{"Alice", "Bob"} {
+		parser := NewTemplateParser(Data{"name": name}, &ParserOption{Route: "/precompile", Precompile: true})
+		html, err := parser.Render(source)
+		if err != nil {
+			t.Fatalf("Render error: %v", err)
+		}
+		assert.Contains(t, html, "<p>static</p>")
+		assert.Contains(t, html, name+"</p>")
+		assert.Contains(t, html, "<footer>© Yao</footer>")
+	}
+
+	// The translated texts and the keys are the same as the parsed template
+	Locales["fr"] = map[string]*Locale{"/precompile-trans": {Keys: map[string]string{"trans_1": "Bonjour"}, version: atomic.LoadUint64(&LocaleVersion)}}
+	defer delete(Locales, "fr")
+	source = `<html><body><h1 s:trans-node="trans_1">Hello</h1><nav><a href="/">Home</a> <a href="/about">About</a></nav>` +
+		`<ul><li s:for="items" s:for-item="item">{{ item }}</li></ul><p class="note">static</p><span>{{ name }}</span></body></html>`
+	for _, items := range [][]interface{}{{"a"}, {"a", "b", "c"}} {
+		data := Data{"name": "Yao", "items": items}
+		expected, err := NewTemplateParser(data, &ParserOption{Route: "/precompile-trans", Locale: "fr"}).Render(source)
+		if err != nil {
+			t.Fatalf("Render error: %v", err)
+		}
+		parser := NewTemplateParser(data, &ParserOption{Route: "/precompile-trans", Locale: "fr", Precompile: true})
+		html, err := parser.Render(source)
+		if err != nil {
+			t.Fatalf("Render error: %v", err)
+		}
+		assert.Contains(t, html, ">Bonjour</h1>")
+		assert.Equal(t, strings.Split(expected, "var __sui_data")[0], strings.Split(html, "var __sui_data")[0])
+	}
+}
+
+func TestParserPrecompileOptions(t *testing.T) {
+	source := `<html><body><div>
+		<p>static   text</p>  <!-- note -->
+		<img src="/logo.png">
+	</div><section>
+		<span>{{ name }}</span>
+	</section></body></html>`
+	render := func(option ParserOption) (string, *TemplateParser) {
+		parser := NewTemplateParser(Data{"name": "Yao"}, &option)
+		html, err := parser.Render(source)
+		if err != nil {
+			t.Fatalf("Render error: %v", err)
+		}
+		return strings.Split(html, "var __sui_data")[0], parser
+	}
+
+	for _, option := range []ParserOption{
+		{Route: "/precompile-options"},
+		{Route: "/precompile-options", Minify: true},
+		{Route: "/precompile-options", Audit: true},
+		{Route: "/precompile-options", Minify: true, Audit: true},
+	} {
+		expected, parsed := render(option)
+		option.Precompile = true
+		html, compiled := render(option)
+		assert.Equal(t, expected, html)
+		assert.Equal(t, len(parsed.Audits()), len(compiled.Audits()))
+	}
+
+	// The static chunks are minified and audited
+	html, parser := render(ParserOption{Route: "/precompile-options", Minify: true, Audit: true, Precompile: true})
+	assert.Contains(t, html, "<p>static text</p>")
+	assert.NotContains(t, html, "note")
+	assert.NotEmpty(t, parser.Audits())
+}
+
+func TestParserPool(t *testing.T) {
+	source := `<html><body><ul><li s:for="items" s:for-item="item">{{ item }}</li></ul><p s:if="show">{{ title }}</p></body></html>`
+	render := func(parser *TemplateParser) string {
+		html, err := parser.Render(source)
+		if err != nil {
+			t.Fatalf("Render error: %v", err)
+		}
+		return strings.Split(html, "var __sui_data")[0] // the data is not ordered
+	}
+
+	for _, data := range []Data{
+		{"items": []interface{}{"a", "b", "c"}, "show": true, "title": "Hello"},
+		{"items": []interface{}{"x"}, "show": false, "title": "World"},
+	} {
+		expected := render(NewTemplateParser(data, &ParserOption{}))
+		parser := AcquireTemplateParser(data, &ParserOption{})
+		assert.Equal(t, expected, render(parser))
+		ReleaseTemplateParser(parser)
+	}
+
+	parser := AcquireTemplateParser(Data{}, &ParserOption{})
+	parser.includes = append(parser.includes, "/partial.html")
+	parser.errors = append(parser.errors, fmt.Errorf("error"))
+	parser.Reset()
+	assert.Nil(t, parser.data)
+	assert.Nil(t, parser.option)
+	assert.Empty(t, parser.includes)
+	assert.Empty(t, parser.errors)
+	assert.Empty(t, parser.replace)
+	assert.Empty(t, parser.mapping)
+}
+
+func TestParserShow(t *testing.T) {
+	source := `<div>` +
+		`<p class="on" s:show="visible" style="color: red">On</p>` +
+		`<p class="off" s:show="!visible" style="color: red; display:none">Off</p>` +
+		`<p class="hidden" s:show="!visible">Hidden</p>` +
+		`<p class="shown" s:show="visible" style="display: none">Shown</p>` +
+		`</div>`
+
+	parser := NewTemplateParser(Data{"visible": true}, &ParserOption{Fragment: true, Request: &Request{}})
+	output, err := parser.Render(source)
+	assert.Nil(t, err)
+
+	doc, err := NewDocumentFragment(output)
+	assert.Nil(t, err)
+
+	// The nodes are kept, the display:none is toggled
+	assert.Equal(t, "color: red", doc.Find(".on").AttrOr("style", ""))
+	assert.Equal(t, "color: red; display: none", doc.Find(".off").AttrOr("style", ""))
+	assert.Equal(t, "display: none", doc.Find(".hidden").AttrOr("style", ""))
+	_, has := doc.Find(".shown").Attr("style")
+	assert.False(t, has)
+	assert.Equal(t, "Hidden", doc.Find(".hidden").Text())
+}
+
+func TestParserTeleport(t *testing.T) {
+	source := `<html><head></head><body>` +
+		`<main><div class="modal" s:teleport="#modals">Modal</div>` +
+		`<div class="hidden" s:if="false" s:teleport="#modals">Hidden</div>` +
+		`<div class="missing" s:teleport="#nowhere">Missing</div></main>` +
+		`<div id="modals"></div>` +
+		`</body></html>`
+
+	parser := NewTemplateParser(Data{}, &ParserOption{Route: "/index", Request: &Request{}})
+	output, err := parser.Render(source)
+	assert.Nil(t, err)
+
+	doc, err := NewDocumentString(output)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, doc.Find("#modals > .modal").Length())
+	assert.Equal(t, 0, doc.Find("main > .modal").Length())
+	assert.NotContains(t, output, "s:teleport")
+
+	// The hidden nodes are not moved
+	assert.Equal(t, 0, doc.Find("#modals > .hidden").Length())
+	assert.NotContains(t, output, "Hidden")
+
+	// The missing target is reported, the node stays
+	assert.Equal(t, 1, doc.Find("main > .missing").Length())
+	errs := parser.Errors()
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "s:teleport", errs[0].Directive)
+	assert.Equal(t, "#nowhere", errs[0].Expression)
+}
+
+func TestAvatarDeterministic(t *testing.T) {
+	assert.Equal(t, AvatarColor("Ada Lovelace"), AvatarColor(" ada lovelace "))
+	assert.NotEqual(t, AvatarColor("Ada Lovelace"), AvatarColor("Alan Turing"))
+	assert.Regexp(t, `^#[0-9a-f]{6}$`, AvatarColor("Ada Lovelace"))
+
+	assert.Equal(t, "AL", Initials("Ada Lovelace", 2))
+	assert.Equal(t, "张", Initials("张三", 2))
+
+	avatar := Avatar("Ada Lovelace", 40, "rounded")
+	assert.Equal(t, avatar, Avatar("Ada Lovelace", 40, "rounded"))
+	assert.Contains(t, avatar, `rx="16"`)
+	assert.Contains(t, avatar, ">AL</text>")
+	assert.Contains(t, Avatar("", 40, ""), ">?</text>")
+
+	identicon := Identicon("ada@example.com", 64, "#fff")
+	assert.Equal(t, identicon, Identicon(" ADA@example.com", 64, "#fff"))
+	assert.NotEqual(t, identicon, Identicon("alan@example.com", 64, "#fff"))
+
+	// The pattern is mirrored
+	re := regexp.MustCompile(`M(\d),(\d)h1v1h-1z`)
+	cells := map[string]bool{}
+	for _, match := range re.FindAllStringSubmatch(identicon, -1) {
+		cells[match[1]+","+match[2]] = true
+	}
+	for cell := range cells {
+		x, y := int(cell[0]-'0'), cell[2:]
+		assert.True(t, cells[fmt.Sprintf("%d,%s", 6-x, y)], cell)
+	}
+}
+
+func TestParserToc(t *testing.T) {
+	source := `<html><head></head><body>` +
+		`<article s:toc="h2,h3">` +
+		`<h2>Intro</h2><h3>Setup</h3><h3>Setup</h3>` +
+		`<h2 id="custom">Usage</h2><h3>Hello, World!</h3>` +
+		`<h2>Intro</h2><p id="intro-3">Taken</p><h2>Intro</h2>` +
+		`</article>` +
+		`<nav><a s:for="$toc" s:for-item="item" href="#{{ item.id }}">{{ item.text }}:{{ len(item.children) }}</a></nav>` +
+		`</body></html>`
+
+	parser := NewTemplateParser(Data{}, &ParserOption{Route: "/docs", Request: &Request{}})
+	output, err := parser.Render(source)
+	assert.Nil(t, err)
+
+	doc, err := NewDocumentString(output)
+	assert.Nil(t, err)
+
+	// The slugs are unique, the existing ids are kept
+	ids := []string{}
+	doc.Find("article h2, article h3").Each(func(i int, sel *goquery.Selection) {
+		ids = append(ids, sel.AttrOr("id", ""))
+	})
+	assert.Equal(t, []string{"intro", "setup", "setup-2", "custom", "hello-world", "intro-2", "intro-4"}, ids)
+
+	// The h3 headings are nested in the h2 headings
+	links := []string{}
+	doc.Find("nav a").Each(func(i int, sel *goquery.Selection) {
+		links = append(links, sel.AttrOr("href", "")+" "+sel.Text())
+	})
+	assert.Equal(t, []string{"#intro Intro:2", "#custom Usage:1", "#intro-2 Intro:0", "#intro-4 Intro:0"}, links)
+
+	assert.Equal(t, "hello-world", Slugify("Hello, World!"))
+	assert.Equal(t, "section", uniqueSlug("", map[string]bool{}))
+}
+
+func TestParserAssert(t *testing.T) {
+	source := `<html><head></head><body>` +
+		`<ul s:assert="{{ len(products) <= 2 }}" s:assert-message="{{ len(products) }} products"><li s:for="products">{{ item }}</li></ul>` +
+		`<p s:if="false" s:assert="false">Hidden</p>` +
+		`<p s:assert="products[0] != nil">First</p>` +
+		`</body></html>`
+
+	// The violation is reported as the render error
+	data := Data{"products": []interface{}{"a", "b", "c"}}
+	parser := NewTemplateParser(data, &ParserOption{Route: "/shop", Request: &Request{}})
+	output, err := parser.Render(source)
+	assert.Nil(t, err)
+	assert.Contains(t, output, "<li>c</li>")
+	assert.NotContains(t, output, "s:assert")
+	errs := parser.Errors()
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "s:assert", errs[0].Directive)
+	assert.Contains(t, errs[0].Message, "3 products")
+
+	// The rendering fails in the strict mode
+	parser = NewTemplateParser(data, &ParserOption{Route: "/shop", Strict: true, Request: &Request{}})
+	_, err = parser.Render(source)
+	var assertion *AssertionError
+	assert.True(t, errors.As(err, &assertion))
+	assert.Equal(t, "3 products", assertion.Message)
+
+	parser = NewTemplateParser(Data{"products": []interface{}{"a"}}, &ParserOption{Route: "/shop", Strict: true, Request: &Request{}})
+	_, err = parser.Render(source)
+	assert.Nil(t, err)
+	assert.Empty(t, parser.Errors())
+}
@@ -0,0 +1,283 @@
+package core
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// PluralSeparator the separator of the plural forms of the keys and the messages of the locale, the forms are the
+// flat entries next to the message, the message itself is the fallback (other)
+//
+//	keys:
+//	  cart_items: "{{ count }} items"
+//	  cart_items#zero: "Your cart is empty"
+//	  cart_items#one: "{{ count }} item"
+//	messages:
+//	  "{{ count }} items#few": "{{ count }} przedmioty"
+//
+// the template selects the form by the bound count, <p s:trans s:trans-plural="cart.count">{{ cart.count }} items</p>
+const PluralSeparator = "#"
+
+// pluralRule the CLDR plural rule of the language, n is the absolute value, i the integer digits,
+// v the number of the visible fraction digits
+type pluralRule func(n float64, i int64, v int) string
+
+// pluralRules the CLDR cardinal plural rules, the key is the language of the locale
+var pluralRules = map[string]pluralRule{}
+
+func init() {
+	// one: i = 1 and v = 0
+	for _, lang := range []string{"en", "de", "nl", "sv", "da", "nb", "no", "nn", "fi", "et", "it", "es", "ca", "gl", "el", "bg", "hu", "tr", "az", "ka", "ur", "sw", "af", "sq", "eu"} {
+		pluralRules[lang] = pluralOne
+	}
+	// one: i = 0,1
+	for _, lang := range []string{"fr", "pt", "hy", "kab"} {
+		pluralRules[lang] = pluralFrench
+	}
+	// other only
+	for _, lang := range []string{"zh", "ja", "ko", "th", "vi", "id", "ms", "lo", "my", "km", "yue"} {
+		pluralRules[lang] = func(float64, int64, int) string { return "other" }
+	}
+	for _, lang := range []string{"ru", "uk", "be"} {
+		pluralRules[lang] = pluralRussian
+	}
+	for _, lang := range []string{"hr", "sr", "bs"} {
+		pluralRules[lang] = pluralCroatian
+	}
+	for _, lang := range []string{"cs", "sk"} {
+		pluralRules[lang] = pluralCzech
+	}
+	pluralRules["pl"] = pluralPolish
+	pluralRules["ar"] = pluralArabic
+	pluralRules["he"] = pluralHebrew
+	pluralRules["lt"] = pluralLithuanian
+	pluralRules["lv"] = pluralLatvian
+	pluralRules["ro"] = pluralRomanian
+}
+
+// PluralCategory the CLDR plural category of the count in the language of the locale, zero, one, two, few, many
+// or other. the languages without the rule are one for 1 and other for the rest
+func PluralCategory(locale string, count float64) string {
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		lang = lang[:i]
+	}
+	rule, has := pluralRules[lang]
+	if !has {
+		rule = pluralOne
+	}
+
+	n := math.Abs(count)
+	i := int64(n)
+	v := 0
+	if n != math.Trunc(n) {
+		s := strconv.FormatFloat(n, 'f', -1, 64)
+		v = len(s) - strings.Index(s, ".") - 1
+	}
+	return rule(n, i, v)
+}
+
+// PluralForms the candidate forms of the count, the explicit zero first, then the category and the other
+func PluralForms(locale string, count float64) []string {
+	category := PluralCategory(locale, count)
+	forms := []string{}
+	if count == 0 && category != "zero" {
+		forms = append(forms, "zero")
+	}
+	forms = append(forms, category)
+	if category != "other" {
+		forms = append(forms, "other")
+	}
+	return forms
+}
+
+// pluralForms the candidate forms of the s:trans-plural count of the node, nil if the node is not plural
+func (parser *TemplateParser) pluralForms(node *html.Node) []string {
+	if parser.locale == nil || node == nil {
+		return nil
+	}
+	stmt, has := nodeAttr(node, "s:trans-plural")
+	if !has || strings.TrimSpace(stmt) == "" {
+		return nil
+	}
+
+	value, _, err := parser.data.ExecGuard(stmt, parser.guard)
+	if err != nil {
+		return nil
+	}
+	count, ok := loopNumber(value)
+	if !ok {
+		return nil
+	}
+	return PluralForms(parser.localeName(), count)
+}
+
+// localeName the name of the locale of the rendering, e.g. zh-cn
+func (parser *TemplateParser) localeName() string {
+	if parser.locale != nil && parser.locale.Name != "" {
+		return parser.locale.Name
+	}
+	name, _ := parser.option.Locale.(string)
+	return name
+}
+
+// localeKey get the translation of the key, the plural forms first
+func (parser *TemplateParser) localeKey(key string, forms []string) (string, bool) {
+	for _, form := range forms {
+		if message, has := parser.locale.Keys[key+PluralSeparator+form]; has {
+			return message, true
+		}
+	}
+	message, has := parser.locale.Keys[key]
+	return message, has
+}
+
+// localeMessage get the translation of the message, the plural forms first
+func (parser *TemplateParser) localeMessage(message string, forms []string) (string, bool) {
+	for _, form := range forms {
+		if lcMessage, has := parser.locale.Messages[message+PluralSeparator+form]; has {
+			return lcMessage, true
+		}
+	}
+	lcMessage, has := parser.locale.Messages[message]
+	return lcMessage, has
+}
+
+func pluralOne(n float64, i int64, v int) string {
+	if i == 1 && v == 0 {
+		return "one"
+	}
+	return "other"
+}
+
+func pluralFrench(n float64, i int64, v int) string {
+	if i == 0 || i == 1 {
+		return "one"
+	}
+	if v == 0 && i != 0 && i%1000000 == 0 {
+		return "many"
+	}
+	return "other"
+}
+
+func pluralRussian(n float64, i int64, v int) string {
+	if v != 0 {
+		return "other"
+	}
+	switch {
+	case i%10 == 1 && i%100 != 11:
+		return "one"
+	case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+		return "few"
+	}
+	return "many"
+}
+
+func pluralCroatian(n float64, i int64, v int) string {
+	if v != 0 {
+		return "other"
+	}
+	switch {
+	case i%10 == 1 && i%100 != 11:
+		return "one"
+	case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+		return "few"
+	}
+	return "other"
+}
+
+func pluralCzech(n float64, i int64, v int) string {
+	switch {
+	case v != 0:
+		return "many"
+	case i == 1:
+		return "one"
+	case i >= 2 && i <= 4:
+		return "few"
+	}
+	return "other"
+}
+
+func pluralPolish(n float64, i int64, v int) string {
+	if v != 0 {
+		return "other"
+	}
+	switch {
+	case i == 1:
+		return "one"
+	case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+		return "few"
+	}
+	return "many"
+}
+
+func pluralArabic(n float64, i int64, v int) string {
+	if v != 0 {
+		return "other"
+	}
+	switch {
+	case i == 0:
+		return "zero"
+	case i == 1:
+		return "one"
+	case i == 2:
+		return "two"
+	case i%100 >= 3 && i%100 <= 10:
+		return "few"
+	case i%100 >= 11 && i%100 <= 99:
+		return "many"
+	}
+	return "other"
+}
+
+func pluralHebrew(n float64, i int64, v int) string {
+	if v != 0 {
+		return "other"
+	}
+	switch i {
+	case 1:
+		return "one"
+	case 2:
+		return "two"
+	}
+	return "other"
+}
+
+func pluralLithuanian(n float64, i int64, v int) string {
+	if v != 0 {
+		return "many"
+	}
+	switch {
+	case i%10 == 1 && (i%100 < 11 || i%100 > 19):
+		return "one"
+	case i%10 >= 2 && (i%100 < 11 || i%100 > 19):
+		return "few"
+	}
+	return "other"
+}
+
+func pluralLatvian(n float64, i int64, v int) string {
+	if v != 0 {
+		return "other"
+	}
+	switch {
+	case i%10 == 0 || (i%100 >= 11 && i%100 <= 19):
+		return "zero"
+	case i%10 == 1 && i%100 != 11:
+		return "one"
+	}
+	return "other"
+}
+
+func pluralRomanian(n float64, i int64, v int) string {
+	switch {
+	case i == 1 && v == 0:
+		return "one"
+	case v != 0 || i == 0 || (i%100 >= 2 && i%100 <= 19):
+		return "few"
+	}
+	return "other"
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluralCategory(t *testing.T) {
+	assert.Equal(t, "one", PluralCategory("en-us", 1))
+	assert.Equal(t, "other", PluralCategory("en-us", 1.5))
+	assert.Equal(t, "one", PluralCategory("fr", 0))
+	assert.Equal(t, "other", PluralCategory("zh-cn", 1))
+	assert.Equal(t, "one", PluralCategory("ru", 21))
+	assert.Equal(t, "few", PluralCategory("ru", 3))
+	assert.Equal(t, "many", PluralCategory("ru", 11))
+	assert.Equal(t, "few", PluralCategory("pl", 22))
+	assert.Equal(t, "many", PluralCategory("pl", 25))
+	assert.Equal(t, "two", PluralCategory("ar", 2))
+	assert.Equal(t, "many", PluralCategory("ar", 11))
+	assert.Equal(t, "many", PluralCategory("cs", 0.5))
+
+	assert.Equal(t, []string{"zero", "other"}, PluralForms("en", 0))
+	assert.Equal(t, []string{"one", "other"}, PluralForms("en", 1))
+	assert.Equal(t, []string{"zero", "one", "other"}, PluralForms("fr", 0))
+}
+
+func TestRenderPlural(t *testing.T) {
+	Locales["pl"] = map[string]*Locale{"/plural": {
+		Keys: map[string]string{
+			"cart":      "{{ count }} przedmiotów",
+			"cart#one":  "{{ count }} przedmiot",
+			"cart#few":  "{{ count }} przedmioty",
+			"cart#zero": "Koszyk jest pusty",
+		},
+		Messages: map[string]string{"Remove the items#one": "Usuń przedmiot", "Remove the items": "Usuń przedmioty"},
+		version:  atomic.LoadUint64(&LocaleVersion),
+	}}
+	defer delete(Locales, "pl")
+
+	source := `<html><body><p s:trans-node="cart" s:trans-plural="count">{{ count }} items</p>` +
+		`<button s:trans-plural="count" s:trans-attr-title="trans_2" title="{{ '::Remove the items' }}"></button></body></html>`
+	for count, expected := range map[int]string{0: "Koszyk jest pusty", 1: "1 przedmiot", 3: "3 przedmioty", 5: "5 przedmiotów", 22: "22 przedmioty"} {
+		parser := NewTemplateParser(Data{"count": count}, &ParserOption{Route: "/plural", Locale: "pl"})
+		html, err := parser.Render(source)
+		assert.Nil(t, err)
+		assert.Contains(t, html, ">"+expected+"</p>")
+		if count == 1 {
+			assert.Contains(t, html, `title="Usuń przedmiot"`)
+		} else {
+			assert.Contains(t, html, `title="Usuń przedmioty"`)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package core
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// MaxPooledBuffer the max capacity of the pooled scratch buffers, the larger buffers are dropped
+var MaxPooledBuffer = 64 * 1024
+
+var parserPool = sync.Pool{
+	New: func() interface{} { return &TemplateParser{} },
+}
+
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// AcquireTemplateParser get a template parser from the pool, the maps and the slices of the released parsers are reused.
+// call ReleaseTemplateParser when the rendering is done, the parser must not be used after the release
+//
+//	parser := core.AcquireTemplateParser(data, &option)
+//	defer core.ReleaseTemplateParser(parser)
+func AcquireTemplateParser(data Data, option *ParserOption) *TemplateParser {
+	parser := parserPool.Get().(*TemplateParser)
+	parser.prepare(data, option)
+	return parser
+}
+
+// ReleaseTemplateParser reset the parser and put it back to the pool
+func ReleaseTemplateParser(parser *TemplateParser) {
+	if parser == nil {
+		return
+	}
+	parser.Reset()
+	parserPool.Put(parser)
+}
+
+// Reset reset the parser state, the allocated maps and slices are kept for the next rendering
+func (parser *TemplateParser) Reset() {
+	parser.data = nil
+	parser.option = nil
+	parser.locale = nil
+	parser.context = nil
+	parser.guard = nil
+	parser.ctx = nil
+	parser.spanCtx = nil
+	parser.span = nil
+	parser.static = nil
+	parser.assertion = nil
+	parser.schedule = time.Time{}
+	parser.geo = false
+	parser.audits = nil
+	parser.violations = nil
+	parser.scopes = nil
+	parser.fragments = nil
+	parser.sequence = 0
+	parser.keyScope = ""
+	parser.catching = 0
+	if parser.nodes != nil {
+		parser.nodes.reset()
+	}
+	parser.depth = 0
+
+	for key := range parser.mapping {
+		delete(parser.mapping, key)
+	}
+	for key := range parser.onces {
+		delete(parser.onces, key)
+	}
+
+	// Clear the elements, the nodes of the previous document should not be kept alive by the pool
+	for i := range parser.replace {
+		parser.replace[i] = replacement{}
+	}
+	for i := range parser.errors {
+		parser.errors[i] = nil
+	}
+	for i := range parser.scripts {
+		parser.scripts[i] = ScriptNode{}
+	}
+	for i := range parser.styles {
+		parser.styles[i] = StyleNode{}
+	}
+	for i := range parser.tracks {
+		parser.tracks[i] = TrackEvent{}
+	}
+
+	parser.replace = parser.replace[:0]
+	parser.errors = parser.errors[:0]
+	parser.scripts = parser.scripts[:0]
+	parser.styles = parser.styles[:0]
+	parser.tracks = parser.tracks[:0]
+	parser.includes = parser.includes[:0]
+}
+
+// prepare set the data and the option of the parser, the missing maps and slices are allocated
+func (parser *TemplateParser) prepare(data Data, option *ParserOption) {
+	if option == nil {
+		option = &ParserOption{}
+	}
+
+	guard := NewDataGuard(option.Deny...)
+	if option.Restricted != nil {
+		guard = option.Restricted.restrictGuard(option.Deny)
+	}
+
+	parser.data = data.Overlay() // the data may be shared by the renderings, e.g. the cached data
+	if _, has := parser.data[processCallsKey]; !has {
+		if calls := newProcessCalls(option); calls != nil {
+			parser.data[processCallsKey] = calls // the process functions of the rendering, shared by the components
+		}
+	}
+	if _, has := parser.data[menusKey]; !has {
+		if m := newMenus(option); m != nil {
+			parser.data[menusKey] = m // the menus of the rendering, shared by the components
+		}
+	}
+	if option.Request != nil && option.Request.CSRF != "" {
+		parser.data[csrfKey] = option.Request.CSRFToken() // {{ $csrf }}, sent to the client runtime for the backend calls
+	}
+	if _, has := parser.data[nowKey]; !has {
+		parser.data[nowKey] = option.Request.Time() // {{ $now }}, shifted by the time-travel preview
+	}
+	if _, has := parser.data[deviceKey]; !has {
+		parser.data[deviceKey] = option.Request.Device().Data() // {{ $device.mobile }}
+	}
+	parser.option = option
+	parser.guard = profileGuard(guard, option.Profile, option.Route)
+	if parser.mapping == nil {
+		parser.mapping = map[string]Mapping{}
+	}
+	parser.hydrate = nil
+	if option.Hydration.partial() {
+		parser.hydrate = map[string]bool{}
+	}
+	parser.bindings = nil
+	if option.Bindings {
+		parser.bindings = map[string]Binding{}
+	}
+	if parser.onces == nil {
+		parser.onces = map[string][]*html.Node{}
+	}
+	if parser.nodes == nil {
+		parser.nodes = &nodeCounter{}
+	}
+	if parser.errors == nil {
+		parser.errors = []error{}
+	}
+	if parser.replace == nil {
+		parser.replace = []replacement{}
+	}
+	if parser.scripts == nil {
+		parser.scripts = []ScriptNode{}
+	}
+	if parser.styles == nil {
+		parser.styles = []StyleNode{}
+	}
+	if parser.tracks == nil {
+		parser.tracks = []TrackEvent{}
+	}
+}
+
+// acquireBuffer get a scratch buffer from the pool, call releaseBuffer when the content is copied
+func acquireBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// releaseBuffer reset the buffer and put it back to the pool
+func releaseBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > MaxPooledBuffer {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
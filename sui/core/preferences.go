@@ -0,0 +1,359 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/store"
+	"github.com/yaoapp/kun/log"
+)
+
+// Preferences the per-user preferences, the values are validated by the typed keys and persisted in the store.
+// set in the preferences section of the sui DSL
+//
+//	"preferences": {
+//	  "store": "preference",
+//	  "user": "$session.user_id",
+//	  "keys": {
+//	    "ui.theme": {"type": "enum", "default": "light", "options": ["light", "dark"]},
+//	    "table.*.columns": {"type": "json"},
+//	    "announcements.dismissed": {"type": "json", "default": []}
+//	  }
+//	}
+//
+// the * of the key matches one or more segments of the name, e.g. table.admin.user.columns. the templates read the
+// preferences of the visitor by {{ $pref.ui.theme }}, the processes by preferences.Get and preferences.Set
+type Preferences struct {
+	Store string                 `json:"store,omitempty"` // the store of the values, in-memory only if the store is not found
+	User  string                 `json:"user,omitempty"`  // the expression of the user of the visitor against the $session, $session.user_id by default
+	Keys  map[string]*SettingKey `json:"keys"`            // the namespaced keys, the tenant of the key is ignored
+}
+
+// PreferencesRefresh the interval of reading the values of the user from the store
+var PreferencesRefresh = 5 * time.Second
+
+// prefKey the data key of the preferences of the rendering, {{ $pref.ui.theme }}
+const prefKey = "$pref"
+
+const preferencesPrefix = "__yao.preferences"
+
+var prefKeyRe = regexp.MustCompile(`^([A-Za-z_*][A-Za-z0-9_\-]*)(\.([A-Za-z_*][A-Za-z0-9_\-]*))*$`)
+var prefNameRe = regexp.MustCompile(`^[A-Za-z0-9_\-]+(\.[A-Za-z0-9_\-]+)*$`)
+
+var preferences = &Preferences{Keys: map[string]*SettingKey{}}
+var preferencesPatterns = map[string]*regexp.Regexp{} // the keys with the *
+var preferencesMemory = map[string]string{}           // the values if the store is not found
+var preferencesCache = map[string]*preferenceCache{}
+var preferencesMutex sync.RWMutex
+
+type preferenceCache struct {
+	values map[string]interface{}
+	loaded time.Time
+}
+
+// RegisterPreferences add the keys of the preferences, the store and the user of the latest are used
+func RegisterPreferences(p *Preferences) error {
+	if p == nil {
+		return nil
+	}
+
+	patterns := map[string]*regexp.Regexp{}
+	for name, key := range p.Keys {
+		if !prefKeyRe.MatchString(name) {
+			return fmt.Errorf("the preference key %s is invalid", name)
+		}
+		if err := key.compile("preference", name); err != nil {
+			return err
+		}
+		if strings.Contains(name, "*") {
+			parts := strings.Split(name, ".")
+			for i, part := range parts {
+				if part == "*" {
+					parts[i] = `[A-Za-z0-9_\-]+(?:\.[A-Za-z0-9_\-]+)*`
+					continue
+				}
+				parts[i] = regexp.QuoteMeta(part)
+			}
+			patterns[name] = regexp.MustCompile(`^` + strings.Join(parts, `\.`) + `$`)
+		}
+	}
+
+	preferencesMutex.Lock()
+	defer preferencesMutex.Unlock()
+	keys := map[string]*SettingKey{}
+	for name, key := range preferences.Keys {
+		keys[name] = key
+	}
+	for name, key := range p.Keys {
+		keys[name] = key
+		delete(preferencesPatterns, name)
+	}
+	for name, re := range patterns {
+		preferencesPatterns[name] = re
+	}
+	preferences = &Preferences{Store: p.Store, User: p.User, Keys: keys}
+	preferencesCache = map[string]*preferenceCache{}
+	return nil
+}
+
+// PreferenceKeys get the keys of the preferences
+func PreferenceKeys() map[string]*SettingKey {
+	preferencesMutex.RLock()
+	defer preferencesMutex.RUnlock()
+	keys := make(map[string]*SettingKey, len(preferences.Keys))
+	for name, key := range preferences.Keys {
+		keys[name] = key
+	}
+	return keys
+}
+
+// GetPreference get the value of the preference of the user, the default if the user has not set it
+func GetPreference(user string, name string) (interface{}, error) {
+	key, err := preferenceKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if user == "" {
+		return key.Default, nil
+	}
+	if value, has := preferenceValues(user)[name]; has {
+		if value, err := key.check("preference", name, value); err == nil { // the stored value of the previous type is ignored
+			return value, nil
+		}
+	}
+	return key.Default, nil
+}
+
+// SetPreference validate and set the value of the preference of the user, the nil value resets it to the default
+func SetPreference(user string, name string, value interface{}) (interface{}, error) {
+	res, err := SetPreferences(user, map[string]interface{}{name: value})
+	if err != nil {
+		return nil, err
+	}
+	return res[name], nil
+}
+
+// SetPreferences set the values of the preferences of the user, the values are validated before any of them is set
+func SetPreferences(user string, values map[string]interface{}) (map[string]interface{}, error) {
+	if user == "" {
+		return nil, fmt.Errorf("the user of the preferences is required")
+	}
+
+	checked := map[string]interface{}{}
+	for name, value := range values {
+		key, err := preferenceKey(name)
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			value, err = key.check("preference", name, value)
+			if err != nil {
+				return nil, err
+			}
+		}
+		checked[name] = value
+	}
+
+	preferencesMutex.Lock()
+	stored := preferencesLoad(user)
+	for name, value := range checked {
+		if value == nil {
+			delete(stored, name)
+			continue
+		}
+		stored[name] = value
+	}
+	err := preferencesSave(user, stored)
+	delete(preferencesCache, user)
+	preferencesMutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[string]interface{}{}
+	for _, name := range sortedKeys(checked) {
+		res[name], _ = GetPreference(user, name)
+	}
+	return res, nil
+}
+
+// PreferencesData the preferences of the user, the defaults of the keys and the values of the user, the keys are
+// nested by the dots, e.g. ui.theme
+func PreferencesData(user string) map[string]interface{} {
+	values := map[string]interface{}{}
+	for name, key := range PreferenceKeys() {
+		if !strings.Contains(name, "*") && key.Default != nil {
+			values[name] = key.Default
+		}
+	}
+	if user != "" {
+		for name := range preferenceValues(user) {
+			if value, err := GetPreference(user, name); err == nil {
+				values[name] = value
+			}
+		}
+	}
+
+	return nestedData(values)
+}
+
+// PreferencesUser get the user of the session by the user expression of the preferences
+func PreferencesUser(sess map[string]interface{}) string {
+	preferencesMutex.RLock()
+	stmt := preferences.User
+	preferencesMutex.RUnlock()
+	if stmt == "" {
+		stmt = "$session.user_id"
+	}
+
+	res, _, err := Data{"$session": sess}.Exec(stmt)
+	if err != nil || res == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", res)
+}
+
+// hasPreferences check if the preferences are defined
+func hasPreferences() bool {
+	preferencesMutex.RLock()
+	defer preferencesMutex.RUnlock()
+	return len(preferences.Keys) > 0
+}
+
+// preferenceKey get the key of the name, the exact key first, then the longest key with the *
+func preferenceKey(name string) (*SettingKey, error) {
+	if !prefNameRe.MatchString(name) {
+		return nil, fmt.Errorf("the preference %s is invalid", name)
+	}
+
+	preferencesMutex.RLock()
+	defer preferencesMutex.RUnlock()
+	if key, has := preferences.Keys[name]; has && !strings.Contains(name, "*") {
+		return key, nil
+	}
+
+	match := ""
+	for pattern, re := range preferencesPatterns {
+		if re.MatchString(name) && (len(pattern) > len(match) || (len(pattern) == len(match) && pattern < match)) {
+			match = pattern
+		}
+	}
+	if match == "" {
+		return nil, fmt.Errorf("the preference %s is not defined", name)
+	}
+	return preferences.Keys[match], nil
+}
+
+// preferenceValues the stored values of the user, cached for PreferencesRefresh
+func preferenceValues(user string) map[string]interface{} {
+	preferencesMutex.RLock()
+	cache, has := preferencesCache[user]
+	preferencesMutex.RUnlock()
+	if has && time.Since(cache.loaded) < PreferencesRefresh {
+		return cache.values
+	}
+
+	preferencesMutex.Lock()
+	defer preferencesMutex.Unlock()
+	values := preferencesLoad(user)
+	preferencesCache[user] = &preferenceCache{values: values, loaded: time.Now()}
+	return values
+}
+
+// preferencesLoad read the values of the user from the store, the caller holds the lock
+func preferencesLoad(user string) map[string]interface{} {
+	id := preferencesID(user)
+	raw := ""
+	s, has := store.Pools[preferences.Store]
+	if !has {
+		raw = preferencesMemory[id]
+	} else if value, ok := s.Get(id); ok {
+		switch v := value.(type) {
+		case string:
+			raw = v
+		case []byte:
+			raw = string(v)
+		}
+	}
+
+	values := map[string]interface{}{}
+	if raw != "" {
+		if err := jsoniter.UnmarshalFromString(raw, &values); err != nil {
+			log.Error("[SUI] the preferences of the user %s are invalid: %s", user, err.Error())
+			return map[string]interface{}{}
+		}
+	}
+	return values
+}
+
+// preferencesSave write the values of the user to the store, the caller holds the lock
+func preferencesSave(user string, values map[string]interface{}) error {
+	id := preferencesID(user)
+	s, has := store.Pools[preferences.Store]
+	if !has && preferences.Store != "" {
+		log.Warn(`[SUI] The preferences store "%s" is not found, the preferences are not persisted`, preferences.Store)
+	}
+
+	if len(values) == 0 {
+		if !has {
+			delete(preferencesMemory, id)
+			return nil
+		}
+		return s.Del(id)
+	}
+
+	raw, err := jsoniter.MarshalToString(values)
+	if err != nil {
+		return err
+	}
+	if !has {
+		preferencesMemory[id] = raw
+		return nil
+	}
+	return s.Set(id, raw, 0)
+}
+
+func preferencesID(user string) string {
+	return preferencesPrefix + "@" + user
+}
+
+// preferredColumns the columns of the table widget in the order of the visitor preference table.<id>.columns,
+// the columns not in the table are ignored, the columns of the table if the visitor has no preference
+func (parser *TemplateParser) preferredColumns(id string, columns []string) []string {
+	if !hasPreferences() {
+		return columns
+	}
+	user := PreferencesUser(parser.option.Request.sessionData())
+	if user == "" {
+		return columns
+	}
+
+	value, err := GetPreference(user, "table."+id+".columns")
+	if err != nil {
+		return columns
+	}
+	list, ok := value.([]interface{})
+	if !ok || len(list) == 0 {
+		return columns
+	}
+
+	has := map[string]bool{}
+	for _, name := range columns {
+		has[name] = true
+	}
+	res := []string{}
+	for _, item := range list {
+		if name, ok := item.(string); ok && has[name] {
+			res = append(res, name)
+			has[name] = false
+		}
+	}
+	if len(res) == 0 {
+		return columns
+	}
+	return res
+}
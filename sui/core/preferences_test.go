@@ -0,0 +1,68 @@
+package core
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreferences(t *testing.T) {
+	defer func(p *Preferences, patterns map[string]*regexp.Regexp) {
+		preferences, preferencesPatterns = p, patterns
+		preferencesMemory, preferencesCache = map[string]string{}, map[string]*preferenceCache{}
+	}(preferences, preferencesPatterns)
+	preferencesPatterns = map[string]*regexp.Regexp{}
+
+	err := RegisterPreferences(&Preferences{Keys: map[string]*SettingKey{
+		"ui.theme":                {Type: "enum", Default: "light", Options: []interface{}{"light", "dark"}},
+		"table.*.columns":         {Type: "json"},
+		"announcements.dismissed": {Type: "json", Default: []interface{}{}},
+	}})
+	assert.Nil(t, err)
+
+	// The defaults of the visitor without the user
+	value, err := GetPreference("", "ui.theme")
+	assert.Nil(t, err)
+	assert.Equal(t, "light", value)
+	_, err = SetPreference("", "ui.theme", "dark")
+	assert.Contains(t, err.Error(), "the user of the preferences is required")
+
+	// The values are validated by the keys, the * matches the segments
+	value, err = SetPreference("1", "ui.theme", "dark")
+	assert.Nil(t, err)
+	assert.Equal(t, "dark", value)
+	_, err = SetPreference("1", "ui.theme", "blue")
+	assert.Contains(t, err.Error(), "the preference ui.theme should be one of [light dark]")
+	_, err = SetPreference("1", "ui.font", "serif")
+	assert.Contains(t, err.Error(), "the preference ui.font is not defined")
+	_, err = SetPreference("1", "table.admin.user.columns", []interface{}{"name", "id"})
+	assert.Nil(t, err)
+
+	// The values are validated before any of them is set
+	_, err = SetPreferences("1", map[string]interface{}{"announcements.dismissed": []interface{}{"v2"}, "ui.theme": "blue"})
+	assert.NotNil(t, err)
+	value, _ = GetPreference("1", "announcements.dismissed")
+	assert.Equal(t, []interface{}{}, value)
+
+	// The preferences of the users are separated
+	value, _ = GetPreference("2", "ui.theme")
+	assert.Equal(t, "light", value)
+
+	data := PreferencesData("1")
+	assert.Equal(t, "dark", data["ui"].(map[string]interface{})["theme"])
+	columns := data["table"].(map[string]interface{})["admin"].(map[string]interface{})["user"].(map[string]interface{})["columns"]
+	assert.Equal(t, []interface{}{"name", "id"}, columns)
+
+	// The reset falls back to the default
+	value, _ = SetPreference("1", "ui.theme", nil)
+	assert.Equal(t, "light", value)
+
+	// The column layout of the table widget
+	assert.Equal(t, "1", PreferencesUser(map[string]interface{}{"user_id": 1}))
+	parser := NewTemplateParser(Data{}, nil)
+	assert.Equal(t, []string{"id", "name", "email"}, parser.preferredColumns("admin.user", []string{"id", "name", "email"}))
+
+	err = RegisterPreferences(&Preferences{Keys: map[string]*SettingKey{"ui..theme": {Type: "string"}}})
+	assert.Contains(t, err.Error(), "the preference key ui..theme is invalid")
+}
@@ -2,16 +2,29 @@ package core
 
 import (
 	"fmt"
+	"time"
 )
 
 // PreviewRender render HTML for the preview
 func (page *Page) PreviewRender(referer string) (string, error) {
+	return page.PreviewRenderAt(referer, time.Time{})
+}
+
+// PreviewRenderAt render HTML for the preview as of the time (time-travel preview), the $now of the page and the
+// "$now" args of the data sources are the time, the current time if zero
+func (page *Page) PreviewRenderAt(referer string, now time.Time) (string, error) {
+	return page.previewRender(referer, now, nil)
+}
+
+// previewRender render HTML for the preview, the renderings are instrumented if the profile is set
+func (page *Page) previewRender(referer string, now time.Time, profile *Profile) (string, error) {
 
 	// get the page config
 	page.GetConfig()
 
 	// Render the page
 	request := NewRequestMock(page.Config.Mock)
+	request.Now = now
 	if referer != "" {
 		request.Referer = referer
 	}
@@ -24,13 +37,16 @@ func (page *Page) PreviewRender(referer string) (string, error) {
 		KeepPageTag: false,
 	})
 
-	// Get the data
-	var data Data = nil
-	if page.Codes.DATA.Code != "" || page.GlobalData != nil {
-		data, err = page.Exec(request)
-		if err != nil {
-			warnings = append(warnings, err.Error())
+	// Get the data, the fixtures are used if exist
+	data, err := page.PreviewData(request)
+	if err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	if !now.IsZero() {
+		if data == nil {
+			data = Data{}
 		}
+		data[nowKey] = now
 	}
 
 	// Add Frame Height
@@ -76,7 +92,7 @@ func (page *Page) PreviewRender(referer string) (string, error) {
 	}
 
 	// Parser and render
-	parser := NewTemplateParser(data, &ParserOption{Preview: true})
+	parser := NewTemplateParser(data, &ParserOption{Preview: true, Route: page.Route, Profile: profile})
 	html, err = parser.Render(html)
 	if err != nil {
 		return "", err
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ProfileReportFile the profiling report of the template in the public root, written by the build with the profile option
+const ProfileReportFile = ".profile.json"
+
+// ProfileTop the max components and expressions of the profiling report
+var ProfileTop = 20
+
+// Profile the instrumentation of the renderings, the time of the components (including the BeforeRender and the
+// nested components) and the expressions are collected across the routes, e.g. the preview renders of all the pages
+// with the fixture data, see Page.Profile
+type Profile struct {
+	components  map[string]*ProfileEntry
+	expressions map[string]*ProfileEntry
+	routes      map[string]bool
+	mutex       sync.Mutex
+}
+
+// ProfileEntry the time of the component or the expression
+type ProfileEntry struct {
+	Name   string        `json:"name"`
+	Calls  int           `json:"calls"`
+	Total  time.Duration `json:"total"` // nanoseconds
+	Mean   time.Duration `json:"mean"`  // nanoseconds
+	Routes []string      `json:"routes"`
+	routes map[string]bool
+}
+
+// ProfileReport the most expensive components and expressions of the profiled routes
+type ProfileReport struct {
+	Routes      []string        `json:"routes"`
+	Components  []*ProfileEntry `json:"components"`
+	Expressions []*ProfileEntry `json:"expressions"`
+}
+
+// NewProfile create the profile
+func NewProfile() *Profile {
+	return &Profile{
+		components:  map[string]*ProfileEntry{},
+		expressions: map[string]*ProfileEntry{},
+		routes:      map[string]bool{},
+	}
+}
+
+// add the time of the component or the expression of the route
+func (profile *Profile) add(entries map[string]*ProfileEntry, name string, route string, duration time.Duration) {
+	profile.mutex.Lock()
+	defer profile.mutex.Unlock()
+	entry, has := entries[name]
+	if !has {
+		entry = &ProfileEntry{Name: name, routes: map[string]bool{}}
+		entries[name] = entry
+	}
+	entry.Calls++
+	entry.Total += duration
+	entry.routes[route] = true
+	profile.routes[route] = true
+}
+
+// Report the most expensive components and expressions by the total time, top ProfileTop
+func (profile *Profile) Report() *ProfileReport {
+	profile.mutex.Lock()
+	defer profile.mutex.Unlock()
+
+	top := func(entries map[string]*ProfileEntry) []*ProfileEntry {
+		res := []*ProfileEntry{}
+		for _, entry := range entries {
+			entry.Mean = entry.Total / time.Duration(entry.Calls)
+			entry.Routes = compatKeys(entry.routes)
+			res = append(res, entry)
+		}
+		sort.Slice(res, func(i, j int) bool {
+			if res[i].Total == res[j].Total {
+				return res[i].Name < res[j].Name
+			}
+			return res[i].Total > res[j].Total
+		})
+		if ProfileTop > 0 && len(res) > ProfileTop {
+			res = res[:ProfileTop]
+		}
+		return res
+	}
+
+	return &ProfileReport{
+		Routes:      compatKeys(profile.routes),
+		Components:  top(profile.components),
+		Expressions: top(profile.expressions),
+	}
+}
+
+// profileComponent start timing the component, call the returned function when the component is rendered
+func (parser *TemplateParser) profileComponent(name string) func() {
+	profile := parser.option.Profile
+	if profile == nil {
+		return func() {}
+	}
+
+	// The route of the component
+	if route, has := parser.option.Imports[name]; has {
+		name = route
+	}
+	route := parser.option.Route
+	start := time.Now()
+	return func() { profile.add(profile.components, name, route, time.Since(start)) }
+}
+
+// profileGuard time the expressions of the rendering by the data guard
+func profileGuard(guard *DataGuard, profile *Profile, route string) *DataGuard {
+	if profile == nil {
+		return guard
+	}
+	if guard == nil {
+		guard = &DataGuard{}
+	}
+	guard.observe = func(stmt string, start time.Time) {
+		profile.add(profile.expressions, stmt, route, time.Since(start))
+	}
+	return guard
+}
+
+// Profile render the page by the mock request and the fixtures as the preview, the time of the components and
+// the expressions are added to the profile
+func (page *Page) Profile(profile *Profile) error {
+	_, err := page.previewRender("", time.Time{}, profile)
+	return err
+}
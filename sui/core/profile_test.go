@@ -0,0 +1,52 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfile(t *testing.T) {
+	source := `<html><head></head><body>` +
+		`<h1>{{ title }}</h1>` +
+		`<div s:cn="Card" prop:title="{{ title }}"><span>{{ total + 1 }}</span></div>` +
+		`<div s:cn="Card"></div>` +
+		`</body></html>`
+
+	profile := NewProfile()
+	for _, route := range []string{"/index", "/about"} {
+		option := &ParserOption{Route: route, Profile: profile, Request: &Request{}, Imports: map[string]string{"Card": "/card"}}
+		parser := NewTemplateParser(Data{"title": "Hello", "total": 1}, option)
+		_, err := parser.Render(source)
+		assert.Nil(t, err)
+	}
+
+	report := profile.Report()
+	assert.Equal(t, []string{"/about", "/index"}, report.Routes)
+
+	assert.Len(t, report.Components, 1)
+	assert.Equal(t, "/card", report.Components[0].Name)
+	assert.Equal(t, 4, report.Components[0].Calls)
+	assert.Equal(t, []string{"/about", "/index"}, report.Components[0].Routes)
+	assert.Equal(t, report.Components[0].Total/4, report.Components[0].Mean)
+
+	expressions := map[string]*ProfileEntry{}
+	for _, entry := range report.Expressions {
+		expressions[entry.Name] = entry
+	}
+	assert.Contains(t, expressions, "{{ total + 1 }}")
+	assert.Equal(t, 2, expressions["{{ total + 1 }}"].Calls)
+	for i := 1; i < len(report.Expressions); i++ {
+		assert.GreaterOrEqual(t, report.Expressions[i-1].Total, report.Expressions[i].Total)
+	}
+
+	// The report keeps the top entries
+	top := ProfileTop
+	ProfileTop = 1
+	defer func() { ProfileTop = top }()
+	assert.Len(t, profile.Report().Expressions, 1)
+
+	// The rendering without the profile is not instrumented
+	parser := NewTemplateParser(Data{"title": "Hello"}, &ParserOption{Route: "/index", Request: &Request{}})
+	assert.Nil(t, parser.guard)
+}
@@ -0,0 +1,536 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QRCode the QR code matrix (byte mode, model 2), the modules[y][x] is true for the dark module
+type QRCode struct {
+	Size    int
+	Version int
+	modules [][]bool
+	fn      [][]bool // the function modules
+}
+
+// QR code error correction levels
+const (
+	QRLevelL = iota
+	QRLevelM
+	QRLevelQ
+	QRLevelH
+)
+
+var qrFormatBits = []int{1, 0, 3, 2}
+
+var qrEccCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var qrNumErrorCorrectionBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// QRLevel get the error correction level by name, L, M (default), Q or H
+func QRLevel(name string) int {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "L":
+		return QRLevelL
+	case "Q":
+		return QRLevelQ
+	case "H":
+		return QRLevelH
+	}
+	return QRLevelM
+}
+
+// EncodeQR encode the text (UTF-8 bytes) as the QR code, the smallest version is used
+func EncodeQR(text string, level int) (*QRCode, error) {
+	if level < QRLevelL || level > QRLevelH {
+		return nil, fmt.Errorf("qrcode error correction level %d is invalid", level)
+	}
+
+	data := []byte(text)
+	version := 0
+	for v := 1; v <= 40; v++ {
+		ccbits := 8
+		if v > 9 {
+			ccbits = 16
+		}
+		if len(data) < 1<<ccbits && 4+ccbits+len(data)*8 <= qrNumDataCodewords(v, level)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("qrcode the data is too long (%d bytes)", len(data))
+	}
+
+	// The bits of the segment
+	bits := qrBits{}
+	bits.append(0x4, 4) // byte mode
+	if version > 9 {
+		bits.append(len(data), 16)
+	} else {
+		bits.append(len(data), 8)
+	}
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+
+	// The terminator and the padding
+	capacity := qrNumDataCodewords(version, level) * 8
+	terminator := capacity - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bits.append(0, terminator)
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	codewords := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			codewords[i>>3] |= 1 << (7 - uint(i&7))
+		}
+	}
+
+	qr := newQRCode(version)
+	qr.drawFunctionPatterns(level)
+	qr.drawCodewords(qrAddEccAndInterleave(codewords, version, level))
+
+	// Choose the mask with the lowest penalty
+	best, minPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		qr.applyMask(mask)
+		qr.drawFormatBits(level, mask)
+		penalty := qr.penalty()
+		if minPenalty < 0 || penalty < minPenalty {
+			best, minPenalty = mask, penalty
+		}
+		qr.applyMask(mask) // undo
+	}
+	qr.applyMask(best)
+	qr.drawFormatBits(level, best)
+	return qr, nil
+}
+
+// Dark check if the module is dark
+func (qr *QRCode) Dark(x, y int) bool {
+	return x >= 0 && y >= 0 && x < qr.Size && y < qr.Size && qr.modules[y][x]
+}
+
+// SVG render the QR code as the inline SVG, the quiet zone is 4 modules
+func (qr *QRCode) SVG(size int, color string, background string) string {
+	border := 4
+	dim := qr.Size + border*2
+	path := acquireBuffer()
+	defer releaseBuffer(path)
+	for y := 0; y < qr.Size; y++ {
+		for x := 0; x < qr.Size; x++ {
+			if qr.modules[y][x] {
+				fmt.Fprintf(path, "M%d,%dh1v1h-1z", x+border, y+border)
+			}
+		}
+	}
+	return svgImage(size, size, dim, dim, background, fmt.Sprintf(`<path d="%s" fill="%s"/>`, path.String(), svgEscape(color)))
+}
+
+// Matrix get the modules with the quiet zone
+func (qr *QRCode) Matrix() [][]bool {
+	border := 4
+	dim := qr.Size + border*2
+	matrix := make([][]bool, dim)
+	for y := range matrix {
+		matrix[y] = make([]bool, dim)
+		for x := range matrix[y] {
+			matrix[y][x] = qr.Dark(x-border, y-border)
+		}
+	}
+	return matrix
+}
+
+type qrBits []bool
+
+func (bits *qrBits) append(value int, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*bits = append(*bits, (value>>uint(i))&1 != 0)
+	}
+}
+
+func newQRCode(version int) *QRCode {
+	size := version*4 + 17
+	qr := &QRCode{Size: size, Version: version, modules: make([][]bool, size), fn: make([][]bool, size)}
+	for i := 0; i < size; i++ {
+		qr.modules[i] = make([]bool, size)
+		qr.fn[i] = make([]bool, size)
+	}
+	return qr
+}
+
+func (qr *QRCode) setFunction(x, y int, dark bool) {
+	qr.modules[y][x] = dark
+	qr.fn[y][x] = true
+}
+
+func (qr *QRCode) drawFunctionPatterns(level int) {
+	// Timing patterns
+	for i := 0; i < qr.Size; i++ {
+		qr.setFunction(6, i, i%2 == 0)
+		qr.setFunction(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns
+	qr.drawFinder(3, 3)
+	qr.drawFinder(qr.Size-4, 3)
+	qr.drawFinder(3, qr.Size-4)
+
+	// Alignment patterns
+	positions := qrAlignmentPositions(qr.Version)
+	n := len(positions)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if (i == 0 && j == 0) || (i == 0 && j == n-1) || (i == n-1 && j == 0) {
+				continue
+			}
+			qr.drawAlignment(positions[i], positions[j])
+		}
+	}
+
+	// Reserve the format bits and draw the version bits
+	qr.drawFormatBits(level, 0)
+	qr.drawVersion()
+}
+
+func (qr *QRCode) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			dist := qrMax(qrAbs(dx), qrAbs(dy))
+			xx, yy := x+dx, y+dy
+			if xx >= 0 && xx < qr.Size && yy >= 0 && yy < qr.Size {
+				qr.setFunction(xx, yy, dist != 2 && dist != 4)
+			}
+		}
+	}
+}
+
+func (qr *QRCode) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			qr.setFunction(x+dx, y+dy, qrMax(qrAbs(dx), qrAbs(dy)) != 1)
+		}
+	}
+}
+
+func (qr *QRCode) drawFormatBits(level int, mask int) {
+	data := qrFormatBits[level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+
+	// The first copy
+	for i := 0; i <= 5; i++ {
+		qr.setFunction(8, i, qrBit(bits, i))
+	}
+	qr.setFunction(8, 7, qrBit(bits, 6))
+	qr.setFunction(8, 8, qrBit(bits, 7))
+	qr.setFunction(7, 8, qrBit(bits, 8))
+	for i := 9; i < 15; i++ {
+		qr.setFunction(14-i, 8, qrBit(bits, i))
+	}
+
+	// The second copy
+	for i := 0; i < 8; i++ {
+		qr.setFunction(qr.Size-1-i, 8, qrBit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		qr.setFunction(8, qr.Size-15+i, qrBit(bits, i))
+	}
+	qr.setFunction(8, qr.Size-8, true) // the dark module
+}
+
+func (qr *QRCode) drawVersion() {
+	if qr.Version < 7 {
+		return
+	}
+
+	rem := qr.Version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := qr.Version<<12 | rem
+	for i := 0; i < 18; i++ {
+		bit := qrBit(bits, i)
+		a := qr.Size - 11 + i%3
+		b := i / 3
+		qr.setFunction(a, b, bit)
+		qr.setFunction(b, a, bit)
+	}
+}
+
+func (qr *QRCode) drawCodewords(data []byte) {
+	i := 0
+	for right := qr.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < qr.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = qr.Size - 1 - vert
+				}
+				if !qr.fn[y][x] && i < len(data)*8 {
+					qr.modules[y][x] = qrBit(int(data[i>>3]), 7-(i&7))
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (qr *QRCode) applyMask(mask int) {
+	for y := 0; y < qr.Size; y++ {
+		for x := 0; x < qr.Size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !qr.fn[y][x] {
+				qr.modules[y][x] = !qr.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty the penalty score of the mask, the lower is better
+func (qr *QRCode) penalty() int {
+	result := 0
+	size := qr.Size
+	get := func(x, y int, row bool) bool {
+		if row {
+			return qr.modules[y][x]
+		}
+		return qr.modules[x][y]
+	}
+
+	for _, row := range []bool{true, false} {
+		for y := 0; y < size; y++ {
+			// The runs of the same color
+			run := 1
+			for x := 1; x < size; x++ {
+				if get(x, y, row) == get(x-1, y, row) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					result += run - 2
+				}
+				run = 1
+			}
+			if run >= 5 {
+				result += run - 2
+			}
+
+			// The finder-like patterns 1:1:3:1:1 with 4 light modules
+			for x := 0; x+10 < size; x++ {
+				if qrFinderLike(get, x, y, row) {
+					result += 40
+				}
+			}
+		}
+	}
+
+	// The 2x2 blocks of the same color
+	dark := 0
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if qr.modules[y][x] {
+				dark++
+			}
+			if x > 0 && y > 0 {
+				c := qr.modules[y][x]
+				if c == qr.modules[y][x-1] && c == qr.modules[y-1][x] && c == qr.modules[y-1][x-1] {
+					result += 3
+				}
+			}
+		}
+	}
+
+	// The balance of the dark modules
+	total := size * size
+	k := (qrAbs(dark*20-total*10)+total-1)/total - 1
+	result += k * 10
+	return result
+}
+
+var qrFinderPatterns = [2][11]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+func qrFinderLike(get func(x, y int, row bool) bool, x, y int, row bool) bool {
+	for _, pattern := range qrFinderPatterns {
+		matched := true
+		for i, dark := range pattern {
+			if get(x+i, y, row) != dark {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func qrAlignmentPositions(version int) []int {
+	if version == 1 {
+		return []int{}
+	}
+	n := version/7 + 2
+	step := (version*8 + n*3 + 5) / (n*4 - 4) * 2
+	positions := make([]int, n)
+	positions[0] = 6
+	pos := version*4 + 17 - 7
+	for i := n - 1; i >= 1; i-- {
+		positions[i] = pos
+		pos -= step
+	}
+	return positions
+}
+
+func qrNumRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		n := version/7 + 2
+		result -= (25*n-10)*n - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+func qrNumDataCodewords(version int, level int) int {
+	return qrNumRawDataModules(version)/8 - qrEccCodewordsPerBlock[level][version]*qrNumErrorCorrectionBlocks[level][version]
+}
+
+func qrAddEccAndInterleave(data []byte, version int, level int) []byte {
+	numBlocks := qrNumErrorCorrectionBlocks[level][version]
+	eccLen := qrEccCodewordsPerBlock[level][version]
+	rawCodewords := qrNumRawDataModules(version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := qrReedSolomonDivisor(eccLen)
+	blocks := make([][]byte, 0, numBlocks)
+	k := 0
+	for i := 0; i < numBlocks; i++ {
+		size := shortBlockLen - eccLen
+		if i >= numShortBlocks {
+			size++
+		}
+		dat := append([]byte{}, data[k:k+size]...)
+		k += size
+		ecc := qrReedSolomonRemainder(dat, divisor)
+		if i < numShortBlocks {
+			dat = append(dat, 0)
+		}
+		blocks = append(blocks, append(dat, ecc...))
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-eccLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+func qrReedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := 0; j < degree; j++ {
+			result[j] = qrMultiply(result[j], root)
+			if j+1 < degree {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = qrMultiply(root, 0x02)
+	}
+	return result
+}
+
+func qrReedSolomonRemainder(data []byte, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= qrMultiply(divisor[i], factor)
+		}
+	}
+	return result
+}
+
+// qrMultiply the multiplication in GF(2^8/0x11D)
+func qrMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+	return byte(z)
+}
+
+func qrBit(x int, i int) bool {
+	return (x>>uint(i))&1 != 0
+}
+
+func qrAbs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func qrMax(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeQR(t *testing.T) {
+	// The error correction codewords of "HELLO WORLD" 1-M
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	assert.Equal(t, []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}, qrReedSolomonRemainder(data, qrReedSolomonDivisor(10)))
+	assert.Equal(t, []int{6, 34, 60, 86, 112, 138}, qrAlignmentPositions(32))
+	assert.Equal(t, 2956, qrNumDataCodewords(40, QRLevelL))
+	assert.Equal(t, 1276, qrNumDataCodewords(40, QRLevelH))
+
+	qr, err := EncodeQR("https://example.com", QRLevel("M"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, qr.Version)
+	assert.Equal(t, 25, qr.Size)
+	assert.True(t, qr.Dark(0, 0))
+	assert.False(t, qr.Dark(7, 7))
+	assert.True(t, qr.Dark(8, qr.Size-8)) // the dark module
+	assert.Contains(t, qr.SVG(160, "#000", "#fff"), `viewBox="0 0 33 33"`)
+
+	_, err = EncodeQR(string(make([]byte, 3000)), QRLevelL)
+	assert.NotNil(t, err)
+}
+
+func TestEncodeCode128(t *testing.T) {
+	barcode, err := EncodeCode128("PJJ123C")
+	assert.Nil(t, err)
+	assert.Equal(t, 9*11+13, barcode.Width())
+
+	// The digits are encoded with the code set C
+	barcode, err = EncodeCode128("1234567890")
+	assert.Nil(t, err)
+	assert.Equal(t, 7*11+13, barcode.Width())
+
+	_, err = EncodeCode128("é")
+	assert.NotNil(t, err)
+}
+
+func TestParserCodes(t *testing.T) {
+	data := Data{"url": "https://example.com", "no": "PJJ123C"}
+	source := `<div>` +
+		`<s:qrcode value="{{ url }}" size="99999" class="ticket" alt="Ticket"></s:qrcode>` +
+		`<s:barcode value="{{ no }}" width="999"></s:barcode>` +
+		`<s:qrcode value="{{ missing }}"></s:qrcode>` +
+		`</div>`
+
+	parser := NewTemplateParser(data, &ParserOption{Fragment: true, Request: &Request{}})
+	html, err := parser.Render(source)
+	assert.Nil(t, err)
+	assert.NotContains(t, html, "s:qrcode")
+	assert.Contains(t, html, `width="2048" height="2048" viewBox="0 0 33 33"`)
+	assert.Contains(t, html, `class="ticket"`)
+	assert.Contains(t, html, `aria-label="Ticket"`)
+	assert.Contains(t, html, fmt.Sprintf(`width="%d"`, (9*11+13+20)*15))
+	assert.NotContains(t, html, `width="999`)
+
+	// The missing value is reported as the render error
+	errs := parser.Errors()
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "s:qrcode", errs[0].Directive)
+}
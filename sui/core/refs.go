@@ -0,0 +1,174 @@
+package core
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/expr-lang/expr/ast"
+	"golang.org/x/net/html"
+)
+
+// TemplateReference the template expression referencing the data path
+type TemplateReference struct {
+	Route      string `json:"route,omitempty"`
+	Path       string `json:"path,omitempty"`      // the CSS-like selector of the node, see NodePath
+	Attr       string `json:"attr,omitempty"`      // the attribute of the expression, empty for the text
+	Expression string `json:"expression"`          // the original expression
+	Reference  string `json:"reference,omitempty"` // the data path read by the expression, the loop items are resolved, e.g. users.*.name
+}
+
+// refsDirectives the directives of the bare expressions (without the {{ }})
+var refsDirectives = map[string]bool{
+	"s:if":        true,
+	"s:elif":      true,
+	"s:show":      true,
+	"s:class":     true,
+	"s:style":     true,
+	"s:for":       true,
+	"s:for-where": true,
+	"s:assert":    true,
+}
+
+var refsIndexRe = regexp.MustCompile(`^(\*|\d+)$`)
+
+// FindReferences find the template expressions referencing the data path, for propagating the schema changes
+// (e.g. renaming a column) to the templates. the path matches the path itself and the nested paths,
+// the loop indexes are ignored (users.name matches users.0.name and the s:for items of users),
+// and the path starting with *. matches the field at any level, e.g. *.name
+func FindReferences(source string, path string) ([]TemplateReference, error) {
+	doc, err := NewDocumentString(source)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := []TemplateReference{}
+	for _, node := range doc.Nodes {
+		refsWalk(node, path, map[string]string{}, &refs)
+	}
+	return refs, nil
+}
+
+func refsWalk(node *html.Node, path string, aliases map[string]string, refs *[]TemplateReference) {
+	switch node.Type {
+	case html.TextNode:
+		for _, match := range dataTokens.FindAllStringSubmatch(node.Data, -1) {
+			refsMatch(node.Parent, "", match[0], match[1], path, aliases, refs)
+		}
+		return
+
+	case html.ElementNode:
+		// The loop items, the element and the children are in the scope of the loop
+		if stmt := attrValue(node, "s:for"); stmt != "" {
+			refsMatch(node, "s:for", stmt, refsStmt(stmt), path, aliases, refs)
+
+			inner := map[string]string{}
+			for k, v := range aliases {
+				inner[k] = v
+			}
+			item := attrValue(node, "s:for-item")
+			if item == "" {
+				item = "item"
+			}
+			delete(inner, item)
+			if paths := refsPaths(refsStmt(stmt), aliases); len(paths) == 1 {
+				inner[item] = paths[0] + ".*"
+			}
+			aliases = inner
+		}
+
+		for _, attr := range node.Attr {
+			if attr.Key == "s:for" {
+				continue
+			}
+			if refsDirectives[attr.Key] {
+				refsMatch(node, attr.Key, attr.Val, refsStmt(attr.Val), path, aliases, refs)
+				continue
+			}
+			for _, match := range dataTokens.FindAllStringSubmatch(attr.Val, -1) {
+				refsMatch(node, attr.Key, match[0], match[1], path, aliases, refs)
+			}
+		}
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		refsWalk(child, path, aliases, refs)
+	}
+}
+
+// refsMatch add the references of the statement matched the path
+func refsMatch(node *html.Node, attr string, expression string, stmt string, path string, aliases map[string]string, refs *[]TemplateReference) {
+	seen := map[string]bool{}
+	for _, ref := range refsPaths(stmt, aliases) {
+		if seen[ref] || !refsPathMatch(ref, path) {
+			continue
+		}
+		seen[ref] = true
+		*refs = append(*refs, TemplateReference{Path: NodePath(node), Attr: attr, Expression: expression, Reference: ref})
+	}
+}
+
+// refsPaths get the data paths read by the statement, the aliases of the loop items are resolved
+func refsPaths(stmt string, aliases map[string]string) []string {
+	program, err := Data{}.New(stmt)
+	if err != nil {
+		return nil
+	}
+
+	node := program.Node()
+	v := &guardVisitor{nodes: []ast.Node{}, bases: map[ast.Node]bool{}, calls: []string{}}
+	ast.Walk(&node, v)
+
+	paths := []string{}
+	for _, n := range v.nodes {
+		if v.bases[n] {
+			continue
+		}
+		path, ok := guardPath(n)
+		if !ok {
+			continue
+		}
+		name, rest, _ := strings.Cut(path, ".")
+		if alias, has := aliases[name]; has {
+			path = alias
+			if rest != "" {
+				path = alias + "." + rest
+			}
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// refsPathMatch check if the data path matches the path of the query
+func refsPathMatch(ref string, path string) bool {
+	any := strings.HasPrefix(path, "*.")
+	ref, path = refsNormalize(ref), refsNormalize(path)
+	if path == "" {
+		return false
+	}
+	if any {
+		return strings.Contains("."+ref+".", "."+path+".")
+	}
+	return ref == path || strings.HasPrefix(ref, path+".")
+}
+
+// refsNormalize remove the loop indexes of the path, e.g. users.0.name => users.name
+func refsNormalize(path string) string {
+	segments := []string{}
+	for _, segment := range strings.Split(strings.TrimSpace(path), ".") {
+		if segment == "" || refsIndexRe.MatchString(segment) {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	return strings.Join(segments, ".")
+}
+
+// refsStmt get the statement of the directive, the {{ }} is optional
+func refsStmt(value string) string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "{{") && strings.HasSuffix(value, "}}") {
+		value = strings.TrimSpace(value[2 : len(value)-2])
+	}
+	return value
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindReferences(t *testing.T) {
+	source := `<html><body>` +
+		`<h1 title="{{ user.name }}">{{ user.name ?? 'Guest' }} {{ user.email }}</h1>` +
+		`<ul><li s:for="{{ user.orders }}" s:for-item="order" s:if="order.paid">{{ order.name }}</li></ul>` +
+		`<p s:if="team.name != ''">{{ team.name }}</p>` +
+		`<span>{{ users[0].name }}</span>` +
+		`</body></html>`
+
+	refs, err := FindReferences(source, "user.name")
+	assert.Nil(t, err)
+	assert.Len(t, refs, 2)
+	assert.Equal(t, "title", refs[0].Attr)
+	assert.Equal(t, "", refs[1].Attr)
+	assert.Equal(t, "{{ user.name ?? 'Guest' }}", refs[1].Expression)
+
+	// The loop items are resolved
+	refs, err = FindReferences(source, "user.orders")
+	assert.Nil(t, err)
+	assert.Len(t, refs, 3)
+	assert.Equal(t, "s:for", refs[0].Attr)
+	assert.Equal(t, "user.orders.*.paid", refs[1].Reference)
+	assert.Equal(t, "user.orders.*.name", refs[2].Reference)
+
+	// The field at any level
+	refs, err = FindReferences(source, "*.name")
+	assert.Nil(t, err)
+	assert.Len(t, refs, 6)
+
+	// The indexes are ignored
+	refs, err = FindReferences(source, "users.name")
+	assert.Nil(t, err)
+	assert.Len(t, refs, 1)
+	assert.Equal(t, "users.0.name", refs[0].Reference)
+}
@@ -0,0 +1,218 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yaoapp/kun/log"
+	"golang.org/x/net/html"
+)
+
+// ComponentRegistry the registry of the compiled just-in-time components, e.g. the remote artifact store or another
+// yao instance, the teams share the component library across the projects without building the copies
+type ComponentRegistry interface {
+	// Fetch get the compiled component (the .jit file) of the route, e.g. /shared/button
+	Fetch(route string) ([]byte, error)
+}
+
+// RegistryOption the http component registry, set in the registries section of the sui DSL, the key is the
+// route prefix of the components
+//
+//	"registries": {
+//	  "/shared": {"url": "https://ui.example.com/web{route}.jit", "assets": "https://ui.example.com", "ttl": 600}
+//	}
+type RegistryOption struct {
+	URL     string            `json:"url"`               // the url of the compiled components, the {route} is replaced with the route
+	Assets  string            `json:"assets,omitempty"`  // the origin of the scripts and the styles of the components
+	Headers map[string]string `json:"headers,omitempty"` // the request headers, the $VAR are replaced with the environment variables
+	TTL     int               `json:"ttl,omitempty"`     // the seconds the fetched components are fresh, DefaultRegistryTTL by default
+}
+
+// DefaultRegistryTTL the default freshness of the components fetched from the http registry
+var DefaultRegistryTTL = 5 * time.Minute
+
+// HTTPRegistry the component registry over http, the fetched components are cached and revalidated by the ETag
+// when they are stale. the stale components are served if the registry is unavailable
+type HTTPRegistry struct {
+	URL     string
+	Assets  string
+	Headers map[string]string
+	TTL     time.Duration
+	client  *http.Client
+	cache   map[string]*registryEntry
+	mutex   sync.Mutex
+}
+
+type registryEntry struct {
+	source []byte
+	etag   string
+	expire time.Time
+}
+
+var componentRegistries = map[string]ComponentRegistry{}
+var registryMutex sync.RWMutex
+
+// RegisterComponentRegistry set the registry of the components of the route prefix, the just-in-time components
+// of the prefix are fetched from the registry. nil to remove the registry
+func RegisterComponentRegistry(prefix string, registry ComponentRegistry) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	prefix = "/" + strings.Trim(prefix, "/")
+	if registry == nil {
+		delete(componentRegistries, prefix)
+		return
+	}
+	componentRegistries[prefix] = registry
+}
+
+// componentRegistry get the registry of the component route, the longest prefix wins, nil if the component is local
+func componentRegistry(route string) ComponentRegistry {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	var res ComponentRegistry = nil
+	matched := -1
+	for prefix, registry := range componentRegistries {
+		if len(prefix) <= matched {
+			continue
+		}
+		if route == prefix || strings.HasPrefix(route, strings.TrimSuffix(prefix, "/")+"/") {
+			res, matched = registry, len(prefix)
+		}
+	}
+	return res
+}
+
+// NewHTTPRegistry create the http component registry of the option
+func NewHTTPRegistry(option *RegistryOption) (*HTTPRegistry, error) {
+	if option == nil || option.URL == "" {
+		return nil, fmt.Errorf("the url of the registry is required")
+	}
+
+	u, err := url.Parse(option.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("the url of the registry %s should be the http or https url", option.URL)
+	}
+
+	ttl := DefaultRegistryTTL
+	if option.TTL > 0 {
+		ttl = time.Duration(option.TTL) * time.Second
+	}
+
+	headers := map[string]string{}
+	for name, value := range option.Headers {
+		headers[name] = os.ExpandEnv(value)
+	}
+
+	return &HTTPRegistry{
+		URL:     option.URL,
+		Assets:  strings.TrimSuffix(option.Assets, "/"),
+		Headers: headers,
+		TTL:     ttl,
+		client:  &http.Client{Timeout: RemoteTimeout},
+		cache:   map[string]*registryEntry{},
+	}, nil
+}
+
+// Fetch get the compiled component of the route, the fresh component is served from the cache
+func (registry *HTTPRegistry) Fetch(route string) ([]byte, error) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	entry := registry.cache[route]
+	if entry != nil && time.Now().Before(entry.expire) {
+		return entry.source, nil
+	}
+
+	source, etag, err := registry.get(route, entry)
+	if err != nil {
+		if entry != nil {
+			log.Warn("[SUI] registry %s: %s, the stale component is served", route, err.Error())
+			entry.expire = time.Now().Add(registry.TTL)
+			return entry.source, nil
+		}
+		return nil, err
+	}
+
+	if source == nil { // not modified
+		entry.expire = time.Now().Add(registry.TTL)
+		return entry.source, nil
+	}
+
+	registry.cache[route] = &registryEntry{source: source, etag: etag, expire: time.Now().Add(registry.TTL)}
+	return source, nil
+}
+
+// get request the component, the source is nil if the cached one is not modified
+func (registry *HTTPRegistry) get(route string, entry *registryEntry) ([]byte, string, error) {
+	target := strings.ReplaceAll(registry.URL, "{route}", route)
+	if !strings.Contains(registry.URL, "{route}") {
+		target = strings.TrimSuffix(registry.URL, "/") + route + ".jit"
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	for name, value := range registry.Headers {
+		req.Header.Set(name, value)
+	}
+	if entry != nil && entry.etag != "" {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+
+	res, err := registry.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotModified && entry != nil:
+		return nil, entry.etag, nil
+	case res.StatusCode != http.StatusOK:
+		return nil, "", fmt.Errorf("the registry responds %s", res.Status)
+	}
+
+	source, err := io.ReadAll(io.LimitReader(res.Body, MaxRemoteSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(source)) > MaxRemoteSize {
+		return nil, "", fmt.Errorf("the component is larger than %d bytes", MaxRemoteSize)
+	}
+	return source, res.Header.Get("ETag"), nil
+}
+
+// AssetOrigin the origin of the scripts and the styles of the components
+func (registry *HTTPRegistry) AssetOrigin() string {
+	return registry.Assets
+}
+
+// registryAssets point the local scripts and styles of the component to the origin of the registry
+func registryAssets(registry ComponentRegistry, scripts []ScriptNode, styles []StyleNode) {
+	assets, ok := registry.(interface{ AssetOrigin() string })
+	if !ok || assets.AssetOrigin() == "" {
+		return
+	}
+
+	origin := assets.AssetOrigin()
+	rewrite := func(attrs []html.Attribute) {
+		for i, attr := range attrs {
+			if (attr.Key == "src" || attr.Key == "href") && strings.HasPrefix(attr.Val, "/") && !strings.HasPrefix(attr.Val, "//") {
+				attrs[i].Val = origin + attr.Val
+			}
+		}
+	}
+	for _, script := range scripts {
+		rewrite(script.Attrs)
+	}
+	for _, style := range styles {
+		rewrite(style.Attrs)
+	}
+}
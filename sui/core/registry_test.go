@@ -0,0 +1,96 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/html"
+)
+
+func TestHTTPRegistry(t *testing.T) {
+	requests := 0
+	down := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if down {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		if r.URL.Path != "/web/shared/button.jit" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`<button class="btn"></button>`))
+	}))
+	defer server.Close()
+
+	t.Setenv("REGISTRY_TOKEN", "secret")
+	registry, err := NewHTTPRegistry(&RegistryOption{
+		URL:     server.URL + "/web{route}.jit",
+		Assets:  "https://ui.example.com/",
+		Headers: map[string]string{"Authorization": "Bearer $REGISTRY_TOKEN"},
+	})
+	assert.Nil(t, err)
+
+	source, err := registry.Fetch("/shared/button")
+	assert.Nil(t, err)
+	assert.Equal(t, `<button class="btn"></button>`, string(source))
+
+	// The fresh component is served from the cache
+	registry.Fetch("/shared/button")
+	assert.Equal(t, 1, requests)
+
+	// The stale component is revalidated by the ETag
+	registry.cache["/shared/button"].expire = time.Now().Add(-time.Second)
+	source, err = registry.Fetch("/shared/button")
+	assert.Nil(t, err)
+	assert.Equal(t, `<button class="btn"></button>`, string(source))
+	assert.Equal(t, 2, requests)
+
+	// The stale component is served if the registry is unavailable
+	down = true
+	registry.cache["/shared/button"].expire = time.Now().Add(-time.Second)
+	source, err = registry.Fetch("/shared/button")
+	assert.Nil(t, err)
+	assert.Equal(t, `<button class="btn"></button>`, string(source))
+
+	_, err = registry.Fetch("/shared/card")
+	assert.Contains(t, err.Error(), "502")
+
+	// The assets of the registry
+	scripts := []ScriptNode{{Attrs: []html.Attribute{{Key: "src", Val: "/web/assets/button.js"}}}, {Source: "console.log(1)"}}
+	styles := []StyleNode{{Attrs: []html.Attribute{{Key: "href", Val: "//cdn.example.com/button.css"}}}}
+	registryAssets(registry, scripts, styles)
+	assert.Equal(t, "https://ui.example.com/web/assets/button.js", scripts[0].Attrs[0].Val)
+	assert.Equal(t, "//cdn.example.com/button.css", styles[0].Attrs[0].Val)
+
+	_, err = NewHTTPRegistry(&RegistryOption{URL: "file:///etc/passwd"})
+	assert.Contains(t, err.Error(), "should be the http or https url")
+}
+
+func TestComponentRegistry(t *testing.T) {
+	shared, _ := NewHTTPRegistry(&RegistryOption{URL: "https://ui.example.com"})
+	forms, _ := NewHTTPRegistry(&RegistryOption{URL: "https://forms.example.com"})
+	RegisterComponentRegistry("/shared", shared)
+	RegisterComponentRegistry("/shared/forms/", forms)
+	defer RegisterComponentRegistry("/shared", nil)
+	defer RegisterComponentRegistry("/shared/forms", nil)
+
+	assert.Equal(t, shared, componentRegistry("/shared/button"))
+	assert.Equal(t, forms, componentRegistry("/shared/forms/input"))
+	assert.Nil(t, componentRegistry("/sharedx/button"))
+	assert.Nil(t, componentRegistry("/local/button"))
+
+	ctx := NewBuildContext(nil)
+	assert.True(t, ctx.isJitComponent("/shared/button"))
+	assert.False(t, ctx.isJitComponent("/local/button"))
+}
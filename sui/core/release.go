@@ -0,0 +1,264 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yaoapp/gou/application"
+	"github.com/yaoapp/kun/log"
+)
+
+// Release the published version of the built pages, the public root of the sui is copied into
+// /.sui/releases/<sui>/<version> and the active version is served instead of the public root,
+// so the next build never serves a half-built mixture of the old and the new pages and assets.
+//
+//	yao run sui.release.publish web template   // build, snapshot and activate
+//	yao run sui.release.rollback web           // activate the previous version
+type Release struct {
+	Version string    `json:"version"`
+	Created time.Time `json:"created"`
+	Active  bool      `json:"active"`
+}
+
+// ReleasesRoot the root of the releases in the application
+var ReleasesRoot = filepath.Join(string(os.PathSeparator), ".sui", "releases")
+
+var releaseVersionRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.\-]*$`)
+var releases = map[string]string{} // the public root and the active release dir
+var releaseMutex sync.RWMutex
+
+// RegisterRelease set the active release dir of the public root, remove it if the dir is empty
+func RegisterRelease(root string, dir string) {
+	releaseMutex.Lock()
+	defer releaseMutex.Unlock()
+	root = "/" + strings.Trim(root, "/")
+	if dir == "" {
+		delete(releases, root)
+		return
+	}
+	releases[root] = dir
+}
+
+// ReleaseFile get the file of the active release, e.g. /public/web/index.sui => /.sui/releases/web/v2/index.sui
+// the file is returned as it is if the public root has no active release or the release has no such file
+func ReleaseFile(file string) string {
+	releaseMutex.RLock()
+	defer releaseMutex.RUnlock()
+	if len(releases) == 0 {
+		return file
+	}
+
+	route := filepath.ToSlash(filepath.Join(string(os.PathSeparator), file))
+	if !strings.HasPrefix(route, "/public/") {
+		return file
+	}
+	route = strings.TrimPrefix(route, "/public")
+
+	dir := ""
+	matched := -1
+	for root, d := range releases {
+		if len(root) <= matched {
+			continue
+		}
+		if root == "/" || route == root || strings.HasPrefix(route, root+"/") {
+			dir, matched = d, len(root)
+		}
+	}
+	if matched < 0 {
+		return file
+	}
+
+	rest := route
+	if matched > 1 {
+		rest = route[matched:]
+	}
+	released := filepath.Join(dir, filepath.FromSlash(rest))
+	if exists, _ := application.App.Exists(released); !exists {
+		return file
+	}
+	return released
+}
+
+// LoadRelease register the active release of the sui, read from the active file of the releases
+func LoadRelease(id string, root string) error {
+	version, err := activeRelease(id)
+	if err != nil || version == "" {
+		return err
+	}
+	RegisterRelease(root, filepath.Join(ReleasesRoot, id, version))
+	return nil
+}
+
+// Releases get the releases of the sui, the latest first
+func Releases(id string) ([]*Release, error) {
+	dir := filepath.Join(application.App.Root(), ReleasesRoot, id)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Release{}, nil
+		}
+		return nil, err
+	}
+
+	active, err := activeRelease(id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*Release{}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue // the active file and the unfinished snapshots
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, &Release{Version: entry.Name(), Created: info.ModTime(), Active: entry.Name() == active})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Created.Equal(res[j].Created) {
+			return res[i].Version > res[j].Version
+		}
+		return res[i].Created.After(res[j].Created)
+	})
+	return res, nil
+}
+
+// SnapshotRelease copy the built public root of the sui into the release of the version. the version is the
+// timestamp if it is empty. the release is copied into a temporary dir first, and renamed when it is complete
+func SnapshotRelease(id string, root string, version string) (*Release, error) {
+	if strings.ContainsAny(root, "{$") {
+		return nil, fmt.Errorf("release: the public root %s is dynamic, the releases need a static public root", root)
+	}
+	if version == "" {
+		version = time.Now().Format("20060102150405")
+	}
+	if !releaseVersionRe.MatchString(version) {
+		return nil, fmt.Errorf("release: the version %s should be letters, digits, _, - and .", version)
+	}
+
+	appRoot := application.App.Root()
+	source := filepath.Join(appRoot, "public", root)
+	target := filepath.Join(appRoot, ReleasesRoot, id, version)
+	if _, err := os.Stat(target); err == nil {
+		return nil, fmt.Errorf("release: the version %s of %s exists", version, id)
+	}
+
+	temp := filepath.Join(appRoot, ReleasesRoot, id, fmt.Sprintf(".%s.%d", version, time.Now().UnixNano()))
+	if err := copyRelease(source, temp); err != nil {
+		os.RemoveAll(temp)
+		return nil, fmt.Errorf("release: %s", err.Error())
+	}
+	if err := os.Rename(temp, target); err != nil {
+		os.RemoveAll(temp)
+		return nil, fmt.Errorf("release: %s", err.Error())
+	}
+	return &Release{Version: version, Created: time.Now()}, nil
+}
+
+// ActivateRelease switch the public root of the sui to the release of the version, the active file
+// is replaced atomically and the caches of the pages, the components and the locales are cleaned
+func ActivateRelease(id string, root string, version string) error {
+	if !releaseVersionRe.MatchString(version) {
+		return fmt.Errorf("release: the version %s should be letters, digits, _, - and .", version)
+	}
+
+	dir := filepath.Join(ReleasesRoot, id, version)
+	if info, err := os.Stat(filepath.Join(application.App.Root(), dir)); err != nil || !info.IsDir() {
+		return fmt.Errorf("release: the version %s of %s is not found", version, id)
+	}
+
+	active := filepath.Join(application.App.Root(), ReleasesRoot, id, ".active")
+	temp := fmt.Sprintf("%s.%d", active, time.Now().UnixNano())
+	if err := os.WriteFile(temp, []byte(version), 0644); err != nil {
+		return fmt.Errorf("release: %s", err.Error())
+	}
+	if err := os.Rename(temp, active); err != nil {
+		os.Remove(temp)
+		return fmt.Errorf("release: %s", err.Error())
+	}
+
+	RegisterRelease(root, dir)
+	CleanCache()
+	chComp <- &componentData{"", nil, cleanComponent}
+	ReloadLocales()
+	log.Info("[SUI] The release %s of %s is activated", version, id)
+	return nil
+}
+
+// RollbackRelease activate the release before the active one, returns the activated release
+func RollbackRelease(id string, root string) (*Release, error) {
+	list, err := Releases(id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, release := range list {
+		if !release.Active {
+			continue
+		}
+		if i+1 >= len(list) {
+			return nil, fmt.Errorf("release: %s has no release before %s", id, release.Version)
+		}
+		previous := list[i+1]
+		if err := ActivateRelease(id, root, previous.Version); err != nil {
+			return nil, err
+		}
+		previous.Active = true
+		return previous, nil
+	}
+	return nil, fmt.Errorf("release: %s has no active release", id)
+}
+
+func activeRelease(id string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(application.App.Root(), ReleasesRoot, id, ".active"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func copyRelease(source string, target string) error {
+	return filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(target, rel)
+		if info.IsDir() {
+			return os.MkdirAll(dest, 0755)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		dst, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(dst, src); err != nil {
+			dst.Close()
+			return err
+		}
+		return dst.Close()
+	})
+}
@@ -0,0 +1,84 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/gou/application"
+)
+
+func TestRelease(t *testing.T) {
+	prepare(t)
+	defer clean()
+
+	id := fmt.Sprintf("__release_test_%d", time.Now().UnixNano())
+	root := "/" + id
+	public := filepath.Join(application.App.Root(), "public", id)
+	defer os.RemoveAll(public)
+	defer os.RemoveAll(filepath.Join(application.App.Root(), ReleasesRoot, id))
+	defer RegisterRelease(root, "")
+
+	write := func(name string, content string) {
+		file := filepath.Join(public, name)
+		assert.Nil(t, os.MkdirAll(filepath.Dir(file), 0755))
+		assert.Nil(t, os.WriteFile(file, []byte(content), 0644))
+	}
+	read := func(file string) string {
+		data, err := application.App.Read(file)
+		assert.Nil(t, err)
+		return string(data)
+	}
+
+	// The first release
+	write("index.sui", "v1")
+	write("assets/app.js", "v1")
+	v1, err := SnapshotRelease(id, root, "v1")
+	assert.Nil(t, err)
+	assert.Nil(t, ActivateRelease(id, root, v1.Version))
+	assert.Equal(t, "v1", read(ReleaseFile(filepath.Join("/public", id, "index.sui"))))
+
+	// The next build does not change the active release
+	write("index.sui", "v2")
+	write("assets/app.js", "v2")
+	write("new.sui", "v2")
+	assert.Equal(t, "v1", read(ReleaseFile(filepath.Join("/public", id, "index.sui"))))
+	assert.Equal(t, "v1", read(ReleaseFile(filepath.Join("/public", id, "assets", "app.js"))))
+	assert.Equal(t, filepath.Join("/public", id, "new.sui"), ReleaseFile(filepath.Join("/public", id, "new.sui")))
+	assert.Equal(t, "/public/other/index.sui", ReleaseFile("/public/other/index.sui"))
+
+	time.Sleep(10 * time.Millisecond)
+	v2, err := SnapshotRelease(id, root, "v2")
+	assert.Nil(t, err)
+	assert.Nil(t, ActivateRelease(id, root, v2.Version))
+	assert.Equal(t, "v2", read(ReleaseFile(filepath.Join("/public", id, "assets", "app.js"))))
+
+	_, err = SnapshotRelease(id, root, "v2")
+	assert.Error(t, err)
+	assert.Error(t, ActivateRelease(id, root, "v3"))
+	assert.Error(t, ActivateRelease(id, root, "../v1"))
+
+	releases, err := Releases(id)
+	assert.Nil(t, err)
+	if assert.Len(t, releases, 2) {
+		assert.Equal(t, "v2", releases[0].Version)
+		assert.True(t, releases[0].Active)
+		assert.False(t, releases[1].Active)
+	}
+
+	// Rollback
+	release, err := RollbackRelease(id, root)
+	assert.Nil(t, err)
+	assert.Equal(t, "v1", release.Version)
+	assert.Equal(t, "v1", read(ReleaseFile(filepath.Join("/public", id, "index.sui"))))
+	_, err = RollbackRelease(id, root)
+	assert.Error(t, err)
+
+	// Load the active release
+	RegisterRelease(root, "")
+	assert.Nil(t, LoadRelease(id, root))
+	assert.Equal(t, "v1", read(ReleaseFile(filepath.Join("/public", id, "index.sui"))))
+}
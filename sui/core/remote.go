@@ -0,0 +1,264 @@
+package core
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RemoteComponents the remote component source of the template, the teams share the components across the projects
+// without vendoring the copies. The sources are declared in the template.json, and used as is="/@acme/button"
+//
+//	"remotes": [
+//	  {"name": "acme", "git": "https://github.com/acme/ui.git", "version": "v1.2.0", "checksum": "9f86d0..."},
+//	  {"name": "kit", "url": "https://registry.example.com/kit/{version}.tar.gz", "version": "2.0.1", "checksum": "2c26b4..."}
+//	]
+type RemoteComponents struct {
+	Name     string `json:"name"`
+	URL      string `json:"url,omitempty"`      // the registry archive (tar.gz), the {version} is replaced with the version
+	Git      string `json:"git,omitempty"`      // the git repository, the version is the tag or the branch
+	Version  string `json:"version"`            // the pinned version
+	Checksum string `json:"checksum,omitempty"` // the sha256 of the files, see RemoteChecksum. required by the registry archives
+}
+
+// RemotePrefix the route prefix of the remote components, e.g. /@acme/button
+const RemotePrefix = "/@"
+
+// RemoteRoot the directory of the fetched remote components in the template, skipped by the page walker
+const RemoteRoot = "__remotes"
+
+// RemoteTimeout the timeout of fetching the remote components
+var RemoteTimeout = 60 * time.Second
+
+// MaxRemoteSize the max size of the registry archive
+var MaxRemoteSize int64 = 64 << 20
+
+// remoteGet download the registry archive
+var remoteGet = func(url string) (*http.Response, error) {
+	client := &http.Client{Timeout: RemoteTimeout}
+	return client.Get(url)
+}
+
+var remoteNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+var remoteVerified = map[string]string{} // the fetched directories and their verified checksums
+var remoteMutex sync.Mutex
+
+// Dir get the directory of the pinned version, e.g. acme@v1.2.0
+func (remote *RemoteComponents) Dir() string {
+	return fmt.Sprintf("%s@%s", remote.Name, remote.Version)
+}
+
+// Validate check the source
+func (remote *RemoteComponents) Validate() error {
+	if !remoteNameRe.MatchString(remote.Name) {
+		return fmt.Errorf("remote components: the name %q is invalid", remote.Name)
+	}
+	if !remoteNameRe.MatchString(remote.Version) {
+		return fmt.Errorf("remote components %s: the version %q is invalid, pin the version", remote.Name, remote.Version)
+	}
+	if (remote.URL == "") == (remote.Git == "") {
+		return fmt.Errorf("remote components %s: set either the url or the git repository", remote.Name)
+	}
+	if remote.URL != "" && remote.Checksum == "" {
+		return fmt.Errorf("remote components %s: the checksum of the registry archive is required", remote.Name)
+	}
+	return nil
+}
+
+// RemoteRoute get the local route of the remote component, e.g. /@acme/button => /__remotes/acme@v1.2.0/button
+// the route is returned as it is if it is not a remote component
+func RemoteRoute(remotes []*RemoteComponents, route string) (string, *RemoteComponents, error) {
+	if !strings.HasPrefix(route, RemotePrefix) {
+		return route, nil, nil
+	}
+
+	name, rest, _ := strings.Cut(strings.TrimPrefix(route, RemotePrefix), "/")
+	for _, remote := range remotes {
+		if remote != nil && remote.Name == name {
+			return "/" + RemoteRoot + "/" + remote.Dir() + "/" + rest, remote, nil
+		}
+	}
+	return route, nil, fmt.Errorf("remote components %s is not declared in the template", name)
+}
+
+// Fetch fetch the pinned version to the root (the template directory) and verify the checksum.
+// the fetched version is cached, it is fetched again if the files are changed
+func (remote *RemoteComponents) Fetch(root string) (string, error) {
+	if err := remote.Validate(); err != nil {
+		return "", err
+	}
+
+	remoteMutex.Lock()
+	defer remoteMutex.Unlock()
+
+	dir := filepath.Join(root, RemoteRoot, remote.Dir())
+	if sum, has := remoteVerified[dir]; has && sum == remote.Checksum {
+		return dir, nil
+	}
+
+	// The cached version
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		if err := remote.verify(dir); err == nil {
+			remoteVerified[dir] = remote.Checksum
+			return dir, nil
+		}
+		os.RemoveAll(dir)
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, RemoteRoot), 0755); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.MkdirTemp(filepath.Join(root, RemoteRoot), ".fetch-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	if remote.Git != "" {
+		err = remote.fetchGit(tmp)
+	} else {
+		err = remote.fetchArchive(tmp)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := remote.verify(tmp); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmp, dir); err != nil {
+		return "", err
+	}
+	remoteVerified[dir] = remote.Checksum
+	return dir, nil
+}
+
+// verify check the checksum of the files, the git sources without the checksum are not verified
+func (remote *RemoteComponents) verify(dir string) error {
+	if remote.Checksum == "" {
+		return nil
+	}
+
+	sum, err := RemoteChecksum(dir)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(sum, remote.Checksum) {
+		return fmt.Errorf("remote components %s: the checksum mismatch, expected %s, got %s", remote.Dir(), remote.Checksum, sum)
+	}
+	return nil
+}
+
+func (remote *RemoteComponents) fetchGit(dir string) error {
+	cmd := exec.Command("git", "clone", "--quiet", "--depth", "1", "--branch", remote.Version, "--", remote.Git, dir)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("remote components %s: git clone %s error: %s %s", remote.Dir(), remote.Git, err.Error(), strings.TrimSpace(string(output)))
+	}
+	return os.RemoveAll(filepath.Join(dir, ".git"))
+}
+
+func (remote *RemoteComponents) fetchArchive(dir string) error {
+	url := strings.ReplaceAll(remote.URL, "{version}", remote.Version)
+	res, err := remoteGet(url)
+	if err != nil {
+		return fmt.Errorf("remote components %s: %s", remote.Dir(), err.Error())
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("remote components %s: %s %s", remote.Dir(), url, res.Status)
+	}
+
+	gz, err := gzip.NewReader(io.LimitReader(res.Body, MaxRemoteSize))
+	if err != nil {
+		return fmt.Errorf("remote components %s: %s", remote.Dir(), err.Error())
+	}
+	defer gz.Close()
+
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("remote components %s: %s", remote.Dir(), err.Error())
+		}
+
+		// Never escape the directory
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(os.PathSeparator)) {
+			return fmt.Errorf("remote components %s: the file %s is not allowed", remote.Dir(), header.Name)
+		}
+		target := filepath.Join(dir, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(file, reader)
+			file.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// RemoteChecksum the sha256 of the files in the directory, the paths and the contents are hashed in order
+func RemoteChecksum(dir string) (string, error) {
+	files := []string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if info.Mode().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, file := range files {
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return "", err
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		h.Write([]byte(filepath.ToSlash(rel) + "\x00" + DependencyHash(content) + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func remoteArchive(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		assert.Nil(t, err)
+		_, err = tw.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, tw.Close())
+	assert.Nil(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestRemoteRoute(t *testing.T) {
+	remotes := []*RemoteComponents{{Name: "acme", Git: "https://example.com/acme/ui.git", Version: "v1.2.0"}}
+
+	route, remote, err := RemoteRoute(remotes, "/@acme/button")
+	assert.Nil(t, err)
+	assert.Equal(t, "/__remotes/acme@v1.2.0/button", route)
+	assert.Equal(t, "acme", remote.Name)
+
+	route, remote, err = RemoteRoute(remotes, "/index")
+	assert.Nil(t, err)
+	assert.Equal(t, "/index", route)
+	assert.Nil(t, remote)
+
+	_, _, err = RemoteRoute(remotes, "/@unknown/button")
+	assert.NotNil(t, err)
+
+	// The version is pinned, the registry archive is verified
+	assert.NotNil(t, (&RemoteComponents{Name: "acme", Git: "https://example.com/acme/ui.git"}).Validate())
+	assert.NotNil(t, (&RemoteComponents{Name: "acme", URL: "https://example.com/{version}.tar.gz", Version: "1.0.0"}).Validate())
+	assert.NotNil(t, (&RemoteComponents{Name: "../acme", Git: "https://example.com/acme/ui.git", Version: "1.0.0"}).Validate())
+}
+
+func TestRemoteFetch(t *testing.T) {
+	archives := map[string][]byte{
+		"/kit/1.0.0.tar.gz": remoteArchive(t, map[string]string{"button/button.html": `<button class="kit">{{ label }}</button>`}),
+		"/kit/6.6.6.tar.gz": remoteArchive(t, map[string]string{"../escape.html": `<p></p>`}),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, has := archives[r.URL.Path]
+		if !has {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(raw)
+	}))
+	defer server.Close()
+
+	// The checksum of the expected files
+	expected := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(expected, "button"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(expected, "button", "button.html"), []byte(`<button class="kit">{{ label }}</button>`), 0644))
+	checksum, err := RemoteChecksum(expected)
+	assert.Nil(t, err)
+
+	root := t.TempDir()
+	remote := &RemoteComponents{Name: "kit", URL: server.URL + "/kit/{version}.tar.gz", Version: "1.0.0", Checksum: checksum}
+	dir, err := remote.Fetch(root)
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(root, "__remotes", "kit@1.0.0"), dir)
+	content, err := os.ReadFile(filepath.Join(dir, "button", "button.html"))
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), `class="kit"`)
+
+	// The cached version
+	dir, err = remote.Fetch(root)
+	assert.Nil(t, err)
+	assert.DirExists(t, dir)
+
+	// The checksum mismatch
+	root = t.TempDir()
+	_, err = (&RemoteComponents{Name: "kit", URL: remote.URL, Version: "1.0.0", Checksum: "0000"}).Fetch(root)
+	assert.NotNil(t, err)
+	assert.NoDirExists(t, filepath.Join(root, "__remotes", "kit@1.0.0"))
+
+	// The files can not escape the directory
+	_, err = (&RemoteComponents{Name: "kit", URL: remote.URL, Version: "6.6.6", Checksum: checksum}).Fetch(root)
+	assert.NotNil(t, err)
+	assert.NoFileExists(t, filepath.Join(root, "__remotes", "escape.html"))
+
+	// The missing version
+	_, err = (&RemoteComponents{Name: "kit", URL: remote.URL, Version: "9.9.9", Checksum: checksum}).Fetch(root)
+	assert.NotNil(t, err)
+}
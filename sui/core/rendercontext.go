@@ -0,0 +1,80 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrPartialRender the rendering is aborted by the context, the output is partial
+var ErrPartialRender = errors.New("partial render")
+
+// PartialRenderError the error of the aborted rendering, unwraps to the error of the context
+// e.g. errors.Is(err, ErrPartialRender), errors.Is(err, context.DeadlineExceeded)
+type PartialRenderError struct {
+	Err error
+}
+
+// Error the error message
+func (e *PartialRenderError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrPartialRender.Error(), e.Err.Error())
+}
+
+// Unwrap get the error of the context
+func (e *PartialRenderError) Unwrap() error {
+	return e.Err
+}
+
+// Is check if the error is the ErrPartialRender
+func (e *PartialRenderError) Is(target error) bool {
+	return target == ErrPartialRender
+}
+
+// RenderContext render the template with the context, the walk, the loops, the JIT components and the
+// backend script calls are aborted when the context is cancelled or times out, the partial output is returned
+// with the PartialRenderError
+func (parser *TemplateParser) RenderContext(ctx context.Context, html string) (string, error) {
+	if ctx == nil {
+		return parser.Render(html)
+	}
+
+	parser.ctx = ctx
+	defer func() { parser.ctx = nil }()
+
+	res, err := parser.Render(html)
+	if err != nil {
+		return res, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return res, &PartialRenderError{Err: err}
+	}
+	return res, nil
+}
+
+// cancelled check if the context of the rendering is cancelled
+func (parser *TemplateParser) cancelled() bool {
+	return parser.ctx != nil && parser.ctx.Err() != nil
+}
+
+// callContext call the function (e.g. the backend script), returns the error of the context if it's done first.
+// the V8 script calls can not be interrupted, the goroutine of the call keeps running until the script returns,
+// its result is discarded, the fn must not write the state shared with the rendering after the context is done
+func (parser *TemplateParser) callContext(fn func() error) error {
+	if parser.ctx == nil {
+		return fn()
+	}
+
+	if err := parser.ctx.Err(); err != nil {
+		return &PartialRenderError{Err: err}
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- fn() }()
+	select {
+	case err := <-done:
+		return err
+	case <-parser.ctx.Done():
+		return &PartialRenderError{Err: parser.ctx.Err()}
+	}
+}
@@ -0,0 +1,169 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// RenderError the structured error of the rendering, the tooling can show the precise diagnostics
+type RenderError struct {
+	Route      string `json:"route,omitempty"`      // the route of the template
+	Path       string `json:"path,omitempty"`       // the CSS-like selector of the node, e.g. body > div#main > ul:nth-child(2) > li
+	Directive  string `json:"directive,omitempty"`  // the directive, e.g. s:if, s:for, s:html, s:include
+	Expression string `json:"expression,omitempty"` // the original expression
+	Message    string `json:"message"`
+	err        error
+}
+
+// Error the error message
+func (e *RenderError) Error() string {
+	var b strings.Builder
+	if e.Route != "" {
+		b.WriteString(e.Route)
+		b.WriteString(" ")
+	}
+	if e.Path != "" {
+		b.WriteString(e.Path)
+		b.WriteString(" ")
+	}
+	if e.Directive != "" {
+		b.WriteString(e.Directive)
+		if e.Expression != "" {
+			fmt.Fprintf(&b, "=%q", e.Expression)
+		}
+		b.WriteString(" ")
+	}
+	if b.Len() == 0 {
+		return e.Message
+	}
+	return b.String() + "error: " + e.Message
+}
+
+// Unwrap get the original error
+func (e *RenderError) Unwrap() error {
+	return e.err
+}
+
+// Errors get the errors of the rendering, the errors without the location only have the route and the message
+func (parser *TemplateParser) Errors() []RenderError {
+	res := make([]RenderError, 0, len(parser.errors))
+	for _, err := range parser.errors {
+		var renderErr *RenderError
+		if errors.As(err, &renderErr) {
+			res = append(res, *renderErr)
+			continue
+		}
+
+		route := ""
+		if parser.option != nil {
+			route = parser.option.Route
+		}
+		res = append(res, RenderError{Route: route, Message: err.Error(), err: err})
+	}
+	return res
+}
+
+// renderError record the error with the location of the node
+func (parser *TemplateParser) renderError(node *html.Node, directive string, expression string, err error) {
+	route := ""
+	if parser.option != nil {
+		route = parser.option.Route
+	}
+	parser.errors = append(parser.errors, &RenderError{
+		Route:      route,
+		Path:       NodePath(node),
+		Directive:  directive,
+		Expression: expression,
+		Message:    err.Error(),
+		err:        err,
+	})
+	parser.spanError(err)
+}
+
+// NodePath get the CSS-like selector of the node, the id is used if the node or the ancestor has it,
+// e.g. div#main > ul:nth-child(2) > li:nth-child(3)
+func NodePath(node *html.Node) string {
+	parts := []string{}
+	for n := node; n != nil && n.Type == html.ElementNode; n = n.Parent {
+		part := n.Data
+		if id := attrValue(n, "id"); id != "" && !strings.ContainsAny(id, "{}") {
+			parts = append(parts, part+"#"+id)
+			break
+		}
+
+		if n.Parent != nil && n.Parent.Type == html.ElementNode {
+			index, siblings := 0, 0
+			for sibling := n.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+				if !isElementNode(sibling) {
+					continue
+				}
+				siblings++
+				if sibling == n {
+					index = siblings
+				}
+			}
+			if siblings > 1 {
+				part = fmt.Sprintf("%s:nth-child(%d)", part, index)
+			}
+		}
+		parts = append(parts, part)
+	}
+
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, " > ")
+}
+
+func attrValue(node *html.Node, name string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == name {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// errorMessage get the message of the error without the location, e.g. the message shown by the s:catch boundary
+func errorMessage(err error) string {
+	var renderErr *RenderError
+	if errors.As(err, &renderErr) {
+		return renderErr.Message
+	}
+	return err.Error()
+}
+
+// componentError isolate the failed component, the rest of the page keeps rendering. The component is replaced by
+// the placeholder <div class="sui-component-error" data-component="...">, the error is shown in the debug mode only,
+// as the diagnostic comment and the error box
+func (parser *TemplateParser) componentError(sel *goquery.Selection, directive string, value string, err error) {
+	parser.renderError(sel.Nodes[0], directive, value, err)
+
+	node := sel.Nodes[0]
+	if parser.debug() {
+		setError(sel, err)
+		comment := strings.ReplaceAll(fmt.Sprintf(" component %s error: %s ", value, errorMessage(err)), "--", "- -")
+		node.InsertBefore(&html.Node{Type: html.CommentNode, Data: comment}, node.FirstChild)
+		return
+	}
+
+	sel.Empty()
+	node.Type, node.Data, node.DataAtom = html.ElementNode, "div", atom.Div
+	node.Attr = []html.Attribute{{Key: "class", Val: "sui-component-error"}, {Key: "data-component", Val: value}}
+	parser.parsed(sel)
+}
+
+// renderComponent render the component by the component parser, the panic of the component is returned as the error
+func renderComponent(compParser *TemplateParser, sel *goquery.Selection) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return compParser.RenderSelection(sel)
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"sync/atomic"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// replacement the node replaced by the rendered nodes after the parsing (the loops and the includes)
+type replacement struct {
+	sel   *goquery.Selection
+	nodes []*html.Node
+}
+
+// ReplaceMetric the metrics of the replacement phase
+type ReplaceMetric struct {
+	Applied   int64 `json:"applied"`   // the replacements applied
+	Discarded int64 `json:"discarded"` // the replacements released without applying (errors, s:catch)
+	Detached  int64 `json:"detached"`  // the replacements skipped, the node is detached from the document
+}
+
+var replaceApplied, replaceDiscarded, replaceDetached int64
+
+// ReplaceMetrics get the metrics of the replacement phase
+func ReplaceMetrics() ReplaceMetric {
+	return ReplaceMetric{
+		Applied:   atomic.LoadInt64(&replaceApplied),
+		Discarded: atomic.LoadInt64(&replaceDiscarded),
+		Detached:  atomic.LoadInt64(&replaceDetached),
+	}
+}
+
+// addReplace replace the node with the nodes after the parsing, the replacements are applied in order
+func (parser *TemplateParser) addReplace(sel *goquery.Selection, nodes []*html.Node) {
+	parser.replace = append(parser.replace, replacement{sel: sel, nodes: nodes})
+}
+
+// applyReplace apply the replacements in order and release them
+func (parser *TemplateParser) applyReplace() {
+	parser.applyReplaceFrom(0)
+}
+
+// applyReplaceFrom apply the replacements from the offset in order and release them (e.g. the replacements of a subtree)
+func (parser *TemplateParser) applyReplaceFrom(offset int) {
+	if offset >= len(parser.replace) {
+		return
+	}
+	for i := offset; i < len(parser.replace); i++ {
+		r := parser.replace[i]
+		parser.replace[i] = replacement{}
+		if len(r.sel.Nodes) == 0 || r.sel.Nodes[0].Parent == nil {
+			atomic.AddInt64(&replaceDetached, 1)
+			continue
+		}
+		r.sel.ReplaceWithNodes(r.nodes...)
+		atomic.AddInt64(&replaceApplied, 1)
+	}
+	parser.replace = parser.replace[:offset]
+}
+
+// releaseReplace release the pending replacements from the offset, the node trees are not retained
+// Call it with defer, the pending replacements are released when the rendering exits early
+func (parser *TemplateParser) releaseReplace(offset int) {
+	if offset >= len(parser.replace) {
+		return
+	}
+	for i := offset; i < len(parser.replace); i++ {
+		parser.replace[i] = replacement{}
+	}
+	atomic.AddInt64(&replaceDiscarded, int64(len(parser.replace)-offset))
+	parser.replace = parser.replace[:offset]
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"strings"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/yaoapp/gou/process"
@@ -65,22 +66,40 @@ func (r *Request) NewData() Data {
 	cookies := r.Cookies()
 	theme := GetTheme(cookies)
 	locale := GetLocale(cookies)
+	if r.Localized != nil {
+		locale = r.Localized.Locale
+	}
 	r.Theme = theme
 	r.Locale = locale
 
 	data := Data{}
 	data["$payload"] = r.Payload
 	data["$query"] = r.Query
-	data["$param"] = r.Params
+	data["$param"] = r.ParamValues()
 	data["$cookie"] = cookies
 	data["$url"] = r.URL
 	data["$theme"] = r.Theme
 	data["$locale"] = r.Locale
 	data["$timezone"] = GetSystemTimezone()
 	data["$direction"] = "ltr"
+	if r.Localized != nil {
+		data["$locales"] = r.Localized.data()
+	}
 	return data
 }
 
+// ParamValues get the typed params of the route, the params are returned as they are if the route is not typed
+func (r *Request) ParamValues() map[string]interface{} {
+	if r.Values != nil {
+		return r.Values
+	}
+	values := map[string]interface{}{}
+	for name, value := range r.Params {
+		values[name] = value
+	}
+	return values
+}
+
 // GetLocale get the locale
 func GetLocale(cookies map[string]string) interface{} {
 	if lang, has := cookies["locale"]; has {
@@ -97,10 +116,13 @@ func GetTheme(cookies map[string]string) interface{} {
 	return nil
 }
 
-// Hash get the hash
+// Hash get the hash of the request, the cache key of the page data and the page. only the inputs of the data and
+// the rendering are hashed, the per-connection fields (the remote address, the CSRF seed, the time) are left out
 func (r *Request) Hash() string {
 	h := fnv.New64a()
-	h.Write([]byte(fmt.Sprintf("%v", r)))
+	fmt.Fprintf(h, "%s|%v|%v|%v|%v", r.Method, r.URL, r.Query, r.Params, r.Payload)
+	fmt.Fprintf(h, "|%v|%v|%s|%s", r.Theme, r.Locale, r.Country, r.Device())
+	fmt.Fprintf(h, "|%s|%v", r.Sid, r.Cookies()) // the data of the visitor, e.g. the $session and the $cookie
 	return fmt.Sprintf("%x", h.Sum64())
 }
 
@@ -203,7 +225,7 @@ func (r *Request) execValue(value interface{}) (interface{}, error) {
 
 		if strings.HasPrefix(v, "$param.") {
 			key := strings.TrimLeft(v, "$param.")
-			if value, has := r.Params[key]; has {
+			if value, has := r.ParamValues()[key]; has {
 				return value, nil
 			}
 			return "", nil
@@ -318,13 +340,15 @@ func (r *Request) call(p interface{}) (interface{}, error) {
 func (r *Request) parseArgs(args []interface{}) ([]interface{}, error) {
 
 	data := any.MapOf(map[string]interface{}{
-		"param":   r.Params,
+		"param":   r.ParamValues(),
 		"query":   r.Query,
 		"payload": map[string]interface{}{},
 		"header":  r.Headers,
 		"theme":   r.Theme,
 		"locale":  r.Locale,
 		"url":     r.URL.Map(),
+		"now":     r.Time().Format(time.RFC3339), // the date-filtered data sources evaluate as of the preview time
+		"device":  r.Device().Data(),
 	}).Dot()
 
 	for i, arg := range args {
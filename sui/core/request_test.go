@@ -0,0 +1,39 @@
+package core
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestHash(t *testing.T) {
+	newRequest := func() *Request {
+		return &Request{
+			Method:  "GET",
+			URL:     ReqeustURL{Path: "/shop"},
+			Query:   url.Values{"page": {"2"}},
+			Headers: url.Values{"User-Agent": {"Mozilla/5.0"}, "Cookie": {"locale=fr"}},
+			Locale:  "fr",
+		}
+	}
+
+	// The per-connection fields are not hashed
+	r1, r2 := newRequest(), newRequest()
+	r1.Remote, r2.Remote = "10.0.0.1:51234", "10.0.0.1:51999"
+	r1.CSRF, r2.CSRF = "a", "b"
+	r2.Now = time.Now()
+	assert.Equal(t, r1.Hash(), r2.Hash())
+
+	// The inputs of the rendering are hashed
+	r2 = newRequest()
+	r2.Query = url.Values{"page": {"3"}}
+	assert.NotEqual(t, r1.Hash(), r2.Hash())
+	r2 = newRequest()
+	r2.Locale = "en"
+	assert.NotEqual(t, r1.Hash(), r2.Hash())
+	r2 = newRequest()
+	r2.Headers.Set("User-Agent", "Mozilla/5.0 (iPhone)")
+	assert.NotEqual(t, r1.Hash(), r2.Hash())
+}
@@ -0,0 +1,357 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// RestrictedProfile the rendering profile of the user-supplied templates (e.g. the pages edited by the customers of a SaaS product)
+// Only the allowed directives and components are rendered, the expressions run in the sandbox, the resources are limited,
+// and the asset urls are isolated in the namespace.
+type RestrictedProfile struct {
+	Directives   []string `json:"directives,omitempty"`   // the allowed directives, DefaultRestrictedDirectives if empty
+	Components   []string `json:"components,omitempty"`   // the allowed components (the name or the route), none if empty
+	Functions    []string `json:"functions,omitempty"`    // the allowed expression functions, DefaultRestrictedFunctions if empty
+	Deny         []string `json:"deny,omitempty"`         // the data paths can not be read, DefaultRestrictedDeny is always denied
+	MaxNodes     int      `json:"maxNodes,omitempty"`     // the max elements to render, DefaultRestrictedMaxNodes if 0
+	MaxLoopItems int      `json:"maxLoopItems,omitempty"` // the max items of a s:for loop, DefaultRestrictedMaxLoopItems if 0
+	AssetRoot    string   `json:"assetRoot,omitempty"`    // the asset root of the template, e.g. /assets
+	Namespace    string   `json:"namespace,omitempty"`    // the asset namespace, e.g. the tenant id
+}
+
+// DefaultRestrictedDirectives the directives allowed in the restricted profile by default
+var DefaultRestrictedDirectives = []string{"s:if", "s:elif", "s:else", "s:for", "s:set", "s:bind", "s:catch", "s:html", "s:show", "s:class"}
+
+// DefaultRestrictedFunctions the expression functions allowed in the restricted profile by default (P_ is not allowed)
+var DefaultRestrictedFunctions = []string{"True", "False", "Empty", "Truncate", "TruncateWords", "Ellipsis", "Decimal", "LocaleURL"}
+
+// DefaultRestrictedDeny the data paths always denied in the restricted profile
+var DefaultRestrictedDeny = []string{"$cookie", "$global", "$session"}
+
+// DefaultRestrictedMaxNodes the max elements to render in the restricted profile
+var DefaultRestrictedMaxNodes = 5000
+
+// DefaultRestrictedMaxLoopItems the max items of a s:for loop in the restricted profile
+var DefaultRestrictedMaxLoopItems = 500
+
+// restrictedDirectives the directives checked by the restricted profile, the attributes generated by the build are not checked
+var restrictedDirectives = map[string]bool{
+	"s:if":           true,
+	"s:elif":         true,
+	"s:else":         true,
+	"s:show":         true,
+	"s:publish-at":   true,
+	"s:unpublish-at": true,
+	"s:geo":          true,
+	"s:class":        true,
+	"s:style":        true,
+	"s:props":        true,
+	"s:model":        true,
+	"s:for":          true,
+	"s:set":          true,
+	"s:bind":         true,
+	"s:raw":          true,
+	"s:html":         true,
+	"s:include":      true,
+	"s:cache":        true,
+	"s:catch":        true,
+	"s:form":         true,
+	"s:track":        true,
+	"s:teleport":     true,
+}
+
+// restrictedDrop the elements of the scripts and the embedded documents, removed with the contents
+var restrictedDrop = map[string]bool{
+	"script":   true,
+	"iframe":   true,
+	"frame":    true,
+	"frameset": true,
+	"object":   true,
+	"embed":    true,
+	"applet":   true,
+	"base":     true,
+}
+
+// restrictedURLAttrs the attributes of the urls, only the protocols of the DefaultSanitizePolicy are allowed
+var restrictedURLAttrs = map[string]bool{
+	"href":       true,
+	"src":        true,
+	"action":     true,
+	"formaction": true,
+	"xlink:href": true,
+	"poster":     true,
+	"data":       true,
+	"cite":       true,
+	"background": true,
+}
+
+// restrictedAssetAttrs the attributes of the asset urls
+var restrictedAssetAttrs = map[string]bool{
+	"src":    true,
+	"href":   true,
+	"poster": true,
+	"srcset": true,
+	"action": true,
+}
+
+var restrictedRoutes = map[string]*RestrictedProfile{}
+var restrictedMutex sync.RWMutex
+
+// RegisterRestrictedRoute impose the restricted profile on the pages of the route prefix, e.g. /tenants
+// the profiles are set by the host (the restricted section of the sui DSL), not by the page config
+func RegisterRestrictedRoute(prefix string, profile *RestrictedProfile) {
+	restrictedMutex.Lock()
+	defer restrictedMutex.Unlock()
+	prefix = "/" + strings.Trim(prefix, "/")
+	if profile == nil {
+		delete(restrictedRoutes, prefix)
+		return
+	}
+	restrictedRoutes[prefix] = profile
+}
+
+// GetRestrictedProfile get the restricted profile of the route, the longest prefix wins, nil if the route is not restricted
+func GetRestrictedProfile(route string) *RestrictedProfile {
+	restrictedMutex.RLock()
+	defer restrictedMutex.RUnlock()
+	var res *RestrictedProfile = nil
+	matched := -1
+	for prefix, profile := range restrictedRoutes {
+		if len(prefix) <= matched {
+			continue
+		}
+		if prefix == "/" || route == prefix || strings.HasPrefix(route, prefix+"/") {
+			res, matched = profile, len(prefix)
+		}
+	}
+	return res
+}
+
+// restrictGuard get the data guard of the restricted profile
+func (profile *RestrictedProfile) restrictGuard(deny []string) *DataGuard {
+	guard := NewDataGuard(append(append(append([]string{}, deny...), DefaultRestrictedDeny...), profile.Deny...)...)
+	if guard == nil {
+		guard = &DataGuard{}
+	}
+	guard.Sandbox = true
+	guard.Functions = profile.Functions
+	if len(guard.Functions) == 0 {
+		guard.Functions = DefaultRestrictedFunctions
+	}
+
+	// The registered filters are allowed
+	guard.Functions = append(append([]string{}, guard.Functions...), "__filter", "__filter_locale")
+	return guard
+}
+
+func (profile *RestrictedProfile) allowDirective(name string) bool {
+	directives := profile.Directives
+	if len(directives) == 0 {
+		directives = DefaultRestrictedDirectives
+	}
+	for _, directive := range directives {
+		if directive == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (profile *RestrictedProfile) allowComponent(names ...string) bool {
+	for _, component := range profile.Components {
+		for _, name := range names {
+			if name != "" && strings.Trim(component, "/") == strings.Trim(name, "/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (profile *RestrictedProfile) maxNodes() int {
+	if profile.MaxNodes > 0 {
+		return profile.MaxNodes
+	}
+	return DefaultRestrictedMaxNodes
+}
+
+func (profile *RestrictedProfile) maxLoopItems() int {
+	if profile.MaxLoopItems > 0 {
+		return profile.MaxLoopItems
+	}
+	return DefaultRestrictedMaxLoopItems
+}
+
+// directiveName get the directive of the attribute, e.g. s:for-item => s:for, s:cache-ttl => s:cache
+func directiveName(key string) string {
+	if !strings.HasPrefix(key, "s:") {
+		return ""
+	}
+	if restrictedDirectives[key] {
+		return key
+	}
+	if idx := strings.Index(key, "-"); idx > 0 && restrictedDirectives[key[:idx]] {
+		return key[:idx]
+	}
+	return ""
+}
+
+// restrictNode apply the restricted profile to the node, return false if the node should not be rendered
+func (parser *TemplateParser) restrictNode(sel *goquery.Selection) bool {
+	profile := parser.option.Restricted
+	if profile == nil {
+		return true
+	}
+
+	node := sel.Nodes[0]
+	if n := parser.nodes.load(); n > profile.maxNodes() {
+		if n == profile.maxNodes()+1 {
+			parser.renderError(node, "restricted", "", fmt.Errorf("restricted: the template has more than %d elements", profile.maxNodes()))
+		}
+		parser.restrictRemove(sel)
+		return false
+	}
+
+	// The scripts and the embedded documents, e.g. <script>, <iframe>, <meta http-equiv="refresh">
+	if restrictedDrop[node.Data] || (node.Data == "meta" && hasAttr(node, "http-equiv")) {
+		parser.renderError(node, "restricted", "", fmt.Errorf("restricted: the element %s is not allowed", node.Data))
+		parser.restrictRemove(sel)
+		return false
+	}
+
+	// The directive elements
+	name := node.Data
+	if name == "set" {
+		name = "s:set"
+	}
+	if directive := directiveName(name); directive != "" && !profile.allowDirective(directive) {
+		parser.renderError(node, directive, "", fmt.Errorf("restricted: the directive %s is not allowed", directive))
+		parser.restrictRemove(sel)
+		return false
+	}
+
+	// The components
+	if parser.isElementComponent(sel) || parser.isJitComponent(sel) {
+		com := sel.AttrOr("s:cn", "")
+		route := ""
+		if parser.option.Imports != nil {
+			route = parser.option.Imports[com]
+		}
+		if !profile.allowComponent(com, route, sel.AttrOr("is", "")) {
+			parser.renderError(node, "s:cn", com, fmt.Errorf("restricted: the component %s is not allowed", sel.AttrOr("is", com)))
+			parser.restrictRemove(sel)
+			return false
+		}
+	}
+
+	// The directive attributes and the event handlers
+	attrs := []html.Attribute{}
+	for _, attr := range node.Attr {
+		if directive := directiveName(attr.Key); directive != "" && !profile.allowDirective(directive) {
+			parser.renderError(node, directive, attr.Val, fmt.Errorf("restricted: the directive %s is not allowed", directive))
+			continue
+		}
+		key := strings.ToLower(attr.Key)
+		if strings.HasPrefix(key, "on") || key == "srcdoc" {
+			parser.renderError(node, "restricted", attr.Key, fmt.Errorf("restricted: the attribute %s is not allowed", attr.Key))
+			continue
+		}
+		attrs = append(attrs, attr)
+	}
+	node.Attr = attrs
+	return true
+}
+
+// restrictRemove hide the node and drop the children and the attributes
+func (parser *TemplateParser) restrictRemove(sel *goquery.Selection) {
+	sel.Empty()
+	sel.Nodes[0].Attr = nil
+	parser.parsed(sel)
+	parser.hide(sel)
+}
+
+// restrictLoop limit the items of the s:for loop
+func (parser *TemplateParser) restrictLoop(node *html.Node, items []interface{}, keys []string) ([]interface{}, []string) {
+	profile := parser.option.Restricted
+	if profile == nil || len(items) <= profile.maxLoopItems() {
+		return items, keys
+	}
+
+	max := profile.maxLoopItems()
+	parser.renderError(node, "s:for", "", fmt.Errorf("restricted: the loop has more than %d items", max))
+	if keys != nil {
+		keys = keys[:max]
+	}
+	return items[:max], keys
+}
+
+// restrictAssets remove the urls of the scripts (e.g. javascript:) and isolate the asset urls in the namespace,
+// e.g. /assets/logo.png => /assets/tenant-1/logo.png. the urls are checked after the bindings are rendered
+func (parser *TemplateParser) restrictAssets(sel *goquery.Selection) {
+	profile := parser.option.Restricted
+	if profile == nil {
+		return
+	}
+
+	node := sel.Nodes[0]
+	attrs := node.Attr[:0]
+	for _, attr := range node.Attr {
+		if restrictedURLAttrs[strings.ToLower(attr.Key)] && !DefaultSanitizePolicy.allowURL(attr.Val) {
+			parser.renderError(node, "restricted", attr.Key, fmt.Errorf("restricted: the url of %s is not allowed", attr.Key))
+			continue
+		}
+		attrs = append(attrs, attr)
+	}
+	node.Attr = attrs
+
+	if profile.Namespace == "" {
+		return
+	}
+
+	roots := []string{"@assets"}
+	if root := strings.TrimRight(profile.AssetRoot, "/"); root != "" {
+		roots = append(roots, root)
+	}
+
+	for i, attr := range node.Attr {
+		if !restrictedAssetAttrs[attr.Key] {
+			continue
+		}
+		node.Attr[i].Val = restrictAssetURL(attr.Val, roots, profile.Namespace)
+	}
+}
+
+// restrictAssetURL add the namespace to the asset urls, the srcset may contain several urls
+func restrictAssetURL(value string, roots []string, namespace string) string {
+	namespace = strings.Trim(namespace, "/")
+	parts := strings.Split(value, ",")
+	for i, part := range parts {
+		url := strings.TrimSpace(part)
+		for _, root := range roots {
+			if !strings.HasPrefix(url, root+"/") {
+				continue
+			}
+
+			path := strings.TrimPrefix(url, root+"/")
+			if strings.HasPrefix(path, namespace+"/") {
+				path = strings.TrimPrefix(path, namespace+"/")
+			}
+
+			// Never escape the namespace
+			segments := []string{}
+			for _, segment := range strings.Split(path, "/") {
+				if segment == ".." || segment == "." {
+					continue
+				}
+				segments = append(segments, segment)
+			}
+			parts[i] = strings.Replace(part, url, fmt.Sprintf("%s/%s/%s", root, namespace, strings.Join(segments, "/")), 1)
+			break
+		}
+	}
+	return strings.Join(parts, ",")
+}
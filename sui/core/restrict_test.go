@@ -0,0 +1,77 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/html"
+)
+
+func TestRestrictedProfile(t *testing.T) {
+	data := Data{
+		"items":    []interface{}{1, 2, 3, 4},
+		"$session": map[string]interface{}{"token": "secret"},
+	}
+	profile := &RestrictedProfile{MaxLoopItems: 2, Namespace: "tenant-1", AssetRoot: "/assets"}
+	guard := profile.restrictGuard(nil)
+
+	_, _, err := data.ExecGuard(`P_("utils.now")`, guard)
+	assert.NotNil(t, err)
+
+	_, _, err = data.ExecGuard(`$session.token`, guard)
+	assert.NotNil(t, err)
+
+	res, _, err := data.ExecGuard(`Empty(items)`, guard)
+	assert.Nil(t, err)
+	assert.Equal(t, false, res)
+
+	assert.True(t, profile.allowDirective("s:for"))
+	assert.False(t, profile.allowDirective("s:raw"))
+	assert.Equal(t, "s:for", directiveName("s:for-item"))
+	assert.Equal(t, "", directiveName("s:cn"))
+
+	parser := NewTemplateParser(data, &ParserOption{Restricted: profile})
+	items, _ := parser.restrictLoop(&html.Node{Type: html.ElementNode, Data: "li"}, data["items"].([]interface{}), nil)
+	assert.Len(t, items, 2)
+	assert.Len(t, parser.errors, 1)
+
+	roots := []string{"@assets", "/assets"}
+	assert.Equal(t, "/assets/tenant-1/logo.png", restrictAssetURL("/assets/logo.png", roots, "tenant-1"))
+	assert.Equal(t, "/assets/tenant-1/logo.png", restrictAssetURL("/assets/tenant-1/logo.png", roots, "tenant-1"))
+	assert.Equal(t, "/assets/tenant-1/tenant-2/logo.png", restrictAssetURL("/assets/../tenant-2/logo.png", roots, "tenant-1"))
+	assert.Equal(t, "https://cdn.com/logo.png", restrictAssetURL("https://cdn.com/logo.png", roots, "tenant-1"))
+}
+
+func TestRestrictedRender(t *testing.T) {
+	data := Data{
+		"link":     "javascript:alert(1)",
+		"home":     "https://yaoapps.com",
+		"$session": map[string]interface{}{"token": "secret"},
+		"$global":  map[string]interface{}{"key": "global-secret"},
+	}
+	source := `<html><body>` +
+		`<script>alert(1)</script><iframe srcdoc="<script>alert(1)</script>"></iframe>` +
+		`<a href="{{ link }}" onclick="alert(1)">Bad</a><a href="{{ home }}">Home</a>` +
+		`<p>{{ $env["$session"].token }}</p>` +
+		`</body></html>`
+
+	parser := NewTemplateParser(data, &ParserOption{Restricted: &RestrictedProfile{}, Request: &Request{}})
+	html, err := parser.Render(source)
+	assert.Nil(t, err)
+	assert.NotContains(t, html, "<script>alert(1)")
+	assert.NotContains(t, html, "onclick")
+	assert.NotContains(t, html, "<iframe")
+	assert.NotContains(t, html, `href="javascript:`)
+	assert.NotContains(t, html, "secret")
+	assert.Contains(t, html, `href="https://yaoapps.com"`)
+	assert.NotEmpty(t, parser.Errors())
+
+	RegisterRestrictedRoute("/tenants", &RestrictedProfile{MaxNodes: 10})
+	RegisterRestrictedRoute("/tenants/vip", &RestrictedProfile{MaxNodes: 20})
+	defer RegisterRestrictedRoute("/tenants", nil)
+	defer RegisterRestrictedRoute("/tenants/vip", nil)
+	assert.Equal(t, 10, GetRestrictedProfile("/tenants/acme/index").MaxNodes)
+	assert.Equal(t, 20, GetRestrictedProfile("/tenants/vip").MaxNodes)
+	assert.Nil(t, GetRestrictedProfile("/tenantsx"))
+	assert.Nil(t, GetRestrictedProfile("/index"))
+}
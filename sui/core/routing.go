@@ -0,0 +1,171 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RoutingPolicy the URL normalization of the pages, the requests are redirected (301) to the canonical URL.
+// set in the routing section of the sui DSL
+//
+//	"routing": {
+//	  "trailing_slash": "strip",   // strip or enforce the trailing slash, keep it as it is if empty
+//	  "lowercase": true,           // redirect the paths with the uppercase letters to the lowercase paths
+//	  "redirects": "suis/redirects" // the redirects file, one rule per line: <from> <to> [status]
+//	}
+//
+// the redirects file, the * of the source matches the rest of the path and is replaced with :splat in the target
+//
+//	# the old blog
+//	/blog/*        /news/:splat
+//	/about-us      /about         302
+type RoutingPolicy struct {
+	TrailingSlash string          `json:"trailing_slash,omitempty"` // strip, enforce
+	Lowercase     bool            `json:"lowercase,omitempty"`
+	Redirects     string          `json:"redirects,omitempty"` // the redirects file of the application
+	Rules         []*RedirectRule `json:"-"`
+}
+
+// RedirectRule the redirect rule of the old route
+type RedirectRule struct {
+	From   string
+	To     string
+	Status int
+}
+
+var routingPolicies = map[string]*RoutingPolicy{}
+var routingMutex sync.RWMutex
+
+// RegisterRoutingPolicy set the routing policy of the route prefix, e.g. the public root of the sui
+func RegisterRoutingPolicy(prefix string, policy *RoutingPolicy) {
+	routingMutex.Lock()
+	defer routingMutex.Unlock()
+	prefix = "/" + strings.Trim(prefix, "/")
+	if policy == nil {
+		delete(routingPolicies, prefix)
+		return
+	}
+	routingPolicies[prefix] = policy
+}
+
+// GetRoutingPolicy get the routing policy of the path, the longest prefix wins, nil if the path has no policy
+func GetRoutingPolicy(route string) *RoutingPolicy {
+	routingMutex.RLock()
+	defer routingMutex.RUnlock()
+	var res *RoutingPolicy = nil
+	matched := -1
+	for prefix, policy := range routingPolicies {
+		if len(prefix) <= matched {
+			continue
+		}
+		if prefix == "/" || route == prefix || strings.HasPrefix(route, prefix+"/") {
+			res, matched = policy, len(prefix)
+		}
+	}
+	return res
+}
+
+// RouteRedirect get the redirect of the request path by the routing policy, false if the path is canonical
+func RouteRedirect(route string) (string, int, bool) {
+	policy := GetRoutingPolicy(route)
+	if policy == nil {
+		return "", 0, false
+	}
+	return policy.Redirect(route)
+}
+
+// ParseRedirects parse the redirects file, one rule per line: <from> <to> [status], the # lines are comments
+func ParseRedirects(data []byte) ([]*RedirectRule, error) {
+	rules := []*RedirectRule{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("redirects line %d: %q should be <from> <to> [status]", line, text)
+		}
+
+		rule := &RedirectRule{From: fields[0], To: fields[1], Status: 301}
+		if !strings.HasPrefix(rule.From, "/") {
+			return nil, fmt.Errorf("redirects line %d: the source %s should start with /", line, rule.From)
+		}
+		if strings.Contains(strings.TrimSuffix(rule.From, "*"), "*") {
+			return nil, fmt.Errorf("redirects line %d: the wildcard of the source %s should be at the end", line, rule.From)
+		}
+		if len(fields) == 3 {
+			status, err := strconv.Atoi(fields[2])
+			if err != nil || status < 300 || status > 308 {
+				return nil, fmt.Errorf("redirects line %d: the status %s is invalid", line, fields[2])
+			}
+			rule.Status = status
+		}
+		rules = append(rules, rule)
+	}
+	return rules, scanner.Err()
+}
+
+// Match match the path, the target is returned if the rule is matched
+func (rule *RedirectRule) Match(route string) (string, bool) {
+	if prefix, wildcard := strings.CutSuffix(rule.From, "*"); wildcard {
+		if !strings.HasPrefix(route, prefix) {
+			return "", false
+		}
+		splat := strings.TrimPrefix(route, prefix)
+		return strings.ReplaceAll(rule.To, ":splat", splat), true
+	}
+
+	if route != rule.From && strings.TrimSuffix(route, "/") != strings.TrimSuffix(rule.From, "/") {
+		return "", false
+	}
+	return rule.To, true
+}
+
+// Redirect get the canonical URL of the path, the redirect rules are matched first,
+// then the case and the trailing slash of the page paths are normalized
+func (policy *RoutingPolicy) Redirect(route string) (string, int, bool) {
+	for _, rule := range policy.Rules {
+		if target, ok := rule.Match(route); ok && target != route {
+			return target, rule.Status, true
+		}
+	}
+
+	// The assets and the files keep their names
+	if strings.Contains(path.Base(route), ".") {
+		return "", 0, false
+	}
+
+	target := route
+	if policy.Lowercase {
+		target = strings.ToLower(target)
+	}
+
+	switch policy.TrailingSlash {
+	case "strip":
+		if target != "/" {
+			target = strings.TrimRight(target, "/")
+			if target == "" {
+				target = "/"
+			}
+		}
+	case "enforce":
+		if !strings.HasSuffix(target, "/") {
+			target = target + "/"
+		}
+	}
+
+	if target == route {
+		return "", 0, false
+	}
+	return target, 301, true
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutingPolicy(t *testing.T) {
+	rules, err := ParseRedirects([]byte(`
+# the old blog
+/blog/*     /news/:splat
+/about-us   /about   302
+`))
+	assert.Nil(t, err)
+	assert.Len(t, rules, 2)
+
+	RegisterRoutingPolicy("/site", &RoutingPolicy{TrailingSlash: "strip", Lowercase: true, Rules: rules})
+	defer RegisterRoutingPolicy("/site", nil)
+
+	tests := []struct {
+		path   string
+		target string
+		status int
+	}{
+		{"/blog/2024/hello", "/news/2024/hello", 301},
+		{"/about-us", "/about", 302},
+		{"/site/Docs/Intro/", "/site/docs/intro", 301},
+		{"/site/docs/", "/site/docs", 301},
+		{"/site/assets/Logo.PNG", "", 0},
+		{"/site/docs", "", 0},
+		{"/other/Docs/", "", 0},
+	}
+
+	RegisterRoutingPolicy("/", &RoutingPolicy{Rules: rules})
+	defer RegisterRoutingPolicy("/", nil)
+	for _, test := range tests {
+		target, status, ok := RouteRedirect(test.path)
+		assert.Equal(t, test.target != "", ok, test.path)
+		assert.Equal(t, test.target, target, test.path)
+		assert.Equal(t, test.status, status, test.path)
+	}
+
+	// Enforce the trailing slash
+	policy := &RoutingPolicy{TrailingSlash: "enforce"}
+	target, _, ok := policy.Redirect("/docs")
+	assert.True(t, ok)
+	assert.Equal(t, "/docs/", target)
+
+	// The invalid rules
+	_, err = ParseRedirects([]byte(`/a/*/b /c`))
+	assert.NotNil(t, err)
+	_, err = ParseRedirects([]byte(`/a /b 200`))
+	assert.NotNil(t, err)
+}
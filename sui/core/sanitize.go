@@ -0,0 +1,260 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// SanitizePolicy the allowlist of the tags and the attributes of the s:html content
+type SanitizePolicy struct {
+	Tags      []string            `json:"tags,omitempty"`      // the allowed tags, the other tags are unwrapped (the children are kept)
+	Attrs     map[string][]string `json:"attrs,omitempty"`     // the allowed attributes of the tags, "*" for all the tags
+	Protocols []string            `json:"protocols,omitempty"` // the allowed protocols of the urls, e.g. http, https, mailto
+	tags      map[string]bool
+	attrs     map[string]map[string]bool
+	once      sync.Once
+}
+
+// DefaultSanitizePolicy the default policy of the s:html content, the rich text of the CMS
+var DefaultSanitizePolicy = &SanitizePolicy{
+	Tags: []string{
+		"a", "abbr", "b", "blockquote", "br", "caption", "code", "dd", "del", "div", "dl", "dt", "em",
+		"figcaption", "figure", "h1", "h2", "h3", "h4", "h5", "h6", "hr", "i", "img", "ins", "kbd", "li",
+		"mark", "ol", "p", "pre", "q", "s", "small", "span", "strong", "sub", "sup", "table", "tbody",
+		"td", "tfoot", "th", "thead", "tr", "u", "ul",
+	},
+	Attrs: map[string][]string{
+		"*":   {"class", "title", "lang", "dir"},
+		"a":   {"href", "target", "rel", "name"},
+		"img": {"src", "alt", "width", "height"},
+		"td":  {"colspan", "rowspan"},
+		"th":  {"colspan", "rowspan", "scope"},
+		"ol":  {"start", "type"},
+	},
+	Protocols: []string{"http", "https", "mailto", "tel"},
+}
+
+// sanitizeDrop the tags removed with the contents
+var sanitizeDrop = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"iframe":   true,
+	"object":   true,
+	"embed":    true,
+	"template": true,
+	"noscript": true,
+	"textarea": true,
+	"select":   true,
+	"title":    true,
+	"svg":      true,
+	"math":     true,
+}
+
+// sanitizeURLAttrs the attributes of the urls
+var sanitizeURLAttrs = map[string]bool{
+	"href":   true,
+	"src":    true,
+	"action": true,
+	"cite":   true,
+	"poster": true,
+}
+
+var sanitizePolicies = map[string]*SanitizePolicy{}
+var sanitizeMutex sync.RWMutex
+
+// RegisterSanitizePolicy register the sanitize policy, use s:html-policy="name" to apply it
+func RegisterSanitizePolicy(name string, policy *SanitizePolicy) {
+	sanitizeMutex.Lock()
+	defer sanitizeMutex.Unlock()
+	sanitizePolicies[name] = policy
+}
+
+// GetSanitizePolicy get the sanitize policy, return the default policy if the name is empty
+func GetSanitizePolicy(name string) (*SanitizePolicy, error) {
+	if name == "" || name == "default" {
+		return DefaultSanitizePolicy, nil
+	}
+
+	sanitizeMutex.RLock()
+	defer sanitizeMutex.RUnlock()
+	policy, has := sanitizePolicies[name]
+	if !has {
+		return nil, fmt.Errorf("sanitize policy %s not found", name)
+	}
+	return policy, nil
+}
+
+// Sanitize sanitize the html, only the allowed tags and attributes are kept
+func (policy *SanitizePolicy) Sanitize(source string) ([]*html.Node, error) {
+	policy.compile()
+	context := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	nodes, err := html.ParseFragment(strings.NewReader(source), context)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &html.Node{Type: html.ElementNode, Data: "div"}
+	for _, node := range nodes {
+		root.AppendChild(node)
+	}
+	policy.sanitize(root)
+
+	res := []*html.Node{}
+	for child := root.FirstChild; child != nil; {
+		next := child.NextSibling
+		root.RemoveChild(child)
+		res = append(res, child)
+		child = next
+	}
+	return res, nil
+}
+
+// SanitizeString sanitize the html and return the string
+func (policy *SanitizePolicy) SanitizeString(source string) (string, error) {
+	nodes, err := policy.Sanitize(source)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, node := range nodes {
+		if err := html.Render(&b, node); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
+func (policy *SanitizePolicy) compile() {
+	policy.once.Do(policy.compileOnce)
+}
+
+func (policy *SanitizePolicy) compileOnce() {
+	attrs := map[string]map[string]bool{}
+	for tag, names := range policy.Attrs {
+		attrs[tag] = map[string]bool{}
+		for _, name := range names {
+			attrs[tag][strings.ToLower(name)] = true
+		}
+	}
+
+	tags := map[string]bool{}
+	for _, tag := range policy.Tags {
+		tags[strings.ToLower(tag)] = true
+	}
+	policy.attrs = attrs
+	policy.tags = tags
+}
+
+func (policy *SanitizePolicy) sanitize(node *html.Node) {
+	for child := node.FirstChild; child != nil; {
+		next := child.NextSibling
+		switch child.Type {
+		case html.TextNode:
+
+		case html.ElementNode:
+			if sanitizeDrop[child.Data] {
+				node.RemoveChild(child)
+				break
+			}
+
+			policy.sanitize(child)
+			if !policy.tags[child.Data] {
+				// Unwrap the tag, keep the children
+				for grand := child.FirstChild; grand != nil; {
+					n := grand.NextSibling
+					child.RemoveChild(grand)
+					node.InsertBefore(grand, child)
+					grand = n
+				}
+				node.RemoveChild(child)
+				break
+			}
+			child.Attr = policy.sanitizeAttrs(child.Data, child.Attr)
+
+		default:
+			// Comments, doctype and the raw nodes
+			node.RemoveChild(child)
+		}
+		child = next
+	}
+}
+
+func (policy *SanitizePolicy) sanitizeAttrs(tag string, attrs []html.Attribute) []html.Attribute {
+	res := make([]html.Attribute, 0, len(attrs))
+	for _, attr := range attrs {
+		key := strings.ToLower(attr.Key)
+		if attr.Namespace != "" || strings.HasPrefix(key, "on") {
+			continue
+		}
+		if !policy.attrs["*"][key] && !policy.attrs[tag][key] {
+			continue
+		}
+		if sanitizeURLAttrs[key] && !policy.allowURL(attr.Val) {
+			continue
+		}
+		res = append(res, html.Attribute{Key: key, Val: attr.Val})
+	}
+	return res
+}
+
+// allowURL the relative urls and the urls with the allowed protocols
+func (policy *SanitizePolicy) allowURL(value string) bool {
+	value = strings.ToLower(strings.Join(strings.Fields(value), ""))
+	idx := strings.Index(value, ":")
+	if idx < 0 || strings.ContainsAny(value[:idx], "/?#") {
+		return true
+	}
+
+	protocol := value[:idx]
+	for _, allowed := range policy.Protocols {
+		if protocol == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func (parser *TemplateParser) isHTML(sel *goquery.Selection) bool {
+	_, has := sel.Attr("s:html")
+	return has
+}
+
+// htmlElementNode the s:html="{{ content }}" directive, inject the sanitized value as the inner html
+// the policy is set by s:html-policy="name", see RegisterSanitizePolicy
+func (parser *TemplateParser) htmlElementNode(sel *goquery.Selection) {
+	defer parser.option.Timing.Start("html", "s:html")()
+	parser.parsed(sel)
+	value, values := parser.data.ReplaceGuard(sel.AttrOr("s:html", ""), parser.guard)
+	for _, v := range values {
+		if v.Error != nil {
+			parser.renderError(sel.Nodes[0], "s:html", v.Stmt, v.Error)
+			sel.Empty()
+			return
+		}
+	}
+
+	policy, err := GetSanitizePolicy(sel.AttrOr("s:html-policy", ""))
+	if err != nil {
+		parser.renderError(sel.Nodes[0], "s:html-policy", sel.AttrOr("s:html-policy", ""), err)
+		sel.Empty()
+		return
+	}
+
+	nodes, err := policy.Sanitize(value)
+	if err != nil {
+		parser.renderError(sel.Nodes[0], "s:html", sel.AttrOr("s:html", ""), err)
+		sel.Empty()
+		return
+	}
+
+	sel.Empty()
+	for _, node := range nodes {
+		sel.Nodes[0].AppendChild(node)
+	}
+}
@@ -0,0 +1,31 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizePolicy(t *testing.T) {
+	res, err := DefaultSanitizePolicy.SanitizeString(`<p class="lead" onclick="alert(1)">Hello <b>Yao</b><script>alert(1)</script></p>`)
+	assert.Nil(t, err)
+	assert.Equal(t, `<p class="lead">Hello <b>Yao</b></p>`, res)
+
+	res, err = DefaultSanitizePolicy.SanitizeString(`<a href="javascript:alert(1)">link</a><a href="/docs">docs</a>`)
+	assert.Nil(t, err)
+	assert.Equal(t, `<a>link</a><a href="/docs">docs</a>`, res)
+
+	res, err = DefaultSanitizePolicy.SanitizeString(`<section><h1>Title</h1><!-- comment --></section>`)
+	assert.Nil(t, err)
+	assert.Equal(t, `<h1>Title</h1>`, res)
+
+	RegisterSanitizePolicy("text", &SanitizePolicy{})
+	policy, err := GetSanitizePolicy("text")
+	assert.Nil(t, err)
+	res, err = policy.SanitizeString(`<p>Hello <b>Yao</b></p>`)
+	assert.Nil(t, err)
+	assert.Equal(t, `Hello Yao`, res)
+
+	_, err = GetSanitizePolicy("not-found")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// scheduleAttrs the attributes of the publish window
+var scheduleAttrs = []string{"s:publish-at", "s:unpublish-at"}
+
+// hasSchedule check if the node has the publish window
+func (parser *TemplateParser) hasSchedule(sel *goquery.Selection) bool {
+	for _, name := range scheduleAttrs {
+		if _, has := sel.Attr(name); has {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleNode the s:publish-at and s:unpublish-at directives, the node is rendered in the publish window only,
+// return false if the node is hidden. the times are compared with the $now (the time-travel preview time)
+//
+//	<div s:publish-at="2030-11-25T00:00:00Z" s:unpublish-at="2030-12-01T00:00:00Z">Black Friday</div>
+//	<div s:publish-at="{{ promo.start }}" s:unpublish-at="{{ promo.end }}">{{ promo.title }}</div>
+//
+// the nearest publish or unpublish time after the $now is kept, the ttl of the page cache and the s:cache
+// fragments are not longer than it, see NextSchedule
+func (parser *TemplateParser) scheduleNode(sel *goquery.Selection) bool {
+	defer parser.option.Timing.Start("schedule", "s:publish-at")()
+	now := parser.now()
+
+	published := true
+	for _, name := range scheduleAttrs {
+		value, has := sel.Attr(name)
+		if !has || strings.TrimSpace(value) == "" {
+			continue
+		}
+
+		at, err := parser.scheduleTime(value)
+		if err != nil {
+			parser.renderError(sel.Nodes[0], name, value, err)
+			continue
+		}
+		if at.IsZero() {
+			continue
+		}
+
+		if at.After(now) {
+			parser.scheduleAt(at)
+			if name == "s:publish-at" {
+				published = false
+			}
+			continue
+		}
+
+		if name == "s:unpublish-at" {
+			published = false
+		}
+	}
+
+	if !published {
+		parser.parsed(sel)
+		parser.hide(sel)
+	}
+	return published
+}
+
+// scheduleTime evaluate the time of the window, the empty binding (e.g. the promo has no end) is the zero time
+func (parser *TemplateParser) scheduleTime(value string) (time.Time, error) {
+	var v interface{} = value
+	if stmt := strings.TrimSpace(value); strings.HasPrefix(stmt, "{{") && strings.HasSuffix(stmt, "}}") {
+		res, _, err := parser.data.ExecGuard(strings.TrimSpace(stmt[2:len(stmt)-2]), parser.guard)
+		if err != nil {
+			return time.Time{}, err
+		}
+		v = res
+	}
+
+	if v == nil {
+		return time.Time{}, nil
+	}
+	if s, ok := v.(string); ok && strings.TrimSpace(s) == "" {
+		return time.Time{}, nil
+	}
+
+	at, err := toTime(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("the schedule time %s", err.Error())
+	}
+	return at, nil
+}
+
+// scheduleAt keep the nearest time of the publish windows, the open s:cache fragments expire at the time
+func (parser *TemplateParser) scheduleAt(at time.Time) {
+	if at.IsZero() {
+		return
+	}
+	if parser.schedule.IsZero() || at.Before(parser.schedule) {
+		parser.schedule = at
+	}
+
+	ttl := at.Sub(parser.now())
+	for _, fc := range parser.fragments {
+		if fc.html == "" && fc.assets == nil && ttl > 0 && ttl < fc.ttl {
+			fc.ttl = ttl
+		}
+	}
+}
+
+// now the current time of the rendering, the $now shifted by the time-travel preview
+func (parser *TemplateParser) now() time.Time {
+	if v, ok := parser.data[nowKey].(time.Time); ok {
+		return v
+	}
+	return parser.option.Request.Time()
+}
+
+// NextSchedule get the nearest publish or unpublish time of the rendered page after the $now, zero if the page has
+// no scheduled contents. the page cache should expire at the time
+func (parser *TemplateParser) NextSchedule() time.Time {
+	return parser.schedule
+}
@@ -0,0 +1,38 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserSchedule(t *testing.T) {
+	now := time.Date(2030, 11, 27, 12, 0, 0, 0, time.UTC)
+	data := Data{"promos": []interface{}{
+		map[string]interface{}{"title": "Black Friday", "start": "2030-11-25T00:00:00Z", "end": "2030-12-01T00:00:00Z"},
+		map[string]interface{}{"title": "Christmas", "start": "2030-12-20T00:00:00Z", "end": nil},
+		map[string]interface{}{"title": "Summer", "start": "2030-06-01T00:00:00Z", "end": "2030-09-01T00:00:00Z"},
+	}}
+
+	parser := NewTemplateParser(data, &ParserOption{Request: &Request{Now: now}})
+	html, err := parser.Render(`<html><body>` +
+		`<p s:publish-at="2030-11-01T00:00:00Z">Live</p>` +
+		`<p s:publish-at="2030-12-24T00:00:00Z">Gifts</p>` +
+		`<p s:unpublish-at="2030-11-26T00:00:00Z">Gone</p>` +
+		`<ul><li s:for="promos" s:for-item="promo" s:publish-at="{{ promo.start }}" s:unpublish-at="{{ promo.end }}">{{ promo.title }}</li></ul>` +
+		`</body></html>`)
+	assert.Nil(t, err)
+	assert.Empty(t, parser.Errors())
+	assert.Contains(t, html, `<p>Live</p>`)
+	assert.NotContains(t, html, `Gifts`)
+	assert.NotContains(t, html, `Gone`)
+	assert.Contains(t, html, `<ul><li>Black Friday</li></ul>`)
+	assert.Equal(t, time.Date(2030, 12, 1, 0, 0, 0, 0, time.UTC).Unix(), parser.NextSchedule().Unix())
+
+	// The invalid time
+	parser = NewTemplateParser(data, &ParserOption{Request: &Request{Now: now}})
+	_, err = parser.Render(`<html><body><p s:publish-at="tomorrow">Soon</p></body></html>`)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, parser.Errors())
+}
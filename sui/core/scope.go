@@ -0,0 +1,43 @@
+package core
+
+// scope the lexical scope of the template, keeps the shadowed variables of the outer scope
+type scope map[string]scopeVar
+
+type scopeVar struct {
+	value  interface{}
+	exists bool
+}
+
+// pushScope enter a new lexical scope (e.g. the s:for item)
+func (parser *TemplateParser) pushScope() {
+	parser.scopes = append(parser.scopes, scope{})
+}
+
+// popScope leave the current lexical scope, restore the shadowed variables
+func (parser *TemplateParser) popScope() {
+	if len(parser.scopes) == 0 {
+		return
+	}
+
+	last := len(parser.scopes) - 1
+	for name, v := range parser.scopes[last] {
+		if v.exists {
+			parser.data[name] = v.value
+			continue
+		}
+		delete(parser.data, name)
+	}
+	parser.scopes = parser.scopes[:last]
+}
+
+// setVar set the variable in the current scope, the outer one will be restored when the scope is popped
+func (parser *TemplateParser) setVar(name string, value interface{}) {
+	if len(parser.scopes) > 0 {
+		current := parser.scopes[len(parser.scopes)-1]
+		if _, saved := current[name]; !saved {
+			old, exists := parser.data[name]
+			current[name] = scopeVar{value: old, exists: exists}
+		}
+	}
+	parser.data[name] = value
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -91,7 +92,10 @@ func LoadScript(file string, disableCache ...bool) (*Script, error) {
 
 // Call the script method
 // This will be refactored to improve the performance
-func (script *Script) Call(r *Request, method string, args ...any) (interface{}, error) {
+func (script *Script) Call(r *Request, method string, args ...any) (res interface{}, err error) {
+	_, _, end := startSpan(context.Background(), "sui.script", "sui.method", method, "sui.route", r.URL.Path)
+	defer func() { end(err) }()
+
 	ctx, err := script.NewContext(r.Sid, nil)
 	if err != nil {
 		return nil, err
@@ -104,7 +108,7 @@ func (script *Script) Call(r *Request, method string, args ...any) (interface{},
 
 	// Set the sid
 	ctx.Sid = r.Sid
-	res, err := ctx.Call(method, args...)
+	res, err = ctx.Call(method, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,655 @@
+package core
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/process"
+	"github.com/yaoapp/gou/store"
+	"github.com/yaoapp/kun/log"
+	xhtml "golang.org/x/net/html"
+)
+
+// Settings the typed settings of the app, the values are persisted in the store and overridden per tenant.
+// set in the settings section of the sui DSL
+//
+//	"settings": {
+//	  "store": "setting",
+//	  "tenant": "$session.tenant_id",
+//	  "onchange": "scripts.settings.Changed",
+//	  "keys": {
+//	    "site.title": {"type": "string", "default": "Yao", "label": "Site title"},
+//	    "orders.limit": {"type": "integer", "default": 20, "min": 1, "max": 100},
+//	    "theme.mode": {"type": "enum", "default": "light", "options": ["light", "dark"]}
+//	  }
+//	}
+//
+// the templates read the settings by {{ $setting.site.title }}, the processes by settings.Get and settings.Set
+type Settings struct {
+	Store    string                 `json:"store,omitempty"`    // the store of the values, in-memory only if the store is not found
+	Tenant   string                 `json:"tenant,omitempty"`   // the expression of the tenant of the visitor against the $session
+	OnChange string                 `json:"onchange,omitempty"` // the process called with the key, the tenant, the old and the new value
+	Keys     map[string]*SettingKey `json:"keys"`
+}
+
+// SettingKey the typed key of the settings
+type SettingKey struct {
+	Type        string        `json:"type"` // string, number, integer, boolean, enum or json
+	Default     interface{}   `json:"default,omitempty"`
+	Label       string        `json:"label,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Options     []interface{} `json:"options,omitempty"` // the values of the enum
+	Min         *float64      `json:"min,omitempty"`     // the min of the number, or the min length of the string
+	Max         *float64      `json:"max,omitempty"`     // the max of the number, or the max length of the string
+	Pattern     string        `json:"pattern,omitempty"` // the regular expression of the string
+	Tenant      bool          `json:"tenant,omitempty"`  // the tenants can override the value
+	pattern     *regexp.Regexp
+}
+
+// SettingChange the change of the setting value
+type SettingChange struct {
+	Key    string      `json:"key"`
+	Tenant string      `json:"tenant,omitempty"`
+	Old    interface{} `json:"old"`
+	New    interface{} `json:"new"`
+}
+
+// SettingsRefresh the interval of reading the values from the store
+var SettingsRefresh = 5 * time.Second
+
+// settingKey the data key of the settings of the rendering, {{ $setting.site.title }}
+const settingKey = "$setting"
+
+const settingsPrefix = "__yao.settings"
+
+var settingKeyRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+var settings = &Settings{Keys: map[string]*SettingKey{}}
+var settingsMemory = map[string]string{} // the values if the store is not found
+var settingsCache = map[string]*settingCache{}
+var settingsListeners = []func(change SettingChange){}
+var settingsMutex sync.RWMutex
+
+type settingCache struct {
+	value  interface{}
+	has    bool
+	loaded time.Time
+}
+
+// RegisterSettings add the keys of the settings, the store, the tenant and the onchange of the latest are used
+func RegisterSettings(s *Settings) error {
+	if s == nil {
+		return nil
+	}
+	for name, key := range s.Keys {
+		if !settingKeyRe.MatchString(name) {
+			return fmt.Errorf("the setting key %s is invalid", name)
+		}
+		if err := key.compile("setting", name); err != nil {
+			return err
+		}
+	}
+
+	settingsMutex.Lock()
+	defer settingsMutex.Unlock()
+	keys := map[string]*SettingKey{}
+	for name, key := range settings.Keys {
+		keys[name] = key
+	}
+	for name, key := range s.Keys {
+		keys[name] = key
+	}
+	settings = &Settings{Store: s.Store, Tenant: s.Tenant, OnChange: s.OnChange, Keys: keys}
+	settingsCache = map[string]*settingCache{}
+	return nil
+}
+
+// OnSettingChange listen to the changes of the settings
+func OnSettingChange(fn func(change SettingChange)) {
+	settingsMutex.Lock()
+	defer settingsMutex.Unlock()
+	settingsListeners = append(settingsListeners, fn)
+}
+
+// SettingKeys get the keys of the settings, the admin edits the values by the keys
+func SettingKeys() map[string]*SettingKey {
+	settingsMutex.RLock()
+	defer settingsMutex.RUnlock()
+	keys := make(map[string]*SettingKey, len(settings.Keys))
+	for name, key := range settings.Keys {
+		keys[name] = key
+	}
+	return keys
+}
+
+// GetSetting get the value of the setting, the value of the tenant, the value of the app or the default
+func GetSetting(name string, tenant string) (interface{}, error) {
+	settingsMutex.RLock()
+	key, has := settings.Keys[name]
+	settingsMutex.RUnlock()
+	if !has {
+		return nil, fmt.Errorf("the setting %s is not defined", name)
+	}
+
+	tenants := []string{""}
+	if tenant != "" && key.Tenant {
+		tenants = []string{tenant, ""}
+	}
+	for _, t := range tenants {
+		if value, has := settingValue(name, t); has {
+			if value, err := key.validate(name, value); err == nil { // the stored value of the previous type is ignored
+				return value, nil
+			}
+		}
+	}
+	return key.Default, nil
+}
+
+// SetSetting validate and set the value of the setting, the tenant overrides the value of the app if the key allows.
+// the nil value removes the value, the listeners and the onchange process are called if the value is changed
+func SetSetting(name string, tenant string, value interface{}) (interface{}, error) {
+	res, err := SetSettings(map[string]interface{}{name: value}, tenant)
+	if err != nil {
+		return nil, err
+	}
+	return res[name], nil
+}
+
+// SetSettings set the values of the settings, e.g. the admin form of the settings. the values are validated before
+// any of them is written, the listeners and the onchange process are called after all of them are written
+func SetSettings(values map[string]interface{}, tenant string) (map[string]interface{}, error) {
+	keys := SettingKeys()
+	names := sortedKeys(values)
+	checked := map[string]interface{}{}
+	for _, name := range names {
+		key, has := keys[name]
+		if !has {
+			return nil, fmt.Errorf("the setting %s is not defined", name)
+		}
+		if tenant != "" && !key.Tenant {
+			return nil, fmt.Errorf("the setting %s can not be overridden by the tenants", name)
+		}
+
+		value := values[name]
+		if value != nil {
+			var err error
+			value, err = key.validate(name, value)
+			if err != nil {
+				return nil, err
+			}
+		}
+		checked[name] = value
+	}
+
+	olds := map[string]interface{}{}
+	for _, name := range names {
+		olds[name], _ = GetSetting(name, tenant)
+	}
+	for _, name := range names {
+		if err := settingStore(name, tenant, checked[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	res := map[string]interface{}{}
+	for _, name := range names {
+		current, _ := GetSetting(name, tenant)
+		res[name] = current
+		if !settingEqual(olds[name], current) {
+			settingChanged(SettingChange{Key: name, Tenant: tenant, Old: olds[name], New: current})
+		}
+	}
+	return res, nil
+}
+
+// settingChanged call the listeners and the onchange process of the settings
+func settingChanged(change SettingChange) {
+	settingsMutex.RLock()
+	onchange := settings.OnChange
+	listeners := append([]func(SettingChange){}, settingsListeners...)
+	settingsMutex.RUnlock()
+	for _, fn := range listeners {
+		fn(change)
+	}
+	if onchange == "" {
+		return
+	}
+
+	p, err := process.Of(onchange, change.Key, change.Tenant, change.Old, change.New)
+	if err != nil {
+		log.Error("[SUI] settings onchange %s: %s", onchange, err.Error())
+		return
+	}
+	if _, err := p.Exec(); err != nil {
+		log.Error("[SUI] settings onchange %s: %s", onchange, err.Error())
+	}
+}
+
+// SettingsData the values of all the settings of the tenant, the keys are nested by the dots, e.g. site.title
+func SettingsData(tenant string) map[string]interface{} {
+	values := map[string]interface{}{}
+	for name := range SettingKeys() {
+		if value, err := GetSetting(name, tenant); err == nil {
+			values[name] = value
+		}
+	}
+	return nestedData(values)
+}
+
+// widgetSettings render the admin form of the settings, the name is the prefix of the keys (* for all the keys), the
+// tenant attribute edits the overrides of the tenant. the inputs are typed by the keys, the form is submitted to the
+// action, e.g. the api saving the values by the sui.settings.save process
+func (parser *TemplateParser) widgetSettings(id string, attrs map[string]string) (string, error) {
+	tenant := strings.TrimSpace(attrs["tenant"])
+	keys := SettingKeys()
+	names := []string{}
+	for name, key := range keys {
+		if id != "*" && name != id && !strings.HasPrefix(name, id+".") {
+			continue
+		}
+		if tenant != "" && !key.Tenant {
+			continue
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf("the settings %s are not defined", id)
+	}
+	sort.Strings(names)
+
+	disabled := ""
+	if attrs["readonly"] == "true" {
+		disabled = " disabled"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<form %s method="post" action="%s"`, widgetAttrs("settings", id, attrs), html.EscapeString(attrs["action"]))
+	if tenant != "" {
+		fmt.Fprintf(&b, ` data-tenant="%s"`, html.EscapeString(tenant))
+	}
+	b.WriteString(`>`)
+	if csrf := parser.option.Request.CSRFToken(); csrf != "" {
+		fmt.Fprintf(&b, `<input type="hidden" name="%s" value="%s">`, CSRFField, csrf)
+	}
+
+	for _, name := range names {
+		key := keys[name]
+		value, _ := GetSetting(name, tenant)
+		label := key.Label
+		if label == "" {
+			label = name
+		}
+		input := html.EscapeString(name)
+
+		fmt.Fprintf(&b, `<label class="sui-widget-field"><span>%s</span>`, html.EscapeString(label))
+		switch key.Type {
+		case "boolean":
+			checked := ""
+			if v, ok := value.(bool); ok && v {
+				checked = " checked"
+			}
+			fmt.Fprintf(&b, `<input type="checkbox" name="%s" value="true"%s%s>`, input, checked, disabled)
+
+		case "enum":
+			fmt.Fprintf(&b, `<select name="%s"%s>`, input, disabled)
+			for _, option := range key.Options {
+				selected := ""
+				if settingEqual(option, value) {
+					selected = " selected"
+				}
+				optValue := html.EscapeString(widgetValue(option))
+				fmt.Fprintf(&b, `<option value="%s"%s>%s</option>`, optValue, selected, optValue)
+			}
+			b.WriteString(`</select>`)
+
+		case "json":
+			fmt.Fprintf(&b, `<textarea name="%s"%s>%s</textarea>`, input, disabled, html.EscapeString(widgetValue(value)))
+
+		case "number", "integer":
+			step := "any"
+			if key.Type == "integer" {
+				step = "1"
+			}
+			fmt.Fprintf(&b, `<input type="number" name="%s" value="%s" step="%s"%s%s>`,
+				input, html.EscapeString(widgetValue(value)), step, settingRange(key, "min", "max"), disabled)
+
+		default:
+			pattern := ""
+			if key.Pattern != "" {
+				pattern = fmt.Sprintf(` pattern="%s"`, html.EscapeString(key.Pattern))
+			}
+			fmt.Fprintf(&b, `<input type="text" name="%s" value="%s"%s%s%s>`,
+				input, html.EscapeString(widgetValue(value)), settingRange(key, "minlength", "maxlength"), pattern, disabled)
+		}
+		if key.Description != "" {
+			fmt.Fprintf(&b, `<small>%s</small>`, html.EscapeString(key.Description))
+		}
+		b.WriteString(`</label>`)
+	}
+	b.WriteString(`</form>`)
+	return b.String(), nil
+}
+
+// settingRange the attributes of the min and the max of the key
+func settingRange(key *SettingKey, min string, max string) string {
+	res := ""
+	if key.Min != nil {
+		res += fmt.Sprintf(` %s="%v"`, min, *key.Min)
+	}
+	if key.Max != nil {
+		res += fmt.Sprintf(` %s="%v"`, max, *key.Max)
+	}
+	return res
+}
+
+// nestedData nest the values by the dots of the keys, e.g. {"site.title": "Yao"} => {"site": {"title": "Yao"}}.
+// the shorter key wins, e.g. site over site.title
+func nestedData(values map[string]interface{}) map[string]interface{} {
+	data := map[string]interface{}{}
+	for _, name := range sortedKeys(values) {
+		parts := strings.Split(name, ".")
+		node := data
+		for _, part := range parts[:len(parts)-1] {
+			if _, has := node[part]; !has {
+				node[part] = map[string]interface{}{}
+			}
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				node = nil
+				break
+			}
+			node = child
+		}
+		if node != nil {
+			node[parts[len(parts)-1]] = values[name]
+		}
+	}
+	return data
+}
+
+// settingsTenant get the tenant of the visitor by the tenant expression of the settings
+func settingsTenant(r *Request) string {
+	settingsMutex.RLock()
+	stmt := settings.Tenant
+	settingsMutex.RUnlock()
+	if stmt == "" || r == nil {
+		return ""
+	}
+
+	res, _, err := Data{"$session": r.sessionData()}.Exec(stmt)
+	if err != nil || res == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", res)
+}
+
+// visitorData set the settings and the preferences of the visitor if the source reads them, e.g. {{ $setting.site.title }}
+// and {{ $pref.ui.theme }}. they are resolved once in the rendering, the sources of the components and the includes are
+// checked when they are loaded
+func (parser *TemplateParser) visitorData(source string) {
+	parser.visitorKeys(strings.Contains(source, settingKey), strings.Contains(source, prefKey))
+}
+
+// visitorNode set the settings and the preferences of the visitor if the texts or the attributes of the node read them
+func (parser *TemplateParser) visitorNode(node *xhtml.Node) {
+	setting, pref := false, false
+	var walk func(n *xhtml.Node)
+	walk = func(n *xhtml.Node) {
+		values := []string{}
+		if n.Type == xhtml.TextNode {
+			values = append(values, n.Data)
+		}
+		for _, attr := range n.Attr {
+			values = append(values, attr.Val)
+		}
+		for _, value := range values {
+			setting = setting || strings.Contains(value, settingKey)
+			pref = pref || strings.Contains(value, prefKey)
+		}
+		for child := n.FirstChild; child != nil && !(setting && pref); child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(node)
+	parser.visitorKeys(setting, pref)
+}
+
+func (parser *TemplateParser) visitorKeys(setting bool, pref bool) {
+	if _, has := parser.data[settingKey]; setting && !has && hasSettings() {
+		parser.data[settingKey] = SettingsData(settingsTenant(parser.option.Request)) // {{ $setting.site.title }}
+	}
+	if _, has := parser.data[prefKey]; pref && !has && hasPreferences() {
+		parser.data[prefKey] = PreferencesData(PreferencesUser(parser.option.Request.sessionData())) // {{ $pref.ui.theme }}
+	}
+}
+
+// hasSettings check if the settings are defined
+func hasSettings() bool {
+	settingsMutex.RLock()
+	defer settingsMutex.RUnlock()
+	return len(settings.Keys) > 0
+}
+
+// settingValue read the value from the store, cached for SettingsRefresh
+func settingValue(name string, tenant string) (interface{}, bool) {
+	id := settingID(name, tenant)
+	settingsMutex.RLock()
+	cache, has := settingsCache[id]
+	settingsMutex.RUnlock()
+	if has && time.Since(cache.loaded) < SettingsRefresh {
+		return cache.value, cache.has
+	}
+
+	var value interface{}
+	raw, ok := settingsGet(id)
+	if ok {
+		if err := jsoniter.UnmarshalFromString(raw, &value); err != nil {
+			ok = false
+		}
+	}
+
+	settingsMutex.Lock()
+	settingsCache[id] = &settingCache{value: value, has: ok, loaded: time.Now()}
+	settingsMutex.Unlock()
+	return value, ok
+}
+
+// settingStore write the value to the store, the nil value removes the value
+func settingStore(name string, tenant string, value interface{}) error {
+	id := settingID(name, tenant)
+	raw := ""
+	if value != nil {
+		var err error
+		raw, err = jsoniter.MarshalToString(value)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The store is written without the lock, the cache is removed after the value is written
+	settingsMutex.RLock()
+	storeName := settings.Store
+	settingsMutex.RUnlock()
+	if s, has := store.Pools[storeName]; has {
+		var err error
+		if value == nil {
+			err = s.Del(id)
+		} else {
+			err = s.Set(id, raw, 0)
+		}
+		settingsMutex.Lock()
+		delete(settingsCache, id)
+		settingsMutex.Unlock()
+		return err
+	}
+
+	if storeName != "" {
+		log.Warn(`[SUI] The settings store "%s" is not found, the settings are not persisted`, storeName)
+	}
+	settingsMutex.Lock()
+	defer settingsMutex.Unlock()
+	delete(settingsCache, id)
+	if value == nil {
+		delete(settingsMemory, id)
+		return nil
+	}
+	settingsMemory[id] = raw
+	return nil
+}
+
+// settingsGet read the json value from the store, the store is read without the lock
+func settingsGet(id string) (string, bool) {
+	settingsMutex.RLock()
+	storeName := settings.Store
+	raw, memory := settingsMemory[id]
+	settingsMutex.RUnlock()
+	s, has := store.Pools[storeName]
+	if !has {
+		return raw, memory
+	}
+
+	value, has := s.Get(id)
+	if !has {
+		return "", false
+	}
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	}
+	return "", false
+}
+
+func settingID(name string, tenant string) string {
+	if tenant == "" {
+		return settingsPrefix + "." + name
+	}
+	return settingsPrefix + "@" + tenant + "." + name
+}
+
+// validate check the type of the value of the setting and convert it, e.g. "20" of the integer => 20
+func (key *SettingKey) validate(name string, value interface{}) (interface{}, error) {
+	return key.check("setting", name, value)
+}
+
+// check check the type of the value of the setting or the preference (the kind) and convert it
+func (key *SettingKey) check(kind string, name string, value interface{}) (interface{}, error) {
+	switch key.Type {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("the %s %s should be a string", kind, name)
+		}
+		if key.Min != nil && float64(len([]rune(s))) < *key.Min {
+			return nil, fmt.Errorf("the %s %s should be at least %v characters", kind, name, *key.Min)
+		}
+		if key.Max != nil && float64(len([]rune(s))) > *key.Max {
+			return nil, fmt.Errorf("the %s %s should be at most %v characters", kind, name, *key.Max)
+		}
+		if key.Pattern != "" {
+			if key.pattern == nil {
+				return nil, fmt.Errorf("the pattern of the %s %s is not compiled", kind, name)
+			}
+			if !key.pattern.MatchString(s) {
+				return nil, fmt.Errorf("the %s %s should match %s", kind, name, key.Pattern)
+			}
+		}
+		return s, nil
+
+	case "number", "integer":
+		var n float64
+		switch v := value.(type) {
+		case float64:
+			n = v
+		case float32:
+			n = float64(v)
+		case int:
+			n = float64(v)
+		case int64:
+			n = float64(v)
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("the %s %s should be a %s", kind, name, key.Type)
+			}
+			n = f
+		default:
+			return nil, fmt.Errorf("the %s %s should be a %s", kind, name, key.Type)
+		}
+		if key.Type == "integer" && n != float64(int64(n)) {
+			return nil, fmt.Errorf("the %s %s should be an integer", kind, name)
+		}
+		if key.Min != nil && n < *key.Min {
+			return nil, fmt.Errorf("the %s %s should be at least %v", kind, name, *key.Min)
+		}
+		if key.Max != nil && n > *key.Max {
+			return nil, fmt.Errorf("the %s %s should be at most %v", kind, name, *key.Max)
+		}
+		if key.Type == "integer" {
+			return int64(n), nil
+		}
+		return n, nil
+
+	case "boolean":
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, nil
+			}
+		}
+		return nil, fmt.Errorf("the %s %s should be a boolean", kind, name)
+
+	case "enum":
+		for _, option := range key.Options {
+			if settingEqual(option, value) {
+				return option, nil
+			}
+		}
+		return nil, fmt.Errorf("the %s %s should be one of %v", kind, name, key.Options)
+	}
+	return value, nil // json
+}
+
+// compile check the type, the pattern and the default of the key of the setting or the preference (the kind)
+func (key *SettingKey) compile(kind string, name string) error {
+	if key == nil {
+		return fmt.Errorf("the %s %s should have the type", kind, name)
+	}
+	switch key.Type {
+	case "string", "number", "integer", "boolean", "enum", "json":
+	default:
+		return fmt.Errorf("the type %s of the %s %s is not supported", key.Type, kind, name)
+	}
+	if key.Pattern != "" {
+		pattern, err := regexp.Compile(key.Pattern)
+		if err != nil {
+			return fmt.Errorf("the pattern of the %s %s is invalid. %s", kind, name, err.Error())
+		}
+		key.pattern = pattern
+	}
+	if key.Default != nil {
+		value, err := key.check(kind, name, key.Default)
+		if err != nil {
+			return err
+		}
+		key.Default = value
+	}
+	return nil
+}
+
+// settingEqual compare the values by the json
+func settingEqual(a, b interface{}) bool {
+	rawA, errA := jsonStable.MarshalToString(a)
+	rawB, errB := jsonStable.MarshalToString(b)
+	return errA == nil && errB == nil && rawA == rawB
+}
@@ -0,0 +1,173 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettings(t *testing.T) {
+	defer func(s *Settings, listeners []func(SettingChange)) {
+		settings, settingsListeners = s, listeners
+		settingsMemory, settingsCache = map[string]string{}, map[string]*settingCache{}
+	}(settings, settingsListeners)
+
+	max := 100.0
+	err := RegisterSettings(&Settings{Keys: map[string]*SettingKey{
+		"site.title":   {Type: "string", Default: "Yao", Tenant: true},
+		"orders.limit": {Type: "integer", Default: 20, Max: &max},
+		"theme.mode":   {Type: "enum", Default: "light", Options: []interface{}{"light", "dark"}},
+		"site":         {Type: "boolean"},
+	}})
+	assert.Nil(t, err)
+
+	changes := []SettingChange{}
+	OnSettingChange(func(change SettingChange) { changes = append(changes, change) })
+
+	value, err := GetSetting("orders.limit", "")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(20), value)
+
+	// The values are validated and converted
+	value, err = SetSetting("orders.limit", "", "50")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(50), value)
+	_, err = SetSetting("orders.limit", "", 500)
+	assert.Contains(t, err.Error(), "the setting orders.limit should be at most 100")
+	_, err = SetSetting("orders.limit", "", 1.5)
+	assert.Contains(t, err.Error(), "should be an integer")
+	_, err = SetSetting("theme.mode", "", "blue")
+	assert.Contains(t, err.Error(), "should be one of [light dark]")
+	_, err = SetSetting("orders.limit", "acme", 30)
+	assert.Contains(t, err.Error(), "can not be overridden by the tenants")
+	_, err = GetSetting("missing", "")
+	assert.Contains(t, err.Error(), "the setting missing is not defined")
+
+	// The tenant overrides the value of the app
+	SetSetting("site.title", "", "Shop")
+	SetSetting("site.title", "acme", "Acme")
+	value, _ = GetSetting("site.title", "acme")
+	assert.Equal(t, "Acme", value)
+	value, _ = GetSetting("site.title", "other")
+	assert.Equal(t, "Shop", value)
+
+	// The reset falls back to the value of the app
+	SetSetting("site.title", "acme", nil)
+	value, _ = GetSetting("site.title", "acme")
+	assert.Equal(t, "Shop", value)
+
+	// The same value is not a change
+	SetSetting("orders.limit", "", 50)
+	assert.Equal(t, []SettingChange{
+		{Key: "orders.limit", Old: int64(20), New: int64(50)},
+		{Key: "site.title", Old: "Yao", New: "Shop"},
+		{Key: "site.title", Tenant: "acme", Old: "Shop", New: "Acme"},
+		{Key: "site.title", Tenant: "acme", Old: "Acme", New: "Shop"},
+	}, changes)
+
+	// The values are validated before any of them is set
+	_, err = SetSettings(map[string]interface{}{"orders.limit": 10, "theme.mode": "blue"}, "")
+	assert.NotNil(t, err)
+	value, _ = GetSetting("orders.limit", "")
+	assert.Equal(t, int64(50), value)
+
+	// The templates read the nested keys
+	parser := NewTemplateParser(Data{}, &ParserOption{Route: "/index", Request: &Request{}})
+	html, err := parser.Render(`<html><body><h1>{{ $setting.theme.mode }} {{ $setting.orders.limit }}</h1></body></html>`)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `<h1>light 50</h1>`)
+	assert.Nil(t, SettingsData("")["site"]) // the shorter key wins
+	assert.NotContains(t, html, `"$setting"`)
+
+	err = RegisterSettings(&Settings{Keys: map[string]*SettingKey{"bad": {Type: "date"}}})
+	assert.Contains(t, err.Error(), "the type date of the setting bad is not supported")
+	err = RegisterSettings(&Settings{Keys: map[string]*SettingKey{"bad": {Type: "string", Pattern: "[a-"}}})
+	assert.Contains(t, err.Error(), "the pattern of the setting bad is invalid")
+}
+
+func TestSetSettings(t *testing.T) {
+	defer func(s *Settings, listeners []func(SettingChange)) {
+		settings, settingsListeners = s, listeners
+		settingsMemory, settingsCache = map[string]string{}, map[string]*settingCache{}
+	}(settings, settingsListeners)
+
+	err := RegisterSettings(&Settings{Keys: map[string]*SettingKey{
+		"site.title": {Type: "string", Default: "Yao", Pattern: `^[A-Z]`},
+		"site.email": {Type: "string"},
+	}})
+	assert.Nil(t, err)
+
+	_, err = SetSetting("site.title", "", "shop")
+	assert.Contains(t, err.Error(), "the setting site.title should match ^[A-Z]")
+
+	// The listeners are called after all the values are written
+	seen := map[string]interface{}{}
+	OnSettingChange(func(change SettingChange) {
+		seen[change.Key], _ = GetSetting("site.title", "")
+	})
+	res, err := SetSettings(map[string]interface{}{"site.title": "Shop", "site.email": "hi@yaoapps.com"}, "")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"site.title": "Shop", "site.email": "hi@yaoapps.com"}, res)
+	assert.Equal(t, map[string]interface{}{"site.title": "Shop", "site.email": "Shop"}, seen)
+}
+
+func TestSettingsLazy(t *testing.T) {
+	defer func(s *Settings) {
+		settings = s
+		settingsMemory, settingsCache = map[string]string{}, map[string]*settingCache{}
+	}(settings)
+	assert.Nil(t, RegisterSettings(&Settings{Keys: map[string]*SettingKey{"site.title": {Type: "string", Default: "Yao"}}}))
+
+	// The settings are resolved only if the template reads them
+	parser := NewTemplateParser(Data{}, &ParserOption{Request: &Request{}})
+	_, err := parser.Render(`<html><body><h1>Hello</h1></body></html>`)
+	assert.Nil(t, err)
+	assert.NotContains(t, parser.data, settingKey)
+
+	parser = NewTemplateParser(Data{}, &ParserOption{Request: &Request{}})
+	html, err := parser.Render(`<html><body><h1 title="{{ $setting.site.title }}">Hello</h1></body></html>`)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `<h1 title="Yao">`)
+}
+
+func TestSettingsWidget(t *testing.T) {
+	defer func(s *Settings) {
+		settings = s
+		settingsMemory, settingsCache = map[string]string{}, map[string]*settingCache{}
+	}(settings)
+	max := 100.0
+	assert.Nil(t, RegisterSettings(&Settings{Keys: map[string]*SettingKey{
+		"site.title":   {Type: "string", Default: "Yao", Label: "Site title", Description: "The <title> of the pages", Tenant: true},
+		"site.open":    {Type: "boolean", Default: true},
+		"orders.limit": {Type: "integer", Default: 20, Max: &max},
+		"theme.mode":   {Type: "enum", Default: "light", Options: []interface{}{"light", "dark"}},
+	}}))
+	SetSetting("site.title", "acme", "Acme")
+
+	RegisterWidgets("/__settings", Widgets{"settings.*": nil})
+	defer RegisterWidgets("/__settings", nil)
+
+	render := func(source string) (string, *TemplateParser) {
+		parser := NewTemplateParser(Data{}, &ParserOption{Route: "/__settings/admin", Request: &Request{}})
+		html, err := parser.Render(`<html><body>` + source + `</body></html>`)
+		assert.Nil(t, err)
+		return html, parser
+	}
+
+	html, parser := render(`<s:widget type="settings" name="*" action="/api/settings"></s:widget>`)
+	assert.Empty(t, parser.Errors())
+	assert.Contains(t, html, `<form class="sui-widget sui-widget-settings" data-widget="settings.*" method="post" action="/api/settings">`)
+	assert.Contains(t, html, `<label class="sui-widget-field"><span>orders.limit</span><input type="number" name="orders.limit" value="20" step="1" max="100"/></label>`)
+	assert.Contains(t, html, `<input type="checkbox" name="site.open" value="true" checked=""/>`)
+	assert.Contains(t, html, `<span>Site title</span><input type="text" name="site.title" value="Yao"/><small>The &lt;title&gt; of the pages</small>`)
+	assert.Contains(t, html, `<select name="theme.mode"><option value="light" selected="">light</option><option value="dark">dark</option></select>`)
+
+	// The overrides of the tenant
+	html, _ = render(`<s:widget type="settings" name="site" tenant="acme"></s:widget>`)
+	assert.Contains(t, html, `data-tenant="acme"`)
+	assert.Contains(t, html, `value="Acme"`)
+	assert.NotContains(t, html, `site.open`)
+
+	_, parser = render(`<s:widget type="settings" name="missing"></s:widget>`)
+	assert.NotEmpty(t, parser.Errors())
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderShorthandBinding(t *testing.T) {
+	source := `<html><body>` +
+		`<a :href="link.url" :title="'Go to ' + link.label" class="link">{{ link.label }}</a>` +
+		`<input :value="user.name" :data-user="user" />` +
+		`<svg :viewBox="box"><rect :width="size"></rect></svg>` +
+		`<a href="/old" :href="link.url">Override</a>` +
+		`</body></html>`
+	data := Data{
+		"link": map[string]interface{}{"url": "/docs", "label": "Docs"},
+		"user": map[string]interface{}{"name": "Ada"},
+		"box":  "0 0 10 10",
+		"size": 10,
+	}
+
+	parser := NewTemplateParser(data, &ParserOption{})
+	html, err := parser.Render(source)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `href="/docs"`)
+	assert.Contains(t, html, `s:bind:href="{{ link.url }}"`)
+	assert.Contains(t, html, `title="Go to Docs"`)
+	assert.Contains(t, html, `value="Ada"`)
+	assert.Contains(t, html, `data-user="{&#34;name&#34;:&#34;Ada&#34;}"`)
+	assert.Contains(t, html, `json-attr-data-user="true"`)
+	assert.Contains(t, html, `viewBox="0 0 10 10"`)
+	assert.Contains(t, html, `width="10"`)
+	assert.NotContains(t, html, `href="/old"`)
+	assert.NotContains(t, html, ` :href=`)
+	assert.NotContains(t, html, ` :value=`)
+
+	// The pre-compiled template
+	parser = NewTemplateParser(data, &ParserOption{Route: "/shorthand", Precompile: true})
+	html, err = parser.Render(source)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `href="/docs"`)
+	assert.Contains(t, html, `viewBox="0 0 10 10"`)
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/yaoapp/kun/maps"
+)
+
+// spreadNameRe the attribute names can be spread, the directives and the event handlers are skipped
+var spreadNameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_\-.:]*$`)
+
+// spreadProps the s:props spreading, expand the map of the data into the attributes of the element or the props of the
+// component call, e.g. <a s:props="{{ link.attrs }}">, <div is="/card" s:jit s:props="{{ card }}">.
+// the attributes set on the element win, the keys of the directives (s:) and the event handlers (on*) are skipped
+func (parser *TemplateParser) spreadProps(sel *goquery.Selection) map[string]interface{} {
+	stmt, has := sel.Attr("s:props")
+	if !has {
+		return nil
+	}
+	defer parser.option.Timing.Start("props", "s:props")()
+
+	stmt = strings.TrimSpace(stmt)
+	if strings.HasPrefix(stmt, "{{") && strings.HasSuffix(stmt, "}}") {
+		stmt = strings.TrimSpace(stmt[2 : len(stmt)-2])
+	}
+	res, _, err := parser.data.ExecGuard(stmt, parser.guard)
+	if err != nil {
+		parser.renderError(sel.Nodes[0], "s:props", stmt, err)
+		return nil
+	}
+
+	values := map[string]interface{}{}
+	switch v := res.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		values = v
+	case maps.MapStrAny:
+		values = v
+	default:
+		parser.renderError(sel.Nodes[0], "s:props", stmt, fmt.Errorf("s:props should be an object, got %T", res))
+		return nil
+	}
+
+	props := map[string]interface{}{}
+	for name, value := range values {
+		lower := strings.ToLower(name)
+		if !spreadNameRe.MatchString(name) || strings.HasPrefix(lower, "s:") || strings.HasPrefix(lower, "on") {
+			continue
+		}
+		props[name] = value
+	}
+	return props
+}
+
+// spreadAttrs set the spread attributes of the element, the attributes set on the element are kept
+func (parser *TemplateParser) spreadAttrs(sel *goquery.Selection, props map[string]interface{}) {
+	for _, key := range sortedKeys(props) {
+		name := attrName(sel.Nodes[0], key)
+		if _, has := sel.Attr(name); has {
+			continue
+		}
+		val, json, ok := spreadValue(props[key])
+		if !ok {
+			continue
+		}
+		sel.SetAttr(name, val)
+		if json {
+			sel.SetAttr(fmt.Sprintf("json-attr-%s", name), "true")
+		}
+	}
+}
+
+// spreadValue the attribute value of the spread value, the objects and the arrays are json encoded
+func spreadValue(value interface{}) (string, bool, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, false, true
+
+	case bool, int, float64:
+		return fmt.Sprintf("%v", v), false, true
+
+	case nil:
+		return "", false, true
+
+	default:
+		str, err := jsonStable.MarshalToString(value)
+		if err != nil {
+			return "", false, false
+		}
+		return str, true, true
+	}
+}
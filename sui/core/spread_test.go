@@ -0,0 +1,50 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserSpreadProps(t *testing.T) {
+	data := Data{
+		"link": map[string]interface{}{
+			"href":     "/docs",
+			"target":   "_blank",
+			"title":    "Docs",
+			"data-ids": []interface{}{1, 2},
+			"onclick":  "alert(1)",
+			"s:html":   "<b>",
+		},
+	}
+	parser := NewTemplateParser(data, &ParserOption{})
+	html, err := parser.Render(`<a title="Read the docs" s:props="{{ link }}">Docs</a>`)
+	assert.Nil(t, err)
+	assert.Contains(t, html, `href="/docs"`)
+	assert.Contains(t, html, `target="_blank"`)
+	assert.Contains(t, html, `title="Read the docs"`)
+	assert.Contains(t, html, `data-ids="[1,2]"`)
+	assert.NotContains(t, html, `onclick="`)
+	assert.NotContains(t, html, "&lt;b&gt;")
+
+	parser = NewTemplateParser(Data{"link": "/docs"}, &ParserOption{})
+	_, err = parser.Render(`<a s:props="{{ link }}">Docs</a>`)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, parser.Errors())
+}
+
+func TestParserSpreadPropsComponent(t *testing.T) {
+	Components["/test/spread"] = &JitComponent{
+		route:       "/test/spread",
+		html:        `<div class="card"><h3>{% title %}</h3><p>{% body %}</p></div>`,
+		buildOption: &BuildOption{},
+	}
+	defer delete(Components, "/test/spread")
+
+	data := Data{"card": map[string]interface{}{"title": "Theirs", "body": "The body"}}
+	parser := NewTemplateParser(data, &ParserOption{})
+	html, err := parser.Render(`<div is="/test/spread" s:jit title="Mine" s:props="{{ card }}"></div>`)
+	assert.Nil(t, err)
+	assert.Contains(t, html, "<h3>Mine</h3>")
+	assert.Contains(t, html, "<p>The body</p>")
+}
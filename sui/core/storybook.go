@@ -0,0 +1,135 @@
+package core
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"sort"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/yao/config"
+)
+
+// StoriesFile the sample props of the components in the template root, the key is the route of the component
+//
+//	{
+//	  "/card": [
+//	    {"name": "Default", "props": {"title": "Hello"}},
+//	    {"name": "Long title", "props": {"title": "A very long title of the card"}, "data": {"$global": {}}}
+//	  ]
+//	}
+const StoriesFile = "__stories.json"
+
+// StorybookEnabled serve the storybook in the production mode (YAO_SUI_STORYBOOK=true), the development mode only by default
+var StorybookEnabled = os.Getenv("YAO_SUI_STORYBOOK") == "true"
+
+// Stories the sample props of the components, the key is the route of the component
+type Stories map[string][]*Story
+
+// Story the sample of the component
+type Story struct {
+	Name  string                 `json:"name"`
+	Props map[string]interface{} `json:"props,omitempty"`
+	Data  map[string]interface{} `json:"data,omitempty"` // the data of the rendering, e.g. $global
+}
+
+// Storybook the just-in-time components of the public root and the stories of the components, the designers
+// browse the component library without building the pages
+type Storybook struct {
+	Root       string   `json:"root"`       // the public root of the components
+	Components []string `json:"components"` // the routes of the built components
+	Stories    Stories  `json:"stories"`
+}
+
+// storybookStyle the style of the storybook page
+const storybookStyle = `body{margin:0;font-family:sans-serif;display:flex}` +
+	`.sui-storybook-nav{width:240px;padding:16px;border-right:1px solid #eee;min-height:100vh;box-sizing:border-box}` +
+	`.sui-storybook-nav a{display:block;padding:4px 0;color:#333;text-decoration:none}` +
+	`.sui-storybook-main{flex:1;padding:16px}` +
+	`.sui-story{margin-bottom:32px}.sui-story h3{font-size:14px;color:#666}` +
+	`.sui-story-canvas{padding:16px;border:1px dashed #ccc}[data-sui-hide]{display:none}`
+
+// StorybookMode check if the storybook is served, the development mode or YAO_SUI_STORYBOOK=true
+func StorybookMode() bool {
+	return StorybookEnabled || config.Conf.Mode == "development"
+}
+
+// ParseStories parse the stories file of the template
+func ParseStories(source []byte) (Stories, error) {
+	stories := Stories{}
+	if len(source) == 0 {
+		return stories, nil
+	}
+	if err := jsoniter.Unmarshal(source, &stories); err != nil {
+		return nil, fmt.Errorf("%s %s", StoriesFile, err.Error())
+	}
+	return stories, nil
+}
+
+// stories get the stories of the component, the default story without the props if the component has no stories
+func (sb *Storybook) stories(route string) []*Story {
+	if stories := sb.Stories[route]; len(stories) > 0 {
+		return stories
+	}
+	return []*Story{{Name: "Default"}}
+}
+
+// Render render the components in isolation with the sample props, the component of the route only if it is set.
+// the components are rendered in the editor mode, the errors of the components are kept in the page
+func (sb *Storybook) Render(request *Request, route string) (string, error) {
+	routes := append([]string{}, sb.Components...)
+	sort.Strings(routes)
+	if route != "" {
+		routes = []string{route}
+	}
+
+	var nav, main strings.Builder
+	for _, component := range routes {
+		fmt.Fprintf(&nav, `<a href="?component=%s">%s</a>`, html.EscapeString(component), html.EscapeString(component))
+		fmt.Fprintf(&main, `<h2>%s</h2>`, html.EscapeString(component))
+		for _, story := range sb.stories(component) {
+			source, err := sb.renderStory(request, component, story)
+			if err != nil {
+				source = fmt.Sprintf(`<div class="sui-component-error">%s</div>`, html.EscapeString(err.Error()))
+			}
+			fmt.Fprintf(&main, `<section class="sui-story" data-component="%s"><h3>%s</h3><div class="sui-story-canvas">%s</div></section>`,
+				html.EscapeString(component), html.EscapeString(story.Name), source)
+		}
+	}
+
+	return fmt.Sprintf(`<!DOCTYPE html><html><head><meta charset="utf-8"><title>SUI Storybook</title><style>%s</style></head>`+
+		`<body><nav class="sui-storybook-nav">%s</nav><main class="sui-storybook-main">%s</main></body></html>`,
+		storybookStyle, nav.String(), main.String()), nil
+}
+
+// renderStory render the component with the props of the story, the scripts and the styles of the component are kept
+func (sb *Storybook) renderStory(request *Request, route string, story *Story) (string, error) {
+	doc, err := NewDocumentString(`<html><body><div class="sui-story-root"></div></body></html>`)
+	if err != nil {
+		return "", err
+	}
+
+	root := doc.Find(".sui-story-root")
+	root.AppendHtml(fmt.Sprintf(`<div is="%s" s:jit="true"></div>`, html.EscapeString(route)))
+	callProps(root.Children().First(), story.Props)
+
+	parser := NewTemplateParser(Data(story.Data), &ParserOption{
+		Editor:  true,
+		Debug:   true,
+		Route:   route,
+		Root:    sb.Root,
+		Request: request,
+	})
+	if err := parser.RenderSelection(root); err != nil {
+		return "", err
+	}
+	root.Find("[sui-hide]").Remove()
+	parser.Tidy(root)
+
+	if parser.context != nil {
+		parser.addScripts(root, parser.context.scripts)
+		parser.addStyles(root, parser.context.styles)
+	}
+	return root.Html()
+}
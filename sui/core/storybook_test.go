@@ -0,0 +1,40 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type storyRegistry map[string]string
+
+func (r storyRegistry) Fetch(route string) ([]byte, error) {
+	return []byte(r[route]), nil
+}
+
+func TestStorybookRender(t *testing.T) {
+	RegisterComponentRegistry("/story", storyRegistry{
+		"/story/card": `<div class="card"><h1>[{ title }]</h1><span s:for="[{ tags }]" s:for-item="tag">{{ tag }}</span></div>` +
+			`<script name="option" type="json">{}</script>`,
+	})
+	defer RegisterComponentRegistry("/story", nil)
+
+	stories, err := ParseStories([]byte(`{"/story/card": [{"name": "Default", "props": {"title": "Hello <b>", "tags": ["new", "hot"]}}]}`))
+	assert.Nil(t, err)
+
+	sb := &Storybook{Root: "/demo", Components: []string{"/story/card", "/story/missing"}, Stories: stories}
+	html, err := sb.Render(&Request{}, "")
+	assert.Nil(t, err)
+	assert.Contains(t, html, `<a href="?component=/story/card">/story/card</a>`)
+	assert.Contains(t, html, `<h1>Hello &lt;b&gt;</h1>`)
+	assert.Contains(t, html, `<span data-sui-generate="true">new</span><span data-sui-generate="true">hot</span>`)
+	assert.Contains(t, html, `data-component="/story/missing"><h3>Default</h3>`)
+
+	// The component of the route only
+	html, err = sb.Render(&Request{}, "/story/card")
+	assert.Nil(t, err)
+	assert.NotContains(t, html, "/story/missing")
+
+	_, err = ParseStories([]byte(`[]`))
+	assert.Contains(t, err.Error(), StoriesFile)
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// teleportNodes move the s:teleport="selector" nodes to the target, e.g. s:teleport="head", s:teleport="#modals"
+// the nodes are moved after the main walk, the hidden nodes (the false s:if branches) are not moved.
+// The nodes of the detached trees (e.g. the loop items) are moved by the outer rendering.
+func (parser *TemplateParser) teleportNodes(section *goquery.Selection) {
+	defer parser.option.Timing.Start("teleport", "s:teleport")()
+
+	if parser.option.Editor {
+		return
+	}
+
+	root := section.Nodes[0]
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	if root.Type != html.DocumentNode {
+		return
+	}
+
+	nodes := section.Find(`[s\:teleport]`)
+	if nodes.Length() == 0 {
+		return
+	}
+
+	doc := goquery.NewDocumentFromNode(root)
+	nodes.Each(func(i int, sel *goquery.Selection) {
+		node := sel.Nodes[0]
+		if teleportHidden(node) {
+			return
+		}
+
+		target := sel.AttrOr("s:teleport", "")
+		sel.RemoveAttr("s:teleport")
+		if target == "" {
+			return
+		}
+
+		targetSel := doc.Find(target).First()
+		if targetSel.Length() == 0 {
+			parser.renderError(node, "s:teleport", target, fmt.Errorf("target not found"))
+			return
+		}
+
+		// The target is inside the node
+		for p := targetSel.Nodes[0]; p != nil; p = p.Parent {
+			if p == node {
+				parser.renderError(node, "s:teleport", target, fmt.Errorf("the target is inside the node"))
+				return
+			}
+		}
+
+		node.Parent.RemoveChild(node)
+		targetSel.Nodes[0].AppendChild(node)
+	})
+}
+
+// teleportHidden check if the node or the ancestors are hidden
+func teleportHidden(node *html.Node) bool {
+	for n := node; n != nil; n = n.Parent {
+		if hasAttr(n, "sui-hide") {
+			return true
+		}
+	}
+	return false
+}
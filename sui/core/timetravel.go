@@ -0,0 +1,43 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nowKey the data key of the current time of the rendering, {{ $now }}
+const nowKey = "$now"
+
+// previewTimeLayouts the layouts of the time-travel preview time, the time without the zone is in the local zone
+var previewTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"}
+
+// ParsePreviewTime parse the time of the time-travel preview, the editors render the page as of the time to verify
+// the scheduled contents, e.g. the campaign banners and the embargoed posts. the value is the RFC3339 time, the date
+// (2006-01-02), the date time (2006-01-02 15:04:05) or the unix seconds
+func ParsePreviewTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(n, 0), nil
+	}
+
+	for _, layout := range previewTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("the preview time %s is invalid, e.g. 2006-01-02T15:04:05Z07:00", value)
+}
+
+// Time get the current time of the request, the time of the time-travel preview if set
+func (r *Request) Time() time.Time {
+	if r == nil || r.Now.IsZero() {
+		return time.Now()
+	}
+	return r.Now
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePreviewTime(t *testing.T) {
+	now, err := ParsePreviewTime("2030-01-02T03:04:05Z")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC).Unix(), now.Unix())
+
+	now, err = ParsePreviewTime("2030-01-02")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2030, 1, 2, 0, 0, 0, 0, time.Local).Unix(), now.Unix())
+
+	now, err = ParsePreviewTime("1893456000")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1893456000), now.Unix())
+
+	now, err = ParsePreviewTime("")
+	assert.Nil(t, err)
+	assert.True(t, now.IsZero())
+
+	_, err = ParsePreviewTime("next monday")
+	assert.NotNil(t, err)
+}
+
+func TestParserTimeTravel(t *testing.T) {
+	r := &Request{Now: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)}
+	args, err := r.parseArgs([]interface{}{"$now"})
+	assert.Nil(t, err)
+	assert.Equal(t, "2030-01-02T03:04:05Z", args[0])
+
+	data := Data{"banner": map[string]interface{}{"start": "2030-01-01T00:00:00Z"}}
+	parser := NewTemplateParser(data, &ParserOption{Request: r})
+	html, err := parser.Render(`<html><body><p>{{ $now.Year() }}</p>` +
+		`<div s:if="$now.After(date(banner.start))">Campaign</div></body></html>`)
+	assert.Nil(t, err)
+	assert.Empty(t, parser.Errors())
+	assert.Contains(t, html, `<p>2030</p>`)
+	assert.Contains(t, html, `<div>Campaign</div>`)
+	assert.NotContains(t, html, nowKey) // not sent to the browser
+
+	// The current time
+	parser = NewTemplateParser(data, &ParserOption{Request: &Request{}})
+	html, err = parser.Render(`<html><body><div s:if="$now.After(date(banner.start))">Campaign</div></body></html>`)
+	assert.Nil(t, err)
+	assert.NotContains(t, html, `<div>Campaign</div>`)
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yaoapp/yao/config"
+)
+
+// TimingEnabled emit the Server-Timing header for all the requests (YAO_SUI_SERVER_TIMING=true)
+var TimingEnabled = os.Getenv("YAO_SUI_SERVER_TIMING") == "true"
+
+// ServerTiming the server timing metrics of the rendering phases
+type ServerTiming struct {
+	metrics []*TimingMetric
+	index   map[string]*TimingMetric
+	depth   map[string]int
+	mutex   sync.Mutex
+}
+
+// TimingMetric the metric of the rendering phase
+type TimingMetric struct {
+	Name     string        `json:"name"`
+	Desc     string        `json:"desc,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Count    int           `json:"count"`
+}
+
+// NewServerTiming create a new server timing
+func NewServerTiming() *ServerTiming {
+	return &ServerTiming{metrics: []*TimingMetric{}, index: map[string]*TimingMetric{}, depth: map[string]int{}}
+}
+
+// TimingMode check if the server timing is enabled for the request
+// YAO_SUI_SERVER_TIMING=true or the development mode, the query can not enable it (the timings leak the internals)
+func (r *Request) TimingMode() bool {
+	return TimingEnabled || config.Conf.Mode == "development"
+}
+
+// Start start timing the phase, call the returned function to stop
+// The nested phases with the same name (e.g. the components in the component) are counted once
+func (timing *ServerTiming) Start(name string, desc string) func() {
+	if timing == nil {
+		return func() {}
+	}
+
+	timing.mutex.Lock()
+	timing.depth[name]++
+	outer := timing.depth[name] == 1
+	timing.mutex.Unlock()
+
+	start := time.Now()
+	return func() {
+		duration := time.Since(start)
+		timing.mutex.Lock()
+		timing.depth[name]--
+		timing.mutex.Unlock()
+		if !outer {
+			duration = 0
+		}
+		timing.Add(name, desc, duration)
+	}
+}
+
+// Add add the duration to the phase, the same phase will be accumulated
+func (timing *ServerTiming) Add(name string, desc string, duration time.Duration) {
+	if timing == nil {
+		return
+	}
+
+	timing.mutex.Lock()
+	defer timing.mutex.Unlock()
+	metric, has := timing.index[name]
+	if !has {
+		metric = &TimingMetric{Name: name, Desc: desc}
+		timing.index[name] = metric
+		timing.metrics = append(timing.metrics, metric)
+	}
+	metric.Duration += duration
+	metric.Count++
+}
+
+// Metrics get the metrics in the order of the phases
+func (timing *ServerTiming) Metrics() []TimingMetric {
+	if timing == nil {
+		return nil
+	}
+
+	timing.mutex.Lock()
+	defer timing.mutex.Unlock()
+	res := make([]TimingMetric, 0, len(timing.metrics))
+	for _, metric := range timing.metrics {
+		res = append(res, *metric)
+	}
+	return res
+}
+
+// Header get the value of the Server-Timing header
+// e.g. parse;dur=0.52;desc="Parse", components;dur=3.10;desc="Components (2)"
+func (timing *ServerTiming) Header() string {
+	metrics := timing.Metrics()
+	values := make([]string, 0, len(metrics))
+	for _, metric := range metrics {
+		value := fmt.Sprintf("%s;dur=%.2f", metric.Name, float64(metric.Duration.Microseconds())/1000)
+		desc := metric.Desc
+		if metric.Count > 1 {
+			desc = fmt.Sprintf("%s (%d)", desc, metric.Count)
+		}
+		if desc != "" {
+			value = fmt.Sprintf(`%s;desc="%s"`, value, strings.ReplaceAll(desc, `"`, `'`))
+		}
+		values = append(values, value)
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/yao/config"
+)
+
+func TestServerTiming(t *testing.T) {
+	timing := NewServerTiming()
+	timing.Add("parse", "Parse", 1500*time.Microsecond)
+
+	outer := timing.Start("components", "Components")
+	inner := timing.Start("components", "Components")
+	inner()
+	outer()
+
+	metrics := timing.Metrics()
+	assert.Len(t, metrics, 2)
+	assert.Equal(t, 2, metrics[1].Count)
+	assert.Contains(t, timing.Header(), `parse;dur=1.50;desc="Parse"`)
+	assert.Contains(t, timing.Header(), `desc="Components (2)"`)
+
+	// nil timing is disabled
+	var disabled *ServerTiming
+	disabled.Start("parse", "Parse")()
+	assert.Equal(t, "", disabled.Header())
+}
+
+func TestTimingMode(t *testing.T) {
+	mode, enabled := config.Conf.Mode, TimingEnabled
+	defer func() { config.Conf.Mode, TimingEnabled = mode, enabled }()
+
+	TimingEnabled = false
+	config.Conf.Mode = "production"
+	r := &Request{Query: map[string][]string{"__sui_timing": {""}, "__debug": {""}}}
+	assert.False(t, r.TimingMode())
+
+	config.Conf.Mode = "development"
+	assert.True(t, r.TimingMode())
+
+	config.Conf.Mode = "production"
+	TimingEnabled = true
+	assert.True(t, (&Request{}).TimingMode())
+}
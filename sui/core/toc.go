@@ -0,0 +1,123 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// DefaultTocHeadings the default headings of the table of contents
+const DefaultTocHeadings = "h2,h3"
+
+func (parser *TemplateParser) isToc(sel *goquery.Selection) bool {
+	_, has := sel.Attr("s:toc")
+	return has
+}
+
+// tocNode the s:toc directive, render the children, inject the stable slug ids to the headings
+// and expose the nested table of contents as $toc (or the s:toc-var name) to the following nodes.
+//
+//	<article s:toc="h2,h3">...</article>
+//	<nav s:teleport="#sidebar"><a s:for="$toc" href="#{{ item.id }}">{{ item.text }}</a></nav>
+//
+// each item has the id, text, level and children fields
+func (parser *TemplateParser) tocNode(sel *goquery.Selection) {
+	defer parser.option.Timing.Start("toc", "s:toc")()
+	node := sel.Nodes[0]
+	headings := strings.TrimSpace(sel.AttrOr("s:toc", ""))
+	if headings == "" {
+		headings = DefaultTocHeadings
+	}
+
+	name := sel.AttrOr("s:toc-var", "$toc")
+	if name == "" {
+		name = "$toc"
+	}
+
+	// Render the children, the replacements of the subtree are applied before the headings are scanned
+	// (the children of the s:once node are rendered already)
+	if !parser.isOnce(sel) {
+		offset := len(parser.replace)
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			parser.parseNode(child)
+		}
+		parser.applyReplaceFrom(offset)
+	}
+
+	used := map[string]bool{}
+	sel.Find("[id]").Each(func(i int, s *goquery.Selection) {
+		used[s.AttrOr("id", "")] = true
+	})
+
+	root := []interface{}{}
+	stack := []map[string]interface{}{}
+	sel.Find(headings).Each(func(i int, heading *goquery.Selection) {
+		if teleportHidden(heading.Nodes[0]) {
+			return
+		}
+
+		text := strings.Join(strings.Fields(heading.Text()), " ")
+		id := heading.AttrOr("id", "")
+		if id == "" {
+			id = uniqueSlug(Slugify(text), used)
+			heading.SetAttr("id", id)
+		}
+
+		item := map[string]interface{}{"id": id, "text": text, "level": headingLevel(heading.Nodes[0]), "children": []interface{}{}}
+		for len(stack) > 0 && stack[len(stack)-1]["level"].(int) >= item["level"].(int) {
+			stack = stack[:len(stack)-1]
+		}
+
+		if len(stack) == 0 {
+			root = append(root, item)
+		} else {
+			parent := stack[len(stack)-1]
+			parent["children"] = append(parent["children"].([]interface{}), item)
+		}
+		stack = append(stack, item)
+	})
+
+	parser.setVar(name, root)
+}
+
+// Slugify get the slug of the text, the letters and the digits are kept, e.g. "Hello, World!" => "hello-world"
+func Slugify(text string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
+
+// uniqueSlug get the unique slug, the suffix is appended if the slug is used, e.g. intro, intro-2
+func uniqueSlug(slug string, used map[string]bool) string {
+	if slug == "" {
+		slug = "section"
+	}
+
+	id := slug
+	for i := 2; used[id]; i++ {
+		id = fmt.Sprintf("%s-%d", slug, i)
+	}
+	used[id] = true
+	return id
+}
+
+func headingLevel(node *html.Node) int {
+	if len(node.Data) == 2 && node.Data[0] == 'h' && node.Data[1] >= '1' && node.Data[1] <= '6' {
+		return int(node.Data[1] - '0')
+	}
+	return 0
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"context"
+	"sync"
+)
+
+// Tracer the tracer of the render pipeline, the spans of the rendering, the components, the loops and the backend
+// script calls are sent to the existing tracing backend. The OpenTelemetry tracer is adapted by the host, e.g.
+//
+//	type otelTracer struct{ trace.Tracer }
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, core.Span) {
+//		ctx, span := t.Tracer.Start(ctx, name)
+//		return ctx, otelSpan{span}
+//	}
+//	core.SetTracer(otelTracer{otel.Tracer("sui")})
+//
+// No span is created if the tracer is not set
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span the span of the render phase
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+var tracer Tracer
+var tracerMutex sync.RWMutex
+
+// SetTracer set the tracer of the render pipeline, nil to disable the spans
+func SetTracer(t Tracer) {
+	tracerMutex.Lock()
+	defer tracerMutex.Unlock()
+	tracer = t
+}
+
+func getTracer() Tracer {
+	tracerMutex.RLock()
+	defer tracerMutex.RUnlock()
+	return tracer
+}
+
+// startSpan start the span, the child of the span in the context. the attributes are the key-value pairs.
+// call the returned function with the error to end the span
+func startSpan(ctx context.Context, name string, attrs ...interface{}) (context.Context, Span, func(err error)) {
+	t := getTracer()
+	if t == nil {
+		return ctx, nil, func(error) {}
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx, span := t.Start(ctx, name)
+	for i := 0; i+1 < len(attrs); i += 2 {
+		if key, ok := attrs[i].(string); ok {
+			span.SetAttribute(key, attrs[i+1])
+		}
+	}
+
+	return ctx, span, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}
+}
+
+// startSpan start the span of the render phase, the child of the current span.
+// the render errors of the phase are recorded to the span, call the returned function with the error to end the span
+func (parser *TemplateParser) startSpan(name string, attrs ...interface{}) func(err error) {
+	parent := parser.spanCtx
+	if parent == nil {
+		parent = parser.ctx
+	}
+
+	ctx, span, end := startSpan(parent, name, attrs...)
+	if span == nil {
+		return end
+	}
+
+	outerCtx, outer := parser.spanCtx, parser.span
+	parser.spanCtx, parser.span = ctx, span
+	return func(err error) {
+		end(err)
+		parser.spanCtx, parser.span = outerCtx, outer
+	}
+}
+
+// spanAttr set the attribute of the current span
+func (parser *TemplateParser) spanAttr(key string, value interface{}) {
+	if parser.span != nil {
+		parser.span.SetAttribute(key, value)
+	}
+}
+
+// spanError record the error to the current span
+func (parser *TemplateParser) spanError(err error) {
+	if parser.span != nil && err != nil {
+		parser.span.RecordError(err)
+	}
+}
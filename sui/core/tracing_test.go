@@ -0,0 +1,97 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testSpanKey struct{}
+
+type testSpan struct {
+	name   string
+	parent string
+	attrs  map[string]interface{}
+	errors []error
+	ended  bool
+}
+
+func (span *testSpan) SetAttribute(key string, value interface{}) { span.attrs[key] = value }
+func (span *testSpan) RecordError(err error)                      { span.errors = append(span.errors, err) }
+func (span *testSpan) End()                                       { span.ended = true }
+
+type testTracer struct {
+	mutex sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	span := &testSpan{name: name, attrs: map[string]interface{}{}}
+	if parent, ok := ctx.Value(testSpanKey{}).(*testSpan); ok {
+		span.parent = parent.name
+	}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func (t *testTracer) find(name string) []*testSpan {
+	spans := []*testSpan{}
+	for _, span := range t.spans {
+		if span.name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func TestParserTracing(t *testing.T) {
+	tracer := &testTracer{}
+	SetTracer(tracer)
+	defer SetTracer(nil)
+
+	Components["/test/traced"] = &JitComponent{
+		route:       "/test/traced",
+		html:        `<ul><li s:for="{{ [1,2,3] }}">{{ item }}</li><li s:for="{{ missing() }}"></li></ul>`,
+		buildOption: &BuildOption{},
+	}
+	defer delete(Components, "/test/traced")
+
+	source := `<html><head></head><body><div is="/test/traced" s:jit></div></body></html>`
+	parser := NewTemplateParser(Data{}, &ParserOption{Request: &Request{}, Route: "/index"})
+	html, err := parser.Render(source)
+	assert.Nil(t, err)
+	assert.Contains(t, html, "<li>3</li>")
+
+	renders := tracer.find("sui.render")
+	if assert.Len(t, renders, 1) {
+		assert.Equal(t, "/index", renders[0].attrs["sui.route"])
+		assert.Equal(t, "", renders[0].parent)
+		assert.True(t, renders[0].ended)
+	}
+
+	components := tracer.find("sui.component")
+	if assert.Len(t, components, 1) {
+		assert.Equal(t, "sui.render", components[0].parent)
+		assert.True(t, components[0].ended)
+	}
+
+	loops := tracer.find("sui.for")
+	if assert.Len(t, loops, 2) {
+		assert.Equal(t, "sui.component", loops[0].parent)
+		assert.Equal(t, 3, loops[0].attrs["sui.loop.size"])
+		assert.Empty(t, loops[0].errors)
+		assert.NotEmpty(t, loops[1].errors)
+	}
+
+	// No spans without the tracer
+	SetTracer(nil)
+	count := len(tracer.spans)
+	parser = NewTemplateParser(Data{}, &ParserOption{Request: &Request{}})
+	_, err = parser.Render(source)
+	assert.Nil(t, err)
+	assert.Len(t, tracer.spans, count)
+}
@@ -0,0 +1,242 @@
+package core
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/yaoapp/kun/log"
+)
+
+// TrackEndpoint the endpoint of the analytics collector
+var TrackEndpoint = "/api/__yao/sui/v1/track"
+
+// TrackTokenTTL the lifetime of the track token of the rendered page
+var TrackTokenTTL = 24 * time.Hour
+
+// MaxTrackEvents the max events of one request posted to the track endpoint
+var MaxTrackEvents = 20
+
+// MaxTrackProps the max props of one event
+var MaxTrackProps = 32
+
+// MaxTrackValue the max length of the event name and the string props
+var MaxTrackValue = 1024
+
+// TrackRateLimit the max requests of one client to the track endpoint in a minute, 0 is unlimited
+var TrackRateLimit = 120
+
+var trackClients = map[string]*trackWindow{}
+var trackClientsMutex sync.Mutex
+
+type trackWindow struct {
+	start int64
+	count int
+}
+
+// TrackEvent the analytics event annotated by the s:track-* attributes
+type TrackEvent struct {
+	ID    string                 `json:"id,omitempty"`
+	Name  string                 `json:"name"`
+	On    string                 `json:"on,omitempty"`
+	Route string                 `json:"route,omitempty"`
+	Time  int64                  `json:"time,omitempty"`
+	Props map[string]interface{} `json:"props,omitempty"`
+}
+
+// TrackCollector the analytics collector, receive the events posted by the client runtime
+type TrackCollector func(events []TrackEvent, r *Request) error
+
+var trackCollectors = map[string]TrackCollector{}
+var trackCollectorsMutex sync.RWMutex
+
+// RegisterTrackCollector register the analytics collector
+func RegisterTrackCollector(name string, collector TrackCollector) {
+	trackCollectorsMutex.Lock()
+	defer trackCollectorsMutex.Unlock()
+	trackCollectors[name] = collector
+}
+
+// RemoveTrackCollector remove the analytics collector
+func RemoveTrackCollector(name string) {
+	trackCollectorsMutex.Lock()
+	defer trackCollectorsMutex.Unlock()
+	delete(trackCollectors, name)
+}
+
+// VerifyTrack verify the events posted to the track endpoint, the token is signed by the rendered page,
+// the events are limited by MaxTrackEvents, MaxTrackProps and MaxTrackValue, the clients by TrackRateLimit
+func VerifyTrack(token string, events []TrackEvent, r *Request) error {
+	route, err := parseTrackToken(token)
+	if err != nil {
+		return err
+	}
+
+	if !trackAllow(r.remoteIP()) {
+		return fmt.Errorf("track rate limit exceeded")
+	}
+
+	if len(events) == 0 || len(events) > MaxTrackEvents {
+		return fmt.Errorf("track events must be 1 to %d", MaxTrackEvents)
+	}
+
+	for i := range events {
+		event := &events[i]
+		if event.Name == "" || len(event.Name) > MaxTrackValue {
+			return fmt.Errorf("track event name must be 1 to %d characters", MaxTrackValue)
+		}
+		if len(event.Props) > MaxTrackProps {
+			return fmt.Errorf("track event %s has more than %d props", event.Name, MaxTrackProps)
+		}
+		for key, value := range event.Props {
+			if len(key) > MaxTrackValue {
+				return fmt.Errorf("track event %s prop is too long", event.Name)
+			}
+			if s, ok := value.(string); ok && len(s) > MaxTrackValue {
+				return fmt.Errorf("track event %s prop %s is too long", event.Name, key)
+			}
+		}
+		event.Route = route // the route is signed, not trusted from the client
+	}
+	return nil
+}
+
+// trackToken sign the route of the rendered page, base64(route).expires.hmac
+func trackToken(route string) string {
+	payload := fmt.Sprintf("%s.%d", base64.RawURLEncoding.EncodeToString([]byte(route)), time.Now().Add(TrackTokenTTL).Unix())
+	return payload + "." + formSign("track:"+payload)
+}
+
+func parseTrackToken(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || !hmac.Equal([]byte(formSign("track:"+parts[0]+"."+parts[1])), []byte(parts[2])) {
+		return "", fmt.Errorf("track token is invalid")
+	}
+
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return "", fmt.Errorf("track token is expired")
+	}
+
+	route, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", fmt.Errorf("track token is invalid")
+	}
+	return string(route), nil
+}
+
+// trackAllow count the request of the client in the current minute, the stale windows are removed
+func trackAllow(client string) bool {
+	if TrackRateLimit <= 0 {
+		return true
+	}
+
+	trackClientsMutex.Lock()
+	defer trackClientsMutex.Unlock()
+	now := time.Now().Unix()
+	window, has := trackClients[client]
+	if !has || now-window.start >= 60 {
+		if len(trackClients) > 10000 {
+			for key, w := range trackClients {
+				if now-w.start >= 60 {
+					delete(trackClients, key)
+				}
+			}
+		}
+		window = &trackWindow{start: now}
+		trackClients[client] = window
+	}
+
+	window.count++
+	return window.count <= TrackRateLimit
+}
+
+// CollectTrack send the events to all the registered collectors
+func CollectTrack(events []TrackEvent, r *Request) error {
+	trackCollectorsMutex.RLock()
+	defer trackCollectorsMutex.RUnlock()
+
+	if len(trackCollectors) == 0 {
+		log.Trace("[SUI] Track %d events, no collector registered", len(events))
+		return nil
+	}
+
+	errs := []string{}
+	for name, collector := range trackCollectors {
+		if err := collector(events, r); err != nil {
+			log.Error("[SUI] Track collector %s: %s", name, err.Error())
+			errs = append(errs, fmt.Sprintf("%s: %s", name, err.Error()))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("track collector error: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Parse the s:track, s:track-on and s:track-<prop> attributes
+func (parser *TemplateParser) trackElementNode(sel *goquery.Selection) {
+	defer parser.option.Timing.Start("track", "s:track")()
+	name := sel.AttrOr("s:track", "")
+	if name == "" {
+		return
+	}
+
+	event := TrackEvent{
+		ID:    fmt.Sprintf("track-%s", parser.nextKey(sel.Nodes[0], name)),
+		On:    "click",
+		Props: map[string]interface{}{},
+	}
+	event.Name, _ = parser.data.ReplaceGuard(name, parser.guard)
+
+	for _, attr := range sel.Nodes[0].Attr {
+		if !strings.HasPrefix(attr.Key, "s:track-") || attr.Key == "s:track-id" {
+			continue
+		}
+
+		key := strings.TrimPrefix(attr.Key, "s:track-")
+		if key == "on" {
+			event.On = attr.Val
+			continue
+		}
+
+		val, values := parser.data.ReplaceGuard(attr.Val, parser.guard)
+		if HasJSON(values) {
+			event.Props[ToCamelCase(key)] = ValueJSON(val)
+			continue
+		}
+		event.Props[ToCamelCase(key)] = val
+	}
+
+	sel.SetAttr("s:track-id", event.ID)
+	parser.tracks = append(parser.tracks, event)
+}
+
+func (parser *TemplateParser) trackInjectionScript() string {
+	if len(parser.tracks) == 0 {
+		return ""
+	}
+
+	events := map[string]TrackEvent{}
+	for _, event := range parser.tracks {
+		events[event.ID] = event
+	}
+
+	raw, err := jsonStable.MarshalToString(map[string]interface{}{
+		"endpoint": TrackEndpoint,
+		"route":    parser.option.Route,
+		"token":    trackToken(parser.option.Route),
+		"events":   events,
+	})
+	if err != nil {
+		log.Error("[SUI] Track %s", err.Error())
+		return ""
+	}
+	return trackInjectionScript(raw)
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParserTrack(t *testing.T) {
+	source := `<html><head></head><body>` +
+		`<button s:track="signup" s:track-plan="{{ plan }}">Sign up</button>` +
+		`<div s:cn="Card"><a s:track="card-open">Open</a></div>` +
+		`</body></html>`
+
+	parser := NewTemplateParser(Data{"plan": "pro"}, &ParserOption{Route: "/pricing", Request: &Request{}})
+	html, err := parser.Render(source)
+	assert.Nil(t, err)
+
+	// The events of the components are merged back to the page
+	assert.Len(t, parser.tracks, 2)
+	assert.Contains(t, html, `"name":"signup"`)
+	assert.Contains(t, html, `"name":"card-open"`)
+	assert.Contains(t, html, `"token":"`)
+}
+
+func TestVerifyTrack(t *testing.T) {
+	limit := TrackRateLimit
+	defer func() { TrackRateLimit = limit }()
+
+	token := trackToken("/pricing")
+	r := &Request{Remote: "203.0.113.10:5000"}
+	events := []TrackEvent{{Name: "signup", Route: "/forged", Props: map[string]interface{}{"plan": "pro"}}}
+	assert.Nil(t, VerifyTrack(token, events, r))
+	assert.Equal(t, "/pricing", events[0].Route)
+
+	// The token is required
+	assert.NotNil(t, VerifyTrack("", events, r))
+	assert.NotNil(t, VerifyTrack(strings.Replace(token, ".", "x.", 1), events, r))
+
+	// The limits of the events
+	assert.NotNil(t, VerifyTrack(token, []TrackEvent{}, r))
+	assert.NotNil(t, VerifyTrack(token, make([]TrackEvent, MaxTrackEvents+1), r))
+	assert.NotNil(t, VerifyTrack(token, []TrackEvent{{Name: strings.Repeat("x", MaxTrackValue+1)}}, r))
+	assert.NotNil(t, VerifyTrack(token, []TrackEvent{{Name: "x", Props: map[string]interface{}{"v": strings.Repeat("x", MaxTrackValue+1)}}}, r))
+	props := map[string]interface{}{}
+	for i := 0; i <= MaxTrackProps; i++ {
+		props[fmt.Sprintf("p%d", i)] = i
+	}
+	assert.NotNil(t, VerifyTrack(token, []TrackEvent{{Name: "x", Props: props}}, r))
+
+	// The rate limit of the client
+	TrackRateLimit = 2
+	r = &Request{Remote: "203.0.113.11:5000"}
+	events = []TrackEvent{{Name: "signup"}}
+	assert.Nil(t, VerifyTrack(token, events, r))
+	assert.Nil(t, VerifyTrack(token, events, r))
+	assert.NotNil(t, VerifyTrack(token, events, r))
+	assert.Nil(t, VerifyTrack(token, events, &Request{Remote: "203.0.113.12:5000"}))
+}
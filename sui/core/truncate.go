@@ -0,0 +1,163 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DefaultEllipsis the default suffix of the truncated text
+const DefaultEllipsis = "…"
+
+// Graphemes split the text into the user-perceived characters (the grapheme clusters),
+// the combining marks, the emoji modifiers, the variation selectors, the ZWJ sequences and the flags are kept together
+func Graphemes(text string) []string {
+	runes := []rune(text)
+	clusters := []string{}
+	for i := 0; i < len(runes); {
+		start := i
+		i++
+
+		// Regional indicator pair (the flags)
+		if isRegionalIndicator(runes[start]) && i < len(runes) && isRegionalIndicator(runes[i]) {
+			i++
+		}
+
+		// CR LF
+		if runes[start] == '\r' && i < len(runes) && runes[i] == '\n' {
+			i++
+		}
+
+		for i < len(runes) {
+			r := runes[i]
+			if isGraphemeExtend(r) {
+				i++
+				continue
+			}
+
+			// ZWJ sequence, e.g. 👨‍👩‍👧
+			if r == 0x200D {
+				i++
+				if i < len(runes) {
+					i++
+				}
+				continue
+			}
+			break
+		}
+		clusters = append(clusters, string(runes[start:i]))
+	}
+	return clusters
+}
+
+// Truncate truncate the text to the length of the grapheme clusters, the suffix is appended if the text is truncated
+// the words are kept if the word boundary mode is enabled (the CJK characters can be broken anywhere)
+func Truncate(text string, length int, suffix string, words bool) string {
+	if length <= 0 {
+		return ""
+	}
+
+	clusters := Graphemes(text)
+	if len(clusters) <= length {
+		return text
+	}
+
+	suffixLen := len(Graphemes(suffix))
+	size := length - suffixLen
+	if size <= 0 {
+		return strings.Join(clusters[:length], "")
+	}
+
+	if words {
+		cut := size
+		for cut > 0 && !isWordBoundary(clusters, cut) {
+			cut--
+		}
+		if cut > 0 {
+			size = cut
+		}
+	}
+
+	res := strings.TrimRightFunc(strings.Join(clusters[:size], ""), unicode.IsSpace)
+	return res + suffix
+}
+
+// isWordBoundary check if the text can be broken before the cluster at the index
+func isWordBoundary(clusters []string, idx int) bool {
+	prev := []rune(clusters[idx-1])
+	next := []rune(clusters[idx])
+	if unicode.IsSpace(prev[len(prev)-1]) || unicode.IsSpace(next[0]) {
+		return true
+	}
+	return isCJK(prev[0]) || isCJK(next[0])
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// isGraphemeExtend the runes extend the previous cluster
+func isGraphemeExtend(r rune) bool {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return true
+	case r >= 0xFE00 && r <= 0xFE0F: // variation selectors
+		return true
+	case r >= 0xE0100 && r <= 0xE01EF: // variation selectors supplement
+		return true
+	case r >= 0x1F3FB && r <= 0x1F3FF: // emoji skin tone modifiers
+		return true
+	case r >= 0xE0020 && r <= 0xE007F: // tags (the subdivision flags)
+		return true
+	}
+	return false
+}
+
+// _truncate Truncate(text, length, [suffix]) the expression function
+func _truncate(args ...any) (interface{}, error) {
+	return truncateArgs("Truncate", false, "", args...)
+}
+
+// _truncateWords TruncateWords(text, length, [suffix]) the expression function, keep the words
+func _truncateWords(args ...any) (interface{}, error) {
+	return truncateArgs("TruncateWords", true, DefaultEllipsis, args...)
+}
+
+// _ellipsis Ellipsis(text, length) the expression function, truncate the text with …
+func _ellipsis(args ...any) (interface{}, error) {
+	return truncateArgs("Ellipsis", false, DefaultEllipsis, args...)
+}
+
+func truncateArgs(name string, words bool, suffix string, args ...any) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("%s should have at least two parameters", name)
+	}
+
+	text := ""
+	switch v := args[0].(type) {
+	case nil:
+		return "", nil
+	case string:
+		text = v
+	default:
+		text = fmt.Sprintf("%v", v)
+	}
+
+	length, ok := loopNumber(args[1])
+	if !ok {
+		return nil, fmt.Errorf("%s the length should be a number", name)
+	}
+
+	if len(args) > 2 {
+		s, ok := args[2].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s the suffix should be a string", name)
+		}
+		suffix = s
+	}
+	return Truncate(text, int(length), suffix, words), nil
+}
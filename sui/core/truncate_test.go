@@ -0,0 +1,29 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphemes(t *testing.T) {
+	assert.Equal(t, []string{"a", "e\u0301", "中"}, Graphemes("ae\u0301中"))
+	assert.Len(t, Graphemes("👨‍👩‍👧🇨🇳👍🏽"), 3)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "Hello", Truncate("Hello", 10, DefaultEllipsis, false))
+	assert.Equal(t, "Hello W…", Truncate("Hello World", 8, DefaultEllipsis, false))
+	assert.Equal(t, "Hello…", Truncate("Hello World", 8, DefaultEllipsis, true))
+	assert.Equal(t, "你好世…", Truncate("你好世界和平", 4, DefaultEllipsis, true))
+	assert.Equal(t, "👨‍👩‍👧🇨🇳", Truncate("👨‍👩‍👧🇨🇳👍🏽", 2, "", false))
+
+	data := Data{"title": "Yao is a low-code engine"}
+	res, _, err := data.Exec(`Ellipsis(title, 10)`)
+	assert.Nil(t, err)
+	assert.Equal(t, "Yao is a…", res)
+
+	res, _, err = data.Exec(`TruncateWords(title, 14, "...")`)
+	assert.Nil(t, err)
+	assert.Equal(t, "Yao is a...", res)
+}
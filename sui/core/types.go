@@ -3,6 +3,7 @@ package core
 import (
 	"net/url"
 	"regexp"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"golang.org/x/net/html"
@@ -10,14 +11,27 @@ import (
 
 // DSL the struct for the DSL
 type DSL struct {
-	ID         string   `json:"-"`
-	Name       string   `json:"name,omitempty"`
-	Guard      string   `json:"guard,omitempty"`
-	Storage    *Storage `json:"storage,omitempty"`
-	Public     *Public  `json:"public,omitempty"`
-	CacheStore string   `json:"cache_store,omitempty"` // The cache store
-	Sid        string   `json:"-"`
-	publicRoot string   `json:"-"`
+	ID          string                        `json:"-"`
+	Name        string                        `json:"name,omitempty"`
+	Guard       string                        `json:"guard,omitempty"`
+	Storage     *Storage                      `json:"storage,omitempty"`
+	Public      *Public                       `json:"public,omitempty"`
+	CacheStore  string                        `json:"cache_store,omitempty"` // The cache store
+	Restricted  map[string]*RestrictedProfile `json:"restricted,omitempty"`  // The restricted profiles imposed on the routes, the key is the route prefix
+	Routing     *RoutingPolicy                `json:"routing,omitempty"`     // The URL normalization and the redirects of the pages
+	I18n        *LocaleRouting                `json:"i18n,omitempty"`        // The locale prefixes and the localized slugs of the routes
+	Engine      string                        `json:"engine,omitempty"`      // The engine versions of the app, e.g. ">=0.10.4 <0.11.0"
+	Visibility  Visibility                    `json:"visibility,omitempty"`  // The visibility rules of the fields in the page data sent to the browser
+	Widgets     Widgets                       `json:"widgets,omitempty"`     // The admin widgets can be embedded in the pages, see <s:widget>
+	Functions   map[string]*ProcessFunction   `json:"functions,omitempty"`   // The expression functions proxy to the processes, e.g. {{ Price(item.price) }}
+	CSRF        *CSRF                         `json:"csrf,omitempty"`        // The CSRF protection of the form submissions and the backend calls
+	Budgets     Budgets                       `json:"budgets,omitempty"`     // The performance budgets of the built pages, checked after the build
+	Registries  map[string]*RegistryOption    `json:"registries,omitempty"`  // The registries of the shared just-in-time components, the key is the route prefix
+	Menus       *MenuOption                   `json:"menus,omitempty"`       // The hierarchical menus stored in the model, e.g. {{ $menu("main") }}
+	Settings    *Settings                     `json:"settings,omitempty"`    // The typed settings of the app, e.g. {{ $setting.site.title }}
+	Preferences *Preferences                  `json:"preferences,omitempty"` // The per-user preferences, e.g. {{ $pref.ui.theme }}
+	Sid         string                        `json:"-"`
+	publicRoot  string                        `json:"-"`
 }
 
 // Setting is the struct for the setting
@@ -40,6 +54,7 @@ type Page struct {
 	Codes      SourceCodes         `json:"-"`
 	Script     *Script             `json:"-"` // The backend script  name.backend.ts / name.backend.js
 	Document   []byte              `json:"-"`
+	Layouts    map[string][]byte   `json:"-"` // the layouts of the template, see ComposeLayout
 	GlobalData []byte              `json:"-"`
 	Attrs      map[string]string   `json:"-"`
 	Attributes []html.Attribute    `json:"-"`
@@ -109,6 +124,7 @@ type StyleNode struct {
 type GlobalBuildContext struct {
 	jitComponents map[string]bool
 	tmpl          ITemplate
+	manifest      *BuildManifest
 }
 
 // Translation is the struct for the translation
@@ -128,6 +144,7 @@ type Locale struct {
 	ScriptMessages map[string]string `json:"script_messages,omitempty" yaml:"script_messages,omitempty"`
 	Direction      string            `json:"direction,omitempty" yaml:"direction,omitempty"`
 	Timezone       string            `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	version        uint64
 }
 
 // PageTreeNode is the struct for the page tree node
@@ -174,19 +191,22 @@ type LayoutItem struct {
 
 // Template is the struct for the template
 type Template struct {
-	Version      int              `json:"version"` // Yao Builder version
-	ID           string           `json:"id"`
-	Name         string           `json:"name"`
-	Descrption   string           `json:"description"`
-	Screenshots  []string         `json:"screenshots"`
-	Themes       []SelectOption   `json:"themes"`
-	Locales      []SelectOption   `json:"locales"`
-	Document     []byte           `json:"-"`
-	GlobalData   []byte           `json:"-"`
-	Scripts      *TemplateScirpts `json:"scripts,omitempty"`
-	Translator   string           `json:"translator,omitempty"`
-	BuildScript  *Script          `json:"-"` // __build.backend.ts / __build.backend.js
-	GlobalScript *Script          `json:"-"` // __global.backend.ts / __global.backend.js
+	Version      int                 `json:"version"` // Yao Builder version
+	ID           string              `json:"id"`
+	Name         string              `json:"name"`
+	Descrption   string              `json:"description"`
+	Screenshots  []string            `json:"screenshots"`
+	Themes       []SelectOption      `json:"themes"`
+	Locales      []SelectOption      `json:"locales"`
+	Document     []byte              `json:"-"`
+	Layouts      map[string][]byte   `json:"-"` // __layouts/*.html, the layouts of the pages, see ComposeLayout
+	GlobalData   []byte              `json:"-"`
+	Stories      []byte              `json:"-"` // __stories.json, the sample props of the components, see Storybook
+	Scripts      *TemplateScirpts    `json:"scripts,omitempty"`
+	Translator   string              `json:"translator,omitempty"`
+	Remotes      []*RemoteComponents `json:"remotes,omitempty"` // the remote component sources, see RemoteComponents
+	BuildScript  *Script             `json:"-"`                 // __build.backend.ts / __build.backend.js
+	GlobalScript *Script             `json:"-"`                 // __global.backend.ts / __global.backend.js
 }
 
 // TemplateScirpts is the struct for the template scripts
@@ -274,6 +294,9 @@ type BuildOption struct {
 	StyleMinify     bool                   `json:"styleminify,omitempty"`
 	ExecScripts     bool                   `json:"exec_scripts,omitempty"`
 	Locales         []string               `json:"locales,omitempty"`
+	Profile         bool                   `json:"profile,omitempty"`       // profile the preview renders of the pages, see ProfileReportFile
+	WebComponents   []string               `json:"webcomponents,omitempty"` // the route patterns of the components exported as the custom elements, see WebComponentsFile
+	Env             string                 `json:"env,omitempty"`           // the environment of the build-time directives, the mode of the app by default, see CommentsEnv
 }
 
 // Request is the struct for the request
@@ -284,6 +307,8 @@ type Request struct {
 	Payload   map[string]interface{} `json:"payload,omitempty"`
 	Query     url.Values             `json:"query,omitempty"`
 	Params    map[string]string      `json:"params,omitempty"`
+	Values    map[string]interface{} `json:"-"` // the typed params, see RouteParam
+	Localized *LocaleRoute           `json:"-"` // the locale of the route prefix, see LocaleRouting
 	Headers   url.Values             `json:"headers,omitempty"`
 	Body      interface{}            `json:"body,omitempty"`
 	URL       ReqeustURL             `json:"url,omitempty"`
@@ -291,6 +316,11 @@ type Request struct {
 	Theme     any                    `json:"theme,omitempty"`
 	Locale    any                    `json:"locale,omitempty"`
 	Script    *Script                `json:"-"`
+	Remote    string                 `json:"-"`                 // the remote address of the connection, the proxy headers are trusted if it is a trusted proxy
+	Models    map[string]string      `json:"-"`                 // the data paths and the models of the data sources, see DataModels
+	CSRF      string                 `json:"-"`                 // the seed of the CSRF token, see CSRFCookie
+	Now       time.Time              `json:"-"`                 // the time of the time-travel preview, zero for the current time, see ParsePreviewTime
+	Country   string                 `json:"country,omitempty"` // the country of the visitor, see GeoCountry
 }
 
 // RequestSource is the struct for the request
@@ -353,6 +383,7 @@ type PageMock struct {
 	Body    interface{}            `json:"body,omitempty"`
 	URL     ReqeustURL             `json:"url,omitempty"`
 	Sid     string                 `json:"sid,omitempty"`
+	Live    bool                   `json:"live,omitempty"` // Use the live data instead of the fixtures (name.mock.json) in the editor and the preview
 }
 
 // ReqeustURL is the struct for the request
@@ -373,15 +404,23 @@ type PageConfig struct {
 
 // PageSetting is the struct for the page setting
 type PageSetting struct {
-	Title       string   `json:"title,omitempty"`
-	Guard       string   `json:"guard,omitempty"`
-	CacheStore  string   `json:"cacheStore,omitempty"`
-	Cache       int      `json:"cache,omitempty"`
-	Root        string   `json:"root,omitempty"`
-	DataCache   int      `json:"dataCache,omitempty"`
-	Description string   `json:"description,omitempty"`
-	SEO         *PageSEO `json:"seo,omitempty"`
-	API         *PageAPI `json:"api,omitempty"`
+	Title       string         `json:"title,omitempty"`
+	Guard       string         `json:"guard,omitempty"`
+	CacheStore  string         `json:"cacheStore,omitempty"`
+	Cache       int            `json:"cache,omitempty"`
+	Root        string         `json:"root,omitempty"`
+	DataCache   int            `json:"dataCache,omitempty"`
+	Description string         `json:"description,omitempty"`
+	SEO         *PageSEO       `json:"seo,omitempty"`
+	API         *PageAPI       `json:"api,omitempty"`
+	Embed       *PageEmbed     `json:"embed,omitempty"`
+	Mask        []MaskRule     `json:"mask,omitempty"`
+	Precompile  bool           `json:"precompile,omitempty"`
+	Minify      bool           `json:"minify,omitempty"`
+	Delimiters  []string       `json:"delimiters,omitempty"` // the statement delimiters of the page, e.g. ["[[", "]]"]
+	Layout      string         `json:"layout,omitempty"`     // the layout of the page, e.g. "admin" is __layouts/admin.html
+	Hydration   *PageHydration `json:"hydration,omitempty"`  // the hydration payload of the page, e.g. {"partial": true}
+	Bindings    bool           `json:"bindings,omitempty"`   // embed the binding map of the page for the client runtimes, see PageBindings
 }
 
 // PageConfigRendered is the struct for the page config rendered
@@ -397,6 +436,14 @@ type PageAPI struct {
 	Guards       map[string]string `json:"guards,omitempty"`
 }
 
+// PageEmbed is the struct for the page embed mode (render in the iframe of third-party sites)
+type PageEmbed struct {
+	Enabled  bool     `json:"enabled,omitempty"`
+	Origins  []string `json:"origins,omitempty"`  // The allowed parent origins, frame-ancestors
+	Height   bool     `json:"height,omitempty"`   // Report the content height to the parent via postMessage
+	Document bool     `json:"document,omitempty"` // Keep the global document body (layout chrome)
+}
+
 // PageSEO is the struct for the page seo
 type PageSEO struct {
 	Title       string `json:"title,omitempty"`
@@ -415,6 +462,7 @@ type SourceCodes struct {
 	LESS Source `json:"-"`
 	DATA Source `json:"-"`
 	CONF Source `json:"-"`
+	MOCK Source `json:"-"` // The preview data fixtures name.mock.json
 }
 
 // Source is the struct for the source
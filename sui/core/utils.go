@@ -13,6 +13,9 @@ import (
 
 // NewDocument create a new document
 func NewDocument(htmlContent []byte) (*goquery.Document, error) {
+	if bytes.Contains(htmlContent, []byte("<s:csrf")) {
+		htmlContent = []byte(closeElements(string(htmlContent)))
+	}
 	docNode, err := html.Parse(bytes.NewReader(htmlContent))
 	if err != nil {
 		return nil, err
@@ -22,7 +25,7 @@ func NewDocument(htmlContent []byte) (*goquery.Document, error) {
 
 // NewDocumentString create a new document
 func NewDocumentString(htmlContent string) (*goquery.Document, error) {
-	docNode, err := html.Parse(strings.NewReader(htmlContent))
+	docNode, err := html.Parse(strings.NewReader(closeElements(htmlContent)))
 	if err != nil {
 		return nil, err
 	}
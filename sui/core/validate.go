@@ -0,0 +1,356 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// ValidateEnabled validate the markup of all the rendered pages (YAO_SUI_VALIDATE=true), e.g. in the CI
+var ValidateEnabled = os.Getenv("YAO_SUI_VALIDATE") == "true"
+
+// posAttr the source position of the element (line:column), set by the source map of the validation mode
+const posAttr = "s:pos"
+
+// Violation the HTML5 conformance violation of the rendered page, located in the template source
+type Violation struct {
+	Rule    string `json:"rule"`             // nesting, end-tag, duplicate-id, attribute, parent
+	Path    string `json:"path,omitempty"`   // the CSS-like selector of the node, see NodePath
+	Line    int    `json:"line,omitempty"`   // the line of the element in the template source
+	Column  int    `json:"column,omitempty"` // the column of the element in the template source
+	Message string `json:"message"`
+}
+
+// Error the message of the violation
+func (v Violation) Error() string {
+	var b strings.Builder
+	if v.Line > 0 {
+		fmt.Fprintf(&b, "%d:%d ", v.Line, v.Column)
+	}
+	if v.Path != "" {
+		b.WriteString(v.Path)
+		b.WriteString(" ")
+	}
+	fmt.Fprintf(&b, "%s: %s", v.Rule, v.Message)
+	return b.String()
+}
+
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true, "input": true,
+	"link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+// optionalEndElements the elements the end tags can be omitted
+var optionalEndElements = map[string]bool{
+	"html": true, "head": true, "body": true, "p": true, "li": true, "dt": true, "dd": true, "option": true,
+	"optgroup": true, "tr": true, "td": true, "th": true, "thead": true, "tbody": true, "tfoot": true,
+	"colgroup": true, "caption": true, "rt": true, "rp": true,
+}
+
+// blockElements the elements close the open paragraph
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "details": true, "dialog": true,
+	"div": true, "dl": true, "fieldset": true, "figcaption": true, "figure": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "header": true, "hgroup": true,
+	"hr": true, "main": true, "menu": true, "nav": true, "ol": true, "p": true, "pre": true, "section": true,
+	"table": true, "ul": true,
+}
+
+// phrasingElements the elements of the phrasing content, can not contain the block elements
+var phrasingElements = map[string]bool{
+	"abbr": true, "b": true, "bdi": true, "bdo": true, "cite": true, "code": true, "dfn": true, "em": true,
+	"i": true, "kbd": true, "label": true, "mark": true, "q": true, "s": true, "samp": true, "small": true,
+	"span": true, "strong": true, "sub": true, "sup": true, "time": true, "u": true, "var": true,
+}
+
+// parentElements the required parents of the elements
+var parentElements = map[string][]string{
+	"li":     {"ul", "ol", "menu"},
+	"option": {"select", "datalist", "optgroup"},
+	"tr":     {"table", "thead", "tbody", "tfoot"},
+	"td":     {"tr"},
+	"th":     {"tr"},
+	"dt":     {"dl", "div"},
+	"dd":     {"dl", "div"},
+}
+
+var inputTypes = map[string]bool{
+	"button": true, "checkbox": true, "color": true, "date": true, "datetime-local": true, "email": true,
+	"file": true, "hidden": true, "image": true, "month": true, "number": true, "password": true, "radio": true,
+	"range": true, "reset": true, "search": true, "submit": true, "tel": true, "text": true, "time": true,
+	"url": true, "week": true,
+}
+
+// sourceMap set the source position (s:pos="line:column") of the start tags, and check the markup the browsers
+// fix silently when parsing: the stray and the misnested end tags, the blocks in the paragraphs, the nested
+// links and forms
+func sourceMap(source string) (string, []Violation) {
+	var b strings.Builder
+	violations := []Violation{}
+	stack := []string{}
+	line, col := 1, 1
+
+	violation := func(rule string, line, col int, message string) {
+		violations = append(violations, Violation{Rule: rule, Line: line, Column: col, Message: message})
+	}
+	contains := func(name string) int {
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i] == name {
+				return i
+			}
+		}
+		return -1
+	}
+
+	z := html.NewTokenizer(strings.NewReader(source))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+
+		raw := string(z.Raw())
+		start, startCol := line, col
+		for _, c := range raw {
+			if c == '\n' {
+				line, col = line+1, 1
+				continue
+			}
+			col++
+		}
+
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken && tt != html.EndTagToken {
+			b.WriteString(raw)
+			continue
+		}
+
+		name, _ := z.TagName()
+		tag := string(name)
+		if tt == html.EndTagToken {
+			b.WriteString(raw)
+			i := contains(tag)
+			if i < 0 {
+				if !voidElements[tag] {
+					violation("end-tag", start, startCol, fmt.Sprintf("the </%s> has no start tag", tag))
+				}
+				continue
+			}
+			for _, open := range stack[i+1:] {
+				if !optionalEndElements[open] {
+					violation("end-tag", start, startCol, fmt.Sprintf("the <%s> is not closed before </%s>", open, tag))
+					break
+				}
+			}
+			stack = stack[:i]
+			continue
+		}
+
+		// The position of the element
+		end := len(raw) - 1
+		if strings.HasSuffix(raw, "/>") {
+			end = len(raw) - 2
+		}
+		b.WriteString(raw[:end])
+		fmt.Fprintf(&b, ` %s="%d:%d"`, posAttr, start, startCol)
+		b.WriteString(raw[end:])
+
+		if tt == html.SelfClosingTagToken || voidElements[tag] {
+			continue
+		}
+
+		// The implied end tags
+		if len(stack) > 0 && optionalEndElements[tag] && optionalEndElements[stack[len(stack)-1]] {
+			top := stack[len(stack)-1]
+			if top == tag || (top == "td" || top == "th") && (tag == "td" || tag == "th") || (top == "dt" || top == "dd") && (tag == "dt" || tag == "dd") {
+				stack = stack[:len(stack)-1]
+			}
+		}
+
+		switch {
+		case blockElements[tag] && contains("p") >= 0:
+			i := contains("p")
+			if i == len(stack)-1 {
+				stack = stack[:i] // the paragraph is closed by the block
+				break
+			}
+			violation("nesting", start, startCol, fmt.Sprintf("the <%s> can not be in the <p>, the paragraph is closed by the browser", tag))
+			stack = stack[:i]
+
+		case (tag == "a" || tag == "form") && contains(tag) >= 0:
+			violation("nesting", start, startCol, fmt.Sprintf("the <%s> can not be in the <%s>, the browser closes the outer one", tag, tag))
+			stack = stack[:contains(tag)]
+		}
+		stack = append(stack, tag)
+	}
+
+	for _, open := range stack {
+		if !optionalEndElements[open] {
+			violation("end-tag", line, col, fmt.Sprintf("the <%s> is not closed", open))
+		}
+	}
+	return b.String(), violations
+}
+
+// Validate check the content model and the attribute values of the rendered nodes: the blocks in the phrasing
+// elements, the nested interactive elements, the elements out of the required parents, the duplicate ids and
+// the invalid attribute values. the violations are located by the s:pos of the source map
+func Validate(sel *goquery.Selection) []Violation {
+	violations := []Violation{}
+	ids := map[string]bool{}
+
+	var walk func(node *html.Node, phrasing string, interactive string)
+	walk = func(node *html.Node, phrasing string, interactive string) {
+		if node.Type != html.ElementNode {
+			for child := node.FirstChild; child != nil; child = child.NextSibling {
+				walk(child, phrasing, interactive)
+			}
+			return
+		}
+
+		if hasAttr(node, "sui-hide") || node.Data == "script" || node.Data == "style" || isInertTemplate(node) {
+			return
+		}
+
+		violation := func(rule, message string) {
+			line, col := sourcePos(node)
+			violations = append(violations, Violation{Rule: rule, Path: NodePath(node), Line: line, Column: col, Message: message})
+		}
+
+		tag := node.Data
+		if blockElements[tag] && phrasing != "" {
+			violation("nesting", fmt.Sprintf("the <%s> can not be in the <%s>", tag, phrasing))
+		}
+		if (tag == "a" || tag == "button") && interactive != "" {
+			violation("nesting", fmt.Sprintf("the <%s> can not be in the <%s>", tag, interactive))
+		}
+		if parents, has := parentElements[tag]; has && node.Parent != nil && node.Parent.Type == html.ElementNode {
+			if !inStrings(node.Parent.Data, parents) {
+				violation("parent", fmt.Sprintf("the <%s> must be in the <%s>", tag, strings.Join(parents, ">, <")))
+			}
+		}
+
+		for _, attr := range node.Attr {
+			if strings.Contains(attr.Val, "{{") {
+				continue
+			}
+			if message := validateAttr(tag, attr.Key, attr.Val); message != "" {
+				violation("attribute", message)
+			}
+		}
+
+		if id := attrValue(node, "id"); id != "" && !strings.ContainsAny(id, " \t\n") {
+			if ids[id] {
+				violation("duplicate-id", fmt.Sprintf("the id %q is not unique", id))
+			}
+			ids[id] = true
+		}
+
+		if phrasingElements[tag] && phrasing == "" {
+			phrasing = tag
+		}
+		if (tag == "a" || tag == "button") && interactive == "" {
+			interactive = tag
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child, phrasing, interactive)
+		}
+	}
+
+	for _, node := range sel.Nodes {
+		walk(node, "", "")
+	}
+	return violations
+}
+
+// validateAttr check the value of the attribute, return the message if the value is invalid
+func validateAttr(tag string, name string, value string) string {
+	switch {
+	case name == "id":
+		if value == "" || strings.ContainsAny(value, " \t\n") {
+			return fmt.Sprintf("the id %q must not be empty or contain the whitespace", value)
+		}
+
+	case name == "tabindex":
+		if _, err := strconv.Atoi(strings.TrimSpace(value)); err != nil {
+			return fmt.Sprintf("the tabindex %q must be an integer", value)
+		}
+
+	case name == "colspan" || name == "rowspan":
+		if n, err := strconv.Atoi(strings.TrimSpace(value)); err != nil || n < 0 || (n == 0 && name == "colspan") {
+			return fmt.Sprintf("the %s %q must be a positive integer", name, value)
+		}
+
+	case name == "dir":
+		if value != "ltr" && value != "rtl" && value != "auto" {
+			return fmt.Sprintf("the dir %q must be ltr, rtl or auto", value)
+		}
+
+	case name == "type" && tag == "input":
+		if !inputTypes[strings.ToLower(value)] {
+			return fmt.Sprintf("the input type %q is unknown", value)
+		}
+
+	case (name == "width" || name == "height") && (tag == "img" || tag == "video" || tag == "canvas" || tag == "iframe"):
+		if n, err := strconv.Atoi(strings.TrimSpace(value)); err != nil || n < 0 {
+			return fmt.Sprintf("the %s %q must be a non-negative integer (pixels)", name, value)
+		}
+	}
+	return ""
+}
+
+// sourcePos get the source position of the node or the nearest ancestor
+func sourcePos(node *html.Node) (int, int) {
+	for n := node; n != nil; n = n.Parent {
+		if n.Type != html.ElementNode || !hasAttr(n, posAttr) {
+			continue
+		}
+		line, col, _ := strings.Cut(attrValue(n, posAttr), ":")
+		l, _ := strconv.Atoi(line)
+		c, _ := strconv.Atoi(col)
+		return l, c
+	}
+	return 0, 0
+}
+
+// stripPositions remove the source positions of the nodes
+func stripPositions(node *html.Node) {
+	if node.Type == html.ElementNode {
+		n := 0
+		for _, attr := range node.Attr {
+			if attr.Key != posAttr {
+				node.Attr[n] = attr
+				n++
+			}
+		}
+		node.Attr = node.Attr[:n]
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		stripPositions(child)
+	}
+}
+
+func inStrings(value string, values []string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// validating check if the rendered page is validated, by the option or the YAO_SUI_VALIDATE
+func (parser *TemplateParser) validating() bool {
+	return parser.option.Validate || ValidateEnabled
+}
+
+// Violations get the HTML5 conformance violations of the rendered page, empty if the validation is not enabled
+func (parser *TemplateParser) Violations() []Violation {
+	if parser.violations == nil {
+		return []Violation{}
+	}
+	return parser.violations
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceMap(t *testing.T) {
+	source := "<html><body>\n" +
+		"<p>Intro<div>Block</div></p>\n" +
+		"<ul><li>One<li>Two</ul>\n" +
+		"<div><span>Text</div>\n" +
+		"<a href=\"/\"><a href=\"/next\">Next</a></a>\n" +
+		"<img src=\"/logo.png\"/><br>\n" +
+		"</body></html>"
+
+	mapped, violations := sourceMap(source)
+	assert.Contains(t, mapped, `<div s:pos="2:9">`)
+	assert.Contains(t, mapped, `<img src="/logo.png" s:pos="6:1"/>`)
+	assert.Contains(t, mapped, `<br s:pos="6:23">`)
+
+	messages := []string{}
+	for _, v := range violations {
+		messages = append(messages, v.Error())
+	}
+	assert.Equal(t, []string{
+		"2:25 end-tag: the </p> has no start tag",
+		"4:16 end-tag: the <span> is not closed before </div>",
+		"5:13 nesting: the <a> can not be in the <a>, the browser closes the outer one",
+		"5:37 end-tag: the </a> has no start tag",
+	}, messages)
+}
+
+func TestParserValidate(t *testing.T) {
+	parser := NewTemplateParser(Data{"items": []interface{}{"a", "b"}}, &ParserOption{Validate: true, Request: &Request{}})
+	html, err := parser.Render("<html><body>\n" +
+		"<span><div>Block</div></span>\n" +
+		"<li s:for=\"items\" s:for-item=\"item\" id=\"item\" tabindex=\"first\">{{ item }}</li>\n" +
+		"<input type=\"mail\"/><button><a href=\"/\">Go</a></button>\n" +
+		"</body></html>")
+	assert.Nil(t, err)
+	assert.NotContains(t, html, posAttr)
+
+	rules := map[string][]int{}
+	for _, v := range parser.Violations() {
+		rules[v.Rule] = append(rules[v.Rule], v.Line)
+	}
+	assert.Equal(t, []int{2, 4}, rules["nesting"])
+	assert.Equal(t, []int{3, 3}, rules["parent"])
+	assert.Equal(t, []int{3, 3, 4}, rules["attribute"])
+	assert.Equal(t, []int{3}, rules["duplicate-id"])
+
+	// Not enabled
+	parser = NewTemplateParser(Data{}, &ParserOption{Request: &Request{}})
+	_, err = parser.Render(`<html><body><span><div>Block</div></span></body></html>`)
+	assert.Nil(t, err)
+	assert.Empty(t, parser.Violations())
+}
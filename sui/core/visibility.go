@@ -0,0 +1,195 @@
+package core
+
+import (
+	"strings"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/session"
+	"github.com/yaoapp/kun/log"
+)
+
+// Visibility the field visibility rules of the page data sent to the browser (__sui_data and the json format),
+// the key is the model ID of the data sources, * for all the data. set in the visibility section of the sui DSL
+//
+//	"visibility": {
+//	  "product": [{"fields": ["cost_price", "internal_notes"], "when": "$session.role == 'admin'"}],
+//	  "*": [{"fields": ["password"]}]
+//	}
+//
+// the fields are removed from the records at any depth (the lists, the paginated results and the relations)
+// unless the when expression is true. the server-side rendering reads the data as it is
+type Visibility map[string][]*VisibilityRule
+
+// VisibilityRule the visibility rule of the fields
+type VisibilityRule struct {
+	Fields []string `json:"fields"`         // the field paths of the records, e.g. cost_price, supplier.phone
+	When   string   `json:"when,omitempty"` // the expression against the $session, the fields are never visible if empty
+}
+
+var visibilities = map[string]Visibility{}
+var visibilityMutex sync.RWMutex
+
+// RegisterVisibility set the visibility rules of the route prefix, e.g. the public root of the sui
+func RegisterVisibility(prefix string, visibility Visibility) {
+	visibilityMutex.Lock()
+	defer visibilityMutex.Unlock()
+	prefix = "/" + strings.Trim(prefix, "/")
+	if visibility == nil {
+		delete(visibilities, prefix)
+		return
+	}
+	visibilities[prefix] = visibility
+}
+
+// GetVisibility get the visibility rules of the path, the longest prefix wins, nil if the path has no rules
+func GetVisibility(route string) Visibility {
+	visibilityMutex.RLock()
+	defer visibilityMutex.RUnlock()
+	var res Visibility = nil
+	matched := -1
+	for prefix, visibility := range visibilities {
+		if len(prefix) <= matched {
+			continue
+		}
+		if prefix == "/" || route == prefix || strings.HasPrefix(route, prefix+"/") {
+			res, matched = visibility, len(prefix)
+		}
+	}
+	return res
+}
+
+// DataModels get the models of the data sources, the key is the data path and the value is the model ID.
+// e.g. {"$products": "models.product.Paginate"} => {"products": "product"}, the global keys are under $global
+func DataModels(page string, global string) map[string]string {
+	models := map[string]string{}
+	dataModels(models, "", page)
+	dataModels(models, "$global.", global)
+	return models
+}
+
+func dataModels(models map[string]string, prefix string, raw string) {
+	if raw == "" {
+		return
+	}
+
+	var sources map[string]interface{}
+	if err := jsoniter.UnmarshalFromString(raw, &sources); err != nil {
+		return
+	}
+
+	for key, value := range sources {
+		if !strings.HasPrefix(key, "$") {
+			continue
+		}
+
+		name := ""
+		switch v := value.(type) {
+		case string:
+			name = v
+		case map[string]interface{}:
+			name, _ = v["process"].(string)
+		}
+
+		// models.<id>.<method>, the id may have the dots, e.g. models.admin.user.Find
+		lower := strings.ToLower(name)
+		if !strings.HasPrefix(lower, "models.") {
+			continue
+		}
+		dot := strings.LastIndex(lower, ".")
+		if dot <= len("models.") {
+			continue
+		}
+		models[prefix+key[1:]] = lower[len("models."):dot]
+	}
+}
+
+// Visible get a copy of the data without the fields the visitor of the request can not see
+func (data Data) Visible(visibility Visibility, r *Request) Data {
+	if len(visibility) == 0 || data == nil {
+		return data
+	}
+
+	var sess map[string]interface{} = nil
+	hides := []MaskRule{}
+	for model, rules := range visibility {
+		paths := []string{"**"}
+		if model != "*" {
+			paths = r.modelPaths(strings.ToLower(model))
+			if len(paths) == 0 {
+				continue
+			}
+		}
+
+		for _, rule := range rules {
+			if rule.When != "" {
+				if sess == nil {
+					sess = r.sessionData()
+				}
+				if rule.visible(sess) {
+					continue
+				}
+			}
+			for _, field := range rule.Fields {
+				for _, path := range paths {
+					hides = append(hides, MaskRule{Pattern: path + ".**." + field, Format: "hide"})
+				}
+			}
+		}
+	}
+	return data.Mask(hides)
+}
+
+// visible check if the fields are visible to the session, the fields are hidden if the expression fails
+func (rule *VisibilityRule) visible(sess map[string]interface{}) bool {
+	return sessionWhen(rule.When, sess)
+}
+
+// sessionWhen check the expression against the session data, false if the expression fails
+func sessionWhen(when string, sess map[string]interface{}) bool {
+	res, _, err := Data{"$session": sess}.Exec(when)
+	if err != nil {
+		log.Error("[SUI] %s: %s", when, err.Error())
+		return false
+	}
+	ok, _ := res.(bool)
+	return ok
+}
+
+// modelPaths the data paths of the model
+func (r *Request) modelPaths(model string) []string {
+	if r == nil {
+		return nil
+	}
+	paths := []string{}
+	for path, m := range r.Models {
+		if m == model {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// sessionData the session data of the visitor, empty if anonymous
+func (r *Request) sessionData() map[string]interface{} {
+	if r == nil || r.Sid == "" {
+		return map[string]interface{}{}
+	}
+	data, err := session.Global().ID(r.Sid).Dump()
+	if err != nil || data == nil {
+		return map[string]interface{}{}
+	}
+	return data
+}
+
+// clientData the page data sent to the browser, without the denied paths and the invisible fields
+func (parser *TemplateParser) clientData() Data {
+	data := parser.guard.Strip(parser.data).Overlay()
+	delete(data, processCallsKey)
+	delete(data, menusKey)
+	delete(data, settingKey)
+	delete(data, prefKey)
+	delete(data, nowKey)
+	delete(data, deviceKey)
+	return data.Visible(GetVisibility(parser.option.Route), parser.option.Request)
+}
@@ -0,0 +1,75 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataModels(t *testing.T) {
+	models := DataModels(
+		`{"$products": {"process": "models.product.Paginate", "args": [1, 20]}, "$user": "models.admin.user.Find", "$menu": "scripts.menu.Get", "title": "Shop"}`,
+		`{"$categories": "models.category.Get"}`,
+	)
+	assert.Equal(t, map[string]string{
+		"products":           "product",
+		"user":               "admin.user",
+		"$global.categories": "category",
+	}, models)
+}
+
+func TestDataVisible(t *testing.T) {
+	visibility := Visibility{
+		"product": {{Fields: []string{"cost_price", "supplier.phone"}, When: "$session.role == 'admin'"}},
+		"*":       {{Fields: []string{"password"}}},
+	}
+
+	data := Data{
+		"title": "Shop",
+		"products": map[string]interface{}{
+			"total": 1,
+			"data": []interface{}{
+				map[string]interface{}{"name": "Pen", "price": 2, "cost_price": 1, "supplier": map[string]interface{}{"name": "ACME", "phone": "123"}},
+			},
+		},
+		"$global": map[string]interface{}{"user": map[string]interface{}{"name": "Ada", "password": "secret"}},
+	}
+
+	r := &Request{Models: map[string]string{"products": "product"}}
+	visible := data.Visible(visibility, r)
+	product := visible["products"].(map[string]interface{})["data"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "Pen", product["name"])
+	assert.NotContains(t, product, "cost_price")
+	assert.Equal(t, map[string]interface{}{"name": "ACME"}, product["supplier"])
+	assert.NotContains(t, visible["$global"].(map[string]interface{})["user"], "password")
+	assert.Equal(t, "Shop", visible["title"])
+
+	// The origin data should not be changed
+	assert.Contains(t, data["products"].(map[string]interface{})["data"].([]interface{})[0], "cost_price")
+
+	// The data without the model is not changed by the model rules
+	visible = data.Visible(visibility, &Request{})
+	assert.Contains(t, visible["products"].(map[string]interface{})["data"].([]interface{})[0], "cost_price")
+
+	// The when expression against the session
+	rule := visibility["product"][0]
+	assert.True(t, rule.visible(map[string]interface{}{"role": "admin"}))
+	assert.False(t, rule.visible(map[string]interface{}{"role": "guest"}))
+	assert.False(t, rule.visible(map[string]interface{}{}))
+}
+
+func TestParserVisibility(t *testing.T) {
+	RegisterVisibility("/__visibility", Visibility{"product": {{Fields: []string{"cost_price"}, When: "$session.role == 'admin'"}}})
+	defer RegisterVisibility("/__visibility", nil)
+
+	data := Data{"product": map[string]interface{}{"name": "Pen", "cost_price": 1}}
+	option := &ParserOption{Route: "/__visibility/product", Request: &Request{Models: map[string]string{"product": "product"}}}
+	parser := NewTemplateParser(data, option)
+	html, err := parser.Render(`<html><body><p>{{ product.name }} {{ product.cost_price }}</p></body></html>`)
+	assert.Nil(t, err)
+
+	// The server-side rendering reads the data as it is, the payload of the browser has no cost_price
+	assert.Contains(t, html, "Pen 1")
+	assert.NotContains(t, html, `"cost_price"`)
+	assert.Contains(t, html, `"name":"Pen"`)
+}
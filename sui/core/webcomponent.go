@@ -0,0 +1,190 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/kun/log"
+)
+
+// WebComponentsFile the bundle of the custom elements exported by the build, in the public root
+const WebComponentsFile = "webcomponents.js"
+
+var webComponentTagRe = regexp.MustCompile(`[^a-z0-9\-]+`)
+
+var webComponentNameRe = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// webComponentTmpl the script of the custom element.
+// the scripts run once for each element in the setup function, the document of the scripts is scoped to the shadow root
+const webComponentTmpl = `(function () {
+	const tag = %s;
+	if (customElements.get(tag)) return;
+	const template = document.createElement("template");
+	template.innerHTML = %s;
+	const scoped = (root) => new Proxy(document, {
+		get(target, prop) {
+			if (prop === "querySelector" || prop === "querySelectorAll" || prop === "getElementById") {
+				return root[prop].bind(root);
+			}
+			const value = Reflect.get(target, prop);
+			return typeof value === "function" ? value.bind(target) : value;
+		},
+	});
+	const setup = function (document, host) {
+%s
+	};
+	class SUIComponent extends HTMLElement {
+		constructor() {
+			super();
+			this.attachShadow({ mode: "open" }).appendChild(template.content.cloneNode(true));
+		}
+		connectedCallback() {
+			const root = this.shadowRoot.firstElementChild;
+			for (const attr of this.attributes) {
+				root && root.setAttribute(attr.name, attr.value);
+			}
+			if (this.__sui_ready) return;
+			this.__sui_ready = true;
+			const ready = setup.call(this, scoped(this.shadowRoot), this);
+			if (typeof ready === "function") {
+				ready.call(root, root);
+			}
+		}
+	}
+	customElements.define(tag, SUIComponent);
+})();
+`
+
+// WebComponentTag get the custom element tag name of the page, the name must contain a hyphen
+func WebComponentTag(route string) string {
+	tag := strings.ToLower(strings.Trim(route, "/"))
+	tag = webComponentTagRe.ReplaceAllString(tag, "-")
+	tag = strings.Trim(tag, "-")
+	if tag == "" || !strings.Contains(tag, "-") || tag[0] < 'a' || tag[0] > 'z' {
+		tag = "sui-" + tag
+	}
+	return strings.TrimRight(tag, "-")
+}
+
+// CompileAsWebComponent compile the page as a standalone custom element
+// The template, the scoped styles and the hydration scripts are bundled into one script
+func (page *Page) CompileAsWebComponent(ctx *BuildContext, option *BuildOption) (string, []string, error) {
+
+	opt := *option
+	opt.IgnoreDocument = true
+	opt.WithWrapper = true
+	opt.JitMode = true
+	doc, warnings, err := page.Build(ctx, &opt)
+	if err != nil {
+		return "", warnings, err
+	}
+
+	for _, warning := range warnings {
+		log.Warn("Compile page %s/%s/%s as web component: %s", page.SuiID, page.TemplateID, page.Route, warning)
+	}
+	return page.bundleWebComponent(ctx, doc, warnings, option)
+}
+
+// bundleWebComponent render the built document and bundle the template, the styles and the scripts
+func (page *Page) bundleWebComponent(ctx *BuildContext, doc *goquery.Document, warnings []string, option *BuildOption) (string, []string, error) {
+	var err error
+	body := doc.Find("body")
+	if body.Children().Length() != 1 {
+		return "", warnings, fmt.Errorf("page %s as web component should have only one root element", page.Route)
+	}
+	root := body.Children().First()
+	ready := strings.TrimSuffix(root.AttrOr("s:ready", ""), "()")
+	if ready != "" && !webComponentNameRe.MatchString(ready) {
+		warnings = append(warnings, fmt.Sprintf("s:ready %s is not a function name", ready))
+		ready = ""
+	}
+
+	// Render the template, the directives and the bindings are rendered with the page data
+	data := Data{}
+	if page.Codes.DATA.Code != "" || page.GlobalData != nil {
+		page.GetConfig()
+		var mock *PageMock = nil
+		if page.Config != nil {
+			mock = page.Config.Mock
+		}
+		data, err = page.Exec(NewRequestMock(mock))
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			data = Data{}
+		}
+	}
+
+	parser := NewTemplateParser(data, &ParserOption{Route: page.Route, Root: page.Root})
+	err = parser.RenderSelection(body)
+	if err != nil {
+		return "", warnings, err
+	}
+	body.Find("[sui-hide]").Remove()
+	parser.Tidy(body)
+	for _, err := range parser.errors {
+		warnings = append(warnings, err.Error())
+	}
+
+	// The styles and the scripts of the page, and of the just-in-time components
+	styleNodes := append([]StyleNode{}, ctx.styles...)
+	scriptNodes := append([]ScriptNode{}, ctx.scripts...)
+	if parser.context != nil {
+		styleNodes = append(styleNodes, parser.context.styles...)
+		scriptNodes = append(scriptNodes, parser.context.scripts...)
+	}
+
+	styles := []string{}
+	for _, style := range styleNodes {
+		if style.Source != "" {
+			styles = append(styles, style.Source)
+		}
+	}
+
+	// The component constructors are exported to the window, the runtime creates the components by the names
+	sources := []string{}
+	exports := []string{}
+	for _, script := range scriptNodes {
+		if script.Source == "" {
+			continue
+		}
+		source := script.Source
+		if script.Component != "" && !strings.Contains(source, "function "+script.Component) {
+			source = fmt.Sprintf(`function %s( component ){%s};`, script.Component, source)
+		}
+		sources = append(sources, source)
+		if script.Component != "" && webComponentNameRe.MatchString(script.Component) {
+			exports = append(exports, fmt.Sprintf(`if (typeof %s === "function") window[%q] = %s;`, script.Component, script.Component, script.Component))
+		}
+	}
+	sources = append(sources, exports...)
+	if ready != "" {
+		sources = append(sources, fmt.Sprintf(`return typeof %s === "function" ? %s : null;`, ready, ready))
+	}
+
+	html, err := body.Html()
+	if err != nil {
+		return "", warnings, err
+	}
+	if len(styles) > 0 {
+		html = fmt.Sprintf("<style>\n%s\n</style>\n%s", strings.Join(styles, "\n"), html)
+	}
+
+	tag := option.ComponentName
+	if tag == "" {
+		tag = WebComponentTag(page.Route)
+	}
+
+	raws := []string{}
+	for _, value := range []interface{}{tag, html} {
+		raw, err := jsoniter.MarshalToString(value)
+		if err != nil {
+			return "", warnings, err
+		}
+		raws = append(raws, raw)
+	}
+
+	return fmt.Sprintf(webComponentTmpl, raws[0], raws[1], strings.Join(sources, "\n")), warnings, nil
+}
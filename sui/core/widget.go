@@ -0,0 +1,403 @@
+package core
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/process"
+	"github.com/yaoapp/kun/maps"
+	xhtml "golang.org/x/net/html"
+)
+
+// Widgets the admin widgets can be embedded in the pages by <s:widget>, the key is <type>.<id> and the * of the id
+// matches all the widgets of the type. set in the widgets section of the sui DSL, the widgets not listed are not rendered
+//
+//	"widgets": {
+//	  "table.orders": {"when": "$session.user_id != nil"},
+//	  "chart.*": {}
+//	}
+//
+// the widget processes run with the session of the visitor, so the permissions of the admin (the excluded
+// columns and fields) and the hooks of the widgets apply to the pages too
+type Widgets map[string]*WidgetAccess
+
+// WidgetAccess the access of the embedded admin widget
+type WidgetAccess struct {
+	When string `json:"when,omitempty"` // the expression against the $session, rendered for everyone if empty
+}
+
+var widgets = map[string]Widgets{}
+var widgetsMutex sync.RWMutex
+
+// RegisterWidgets set the admin widgets can be embedded in the pages of the route prefix, e.g. the public root of the sui
+func RegisterWidgets(prefix string, w Widgets) {
+	widgetsMutex.Lock()
+	defer widgetsMutex.Unlock()
+	prefix = "/" + strings.Trim(prefix, "/")
+	if w == nil {
+		delete(widgets, prefix)
+		return
+	}
+	widgets[prefix] = w
+}
+
+// GetWidgets get the admin widgets of the path, the longest prefix wins, nil if the path has no widgets
+func GetWidgets(route string) Widgets {
+	widgetsMutex.RLock()
+	defer widgetsMutex.RUnlock()
+	var res Widgets = nil
+	matched := -1
+	for prefix, w := range widgets {
+		if len(prefix) <= matched {
+			continue
+		}
+		if prefix == "/" || route == prefix || strings.HasPrefix(route, prefix+"/") {
+			res, matched = w, len(prefix)
+		}
+	}
+	return res
+}
+
+// access get the access of the widget, nil if the widget can not be embedded
+func (w Widgets) access(typ string, id string) *WidgetAccess {
+	if access, has := w[typ+"."+id]; has {
+		if access == nil {
+			return &WidgetAccess{}
+		}
+		return access
+	}
+	if access, has := w[typ+".*"]; has {
+		if access == nil {
+			return &WidgetAccess{}
+		}
+		return access
+	}
+	return nil
+}
+
+// widgetNode render the <s:widget> admin widget on the server
+//
+//	<s:widget type="table" name="orders" params='{"where.status.eq": "paid"}' page="1" pagesize="20"></s:widget>
+//	<s:widget type="form" name="profile" primary="{{ user.id }}" action="/api/profile"></s:widget>
+//	<s:widget type="chart" name="sales" params='{"range": "30d"}'></s:widget>
+//	<s:widget type="menu" name="main"></s:widget>
+//	<s:widget type="settings" name="site" action="/api/settings"></s:widget>
+//
+// the table is rendered as the <table>, the form as the <form> with the values of the primary record,
+// the chart as the json data for the client scripts, the menu as the nested <ul> of the links, and the settings as
+// the <form> of the typed keys for the admin. the id, class and style attributes are kept
+func (parser *TemplateParser) widgetNode(sel *goquery.Selection) {
+	defer parser.option.Timing.Start("widget", "Admin widgets")()
+	parser.parsed(sel)
+	parser.hide(sel)
+
+	attrs := map[string]string{}
+	for _, attr := range sel.Nodes[0].Attr {
+		val, values := parser.data.ReplaceGuard(attr.Val, parser.guard)
+		parser.catchValues(sel.Nodes[0], attr.Key, values)
+		attrs[attr.Key] = val
+	}
+
+	typ, id := strings.ToLower(attrs["type"]), attrs["name"]
+	value := typ + "." + id
+	if parser.option.Restricted != nil {
+		parser.componentError(sel, "s:widget", value, fmt.Errorf("the widgets are not allowed in the restricted templates"))
+		return
+	}
+
+	access := GetWidgets(parser.option.Route).access(typ, id)
+	if access == nil {
+		parser.componentError(sel, "s:widget", value, fmt.Errorf("the widget %s is not allowed, please add it to the widgets of the sui", value))
+		return
+	}
+
+	// The visitor can not see the widget, keep it hidden
+	if access.When != "" && !sessionWhen(access.When, parser.option.Request.sessionData()) {
+		return
+	}
+
+	var source string
+	var err error
+	switch typ {
+	case "table":
+		source, err = parser.widgetTable(id, attrs)
+	case "form":
+		source, err = parser.widgetForm(id, attrs)
+	case "chart":
+		source, err = parser.widgetChart(id, attrs)
+	case "menu":
+		source, err = parser.widgetMenu(id, attrs)
+	case "settings":
+		source, err = parser.widgetSettings(id, attrs)
+	default:
+		err = fmt.Errorf("the widget type %s is not supported, should be table, form, chart, menu or settings", attrs["type"])
+	}
+	if err != nil {
+		parser.componentError(sel, "s:widget", value, err)
+		return
+	}
+
+	nodes, err := xhtml.ParseFragment(strings.NewReader(source), fragmentContext(sel.Nodes[0].Parent))
+	if err != nil || len(nodes) == 0 {
+		parser.componentError(sel, "s:widget", value, fmt.Errorf("%s error: %v", value, err))
+		return
+	}
+	parser.addReplace(sel, nodes)
+}
+
+// widgetTable render the rows of the table widget searched by yao.table.Search
+func (parser *TemplateParser) widgetTable(id string, attrs map[string]string) (string, error) {
+	setting, err := parser.widgetProcess("yao.table.Setting", id)
+	if err != nil {
+		return "", err
+	}
+
+	params := map[string]interface{}{}
+	if raw := strings.TrimSpace(attrs["params"]); raw != "" {
+		if err := jsoniter.UnmarshalFromString(raw, &params); err != nil {
+			return "", fmt.Errorf("the params should be a json object. %s", err.Error())
+		}
+	}
+	page := codeInt(attrs, "page", 1, 1<<20)
+	pagesize := codeInt(attrs, "pagesize", 20, 200)
+	res, err := parser.widgetProcess("yao.table.Search", id, params, page, pagesize)
+	if err != nil {
+		return "", err
+	}
+
+	fields := widgetMap(widgetMap(setting["fields"])["table"])
+	columns := []string{}
+	for _, column := range widgetList(widgetMap(setting["table"])["columns"]) {
+		if name, ok := widgetMap(column)["name"].(string); ok && name != "" {
+			columns = append(columns, name)
+		}
+	}
+	columns = parser.preferredColumns(id, columns) // the column layout of the visitor, table.<id>.columns
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<table %s data-total="%v" data-page="%d" data-pagesize="%d"><thead><tr>`,
+		widgetAttrs("table", id, attrs), res["total"], page, pagesize)
+	for _, name := range columns {
+		fmt.Fprintf(&b, `<th>%s</th>`, html.EscapeString(name))
+	}
+	b.WriteString(`</tr></thead><tbody>`)
+	for _, row := range widgetList(res["data"]) {
+		record := maps.Of(widgetMap(row)).Dot()
+		b.WriteString(`<tr>`)
+		for _, name := range columns {
+			bind, _ := widgetMap(fields[name])["bind"].(string)
+			fmt.Fprintf(&b, `<td>%s</td>`, html.EscapeString(widgetValue(record.Get(bind))))
+		}
+		b.WriteString(`</tr>`)
+	}
+	b.WriteString(`</tbody></table>`)
+	return b.String(), nil
+}
+
+// widgetForm render the fields of the form widget, the values are read by yao.form.Find if the primary is set
+func (parser *TemplateParser) widgetForm(id string, attrs map[string]string) (string, error) {
+	setting, err := parser.widgetProcess("yao.form.Setting", id)
+	if err != nil {
+		return "", err
+	}
+
+	record := maps.MapStrAny{}
+	if primary := strings.TrimSpace(attrs["primary"]); primary != "" {
+		res, err := parser.widgetProcess("yao.form.Find", id, primary)
+		if err != nil {
+			return "", err
+		}
+		record = maps.Of(res).Dot()
+	}
+
+	names := []string{}
+	for _, section := range widgetList(widgetMap(setting["form"])["sections"]) {
+		names = widgetColumns(names, widgetList(widgetMap(section)["columns"]))
+	}
+
+	fields := widgetMap(widgetMap(setting["fields"])["form"])
+	disabled := ""
+	if attrs["readonly"] == "true" {
+		disabled = " disabled"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<form %s method="post" action="%s">`, widgetAttrs("form", id, attrs), html.EscapeString(attrs["action"]))
+	for _, name := range names {
+		field := widgetMap(fields[name])
+		bind, _ := field["bind"].(string)
+		if bind == "" {
+			continue
+		}
+		edit := widgetMap(field["edit"])
+		props := widgetMap(edit["props"])
+		typ, _ := edit["type"].(string)
+		value := html.EscapeString(widgetValue(record.Get(bind)))
+		input := html.EscapeString(bind)
+		placeholder := html.EscapeString(widgetValue(props["placeholder"]))
+
+		fmt.Fprintf(&b, `<label class="sui-widget-field"><span>%s</span>`, html.EscapeString(name))
+		switch typ {
+		case "TextArea":
+			fmt.Fprintf(&b, `<textarea name="%s" placeholder="%s"%s>%s</textarea>`, input, placeholder, disabled, value)
+
+		case "Select", "RadioGroup":
+			fmt.Fprintf(&b, `<select name="%s"%s>`, input, disabled)
+			for _, option := range widgetList(props["options"]) {
+				opt := widgetMap(option)
+				optValue := html.EscapeString(widgetValue(opt["value"]))
+				selected := ""
+				if optValue == value {
+					selected = " selected"
+				}
+				fmt.Fprintf(&b, `<option value="%s"%s>%s</option>`, optValue, selected, html.EscapeString(widgetValue(opt["label"])))
+			}
+			b.WriteString(`</select>`)
+
+		case "Switch", "Checkbox":
+			checked := ""
+			if ok, _ := _true(record.Get(bind)); ok == true {
+				checked = " checked"
+			}
+			fmt.Fprintf(&b, `<input type="checkbox" name="%s" value="1"%s%s>`, input, checked, disabled)
+
+		default:
+			fmt.Fprintf(&b, `<input type="%s" name="%s" value="%s" placeholder="%s"%s>`, widgetInputType(typ), input, value, placeholder, disabled)
+		}
+		b.WriteString(`</label>`)
+	}
+	b.WriteString(`</form>`)
+	return b.String(), nil
+}
+
+// widgetChart render the data of the chart widget read by yao.chart.Data, for the client scripts
+func (parser *TemplateParser) widgetChart(id string, attrs map[string]string) (string, error) {
+	params := map[string]interface{}{}
+	if raw := strings.TrimSpace(attrs["params"]); raw != "" {
+		if err := jsoniter.UnmarshalFromString(raw, &params); err != nil {
+			return "", fmt.Errorf("the params should be a json object. %s", err.Error())
+		}
+	}
+
+	res, err := parser.widgetProcess("yao.chart.Data", id, params)
+	if err != nil {
+		return "", err
+	}
+	data, err := jsonStable.MarshalToString(res)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`<div %s><script type="application/json">%s</script></div>`, widgetAttrs("chart", id, attrs), data), nil
+}
+
+// widgetProcess run the process of the widget with the session of the visitor, the result is a json object
+func (parser *TemplateParser) widgetProcess(name string, args ...interface{}) (res map[string]interface{}, err error) {
+	p, err := process.Of(name, args...)
+	if err != nil {
+		return nil, err
+	}
+	if parser.option.Request != nil && parser.option.Request.Sid != "" {
+		p.WithSID(parser.option.Request.Sid)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s %v", name, r)
+		}
+	}()
+	value, err := p.Exec()
+	if err != nil {
+		return nil, err
+	}
+
+	// The typed maps and slices of the widgets
+	raw, err := jsoniter.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	res = map[string]interface{}{}
+	if err := jsoniter.Unmarshal(raw, &res); err != nil {
+		return nil, fmt.Errorf("%s should return an object. %s", name, err.Error())
+	}
+	return res, nil
+}
+
+// widgetColumns collect the column names of the form sections, the columns of the tabs are flattened
+func widgetColumns(names []string, columns []interface{}) []string {
+	for _, column := range columns {
+		col := widgetMap(column)
+		if name, ok := col["name"].(string); ok && name != "" {
+			names = append(names, name)
+		}
+		for _, tab := range widgetList(col["tabs"]) {
+			names = widgetColumns(names, widgetList(widgetMap(tab)["columns"]))
+		}
+	}
+	return names
+}
+
+// widgetAttrs the attributes of the widget root, the id, class and style of the <s:widget> are kept
+func widgetAttrs(typ string, id string, attrs map[string]string) string {
+	class := strings.TrimSpace("sui-widget sui-widget-" + typ + " " + attrs["class"])
+	res := fmt.Sprintf(`class="%s" data-widget="%s.%s"`, html.EscapeString(class), typ, html.EscapeString(id))
+	for _, key := range []string{"id", "style"} {
+		if val, has := attrs[key]; has {
+			res += fmt.Sprintf(` %s="%s"`, key, html.EscapeString(val))
+		}
+	}
+	return res
+}
+
+// widgetInputType the input type of the edit component of the form field
+func widgetInputType(typ string) string {
+	switch typ {
+	case "Password":
+		return "password"
+	case "InputNumber":
+		return "number"
+	case "DatePicker":
+		return "date"
+	case "Upload":
+		return "file"
+	}
+	return "text"
+}
+
+func widgetValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool, int, int64, float64:
+		return fmt.Sprintf("%v", v)
+	}
+	raw, err := jsonStable.MarshalToString(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return raw
+}
+
+func widgetMap(value interface{}) map[string]interface{} {
+	if v, ok := value.(map[string]interface{}); ok {
+		return v
+	}
+	return map[string]interface{}{}
+}
+
+func widgetList(value interface{}) []interface{} {
+	if v, ok := value.([]interface{}); ok {
+		return v
+	}
+	return []interface{}{}
+}
+
+// isWidget check if the node is the <s:widget> admin widget
+func (parser *TemplateParser) isWidget(sel *goquery.Selection) bool {
+	return len(sel.Nodes) > 0 && sel.Nodes[0].Data == "s:widget"
+}
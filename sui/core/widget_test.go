@@ -0,0 +1,74 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/gou/process"
+)
+
+func TestWidgetsAccess(t *testing.T) {
+	w := Widgets{"table.orders": {When: "$session.user_id != nil"}, "chart.*": nil}
+	assert.Equal(t, "$session.user_id != nil", w.access("table", "orders").When)
+	assert.NotNil(t, w.access("chart", "sales"))
+	assert.Nil(t, w.access("table", "users"))
+	assert.Nil(t, w.access("form", "orders"))
+}
+
+func TestParserWidget(t *testing.T) {
+	process.Register("yao.table.Setting", func(p *process.Process) interface{} {
+		return map[string]interface{}{
+			"table":  map[string]interface{}{"columns": []interface{}{map[string]interface{}{"name": "Name"}, map[string]interface{}{"name": "City"}}},
+			"fields": map[string]interface{}{"table": map[string]interface{}{"Name": map[string]interface{}{"bind": "name"}, "City": map[string]interface{}{"bind": "address.city"}}},
+		}
+	})
+	process.Register("yao.table.Search", func(p *process.Process) interface{} {
+		return map[string]interface{}{"total": 1, "data": []interface{}{
+			map[string]interface{}{"name": "<Ada>", "address": map[string]interface{}{"city": "London"}},
+		}}
+	})
+	process.Register("yao.form.Setting", func(p *process.Process) interface{} {
+		return map[string]interface{}{
+			"form": map[string]interface{}{"sections": []interface{}{map[string]interface{}{"columns": []interface{}{
+				map[string]interface{}{"name": "Name"}, map[string]interface{}{"name": "Status"},
+			}}}},
+			"fields": map[string]interface{}{"form": map[string]interface{}{
+				"Name":   map[string]interface{}{"bind": "name", "edit": map[string]interface{}{"type": "Input"}},
+				"Status": map[string]interface{}{"bind": "status", "edit": map[string]interface{}{"type": "Select", "props": map[string]interface{}{"options": []interface{}{map[string]interface{}{"label": "On", "value": "on"}, map[string]interface{}{"label": "Off", "value": "off"}}}}},
+			}},
+		}
+	})
+	process.Register("yao.form.Find", func(p *process.Process) interface{} {
+		return map[string]interface{}{"name": "Ada", "status": "off"}
+	})
+
+	RegisterWidgets("/__widget", Widgets{"table.users": {}, "form.user": {}, "table.secret": {When: "$session.role == 'admin'"}})
+	defer RegisterWidgets("/__widget", nil)
+
+	render := func(source string) (string, *TemplateParser) {
+		parser := NewTemplateParser(Data{"id": 1}, &ParserOption{Route: "/__widget/page", Request: &Request{}})
+		html, err := parser.Render(`<html><body>` + source + `</body></html>`)
+		assert.Nil(t, err)
+		return html, parser
+	}
+
+	html, parser := render(`<s:widget type="table" name="users" class="wide"></s:widget>`)
+	assert.Empty(t, parser.Errors())
+	assert.Contains(t, html, `<table class="sui-widget sui-widget-table wide" data-widget="table.users" data-total="1" data-page="1" data-pagesize="20">`)
+	assert.Contains(t, html, `<th>Name</th><th>City</th>`)
+	assert.Contains(t, html, `<td>&lt;Ada&gt;</td><td>London</td>`)
+
+	html, parser = render(`<s:widget type="form" name="user" primary="{{ id }}"></s:widget>`)
+	assert.Empty(t, parser.Errors())
+	assert.Contains(t, html, `<input type="text" name="name" value="Ada" placeholder=""/>`)
+	assert.Contains(t, html, `<option value="off" selected="">Off</option>`)
+
+	// The visitor can not see the widget
+	html, parser = render(`<s:widget type="table" name="secret"></s:widget>`)
+	assert.Empty(t, parser.Errors())
+	assert.NotContains(t, html, "<table")
+
+	// The widget is not allowed
+	_, parser = render(`<s:widget type="table" name="orders"></s:widget>`)
+	assert.NotEmpty(t, parser.Errors())
+}
@@ -128,6 +128,18 @@ function __sui_component(elm, component) {
   this.emit = function (name, data) {
     const event = new CustomEvent(name, { detail: data });
     __self.root.dispatchEvent(event);
+
+    // The component event of the instance, the callers listen by s:on:<name>
+    const key = String(name).toLowerCase();
+    const declared = __self.root.getAttribute("s:emit");
+    if (declared !== null) {
+      const names = declared.split(",").map((n) => n.trim().toLowerCase());
+      if (!names.includes(key)) {
+        const cn = __self.root.getAttribute("s:cn");
+        console.warn(`[SUI] ${cn} emits the undeclared event ${name}`, __self.root);
+      }
+    }
+    __self.root.dispatchEvent(new CustomEvent("emit:" + key, { detail: data }));
   };
 
   this.render = function (name, data, option) {
@@ -165,6 +177,74 @@ function __sui_event_handler(event, dataKeys, jsonKeys, target, root, handler) {
     });
 }
 
+/**
+ * Listen the event with the modifiers of the s:event-mods attribute (or the name), e.g. click.prevent.stop
+ * prevent, stop, self, once, capture, passive, debounce:ms and throttle:ms
+ */
+function __sui_event_listen(
+  elm: Element,
+  name: string,
+  listener: (event: Event) => void
+) {
+  const parts = name.split(".");
+  const type = parts[0];
+  let mods: Record<string, any> = {};
+  try {
+    const all = JSON.parse(elm.getAttribute("s:event-mods") || "{}");
+    mods = { ...(all[type] || {}) };
+  } catch (e) {
+    console.error("[SUI] Invalid event modifiers", elm);
+  }
+  parts.slice(1).forEach((mod) => {
+    const [key, value] = mod.split(":");
+    mods[key] = value !== undefined ? Number(value) : true;
+  });
+
+  let call = listener;
+  if (mods.debounce) {
+    const wait = mods.debounce === true ? 250 : mods.debounce;
+    const fn = call;
+    let timer: any = null;
+    call = (event) => {
+      clearTimeout(timer);
+      timer = setTimeout(() => fn(event), wait);
+    };
+  }
+
+  if (mods.throttle) {
+    const wait = mods.throttle === true ? 250 : mods.throttle;
+    const fn = call;
+    let last = 0;
+    call = (event) => {
+      const now = Date.now();
+      if (now - last < wait) {
+        return;
+      }
+      last = now;
+      fn(event);
+    };
+  }
+
+  // The prevent and the stop are applied at once, the debounced handler is called later
+  const options = { capture: !!mods.capture, passive: !!mods.passive };
+  const handler = (event: Event) => {
+    if (mods.self && event.target !== event.currentTarget) {
+      return;
+    }
+    if (mods.prevent) {
+      event.preventDefault();
+    }
+    if (mods.stop) {
+      event.stopPropagation();
+    }
+    if (mods.once) {
+      elm.removeEventListener(type, handler, options);
+    }
+    call(event);
+  };
+  elm.addEventListener(type, handler, options);
+}
+
 function __sui_event_init(elm: Element) {
   const bindEvent = (eventElm) => {
     const cn = eventElm.getAttribute("s:event-cn") || "";
@@ -197,7 +277,7 @@ function __sui_event_init(elm: Element) {
         const handler = window[bind];
         const root = document.body;
         const target = eventElm;
-        eventElm.addEventListener(name, (event) => {
+        __sui_event_listen(eventElm, name, (event) => {
           __sui_event_handler(event, dataKeys, jsonKeys, target, root, handler);
         });
         continue;
@@ -214,7 +294,7 @@ function __sui_event_init(elm: Element) {
       const handler = comp[bind];
       const root = comp.root;
       const target = eventElm;
-      eventElm.addEventListener(name, (event) => {
+      __sui_event_listen(eventElm, name, (event) => {
         __sui_event_handler(event, dataKeys, jsonKeys, target, root, handler);
       });
     }
@@ -226,6 +306,75 @@ function __sui_event_init(elm: Element) {
   jitEventElms.forEach((eventElm) => bindEvent(eventElm));
 }
 
+function __sui_model_init(elm: Element) {
+  const setValue = (path: string, value: any) => {
+    // @ts-ignore
+    let data = typeof __sui_data === "object" && __sui_data ? __sui_data : {};
+    const keys = path.split(".");
+    for (let i = 0; i < keys.length - 1; i++) {
+      if (typeof data[keys[i]] !== "object" || data[keys[i]] === null) {
+        data[keys[i]] = {};
+      }
+      data = data[keys[i]];
+    }
+    data[keys[keys.length - 1]] = value;
+  };
+
+  const getValue = (modelElm, path: string) => {
+    const type = (modelElm.getAttribute("type") || "").toLowerCase();
+    if (modelElm.tagName === "SELECT" && modelElm.multiple) {
+      return Array.from(modelElm.selectedOptions).map((option: any) => option.value);
+    }
+
+    if (type === "checkbox") {
+      // The checkboxes of the same path are the list of the values
+      const boxes = document.querySelectorAll(
+        `input[type=checkbox][s\\:model="${path}"]`
+      );
+      if (boxes.length > 1 || modelElm.hasAttribute("value")) {
+        return Array.from(boxes)
+          .filter((box: any) => box.checked)
+          .map((box: any) => box.value);
+      }
+      return modelElm.checked;
+    }
+
+    if (type === "number" || type === "range") {
+      return modelElm.value === "" ? null : Number(modelElm.value);
+    }
+    return modelElm.value;
+  };
+
+  const modelElms = elm.querySelectorAll("[s\\:model]");
+  modelElms.forEach((modelElm: any) => {
+    if (modelElm.__sui_model) {
+      return;
+    }
+    modelElm.__sui_model = true;
+
+    const path = modelElm.getAttribute("s:model") || "";
+    const type = (modelElm.getAttribute("type") || "").toLowerCase();
+    const name =
+      modelElm.tagName === "SELECT" || type === "checkbox" || type === "radio"
+        ? "change"
+        : "input";
+
+    modelElm.addEventListener(name, () => {
+      if (type === "radio" && !modelElm.checked) {
+        return;
+      }
+      const value = getValue(modelElm, path);
+      setValue(path, value);
+      modelElm.dispatchEvent(
+        new CustomEvent("model:change", {
+          bubbles: true,
+          detail: { path: path, value: value },
+        })
+      );
+    });
+  });
+}
+
 function __sui_store(elm) {
   elm = elm || document.body;
 
@@ -268,10 +417,13 @@ async function __sui_backend_call(
   ...args: any
 ): Promise<any> {
   const url = `/api/__yao/sui/v1/run${route}`;
+  // @ts-ignore
+  const csrf = typeof __sui_data === "object" && __sui_data ? __sui_data["$csrf"] : null;
   headers = {
     "Content-Type": "application/json",
     Referer: window.location.href,
     Cookie: document.cookie,
+    ...(csrf ? { "X-Sui-Csrf": csrf } : {}),
     ...headers,
   };
   const payload = { method, args };
@@ -388,6 +540,7 @@ async function __sui_render(
       elm.innerHTML = text;
       try {
         __sui_event_init(elm);
+        __sui_model_init(elm);
       } catch (e) {
         const message = e.message || "Failed to init events";
         Promise.reject(message);
@@ -405,6 +558,77 @@ async function __sui_render(
   }
 }
 
+/**
+ * Load the async components (s:async), the placeholder is filled in by the render api on the page load (load),
+ * the idle time (idle) or when the placeholder is scrolled into the view (visible)
+ */
+function __sui_async_init(elm: Element) {
+  elm.querySelectorAll("[s\\:async]").forEach((asyncElm) => {
+    const trigger = asyncElm.getAttribute("s:async") || "load";
+    const load = () => __sui_async_load(asyncElm);
+    if (trigger == "visible" && "IntersectionObserver" in window) {
+      const observer = new IntersectionObserver((entries) => {
+        if (entries.some((entry) => entry.isIntersecting)) {
+          observer.disconnect();
+          load();
+        }
+      });
+      observer.observe(asyncElm);
+      return;
+    }
+
+    if (trigger == "idle" && "requestIdleCallback" in window) {
+      // @ts-ignore
+      window.requestIdleCallback(load);
+      return;
+    }
+    load();
+  });
+}
+
+async function __sui_async_load(elm: Element) {
+  const name = elm.getAttribute("s:render");
+  if (!name) {
+    console.error("[SUI] The async component has no render name", elm);
+    return;
+  }
+
+  const routeElm = elm.closest("[s\\:route]");
+  const routeAttr = routeElm ? routeElm.getAttribute("s:route") : false;
+  const root = document.body.getAttribute("s:public") || "";
+  const route = routeAttr ? `${root}${routeAttr}` : window.location.pathname;
+  const url = `/api/__yao/sui/v1/render${route}`;
+  const props = new __sui_props(elm).List();
+  const payload = { name, data: {}, option: { props } };
+  const headers = {
+    "Content-Type": "application/json",
+    Cookie: document.cookie,
+  };
+
+  try {
+    const body = JSON.stringify(payload);
+    const response = await fetch(url, { method: "POST", headers, body: body });
+    if (!response.ok) {
+      throw new Error(`${response.status} ${response.statusText}`);
+    }
+    elm.innerHTML = await response.text();
+    elm.removeAttribute("s:async");
+
+    // Init the filled component
+    const cn = elm.getAttribute("s:cn");
+    if (cn && typeof window[cn] === "function") {
+      new window[cn](elm);
+    }
+    __sui_event_init(elm);
+    __sui_model_init(elm);
+    __sui_async_init(elm);
+    elm.dispatchEvent(new CustomEvent("async:loaded"));
+  } catch (e) {
+    elm.innerHTML = `<span class="sui-render-error">Failed to render</span>`;
+    console.error("[SUI] Failed to load the async component", e);
+  }
+}
+
 export type Component = {
   root: HTMLElement;
   state: ComponentState;
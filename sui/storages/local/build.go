@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/yaoapp/gou/application"
 	"github.com/yaoapp/gou/process"
 	v8 "github.com/yaoapp/gou/runtime/v8"
@@ -121,12 +123,59 @@ func (tmpl *Template) Build(option *core.BuildOption) ([]string, error) {
 		}
 	}
 
+	// Export the components as the custom elements
+	if len(option.WebComponents) > 0 {
+		messages, err := tmpl.writeWebComponents(ctx, option)
+		if err != nil {
+			return warnings, err
+		}
+		warnings = append(warnings, messages...)
+	}
+
 	// Add sui lib to the global
 	err = tmpl.UpdateJSSDK(option)
 	if err != nil {
 		return warnings, err
 	}
 
+	// Check the links and the asset references of the pages
+	broken, err := tmpl.checkLinks(ctx.Manifest(), option.Data)
+	if err != nil {
+		return warnings, err
+	}
+	for _, link := range broken {
+		warnings = append(warnings, fmt.Sprintf("Broken link %s", link.Error()))
+	}
+
+	// Check the performance budgets of the built pages
+	exceeded := []string{}
+	for _, violation := range tmpl.checkBudgets(ctx.Manifest()) {
+		if violation.Level == "error" {
+			exceeded = append(exceeded, violation.Error())
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("Budget exceeded %s", violation.Error()))
+	}
+
+	// Write the build manifest, checked when the sui is loaded
+	err = tmpl.writeManifest(ctx.Manifest(), option.Data)
+	if err != nil {
+		return warnings, err
+	}
+
+	if len(exceeded) > 0 {
+		return warnings, fmt.Errorf("Performance budgets exceeded: %s", strings.Join(exceeded, ";\n"))
+	}
+
+	// Profile the preview renders of the pages
+	if option.Profile {
+		messages, err := tmpl.writeProfile(option.Data)
+		if err != nil {
+			return warnings, err
+		}
+		warnings = append(warnings, messages...)
+	}
+
 	// Execute the build after hook
 	if option.ExecScripts {
 		res := tmpl.ExecAfterBuildScripts()
@@ -266,6 +315,147 @@ func (tmpl *Template) writeGlobalScript(data map[string]interface{}) error {
 	return os.WriteFile(target, source, 0644)
 }
 
+// writeManifest write the build manifest to the public root
+func (tmpl *Template) writeManifest(manifest *core.BuildManifest, data map[string]interface{}) error {
+	source, err := manifest.Bytes()
+	if err != nil {
+		return err
+	}
+
+	root, err := tmpl.local.DSL.PublicRoot(data)
+	if err != nil {
+		log.Error("WriteManifest: Get the public root error: %s. use %s", err.Error(), tmpl.local.DSL.Public.Root)
+		root = tmpl.local.DSL.Public.Root
+	}
+	target := filepath.Join(application.App.Root(), "public", root, core.BuildManifestFile)
+	dir := filepath.Dir(target)
+	if exist, _ := os.Stat(dir); exist == nil {
+		os.MkdirAll(dir, os.ModePerm)
+	}
+	return os.WriteFile(target, source, 0644)
+}
+
+// checkLinks check the links of the built pages against the routes and the public files, the report of the
+// broken links is written to the public root
+func (tmpl *Template) checkLinks(manifest *core.BuildManifest, data map[string]interface{}) ([]core.BrokenLink, error) {
+	root, err := tmpl.local.DSL.PublicRoot(data)
+	if err != nil {
+		log.Error("CheckLinks: Get the public root error: %s. use %s", err.Error(), tmpl.local.DSL.Public.Root)
+		root = tmpl.local.DSL.Public.Root
+	}
+
+	public := filepath.Join(application.App.Root(), "public")
+	broken := manifest.CheckLinks(root, func(file string) bool {
+		_, err := os.Stat(filepath.Join(public, filepath.FromSlash(file)))
+		return err == nil
+	})
+
+	source, err := jsoniter.MarshalIndent(broken, "", "  ")
+	if err != nil {
+		return broken, err
+	}
+
+	target := filepath.Join(public, root, core.LinkReportFile)
+	dir := filepath.Dir(target)
+	if exist, _ := os.Stat(dir); exist == nil {
+		os.MkdirAll(dir, os.ModePerm)
+	}
+	return broken, os.WriteFile(target, source, 0644)
+}
+
+// checkBudgets check the metrics of the built pages against the performance budgets of the DSL, the local
+// scripts are measured by the files of the public root
+func (tmpl *Template) checkBudgets(manifest *core.BuildManifest) []core.BudgetViolation {
+	public := filepath.Join(application.App.Root(), "public")
+	return manifest.CheckBudgets(tmpl.local.DSL.Budgets, func(file string) (int64, bool) {
+		info, err := os.Stat(filepath.Join(public, filepath.FromSlash(file)))
+		if err != nil {
+			return 0, false
+		}
+		return info.Size(), true
+	})
+}
+
+// writeWebComponents build the components of the route patterns as the custom elements <route>.wc.js, and bundle
+// them into the WebComponentsFile of the public root, the non-SUI frontends embed the components by one script
+func (tmpl *Template) writeWebComponents(ctx *core.GlobalBuildContext, option *core.BuildOption) ([]string, error) {
+	warnings := []string{}
+	routes, err := tmpl.GlobRoutes(option.WebComponents, true)
+	if err != nil {
+		return warnings, err
+	}
+	sort.Strings(routes)
+
+	sources := []string{}
+	for _, route := range routes {
+		page, has := tmpl.loaded[route]
+		if !has {
+			log.Warn("The page %s is not loaded", route)
+			continue
+		}
+
+		opt := *option
+		opt.ComponentName = ""
+		file, messages, err := page.BuildAsWebComponent(ctx, &opt)
+		warnings = append(warnings, messages...)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Web component %s: %s", route, err.Error()))
+			continue
+		}
+
+		source, err := os.ReadFile(filepath.Join(application.App.Root(), file))
+		if err != nil {
+			return warnings, err
+		}
+		sources = append(sources, fmt.Sprintf("/* %s <%s> */\n%s", route, core.WebComponentTag(route), source))
+	}
+
+	root, err := tmpl.local.DSL.PublicRoot(option.Data)
+	if err != nil {
+		log.Error("WriteWebComponents: Get the public root error: %s. use %s", err.Error(), tmpl.local.DSL.Public.Root)
+		root = tmpl.local.DSL.Public.Root
+	}
+	target := filepath.Join(application.App.Root(), "public", root, core.WebComponentsFile)
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return warnings, err
+	}
+	return warnings, os.WriteFile(target, []byte(strings.Join(sources, "\n")), 0644)
+}
+
+// writeProfile render the loaded pages as the previews with the fixture data, and write the report of the most
+// expensive components and expressions to the public root
+func (tmpl *Template) writeProfile(data map[string]interface{}) ([]string, error) {
+	warnings := []string{}
+	profile := core.NewProfile()
+	routes := []string{}
+	for route := range tmpl.loaded {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	for _, route := range routes {
+		if err := tmpl.loaded[route].Get().Profile(profile); err != nil {
+			warnings = append(warnings, fmt.Sprintf("Profile page %s: %s", route, err.Error()))
+		}
+	}
+
+	source, err := jsoniter.MarshalIndent(profile.Report(), "", "  ")
+	if err != nil {
+		return warnings, err
+	}
+
+	root, err := tmpl.local.DSL.PublicRoot(data)
+	if err != nil {
+		log.Error("WriteProfile: Get the public root error: %s. use %s", err.Error(), tmpl.local.DSL.Public.Root)
+		root = tmpl.local.DSL.Public.Root
+	}
+	target := filepath.Join(application.App.Root(), "public", root, core.ProfileReportFile)
+	dir := filepath.Dir(target)
+	if exist, _ := os.Stat(dir); exist == nil {
+		os.MkdirAll(dir, os.ModePerm)
+	}
+	return warnings, os.WriteFile(target, source, 0644)
+}
+
 // SyncAssets sync the assets
 func (tmpl *Template) SyncAssets(option *core.BuildOption) error {
 
@@ -526,6 +716,38 @@ func (page *Page) BuildAsComponent(globalCtx *core.GlobalBuildContext, option *c
 	return warnings, err
 }
 
+// BuildAsWebComponent build the page as a standalone custom element, return the public file of the script
+func (page *Page) BuildAsWebComponent(globalCtx *core.GlobalBuildContext, option *core.BuildOption) (string, []string, error) {
+
+	ctx := core.NewBuildContext(globalCtx)
+	if option.AssetRoot == "" {
+		root, err := page.tmpl.local.DSL.PublicRoot(option.Data)
+		if err != nil {
+			log.Error("SyncAssets: Get the public root error: %s. use %s", err.Error(), page.tmpl.local.DSL.Public.Root)
+			root = page.tmpl.local.DSL.Public.Root
+		}
+		option.AssetRoot = filepath.Join(root, "assets")
+	}
+
+	js, warnings, err := page.Page.CompileAsWebComponent(ctx, option)
+	if err != nil {
+		return "", warnings, err
+	}
+
+	file := fmt.Sprintf("%s.wc.js", page.publicFile(option.Data))
+	fileAbs := filepath.Join(application.App.Root(), file)
+	dir := filepath.Dir(fileAbs)
+	if exist, _ := os.Stat(dir); exist == nil {
+		os.MkdirAll(dir, os.ModePerm)
+	}
+
+	err = os.WriteFile(fileAbs, []byte(js), 0644)
+	if err != nil {
+		return "", warnings, err
+	}
+	return file, warnings, nil
+}
+
 // Trans the page
 func (page *Page) Trans(globalCtx *core.GlobalBuildContext, option *core.BuildOption) ([]string, error) {
 	warnings := []string{}
@@ -3,6 +3,7 @@ package local
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -100,6 +101,46 @@ func TestTemplateBuildAsComponent(t *testing.T) {
 	assert.Contains(t, string(content), `type="hook-bar"`)
 }
 
+func TestTemplateBuildWebComponents(t *testing.T) {
+	tests := prepare(t)
+	defer clean()
+
+	tmpl, err := tests.Test.GetTemplate("advanced")
+	if err != nil {
+		t.Fatalf("GetTemplate error: %v", err)
+	}
+
+	root := application.App.Root()
+	public := tmpl.(*Template).local.GetPublic()
+	path := filepath.Join(root, "public", public.Root)
+
+	// Remove files and directories in Public directory if exists
+	err = os.RemoveAll(path)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatalf("RemoveAll error: %v", err)
+	}
+
+	_, err = tmpl.Build(&core.BuildOption{SSR: true, WebComponents: []string{"/index"}})
+	if err != nil {
+		t.Fatalf("Build error: %v", err)
+	}
+
+	// Check the custom element of the page
+	assert.FileExists(t, filepath.Join(path, "/index.wc.js"))
+
+	// Check the bundle
+	bundle := filepath.Join(path, core.WebComponentsFile)
+	assert.FileExists(t, bundle)
+	content, err := os.ReadFile(bundle)
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+
+	assert.True(t, strings.HasPrefix(string(content), "/* /index <sui-index> */\n"))
+	assert.Contains(t, string(content), "customElements.define(tag, SUIComponent);")
+	assert.Equal(t, 1, strings.Count(string(content), "/* /"))
+}
+
 func TestPageBuild(t *testing.T) {
 	tests := prepare(t)
 	defer clean()
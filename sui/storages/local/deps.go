@@ -0,0 +1,119 @@
+package local
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/yaoapp/yao/sui/core"
+)
+
+// Dependencies get the dependency closure of the page, the layout, the components (recursive), the partials,
+// the scripts, the styles, the assets, the locales and the data processes, with the content hashes
+func (tmpl *Template) Dependencies(route string) (*core.PageDependencies, error) {
+	if !tmpl.PageExist(route) {
+		return nil, fmt.Errorf("Page %s not found", route)
+	}
+
+	deps := core.NewPageDependencies(route)
+	deps.Add("layout", "__document.html", filepath.Join(tmpl.Root, "__document.html"), tmpl.Document)
+	if tmpl.GlobalData != nil {
+		deps.Add("data", "__data.json", filepath.Join(tmpl.Root, "__data.json"), tmpl.GlobalData)
+		for _, name := range core.DependencyProcesses(string(tmpl.GlobalData)) {
+			deps.Add("process", name, "", nil)
+		}
+	}
+	tmpl.addLocaleDependency(deps, "__global")
+
+	err := tmpl.addPageDependencies(deps, "page", route)
+	if err != nil {
+		return nil, err
+	}
+
+	deps.Sum()
+	return deps, nil
+}
+
+func (tmpl *Template) addPageDependencies(deps *core.PageDependencies, typ string, route string) error {
+	if deps.Has("page", route) || deps.Has("component", route) {
+		return nil
+	}
+
+	ipage, err := tmpl.Page(route)
+	if err != nil {
+		deps.Add(typ, route, "", nil)
+		return nil
+	}
+
+	page := ipage.Get()
+	codes := page.Codes
+	deps.Add(typ, route, filepath.Join(page.Path, codes.HTML.File), []byte(codes.HTML.Code))
+	tmpl.addCodeDependency(deps, "script", page.Path, codes.TS)
+	if codes.TS.Code == "" {
+		tmpl.addCodeDependency(deps, "script", page.Path, codes.JS)
+	}
+	tmpl.addCodeDependency(deps, "style", page.Path, codes.CSS)
+	tmpl.addCodeDependency(deps, "data", page.Path, codes.DATA)
+	tmpl.addCodeDependency(deps, "config", page.Path, codes.CONF)
+	for _, name := range core.DependencyProcesses(codes.DATA.Code) {
+		deps.Add("process", name, "", nil)
+	}
+
+	// The backend script
+	for _, ext := range []string{".backend.ts", ".backend.js"} {
+		file := filepath.Join(page.Path, page.Name+ext)
+		if content, err := tmpl.local.fs.ReadFile(file); err == nil {
+			deps.Add("script", strings.TrimPrefix(file, tmpl.Root), file, content)
+			break
+		}
+	}
+
+	tmpl.addLocaleDependency(deps, route)
+
+	// The layout chain of the page, the components of the layouts are the dependencies of the page
+	html := codes.HTML.Code
+	for _, name := range core.LayoutChain(page.Layout(), page.Layouts) {
+		file := filepath.Join(tmpl.Root, core.LayoutsDir, name+".html")
+		deps.Add("layout", name, file, page.Layouts[name])
+		html += string(page.Layouts[name])
+	}
+
+	components, _, assets := core.DependencyScan(html)
+	for _, asset := range assets {
+		file := filepath.Join(tmpl.Root, "__assets", asset)
+		content, _ := tmpl.local.fs.ReadFile(file)
+		deps.Add("asset", asset, file, content)
+	}
+
+	root := tmpl.local.DSL.Public.Root
+	deps.AddPartials(root, route, codes.HTML.Code)
+
+	for _, component := range components {
+		err := tmpl.addPageDependencies(deps, "component", component)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tmpl *Template) addCodeDependency(deps *core.PageDependencies, typ string, path string, source core.Source) {
+	if source.File == "" || source.Code == "" {
+		return
+	}
+	file := filepath.Join(path, source.File)
+	deps.Add(typ, strings.TrimPrefix(file, tmpl.Root), file, []byte(source.Code))
+}
+
+// addLocaleDependency add the locale files of the route, __locales/{locale}/{route}.yml
+func (tmpl *Template) addLocaleDependency(deps *core.PageDependencies, route string) {
+	for _, locale := range tmpl.Locales() {
+		name := filepath.Join(locale.Value, fmt.Sprintf("%s.yml", route))
+		file := filepath.Join(tmpl.Root, "__locales", name)
+		if exist, _ := tmpl.local.fs.Exists(file); !exist {
+			continue
+		}
+		content, _ := tmpl.local.fs.ReadFile(file)
+		deps.Add("locale", name, file, content)
+	}
+}
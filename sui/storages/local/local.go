@@ -10,6 +10,7 @@ import (
 	"github.com/yaoapp/gou/application"
 	"github.com/yaoapp/gou/fs"
 	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/config"
 	"github.com/yaoapp/yao/sui/core"
 	sui "github.com/yaoapp/yao/sui/core"
 )
@@ -147,18 +148,50 @@ func (local *Local) getTemplate(id string, path string) (*Template, error) {
 		tmpl.Document = documentBytes
 	}
 
-	// load the __data.json
-	dataFile := filepath.Join(path, "__data.json")
-	if local.fs.IsFile(dataFile) {
+	// load the __layouts/*.html, the name of the layout is the path without the extension
+	layouts, err := local.getLayouts(filepath.Join(path, core.LayoutsDir))
+	if err != nil {
+		return nil, err
+	}
+	tmpl.Layouts = layouts
+
+	// load the __data.json and the __data.{mode}.json of the environment (e.g. __data.development.json)
+	// the global data is exposed as $global for all the pages
+	globals := [][]byte{}
+	for _, name := range []string{"__data.json", fmt.Sprintf("__data.%s.json", config.Conf.Mode)} {
+		dataFile := filepath.Join(path, name)
+		if !local.fs.IsFile(dataFile) {
+			continue
+		}
 		dataBytes, err := local.fs.ReadFile(dataFile)
 		if err != nil {
 			return nil, err
 		}
+		globals = append(globals, dataBytes)
+	}
+
+	if len(globals) == 1 {
+		tmpl.GlobalData = globals[0]
+	} else if len(globals) > 1 {
+		dataBytes, err := core.MergeGlobalData(globals...)
+		if err != nil {
+			return nil, fmt.Errorf("template %s global data error: %s", id, err.Error())
+		}
 		tmpl.GlobalData = dataBytes
 	}
 
+	// load the __stories.json, the sample props of the components in the storybook
+	storiesFile := filepath.Join(path, core.StoriesFile)
+	if local.fs.IsFile(storiesFile) {
+		storiesBytes, err := local.fs.ReadFile(storiesFile)
+		if err != nil {
+			return nil, err
+		}
+		tmpl.Stories = storiesBytes
+	}
+
 	// load the __build.backend.ts / __build.backend.js
-	err := tmpl.loadBuildScript()
+	err = tmpl.loadBuildScript()
 	if err != nil {
 		return nil, err
 	}
@@ -166,6 +199,31 @@ func (local *Local) getTemplate(id string, path string) (*Template, error) {
 	return &tmpl, nil
 }
 
+// getLayouts get the layouts of the template, nil if the template has no layouts
+func (local *Local) getLayouts(dir string) (map[string][]byte, error) {
+	if !local.fs.IsDir(dir) {
+		return nil, nil
+	}
+
+	layouts := map[string][]byte{}
+	err := local.fs.Walk(dir, func(root, file string, isdir bool) error {
+		if isdir {
+			return nil
+		}
+		source, err := local.fs.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimPrefix(filepath.ToSlash(strings.TrimPrefix(file, dir)), "/")
+		layouts[strings.TrimSuffix(name, filepath.Ext(name))] = source
+		return nil
+	}, "*.html")
+	if err != nil {
+		return nil, err
+	}
+	return layouts, nil
+}
+
 // GetTemplateID get the template ID
 func (local *Local) getTemplateID(path string) string {
 	return filepath.Base(path)
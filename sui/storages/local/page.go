@@ -144,6 +144,11 @@ func (tmpl *Template) PageTree(route string) ([]*core.PageTreeNode, error) {
 
 // Page get the page
 func (tmpl *Template) Page(route string) (core.IPage, error) {
+	route, err := tmpl.remoteRoute(route)
+	if err != nil {
+		return nil, err
+	}
+
 	path := tmpl.getPagePath(route)
 	exts := []string{".sui", ".html", ".htm", ".page"}
 	for _, ext := range exts {
@@ -168,6 +173,11 @@ func (tmpl *Template) Page(route string) (core.IPage, error) {
 
 // PageExist check if the page exist
 func (tmpl *Template) PageExist(route string) bool {
+	route, err := tmpl.remoteRoute(route)
+	if err != nil {
+		return false
+	}
+
 	path := tmpl.getPagePath(route)
 	exts := []string{".sui", ".html", ".htm", ".page"}
 	for _, ext := range exts {
@@ -387,6 +397,7 @@ func (tmpl *Template) getPage(route, file string) (core.IPage, error) {
 				TS:   core.Source{File: fmt.Sprintf("%s.ts", name)},
 				LESS: core.Source{File: fmt.Sprintf("%s.less", name)},
 				CONF: core.Source{File: fmt.Sprintf("%s.config", name)},
+				MOCK: core.Source{File: fmt.Sprintf("%s.mock.json", name)},
 			},
 		},
 	}, nil
@@ -460,6 +471,16 @@ func (page *Page) Load() error {
 		page.Codes.DATA.Code = string(dataCode)
 	}
 
+	// Read the preview data fixtures
+	mockFile := filepath.Join(page.Path, page.Codes.MOCK.File)
+	if exist, _ := page.tmpl.local.fs.Exists(mockFile); exist && page.Codes.MOCK.File != "" {
+		mockCode, err := page.tmpl.local.fs.ReadFile(mockFile)
+		if err != nil {
+			return err
+		}
+		page.Codes.MOCK.Code = string(mockCode)
+	}
+
 	// Read the config code
 	confFile := filepath.Join(page.Path, page.Codes.CONF.File)
 	if exist, _ := page.tmpl.local.fs.Exists(confFile); exist {
@@ -476,6 +497,9 @@ func (page *Page) Load() error {
 	// Set the page document
 	page.Document = page.tmpl.Document
 
+	// Set the page layouts
+	page.Layouts = page.tmpl.Layouts
+
 	// Set the page global data
 	page.GlobalData = page.tmpl.GlobalData
 
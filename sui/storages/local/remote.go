@@ -0,0 +1,23 @@
+package local
+
+import (
+	"path/filepath"
+
+	"github.com/yaoapp/yao/sui/core"
+)
+
+// remoteRoute get the local route of the remote component (e.g. /@acme/button), the pinned version
+// is fetched to the template on the first use
+func (tmpl *Template) remoteRoute(route string) (string, error) {
+	if tmpl.Template == nil {
+		return route, nil
+	}
+
+	local, remote, err := core.RemoteRoute(tmpl.Template.Remotes, route)
+	if err != nil || remote == nil {
+		return local, err
+	}
+
+	_, err = remote.Fetch(filepath.Join(tmpl.local.fs.Root(), tmpl.Root))
+	return local, err
+}
@@ -0,0 +1,50 @@
+package local
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yaoapp/gou/application"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/sui/core"
+)
+
+// Storybook get the built just-in-time components of the public root and the stories of the template
+func (tmpl *Template) Storybook(data map[string]interface{}) (*core.Storybook, error) {
+	stories, err := core.ParseStories(tmpl.Stories)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := tmpl.local.DSL.PublicRoot(data)
+	if err != nil {
+		log.Error("Storybook: Get the public root error: %s. use %s", err.Error(), tmpl.local.DSL.Public.Root)
+		root = tmpl.local.DSL.Public.Root
+	}
+
+	components := []string{}
+	dir := filepath.Join(application.App.Root(), "public", root)
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir // not built yet
+			}
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(path, ".jit") {
+			return nil
+		}
+		route := strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(path), filepath.ToSlash(dir)), ".jit")
+		components = append(components, "/"+strings.TrimPrefix(route, "/"))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(components)
+
+	return &core.Storybook{Root: root, Components: components, Stories: stories}, nil
+}